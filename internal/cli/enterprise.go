@@ -20,6 +20,7 @@ import (
 
 	"github.com/redpanda-data/connect/v4/internal/impl/kafka/enterprise"
 	"github.com/redpanda-data/connect/v4/internal/license"
+	"github.com/redpanda-data/connect/v4/internal/lifecycle"
 	"github.com/redpanda-data/connect/v4/internal/secrets"
 	"github.com/redpanda-data/connect/v4/internal/telemetry"
 )
@@ -34,6 +35,7 @@ func InitEnterpriseCLI(binaryName, version, dateBuilt string, schema *service.Co
 	instanceID := xid.New().String()
 
 	rpLogger := enterprise.NewTopicLogger(instanceID)
+	hooks := lifecycle.New(instanceID)
 	var fbLogger *service.Logger
 
 	cListApplied, err := ApplyConnectorsList(connectorListPath, schema)
@@ -92,10 +94,14 @@ func InitEnterpriseCLI(binaryName, version, dateBuilt string, schema *service.Co
 			if !disableTelemetry {
 				telemetry.ActivateExporter(instanceID, version, fbLogger, schema, pConf)
 			}
+			if err := hooks.InitFromParsed(pConf.Namespace("lifecycle_hooks")); err != nil {
+				return err
+			}
 			return rpLogger.InitOutputFromParsed(pConf.Namespace("redpanda"))
 		}),
 		service.CLIOptOnStreamStart(func(s *service.RunningStreamSummary) error {
 			rpLogger.SetStreamSummary(s)
+			hooks.TriggerStart(context.Background())
 			return nil
 		}),
 
@@ -103,7 +109,7 @@ func InitEnterpriseCLI(binaryName, version, dateBuilt string, schema *service.Co
 		service.CLIOptCustomRunFlags([]cli.Flag{
 			&cli.StringSliceFlag{
 				Name:  "secrets",
-				Usage: "Attempt to load secrets from a provided URN. If more than one entry is specified they will be attempted in order until a value is found. Environment variable lookups are specified with the URN `env:`, which by default is the only entry. In order to disable all secret lookups specify a single entry of `none:`.",
+				Usage: "Attempt to load secrets from a provided URN. If more than one entry is specified they will be attempted in order until a value is found. Environment variable lookups are specified with the URN `env:`, which by default is the only entry. In order to disable all secret lookups specify a single entry of `none:`. Secrets stored within a HashiCorp Vault KV version 2 secrets engine are specified with URNs of the form `vault://host:port/mount/path`, where `mount` is the mount of the secrets engine and the optional `path` is added as a prefix to the names of secrets, the query parameter `scheme=http` disables TLS, and requests are authenticated with the environment variable `VAULT_TOKEN` or else by logging in with the AppRole of `VAULT_ROLE_ID` and `VAULT_SECRET_ID`, mounted at `VAULT_APPROLE_MOUNT` (default `approle`).",
 				Value: cli.NewStringSlice("env:"),
 			},
 			&cli.BoolFlag{
@@ -141,6 +147,13 @@ func InitEnterpriseCLI(binaryName, version, dateBuilt string, schema *service.Co
 	}
 	rpLogger.TriggerEventStopped(err)
 
+	stopErr := err
+	if stopErr == nil && exitCode != 0 {
+		stopErr = fmt.Errorf("exited with code %v", exitCode)
+	}
+	hooks.TriggerStopped(context.Background(), stopErr)
+	hooks.Close()
+
 	_ = rpLogger.Close(context.Background())
 	if exitCode != 0 {
 		os.Exit(exitCode)
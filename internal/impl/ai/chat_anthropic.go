@@ -0,0 +1,213 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	anthropicDefaultAddress   = "https://api.anthropic.com"
+	anthropicAPIVersion       = "2023-06-01"
+	anthropicDefaultMaxTokens = 4096
+)
+
+// anthropicChatModel sends requests to the Anthropic messages API, see
+// https://docs.anthropic.com/en/api/messages.
+type anthropicChatModel struct {
+	client *http.Client
+	addr   string
+	apiKey string
+	model  string
+}
+
+func newAnthropicChatModel(addr, apiKey, model string) *anthropicChatModel {
+	if addr == "" {
+		addr = anthropicDefaultAddress
+	}
+	return &anthropicChatModel{
+		client: http.DefaultClient,
+		addr:   strings.TrimSuffix(addr, "/"),
+		apiKey: apiKey,
+		model:  model,
+	}
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Tools       []anthropicTool    `json:"tools,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string                  `json:"role"`
+	Content []anthropicContentBlock `json:"content"`
+}
+
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+
+	// Set for text blocks.
+	Text string `json:"text,omitempty"`
+
+	// Set for tool_use blocks.
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+
+	// Set for tool_result blocks.
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+}
+
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+}
+
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (a *anthropicChatModel) chat(ctx context.Context, req *chatRequest) (chatMessage, error) {
+	body := anthropicRequest{
+		Model:       a.model,
+		MaxTokens:   anthropicDefaultMaxTokens,
+		System:      req.system,
+		Temperature: req.temperature,
+	}
+	if req.maxTokens != nil {
+		body.MaxTokens = *req.maxTokens
+	}
+	var err error
+	for _, m := range req.messages {
+		switch m.role {
+		case chatRoleUser:
+			body.Messages = append(body.Messages, anthropicMessage{
+				Role:    "user",
+				Content: []anthropicContentBlock{{Type: "text", Text: m.text}},
+			})
+		case chatRoleAssistant:
+			msg := anthropicMessage{Role: "assistant"}
+			if m.text != "" {
+				msg.Content = append(msg.Content, anthropicContentBlock{Type: "text", Text: m.text})
+			}
+			for _, c := range m.toolCalls {
+				args := []byte("{}")
+				if len(c.args) > 0 {
+					if args, err = json.Marshal(c.args); err != nil {
+						return chatMessage{}, err
+					}
+				}
+				msg.Content = append(msg.Content, anthropicContentBlock{
+					Type:  "tool_use",
+					ID:    c.id,
+					Name:  c.name,
+					Input: args,
+				})
+			}
+			body.Messages = append(body.Messages, msg)
+		case chatRoleTool:
+			block := anthropicContentBlock{
+				Type:      "tool_result",
+				ToolUseID: m.toolResult.id,
+				Content:   m.text,
+			}
+			// The results of all tool calls requested by a single response must
+			// be sent within the same user message.
+			if n := len(body.Messages); n > 0 && body.Messages[n-1].Role == "user" && body.Messages[n-1].Content[0].Type == "tool_result" {
+				body.Messages[n-1].Content = append(body.Messages[n-1].Content, block)
+			} else {
+				body.Messages = append(body.Messages, anthropicMessage{
+					Role:    "user",
+					Content: []anthropicContentBlock{block},
+				})
+			}
+		}
+	}
+	for _, t := range req.tools {
+		body.Tools = append(body.Tools, anthropicTool{
+			Name:        t.name,
+			Description: t.description,
+			InputSchema: t.schema(),
+		})
+	}
+
+	reqBytes, err := json.Marshal(body)
+	if err != nil {
+		return chatMessage{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.addr+"/v1/messages", bytes.NewReader(reqBytes))
+	if err != nil {
+		return chatMessage{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Api-Key", a.apiKey)
+	httpReq.Header.Set("Anthropic-Version", anthropicAPIVersion)
+
+	httpResp, err := a.client.Do(httpReq)
+	if err != nil {
+		return chatMessage{}, err
+	}
+	defer httpResp.Body.Close()
+	respBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return chatMessage{}, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		var e anthropicError
+		if err := json.Unmarshal(respBytes, &e); err == nil && e.Error.Message != "" {
+			return chatMessage{}, fmt.Errorf("anthropic API returned %s: %s", e.Error.Type, e.Error.Message)
+		}
+		return chatMessage{}, fmt.Errorf("anthropic API returned unexpected status code %d: %s", httpResp.StatusCode, respBytes)
+	}
+
+	var resp anthropicResponse
+	if err := json.Unmarshal(respBytes, &resp); err != nil {
+		return chatMessage{}, fmt.Errorf("unable to parse anthropic response: %w", err)
+	}
+	out := chatMessage{role: chatRoleAssistant}
+	var text []string
+	for _, c := range resp.Content {
+		switch c.Type {
+		case "text":
+			text = append(text, c.Text)
+		case "tool_use":
+			args, err := toolArgs(string(c.Input))
+			if err != nil {
+				return chatMessage{}, err
+			}
+			out.toolCalls = append(out.toolCalls, toolCall{
+				id:   c.ID,
+				name: c.Name,
+				args: args,
+			})
+		}
+	}
+	out.text = strings.Join(text, "")
+	return out, nil
+}
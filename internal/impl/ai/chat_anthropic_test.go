@@ -0,0 +1,95 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatAnthropic(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		assert.Equal(t, anthropicAPIVersion, r.Header.Get("Anthropic-Version"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+  "model": "claude",
+  "max_tokens": 4096,
+  "system": "be helpful",
+  "messages": [
+    {"role": "user", "content": [{"type": "text", "text": "hello"}]},
+    {"role": "assistant", "content": [
+      {"type": "text", "text": "let me check"},
+      {"type": "tool_use", "id": "a", "name": "double", "input": {"n": 1}},
+      {"type": "tool_use", "id": "b", "name": "double", "input": {}}
+    ]},
+    {"role": "user", "content": [
+      {"type": "tool_result", "tool_use_id": "a", "content": "2"},
+      {"type": "tool_result", "tool_use_id": "b", "content": "0"}
+    ]}
+  ],
+  "tools": [{
+    "name": "double",
+    "description": "Doubles a number.",
+    "input_schema": {"type": "object", "required": [], "properties": {}}
+  }]
+}`, string(body))
+		_, _ = w.Write([]byte(`{
+  "content": [
+    {"type": "text", "text": "one more"},
+    {"type": "tool_use", "id": "c", "name": "double", "input": {"n": 3}}
+  ],
+  "stop_reason": "tool_use"
+}`))
+	}))
+	defer srv.Close()
+
+	model := newAnthropicChatModel(srv.URL+"/", "secret", "claude")
+	resp, err := model.chat(context.Background(), &chatRequest{
+		system: "be helpful",
+		messages: []chatMessage{
+			{role: chatRoleUser, text: "hello"},
+			{role: chatRoleAssistant, text: "let me check", toolCalls: []toolCall{
+				{id: "a", name: "double", args: map[string]any{"n": 1}},
+				{id: "b", name: "double"},
+			}},
+			{role: chatRoleTool, text: "2", toolResult: &toolResult{id: "a", name: "double"}},
+			{role: chatRoleTool, text: "0", toolResult: &toolResult{id: "b", name: "double"}},
+		},
+		tools: []toolSpec{{name: "double", description: "Doubles a number."}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, chatMessage{
+		role:      chatRoleAssistant,
+		text:      "one more",
+		toolCalls: []toolCall{{id: "c", name: "double", args: map[string]any{"n": 3.0}}},
+	}, resp)
+}
+
+func TestChatAnthropicError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"bad model"}}`))
+	}))
+	defer srv.Close()
+
+	model := newAnthropicChatModel(srv.URL, "secret", "claude")
+	_, err := model.chat(context.Background(), &chatRequest{
+		messages: []chatMessage{{role: chatRoleUser, text: "hello"}},
+	})
+	require.EqualError(t, err, "anthropic API returned invalid_request_error: bad model")
+}
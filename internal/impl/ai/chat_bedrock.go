@@ -0,0 +1,164 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	bedrocktypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/aws"
+)
+
+// bedrockClient is the subset of the Bedrock runtime client used for chat,
+// which allows mocking it in tests.
+type bedrockClient interface {
+	Converse(ctx context.Context, params *bedrockruntime.ConverseInput, optFns ...func(*bedrockruntime.Options)) (*bedrockruntime.ConverseOutput, error)
+}
+
+type bedrockChatModel struct {
+	client bedrockClient
+	model  string
+}
+
+func newBedrockChatModel(ctx context.Context, conf *service.ParsedConfig, model string) (*bedrockChatModel, error) {
+	aconf, err := aws.GetSession(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	return &bedrockChatModel{client: bedrockruntime.NewFromConfig(aconf), model: model}, nil
+}
+
+func (b *bedrockChatModel) chat(ctx context.Context, req *chatRequest) (chatMessage, error) {
+	input := &bedrockruntime.ConverseInput{
+		ModelId:         &b.model,
+		InferenceConfig: &bedrocktypes.InferenceConfiguration{},
+	}
+	if req.maxTokens != nil {
+		input.InferenceConfig.MaxTokens = awssdk.Int32(int32(*req.maxTokens))
+	}
+	if req.temperature != nil {
+		input.InferenceConfig.Temperature = awssdk.Float32(float32(*req.temperature))
+	}
+	if req.system != "" {
+		input.System = []bedrocktypes.SystemContentBlock{
+			&bedrocktypes.SystemContentBlockMemberText{Value: req.system},
+		}
+	}
+	for _, m := range req.messages {
+		switch m.role {
+		case chatRoleUser:
+			input.Messages = append(input.Messages, bedrocktypes.Message{
+				Role: bedrocktypes.ConversationRoleUser,
+				Content: []bedrocktypes.ContentBlock{
+					&bedrocktypes.ContentBlockMemberText{Value: m.text},
+				},
+			})
+		case chatRoleAssistant:
+			msg := bedrocktypes.Message{Role: bedrocktypes.ConversationRoleAssistant}
+			if m.text != "" {
+				msg.Content = append(msg.Content, &bedrocktypes.ContentBlockMemberText{Value: m.text})
+			}
+			for _, c := range m.toolCalls {
+				args := c.args
+				if args == nil {
+					args = map[string]any{}
+				}
+				msg.Content = append(msg.Content, &bedrocktypes.ContentBlockMemberToolUse{
+					Value: bedrocktypes.ToolUseBlock{
+						ToolUseId: awssdk.String(c.id),
+						Name:      awssdk.String(c.name),
+						Input:     document.NewLazyDocument(args),
+					},
+				})
+			}
+			input.Messages = append(input.Messages, msg)
+		case chatRoleTool:
+			block := &bedrocktypes.ContentBlockMemberToolResult{
+				Value: bedrocktypes.ToolResultBlock{
+					ToolUseId: awssdk.String(m.toolResult.id),
+					Content: []bedrocktypes.ToolResultContentBlock{
+						&bedrocktypes.ToolResultContentBlockMemberText{Value: m.text},
+					},
+				},
+			}
+			// The results of all tool calls requested by a single response must
+			// be sent within the same user message.
+			if n := len(input.Messages); n > 0 && isBedrockToolResults(input.Messages[n-1]) {
+				input.Messages[n-1].Content = append(input.Messages[n-1].Content, block)
+			} else {
+				input.Messages = append(input.Messages, bedrocktypes.Message{
+					Role:    bedrocktypes.ConversationRoleUser,
+					Content: []bedrocktypes.ContentBlock{block},
+				})
+			}
+		}
+	}
+	if len(req.tools) > 0 {
+		input.ToolConfig = &bedrocktypes.ToolConfiguration{}
+		for _, t := range req.tools {
+			input.ToolConfig.Tools = append(input.ToolConfig.Tools, &bedrocktypes.ToolMemberToolSpec{
+				Value: bedrocktypes.ToolSpecification{
+					Name:        awssdk.String(t.name),
+					Description: awssdk.String(t.description),
+					InputSchema: &bedrocktypes.ToolInputSchemaMemberJson{
+						Value: document.NewLazyDocument(t.schema()),
+					},
+				},
+			})
+		}
+	}
+
+	resp, err := b.client.Converse(ctx, input)
+	if err != nil {
+		return chatMessage{}, err
+	}
+	respOut, ok := resp.Output.(*bedrocktypes.ConverseOutputMemberMessage)
+	if !ok {
+		return chatMessage{}, fmt.Errorf("unexpected output: %T", resp.Output)
+	}
+	out := chatMessage{role: chatRoleAssistant}
+	var text []string
+	for _, c := range respOut.Value.Content {
+		switch c := c.(type) {
+		case *bedrocktypes.ContentBlockMemberText:
+			text = append(text, c.Value)
+		case *bedrocktypes.ContentBlockMemberToolUse:
+			args := map[string]any{}
+			if c.Value.Input != nil {
+				if err := c.Value.Input.UnmarshalSmithyDocument(&args); err != nil {
+					return chatMessage{}, fmt.Errorf("unable to parse tool call arguments: %w", err)
+				}
+			}
+			out.toolCalls = append(out.toolCalls, toolCall{
+				id:   awssdk.ToString(c.Value.ToolUseId),
+				name: awssdk.ToString(c.Value.Name),
+				args: args,
+			})
+		default:
+			return chatMessage{}, fmt.Errorf("unsupported response content type: %T", c)
+		}
+	}
+	out.text = strings.Join(text, "")
+	return out, nil
+}
+
+func isBedrockToolResults(msg bedrocktypes.Message) bool {
+	if msg.Role != bedrocktypes.ConversationRoleUser || len(msg.Content) == 0 {
+		return false
+	}
+	_, ok := msg.Content[0].(*bedrocktypes.ContentBlockMemberToolResult)
+	return ok
+}
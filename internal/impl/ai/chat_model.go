@@ -0,0 +1,96 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type chatRole string
+
+const (
+	chatRoleUser      chatRole = "user"
+	chatRoleAssistant chatRole = "assistant"
+	chatRoleTool      chatRole = "tool"
+)
+
+// chatMessage is a provider agnostic message within a conversation. Assistant
+// messages may contain tool calls, and tool messages contain the result of a
+// single tool call.
+type chatMessage struct {
+	role       chatRole
+	text       string
+	toolCalls  []toolCall
+	toolResult *toolResult
+}
+
+type toolCall struct {
+	id   string
+	name string
+	args map[string]any
+}
+
+type toolResult struct {
+	id   string
+	name string
+}
+
+type chatRequest struct {
+	system      string
+	messages    []chatMessage
+	tools       []toolSpec
+	maxTokens   *int
+	temperature *float64
+}
+
+// chatModel is implemented by each of the supported providers, and generates
+// the next assistant message of a conversation.
+type chatModel interface {
+	chat(ctx context.Context, req *chatRequest) (chatMessage, error)
+}
+
+func newChatModel(ctx context.Context, provider string, conf *service.ParsedConfig) (chatModel, error) {
+	model, err := conf.FieldString(acpFieldModel)
+	if err != nil {
+		return nil, err
+	}
+	var addr string
+	if conf.Contains(acpFieldServerAddress) {
+		if addr, err = conf.FieldString(acpFieldServerAddress); err != nil {
+			return nil, err
+		}
+	}
+	apiKey, err := conf.FieldString(acpFieldAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	switch provider {
+	case providerOpenAI:
+		return newOpenAIChatModel(addr, apiKey, model), nil
+	case providerAzureOpenAI:
+		if addr == "" {
+			return nil, fmt.Errorf("field `%s` is required when the provider is %s", acpFieldServerAddress, provider)
+		}
+		version, err := conf.FieldString(acpFieldAPIVersion)
+		if err != nil {
+			return nil, err
+		}
+		return newAzureOpenAIChatModel(addr, apiKey, version, model), nil
+	case providerAnthropic:
+		return newAnthropicChatModel(addr, apiKey, model), nil
+	case providerBedrock:
+		return newBedrockChatModel(ctx, conf.Namespace(acpFieldAWS), model)
+	case providerOllama:
+		return newOllamaChatModel(addr, model)
+	}
+	return nil, fmt.Errorf("unsupported provider: %s", provider)
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ollama/ollama/api"
+)
+
+const ollamaDefaultAddress = "http://127.0.0.1:11434"
+
+// ollamaClient is the subset of the Ollama client used for chat, which allows
+// mocking it in tests.
+type ollamaClient interface {
+	Chat(ctx context.Context, req *api.ChatRequest, fn api.ChatResponseFunc) error
+}
+
+type ollamaChatModel struct {
+	client ollamaClient
+	model  string
+}
+
+func newOllamaChatModel(addr, model string) (*ollamaChatModel, error) {
+	if addr == "" {
+		addr = ollamaDefaultAddress
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse server address: %w", err)
+	}
+	return &ollamaChatModel{client: api.NewClient(u, http.DefaultClient), model: model}, nil
+}
+
+func (o *ollamaChatModel) chat(ctx context.Context, req *chatRequest) (chatMessage, error) {
+	shouldStream := false
+	body := api.ChatRequest{
+		Model:   o.model,
+		Stream:  &shouldStream,
+		Options: map[string]any{},
+	}
+	if req.maxTokens != nil {
+		body.Options["num_predict"] = *req.maxTokens
+	}
+	if req.temperature != nil {
+		body.Options["temperature"] = *req.temperature
+	}
+	if req.system != "" {
+		body.Messages = append(body.Messages, api.Message{Role: "system", Content: req.system})
+	}
+	for _, m := range req.messages {
+		msg := api.Message{Role: string(m.role), Content: m.text}
+		for _, c := range m.toolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, api.ToolCall{
+				Function: api.ToolCallFunction{
+					Name:      c.name,
+					Arguments: c.args,
+				},
+			})
+		}
+		body.Messages = append(body.Messages, msg)
+	}
+	for _, t := range req.tools {
+		tool := api.Tool{Type: "function"}
+		tool.Function.Name = t.name
+		tool.Function.Description = t.description
+		// The parameters are a fixed struct in the Ollama API, so the schema is
+		// round tripped through JSON.
+		schema, err := json.Marshal(t.schema())
+		if err != nil {
+			return chatMessage{}, err
+		}
+		if err := json.Unmarshal(schema, &tool.Function.Parameters); err != nil {
+			return chatMessage{}, err
+		}
+		body.Tools = append(body.Tools, tool)
+	}
+
+	var resp api.ChatResponse
+	if err := o.client.Chat(ctx, &body, func(r api.ChatResponse) error {
+		resp = r
+		return nil
+	}); err != nil {
+		return chatMessage{}, err
+	}
+	out := chatMessage{role: chatRoleAssistant, text: resp.Message.Content}
+	for _, c := range resp.Message.ToolCalls {
+		out.toolCalls = append(out.toolCalls, toolCall{
+			name: c.Function.Name,
+			args: c.Function.Arguments,
+		})
+	}
+	return out, nil
+}
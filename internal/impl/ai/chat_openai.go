@@ -0,0 +1,127 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	oai "github.com/sashabaranov/go-openai"
+)
+
+// openAIClient is the subset of the OpenAI client used for chat, which allows
+// mocking it in tests.
+type openAIClient interface {
+	CreateChatCompletion(ctx context.Context, body oai.ChatCompletionRequest) (oai.ChatCompletionResponse, error)
+}
+
+type openAIChatModel struct {
+	client openAIClient
+	model  string
+}
+
+func newOpenAIChatModel(addr, apiKey, model string) *openAIChatModel {
+	cfg := oai.DefaultConfig(apiKey)
+	if addr != "" {
+		cfg.BaseURL = addr
+	}
+	return &openAIChatModel{client: oai.NewClientWithConfig(cfg), model: model}
+}
+
+func newAzureOpenAIChatModel(addr, apiKey, version, model string) *openAIChatModel {
+	cfg := oai.DefaultAzureConfig(apiKey, addr)
+	cfg.APIVersion = version
+	// The model is the name of a deployment, which is used verbatim.
+	cfg.AzureModelMapperFunc = func(model string) string { return model }
+	return &openAIChatModel{client: oai.NewClientWithConfig(cfg), model: model}
+}
+
+func (o *openAIChatModel) chat(ctx context.Context, req *chatRequest) (chatMessage, error) {
+	body := oai.ChatCompletionRequest{Model: o.model}
+	if req.maxTokens != nil {
+		body.MaxTokens = *req.maxTokens
+	}
+	if req.temperature != nil {
+		body.Temperature = float32(*req.temperature)
+	}
+	if req.system != "" {
+		body.Messages = append(body.Messages, oai.ChatCompletionMessage{
+			Role:    oai.ChatMessageRoleSystem,
+			Content: req.system,
+		})
+	}
+	for _, m := range req.messages {
+		switch m.role {
+		case chatRoleUser:
+			body.Messages = append(body.Messages, oai.ChatCompletionMessage{
+				Role:    oai.ChatMessageRoleUser,
+				Content: m.text,
+			})
+		case chatRoleAssistant:
+			msg := oai.ChatCompletionMessage{
+				Role:    oai.ChatMessageRoleAssistant,
+				Content: m.text,
+			}
+			for _, c := range m.toolCalls {
+				args, err := json.Marshal(c.args)
+				if err != nil {
+					return chatMessage{}, err
+				}
+				msg.ToolCalls = append(msg.ToolCalls, oai.ToolCall{
+					ID:   c.id,
+					Type: oai.ToolTypeFunction,
+					Function: oai.FunctionCall{
+						Name:      c.name,
+						Arguments: string(args),
+					},
+				})
+			}
+			body.Messages = append(body.Messages, msg)
+		case chatRoleTool:
+			body.Messages = append(body.Messages, oai.ChatCompletionMessage{
+				Role:       oai.ChatMessageRoleTool,
+				Content:    m.text,
+				ToolCallID: m.toolResult.id,
+			})
+		}
+	}
+	for _, t := range req.tools {
+		body.Tools = append(body.Tools, oai.Tool{
+			Type: oai.ToolTypeFunction,
+			Function: &oai.FunctionDefinition{
+				Name:        t.name,
+				Description: t.description,
+				Parameters:  t.schema(),
+			},
+		})
+	}
+
+	resp, err := o.client.CreateChatCompletion(ctx, body)
+	if err != nil {
+		return chatMessage{}, err
+	}
+	if len(resp.Choices) != 1 {
+		return chatMessage{}, errors.New("invalid OpenAI response, expected exactly one choice")
+	}
+	respMsg := resp.Choices[0].Message
+	out := chatMessage{role: chatRoleAssistant, text: respMsg.Content}
+	for _, c := range respMsg.ToolCalls {
+		args, err := toolArgs(c.Function.Arguments)
+		if err != nil {
+			return chatMessage{}, err
+		}
+		out.toolCalls = append(out.toolCalls, toolCall{
+			id:   c.ID,
+			name: c.Function.Name,
+			args: args,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,301 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/aws/config"
+	"github.com/redpanda-data/connect/v4/internal/license"
+)
+
+const (
+	acpFieldProvider      = "provider"
+	acpFieldModel         = "model"
+	acpFieldServerAddress = "server_address"
+	acpFieldAPIKey        = "api_key"
+	acpFieldAPIVersion    = "api_version"
+	acpFieldAWS           = "aws"
+	acpFieldUserPrompt    = "prompt"
+	acpFieldSystemPrompt  = "system_prompt"
+	acpFieldMaxTokens     = "max_tokens"
+	acpFieldTemp          = "temperature"
+	acpFieldMaxToolCalls  = "max_tool_calls"
+)
+
+const (
+	providerOpenAI      = "openai"
+	providerAzureOpenAI = "azure_openai"
+	providerAnthropic   = "anthropic"
+	providerBedrock     = "bedrock"
+	providerOllama      = "ollama"
+)
+
+func init() {
+	err := service.RegisterProcessor("ai_chat", aiChatProcessorConfig(), newAIChatProcessor)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func aiChatProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("AI").
+		Summary("Generates responses to messages in a chat conversation, using one of several large language model (LLM) providers.").
+		Description(`This processor sends prompts to a large language model (LLM) and replaces the message with the generated response. The same configuration works with each of the supported providers, which makes it possible to switch between them by changing the `+"`"+acpFieldProvider+"` and `"+acpFieldModel+"`"+` fields:
+
+- `+"`openai`"+`: The https://platform.openai.com/docs/api-reference/chat[OpenAI chat completions API^], or any compatible service.
+- `+"`azure_openai`"+`: A model deployment within https://learn.microsoft.com/en-us/azure/ai-services/openai/[Azure OpenAI^], where `+"`"+acpFieldModel+"`"+` is the name of the deployment.
+- `+"`anthropic`"+`: The https://docs.anthropic.com/en/api/messages[Anthropic messages API^].
+- `+"`bedrock`"+`: The https://docs.aws.amazon.com/bedrock/latest/userguide/conversation-inference.html[AWS Bedrock Converse API^], authenticated with the `+"`"+acpFieldAWS+"`"+` fields.
+- `+"`ollama`"+`: An already running https://github.com/ollama/ollama[Ollama^] server.
+
+== Tools
+
+The `+"`"+actFieldTools+"`"+` field lists functions that the model can choose to call while generating a response. When the model calls a tool the processor executes it with the arguments provided by the model, sends the result back to the model and waits for the next response. This repeats until the model responds without calling a tool, or until `+"`"+acpFieldMaxToolCalls+"`"+` rounds of tool calls have been made, in which case the message fails.
+
+Each tool is either a `+"`"+actFieldBloblang+"`"+` mapping, which is executed against the arguments as a JSON object, or an `+"`"+actFieldHTTP+"`"+` endpoint, which is sent the arguments as a JSON request body. The result of the mapping or the body of the response is returned to the model.`).
+		Version("4.48.0").
+		Fields(
+			service.NewStringEnumField(acpFieldProvider, providerOpenAI, providerAzureOpenAI, providerAnthropic, providerBedrock, providerOllama).
+				Description("The LLM provider to send requests to."),
+			service.NewStringField(acpFieldModel).
+				Description("The name of the model to use. When the provider is `azure_openai` this is the name of the model deployment.").
+				Examples("gpt-4o", "claude-3-5-sonnet-latest", "anthropic.claude-3-5-sonnet-20240620-v1:0", "llama3.2"),
+			service.NewStringField(acpFieldServerAddress).
+				Description("The address of the provider API. By default the public API of the provider is used, or `http://127.0.0.1:11434` for `ollama`. This field is required when the provider is `azure_openai`, in which case it is the endpoint of the Azure OpenAI resource.").
+				Example("https://example-resource.openai.azure.com").
+				Optional(),
+			service.NewStringField(acpFieldAPIKey).
+				Description("The API key to authenticate with. This field is used by the `openai`, `azure_openai` and `anthropic` providers.").
+				Secret().
+				Default(""),
+			service.NewStringField(acpFieldAPIVersion).
+				Description("The API version to use when the provider is `azure_openai`.").
+				Default("2024-06-01").
+				Advanced(),
+			service.NewObjectField(acpFieldAWS, config.SessionFields()...).
+				Description("The AWS configuration to use when the provider is `bedrock`.").
+				Advanced(),
+			service.NewInterpolatedStringField(acpFieldUserPrompt).
+				Description("The prompt you want to generate a response for. By default, the processor submits the entire payload as a string.").
+				Optional(),
+			service.NewInterpolatedStringField(acpFieldSystemPrompt).
+				Description("The system prompt to submit to the model.").
+				Optional(),
+			service.NewIntField(acpFieldMaxTokens).
+				Description("The maximum number of tokens to generate in each response. The `anthropic` provider requires a limit and defaults to `4096` when this field is not set.").
+				Optional().
+				LintRule(`root = if this < 1 { ["field must be greater than or equal to 1"] }`),
+			service.NewFloatField(acpFieldTemp).
+				Description("The sampling temperature of the model. Higher values make the output more random.").
+				Optional().
+				LintRule(`root = if this < 0 || this > 2 { ["field must be between 0.0 and 2.0"] }`),
+			service.NewIntField(acpFieldMaxToolCalls).
+				Description("The maximum number of sequential rounds of tool calls.").
+				Default(3).
+				Advanced().
+				LintRule(`root = if this <= 0 { ["field must be greater than zero"] }`),
+			toolsField(),
+		).
+		Example(
+			"Answer questions with a Bloblang tool",
+			"This example allows the model to look up the stock level of a product by calling a Bloblang mapping.",
+			`
+pipeline:
+  processors:
+    - ai_chat:
+        provider: anthropic
+        model: claude-3-5-sonnet-latest
+        api_key: "${ANTHROPIC_API_KEY}"
+        prompt: "${!this.question}"
+        tools:
+          - name: get_stock_level
+            description: Retrieve the number of units in stock for a product.
+            parameters:
+              required: [ "sku" ]
+              properties:
+                sku:
+                  type: string
+                  description: The SKU of the product.
+            bloblang: |
+              root.sku = this.sku
+              root.units = match this.sku {
+                "A-100" => 12,
+                "B-200" => 0,
+                _ => throw("unknown product")
+              }
+`).
+		Example(
+			"Call an HTTP endpoint as a tool",
+			"This example allows a model hosted in AWS Bedrock to fetch the weather for a city from an HTTP service.",
+			`
+pipeline:
+  processors:
+    - ai_chat:
+        provider: bedrock
+        model: anthropic.claude-3-5-sonnet-20240620-v1:0
+        aws:
+          region: us-east-1
+        prompt: "${!content().string()}"
+        tools:
+          - name: get_weather
+            description: Retrieve the weather for a specific city.
+            parameters:
+              required: [ "city" ]
+              properties:
+                city:
+                  type: string
+                  description: The city to look up the weather for.
+            http:
+              url: 'https://wttr.in/${!this.city.escape_url_query()}?format=j1'
+              verb: GET
+`)
+}
+
+func newAIChatProcessor(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+	if err := license.CheckRunningEnterprise(mgr); err != nil {
+		return nil, err
+	}
+
+	provider, err := conf.FieldString(acpFieldProvider)
+	if err != nil {
+		return nil, err
+	}
+	model, err := newChatModel(context.Background(), provider, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &aiChatProcessor{model: model, logger: mgr.Logger()}
+	if conf.Contains(acpFieldUserPrompt) {
+		if p.userPrompt, err = conf.FieldInterpolatedString(acpFieldUserPrompt); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(acpFieldSystemPrompt) {
+		if p.systemPrompt, err = conf.FieldInterpolatedString(acpFieldSystemPrompt); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(acpFieldMaxTokens) {
+		v, err := conf.FieldInt(acpFieldMaxTokens)
+		if err != nil {
+			return nil, err
+		}
+		p.maxTokens = &v
+	}
+	if conf.Contains(acpFieldTemp) {
+		v, err := conf.FieldFloat(acpFieldTemp)
+		if err != nil {
+			return nil, err
+		}
+		p.temperature = &v
+	}
+	if p.maxToolCalls, err = conf.FieldInt(acpFieldMaxToolCalls); err != nil {
+		return nil, err
+	}
+	if p.tools, err = toolsFromParsed(conf); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type aiChatProcessor struct {
+	model  chatModel
+	logger *service.Logger
+
+	userPrompt   *service.InterpolatedString
+	systemPrompt *service.InterpolatedString
+	maxTokens    *int
+	temperature  *float64
+	maxToolCalls int
+	tools        []*chatTool
+}
+
+func (p *aiChatProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	req := chatRequest{
+		maxTokens:   p.maxTokens,
+		temperature: p.temperature,
+	}
+	if p.systemPrompt != nil {
+		sp, err := p.systemPrompt.TryString(msg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to interpolate `%s`: %w", acpFieldSystemPrompt, err)
+		}
+		req.system = sp
+	}
+	up, err := p.computePrompt(msg)
+	if err != nil {
+		return nil, err
+	}
+	req.messages = []chatMessage{{role: chatRoleUser, text: up}}
+	for _, t := range p.tools {
+		req.tools = append(req.tools, t.spec)
+	}
+
+	// Allow up to N rounds of calling tools
+	for range p.maxToolCalls + 1 {
+		resp, err := p.model.chat(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.toolCalls) == 0 {
+			out := msg.Copy()
+			out.SetBytes([]byte(resp.text))
+			return service.MessageBatch{out}, nil
+		}
+		req.messages = append(req.messages, resp)
+		for _, call := range resp.toolCalls {
+			p.logger.Debugf("LLM requested tool %s with arguments: %v", call.name, call.args)
+			idx := slices.IndexFunc(p.tools, func(t *chatTool) bool { return t.spec.name == call.name })
+			if idx < 0 {
+				return nil, fmt.Errorf("unknown tool call requested: %s", call.name)
+			}
+			result, err := p.tools[idx].execute(ctx, call.args)
+			if err != nil {
+				return nil, fmt.Errorf("error calling tool %s: %w", call.name, err)
+			}
+			p.logger.Debugf("Tool %s response: %s", call.name, result)
+			req.messages = append(req.messages, chatMessage{
+				role: chatRoleTool,
+				text: result,
+				toolResult: &toolResult{
+					id:   call.id,
+					name: call.name,
+				},
+			})
+		}
+	}
+	return nil, fmt.Errorf("model did not finish after %d rounds of tool calls", p.maxToolCalls)
+}
+
+func (p *aiChatProcessor) computePrompt(msg *service.Message) (string, error) {
+	if p.userPrompt != nil {
+		return p.userPrompt.TryString(msg)
+	}
+	b, err := msg.AsBytes()
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("message payload contained invalid UTF8")
+	}
+	return string(b), nil
+}
+
+func (p *aiChatProcessor) Close(ctx context.Context) error {
+	return nil
+}
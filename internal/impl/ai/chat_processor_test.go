@@ -0,0 +1,280 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	oai "github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChatModel struct {
+	requests  []chatRequest
+	responses []chatMessage
+}
+
+func (f *fakeChatModel) chat(ctx context.Context, req *chatRequest) (chatMessage, error) {
+	r := *req
+	r.messages = append([]chatMessage(nil), req.messages...)
+	f.requests = append(f.requests, r)
+	resp := f.responses[0]
+	f.responses = f.responses[1:]
+	return resp, nil
+}
+
+func toolsForTest(t *testing.T, yaml string) []*chatTool {
+	t.Helper()
+	conf, err := service.NewConfigSpec().Field(toolsField()).ParseYAML(yaml, nil)
+	require.NoError(t, err)
+	tools, err := toolsFromParsed(conf)
+	require.NoError(t, err)
+	return tools
+}
+
+func TestChatToolCalls(t *testing.T) {
+	model := &fakeChatModel{
+		responses: []chatMessage{
+			{
+				role: chatRoleAssistant,
+				toolCalls: []toolCall{
+					{id: "1", name: "double", args: map[string]any{"n": 2}},
+					{id: "2", name: "double", args: map[string]any{"n": 5}},
+				},
+			},
+			{role: chatRoleAssistant, text: "the answers are 4 and 10"},
+		},
+	}
+	p := &aiChatProcessor{
+		model:        model,
+		logger:       service.MockResources().Logger(),
+		maxToolCalls: 3,
+		tools: toolsForTest(t, `
+tools:
+  - name: double
+    description: Doubles a number.
+    parameters:
+      required: [ n ]
+      properties:
+        n:
+          type: number
+          description: The number to double.
+    bloblang: 'root.result = this.n * 2'
+`),
+	}
+
+	out, err := p.Process(context.Background(), service.NewMessage([]byte("double 2 and 5")))
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	b, err := out[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "the answers are 4 and 10", string(b))
+
+	require.Len(t, model.requests, 2)
+	assert.Equal(t, []chatMessage{{role: chatRoleUser, text: "double 2 and 5"}}, model.requests[0].messages)
+	require.Len(t, model.requests[0].tools, 1)
+	assert.Equal(t, "double", model.requests[0].tools[0].name)
+
+	msgs := model.requests[1].messages
+	require.Len(t, msgs, 4)
+	assert.Equal(t, chatRoleAssistant, msgs[1].role)
+	assert.Equal(t, chatMessage{role: chatRoleTool, text: `{"result":4}`, toolResult: &toolResult{id: "1", name: "double"}}, msgs[2])
+	assert.Equal(t, chatMessage{role: chatRoleTool, text: `{"result":10}`, toolResult: &toolResult{id: "2", name: "double"}}, msgs[3])
+}
+
+func TestChatToolCallLimit(t *testing.T) {
+	call := chatMessage{
+		role:      chatRoleAssistant,
+		toolCalls: []toolCall{{id: "1", name: "noop"}},
+	}
+	p := &aiChatProcessor{
+		model:        &fakeChatModel{responses: []chatMessage{call, call, call}},
+		logger:       service.MockResources().Logger(),
+		maxToolCalls: 2,
+		tools: toolsForTest(t, `
+tools:
+  - name: noop
+    description: Does nothing.
+    parameters:
+      properties: {}
+    bloblang: 'root = "ok"'
+`),
+	}
+
+	_, err := p.Process(context.Background(), service.NewMessage([]byte("hello")))
+	require.ErrorContains(t, err, "model did not finish after 2 rounds of tool calls")
+}
+
+func TestChatUnknownTool(t *testing.T) {
+	p := &aiChatProcessor{
+		model: &fakeChatModel{responses: []chatMessage{{
+			role:      chatRoleAssistant,
+			toolCalls: []toolCall{{id: "1", name: "missing"}},
+		}}},
+		logger:       service.MockResources().Logger(),
+		maxToolCalls: 2,
+	}
+
+	_, err := p.Process(context.Background(), service.NewMessage([]byte("hello")))
+	require.ErrorContains(t, err, "unknown tool call requested: missing")
+}
+
+func TestChatHTTPTool(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "/cities/chicago", r.URL.Path)
+		assert.Equal(t, "chicago", r.Header.Get("X-City"))
+		assert.JSONEq(t, `{"city":"chicago","units":"metric"}`, string(body))
+		_, _ = w.Write([]byte(`{"temp":21}`))
+	}))
+	defer srv.Close()
+
+	tools := toolsForTest(t, `
+tools:
+  - name: weather
+    description: Gets the weather.
+    parameters:
+      properties: {}
+    http:
+      url: '`+srv.URL+`/cities/${!this.city}'
+      headers:
+        X-City: '${!this.city}'
+`)
+	require.Len(t, tools, 1)
+	res, err := tools[0].execute(context.Background(), map[string]any{"city": "chicago", "units": "metric"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"temp":21}`, res)
+}
+
+func TestChatHTTPToolErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	tools := toolsForTest(t, `
+tools:
+  - name: weather
+    description: Gets the weather.
+    parameters:
+      properties: {}
+    http:
+      url: '`+srv.URL+`'
+      verb: GET
+`)
+	_, err := tools[0].execute(context.Background(), nil)
+	require.ErrorContains(t, err, "unexpected status code 502")
+}
+
+func TestChatToolConfigValidation(t *testing.T) {
+	spec := service.NewConfigSpec().Field(toolsField())
+	for name, yaml := range map[string]string{
+		"neither": `
+tools:
+  - name: foo
+    description: foo
+    parameters:
+      properties: {}
+`,
+		"both": `
+tools:
+  - name: foo
+    description: foo
+    parameters:
+      properties: {}
+    bloblang: 'root = this'
+    http:
+      url: http://localhost
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := spec.ParseYAML(yaml, nil)
+			require.NoError(t, err)
+			_, err = toolsFromParsed(conf)
+			require.ErrorContains(t, err, "tool foo must specify exactly one of")
+		})
+	}
+}
+
+func TestToolSpecSchema(t *testing.T) {
+	spec := toolSpec{
+		name:     "foo",
+		required: []string{"a"},
+		properties: map[string]toolParam{
+			"a": {Type: "string", Description: "The a.", Enum: []string{"x", "y"}},
+			"b": {Type: "number", Description: "The b."},
+		},
+	}
+	b, err := json.Marshal(spec.schema())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "type": "object",
+  "required": ["a"],
+  "properties": {
+    "a": {"type": "string", "description": "The a.", "enum": ["x", "y"]},
+    "b": {"type": "number", "description": "The b."}
+  }
+}`, string(b))
+}
+
+type mockOpenAIClient struct {
+	requests []oai.ChatCompletionRequest
+}
+
+func (m *mockOpenAIClient) CreateChatCompletion(ctx context.Context, body oai.ChatCompletionRequest) (oai.ChatCompletionResponse, error) {
+	m.requests = append(m.requests, body)
+	return oai.ChatCompletionResponse{
+		Choices: []oai.ChatCompletionChoice{{
+			Message: oai.ChatCompletionMessage{
+				Role: oai.ChatMessageRoleAssistant,
+				ToolCalls: []oai.ToolCall{{
+					ID:       "call_1",
+					Type:     oai.ToolTypeFunction,
+					Function: oai.FunctionCall{Name: "double", Arguments: `{"n":2}`},
+				}},
+			},
+		}},
+	}, nil
+}
+
+func TestChatOpenAIMessages(t *testing.T) {
+	client := &mockOpenAIClient{}
+	model := &openAIChatModel{client: client, model: "gpt-4o"}
+
+	resp, err := model.chat(context.Background(), &chatRequest{
+		system: "be helpful",
+		messages: []chatMessage{
+			{role: chatRoleUser, text: "hello"},
+			{role: chatRoleAssistant, toolCalls: []toolCall{{id: "call_0", name: "double", args: map[string]any{"n": 1}}}},
+			{role: chatRoleTool, text: "2", toolResult: &toolResult{id: "call_0", name: "double"}},
+		},
+		tools: []toolSpec{{name: "double", description: "Doubles a number."}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []toolCall{{id: "call_1", name: "double", args: map[string]any{"n": 2.0}}}, resp.toolCalls)
+
+	require.Len(t, client.requests, 1)
+	req := client.requests[0]
+	assert.Equal(t, "gpt-4o", req.Model)
+	require.Len(t, req.Messages, 4)
+	assert.Equal(t, oai.ChatMessageRoleSystem, req.Messages[0].Role)
+	assert.Equal(t, `{"n":1}`, req.Messages[2].ToolCalls[0].Function.Arguments)
+	assert.Equal(t, oai.ChatMessageRoleTool, req.Messages[3].Role)
+	assert.Equal(t, "call_0", req.Messages[3].ToolCallID)
+	require.Len(t, req.Tools, 1)
+	assert.Equal(t, "double", req.Tools[0].Function.Name)
+}
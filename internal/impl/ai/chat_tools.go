@@ -0,0 +1,309 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	actFieldTools                = "tools"
+	actFieldName                 = "name"
+	actFieldDesc                 = "description"
+	actFieldParams               = "parameters"
+	actParamFieldRequired        = "required"
+	actParamFieldProps           = "properties"
+	actParamPropFieldType        = "type"
+	actParamPropFieldDescription = "description"
+	actParamPropFieldEnum        = "enum"
+	actFieldBloblang             = "bloblang"
+	actFieldHTTP                 = "http"
+	actHTTPFieldURL              = "url"
+	actHTTPFieldVerb             = "verb"
+	actHTTPFieldHeaders          = "headers"
+	actHTTPFieldTimeout          = "timeout"
+)
+
+// The maximum size of an HTTP tool response that is returned to the model.
+const maxToolResponseBytes = 1 << 20
+
+func toolsField() *service.ConfigField {
+	return service.NewObjectListField(
+		actFieldTools,
+		service.NewStringField(actFieldName).Description("The name of this tool."),
+		service.NewStringField(actFieldDesc).Description("A description of this tool, the LLM uses this to decide if the tool should be used."),
+		service.NewObjectField(
+			actFieldParams,
+			service.NewStringListField(actParamFieldRequired).Default([]string{}).Description("The required parameters for this tool."),
+			service.NewObjectMapField(
+				actParamFieldProps,
+				service.NewStringField(actParamPropFieldType).Description("The type of this parameter."),
+				service.NewStringField(actParamPropFieldDescription).Description("A description of this parameter."),
+				service.NewStringListField(actParamPropFieldEnum).Default([]string{}).Description("Specifies that this parameter is an enum and only these specific values should be used."),
+			).Description("The properties for the tool's input data."),
+		).Description("The parameters the LLM needs to provide to invoke this tool."),
+		service.NewBloblangField(actFieldBloblang).
+			Description("A mapping to execute when the LLM uses this tool. The mapping is executed against the arguments of the tool call as a JSON object, and the result is returned to the LLM.").
+			Optional(),
+		service.NewObjectField(
+			actFieldHTTP,
+			service.NewInterpolatedStringField(actHTTPFieldURL).
+				Description("The URL to send a request to when the LLM uses this tool. Interpolations are resolved against the arguments of the tool call. When empty the tool must instead specify a `"+actFieldBloblang+"` mapping.\n\nThe arguments of a tool call are chosen by the LLM, and therefore might be influenced by untrusted input such as the prompt. Arguments should be escaped with `escape_url_query` when interpolated into a URL so that they cannot change its host, path or query, and tools should only be given access to endpoints that are safe for the LLM to call with arbitrary arguments.").
+				Default(""),
+			service.NewStringField(actHTTPFieldVerb).
+				Description("The HTTP verb to use. The arguments of the tool call are sent as a JSON request body for all verbs other than `GET`.").
+				Default("POST"),
+			service.NewInterpolatedStringMapField(actHTTPFieldHeaders).
+				Description("A map of headers to add to the request. Interpolations are resolved against the arguments of the tool call.").
+				Default(map[string]any{}),
+			service.NewDurationField(actHTTPFieldTimeout).
+				Description("The maximum time to wait for a response.").
+				Default("30s"),
+		).Description("An HTTP endpoint to call when the LLM uses this tool. The body of the response is returned to the LLM, and responses with a status code outside of the 2XX range fail the message."),
+	).
+		Description("The tools to allow the LLM to invoke. Each tool must specify either a `" + actFieldBloblang + "` mapping or an `" + actFieldHTTP + "` endpoint.").
+		Default([]any{})
+}
+
+// toolSpec describes a tool to the model. The parameters of a tool are an
+// object described by a JSON schema.
+type toolSpec struct {
+	name        string
+	description string
+	required    []string
+	properties  map[string]toolParam
+}
+
+type toolParam struct {
+	Type        string
+	Description string
+	Enum        []string
+}
+
+// schema returns the JSON schema of the tool parameters.
+func (t toolSpec) schema() map[string]any {
+	props := map[string]any{}
+	for k, v := range t.properties {
+		p := map[string]any{
+			"type":        v.Type,
+			"description": v.Description,
+		}
+		if len(v.Enum) > 0 {
+			enum := make([]any, len(v.Enum))
+			for i, e := range v.Enum {
+				enum[i] = e
+			}
+			p["enum"] = enum
+		}
+		props[k] = p
+	}
+	required := make([]any, len(t.required))
+	for i, r := range t.required {
+		required[i] = r
+	}
+	return map[string]any{
+		"type":       "object",
+		"required":   required,
+		"properties": props,
+	}
+}
+
+type chatTool struct {
+	spec toolSpec
+
+	mapping *bloblang.Executor
+
+	url     *service.InterpolatedString
+	verb    string
+	headers map[string]*service.InterpolatedString
+	client  *http.Client
+}
+
+func toolsFromParsed(conf *service.ParsedConfig) ([]*chatTool, error) {
+	toolConfs, err := conf.FieldObjectList(actFieldTools)
+	if err != nil {
+		return nil, err
+	}
+	var tools []*chatTool
+	for _, toolConf := range toolConfs {
+		t, err := toolFromParsed(toolConf)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, t)
+	}
+	return tools, nil
+}
+
+func toolFromParsed(conf *service.ParsedConfig) (*chatTool, error) {
+	t := &chatTool{}
+	var err error
+	if t.spec.name, err = conf.FieldString(actFieldName); err != nil {
+		return nil, err
+	}
+	if t.spec.description, err = conf.FieldString(actFieldDesc); err != nil {
+		return nil, err
+	}
+	paramsConf := conf.Namespace(actFieldParams)
+	if t.spec.required, err = paramsConf.FieldStringList(actParamFieldRequired); err != nil {
+		return nil, err
+	}
+	propsConf, err := paramsConf.FieldObjectMap(actParamFieldProps)
+	if err != nil {
+		return nil, err
+	}
+	t.spec.properties = map[string]toolParam{}
+	for name, propConf := range propsConf {
+		var p toolParam
+		if p.Type, err = propConf.FieldString(actParamPropFieldType); err != nil {
+			return nil, err
+		}
+		if p.Description, err = propConf.FieldString(actParamPropFieldDescription); err != nil {
+			return nil, err
+		}
+		if p.Enum, err = propConf.FieldStringList(actParamPropFieldEnum); err != nil {
+			return nil, err
+		}
+		t.spec.properties[name] = p
+	}
+
+	if conf.Contains(actFieldBloblang) {
+		if t.mapping, err = conf.FieldBloblang(actFieldBloblang); err != nil {
+			return nil, err
+		}
+	}
+	httpConf := conf.Namespace(actFieldHTTP)
+	rawURL, err := httpConf.FieldString(actHTTPFieldURL)
+	if err != nil {
+		return nil, err
+	}
+	if rawURL != "" {
+		if t.url, err = httpConf.FieldInterpolatedString(actHTTPFieldURL); err != nil {
+			return nil, err
+		}
+		if t.verb, err = httpConf.FieldString(actHTTPFieldVerb); err != nil {
+			return nil, err
+		}
+		if t.headers, err = httpConf.FieldInterpolatedStringMap(actHTTPFieldHeaders); err != nil {
+			return nil, err
+		}
+		timeout, err := httpConf.FieldDuration(actHTTPFieldTimeout)
+		if err != nil {
+			return nil, err
+		}
+		t.client = &http.Client{Timeout: timeout}
+	}
+	if (t.mapping == nil) == (t.url == nil) {
+		return nil, fmt.Errorf("tool %s must specify exactly one of `%s` or `%s.%s`", t.spec.name, actFieldBloblang, actFieldHTTP, actHTTPFieldURL)
+	}
+	return t, nil
+}
+
+// execute runs the tool with the arguments provided by the model and returns
+// the result to send back to the model.
+func (t *chatTool) execute(ctx context.Context, args map[string]any) (string, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(args)
+	if t.mapping != nil {
+		return t.executeMapping(msg)
+	}
+	return t.executeHTTP(ctx, msg)
+}
+
+func (t *chatTool) executeMapping(msg *service.Message) (string, error) {
+	res, err := msg.BloblangQuery(t.mapping)
+	if err != nil {
+		return "", err
+	}
+	if res == nil {
+		return "", errors.New("mapping deleted the result")
+	}
+	if res.HasStructured() {
+		v, err := res.AsStructured()
+		if err != nil {
+			return "", err
+		}
+		return bloblang.ValueToString(v), nil
+	}
+	b, err := res.AsBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func (t *chatTool) executeHTTP(ctx context.Context, msg *service.Message) (string, error) {
+	url, err := t.url.TryString(msg)
+	if err != nil {
+		return "", fmt.Errorf("unable to interpolate `%s`: %w", actHTTPFieldURL, err)
+	}
+	var body io.Reader
+	if t.verb != http.MethodGet {
+		b, err := msg.AsBytes()
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, t.verb, url, body)
+	if err != nil {
+		return "", err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range t.headers {
+		hv, err := v.TryString(msg)
+		if err != nil {
+			return "", fmt.Errorf("unable to interpolate header %s: %w", k, err)
+		}
+		req.Header.Set(k, hv)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxToolResponseBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("request returned unexpected status code %d: %s", resp.StatusCode, b)
+	}
+	if len(b) > maxToolResponseBytes {
+		return "", fmt.Errorf("response body exceeded %d bytes", maxToolResponseBytes)
+	}
+	return string(b), nil
+}
+
+// toolArgs decodes the JSON encoded arguments of a tool call.
+func toolArgs(raw string) (map[string]any, error) {
+	if raw == "" {
+		return map[string]any{}, nil
+	}
+	var args map[string]any
+	if err := json.Unmarshal([]byte(raw), &args); err != nil {
+		return nil, fmt.Errorf("unable to parse tool call arguments: %w", err)
+	}
+	return args, nil
+}
@@ -37,6 +37,12 @@ const (
 	azureRenewLockField   = "azure_renew_lock"
 	getMessageHeaderField = "read_header"
 	creditField           = "credit"
+	containerIDField      = "container_id"
+	linkNameField         = "link_name"
+	terminusField         = "terminus"
+	terminusDurableField  = "durability"
+	terminusExpiryField   = "expiry_policy"
+	terminusTimeoutField  = "expiry_timeout"
 
 	// Output
 	targetAddrField  = "target_address"
@@ -74,11 +74,46 @@ func amqp1InputSpec() *service.ConfigSpec {
 				Version("4.26.0").
 				Default(64).
 				Advanced(),
+			service.NewStringField(containerIDField).
+				Description("The container ID to identify the connection with. Brokers such as ActiveMQ and Solace identify durable subscriptions by the container ID and the link name, and therefore both must remain the same across restarts in order to resume a subscription. When empty a random ID is generated.").
+				Version("4.48.0").
+				Default("").
+				Advanced(),
+			service.NewStringField(linkNameField).
+				Description("The name of the receiver link. A durable terminus can only be resumed by a link of the same name. When empty a random name is generated.").
+				Version("4.48.0").
+				Default("").
+				Advanced(),
+			service.NewObjectField(terminusField,
+				service.NewStringAnnotatedEnumField(terminusDurableField, map[string]string{
+					"none":            "No state of the terminus is retained.",
+					"configuration":   "The configuration of the terminus is retained, such as a subscription.",
+					"unsettled_state": "The configuration and the unsettled deliveries of the terminus are retained.",
+				}).
+					Description("The state of the source terminus retained by the broker.").
+					Default("none"),
+				service.NewStringAnnotatedEnumField(terminusExpiryField, map[string]string{
+					"link_detach":      "The expiry timer starts when the link is detached.",
+					"session_end":      "The expiry timer starts when the session ends.",
+					"connection_close": "The expiry timer starts when the connection is closed.",
+					"never":            "The terminus never expires.",
+				}).
+					Description("The event that starts the expiry timer of the source terminus, after which the broker discards it.").
+					Default("session_end"),
+				service.NewDurationField(terminusTimeoutField).
+					Description("The period of time the source terminus is retained for once the expiry timer has started.").
+					Default("0s"),
+			).
+				Description("Configures the source terminus of the receiver link retained by the broker, which allows durable subscriptions to be resumed after the link is detached or the connection is lost.").
+				Version("4.48.0").
+				Advanced(),
 			service.NewTLSToggledField(tlsField),
 			saslFieldSpec(),
 		).LintRule(`
 root = if this.url.or("") == "" && this.urls.or([]).length() == 0 {
   "field 'urls' must be set"
+} else if this.terminus.durability.or("none") != "none" && this.link_name.or("") == "" {
+  "field 'link_name' must be set when the terminus is durable"
 }
 `)
 }
@@ -102,6 +137,7 @@ type amqp1Reader struct {
 	getHeader  bool
 	credit     int // max_in_flight
 	connOpts   *amqp.ConnOptions
+	rcvOpts    *amqp.ReceiverOptions
 	log        *service.Logger
 
 	m    sync.RWMutex
@@ -154,6 +190,14 @@ func amqp1ReaderFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (
 		return nil, err
 	}
 
+	if a.connOpts.ContainerID, err = conf.FieldString(containerIDField); err != nil {
+		return nil, err
+	}
+
+	if a.rcvOpts, err = receiverOptsFromParsed(conf, a.credit); err != nil {
+		return nil, err
+	}
+
 	if err := saslOptFnsFromParsed(conf, a.connOpts); err != nil {
 		return nil, err
 	}
@@ -174,7 +218,20 @@ func (a *amqp1Reader) Connect(ctx context.Context) (err error) {
 	defer a.m.Unlock()
 
 	if a.conn != nil {
-		return
+		if a.conn.receiver != nil {
+			return
+		}
+
+		// The receiver link was detached while the connection remained open,
+		// in which case the link is resumed before falling back to opening a
+		// new connection.
+		if a.conn.receiver, err = a.conn.session.NewReceiver(ctx, a.sourceAddr, a.rcvOpts); err == nil {
+			a.log.Infof("Resumed receiver link to %v", a.sourceAddr)
+			return
+		}
+		a.log.Errorf("Failed to resume receiver link, reconnecting: %v", err)
+		_ = a.conn.Close(ctx)
+		a.conn = nil
 	}
 
 	conn := &amqp1Conn{
@@ -194,9 +251,7 @@ func (a *amqp1Reader) Connect(ctx context.Context) (err error) {
 	}
 
 	// Create a receiver
-	if conn.receiver, err = conn.session.NewReceiver(ctx, a.sourceAddr, &amqp.ReceiverOptions{
-		Credit: int32(a.credit),
-	}); err != nil {
+	if conn.receiver, err = conn.session.NewReceiver(ctx, a.sourceAddr, a.rcvOpts); err != nil {
 		_ = conn.Close(ctx)
 		return
 	}
@@ -236,18 +291,28 @@ func (a *amqp1Reader) disconnect(ctx context.Context) error {
 func (a *amqp1Reader) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
 	a.m.RLock()
 	conn := a.conn
+	var receiver *amqp.Receiver
+	if conn != nil {
+		receiver = conn.receiver
+	}
 	a.m.RUnlock()
 
-	if conn == nil {
+	if receiver == nil {
 		return nil, nil, service.ErrNotConnected
 	}
 
 	// Receive next message
-	amqpMsg, err := conn.receiver.Receive(ctx, nil)
+	amqpMsg, err := receiver.Receive(ctx, nil)
 	if err != nil {
 		if ctx.Err() == nil {
-			a.log.Errorf("Lost connection due to: %v", err)
-			_ = a.disconnect(ctx)
+			var linkErr *amqp.LinkError
+			if errors.As(err, &linkErr) {
+				a.log.Warnf("Receiver link detached due to: %v", err)
+				a.detachReceiver(ctx, conn, receiver)
+			} else {
+				a.log.Errorf("Lost connection due to: %v", err)
+				_ = a.disconnect(ctx)
+			}
 			err = service.ErrNotConnected
 		}
 		return nil, nil, err
@@ -300,20 +365,88 @@ func (a *amqp1Reader) ReadBatch(ctx context.Context) (service.MessageBatch, serv
 		// TODO: These methods were moved in v0.16.0, but nacking seems broken
 		// (integration tests fail)
 		if res != nil {
-			return conn.receiver.ModifyMessage(ctx, amqpMsg, &amqp.ModifyMessageOptions{
+			return receiver.ModifyMessage(ctx, amqpMsg, &amqp.ModifyMessageOptions{
 				DeliveryFailed:    true,
 				UndeliverableHere: false,
 				Annotations:       amqpMsg.Annotations,
 			})
 		}
-		return conn.receiver.AcceptMessage(ctx, amqpMsg)
+		return receiver.AcceptMessage(ctx, amqpMsg)
 	}, nil
 }
 
+// detachReceiver closes the receiver link of a connection so that it is
+// resumed by the next call to Connect.
+func (a *amqp1Reader) detachReceiver(ctx context.Context, conn *amqp1Conn, receiver *amqp.Receiver) {
+	a.m.Lock()
+	defer a.m.Unlock()
+
+	if a.conn != conn || conn.receiver != receiver {
+		return
+	}
+	_ = receiver.Close(ctx)
+	conn.receiver = nil
+}
+
 func (a *amqp1Reader) Close(ctx context.Context) error {
 	return a.disconnect(ctx)
 }
 
+func receiverOptsFromParsed(conf *service.ParsedConfig, credit int) (*amqp.ReceiverOptions, error) {
+	opts := &amqp.ReceiverOptions{
+		Credit: int32(credit),
+	}
+
+	var err error
+	if opts.Name, err = conf.FieldString(linkNameField); err != nil {
+		return nil, err
+	}
+
+	if !conf.Contains(terminusField) {
+		return opts, nil
+	}
+	tConf := conf.Namespace(terminusField)
+
+	durability, err := tConf.FieldString(terminusDurableField)
+	if err != nil {
+		return nil, err
+	}
+	switch durability {
+	case "none":
+		opts.SourceDurability = amqp.DurabilityNone
+	case "configuration":
+		opts.SourceDurability = amqp.DurabilityConfiguration
+	case "unsettled_state":
+		opts.SourceDurability = amqp.DurabilityUnsettledState
+	default:
+		return nil, fmt.Errorf("terminus durability not recognised: %v", durability)
+	}
+
+	expiry, err := tConf.FieldString(terminusExpiryField)
+	if err != nil {
+		return nil, err
+	}
+	switch expiry {
+	case "link_detach":
+		opts.SourceExpiryPolicy = amqp.ExpiryPolicyLinkDetach
+	case "session_end":
+		opts.SourceExpiryPolicy = amqp.ExpiryPolicySessionEnd
+	case "connection_close":
+		opts.SourceExpiryPolicy = amqp.ExpiryPolicyConnectionClose
+	case "never":
+		opts.SourceExpiryPolicy = amqp.ExpiryPolicyNever
+	default:
+		return nil, fmt.Errorf("terminus expiry policy not recognised: %v", expiry)
+	}
+
+	timeout, err := tConf.FieldDuration(terminusTimeoutField)
+	if err != nil {
+		return nil, err
+	}
+	opts.SourceExpiryTimeout = uint32(timeout.Seconds())
+	return opts, nil
+}
+
 // reDial connection to amqp with one or more fallback URLs.
 func (a *amqp1Reader) reDial(ctx context.Context, urls []string) (conn *amqp.Conn, err error) {
 	for i, url := range urls {
@@ -0,0 +1,219 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	havro "github.com/hamba/avro/v2"
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	atpFieldWriterSchema     = "writer_schema"
+	atpFieldWriterSchemaPath = "writer_schema_path"
+	atpFieldReaderSchema     = "reader_schema"
+	atpFieldReaderSchemaPath = "reader_schema_path"
+	atpFieldEncoding         = "encoding"
+)
+
+func avroTransformConfigSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Version("4.48.0").
+		Summary(`Converts Avro encoded messages written with one schema into Avro encoded messages of another schema.`).
+		Description(`
+Each message is decoded with the writer schema, resolved against the reader schema following the Avro schema resolution rules and then re-encoded with the reader schema. Fields missing from the reader schema are dropped, fields missing from the writer schema are populated from their defaults and primitive types are promoted where permitted (for example `+"`int`"+` to `+"`long`"+`). Documents are never converted to JSON, which means logical types such as timestamps and decimals are carried over without loss of precision.
+
+The two schemas must be compatible, which is checked when the processor is created.
+
+WARNING: If you are consuming or generating messages using a schema registry service then it is likely this processor will fail as those services require messages to be prefixed with the identifier of the schema version being used.`).
+		Fields(
+			service.NewStringField(atpFieldWriterSchema).
+				Description("The Avro schema that input messages were written with.").
+				Default(""),
+			service.NewStringField(atpFieldWriterSchemaPath).
+				Description("The path of the writer schema document. Use either this or the `"+atpFieldWriterSchema+"` field.").
+				Default("").
+				Example("file://path/to/v1.avsc").
+				Example("http://localhost:8081/path/to/spec/versions/1"),
+			service.NewStringField(atpFieldReaderSchema).
+				Description("The Avro schema that output messages are written with.").
+				Default(""),
+			service.NewStringField(atpFieldReaderSchemaPath).
+				Description("The path of the reader schema document. Use either this or the `"+atpFieldReaderSchema+"` field.").
+				Default("").
+				Example("file://path/to/v2.avsc").
+				Example("http://localhost:8081/path/to/spec/versions/2"),
+			service.NewStringEnumField(atpFieldEncoding, "binary", "single").
+				Description("The Avro encoding of both input and output messages. When `single` is used the fingerprint of input messages must match the writer schema and output messages are prefixed with the fingerprint of the reader schema.").
+				Default("binary"),
+		).
+		Example("Schema Evolution", "Upgrade records from version 1 of a schema to version 2, where a field has been renamed via an alias and a new field with a default has been added.", `
+pipeline:
+  processors:
+    - avro_transform:
+        writer_schema_path: file://./schemas/user_v1.avsc
+        reader_schema_path: file://./schemas/user_v2.avsc
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor("avro_transform", avroTransformConfigSpec(), newAvroTransformFromConfig)
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func schemaFromConfig(conf *service.ParsedConfig, schemaField, pathField string) (string, error) {
+	schema, err := conf.FieldString(schemaField)
+	if err != nil {
+		return "", err
+	}
+	schemaPath, err := conf.FieldString(pathField)
+	if err != nil {
+		return "", err
+	}
+	if schemaPath != "" {
+		if !(strings.HasPrefix(schemaPath, "file://") || strings.HasPrefix(schemaPath, "http://")) {
+			return "", fmt.Errorf("invalid %v provided, must start with file:// or http://", pathField)
+		}
+		if schema, err = loadSchema(schemaPath); err != nil {
+			return "", fmt.Errorf("failed to load Avro schema definition: %v", err)
+		}
+	}
+	if schema == "" {
+		return "", fmt.Errorf("a schema must be specified with either the `%v` or `%v` fields", schemaField, pathField)
+	}
+	return schema, nil
+}
+
+// singleObjectHeader returns the prefix of single object encoded messages of
+// a schema, which is a two byte marker followed by the little endian Rabin
+// fingerprint of the schema's parsing canonical form.
+func singleObjectHeader(schema string) ([]byte, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, err
+	}
+	return binary.LittleEndian.AppendUint64([]byte{0xC3, 0x01}, codec.Rabin), nil
+}
+
+type avroTransform struct {
+	reader   havro.Schema
+	resolved havro.Schema
+
+	single       bool
+	writerHeader []byte
+	readerHeader []byte
+}
+
+func newAvroTransformFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+	writer, err := schemaFromConfig(conf, atpFieldWriterSchema, atpFieldWriterSchemaPath)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := schemaFromConfig(conf, atpFieldReaderSchema, atpFieldReaderSchemaPath)
+	if err != nil {
+		return nil, err
+	}
+	encoding, err := conf.FieldString(atpFieldEncoding)
+	if err != nil {
+		return nil, err
+	}
+	return newAvroTransform(writer, reader, encoding == "single")
+}
+
+func newAvroTransform(writerSchema, readerSchema string, single bool) (*avroTransform, error) {
+	writer, err := havro.ParseWithCache(writerSchema, "", &havro.SchemaCache{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse writer schema: %w", err)
+	}
+	reader, err := havro.ParseWithCache(readerSchema, "", &havro.SchemaCache{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reader schema: %w", err)
+	}
+	resolved, err := havro.NewSchemaCompatibility().Resolve(reader, writer)
+	if err != nil {
+		return nil, fmt.Errorf("reader schema is not compatible with writer schema: %w", err)
+	}
+	t := &avroTransform{
+		reader:   reader,
+		resolved: resolved,
+		single:   single,
+	}
+	if single {
+		if t.writerHeader, err = singleObjectHeader(writerSchema); err != nil {
+			return nil, fmt.Errorf("failed to fingerprint writer schema: %w", err)
+		}
+		if t.readerHeader, err = singleObjectHeader(readerSchema); err != nil {
+			return nil, fmt.Errorf("failed to fingerprint reader schema: %w", err)
+		}
+	}
+	return t, nil
+}
+
+func (t *avroTransform) transform(b []byte) ([]byte, error) {
+	if t.single {
+		if len(b) < len(t.writerHeader) || !bytes.Equal(b[:2], t.writerHeader[:2]) {
+			return nil, errors.New("message is not single object encoded")
+		}
+		if !bytes.Equal(b[:len(t.writerHeader)], t.writerHeader) {
+			return nil, errors.New("message fingerprint does not match the writer schema")
+		}
+		b = b[len(t.writerHeader):]
+	}
+
+	var v any
+	if err := havro.Unmarshal(t.resolved, b, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode Avro message: %w", err)
+	}
+	out, err := havro.Marshal(t.reader, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Avro message: %w", err)
+	}
+
+	if t.single {
+		out = append(append([]byte{}, t.readerHeader...), out...)
+	}
+	return out, nil
+}
+
+func (t *avroTransform) Process(_ context.Context, msg *service.Message) (service.MessageBatch, error) {
+	b, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	out, err := t.transform(b)
+	if err != nil {
+		return nil, err
+	}
+	msg.SetBytes(out)
+	return service.MessageBatch{msg}, nil
+}
+
+func (*avroTransform) Close(context.Context) error {
+	return nil
+}
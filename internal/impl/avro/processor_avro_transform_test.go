@@ -0,0 +1,171 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package avro
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const transformWriterSchema = `{
+  "type": "record",
+  "name": "user",
+  "fields": [
+    { "name": "id", "type": "int" },
+    { "name": "name", "type": "string" },
+    { "name": "score", "type": "float" },
+    { "name": "created_at", "type": { "type": "long", "logicalType": "timestamp-micros" } },
+    { "name": "balance", "type": { "type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2 } },
+    { "name": "legacy", "type": "string" }
+  ]
+}`
+
+const transformReaderSchema = `{
+  "type": "record",
+  "name": "user",
+  "fields": [
+    { "name": "id", "type": "long" },
+    { "name": "full_name", "type": "string", "aliases": ["name"] },
+    { "name": "score", "type": "double" },
+    { "name": "created_at", "type": { "type": "long", "logicalType": "timestamp-micros" } },
+    { "name": "balance", "type": { "type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2 } },
+    { "name": "tier", "type": "string", "default": "basic" }
+  ]
+}`
+
+func TestAvroTransform(t *testing.T) {
+	ts := time.Date(2024, 3, 4, 5, 6, 7, 123456000, time.UTC)
+
+	for _, encoding := range []string{"binary", "single"} {
+		t.Run(encoding, func(t *testing.T) {
+			conf, err := avroTransformConfigSpec().ParseYAML(fmt.Sprintf(`
+writer_schema: '%v'
+reader_schema: '%v'
+encoding: %v
+`, transformWriterSchema, transformReaderSchema, encoding), nil)
+			require.NoError(t, err)
+
+			proc, err := newAvroTransformFromConfig(conf, service.MockResources())
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, proc.Close(context.Background())) })
+
+			writerCodec, err := goavro.NewCodec(transformWriterSchema)
+			require.NoError(t, err)
+			readerCodec, err := goavro.NewCodec(transformReaderSchema)
+			require.NoError(t, err)
+
+			native := map[string]any{
+				"id":         int32(42),
+				"name":       "foo",
+				"score":      float32(1.5),
+				"created_at": ts,
+				"balance":    big.NewRat(12345, 100),
+				"legacy":     "drop me",
+			}
+			var input []byte
+			if encoding == "single" {
+				input, err = writerCodec.SingleFromNative(nil, native)
+			} else {
+				input, err = writerCodec.BinaryFromNative(nil, native)
+			}
+			require.NoError(t, err)
+
+			batch, err := proc.Process(context.Background(), service.NewMessage(input))
+			require.NoError(t, err)
+			require.Len(t, batch, 1)
+
+			output, err := batch[0].AsBytes()
+			require.NoError(t, err)
+
+			var decoded any
+			if encoding == "single" {
+				decoded, _, err = readerCodec.NativeFromSingle(output)
+			} else {
+				decoded, _, err = readerCodec.NativeFromBinary(output)
+			}
+			require.NoError(t, err)
+
+			record := decoded.(map[string]any)
+			assert.Equal(t, int64(42), record["id"])
+			assert.Equal(t, "foo", record["full_name"])
+			assert.Equal(t, float64(1.5), record["score"])
+			assert.True(t, ts.Equal(record["created_at"].(time.Time)))
+			assert.Equal(t, 0, big.NewRat(12345, 100).Cmp(record["balance"].(*big.Rat)))
+			assert.Equal(t, "basic", record["tier"])
+			assert.NotContains(t, record, "legacy")
+		})
+	}
+}
+
+func TestAvroTransformSingleFingerprintMismatch(t *testing.T) {
+	conf, err := avroTransformConfigSpec().ParseYAML(fmt.Sprintf(`
+writer_schema: '%v'
+reader_schema: '%v'
+encoding: single
+`, transformWriterSchema, transformReaderSchema), nil)
+	require.NoError(t, err)
+
+	proc, err := newAvroTransformFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+
+	readerCodec, err := goavro.NewCodec(transformReaderSchema)
+	require.NoError(t, err)
+
+	input, err := readerCodec.SingleFromNative(nil, map[string]any{
+		"id":         int64(1),
+		"full_name":  "foo",
+		"score":      float64(1),
+		"created_at": time.Now(),
+		"balance":    big.NewRat(1, 1),
+		"tier":       "gold",
+	})
+	require.NoError(t, err)
+
+	_, err = proc.Process(context.Background(), service.NewMessage(input))
+	require.ErrorContains(t, err, "fingerprint does not match")
+
+	_, err = proc.Process(context.Background(), service.NewMessage([]byte("nope")))
+	require.ErrorContains(t, err, "not single object encoded")
+}
+
+func TestAvroTransformIncompatible(t *testing.T) {
+	conf, err := avroTransformConfigSpec().ParseYAML(fmt.Sprintf(`
+writer_schema: '%v'
+reader_schema: '{"type":"record","name":"user","fields":[{"name":"required","type":"string"}]}'
+`, transformWriterSchema), nil)
+	require.NoError(t, err)
+
+	_, err = newAvroTransformFromConfig(conf, service.MockResources())
+	require.ErrorContains(t, err, "not compatible")
+}
+
+func TestAvroTransformMissingSchema(t *testing.T) {
+	conf, err := avroTransformConfigSpec().ParseYAML(fmt.Sprintf(`
+writer_schema: '%v'
+`, transformWriterSchema), nil)
+	require.NoError(t, err)
+
+	_, err = newAvroTransformFromConfig(conf, service.MockResources())
+	require.ErrorContains(t, err, "reader_schema")
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/service"
 
 	"github.com/redpanda-data/connect/v4/internal/impl/aws/config"
+	"github.com/redpanda-data/connect/v4/internal/objectcache"
 )
 
 func s3CacheConfig() *service.ConfigSpec {
@@ -41,12 +42,26 @@ func s3CacheConfig() *service.ConfigSpec {
 		Stable().
 		Version("3.36.0").
 		Summary(`Stores each item in an S3 bucket as a file, where an item ID is the path of the item within the bucket.`).
-		Description(`It is not possible to atomically upload S3 objects exclusively when the target does not already exist, therefore this cache is not suitable for deduplication.`).
+		Description(`It is not possible to atomically upload S3 objects exclusively when the target does not already exist, therefore this cache is not suitable for deduplication.
+
+=== TTL
+
+Items written with a TTL, either from the ` + "`default_ttl`" + ` field or from the TTL of the cache operation, have their expiry time stored within the ` + "`" + objectcache.ExpiresAtKey + "`" + ` object metadata key. Expired items are treated as missing, but are not removed from the bucket, and therefore it is recommended to also configure a lifecycle rule on the bucket that removes stale objects.
+
+=== Local cache
+
+A local in-process layer can be enabled with the ` + "`local_cache`" + ` field, which is useful for large datasets that are read frequently, such as enrichment tables.`).
 		Field(service.NewStringField("bucket").
 			Description("The S3 bucket to store items in.")).
 		Field(service.NewStringField("content_type").
 			Description("The content type to set for each item.").
 			Default("application/octet-stream")).
+		Field(service.NewDurationField("default_ttl").
+			Description("An optional default TTL to set for items, calculated from the moment the item is cached.").
+			Optional().
+			Advanced().
+			Version("4.48.0")).
+		Field(objectcache.LocalCacheField()).
 		Field(service.NewBoolField("force_path_style_urls").
 			Description("Forces the client API to use path style URLs, which helps when connecting to custom endpoints.").
 			Advanced().
@@ -68,7 +83,7 @@ func init() {
 			if err != nil {
 				return nil, err
 			}
-			return s, nil
+			return objectcache.WrapFromParsed(conf, s)
 		})
 	if err != nil {
 		panic(err)
@@ -88,6 +103,14 @@ func newS3CacheFromConfig(conf *service.ParsedConfig) (*s3Cache, error) {
 	if err != nil {
 		return nil, err
 	}
+	var ttl *time.Duration
+	if conf.Contains("default_ttl") {
+		ttlTmp, err := conf.FieldDuration("default_ttl")
+		if err != nil {
+			return nil, err
+		}
+		ttl = &ttlTmp
+	}
 
 	sess, err := GetSession(context.Background(), conf)
 	if err != nil {
@@ -103,7 +126,9 @@ func newS3CacheFromConfig(conf *service.ParsedConfig) (*s3Cache, error) {
 		return nil, err
 	}
 
-	return newS3Cache(bucket, contentType, backOff, client), nil
+	s := newS3Cache(bucket, contentType, backOff, client)
+	s.ttl = ttl
+	return s, nil
 }
 
 //------------------------------------------------------------------------------
@@ -113,6 +138,7 @@ type s3Cache struct {
 
 	bucket      string
 	contentType string
+	ttl         *time.Duration
 
 	boffPool sync.Pool
 }
@@ -155,6 +181,11 @@ func (s *s3Cache) Get(ctx context.Context, key string) (body []byte, err error)
 				return
 			}
 		} else {
+			if objectcache.Expired(time.Now(), obj.Metadata[objectcache.ExpiresAtKey]) {
+				_ = obj.Body.Close()
+				err = service.ErrKeyNotFound
+				return
+			}
 			body, err = io.ReadAll(obj.Body)
 			_ = obj.Body.Close()
 			return
@@ -173,19 +204,28 @@ func (s *s3Cache) Get(ctx context.Context, key string) (body []byte, err error)
 }
 
 // Set attempts to set the value of a key.
-func (s *s3Cache) Set(ctx context.Context, key string, value []byte, _ *time.Duration) (err error) {
+func (s *s3Cache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) (err error) {
 	boff := s.boffPool.Get().(backoff.BackOff)
 	defer func() {
 		boff.Reset()
 		s.boffPool.Put(boff)
 	}()
 
+	if ttl == nil {
+		ttl = s.ttl
+	}
+	var metadata map[string]string
+	if expiresAt, ok := objectcache.ExpiresAt(time.Now(), ttl); ok {
+		metadata = map[string]string{objectcache.ExpiresAtKey: expiresAt}
+	}
+
 	for {
 		if _, err = s.s3.PutObject(ctx, &s3.PutObjectInput{
 			Bucket:      &s.bucket,
 			Key:         &key,
 			Body:        bytes.NewReader(value),
 			ContentType: &s.contentType,
+			Metadata:    metadata,
 		}); err == nil {
 			return
 		}
@@ -202,14 +242,14 @@ func (s *s3Cache) Set(ctx context.Context, key string, value []byte, _ *time.Dur
 	}
 }
 
-func (s *s3Cache) Add(ctx context.Context, key string, value []byte, _ *time.Duration) error {
-	if _, err := s.s3.HeadObject(ctx, &s3.HeadObjectInput{
+func (s *s3Cache) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	if head, err := s.s3.HeadObject(ctx, &s3.HeadObjectInput{
 		Bucket: &s.bucket,
 		Key:    &key,
-	}); err == nil {
+	}); err == nil && !objectcache.Expired(time.Now(), head.Metadata[objectcache.ExpiresAtKey]) {
 		return service.ErrKeyAlreadyExists
 	}
-	return s.Set(ctx, key, value, nil)
+	return s.Set(ctx, key, value, ttl)
 }
 
 func (s *s3Cache) Delete(ctx context.Context, key string) (err error) {
@@ -101,6 +101,7 @@ type s3iConfig struct {
 	ForcePathStyleURLs bool
 	DeleteObjects      bool
 	SQS                s3iSQSConfig
+	Poll               *s3iPollConfig
 	CodecCtor          codec.DeprecatedFallbackCodec
 }
 
@@ -125,6 +126,9 @@ func s3iConfigFromParsed(pConf *service.ParsedConfig) (conf s3iConfig, err error
 			return
 		}
 	}
+	if conf.Poll, err = s3iPollConfigFromParsed(pConf, conf.Bucket, conf.Prefix); err != nil {
+		return
+	}
 	return
 }
 
@@ -144,6 +148,27 @@ If your notification events are being routed to SQS via an SNS topic then the ev
 
 When using SQS please make sure you have sensible values for `+"`sqs.max_messages`"+` and also the visibility timeout of the queue itself. When Redpanda Connect consumes an S3 object the SQS message that triggered it is not deleted until the S3 object has been sent onwards. This ensures at-least-once crash resiliency, but also means that if the S3 object takes longer to process than the visibility timeout of your queue then the same objects might be processed multiple times.
 
+== Polling
+
+When configuring SQS notifications isn't possible the input can instead poll the bucket for new objects by setting `+"`poll.cache`"+` to a xref:components:caches/about.adoc[cache resource]. Rather than shutting down once the bucket has been walked, the bucket is listed every `+"`poll.interval`"+` and objects are consumed in the order of their last modified time, followed by their key. The last modified time and key of the latest processed object is stored in the cache as a watermark, and only objects beyond the watermark are consumed, so that polling resumes where it left off when the pipeline restarts.
+
+Each poll lists all objects of the bucket with the `+"`prefix`"+`, and therefore polling is best suited to buckets with a moderate number of objects, or where a prefix narrows them down, for example by moving or deleting processed objects with `+"`delete_objects`"+`.
+
+`+"```yaml"+`
+input:
+  aws_s3:
+    bucket: TODO
+    prefix: events/
+    poll:
+      cache: watermarks
+      interval: 30s
+
+cache_resources:
+  - label: watermarks
+    redis:
+      url: redis://localhost:6379
+`+"```"+`
+
 == Download large files
 
 When downloading large files it's often necessary to process it in streamed parts in order to avoid loading the entire file in memory at a given time. In order to do this a `+"<<scanner, `scanner`>>"+` can be specified that determines how to break the input into smaller individual messages.
@@ -222,6 +247,7 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 			).
 				Description("Consume SQS messages in order to trigger key downloads.").
 				Optional(),
+			s3iPollField(),
 		)
 }
 
@@ -661,6 +687,7 @@ type awsS3Reader struct {
 	objectMut sync.Mutex
 	object    *s3PendingObject
 
+	mgr *service.Resources
 	log *service.Logger
 }
 
@@ -679,9 +706,13 @@ func newAmazonS3Reader(conf s3iConfig, awsConf aws.Config, nm *service.Resources
 	if conf.Prefix != "" && conf.SQS.URL != "" {
 		return nil, errors.New("cannot specify both a prefix and sqs.url")
 	}
+	if conf.Poll != nil && (conf.Bucket == "" || conf.SQS.URL != "") {
+		return nil, errors.New("polling requires a bucket and cannot be combined with sqs.url")
+	}
 	s := &awsS3Reader{
 		conf:              conf,
 		awsConf:           awsConf,
+		mgr:               nm,
 		log:               nm.Logger(),
 		objectScannerCtor: conf.CodecCtor,
 	}
@@ -698,6 +729,11 @@ func (a *awsS3Reader) getTargetReader(ctx context.Context) (s3ObjectTargetReader
 	if a.sqs != nil {
 		return newSQSTargetReader(a.conf, a.log, a.s3, a.sqs), nil
 	}
+	if a.conf.Poll != nil {
+		return newPollTargetReader(ctx, a.conf, a.mgr, a.s3, func(bucket, key string, prev service.AckFunc) service.AckFunc {
+			return deleteS3ObjectAckFn(a.s3, bucket, key, a.conf.DeleteObjects, prev)
+		})
+	}
 	return newStaticTargetReader(ctx, a.conf, a.log, a.s3)
 }
 
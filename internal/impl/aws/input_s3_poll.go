@@ -0,0 +1,281 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Jeffail/checkpoint"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// S3 Input Poll Fields
+	s3iFieldPoll         = "poll"
+	s3iPollFieldCache    = "cache"
+	s3iPollFieldCacheKey = "cache_key"
+	s3iPollFieldInterval = "interval"
+	s3iPollFieldOverlap  = "overlap"
+)
+
+func s3iPollField() *service.ConfigField {
+	return service.NewObjectField(s3iFieldPoll,
+		service.NewStringField(s3iPollFieldCache).
+			Description("A xref:components:caches/about.adoc[cache resource] to store the watermark of the latest processed object in. Polling is only enabled when a cache is specified.").
+			Default(""),
+		service.NewStringField(s3iPollFieldCacheKey).
+			Description("The key to store the watermark under in the cache. Defaults to the bucket followed by the prefix.").
+			Optional(),
+		service.NewDurationField(s3iPollFieldInterval).
+			Description("The period to wait between listing the bucket once the objects of the previous listing are consumed.").
+			Default("1m"),
+		service.NewDurationField(s3iPollFieldOverlap).
+			Description("A window by which each listing also considers objects last modified before the watermark, in order to consume objects that became visible after newer objects were consumed, such as multipart uploads which are dated by when they began. Objects within the window that were already consumed are skipped, unless the input was restarted since.").
+			Default("0s").
+			Example("5m"),
+	).
+		Description("Continuously poll the bucket for new objects by listing it periodically and tracking a watermark of the last modified time and key of the latest object, as an alternative to SQS notifications. See <<polling>>.").
+		Advanced().
+		Version("4.48.0")
+}
+
+type s3iPollConfig struct {
+	Cache    string
+	CacheKey string
+	Interval time.Duration
+	Overlap  time.Duration
+}
+
+func s3iPollConfigFromParsed(pConf *service.ParsedConfig, bucket, prefix string) (*s3iPollConfig, error) {
+	pConf = pConf.Namespace(s3iFieldPoll)
+
+	conf := &s3iPollConfig{CacheKey: bucket + "/" + prefix}
+	var err error
+	if conf.Cache, err = pConf.FieldString(s3iPollFieldCache); err != nil || conf.Cache == "" {
+		return nil, err
+	}
+	if pConf.Contains(s3iPollFieldCacheKey) {
+		if conf.CacheKey, err = pConf.FieldString(s3iPollFieldCacheKey); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Interval, err = pConf.FieldDuration(s3iPollFieldInterval); err != nil {
+		return nil, err
+	}
+	if conf.Overlap, err = pConf.FieldDuration(s3iPollFieldOverlap); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+//------------------------------------------------------------------------------
+
+// s3Watermark identifies the position of an object in the order objects are
+// consumed by polling, which is by last modified time and then by key.
+type s3Watermark struct {
+	LastModified time.Time `json:"last_modified"`
+	Key          string    `json:"key"`
+}
+
+func (w s3Watermark) before(o s3Watermark) bool {
+	if !w.LastModified.Equal(o.LastModified) {
+		return w.LastModified.Before(o.LastModified)
+	}
+	return w.Key < o.Key
+}
+
+type pollTargetReader struct {
+	conf     s3iConfig
+	mgr      *service.Resources
+	log      *service.Logger
+	s3       s3.ListObjectsV2APIClient
+	deleteFn func(bucket, key string, prev service.AckFunc) service.AckFunc
+
+	// The watermark of the latest object listed, and the time of the latest
+	// listing.
+	latest   *s3Watermark
+	lastPoll time.Time
+
+	// Keys of objects within the overlap window that were already listed, and
+	// their last modified time at the time, so that overwritten objects are
+	// listed again.
+	seen map[string]time.Time
+
+	// Objects listed but not yet popped, which are only tracked by the
+	// checkpointer once popped so that listings may exceed its capacity.
+	pending      []s3Watermark
+	checkpointer *checkpoint.Capped[s3Watermark]
+}
+
+func newPollTargetReader(
+	ctx context.Context,
+	conf s3iConfig,
+	mgr *service.Resources,
+	s3Client s3.ListObjectsV2APIClient,
+	deleteFn func(bucket, key string, prev service.AckFunc) service.AckFunc,
+) (*pollTargetReader, error) {
+	p := &pollTargetReader{
+		conf:         conf,
+		mgr:          mgr,
+		log:          mgr.Logger(),
+		s3:           s3Client,
+		deleteFn:     deleteFn,
+		seen:         map[string]time.Time{},
+		checkpointer: checkpoint.NewCapped[s3Watermark](1024),
+	}
+
+	var wBytes []byte
+	var cacheErr error
+	if err := mgr.AccessCache(ctx, conf.Poll.Cache, func(c service.Cache) {
+		wBytes, cacheErr = c.Get(ctx, conf.Poll.CacheKey)
+	}); err != nil {
+		return nil, err
+	}
+	if errors.Is(cacheErr, service.ErrKeyNotFound) {
+		return p, nil
+	}
+	if cacheErr != nil {
+		return nil, fmt.Errorf("failed to obtain watermark: %w", cacheErr)
+	}
+
+	var w s3Watermark
+	if err := json.Unmarshal(wBytes, &w); err != nil {
+		return nil, fmt.Errorf("failed to parse watermark: %w", err)
+	}
+	p.latest = &w
+	return p, nil
+}
+
+func (p *pollTargetReader) Pop(ctx context.Context) (*s3ObjectTarget, error) {
+	for len(p.pending) == 0 {
+		select {
+		case <-time.After(time.Until(p.lastPoll.Add(p.conf.Poll.Interval))):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if err := p.list(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	w := p.pending[0]
+	release, err := p.checkpointer.Track(ctx, w, 1)
+	if err != nil {
+		return nil, err
+	}
+	p.pending = p.pending[1:]
+
+	ackFn := p.deleteFn(p.conf.Bucket, w.Key, p.storeWatermarkFn(release))
+	return newS3ObjectTarget(w.Key, p.conf.Bucket, time.Time{}, ackFn), nil
+}
+
+// list lists the bucket and queues the objects beyond the watermark, ordered
+// by last modified time and key.
+func (p *pollTargetReader) list(ctx context.Context) error {
+	p.lastPoll = time.Now()
+
+	var from s3Watermark
+	if p.latest != nil {
+		from = *p.latest
+		from.LastModified = from.LastModified.Add(-p.conf.Poll.Overlap)
+	}
+
+	listInput := &s3.ListObjectsV2Input{Bucket: &p.conf.Bucket}
+	if p.conf.Prefix != "" {
+		listInput.Prefix = &p.conf.Prefix
+	}
+
+	var found []s3Watermark
+	paginator := s3.NewListObjectsV2Paginator(p.s3, listInput)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range output.Contents {
+			if obj.Key == nil || obj.LastModified == nil {
+				continue
+			}
+			w := s3Watermark{LastModified: *obj.LastModified, Key: *obj.Key}
+			if p.latest != nil && !from.before(w) {
+				continue
+			}
+			if t, exists := p.seen[w.Key]; exists && t.Equal(w.LastModified) {
+				continue
+			}
+			found = append(found, w)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].before(found[j])
+	})
+
+	for _, w := range found {
+		if p.conf.Poll.Overlap > 0 {
+			p.seen[w.Key] = w.LastModified
+		}
+		if p.latest == nil || p.latest.before(w) {
+			latest := w
+			p.latest = &latest
+		}
+		p.pending = append(p.pending, w)
+	}
+
+	if p.latest != nil {
+		cutoff := p.latest.LastModified.Add(-p.conf.Poll.Overlap)
+		for k, t := range p.seen {
+			if t.Before(cutoff) {
+				delete(p.seen, k)
+			}
+		}
+	}
+	return nil
+}
+
+// storeWatermarkFn returns an ack function which stores the watermark of the
+// latest object for which it and all preceding objects are processed.
+func (p *pollTargetReader) storeWatermarkFn(release func() *s3Watermark) service.AckFunc {
+	return func(ctx context.Context, _ error) error {
+		// Nacks are retried indefinitely by the reader, and so errors only
+		// arise from objects that couldn't be read, which are skipped in the
+		// same way as when walking a bucket.
+		highest := release()
+		if highest == nil {
+			return nil
+		}
+		wBytes, err := json.Marshal(highest)
+		if err != nil {
+			return err
+		}
+		var setErr error
+		if err := p.mgr.AccessCache(ctx, p.conf.Poll.Cache, func(c service.Cache) {
+			setErr = c.Set(ctx, p.conf.Poll.CacheKey, wBytes, nil)
+		}); err != nil {
+			return err
+		}
+		return setErr
+	}
+}
+
+func (p *pollTargetReader) Close(context.Context) error {
+	return nil
+}
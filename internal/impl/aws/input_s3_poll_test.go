@@ -0,0 +1,236 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeS3Lister struct {
+	mut     sync.Mutex
+	objects map[string]time.Time
+}
+
+func (f *fakeS3Lister) put(key string, t time.Time) {
+	f.mut.Lock()
+	f.objects[key] = t
+	f.mut.Unlock()
+}
+
+func (f *fakeS3Lister) ListObjectsV2(_ context.Context, input *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	// Return a single object per page in order to exercise pagination.
+	output := &s3.ListObjectsV2Output{}
+	var next string
+	for k := range f.objects {
+		if input.ContinuationToken != nil && k <= *input.ContinuationToken {
+			continue
+		}
+		if next == "" || k < next {
+			next = k
+		}
+	}
+	if next != "" {
+		output.Contents = []types.Object{{Key: aws.String(next), LastModified: aws.Time(f.objects[next])}}
+		output.IsTruncated = aws.Bool(true)
+		output.NextContinuationToken = aws.String(next)
+	}
+	return output, nil
+}
+
+func testPollReader(t *testing.T, mgr *service.Resources, lister *fakeS3Lister, overlap time.Duration) *pollTargetReader {
+	t.Helper()
+
+	p, err := newPollTargetReader(context.Background(), s3iConfig{
+		Bucket: "foo",
+		Poll: &s3iPollConfig{
+			Cache:    "watermarks",
+			CacheKey: "foo/",
+			Interval: time.Millisecond,
+			Overlap:  overlap,
+		},
+	}, mgr, lister, func(_, _ string, prev service.AckFunc) service.AckFunc {
+		return prev
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func popKeys(t *testing.T, p *pollTargetReader, n int) (keys []string, targets []*s3ObjectTarget) {
+	t.Helper()
+	for range n {
+		ctx, done := context.WithTimeout(context.Background(), time.Second)
+		target, err := p.Pop(ctx)
+		done()
+		require.NoError(t, err)
+		keys = append(keys, target.key)
+		targets = append(targets, target)
+	}
+	return
+}
+
+func TestS3PollConfig(t *testing.T) {
+	spec := service.NewConfigSpec().Field(s3iPollField())
+
+	pConf, err := spec.ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+	conf, err := s3iPollConfigFromParsed(pConf, "foo", "bar/")
+	require.NoError(t, err)
+	assert.Nil(t, conf)
+
+	pConf, err = spec.ParseYAML(`
+poll:
+  cache: watermarks
+  interval: 10s
+`, nil)
+	require.NoError(t, err)
+	conf, err = s3iPollConfigFromParsed(pConf, "foo", "bar/")
+	require.NoError(t, err)
+	assert.Equal(t, &s3iPollConfig{
+		Cache:    "watermarks",
+		CacheKey: "foo/bar/",
+		Interval: 10 * time.Second,
+	}, conf)
+}
+
+func TestS3PollWatermark(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	lister := &fakeS3Lister{objects: map[string]time.Time{
+		"c": now.Add(-time.Hour),
+		"a": now.Add(-time.Minute),
+		"b": now.Add(-time.Minute),
+	}}
+	mgr := service.MockResources(service.MockResourcesOptAddCache("watermarks"))
+
+	p := testPollReader(t, mgr, lister, 0)
+	keys, targets := popKeys(t, p, 3)
+	assert.Equal(t, []string{"c", "a", "b"}, keys)
+
+	// Acknowledging out of order only stores the watermark of contiguous
+	// objects.
+	require.NoError(t, targets[1].ackFn(context.Background(), nil))
+	getWatermark := func() (w s3Watermark) {
+		var wBytes []byte
+		var cErr error
+		require.NoError(t, mgr.AccessCache(context.Background(), "watermarks", func(c service.Cache) {
+			wBytes, cErr = c.Get(context.Background(), "foo/")
+		}))
+		require.NoError(t, cErr)
+		require.NoError(t, json.Unmarshal(wBytes, &w))
+		return
+	}
+	require.NoError(t, targets[0].ackFn(context.Background(), nil))
+	assert.Equal(t, s3Watermark{LastModified: now.Add(-time.Minute), Key: "a"}, getWatermark())
+
+	lister.put("d", now)
+	keys, targets = popKeys(t, p, 1)
+	assert.Equal(t, []string{"d"}, keys)
+	require.NoError(t, targets[0].ackFn(context.Background(), nil))
+
+	// Object b was never acknowledged and so is consumed again after a
+	// restart.
+	p = testPollReader(t, mgr, lister, 0)
+	keys, _ = popKeys(t, p, 2)
+	assert.Equal(t, []string{"b", "d"}, keys)
+}
+
+func TestS3PollOverlap(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	lister := &fakeS3Lister{objects: map[string]time.Time{
+		"a": now,
+	}}
+	mgr := service.MockResources(service.MockResourcesOptAddCache("watermarks"))
+
+	p := testPollReader(t, mgr, lister, time.Minute)
+	keys, _ := popKeys(t, p, 1)
+	assert.Equal(t, []string{"a"}, keys)
+
+	// An object that becomes visible late is consumed when within the
+	// overlap, without consuming objects already seen.
+	lister.put("b", now.Add(-time.Second*30))
+	lister.put("c", now.Add(-time.Hour))
+	keys, _ = popKeys(t, p, 1)
+	assert.Equal(t, []string{"b"}, keys)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer done()
+	_, err := p.Pop(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestS3PollManyObjects(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	lister := &fakeS3Lister{objects: map[string]time.Time{}}
+	for i := range 1100 {
+		lister.put(fmt.Sprintf("%04d", i), now)
+	}
+	mgr := service.MockResources(service.MockResourcesOptAddCache("watermarks"))
+
+	// Listings larger than the capacity of the checkpointer are consumed as
+	// long as objects are acknowledged.
+	p := testPollReader(t, mgr, lister, 0)
+	keys, targets := popKeys(t, p, 1024)
+	assert.Equal(t, "0000", keys[0])
+	assert.Equal(t, "1023", keys[1023])
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer done()
+	_, err := p.Pop(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	for _, target := range targets {
+		require.NoError(t, target.ackFn(context.Background(), nil))
+	}
+	keys, _ = popKeys(t, p, 76)
+	assert.Equal(t, "1024", keys[0])
+	assert.Equal(t, "1099", keys[75])
+}
+
+func TestS3PollOverwritten(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	lister := &fakeS3Lister{objects: map[string]time.Time{
+		"a": now,
+	}}
+	mgr := service.MockResources(service.MockResourcesOptAddCache("watermarks"))
+
+	p := testPollReader(t, mgr, lister, time.Minute)
+	keys, _ := popKeys(t, p, 1)
+	assert.Equal(t, []string{"a"}, keys)
+
+	// An object overwritten within the overlap is consumed again.
+	lister.put("a", now.Add(time.Second))
+	keys, _ = popKeys(t, p, 1)
+	assert.Equal(t, []string{"a"}, keys)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer done()
+	_, err := p.Pop(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/service"
 
 	"github.com/redpanda-data/connect/v4/internal/impl/aws/config"
+	"github.com/redpanda-data/connect/v4/internal/rolling"
 )
 
 const (
@@ -54,6 +55,9 @@ const (
 	s3oFieldKMSKeyID                = "kms_key_id"
 	s3oFieldServerSideEncryption    = "server_side_encryption"
 	s3oFieldBatching                = "batching"
+	s3oFieldMultipartThreshold      = "multipart_threshold"
+	s3oFieldMultipartPartSize       = "multipart_part_size"
+	s3oFieldMultipartConcurrency    = "multipart_concurrency"
 )
 
 type s3TagPair struct {
@@ -80,6 +84,10 @@ type s3oConfig struct {
 	KMSKeyID                string
 	ServerSideEncryption    string
 	UsePathStyle            bool
+	MultipartThreshold      int
+	MultipartPartSize       int
+	MultipartConcurrency    int
+	Rolling                 *rolling.Config
 
 	aconf aws.Config
 }
@@ -149,6 +157,26 @@ func s3oConfigFromParsed(pConf *service.ParsedConfig) (conf s3oConfig, err error
 	if conf.ServerSideEncryption, err = pConf.FieldString(s3oFieldServerSideEncryption); err != nil {
 		return
 	}
+	if conf.MultipartThreshold, err = pConf.FieldInt(s3oFieldMultipartThreshold); err != nil {
+		return
+	}
+	if conf.MultipartPartSize, err = pConf.FieldInt(s3oFieldMultipartPartSize); err != nil {
+		return
+	}
+	if conf.MultipartPartSize < int(manager.MinUploadPartSize) {
+		err = fmt.Errorf("%v must be at least %v bytes", s3oFieldMultipartPartSize, manager.MinUploadPartSize)
+		return
+	}
+	if conf.MultipartConcurrency, err = pConf.FieldInt(s3oFieldMultipartConcurrency); err != nil {
+		return
+	}
+	if conf.MultipartConcurrency < 1 {
+		err = fmt.Errorf("%v must be at least 1", s3oFieldMultipartConcurrency)
+		return
+	}
+	if conf.Rolling, err = rolling.ConfigFromParsed(pConf); err != nil {
+		return
+	}
 	if conf.aconf, err = GetSession(context.TODO(), pConf); err != nil {
 		return
 	}
@@ -219,7 +247,31 @@ output:
       processors:
         - archive:
             format: json_array
-`+"```"+``+service.OutputPerformanceDocs(true, false)).
+`+"```"+`
+
+== Rolling files
+
+When `+"`rolling.enabled`"+` is set messages are accumulated into files which are uploaded as single objects once they are rotated, which happens when a file reaches `+"`rolling.max_bytes`"+` or `+"`rolling.max_records`"+`, or when `+"`rolling.max_age`"+` has passed since it received its first message. A file is held in memory until it's uploaded, and is uploaded in full with a single request once rotated, and so partially written objects are never visible. The `+"`timeout`"+` field applies to the upload of each file, and may need increasing for large files.
+
+The `+"`path`"+` and other interpolated fields are evaluated once per file against its first message at the time of rotation, and therefore functions such as `+"`uuid_v4()`"+` and `+"`now()`"+` produce a value unique to each file:
+
+`+"```yaml"+`
+output:
+  aws_s3:
+    bucket: TODO
+    path: events/${! now().ts_format("2006/01/02") }/${! uuid_v4() }.parquet
+    max_in_flight: 64
+    rolling:
+      enabled: true
+      format: parquet
+      max_bytes: 134217728
+      max_age: 10m
+    processors:
+      - parquet_encode:
+          infer_schema: true
+`+"```"+`
+
+Messages are only acknowledged once the file they were added to is uploaded, and a failed upload results in all messages of the file being reattempted. Since writes are blocked until a file is rotated the number of messages that can be accumulated into a file is limited by `+"`max_in_flight`"+` multiplied by the size of batches, which should be set high enough to reach the size or record limits, otherwise files are only rotated by age.`+service.OutputPerformanceDocs(true, false)).
 		Fields(
 			service.NewStringField(s3oFieldBucket).
 				Description("The bucket to upload messages to."),
@@ -296,6 +348,22 @@ output:
 				Advanced().
 				Default("5s"),
 			service.NewBatchPolicyField(s3oFieldBatching),
+			service.NewIntField(s3oFieldMultipartThreshold).
+				Description("The size in bytes of objects from which they are uploaded in parts with a multipart upload, rather than with a single request.").
+				Advanced().
+				Default(int(manager.DefaultUploadPartSize)).
+				Version("4.48.0"),
+			service.NewIntField(s3oFieldMultipartPartSize).
+				Description("The size in bytes of the parts of multipart uploads, which must be at least 5MiB. An object can consist of at most 10,000 parts, and so this must be increased for objects larger than roughly 48GiB.").
+				Advanced().
+				Default(int(manager.DefaultUploadPartSize)).
+				Version("4.48.0"),
+			service.NewIntField(s3oFieldMultipartConcurrency).
+				Description("The number of parts of each multipart upload that are uploaded in parallel.").
+				Advanced().
+				Default(manager.DefaultUploadConcurrency).
+				Version("4.48.0"),
+			rolling.ConfigField(),
 		).
 		Fields(config.SessionFields()...)
 }
@@ -321,9 +389,19 @@ func init() {
 	}
 }
 
+type s3PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+type s3UploaderAPI interface {
+	Upload(ctx context.Context, input *s3.PutObjectInput, opts ...func(*manager.Uploader)) (*manager.UploadOutput, error)
+}
+
 type amazonS3Writer struct {
 	conf     s3oConfig
-	uploader *manager.Uploader
+	client   s3PutObjectAPI
+	uploader s3UploaderAPI
+	roller   *rolling.Roller
 	log      *service.Logger
 }
 
@@ -343,7 +421,14 @@ func (a *amazonS3Writer) Connect(ctx context.Context) error {
 	client := s3.NewFromConfig(a.conf.aconf, func(o *s3.Options) {
 		o.UsePathStyle = a.conf.UsePathStyle
 	})
-	a.uploader = manager.NewUploader(client)
+	a.client = client
+	a.uploader = manager.NewUploader(client, func(u *manager.Uploader) {
+		u.PartSize = int64(a.conf.MultipartPartSize)
+		u.Concurrency = a.conf.MultipartConcurrency
+	})
+	if a.conf.Rolling != nil {
+		a.roller = rolling.NewRoller(a.conf.Rolling, a.uploadFile, a.log)
+	}
 	return nil
 }
 
@@ -351,132 +436,170 @@ func (a *amazonS3Writer) WriteBatch(wctx context.Context, msg service.MessageBat
 	if a.uploader == nil {
 		return service.ErrNotConnected
 	}
+	if a.roller != nil {
+		return a.roller.WriteBatch(wctx, msg)
+	}
 
 	ctx, cancel := context.WithTimeout(wctx, a.conf.Timeout)
 	defer cancel()
 
 	return msg.WalkWithBatchedErrors(func(i int, m *service.Message) error {
-		metadata := map[string]string{}
-		_ = a.conf.Metadata.WalkMut(m, func(k string, v any) error {
-			metadata[k] = bloblang.ValueToString(v)
-			return nil
-		})
-
-		var contentEncoding *string
-		ce, err := msg.TryInterpolatedString(i, a.conf.ContentEncoding)
+		uploadInput, err := a.objectInput(msg, i)
 		if err != nil {
-			return fmt.Errorf("content encoding interpolation: %w", err)
-		}
-		if ce != "" {
-			contentEncoding = aws.String(ce)
-		}
-		var cacheControl *string
-		if ce, err = msg.TryInterpolatedString(i, a.conf.CacheControl); err != nil {
-			return fmt.Errorf("cache control interpolation: %w", err)
-		}
-		if ce != "" {
-			cacheControl = aws.String(ce)
-		}
-		var contentDisposition *string
-		if ce, err = msg.TryInterpolatedString(i, a.conf.ContentDisposition); err != nil {
-			return fmt.Errorf("content disposition interpolation: %w", err)
-		}
-		if ce != "" {
-			contentDisposition = aws.String(ce)
-		}
-		var contentLanguage *string
-		if ce, err = msg.TryInterpolatedString(i, a.conf.ContentLanguage); err != nil {
-			return fmt.Errorf("content language interpolation: %w", err)
-		}
-		if ce != "" {
-			contentLanguage = aws.String(ce)
-		}
-		var contentMD5 *string
-		if ce, err = msg.TryInterpolatedString(i, a.conf.ContentMD5); err != nil {
-			return fmt.Errorf("content MD5 interpolation: %w", err)
-		}
-		if ce != "" {
-			contentMD5 = aws.String(ce)
-		}
-		var websiteRedirectLocation *string
-		if ce, err = msg.TryInterpolatedString(i, a.conf.WebsiteRedirectLocation); err != nil {
-			return fmt.Errorf("website redirect location interpolation: %w", err)
-		}
-		if ce != "" {
-			websiteRedirectLocation = aws.String(ce)
+			return err
 		}
 
-		key, err := msg.TryInterpolatedString(i, a.conf.Path)
+		mBytes, err := m.AsBytes()
 		if err != nil {
-			return fmt.Errorf("key interpolation: %w", err)
+			return err
 		}
+		return a.upload(ctx, uploadInput, mBytes)
+	})
+}
 
-		contentType, err := msg.TryInterpolatedString(i, a.conf.ContentType)
-		if err != nil {
-			return fmt.Errorf("content type interpolation: %w", err)
-		}
+// upload uploads an object with a single request, or in parts with a multipart
+// upload when it reaches the multipart threshold. Parts are read directly from
+// the body rather than being copied.
+func (a *amazonS3Writer) upload(ctx context.Context, uploadInput *s3.PutObjectInput, body []byte) error {
+	uploadInput.Body = bytes.NewReader(body)
+	if len(body) < a.conf.MultipartThreshold {
+		_, err := a.client.PutObject(ctx, uploadInput)
+		return err
+	}
+	_, err := a.uploader.Upload(ctx, uploadInput)
+	return err
+}
 
-		storageClass, err := msg.TryInterpolatedString(i, a.conf.StorageClass)
-		if err != nil {
-			return fmt.Errorf("storage class interpolation: %w", err)
-		}
+// uploadFile uploads a rolling file as an object with fields interpolated from
+// its first message.
+func (a *amazonS3Writer) uploadFile(ctx context.Context, first *service.Message, body []byte) error {
+	uploadInput, err := a.objectInput(service.MessageBatch{first}, 0)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, a.conf.Timeout)
+	defer cancel()
 
-		mBytes, err := m.AsBytes()
-		if err != nil {
-			return err
-		}
+	return a.upload(ctx, uploadInput, body)
+}
 
-		uploadInput := &s3.PutObjectInput{
-			Bucket:                  &a.conf.Bucket,
-			Key:                     aws.String(key),
-			Body:                    bytes.NewReader(mBytes),
-			ContentType:             aws.String(contentType),
-			ContentEncoding:         contentEncoding,
-			CacheControl:            cacheControl,
-			ContentDisposition:      contentDisposition,
-			ContentLanguage:         contentLanguage,
-			ContentMD5:              contentMD5,
-			WebsiteRedirectLocation: websiteRedirectLocation,
-			StorageClass:            types.StorageClass(storageClass),
-			Metadata:                metadata,
-		}
+// objectInput returns the input for uploading an object, without its body,
+// with fields interpolated from a message of a batch.
+func (a *amazonS3Writer) objectInput(msg service.MessageBatch, i int) (*s3.PutObjectInput, error) {
+	metadata := map[string]string{}
+	_ = a.conf.Metadata.WalkMut(msg[i], func(k string, v any) error {
+		metadata[k] = bloblang.ValueToString(v)
+		return nil
+	})
+
+	var contentEncoding *string
+	ce, err := msg.TryInterpolatedString(i, a.conf.ContentEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("content encoding interpolation: %w", err)
+	}
+	if ce != "" {
+		contentEncoding = aws.String(ce)
+	}
+	var cacheControl *string
+	if ce, err = msg.TryInterpolatedString(i, a.conf.CacheControl); err != nil {
+		return nil, fmt.Errorf("cache control interpolation: %w", err)
+	}
+	if ce != "" {
+		cacheControl = aws.String(ce)
+	}
+	var contentDisposition *string
+	if ce, err = msg.TryInterpolatedString(i, a.conf.ContentDisposition); err != nil {
+		return nil, fmt.Errorf("content disposition interpolation: %w", err)
+	}
+	if ce != "" {
+		contentDisposition = aws.String(ce)
+	}
+	var contentLanguage *string
+	if ce, err = msg.TryInterpolatedString(i, a.conf.ContentLanguage); err != nil {
+		return nil, fmt.Errorf("content language interpolation: %w", err)
+	}
+	if ce != "" {
+		contentLanguage = aws.String(ce)
+	}
+	var contentMD5 *string
+	if ce, err = msg.TryInterpolatedString(i, a.conf.ContentMD5); err != nil {
+		return nil, fmt.Errorf("content MD5 interpolation: %w", err)
+	}
+	if ce != "" {
+		contentMD5 = aws.String(ce)
+	}
+	var websiteRedirectLocation *string
+	if ce, err = msg.TryInterpolatedString(i, a.conf.WebsiteRedirectLocation); err != nil {
+		return nil, fmt.Errorf("website redirect location interpolation: %w", err)
+	}
+	if ce != "" {
+		websiteRedirectLocation = aws.String(ce)
+	}
+
+	key, err := msg.TryInterpolatedString(i, a.conf.Path)
+	if err != nil {
+		return nil, fmt.Errorf("key interpolation: %w", err)
+	}
+
+	contentType, err := msg.TryInterpolatedString(i, a.conf.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("content type interpolation: %w", err)
+	}
+
+	storageClass, err := msg.TryInterpolatedString(i, a.conf.StorageClass)
+	if err != nil {
+		return nil, fmt.Errorf("storage class interpolation: %w", err)
+	}
+
+	uploadInput := &s3.PutObjectInput{
+		Bucket:                  &a.conf.Bucket,
+		Key:                     aws.String(key),
+		ContentType:             aws.String(contentType),
+		ContentEncoding:         contentEncoding,
+		CacheControl:            cacheControl,
+		ContentDisposition:      contentDisposition,
+		ContentLanguage:         contentLanguage,
+		ContentMD5:              contentMD5,
+		WebsiteRedirectLocation: websiteRedirectLocation,
+		StorageClass:            types.StorageClass(storageClass),
+		Metadata:                metadata,
+	}
 
-		// Prepare tags, escaping keys and values to ensure they're valid query string parameters.
-		if len(a.conf.Tags) > 0 {
-			tags := make([]string, len(a.conf.Tags))
-			for j, pair := range a.conf.Tags {
-				tagStr, err := msg.TryInterpolatedString(i, pair.value)
-				if err != nil {
-					return fmt.Errorf("tag %v interpolation: %w", pair.key, err)
-				}
-				tags[j] = url.QueryEscape(pair.key) + "=" + url.QueryEscape(tagStr)
+	// Prepare tags, escaping keys and values to ensure they're valid query string parameters.
+	if len(a.conf.Tags) > 0 {
+		tags := make([]string, len(a.conf.Tags))
+		for j, pair := range a.conf.Tags {
+			tagStr, err := msg.TryInterpolatedString(i, pair.value)
+			if err != nil {
+				return nil, fmt.Errorf("tag %v interpolation: %w", pair.key, err)
 			}
-			uploadInput.Tagging = aws.String(strings.Join(tags, "&"))
+			tags[j] = url.QueryEscape(pair.key) + "=" + url.QueryEscape(tagStr)
 		}
+		uploadInput.Tagging = aws.String(strings.Join(tags, "&"))
+	}
 
-		if a.conf.KMSKeyID != "" {
-			uploadInput.ServerSideEncryption = types.ServerSideEncryptionAwsKms
-			uploadInput.SSEKMSKeyId = &a.conf.KMSKeyID
-		}
+	if a.conf.KMSKeyID != "" {
+		uploadInput.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		uploadInput.SSEKMSKeyId = &a.conf.KMSKeyID
+	}
 
-		if a.conf.ChecksumAlgorithm != "" {
-			uploadInput.ChecksumAlgorithm = types.ChecksumAlgorithm(a.conf.ChecksumAlgorithm)
-		}
+	if a.conf.ChecksumAlgorithm != "" {
+		uploadInput.ChecksumAlgorithm = types.ChecksumAlgorithm(a.conf.ChecksumAlgorithm)
+	}
 
-		// NOTE: This overrides the ServerSideEncryption set above. We need this to preserve
-		// backwards compatibility, where it is allowed to only set kms_key_id in the config and
-		// the ServerSideEncryption value of "aws:kms" is implied.
-		if a.conf.ServerSideEncryption != "" {
-			uploadInput.ServerSideEncryption = types.ServerSideEncryption(a.conf.ServerSideEncryption)
-		}
+	// NOTE: This overrides the ServerSideEncryption set above. We need this to preserve
+	// backwards compatibility, where it is allowed to only set kms_key_id in the config and
+	// the ServerSideEncryption value of "aws:kms" is implied.
+	if a.conf.ServerSideEncryption != "" {
+		uploadInput.ServerSideEncryption = types.ServerSideEncryption(a.conf.ServerSideEncryption)
+	}
 
-		if _, err := a.uploader.Upload(ctx, uploadInput); err != nil {
-			return err
-		}
-		return nil
-	})
+	return uploadInput, nil
 }
 
-func (a *amazonS3Writer) Close(context.Context) error {
+func (a *amazonS3Writer) Close(ctx context.Context) error {
+	if a.roller != nil {
+		return a.roller.Close(ctx)
+	}
 	return nil
 }
@@ -0,0 +1,80 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type mockS3Uploads struct {
+	puts       []string
+	multiparts []string
+}
+
+func (m *mockS3Uploads) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	b, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.puts = append(m.puts, string(b))
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (m *mockS3Uploads) Upload(ctx context.Context, input *s3.PutObjectInput, opts ...func(*manager.Uploader)) (*manager.UploadOutput, error) {
+	b, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.multiparts = append(m.multiparts, string(b))
+	return &manager.UploadOutput{}, nil
+}
+
+func TestS3OutputMultipartThreshold(t *testing.T) {
+	pConf, err := s3oOutputSpec().ParseYAML(`
+bucket: foo
+path: ${! counter() }.txt
+region: us-east-1
+multipart_threshold: 4
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := s3oConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	w, err := newAmazonS3Writer(conf, service.MockResources())
+	require.NoError(t, err)
+
+	var m mockS3Uploads
+	w.client = &m
+	w.uploader = &m
+
+	require.NoError(t, w.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte("abc")),
+		service.NewMessage([]byte("abcd")),
+		service.NewMessage([]byte("abcde")),
+	}))
+
+	assert.Equal(t, []string{"abc"}, m.puts)
+	assert.Equal(t, []string{"abcd", "abcde"}, m.multiparts)
+}
@@ -47,6 +47,7 @@ type bsiConfig struct {
 	Prefix        string
 	DeleteObjects bool
 	FileReader    *service.OwnedInput
+	Queue         bsiQueueConfig
 	Codec         codec.DeprecatedFallbackCodec
 }
 
@@ -80,6 +81,13 @@ func bsiConfigFromParsed(pConf *service.ParsedConfig) (conf bsiConfig, err error
 			return
 		}
 	}
+	if conf.Queue, err = bsiQueueConfigFromParsed(pConf); err != nil {
+		return
+	}
+	if conf.Queue.Name != "" && conf.FileReader != nil {
+		err = errors.New("cannot specify both a queue.name and a targets_input")
+		return
+	}
 	return
 }
 
@@ -109,6 +117,8 @@ When downloading large files it's often necessary to process it in streamed part
 
 By default this input will consume all files found within the target container and will then gracefully terminate. This is referred to as a "batch" mode of operation. However, it's possible to instead configure a container as https://learn.microsoft.com/en-gb/azure/event-grid/event-schema-blob-storage[an Event Grid source^] and then use this as a `+"<<targetsinput, `targets_input`>>"+`, in which case new files are consumed as they're uploaded and Redpanda Connect will continue listening for and downloading files as they arrive. This is referred to as a "streamed" mode of operation.
 
+The simplest way to stream new files is to create an Event Grid subscription of the container with a https://learn.microsoft.com/en-us/azure/event-grid/handler-storage-queues[Storage Queue endpoint^] and specify the queue with the field `+"`queue.name`"+`, in which case `+"`Microsoft.Storage.BlobCreated`"+` events are consumed from the queue and events of other types, other containers, or blobs outside of the `+"`prefix`"+` are deleted. An event is only deleted from the queue once its blob has been processed, and otherwise is made visible again in order to be redelivered. Events that reference blobs which no longer exist, or which can't be parsed, are moved to the `+"`queue.dead_letter_queue`"+` when specified, and are otherwise deleted.
+
 == Metadata
 
 This input adds the following metadata fields to each message:
@@ -163,6 +173,7 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 						},
 					},
 				}),
+			bsiQueueField(),
 		)
 }
 
@@ -179,7 +190,7 @@ func init() {
 				return nil, err
 			}
 
-			if conf.FileReader == nil {
+			if conf.FileReader == nil && conf.Queue.Name == "" {
 				rdr = service.AutoRetryNacksBatched(rdr)
 			}
 			return rdr, nil
@@ -243,6 +254,9 @@ type azureTargetReader interface {
 }
 
 func newAzureTargetReader(ctx context.Context, logger *service.Logger, conf bsiConfig) (azureTargetReader, error) {
+	if conf.Queue.Name != "" {
+		return newAzureTargetQueueReader(conf, logger), nil
+	}
 	if conf.FileReader == nil {
 		return newAzureTargetBatchReader(ctx, conf)
 	}
@@ -0,0 +1,268 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	azq "github.com/Azure/azure-sdk-for-go/sdk/storage/azqueue"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Blob Storage Input Queue Fields
+	bsiFieldQueue                  = "queue"
+	bsiQueueFieldName              = "name"
+	bsiQueueFieldDeadLetterQueue   = "dead_letter_queue"
+	bsiQueueFieldVisibilityTimeout = "visibility_timeout"
+	bsiQueueFieldMaxMessages       = "max_messages"
+)
+
+func bsiQueueField() *service.ConfigField {
+	return service.NewObjectField(bsiFieldQueue,
+		service.NewStringField(bsiQueueFieldName).
+			Description("An optional Storage Queue to which Event Grid delivers the blob events of the container. When specified events received from the queue control which blobs are downloaded.").
+			Default(""),
+		service.NewStringField(bsiQueueFieldDeadLetterQueue).
+			Description("An optional Storage Queue to move events to that reference blobs which no longer exist, or which can't be parsed. When empty such events are deleted.").
+			Default(""),
+		service.NewDurationField(bsiQueueFieldVisibilityTimeout).
+			Description("The period for which events are hidden from other consumers once received, which should exceed the time taken to process a blob.").
+			Default("5m").
+			Advanced(),
+		service.NewIntField(bsiQueueFieldMaxMessages).
+			Description("The maximum number of events to receive from each request, between 1 and 32.").
+			Default(10).
+			Advanced(),
+	).
+		Description("Consume Event Grid blob events from a Storage Queue in order to trigger blob downloads. See <<stream-new-files>>.").
+		Version("4.48.0")
+}
+
+type bsiQueueConfig struct {
+	client            *azq.ServiceClient
+	Name              string
+	DeadLetterQueue   string
+	VisibilityTimeout time.Duration
+	MaxMessages       int
+}
+
+func bsiQueueConfigFromParsed(pConf *service.ParsedConfig) (conf bsiQueueConfig, err error) {
+	qConf := pConf.Namespace(bsiFieldQueue)
+	if conf.Name, err = qConf.FieldString(bsiQueueFieldName); err != nil || conf.Name == "" {
+		return
+	}
+	if conf.DeadLetterQueue, err = qConf.FieldString(bsiQueueFieldDeadLetterQueue); err != nil {
+		return
+	}
+	if conf.VisibilityTimeout, err = qConf.FieldDuration(bsiQueueFieldVisibilityTimeout); err != nil {
+		return
+	}
+	if conf.MaxMessages, err = qConf.FieldInt(bsiQueueFieldMaxMessages); err != nil {
+		return
+	}
+	if conf.MaxMessages < 1 || conf.MaxMessages > 32 {
+		err = fmt.Errorf("queue max_messages must be between 1 and 32, got %v", conf.MaxMessages)
+		return
+	}
+	conf.client, err = queueServiceClientFromParsed(pConf)
+	return
+}
+
+//------------------------------------------------------------------------------
+
+// blobEvent is an Event Grid event of a blob, in either the Event Grid or the
+// CloudEvents schema.
+type blobEvent struct {
+	EventType string `json:"eventType"`
+	Type      string `json:"type"`
+	Subject   string `json:"subject"`
+}
+
+// parseBlobEvent parses the text of a queue message as a blob event, which
+// Event Grid encodes as base64 by default.
+func parseBlobEvent(text string) (blobEvent, error) {
+	var event blobEvent
+	b := []byte(text)
+	if decoded, err := base64.StdEncoding.DecodeString(text); err == nil {
+		b = decoded
+	}
+	if err := json.Unmarshal(b, &event); err != nil {
+		return event, fmt.Errorf("failed to parse event: %w", err)
+	}
+	if event.Type == "" {
+		event.Type = event.EventType
+	}
+	if event.Type == "" || event.Subject == "" {
+		return event, errors.New("message is not an event")
+	}
+	return event, nil
+}
+
+// blobName returns the name of the blob which was created by an event, and
+// false if the event isn't of a created blob within the container and prefix.
+func (e blobEvent) blobName(container, prefix string) (string, bool) {
+	if e.Type != "Microsoft.Storage.BlobCreated" {
+		return "", false
+	}
+	eventContainer, name, ok := strings.Cut(strings.TrimPrefix(e.Subject, "/blobServices/default/containers/"), "/blobs/")
+	if !ok || name == "" || (container != "" && eventContainer != container) {
+		return "", false
+	}
+	if !strings.HasPrefix(name, prefix) {
+		return "", false
+	}
+	return name, true
+}
+
+// blobEventQueue is the subset of a queue client used for consuming events.
+type blobEventQueue interface {
+	DequeueMessages(ctx context.Context, o *azq.DequeueMessagesOptions) (azq.DequeueMessagesResponse, error)
+	UpdateMessage(ctx context.Context, messageID, popReceipt, content string, o *azq.UpdateMessageOptions) (azq.UpdateMessageResponse, error)
+	DeleteMessage(ctx context.Context, messageID, popReceipt string, o *azq.DeleteMessageOptions) (azq.DeleteMessageResponse, error)
+	EnqueueMessage(ctx context.Context, content string, o *azq.EnqueueMessageOptions) (azq.EnqueueMessagesResponse, error)
+}
+
+type azureTargetQueueReader struct {
+	conf       bsiConfig
+	log        *service.Logger
+	queue      blobEventQueue
+	deadLetter blobEventQueue
+	deleteFn   func(key string, prev service.AckFunc) service.AckFunc
+
+	pending []*azureObjectTarget
+}
+
+func newAzureTargetQueueReader(conf bsiConfig, log *service.Logger) *azureTargetQueueReader {
+	r := &azureTargetQueueReader{
+		conf:  conf,
+		log:   log,
+		queue: conf.Queue.client.NewQueueClient(conf.Queue.Name),
+		deleteFn: func(key string, prev service.AckFunc) service.AckFunc {
+			return deleteAzureObjectAckFn(conf.client, conf.Container, key, conf.DeleteObjects, prev)
+		},
+	}
+	if conf.Queue.DeadLetterQueue != "" {
+		r.deadLetter = conf.Queue.client.NewQueueClient(conf.Queue.DeadLetterQueue)
+	}
+	return r
+}
+
+func (r *azureTargetQueueReader) Pop(ctx context.Context) (*azureObjectTarget, error) {
+	for len(r.pending) == 0 {
+		if err := r.receive(ctx); err != nil {
+			return nil, err
+		}
+		if len(r.pending) > 0 {
+			break
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	t := r.pending[0]
+	r.pending = r.pending[1:]
+	return t, nil
+}
+
+func (r *azureTargetQueueReader) receive(ctx context.Context) error {
+	numMessages := int32(r.conf.Queue.MaxMessages)
+	visibilityTimeout := int32(r.conf.Queue.VisibilityTimeout.Seconds())
+	res, err := r.queue.DequeueMessages(ctx, &azq.DequeueMessagesOptions{
+		NumberOfMessages:  &numMessages,
+		VisibilityTimeout: &visibilityTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to dequeue events: %w", err)
+	}
+
+	for _, m := range res.Messages {
+		if m.MessageID == nil || m.PopReceipt == nil || m.MessageText == nil {
+			continue
+		}
+
+		event, err := parseBlobEvent(*m.MessageText)
+		if err != nil {
+			r.log.Warnf("Dead-lettering queue message %v: %v", *m.MessageID, err)
+			if err := r.deadLetterMessage(ctx, m); err != nil {
+				r.log.Errorf("Failed to dead-letter queue message %v: %v", *m.MessageID, err)
+			}
+			continue
+		}
+
+		name, ok := event.blobName(r.conf.Container, r.conf.Prefix)
+		if !ok {
+			// Events of other types, such as deletions, are dropped.
+			if err := r.deleteMessage(ctx, m); err != nil {
+				r.log.Errorf("Failed to delete queue message %v: %v", *m.MessageID, err)
+			}
+			continue
+		}
+		r.pending = append(r.pending, newAzureObjectTarget(name, r.deleteFn(name, r.ackFn(m, name))))
+	}
+	return nil
+}
+
+// ackFn returns a function which deletes an event once its blob is processed,
+// or makes it visible again in order for it to be redelivered. Events of blobs
+// which no longer exist are dead-lettered.
+func (r *azureTargetQueueReader) ackFn(m *azq.DequeuedMessage, name string) service.AckFunc {
+	return func(ctx context.Context, err error) error {
+		if err == nil {
+			return r.deleteMessage(ctx, m)
+		}
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			r.log.Warnf("Dead-lettering event of blob %v which no longer exists", name)
+			return r.deadLetterMessage(ctx, m)
+		}
+		_, uerr := r.queue.UpdateMessage(ctx, *m.MessageID, *m.PopReceipt, *m.MessageText, &azq.UpdateMessageOptions{
+			VisibilityTimeout: new(int32),
+		})
+		return uerr
+	}
+}
+
+func (r *azureTargetQueueReader) deleteMessage(ctx context.Context, m *azq.DequeuedMessage) error {
+	_, err := r.queue.DeleteMessage(ctx, *m.MessageID, *m.PopReceipt, nil)
+	return err
+}
+
+func (r *azureTargetQueueReader) deadLetterMessage(ctx context.Context, m *azq.DequeuedMessage) error {
+	if r.deadLetter != nil {
+		if _, err := r.deadLetter.EnqueueMessage(ctx, *m.MessageText, nil); err != nil {
+			return err
+		}
+	}
+	return r.deleteMessage(ctx, m)
+}
+
+func (r *azureTargetQueueReader) Close(ctx context.Context) error {
+	for _, p := range r.pending {
+		_ = p.ackFn(ctx, errors.New("shutting down"))
+	}
+	r.pending = nil
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	azq "github.com/Azure/azure-sdk-for-go/sdk/storage/azqueue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeBlobEventQueue struct {
+	messages []*azq.DequeuedMessage
+	actions  []string
+}
+
+func (f *fakeBlobEventQueue) DequeueMessages(context.Context, *azq.DequeueMessagesOptions) (azq.DequeueMessagesResponse, error) {
+	var res azq.DequeueMessagesResponse
+	res.Messages, f.messages = f.messages, nil
+	return res, nil
+}
+
+func (f *fakeBlobEventQueue) UpdateMessage(_ context.Context, messageID, _, _ string, _ *azq.UpdateMessageOptions) (azq.UpdateMessageResponse, error) {
+	f.actions = append(f.actions, "update "+messageID)
+	return azq.UpdateMessageResponse{}, nil
+}
+
+func (f *fakeBlobEventQueue) DeleteMessage(_ context.Context, messageID, _ string, _ *azq.DeleteMessageOptions) (azq.DeleteMessageResponse, error) {
+	f.actions = append(f.actions, "delete "+messageID)
+	return azq.DeleteMessageResponse{}, nil
+}
+
+func (f *fakeBlobEventQueue) EnqueueMessage(_ context.Context, content string, _ *azq.EnqueueMessageOptions) (azq.EnqueueMessagesResponse, error) {
+	f.actions = append(f.actions, "enqueue "+content)
+	return azq.EnqueueMessagesResponse{}, nil
+}
+
+func blobEventMessage(id, text string) *azq.DequeuedMessage {
+	popReceipt := "receipt"
+	return &azq.DequeuedMessage{MessageID: &id, PopReceipt: &popReceipt, MessageText: &text}
+}
+
+func TestBlobEventParse(t *testing.T) {
+	gridEvent := `{"eventType":"Microsoft.Storage.BlobCreated","subject":"/blobServices/default/containers/foo/blobs/bar/baz.json"}`
+	cloudEvent := `{"type":"Microsoft.Storage.BlobCreated","subject":"/blobServices/default/containers/foo/blobs/bar/baz.json"}`
+
+	for _, text := range []string{gridEvent, cloudEvent, base64.StdEncoding.EncodeToString([]byte(gridEvent))} {
+		event, err := parseBlobEvent(text)
+		require.NoError(t, err, text)
+
+		name, ok := event.blobName("foo", "bar/")
+		assert.True(t, ok)
+		assert.Equal(t, "bar/baz.json", name)
+
+		_, ok = event.blobName("other", "")
+		assert.False(t, ok)
+
+		_, ok = event.blobName("foo", "baz/")
+		assert.False(t, ok)
+	}
+
+	_, err := parseBlobEvent(`{"foo":"bar"}`)
+	require.Error(t, err)
+
+	_, err = parseBlobEvent(`nope`)
+	require.Error(t, err)
+}
+
+func TestBlobStorageQueueTargets(t *testing.T) {
+	event := func(eventType, name string) string {
+		return fmt.Sprintf(`{"eventType":%q,"subject":"/blobServices/default/containers/foo/blobs/%v"}`, eventType, name)
+	}
+
+	queue := &fakeBlobEventQueue{messages: []*azq.DequeuedMessage{
+		blobEventMessage("1", event("Microsoft.Storage.BlobDeleted", "a")),
+		blobEventMessage("2", "nope"),
+		blobEventMessage("3", event("Microsoft.Storage.BlobCreated", "b")),
+		blobEventMessage("4", event("Microsoft.Storage.BlobCreated", "c")),
+		blobEventMessage("5", event("Microsoft.Storage.BlobCreated", "d")),
+	}}
+	deadLetter := &fakeBlobEventQueue{}
+
+	r := &azureTargetQueueReader{
+		conf:       bsiConfig{Container: "foo", Queue: bsiQueueConfig{MaxMessages: 10}},
+		log:        service.MockResources().Logger(),
+		queue:      queue,
+		deadLetter: deadLetter,
+		deleteFn: func(_ string, prev service.AckFunc) service.AckFunc {
+			return prev
+		},
+	}
+
+	var names []string
+	var targets []*azureObjectTarget
+	for range 3 {
+		target, err := r.Pop(context.Background())
+		require.NoError(t, err)
+		names = append(names, target.key)
+		targets = append(targets, target)
+	}
+	assert.Equal(t, []string{"b", "c", "d"}, names)
+	assert.Equal(t, []string{"enqueue nope"}, deadLetter.actions)
+
+	notFound := &azcore.ResponseError{StatusCode: http.StatusNotFound, ErrorCode: string(bloberror.BlobNotFound)}
+	require.NoError(t, targets[0].ackFn(context.Background(), nil))
+	require.NoError(t, targets[1].ackFn(context.Background(), errors.New("nope")))
+	require.NoError(t, targets[2].ackFn(context.Background(), notFound))
+
+	assert.Equal(t, []string{"delete 1", "delete 2", "delete 3", "update 4", "delete 5"}, queue.actions)
+	assert.Len(t, deadLetter.actions, 2)
+}
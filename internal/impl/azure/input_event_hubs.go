@@ -0,0 +1,579 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs/checkpoints"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Jeffail/checkpoint"
+	"github.com/Jeffail/shutdown"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Event Hubs Input Fields
+	ehiFieldConnectionString  = "connection_string"
+	ehiFieldNamespace         = "namespace"
+	ehiFieldEventHub          = "event_hub"
+	ehiFieldConsumerGroup     = "consumer_group"
+	ehiFieldCheckpointStore   = "checkpoint_store"
+	ehiFieldStoreConnString   = "storage_connection_string"
+	ehiFieldStoreAccount      = "storage_account"
+	ehiFieldStoreContainer    = "container"
+	ehiFieldStartFromOldest   = "start_from_oldest"
+	ehiFieldLoadBalancing     = "load_balancing_strategy"
+	ehiFieldBatchSize         = "batch_size"
+	ehiFieldBatchPeriod       = "batch_period"
+	ehiFieldCheckpointLimit   = "checkpoint_limit"
+	ehiFieldLagUpdateInterval = "lag_update_interval"
+)
+
+type ehiConfig struct {
+	ConnectionString  string
+	Namespace         string
+	EventHub          string
+	ConsumerGroup     string
+	StoreConnString   string
+	StoreAccount      string
+	StoreContainer    string
+	StartFromOldest   bool
+	LoadBalancing     azeventhubs.ProcessorStrategy
+	BatchSize         int
+	BatchPeriod       time.Duration
+	CheckpointLimit   int
+	LagUpdateInterval time.Duration
+}
+
+func ehiConfigFromParsed(pConf *service.ParsedConfig) (conf ehiConfig, err error) {
+	if conf.ConnectionString, err = pConf.FieldString(ehiFieldConnectionString); err != nil {
+		return
+	}
+	if conf.Namespace, err = pConf.FieldString(ehiFieldNamespace); err != nil {
+		return
+	}
+	if conf.ConnectionString == "" && conf.Namespace == "" {
+		err = errors.New("either connection_string or namespace must be set")
+		return
+	}
+	if conf.EventHub, err = pConf.FieldString(ehiFieldEventHub); err != nil {
+		return
+	}
+	if conf.ConsumerGroup, err = pConf.FieldString(ehiFieldConsumerGroup); err != nil {
+		return
+	}
+
+	storeConf := pConf.Namespace(ehiFieldCheckpointStore)
+	if conf.StoreConnString, err = storeConf.FieldString(ehiFieldStoreConnString); err != nil {
+		return
+	}
+	if conf.StoreAccount, err = storeConf.FieldString(ehiFieldStoreAccount); err != nil {
+		return
+	}
+	if conf.StoreConnString == "" && conf.StoreAccount == "" {
+		err = errors.New("either storage_connection_string or storage_account must be set for the checkpoint store")
+		return
+	}
+	if conf.StoreContainer, err = storeConf.FieldString(ehiFieldStoreContainer); err != nil {
+		return
+	}
+
+	if conf.StartFromOldest, err = pConf.FieldBool(ehiFieldStartFromOldest); err != nil {
+		return
+	}
+	var strategy string
+	if strategy, err = pConf.FieldString(ehiFieldLoadBalancing); err != nil {
+		return
+	}
+	conf.LoadBalancing = azeventhubs.ProcessorStrategy(strategy)
+	if conf.BatchSize, err = pConf.FieldInt(ehiFieldBatchSize); err != nil {
+		return
+	}
+	if conf.BatchSize < 1 {
+		err = errors.New("batch_size must be at least 1")
+		return
+	}
+	if conf.BatchPeriod, err = pConf.FieldDuration(ehiFieldBatchPeriod); err != nil {
+		return
+	}
+	if conf.CheckpointLimit, err = pConf.FieldInt(ehiFieldCheckpointLimit); err != nil {
+		return
+	}
+	if conf.LagUpdateInterval, err = pConf.FieldDuration(ehiFieldLagUpdateInterval); err != nil {
+		return
+	}
+	return
+}
+
+func ehiSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services", "Azure").
+		Version("4.48.0").
+		Summary(`Consumes events from an Azure Event Hub, balancing partitions across instances and storing checkpoints in Azure Blob Storage.`).
+		Description(`
+Only one authentication method is required for each of the Event Hub and the checkpoint store, either a connection string or a namespace and storage account respectively. When a connection string is not provided the credentials are obtained with https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/sdk/azidentity#DefaultAzureCredential[DefaultAzureCredential^].
+
+== Load balancing
+
+Instances that consume from the same event hub and consumer group, and share a checkpoint store container, claim ownership of partitions from each other with the chosen `+"`load_balancing_strategy`"+`, and therefore partitions are rebalanced as instances are added or removed.
+
+== Checkpoints
+
+The checkpoint of a partition is updated once all events up to it have been acknowledged. Partitions without a checkpoint are consumed from either the oldest or the newest event, depending on `+"`start_from_oldest`"+`.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- event_hubs_partition_id
+- event_hubs_sequence_number
+- event_hubs_offset
+- event_hubs_enqueued_time
+- event_hubs_partition_key
+- event_hubs_content_type
+- event_hubs_message_id
+- All application properties
+`+"```"+`
+
+== Metrics
+
+The gauge `+"`azure_event_hubs_lag`"+` tracks the number of events in each partition that have not yet been consumed, which is labelled with the event hub, consumer group and partition.`).
+		Fields(
+			service.NewStringField(ehiFieldConnectionString).
+				Description("A connection string of the Event Hubs namespace, which takes precedence over `namespace`.").
+				Example("Endpoint=sb://example.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=...").
+				Secret().
+				Default(""),
+			service.NewStringField(ehiFieldNamespace).
+				Description("The fully qualified namespace to connect to using the default Azure credentials.").
+				Example("example.servicebus.windows.net").
+				Default(""),
+			service.NewStringField(ehiFieldEventHub).
+				Description("The name of the event hub to consume from."),
+			service.NewStringField(ehiFieldConsumerGroup).
+				Description("The consumer group to consume as.").
+				Default("$Default"),
+			service.NewObjectField(ehiFieldCheckpointStore,
+				service.NewStringField(ehiFieldStoreConnString).
+					Description("A connection string of the storage account, which takes precedence over `storage_account`.").
+					Secret().
+					Default(""),
+				service.NewStringField(ehiFieldStoreAccount).
+					Description("The name of the storage account to access using the default Azure credentials.").
+					Default(""),
+				service.NewStringField(ehiFieldStoreContainer).
+					Description("The name of the blob container to store checkpoints and partition ownership in, which must already exist."),
+			).
+				Description("The Azure Blob Storage container to store checkpoints and partition ownership in."),
+			service.NewBoolField(ehiFieldStartFromOldest).
+				Description("Whether to consume partitions without a checkpoint from the oldest event, otherwise only new events are consumed.").
+				Default(true),
+			service.NewStringAnnotatedEnumField(ehiFieldLoadBalancing, map[string]string{
+				string(azeventhubs.ProcessorStrategyBalanced): "Claims a single partition at a time until partitions are balanced across instances.",
+				string(azeventhubs.ProcessorStrategyGreedy):   "Claims as many partitions as required to balance partitions across instances at once.",
+			}).
+				Description("The strategy for claiming partitions from other instances.").
+				Advanced().
+				Default(string(azeventhubs.ProcessorStrategyBalanced)),
+			service.NewIntField(ehiFieldBatchSize).
+				Description("The maximum number of events to consume from a partition as a batch.").
+				Default(100),
+			service.NewDurationField(ehiFieldBatchPeriod).
+				Description("The maximum period of time to wait for a batch to fill up before it is consumed.").
+				Default("1s"),
+			service.NewIntField(ehiFieldCheckpointLimit).
+				Description("The maximum number of batches of a partition that can be pending acknowledgement at any given time.").
+				Advanced().
+				Default(1024),
+			service.NewDurationField(ehiFieldLagUpdateInterval).
+				Description("The interval at which the lag of each partition is updated.").
+				Advanced().
+				Default("30s"),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Consume With Checkpoints", "Consumes an event hub across any number of instances that share a checkpoint container.", `
+input:
+  azure_event_hubs:
+    connection_string: ${EVENT_HUBS_CONNECTION_STRING}
+    event_hub: telemetry
+    consumer_group: connect
+    checkpoint_store:
+      storage_connection_string: ${STORAGE_CONNECTION_STRING}
+      container: checkpoints
+`)
+}
+
+func init() {
+	err := service.RegisterBatchInput("azure_event_hubs", ehiSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			pConf, err := ehiConfigFromParsed(conf)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksBatchedToggled(conf, newAzureEventHubsReader(pConf, mgr))
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type asyncEventBatch struct {
+	batch service.MessageBatch
+	ackFn service.AckFunc
+}
+
+type azureEventHubsReader struct {
+	conf ehiConfig
+	log  *service.Logger
+	lag  *service.MetricGauge
+
+	shutSig *shutdown.Signaller
+
+	connMut sync.Mutex
+	msgChan chan asyncEventBatch
+	runDone chan struct{}
+}
+
+func newAzureEventHubsReader(conf ehiConfig, mgr *service.Resources) *azureEventHubsReader {
+	return &azureEventHubsReader{
+		conf:    conf,
+		log:     mgr.Logger(),
+		lag:     mgr.Metrics().NewGauge("azure_event_hubs_lag", "event_hub", "consumer_group", "partition"),
+		shutSig: shutdown.NewSignaller(),
+	}
+}
+
+func (a *azureEventHubsReader) newClients() (*azeventhubs.ConsumerClient, *checkpoints.BlobStore, error) {
+	var cred *azidentity.DefaultAzureCredential
+	getCred := func() (*azidentity.DefaultAzureCredential, error) {
+		if cred != nil {
+			return cred, nil
+		}
+		var err error
+		if cred, err = azidentity.NewDefaultAzureCredential(nil); err != nil {
+			return nil, fmt.Errorf("getting default Azure credentials: %w", err)
+		}
+		return cred, nil
+	}
+
+	var containerClient *container.Client
+	var err error
+	if a.conf.StoreConnString != "" {
+		containerClient, err = container.NewClientFromConnectionString(a.conf.StoreConnString, a.conf.StoreContainer, nil)
+	} else {
+		var c *azidentity.DefaultAzureCredential
+		if c, err = getCred(); err != nil {
+			return nil, nil, err
+		}
+		containerURL := fmt.Sprintf(blobEndpointExp, a.conf.StoreAccount) + "/" + a.conf.StoreContainer
+		containerClient, err = container.NewClient(containerURL, c, nil)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating checkpoint store client: %w", err)
+	}
+	store, err := checkpoints.NewBlobStore(containerClient, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating checkpoint store: %w", err)
+	}
+
+	var consumer *azeventhubs.ConsumerClient
+	if a.conf.ConnectionString != "" {
+		consumer, err = azeventhubs.NewConsumerClientFromConnectionString(a.conf.ConnectionString, a.conf.EventHub, a.conf.ConsumerGroup, nil)
+	} else {
+		var c *azidentity.DefaultAzureCredential
+		if c, err = getCred(); err != nil {
+			return nil, nil, err
+		}
+		consumer, err = azeventhubs.NewConsumerClient(a.conf.Namespace, a.conf.EventHub, a.conf.ConsumerGroup, c, nil)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating consumer client: %w", err)
+	}
+	return consumer, store, nil
+}
+
+func (a *azureEventHubsReader) Connect(ctx context.Context) error {
+	a.connMut.Lock()
+	defer a.connMut.Unlock()
+
+	if a.msgChan != nil {
+		return nil
+	}
+	if a.shutSig.IsSoftStopSignalled() {
+		return service.ErrEndOfInput
+	}
+
+	consumer, store, err := a.newClients()
+	if err != nil {
+		return err
+	}
+
+	startPos := azeventhubs.StartPosition{}
+	enabled := true
+	if a.conf.StartFromOldest {
+		startPos.Earliest = &enabled
+	} else {
+		startPos.Latest = &enabled
+	}
+
+	processor, err := azeventhubs.NewProcessor(consumer, store, &azeventhubs.ProcessorOptions{
+		LoadBalancingStrategy: a.conf.LoadBalancing,
+		StartPositions: azeventhubs.StartPositions{
+			Default: startPos,
+		},
+	})
+	if err != nil {
+		_ = consumer.Close(ctx)
+		return fmt.Errorf("creating processor: %w", err)
+	}
+
+	msgChan := make(chan asyncEventBatch)
+	runDone := make(chan struct{})
+	go func() {
+		defer close(runDone)
+
+		runCtx, runCancel := a.shutSig.SoftStopCtx(context.Background())
+		defer runCancel()
+
+		go func() {
+			if err := processor.Run(runCtx); err != nil && runCtx.Err() == nil {
+				a.log.Errorf("Event hub processor stopped: %v", err)
+			}
+			// Stop all partitions when the processor stops for any reason.
+			runCancel()
+		}()
+
+		var wg sync.WaitGroup
+		for {
+			pc := processor.NextPartitionClient(runCtx)
+			if pc == nil {
+				break
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a.consumePartition(runCtx, consumer, pc, msgChan)
+			}()
+		}
+		wg.Wait()
+
+		if err := consumer.Close(context.Background()); err != nil {
+			a.log.Errorf("Failed to cleanly close consumer client: %v", err)
+		}
+	}()
+
+	a.msgChan = msgChan
+	a.runDone = runDone
+	return nil
+}
+
+func (a *azureEventHubsReader) eventToMessage(partitionID string, event *azeventhubs.ReceivedEventData) *service.Message {
+	msg := service.NewMessage(event.Body)
+	msg.MetaSetMut("event_hubs_partition_id", partitionID)
+	msg.MetaSetMut("event_hubs_sequence_number", event.SequenceNumber)
+	msg.MetaSetMut("event_hubs_offset", event.Offset)
+	if event.EnqueuedTime != nil {
+		msg.MetaSetMut("event_hubs_enqueued_time", event.EnqueuedTime.Format(time.RFC3339Nano))
+	}
+	if event.PartitionKey != nil {
+		msg.MetaSetMut("event_hubs_partition_key", *event.PartitionKey)
+	}
+	if event.ContentType != nil {
+		msg.MetaSetMut("event_hubs_content_type", *event.ContentType)
+	}
+	if event.MessageID != nil {
+		msg.MetaSetMut("event_hubs_message_id", *event.MessageID)
+	}
+	for k, v := range event.Properties {
+		msg.MetaSetMut(k, v)
+	}
+	return msg
+}
+
+// consumePartition reads batches of events from a partition that this
+// instance owns until ownership is lost or the context is cancelled.
+func (a *azureEventHubsReader) consumePartition(ctx context.Context, consumer *azeventhubs.ConsumerClient, pc *azeventhubs.ProcessorPartitionClient, msgChan chan<- asyncEventBatch) {
+	defer func() {
+		_ = pc.Close(context.Background())
+	}()
+
+	partitionID := pc.PartitionID()
+	a.log.Debugf("Claimed partition %v", partitionID)
+
+	checkpointer := checkpoint.NewCapped[*azeventhubs.ReceivedEventData](int64(a.conf.CheckpointLimit))
+
+	// Checkpoints are updated from acknowledgements, which may be resolved
+	// concurrently and out of order.
+	var checkpointMut sync.Mutex
+	var checkpointedSeq int64 = -1
+
+	var lastSeq atomic.Int64
+	lastSeq.Store(-1)
+
+	lagCtx, lagDone := context.WithCancel(ctx)
+	defer lagDone()
+	go a.trackLag(lagCtx, consumer, partitionID, &lastSeq)
+
+	for {
+		receiveCtx, receiveDone := context.WithTimeout(ctx, a.conf.BatchPeriod)
+		events, err := pc.ReceiveEvents(receiveCtx, a.conf.BatchSize, nil)
+		receiveDone()
+
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			var ehErr *azeventhubs.Error
+			if errors.As(err, &ehErr) && ehErr.Code == azeventhubs.ErrorCodeOwnershipLost {
+				a.log.Debugf("Lost ownership of partition %v", partitionID)
+			} else {
+				a.log.Errorf("Failed to receive events from partition %v: %v", partitionID, err)
+			}
+			return
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		latest := events[len(events)-1]
+		lastSeq.Store(latest.SequenceNumber)
+
+		batch := make(service.MessageBatch, len(events))
+		for i, event := range events {
+			batch[i] = a.eventToMessage(partitionID, event)
+		}
+
+		release, err := checkpointer.Track(ctx, latest, int64(len(events)))
+		if err != nil {
+			return
+		}
+
+		select {
+		case msgChan <- asyncEventBatch{
+			batch: batch,
+			ackFn: func(ctx context.Context, err error) error {
+				highest := release()
+				if highest == nil {
+					return nil
+				}
+
+				checkpointMut.Lock()
+				defer checkpointMut.Unlock()
+
+				if (*highest).SequenceNumber <= checkpointedSeq {
+					return nil
+				}
+				if err := pc.UpdateCheckpoint(ctx, *highest, nil); err != nil {
+					return fmt.Errorf("updating checkpoint of partition %v: %w", partitionID, err)
+				}
+				checkpointedSeq = (*highest).SequenceNumber
+				return nil
+			},
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// trackLag periodically updates the number of events in a partition that
+// have not yet been received.
+func (a *azureEventHubsReader) trackLag(ctx context.Context, consumer *azeventhubs.ConsumerClient, partitionID string, lastSeq *atomic.Int64) {
+	ticker := time.NewTicker(a.conf.LagUpdateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		props, err := consumer.GetPartitionProperties(ctx, partitionID, nil)
+		if err != nil {
+			if ctx.Err() == nil {
+				a.log.Debugf("Failed to get properties of partition %v: %v", partitionID, err)
+			}
+			continue
+		}
+
+		var lag int64
+		if seq := lastSeq.Load(); seq >= 0 {
+			lag = props.LastEnqueuedSequenceNumber - seq
+		} else if !props.IsEmpty {
+			lag = props.LastEnqueuedSequenceNumber - props.BeginningSequenceNumber + 1
+		}
+		a.lag.Set(max(lag, 0), a.conf.EventHub, a.conf.ConsumerGroup, partitionID)
+	}
+}
+
+func (a *azureEventHubsReader) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	a.connMut.Lock()
+	msgChan, runDone := a.msgChan, a.runDone
+	a.connMut.Unlock()
+
+	if msgChan == nil {
+		return nil, nil, service.ErrNotConnected
+	}
+
+	select {
+	case b := <-msgChan:
+		return b.batch, b.ackFn, nil
+	case <-runDone:
+		a.connMut.Lock()
+		if a.msgChan == msgChan {
+			a.msgChan = nil
+			a.runDone = nil
+		}
+		a.connMut.Unlock()
+		if a.shutSig.IsSoftStopSignalled() {
+			return nil, nil, service.ErrEndOfInput
+		}
+		return nil, nil, service.ErrNotConnected
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (a *azureEventHubsReader) Close(ctx context.Context) error {
+	a.shutSig.TriggerSoftStop()
+
+	a.connMut.Lock()
+	runDone := a.runDone
+	a.connMut.Unlock()
+
+	if runDone == nil {
+		return nil
+	}
+	select {
+	case <-runDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azeventhubs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestEventHubsInputConfig(t *testing.T) {
+	pConf, err := ehiSpec().ParseYAML(`
+connection_string: Endpoint=sb://example.servicebus.windows.net/;SharedAccessKeyName=foo;SharedAccessKey=bar
+event_hub: foo
+checkpoint_store:
+  storage_account: bar
+  container: checkpoints
+load_balancing_strategy: greedy
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := ehiConfigFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, "$Default", conf.ConsumerGroup)
+	assert.Equal(t, azeventhubs.ProcessorStrategyGreedy, conf.LoadBalancing)
+	assert.Equal(t, 100, conf.BatchSize)
+	assert.True(t, conf.StartFromOldest)
+
+	pConf, err = ehiSpec().ParseYAML(`
+namespace: example.servicebus.windows.net
+event_hub: foo
+checkpoint_store:
+  container: checkpoints
+`, nil)
+	require.NoError(t, err)
+
+	_, err = ehiConfigFromParsed(pConf)
+	require.Error(t, err)
+}
+
+func TestEventHubsInputMessage(t *testing.T) {
+	enqueued := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	partitionKey, contentType := "foo", "application/json"
+
+	a := newAzureEventHubsReader(ehiConfig{}, service.MockResources())
+	msg := a.eventToMessage("3", &azeventhubs.ReceivedEventData{
+		EventData: azeventhubs.EventData{
+			Body:        []byte(`{"hello":"world"}`),
+			ContentType: &contentType,
+			Properties:  map[string]any{"source": "sensor"},
+		},
+		EnqueuedTime:   &enqueued,
+		PartitionKey:   &partitionKey,
+		Offset:         1024,
+		SequenceNumber: 42,
+	})
+
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, `{"hello":"world"}`, string(b))
+
+	meta := map[string]any{}
+	require.NoError(t, msg.MetaWalkMut(func(k string, v any) error {
+		meta[k] = v
+		return nil
+	}))
+	assert.Equal(t, map[string]any{
+		"event_hubs_partition_id":    "3",
+		"event_hubs_sequence_number": int64(42),
+		"event_hubs_offset":          int64(1024),
+		"event_hubs_enqueued_time":   "2025-01-01T12:00:00Z",
+		"event_hubs_partition_key":   "foo",
+		"event_hubs_content_type":    "application/json",
+		"source":                     "sensor",
+	}, meta)
+}
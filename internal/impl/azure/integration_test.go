@@ -99,6 +99,36 @@ output:
     public_access_level: PRIVATE
     storage_connection_string: $VAR3
 
+input:
+  azure_blob_storage:
+    container: $VAR1-$ID
+    prefix: $VAR2
+    storage_connection_string: $VAR3
+`
+		integration.StreamTests(
+			integration.StreamTestOpenCloseIsolated(),
+			integration.StreamTestStreamIsolated(10),
+		).Run(
+			t, template,
+			integration.StreamTestOptVarSet("VAR1", dummyContainer),
+			integration.StreamTestOptVarSet("VAR2", dummyPrefix),
+			integration.StreamTestOptVarSet("VAR3", connString),
+		)
+	})
+
+	t.Run("blob_storage_staged_blocks", func(t *testing.T) {
+		template := `
+output:
+  azure_blob_storage:
+    blob_type: BLOCK
+    container: $VAR1-$ID
+    max_in_flight: 1
+    path: $VAR2/${!counter()}.txt
+    storage_connection_string: $VAR3
+    upload_block_threshold: 1
+    upload_block_size: 4
+    upload_concurrency: 2
+
 input:
   azure_blob_storage:
     container: $VAR1-$ID
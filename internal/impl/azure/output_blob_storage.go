@@ -17,6 +17,7 @@ package azure
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 
@@ -24,8 +25,13 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/gofrs/uuid/v5"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/rolling"
 )
 
 const (
@@ -34,6 +40,9 @@ const (
 	bsoFieldPath              = "path"
 	bsoFieldBlobType          = "blob_type"
 	bsoFieldPublicAccessLevel = "public_access_level"
+	bsoFieldBlockThreshold    = "upload_block_threshold"
+	bsoFieldBlockSize         = "upload_block_size"
+	bsoFieldConcurrency       = "upload_concurrency"
 )
 
 type bsoConfig struct {
@@ -42,6 +51,10 @@ type bsoConfig struct {
 	Path              *service.InterpolatedString
 	BlobType          *service.InterpolatedString
 	PublicAccessLevel *service.InterpolatedString
+	BlockThreshold    int
+	BlockSize         int
+	Concurrency       int
+	Rolling           *rolling.Config
 }
 
 func bsoConfigFromParsed(pConf *service.ParsedConfig) (conf bsoConfig, err error) {
@@ -65,6 +78,26 @@ func bsoConfigFromParsed(pConf *service.ParsedConfig) (conf bsoConfig, err error
 	if conf.PublicAccessLevel, err = pConf.FieldInterpolatedString(bsoFieldPublicAccessLevel); err != nil {
 		return
 	}
+	if conf.BlockThreshold, err = pConf.FieldInt(bsoFieldBlockThreshold); err != nil {
+		return
+	}
+	if conf.BlockSize, err = pConf.FieldInt(bsoFieldBlockSize); err != nil {
+		return
+	}
+	if conf.BlockSize < 1 || conf.BlockSize > blockblob.MaxStageBlockBytes {
+		err = fmt.Errorf("%v must be between 1 and %v bytes", bsoFieldBlockSize, blockblob.MaxStageBlockBytes)
+		return
+	}
+	if conf.Concurrency, err = pConf.FieldInt(bsoFieldConcurrency); err != nil {
+		return
+	}
+	if conf.Concurrency < 1 {
+		err = fmt.Errorf("%v must be at least 1", bsoFieldConcurrency)
+		return
+	}
+	if conf.Rolling, err = rolling.ConfigFromParsed(pConf); err != nil {
+		return
+	}
 	return
 }
 
@@ -88,7 +121,32 @@ Supports multiple authentication methods but only one of the following is requir
 If multiple are set then the `+"`storage_connection_string`"+` is given priority.
 
 If the `+"`storage_connection_string`"+` does not contain the `+"`AccountName`"+` parameter, please specify it in the
-`+"`storage_account`"+` field.`+service.OutputPerformanceDocs(true, false)).
+`+"`storage_account`"+` field.
+
+== Rolling files
+
+When `+"`rolling.enabled`"+` is set messages are accumulated into files which are uploaded as single blobs once they are rotated, which happens when a file reaches `+"`rolling.max_bytes`"+` or `+"`rolling.max_records`"+`, or when `+"`rolling.max_age`"+` has passed since it received its first message. A file is held in memory until it's uploaded, and block blobs are only visible in the container once their upload is committed, whereas a file written to an append blob is appended as a single block.
+
+The `+"`path`"+` and other interpolated fields are evaluated once per file against its first message at the time of rotation, and therefore functions such as `+"`uuid_v4()`"+` and `+"`now()`"+` produce a value unique to each file:
+
+`+"```yaml"+`
+output:
+  azure_blob_storage:
+    storage_account: TODO
+    container: TODO
+    path: events/${! now().ts_format("2006/01/02") }/${! uuid_v4() }.parquet
+    max_in_flight: 1024
+    rolling:
+      enabled: true
+      format: parquet
+      max_bytes: 134217728
+      max_age: 10m
+    processors:
+      - parquet_encode:
+          infer_schema: true
+`+"```"+`
+
+Messages are only acknowledged once the file they were added to is uploaded, and a failed upload results in all messages of the file being reattempted. Since writes are blocked until a file is rotated the number of messages that can be accumulated into a file is limited by `+"`max_in_flight`"+`, which should be set high enough to reach the size or record limits, otherwise files are only rotated by age.`+service.OutputPerformanceDocs(true, false)).
 		Fields(
 			service.NewInterpolatedStringField(bsoFieldContainer).
 				Description("The container for uploading the messages to.").
@@ -107,7 +165,23 @@ If the `+"`storage_connection_string`"+` does not contain the `+"`AccountName`"+
 				Description(`The container's public access level. The default value is `+"`PRIVATE`"+`.`).
 				Advanced().
 				Default("PRIVATE"),
+			service.NewIntField(bsoFieldBlockThreshold).
+				Description("The size in bytes of block blobs from which they are uploaded as separately staged blocks, rather than with a single request.").
+				Advanced().
+				Default(32*1024*1024).
+				Version("4.48.0"),
+			service.NewIntField(bsoFieldBlockSize).
+				Description("The size in bytes of the blocks of block blobs that are uploaded as staged blocks. A blob can consist of at most 50,000 blocks.").
+				Advanced().
+				Default(8*1024*1024).
+				Version("4.48.0"),
+			service.NewIntField(bsoFieldConcurrency).
+				Description("The number of blocks of each block blob that are staged in parallel.").
+				Advanced().
+				Default(4).
+				Version("4.48.0"),
 			service.NewOutputMaxInFlightField(),
+			rolling.ConfigField(),
 		)
 }
 
@@ -132,8 +206,9 @@ func init() {
 }
 
 type azureBlobStorageWriter struct {
-	conf bsoConfig
-	log  *service.Logger
+	conf   bsoConfig
+	roller *rolling.Roller
+	log    *service.Logger
 }
 
 func newAzureBlobStorageWriter(conf bsoConfig, log *service.Logger) (*azureBlobStorageWriter, error) {
@@ -141,6 +216,9 @@ func newAzureBlobStorageWriter(conf bsoConfig, log *service.Logger) (*azureBlobS
 		conf: conf,
 		log:  log,
 	}
+	if conf.Rolling != nil {
+		a.roller = rolling.NewRoller(conf.Rolling, a.writeBlob, log)
+	}
 	return a, nil
 }
 
@@ -171,14 +249,52 @@ func (a *azureBlobStorageWriter) uploadBlob(ctx context.Context, containerName,
 			}
 		}
 	} else {
-		_, err = containerClient.NewBlockBlobClient(blobName).UploadStream(ctx, bytes.NewReader(message), nil)
-		if err != nil {
+		if err = a.uploadBlockBlob(ctx, containerClient.NewBlockBlobClient(blobName), message); err != nil {
 			return fmt.Errorf("failed to push block to blob: %w", err)
 		}
 	}
 	return nil
 }
 
+// uploadBlockBlob uploads a block blob with a single request, or as staged
+// blocks that are committed once uploaded when it reaches the block threshold.
+// Blocks are read directly from the message rather than being copied.
+func (a *azureBlobStorageWriter) uploadBlockBlob(ctx context.Context, client *blockblob.Client, message []byte) error {
+	if len(message) < a.conf.BlockThreshold {
+		_, err := client.Upload(ctx, streaming.NopCloser(bytes.NewReader(message)), nil)
+		return err
+	}
+
+	numBlocks := (len(message) + a.conf.BlockSize - 1) / a.conf.BlockSize
+	if numBlocks > blockblob.MaxBlocks {
+		return fmt.Errorf("blob of %v bytes exceeds the maximum of %v blocks", len(message), blockblob.MaxBlocks)
+	}
+
+	// Block IDs must be unique within the blob and of equal length.
+	blockPrefix, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	blockIDs := make([]string, numBlocks)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(a.conf.Concurrency)
+	for i := range blockIDs {
+		blockIDs[i] = base64.StdEncoding.EncodeToString(fmt.Appendf(nil, "%v-%05d", blockPrefix, i))
+		block := message[i*a.conf.BlockSize : min((i+1)*a.conf.BlockSize, len(message))]
+		eg.Go(func() error {
+			_, err := client.StageBlock(egCtx, blockIDs[i], streaming.NopCloser(bytes.NewReader(block)), nil)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	_, err = client.CommitBlockList(ctx, blockIDs, nil)
+	return err
+}
+
 func (a *azureBlobStorageWriter) createContainer(ctx context.Context, containerName, accessLevel string) error {
 	var opts azblob.CreateContainerOptions
 	switch accessLevel {
@@ -194,6 +310,20 @@ func (a *azureBlobStorageWriter) createContainer(ctx context.Context, containerN
 }
 
 func (a *azureBlobStorageWriter) Write(ctx context.Context, msg *service.Message) error {
+	if a.roller != nil {
+		return a.roller.WriteBatch(ctx, service.MessageBatch{msg})
+	}
+
+	mBytes, err := msg.AsBytes()
+	if err != nil {
+		return err
+	}
+	return a.writeBlob(ctx, msg, mBytes)
+}
+
+// writeBlob uploads the body of a blob with fields interpolated from a message,
+// creating its container when it doesn't exist.
+func (a *azureBlobStorageWriter) writeBlob(ctx context.Context, msg *service.Message, mBytes []byte) error {
 	containerName, err := a.conf.Container.TryString(msg)
 	if err != nil {
 		return fmt.Errorf("container interpolation error: %s", err)
@@ -209,11 +339,6 @@ func (a *azureBlobStorageWriter) Write(ctx context.Context, msg *service.Message
 		return fmt.Errorf("blob type interpolation error: %s", err)
 	}
 
-	mBytes, err := msg.AsBytes()
-	if err != nil {
-		return err
-	}
-
 	if err := a.uploadBlob(ctx, containerName, blobName, blobType, mBytes); err != nil {
 		if isErrorCode(err, bloberror.ContainerNotFound) {
 			var accessLevel string
@@ -237,7 +362,10 @@ func (a *azureBlobStorageWriter) Write(ctx context.Context, msg *service.Message
 	return nil
 }
 
-func (a *azureBlobStorageWriter) Close(context.Context) error {
+func (a *azureBlobStorageWriter) Close(ctx context.Context) error {
+	if a.roller != nil {
+		return a.roller.Close(ctx)
+	}
 	return nil
 }
 
@@ -0,0 +1,75 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestBlobStorageOutputBlockThreshold(t *testing.T) {
+	var mut sync.Mutex
+	requests := map[string][]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		comp := r.URL.Query().Get("comp")
+		if comp == "" {
+			comp = "upload"
+		}
+		mut.Lock()
+		requests[path.Base(r.URL.Path)] = append(requests[path.Base(r.URL.Path)], comp)
+		mut.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	pConf, err := bsoSpec().ParseYAML(`
+storage_connection_string: "DefaultEndpointsProtocol=http;AccountName=foo;AccountKey=Zm9v;BlobEndpoint=`+srv.URL+`/foo;"
+container: bar
+path: ${! content().length() }
+upload_block_threshold: 4
+upload_block_size: 2
+upload_concurrency: 1
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := bsoConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	w, err := newAzureBlobStorageWriter(conf, service.MockResources().Logger())
+	require.NoError(t, err)
+
+	for _, s := range []string{"abc", "abcd", "abcde"} {
+		require.NoError(t, w.Write(context.Background(), service.NewMessage([]byte(s))))
+	}
+
+	assert.Equal(t, map[string][]string{
+		"3": {"upload"},
+		"4": {"block", "block", "blocklist"},
+		"5": {"block", "block", "block", "blocklist"},
+	}, requests)
+}
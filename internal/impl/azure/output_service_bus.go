@@ -0,0 +1,327 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Service Bus Output Fields
+	sboFieldConnectionString = "connection_string"
+	sboFieldNamespace        = "namespace"
+	sboFieldQueueOrTopic     = "queue_or_topic"
+	sboFieldSessionID        = "session_id"
+	sboFieldMessageID        = "message_id"
+	sboFieldScheduledTime    = "scheduled_enqueue_time"
+	sboFieldMetadata         = "metadata"
+	sboFieldBatching         = "batching"
+)
+
+type sboConfig struct {
+	ConnectionString string
+	Namespace        string
+	QueueOrTopic     string
+	SessionID        *service.InterpolatedString
+	MessageID        *service.InterpolatedString
+	ScheduledTime    *service.InterpolatedString
+	MetaFilter       *service.MetadataExcludeFilter
+}
+
+func sboConfigFromParsed(pConf *service.ParsedConfig) (conf sboConfig, err error) {
+	if conf.ConnectionString, err = pConf.FieldString(sboFieldConnectionString); err != nil {
+		return
+	}
+	if conf.Namespace, err = pConf.FieldString(sboFieldNamespace); err != nil {
+		return
+	}
+	if conf.ConnectionString == "" && conf.Namespace == "" {
+		err = errors.New("either connection_string or namespace must be set")
+		return
+	}
+	if conf.QueueOrTopic, err = pConf.FieldString(sboFieldQueueOrTopic); err != nil {
+		return
+	}
+	if pConf.Contains(sboFieldSessionID) {
+		if conf.SessionID, err = pConf.FieldInterpolatedString(sboFieldSessionID); err != nil {
+			return
+		}
+	}
+	if pConf.Contains(sboFieldMessageID) {
+		if conf.MessageID, err = pConf.FieldInterpolatedString(sboFieldMessageID); err != nil {
+			return
+		}
+	}
+	if pConf.Contains(sboFieldScheduledTime) {
+		if conf.ScheduledTime, err = pConf.FieldInterpolatedString(sboFieldScheduledTime); err != nil {
+			return
+		}
+	}
+	if conf.MetaFilter, err = pConf.FieldMetadataExcludeFilter(sboFieldMetadata); err != nil {
+		return
+	}
+	return
+}
+
+func sboSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services", "Azure").
+		Version("4.48.0").
+		Summary(`Sends messages to an Azure Service Bus queue or topic.`).
+		Description(`
+Only one authentication method is required, `+"`connection_string`"+` or `+"`namespace`"+`. When only a `+"`namespace`"+` is set the credentials are obtained with https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/sdk/azidentity#DefaultAzureCredential[DefaultAzureCredential^].
+
+The messages of a batch are sent with as few requests as possible, within the size limits of the Service Bus tier.
+
+== Sessions
+
+Queues and subscriptions that require sessions only accept messages with a session ID, which is set with the `+"`session_id`"+` field. Messages of the same session are delivered in order to a single consumer.
+
+== Scheduled messages
+
+When `+"`scheduled_enqueue_time`"+` resolves to a timestamp the message is only made available to consumers once that time has passed.
+
+== Metadata
+
+Metadata fields of messages are sent as application properties, and can be filtered with the `+"`metadata`"+` field.`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewStringField(sboFieldConnectionString).
+				Description("A connection string of the Service Bus namespace, which takes precedence over `namespace`.").
+				Example("Endpoint=sb://example.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=...").
+				Secret().
+				Default(""),
+			service.NewStringField(sboFieldNamespace).
+				Description("The fully qualified namespace to connect to using the default Azure credentials.").
+				Example("example.servicebus.windows.net").
+				Default(""),
+			service.NewStringField(sboFieldQueueOrTopic).
+				Description("The name of the queue or topic to send messages to."),
+			service.NewInterpolatedStringField(sboFieldSessionID).
+				Description("The session ID of each message.").
+				Example(`${! this.customer_id }`).
+				Optional(),
+			service.NewInterpolatedStringField(sboFieldMessageID).
+				Description("The ID of each message, which is used by Service Bus to detect duplicate messages.").
+				Example(`${! this.id }`).
+				Advanced().
+				Optional(),
+			service.NewInterpolatedStringField(sboFieldScheduledTime).
+				Description("An RFC3339 timestamp at which each message should be made available to consumers. When empty messages are available immediately.").
+				Example(`${! now().ts_add_iso8601("PT1H") }`).
+				Example(`${! this.deliver_at }`).
+				Optional(),
+			service.NewMetadataExcludeFilterField(sboFieldMetadata).
+				Description("Specify criteria for which metadata values are sent as application properties."),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(sboFieldBatching),
+		).
+		Example("Ordered Sessions", "Sends the events of each customer to a session enabled queue so that they are processed in order.", `
+output:
+  azure_service_bus:
+    connection_string: ${SERVICE_BUS_CONNECTION_STRING}
+    queue_or_topic: customer_events
+    session_id: ${! this.customer_id }
+    batching:
+      count: 100
+      period: 100ms
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("azure_service_bus", sboSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batcher service.BatchPolicy, mif int, err error) {
+			var pConf sboConfig
+			if pConf, err = sboConfigFromParsed(conf); err != nil {
+				return
+			}
+			if batcher, err = conf.FieldBatchPolicy(sboFieldBatching); err != nil {
+				return
+			}
+			if mif, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			out = newAzureServiceBusWriter(pConf, mgr.Logger())
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type azureServiceBusWriter struct {
+	conf sboConfig
+	log  *service.Logger
+
+	mut    sync.RWMutex
+	client *azservicebus.Client
+	sender *azservicebus.Sender
+}
+
+func newAzureServiceBusWriter(conf sboConfig, log *service.Logger) *azureServiceBusWriter {
+	return &azureServiceBusWriter{
+		conf: conf,
+		log:  log,
+	}
+}
+
+func (a *azureServiceBusWriter) Connect(ctx context.Context) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if a.sender != nil {
+		return nil
+	}
+
+	var client *azservicebus.Client
+	var err error
+	if a.conf.ConnectionString != "" {
+		client, err = azservicebus.NewClientFromConnectionString(a.conf.ConnectionString, nil)
+	} else {
+		var cred *azidentity.DefaultAzureCredential
+		if cred, err = azidentity.NewDefaultAzureCredential(nil); err != nil {
+			return fmt.Errorf("getting default Azure credentials: %w", err)
+		}
+		client, err = azservicebus.NewClient(a.conf.Namespace, cred, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("creating client: %w", err)
+	}
+
+	sender, err := client.NewSender(a.conf.QueueOrTopic, nil)
+	if err != nil {
+		_ = client.Close(ctx)
+		return fmt.Errorf("creating sender: %w", err)
+	}
+
+	a.client = client
+	a.sender = sender
+	return nil
+}
+
+func (a *azureServiceBusWriter) toServiceBusMessage(batch service.MessageBatch, i int) (*azservicebus.Message, error) {
+	msg := batch[i]
+	body, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	sbMsg := &azservicebus.Message{Body: body}
+	if a.conf.SessionID != nil {
+		sessionID, err := batch.TryInterpolatedString(i, a.conf.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("session id interpolation error: %w", err)
+		}
+		if sessionID != "" {
+			sbMsg.SessionID = &sessionID
+		}
+	}
+	if a.conf.MessageID != nil {
+		messageID, err := batch.TryInterpolatedString(i, a.conf.MessageID)
+		if err != nil {
+			return nil, fmt.Errorf("message id interpolation error: %w", err)
+		}
+		if messageID != "" {
+			sbMsg.MessageID = &messageID
+		}
+	}
+	if a.conf.ScheduledTime != nil {
+		scheduledStr, err := batch.TryInterpolatedString(i, a.conf.ScheduledTime)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled enqueue time interpolation error: %w", err)
+		}
+		if scheduledStr != "" {
+			scheduled, err := time.Parse(time.RFC3339Nano, scheduledStr)
+			if err != nil {
+				return nil, fmt.Errorf("parsing scheduled enqueue time: %w", err)
+			}
+			sbMsg.ScheduledEnqueueTime = &scheduled
+		}
+	}
+
+	_ = a.conf.MetaFilter.WalkMut(msg, func(k string, v any) error {
+		if sbMsg.ApplicationProperties == nil {
+			sbMsg.ApplicationProperties = map[string]any{}
+		}
+		sbMsg.ApplicationProperties[k] = v
+		return nil
+	})
+	return sbMsg, nil
+}
+
+func (a *azureServiceBusWriter) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	a.mut.RLock()
+	sender := a.sender
+	a.mut.RUnlock()
+
+	if sender == nil {
+		return service.ErrNotConnected
+	}
+
+	sbBatch, err := sender.NewMessageBatch(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for i := range batch {
+		sbMsg, err := a.toServiceBusMessage(batch, i)
+		if err != nil {
+			return err
+		}
+
+		err = sbBatch.AddMessage(sbMsg, nil)
+		if errors.Is(err, azservicebus.ErrMessageTooLarge) && sbBatch.NumMessages() > 0 {
+			// Send what we have so far and start a new batch.
+			if err = sender.SendMessageBatch(ctx, sbBatch, nil); err != nil {
+				return err
+			}
+			if sbBatch, err = sender.NewMessageBatch(ctx, nil); err != nil {
+				return err
+			}
+			err = sbBatch.AddMessage(sbMsg, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("adding message to batch: %w", err)
+		}
+	}
+	if sbBatch.NumMessages() > 0 {
+		return sender.SendMessageBatch(ctx, sbBatch, nil)
+	}
+	return nil
+}
+
+func (a *azureServiceBusWriter) Close(ctx context.Context) error {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if a.sender == nil {
+		return nil
+	}
+	if err := a.sender.Close(ctx); err != nil {
+		a.log.Errorf("Failed to cleanly close sender: %v", err)
+	}
+	err := a.client.Close(ctx)
+	a.sender = nil
+	a.client = nil
+	return err
+}
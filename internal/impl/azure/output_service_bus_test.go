@@ -0,0 +1,74 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestServiceBusOutputMessages(t *testing.T) {
+	pConf, err := sboSpec().ParseYAML(`
+connection_string: Endpoint=sb://example.servicebus.windows.net/;SharedAccessKeyName=foo;SharedAccessKey=bar
+queue_or_topic: foo
+session_id: ${! this.customer }
+message_id: ${! this.id }
+scheduled_enqueue_time: ${! this.at.or("") }
+metadata:
+  exclude_prefixes: [ skip_ ]
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := sboConfigFromParsed(pConf)
+	require.NoError(t, err)
+	w := newAzureServiceBusWriter(conf, nil)
+
+	msgA := service.NewMessage([]byte(`{"customer":"a","id":"1","at":"2025-01-01T12:00:00Z"}`))
+	msgA.MetaSetMut("region", "eu")
+	msgA.MetaSetMut("skip_me", "true")
+	msgB := service.NewMessage([]byte(`{"customer":"b","id":"2"}`))
+	batch := service.MessageBatch{msgA, msgB}
+
+	sbMsg, err := w.toServiceBusMessage(batch, 0)
+	require.NoError(t, err)
+	assert.Equal(t, `{"customer":"a","id":"1","at":"2025-01-01T12:00:00Z"}`, string(sbMsg.Body))
+	require.NotNil(t, sbMsg.SessionID)
+	assert.Equal(t, "a", *sbMsg.SessionID)
+	require.NotNil(t, sbMsg.MessageID)
+	assert.Equal(t, "1", *sbMsg.MessageID)
+	require.NotNil(t, sbMsg.ScheduledEnqueueTime)
+	assert.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), *sbMsg.ScheduledEnqueueTime)
+	assert.Equal(t, map[string]any{"region": "eu"}, sbMsg.ApplicationProperties)
+
+	sbMsg, err = w.toServiceBusMessage(batch, 1)
+	require.NoError(t, err)
+	require.NotNil(t, sbMsg.SessionID)
+	assert.Equal(t, "b", *sbMsg.SessionID)
+	assert.Nil(t, sbMsg.ScheduledEnqueueTime)
+	assert.Nil(t, sbMsg.ApplicationProperties)
+}
+
+func TestServiceBusOutputRequiresCredentials(t *testing.T) {
+	pConf, err := sboSpec().ParseYAML(`queue_or_topic: foo`, nil)
+	require.NoError(t, err)
+
+	_, err = sboConfigFromParsed(pConf)
+	require.Error(t, err)
+}
@@ -0,0 +1,325 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+
+	errUnsupportedType = errors.New("unsupported type")
+)
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// convertColumnValue converts a value decoded from a JSON document into the Go
+// type the driver scans a column into, which is also the type the driver is
+// guaranteed to accept when appending to the column. Values of types which
+// can't be converted are returned as is, as the driver accepts other types for
+// some columns, such as strings for UUID and IP address columns.
+func convertColumnValue(v any, t reflect.Type) (any, error) {
+	rv, err := convertValue(v, t)
+	if errors.Is(err, errUnsupportedType) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rv.Interface(), nil
+}
+
+func convertValue(v any, t reflect.Type) (reflect.Value, error) {
+	if v != nil && reflect.TypeOf(v) == t {
+		return reflect.ValueOf(v), nil
+	}
+
+	switch t {
+	case timeType:
+		ts, err := toTime(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(ts), nil
+	case decimalType:
+		d, err := toDecimal(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if v == nil {
+			return reflect.Zero(t), nil
+		}
+		ev, err := convertValue(v, t.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(ev)
+		return ptr, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		rv := reflect.New(t).Elem()
+		if rv.OverflowInt(i) {
+			return reflect.Value{}, fmt.Errorf("value %v overflows %v", i, t)
+		}
+		rv.SetInt(i)
+		return rv, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := toUint64(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		rv := reflect.New(t).Elem()
+		if rv.OverflowUint(u) {
+			return reflect.Value{}, fmt.Errorf("value %v overflows %v", u, t)
+		}
+		rv.SetUint(u)
+		return rv, nil
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		rv := reflect.New(t).Elem()
+		rv.SetFloat(f)
+		return rv, nil
+	case reflect.Bool:
+		b, err := toBool(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b).Convert(t), nil
+	case reflect.String:
+		return reflect.ValueOf(toString(v)).Convert(t), nil
+	case reflect.Slice:
+		arr, ok := v.([]any)
+		if !ok {
+			if v == nil {
+				return reflect.MakeSlice(t, 0, 0), nil
+			}
+			return reflect.Value{}, fmt.Errorf("expected array, got %T", v)
+		}
+		rv := reflect.MakeSlice(t, len(arr), len(arr))
+		for i, e := range arr {
+			ev, err := convertValue(e, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("index %v: %w", i, err)
+			}
+			rv.Index(i).Set(ev)
+		}
+		return rv, nil
+	case reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			if v == nil {
+				return reflect.MakeMap(t), nil
+			}
+			return reflect.Value{}, fmt.Errorf("expected object, got %T", v)
+		}
+		rv := reflect.MakeMapWithSize(t, len(obj))
+		for k, e := range obj {
+			kv, err := convertValue(k, t.Key())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %v: %w", k, err)
+			}
+			ev, err := convertValue(e, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %v: %w", k, err)
+			}
+			rv.SetMapIndex(kv, ev)
+		}
+		return rv, nil
+	case reflect.Interface:
+		if v == nil {
+			return reflect.Zero(t), nil
+		}
+		return reflect.ValueOf(v), nil
+	}
+	return reflect.Value{}, errUnsupportedType
+}
+
+func toInt64(v any) (int64, error) {
+	switch t := v.(type) {
+	case int:
+		return int64(t), nil
+	case int64:
+		return t, nil
+	case int32:
+		return int64(t), nil
+	case uint64:
+		if t > math.MaxInt64 {
+			return 0, fmt.Errorf("value %v overflows int64", t)
+		}
+		return int64(t), nil
+	case float64:
+		if t != math.Trunc(t) || t < math.MinInt64 || t >= math.MaxInt64 {
+			return 0, fmt.Errorf("value %v is not an integer", t)
+		}
+		return int64(t), nil
+	case json.Number:
+		return t.Int64()
+	case string:
+		return strconv.ParseInt(t, 10, 64)
+	case bool:
+		if t {
+			return 1, nil
+		}
+		return 0, nil
+	case nil:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("expected number, got %T", v)
+}
+
+func toUint64(v any) (uint64, error) {
+	switch t := v.(type) {
+	case uint64:
+		return t, nil
+	case float64:
+		if t != math.Trunc(t) || t < 0 || t >= math.MaxUint64 {
+			return 0, fmt.Errorf("value %v is not an unsigned integer", t)
+		}
+		return uint64(t), nil
+	case json.Number:
+		return strconv.ParseUint(t.String(), 10, 64)
+	case string:
+		return strconv.ParseUint(t, 10, 64)
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("value %v is not an unsigned integer", i)
+	}
+	return uint64(i), nil
+}
+
+func toFloat64(v any) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case json.Number:
+		return t.Float64()
+	case string:
+		return strconv.ParseFloat(t, 64)
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	return float64(i), nil
+}
+
+func toBool(v any) (bool, error) {
+	switch t := v.(type) {
+	case bool:
+		return t, nil
+	case string:
+		return strconv.ParseBool(t)
+	case nil:
+		return false, nil
+	}
+	f, err := toFloat64(v)
+	if err != nil {
+		return false, err
+	}
+	return f != 0, nil
+}
+
+// toString returns strings as is, and encodes any other value as JSON so that
+// structured values can be written to String columns.
+func toString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	case nil:
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// toTime converts strings in common layouts, and numbers as seconds since the
+// unix epoch, to timestamps.
+func toTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		for _, layout := range timeLayouts {
+			if ts, err := time.Parse(layout, t); err == nil {
+				return ts, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("failed to parse timestamp %q", t)
+	case nil:
+		return time.Time{}, nil
+	}
+	f, err := toFloat64(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected timestamp, got %T", v)
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+}
+
+func toDecimal(v any) (decimal.Decimal, error) {
+	switch t := v.(type) {
+	case string:
+		return decimal.NewFromString(t)
+	case json.Number:
+		return decimal.NewFromString(t.String())
+	case float64:
+		return decimal.NewFromFloat(t), nil
+	case nil:
+		return decimal.Zero, nil
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("expected decimal, got %T", v)
+	}
+	return decimal.NewFromInt(i), nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestConvertColumnValue(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    any
+		target   any
+		expected any
+		errs     bool
+	}{
+		{name: "float to int", value: 5.0, target: int32(0), expected: int32(5)},
+		{name: "fractional float to int", value: 5.5, target: int32(0), errs: true},
+		{name: "overflow", value: 300.0, target: uint8(0), expected: nil, errs: true},
+		{name: "negative to uint", value: int64(-1), target: uint64(0), errs: true},
+		{name: "json number to uint", value: json.Number("18446744073709551615"), target: uint64(0), expected: uint64(18446744073709551615)},
+		{name: "string to float", value: "1.5", target: float32(0), expected: float32(1.5)},
+		{name: "number to bool", value: 1.0, target: false, expected: true},
+		{name: "object to string", value: map[string]any{"a": 1.0}, target: "", expected: `{"a":1}`},
+		{name: "rfc3339 to time", value: "2025-01-02T03:04:05Z", target: time.Time{}, expected: ts},
+		{name: "datetime to time", value: "2025-01-02 03:04:05", target: time.Time{}, expected: ts},
+		{name: "unix to time", value: float64(ts.Unix()), target: time.Time{}, expected: ts},
+		{name: "bad time", value: "nope", target: time.Time{}, errs: true},
+		{name: "string to decimal", value: "1.25", target: decimal.Decimal{}, expected: decimal.RequireFromString("1.25")},
+		{name: "null to nullable", value: nil, target: (*int64)(nil), expected: (*int64)(nil)},
+		{name: "value to nullable", value: 3.0, target: (*int64)(nil), expected: ptr(int64(3))},
+		{name: "array", value: []any{1.0, 2.0}, target: []uint16{}, expected: []uint16{1, 2}},
+		{name: "array of nullable", value: []any{"a", nil}, target: []*string{}, expected: []*string{ptr("a"), nil}},
+		{name: "map", value: map[string]any{"a": 1.0}, target: map[string]int64{}, expected: map[string]int64{"a": 1}},
+		{name: "not an array", value: "a", target: []string{}, errs: true},
+		{name: "unsupported passthrough", value: "127.0.0.1", target: struct{}{}, expected: "127.0.0.1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v, err := convertColumnValue(test.value, reflect.TypeOf(test.target))
+			if test.errs {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, v)
+		})
+	}
+}
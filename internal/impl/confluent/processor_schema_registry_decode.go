@@ -62,6 +62,8 @@ However, it is possible to instead create documents in https://pkg.go.dev/github
 
 This processor decodes protobuf messages to JSON documents, you can read more about JSON mapping of protobuf messages here: https://developers.google.com/protocol-buffers/docs/proto3#json
 
+Messages are decoded against the message definition identified by the message indexes of the wire format, which includes nested message definitions. Well-known types such as ` + "`google.protobuf.Timestamp`" + ` are rendered in their canonical JSON representation, and schema references are resolved from the registry.
+
 == Metadata
 
 This processor also adds the following metadata to each outgoing message:
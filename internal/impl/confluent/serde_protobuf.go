@@ -175,20 +175,31 @@ func (s *schemaRegistryEncoder) getProtobufEncoder(ctx context.Context, schema s
 type cachedMessageTypes struct {
 	singleMsgType protoreflect.MessageDescriptor
 	msgTypeMap    map[string]protoreflect.MessageDescriptor
-	allTypes      *protoregistry.Types
+	// The message indexes of msgTypeMap in the order in which they should be
+	// attempted, top level messages first and nested messages after.
+	msgTypeKeys []string
+	allTypes    *protoregistry.Types
 
 	lastSuccessful string
 	cacheMut       sync.Mutex
 }
 
-func messageDescriptorsToMap(msgs protoreflect.MessageDescriptors, m map[string]protoreflect.MessageDescriptor) {
+func (c *cachedMessageTypes) addMessageDescriptors(msgs protoreflect.MessageDescriptors) {
+	var nested []protoreflect.MessageDescriptors
 	for i := 0; i < msgs.Len(); i++ {
 		msg := msgs.Get(i)
-		indexBytes := toMessageIndexBytes(msg)
-		m[string(indexBytes)] = msg
-		// TODO: Currently we ignore nested message types and only test those
-		// at the top level of the file.
-		// messageDescriptorsToMap(msg.Messages(), m)
+		if msg.IsMapEntry() {
+			continue
+		}
+		key := string(toMessageIndexBytes(msg))
+		c.msgTypeMap[key] = msg
+		c.msgTypeKeys = append(c.msgTypeKeys, key)
+		if msg.Messages().Len() > 0 {
+			nested = append(nested, msg.Messages())
+		}
+	}
+	for _, n := range nested {
+		c.addMessageDescriptors(n)
 	}
 }
 
@@ -200,7 +211,7 @@ func newCachedMessageTypes(rootMsgs protoreflect.MessageDescriptors, allTypes *p
 		c.singleMsgType = rootMsgs.Get(0)
 	} else {
 		c.msgTypeMap = map[string]protoreflect.MessageDescriptor{}
-		messageDescriptorsToMap(rootMsgs, c.msgTypeMap)
+		c.addMessageDescriptors(rootMsgs)
 	}
 	return c
 }
@@ -230,8 +241,8 @@ func (c *cachedMessageTypes) TryParseMsg(data []byte) (*dynamicpb.Message, []byt
 	}
 
 	var errs error
-	for k, msgDesc := range c.msgTypeMap {
-		dynMsg, err := c.tryDesc(data, msgDesc)
+	for _, k := range c.msgTypeKeys {
+		dynMsg, err := c.tryDesc(data, c.msgTypeMap[k])
 		if err == nil {
 			c.cacheMut.Lock()
 			c.lastSuccessful = k
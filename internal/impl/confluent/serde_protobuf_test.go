@@ -390,3 +390,110 @@ message bar {
 		})
 	})
 }
+
+func TestProtobufWellKnownTypesAndNestedMessages(t *testing.T) {
+	tCtx, done := context.WithTimeout(context.Background(), time.Second*10)
+	defer done()
+
+	thingsSchema := `
+syntax = "proto3";
+package things;
+
+import "google/protobuf/any.proto";
+import "google/protobuf/duration.proto";
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+
+message foo {
+  google.protobuf.Timestamp created_at = 1;
+  google.protobuf.Duration ttl = 2;
+  google.protobuf.StringValue label = 3;
+  google.protobuf.Any details = 4;
+}
+
+message bar {
+  message baz {
+    int32 nested_count = 1;
+  }
+  string b = 1;
+}
+`
+
+	urlStr := runSchemaRegistryServer(t, func(path string) ([]byte, error) {
+		switch path {
+		case "/subjects/things/versions/latest", "/schemas/ids/1":
+			return mustJBytes(t, map[string]any{
+				"id":         1,
+				"version":    10,
+				"schema":     thingsSchema,
+				"schemaType": "PROTOBUF",
+			}), nil
+		}
+		return nil, nil
+	})
+
+	subj, err := service.NewInterpolatedString("${! @subject }")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		input        string
+		output       string
+		indexesBytes []byte
+	}{
+		{
+			name:         "well-known types",
+			input:        `{"createdAt":"2025-01-02T03:04:05Z","ttl":"3.500s","label":"hello","details":{"@type":"type.googleapis.com/google.protobuf.Timestamp","value":"2025-01-02T03:04:05Z"}}`,
+			output:       `{"createdAt":"2025-01-02T03:04:05Z","ttl":"3.500s","label":"hello","details":{"@type":"type.googleapis.com/google.protobuf.Timestamp","value":"2025-01-02T03:04:05Z"}}`,
+			indexesBytes: []byte{0},
+		},
+		{
+			name:         "nested message",
+			input:        `{"nestedCount":5}`,
+			output:       `{"nestedCount":5}`,
+			indexesBytes: []byte{4, 2, 0}, // Zig-zag encoded [1, 0]
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoder, err := newSchemaRegistryEncoder(urlStr, noopReqSign, nil, subj, true, time.Minute*10, time.Minute, service.MockResources())
+			require.NoError(t, err)
+
+			decoder, err := newSchemaRegistryDecoder(urlStr, noopReqSign, nil, decodingConfig{}, service.MockResources())
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				_ = encoder.Close(tCtx)
+				_ = decoder.Close(tCtx)
+			})
+
+			inMsg := service.NewMessage([]byte(test.input))
+			inMsg.MetaSetMut("subject", "things")
+
+			encodedMsgs, err := encoder.ProcessBatch(tCtx, service.MessageBatch{inMsg})
+			require.NoError(t, err)
+			require.Len(t, encodedMsgs, 1)
+			require.Len(t, encodedMsgs[0], 1)
+
+			encodedMsg := encodedMsgs[0][0]
+			require.NoError(t, encodedMsg.GetError())
+
+			b, err := encodedMsg.AsBytes()
+			require.NoError(t, err)
+			// Skip the magic byte and the schema ID.
+			assert.Equal(t, test.indexesBytes, b[5:5+len(test.indexesBytes)])
+
+			decodedMsgs, err := decoder.Process(tCtx, encodedMsg)
+			require.NoError(t, err)
+			require.Len(t, decodedMsgs, 1)
+
+			decodedMsg := decodedMsgs[0]
+			require.NoError(t, decodedMsg.GetError())
+
+			b, err = decodedMsg.AsBytes()
+			require.NoError(t, err)
+			assert.JSONEq(t, test.output, string(b))
+		})
+	}
+}
@@ -149,14 +149,39 @@ func (c *Client) GetVersionsForSubject(ctx context.Context, subject string, incl
 	return c.Client.SubjectVersions(ctx, subject)
 }
 
-// CreateSchema creates a new schema for the given subject.
-func (c *Client) CreateSchema(ctx context.Context, subject string, schema sr.Schema) (int, error) {
+// CreateSchema creates a new schema for the given subject and returns the ID
+// and version assigned to it by the registry.
+func (c *Client) CreateSchema(ctx context.Context, subject string, schema sr.Schema) (sr.SubjectSchema, error) {
 	ss, err := c.Client.CreateSchema(ctx, subject, schema)
 	if err != nil {
-		return -1, fmt.Errorf("failed to create schema for subject %q: %s", subject, err)
+		return sr.SubjectSchema{}, fmt.Errorf("failed to create schema for subject %q: %s", subject, err)
 	}
 
-	return ss.ID, nil
+	return ss, nil
+}
+
+// CreateSchemaWithIDAndVersion creates a new schema for the given subject with
+// a fixed ID and version. This requires the subject to be in IMPORT mode.
+func (c *Client) CreateSchemaWithIDAndVersion(ctx context.Context, subject string, schema sr.Schema, id, version int) (sr.SubjectSchema, error) {
+	ss, err := c.Client.CreateSchemaWithIDAndVersion(ctx, subject, schema, id, version)
+	if err != nil {
+		return sr.SubjectSchema{}, fmt.Errorf("failed to create schema with ID %d and version %d for subject %q: %s", id, version, subject, err)
+	}
+
+	return ss, nil
+}
+
+// SetSubjectMode sets the mode of the given subject. Setting the mode to IMPORT
+// is forced, which allows it to be applied to subjects which already contain
+// schemas.
+func (c *Client) SetSubjectMode(ctx context.Context, subject string, mode sr.Mode) error {
+	res := c.Client.SetMode(sr.WithParams(ctx, sr.Force), mode, subject)
+	// There will be one and only one element in the response.
+	if res[0].Err != nil {
+		return fmt.Errorf("request failed: %s", res[0].Err)
+	}
+
+	return nil
 }
 
 type refWalkFn func(ctx context.Context, name string, info sr.Schema) error
@@ -0,0 +1,226 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ddFieldSite        = "site"
+	ddFieldAPIKey      = "api_key"
+	ddFieldEndpoint    = "endpoint"
+	ddFieldCompression = "compression"
+	ddFieldTimeout     = "timeout"
+	ddFieldTLS         = "tls"
+	ddFieldTags        = "tags"
+	ddFieldBatching    = "batching"
+)
+
+func clientFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(ddFieldAPIKey).
+			Description("A Datadog API key used for authentication.").
+			Secret(),
+		service.NewStringField(ddFieldSite).
+			Description("The https://docs.datadoghq.com/getting_started/site/[Datadog site^] to send data to.").
+			Default("datadoghq.com").
+			Example("datadoghq.eu").
+			Example("us3.datadoghq.com"),
+		service.NewURLField(ddFieldEndpoint).
+			Description("An optional URL to send data to, which overrides the URL derived from the `" + ddFieldSite + "`. This is useful when sending data via a proxy.").
+			Optional().
+			Advanced(),
+		service.NewStringEnumField(ddFieldCompression, "none", "gzip").
+			Description("The compression algorithm to use for request payloads.").
+			Default("gzip").
+			Advanced(),
+		service.NewDurationField(ddFieldTimeout).
+			Description("The maximum period to wait for a request to complete.").
+			Default("30s").
+			Advanced(),
+		service.NewTLSToggledField(ddFieldTLS),
+	}
+}
+
+func tagsField() *service.ConfigField {
+	return service.NewBloblangField(ddFieldTags).
+		Description("An optional Bloblang mapping executed for each message that returns the tags to attach to it. The mapping can return either an object, where each key and value pair becomes a `key:value` tag, or an array of tag strings.").
+		Example(`root.env = "prod"
+root.team = @team`).
+		Example(`root = [ "env:prod", "region:" + this.region ]`).
+		Optional()
+}
+
+type client struct {
+	url      string
+	apiKey   string
+	gzip     bool
+	http     *http.Client
+	tagsExec *bloblang.Executor
+}
+
+func newClient(conf *service.ParsedConfig, defaultURL func(site string) string) (*client, error) {
+	c := &client{http: &http.Client{}}
+
+	var err error
+	if c.apiKey, err = conf.FieldString(ddFieldAPIKey); err != nil {
+		return nil, err
+	}
+	if conf.Contains(ddFieldEndpoint) {
+		if c.url, err = conf.FieldString(ddFieldEndpoint); err != nil {
+			return nil, err
+		}
+	} else {
+		site, err := conf.FieldString(ddFieldSite)
+		if err != nil {
+			return nil, err
+		}
+		c.url = defaultURL(site)
+	}
+
+	compression, err := conf.FieldString(ddFieldCompression)
+	if err != nil {
+		return nil, err
+	}
+	c.gzip = compression == "gzip"
+
+	if c.http.Timeout, err = conf.FieldDuration(ddFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(ddFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConf
+		c.http.Transport = transport
+	}
+
+	if conf.Contains(ddFieldTags) {
+		if c.tagsExec, err = conf.FieldBloblang(ddFieldTags); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// tags executes the tags mapping against a message of a batch and returns the
+// resulting tags in the form key:value, or nil if no mapping is configured.
+func (c *client) tags(batch service.MessageBatch, i int) ([]string, error) {
+	if c.tagsExec == nil {
+		return nil, nil
+	}
+	msg, err := batch.BloblangQuery(i, c.tagsExec)
+	if err != nil {
+		return nil, fmt.Errorf("tags mapping failed: %w", err)
+	}
+	if msg == nil {
+		return nil, nil
+	}
+	v, err := msg.AsStructured()
+	if err != nil {
+		return nil, fmt.Errorf("tags mapping failed: %w", err)
+	}
+
+	var tags []string
+	switch t := v.(type) {
+	case map[string]any:
+		for k, v := range t {
+			tags = append(tags, k+":"+bloblang.ValueToString(v))
+		}
+		sort.Strings(tags)
+	case []any:
+		for _, e := range t {
+			tags = append(tags, bloblang.ValueToString(e))
+		}
+	case nil:
+	default:
+		return nil, fmt.Errorf("tags mapping returned %T, expected an object or array", v)
+	}
+	return tags, nil
+}
+
+// post sends a JSON payload to the configured URL.
+func (c *client) post(ctx context.Context, payload any) error {
+	var body bytes.Buffer
+	var w io.Writer = &body
+	var gz *gzip.Writer
+	if c.gzip {
+		gz = gzip.NewWriter(&body)
+		w = gz
+	}
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to compress payload: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, &body)
+	if err != nil {
+		return fmt.Errorf("failed to construct HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", c.apiKey)
+	if c.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("HTTP request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (c *client) close() {
+	c.http.CloseIdleConnections()
+}
+
+// requiredString resolves an interpolated string for a message of a batch and
+// returns an error if the result is empty.
+func requiredString(batch service.MessageBatch, i int, name string, s *service.InterpolatedString) (string, error) {
+	v, err := batch.TryInterpolatedString(i, s)
+	if err != nil {
+		return "", fmt.Errorf("%v interpolation error: %w", name, err)
+	}
+	if v == "" {
+		return "", fmt.Errorf("%v resolved to an empty string", name)
+	}
+	return v, nil
+}
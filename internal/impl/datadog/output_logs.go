@@ -0,0 +1,239 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Limits of a single request to the logs intake API.
+	dloMaxRequestLogs  = 1000
+	dloMaxRequestBytes = 5 * 1000 * 1000
+)
+
+const (
+	dloFieldService  = "service"
+	dloFieldSource   = "source"
+	dloFieldHostname = "hostname"
+)
+
+func logsOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.48.0").
+		Categories("Services").
+		Summary("Sends messages as logs to the https://docs.datadoghq.com/api/latest/logs/#send-logs[Datadog logs intake API^].").
+		Description(`
+Messages that are JSON objects are sent as structured logs where each field becomes a log attribute, and should therefore contain a `+"`message`"+` field. All other messages are sent with their contents as the `+"`message`"+` of the log.
+
+Batches are split into as few requests as possible within the limits of the intake API, which at the time of writing are 1000 logs and 5MB (uncompressed) per request. When a request fails only the messages of that request are reattempted.
+`+service.OutputPerformanceDocs(true, true)).
+		Fields(clientFields()...).
+		Fields(
+			service.NewInterpolatedStringField(dloFieldService).
+				Description("The name of the application or service generating the log.").
+				Optional(),
+			service.NewInterpolatedStringField(dloFieldSource).
+				Description("The integration name associated with the log, which is used by Datadog to select log processing pipelines.").
+				Example("nginx").
+				Optional(),
+			service.NewInterpolatedStringField(dloFieldHostname).
+				Description("The name of the host that generated the log.").
+				Example("${! hostname() }").
+				Optional(),
+			tagsField(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(ddFieldBatching),
+		).
+		Example("Structured Logs", "Send structured logs with tags derived from each message.", `
+output:
+  datadog_logs:
+    api_key: ${DD_API_KEY}
+    site: datadoghq.eu
+    service: checkout
+    source: redpanda-connect
+    hostname: ${! hostname() }
+    tags: |
+      root.env = "prod"
+      root.tenant = this.tenant_id
+    batching:
+      count: 500
+      period: 1s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("datadog_logs", logsOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ddFieldBatching); err != nil {
+				return
+			}
+			out, err = newLogsOutput(conf)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type logsOutput struct {
+	client   *client
+	service  *service.InterpolatedString
+	source   *service.InterpolatedString
+	hostname *service.InterpolatedString
+}
+
+func newLogsOutput(conf *service.ParsedConfig) (*logsOutput, error) {
+	c, err := newClient(conf, func(site string) string {
+		return "https://http-intake.logs." + site + "/api/v2/logs"
+	})
+	if err != nil {
+		return nil, err
+	}
+	o := &logsOutput{client: c}
+	for _, f := range []struct {
+		name   string
+		target **service.InterpolatedString
+	}{
+		{dloFieldService, &o.service},
+		{dloFieldSource, &o.source},
+		{dloFieldHostname, &o.hostname},
+	} {
+		if !conf.Contains(f.name) {
+			continue
+		}
+		if *f.target, err = conf.FieldInterpolatedString(f.name); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+func (*logsOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (o *logsOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	logs := make([]json.RawMessage, 0, len(batch))
+	for i, msg := range batch {
+		var entry map[string]any
+		if structured, err := msg.AsStructured(); err == nil {
+			if obj, ok := structured.(map[string]any); ok {
+				entry = maps.Clone(obj)
+			}
+		}
+		if entry == nil {
+			raw, err := msg.AsBytes()
+			if err != nil {
+				return err
+			}
+			entry = map[string]any{"message": string(raw)}
+		}
+
+		for _, f := range []struct {
+			key   string
+			value *service.InterpolatedString
+		}{
+			{"service", o.service},
+			{"ddsource", o.source},
+			{"hostname", o.hostname},
+		} {
+			if f.value == nil {
+				continue
+			}
+			v, err := batch.TryInterpolatedString(i, f.value)
+			if err != nil {
+				return fmt.Errorf("%v interpolation error: %w", f.key, err)
+			}
+			if v != "" {
+				entry[f.key] = v
+			}
+		}
+
+		tags, err := o.client.tags(batch, i)
+		if err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			entry["ddtags"] = strings.Join(tags, ",")
+		}
+
+		entryBytes, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal log: %w", err)
+		}
+		if len(entryBytes)+2 > dloMaxRequestBytes {
+			return fmt.Errorf("log of %v bytes exceeds the maximum request size of %v bytes", len(entryBytes), dloMaxRequestBytes)
+		}
+		logs = append(logs, entryBytes)
+	}
+
+	var batchErr *service.BatchError
+	for _, r := range splitLogRequests(logs) {
+		if err := o.client.post(ctx, logs[r.start:r.end]); err != nil {
+			if batchErr == nil {
+				batchErr = service.NewBatchError(batch, err)
+			}
+			for i := r.start; i < r.end; i++ {
+				batchErr.Failed(i, err)
+			}
+		}
+	}
+	if batchErr != nil {
+		return batchErr
+	}
+	return nil
+}
+
+type logRequestRange struct {
+	start, end int
+}
+
+// splitLogRequests returns the ranges of logs to send within each request such
+// that requests are within the limits of the intake API, where the size of a
+// request is that of a JSON array of the logs.
+func splitLogRequests(logs []json.RawMessage) []logRequestRange {
+	var ranges []logRequestRange
+	start, size := 0, 2
+	for i, l := range logs {
+		if i > start && (i-start >= dloMaxRequestLogs || size+1+len(l) > dloMaxRequestBytes) {
+			ranges = append(ranges, logRequestRange{start: start, end: i})
+			start, size = i, 2
+		}
+		if i > start {
+			size++
+		}
+		size += len(l)
+	}
+	if start < len(logs) {
+		ranges = append(ranges, logRequestRange{start: start, end: len(logs)})
+	}
+	return ranges
+}
+
+func (o *logsOutput) Close(context.Context) error {
+	o.client.close()
+	return nil
+}
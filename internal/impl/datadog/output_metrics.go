@@ -0,0 +1,261 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dmoFieldMetric    = "metric"
+	dmoFieldValue     = "value"
+	dmoFieldType      = "type"
+	dmoFieldTimestamp = "timestamp"
+	dmoFieldInterval  = "interval"
+	dmoFieldUnit      = "unit"
+	dmoFieldHostname  = "hostname"
+)
+
+// The metric types of the series API.
+var metricTypes = map[string]int{
+	"unspecified": 0,
+	"count":       1,
+	"rate":        2,
+	"gauge":       3,
+}
+
+func metricsOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.48.0").
+		Categories("Services").
+		Summary("Sends a metric data point for each message to the https://docs.datadoghq.com/api/latest/metrics/#submit-metrics[Datadog series API^].").
+		Description(`
+Each message of a batch becomes a single point of a series, where the name, value and tags of the series are resolved from the message. All messages of a batch are sent in a single request.
+`+service.OutputPerformanceDocs(true, true)).
+		Fields(clientFields()...).
+		Fields(
+			service.NewInterpolatedStringField(dmoFieldMetric).
+				Description("The name of the metric.").
+				Example("orders.processed").
+				Example(`${! @metric_name }`),
+			service.NewInterpolatedStringField(dmoFieldValue).
+				Description("The value of the data point, which must resolve to a number.").
+				Example(`${! this.latency_ms }`).
+				Default("1"),
+			service.NewStringEnumField(dmoFieldType, "gauge", "count", "rate", "unspecified").
+				Description("The type of the metric.").
+				Default("gauge"),
+			service.NewInterpolatedStringField(dmoFieldTimestamp).
+				Description("An optional unix timestamp in seconds of the data point. When left empty the time at which the message is sent is used.").
+				Example(`${! timestamp_unix() }`).
+				Example(`${! this.created_at.ts_unix() }`).
+				Optional(),
+			service.NewIntField(dmoFieldInterval).
+				Description("The interval in seconds of the metric, which is required by Datadog when the type is `count` or `rate`.").
+				Optional(),
+			service.NewStringField(dmoFieldUnit).
+				Description("An optional unit of the metric.").
+				Example("millisecond").
+				Optional(),
+			service.NewInterpolatedStringField(dmoFieldHostname).
+				Description("The name of the host that produced the metric.").
+				Example("${! hostname() }").
+				Optional(),
+			tagsField(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(ddFieldBatching),
+		).
+		LintRule(`root = if ["count", "rate"].contains(this.type.or("gauge")) && !this.exists("interval") { "an interval is required for metrics of type count or rate" }`).
+		Example("Order Latency", "Send the latency of each order as a gauge tagged by region.", `
+output:
+  datadog_metrics:
+    api_key: ${DD_API_KEY}
+    metric: orders.latency
+    value: ${! this.latency_ms }
+    timestamp: ${! this.completed_at.ts_unix() }
+    unit: millisecond
+    tags: |
+      root.region = this.region
+      root.env = "prod"
+    batching:
+      count: 100
+      period: 5s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("datadog_metrics", metricsOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ddFieldBatching); err != nil {
+				return
+			}
+			out, err = newMetricsOutput(conf)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type metricsOutput struct {
+	client     *client
+	metric     *service.InterpolatedString
+	value      *service.InterpolatedString
+	metricType int
+	timestamp  *service.InterpolatedString
+	interval   int
+	unit       string
+	hostname   *service.InterpolatedString
+
+	nowFn func() time.Time
+}
+
+func newMetricsOutput(conf *service.ParsedConfig) (*metricsOutput, error) {
+	c, err := newClient(conf, func(site string) string {
+		return "https://api." + site + "/api/v2/series"
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o := &metricsOutput{client: c, nowFn: time.Now}
+	if o.metric, err = conf.FieldInterpolatedString(dmoFieldMetric); err != nil {
+		return nil, err
+	}
+	if o.value, err = conf.FieldInterpolatedString(dmoFieldValue); err != nil {
+		return nil, err
+	}
+	typeStr, err := conf.FieldString(dmoFieldType)
+	if err != nil {
+		return nil, err
+	}
+	o.metricType = metricTypes[typeStr]
+	if conf.Contains(dmoFieldTimestamp) {
+		if o.timestamp, err = conf.FieldInterpolatedString(dmoFieldTimestamp); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(dmoFieldInterval) {
+		if o.interval, err = conf.FieldInt(dmoFieldInterval); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(dmoFieldUnit) {
+		if o.unit, err = conf.FieldString(dmoFieldUnit); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(dmoFieldHostname) {
+		if o.hostname, err = conf.FieldInterpolatedString(dmoFieldHostname); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+type seriesPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+type seriesResource struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type series struct {
+	Metric    string           `json:"metric"`
+	Type      int              `json:"type"`
+	Points    []seriesPoint    `json:"points"`
+	Tags      []string         `json:"tags,omitempty"`
+	Unit      string           `json:"unit,omitempty"`
+	Interval  int              `json:"interval,omitempty"`
+	Resources []seriesResource `json:"resources,omitempty"`
+}
+
+func (*metricsOutput) Connect(context.Context) error {
+	return nil
+}
+
+func (o *metricsOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	payload := struct {
+		Series []series `json:"series"`
+	}{Series: make([]series, 0, len(batch))}
+
+	now := o.nowFn().Unix()
+	for i := range batch {
+		s := series{
+			Type:     o.metricType,
+			Unit:     o.unit,
+			Interval: o.interval,
+		}
+
+		var err error
+		if s.Metric, err = requiredString(batch, i, dmoFieldMetric, o.metric); err != nil {
+			return err
+		}
+
+		valueStr, err := requiredString(batch, i, dmoFieldValue, o.value)
+		if err != nil {
+			return err
+		}
+		point := seriesPoint{Timestamp: now}
+		if point.Value, err = strconv.ParseFloat(valueStr, 64); err != nil {
+			return fmt.Errorf("failed to parse %v: %w", dmoFieldValue, err)
+		}
+		if o.timestamp != nil {
+			tsStr, err := batch.TryInterpolatedString(i, o.timestamp)
+			if err != nil {
+				return fmt.Errorf("%v interpolation error: %w", dmoFieldTimestamp, err)
+			}
+			if tsStr != "" {
+				if point.Timestamp, err = strconv.ParseInt(tsStr, 10, 64); err != nil {
+					return fmt.Errorf("failed to parse %v: %w", dmoFieldTimestamp, err)
+				}
+			}
+		}
+		s.Points = []seriesPoint{point}
+
+		if o.hostname != nil {
+			host, err := batch.TryInterpolatedString(i, o.hostname)
+			if err != nil {
+				return fmt.Errorf("%v interpolation error: %w", dmoFieldHostname, err)
+			}
+			if host != "" {
+				s.Resources = []seriesResource{{Name: host, Type: "host"}}
+			}
+		}
+
+		if s.Tags, err = o.client.tags(batch, i); err != nil {
+			return err
+		}
+		payload.Series = append(payload.Series, s)
+	}
+	return o.client.post(ctx, payload)
+}
+
+func (o *metricsOutput) Close(context.Context) error {
+	o.client.close()
+	return nil
+}
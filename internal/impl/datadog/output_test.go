@@ -0,0 +1,325 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeIntake struct {
+	mut      sync.Mutex
+	status   int
+	bodies   []any
+	encoding []string
+}
+
+func (f *fakeIntake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if r.Header.Get("DD-API-KEY") != "fookey" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var body io.Reader = r.Body
+	f.encoding = append(f.encoding, r.Header.Get("Content-Encoding"))
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+
+	var v any
+	if err := json.NewDecoder(body).Decode(&v); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.bodies = append(f.bodies, v)
+
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+		_, _ = w.Write([]byte(`{"errors":["nope"]}`))
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func testIntake(t *testing.T) (*fakeIntake, string) {
+	t.Helper()
+
+	intake := &fakeIntake{}
+	srv := httptest.NewServer(intake)
+	t.Cleanup(srv.Close)
+	return intake, srv.URL
+}
+
+func TestLogsOutput(t *testing.T) {
+	intake, url := testIntake(t)
+
+	conf, err := logsOutputSpec().ParseYAML(`
+api_key: fookey
+endpoint: `+url+`
+service: ${! @service.or("") }
+source: redpanda-connect
+tags: |
+  root.env = "prod"
+  root.tenant = @tenant
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newLogsOutput(conf)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, out.Close(context.Background())) })
+
+	msgA := service.NewMessage([]byte(`{"message":"hello","status":"info"}`))
+	msgA.MetaSetMut("service", "checkout")
+	msgA.MetaSetMut("tenant", "acme")
+	msgB := service.NewMessage([]byte(`plain text`))
+	msgB.MetaSetMut("tenant", "globex")
+
+	require.NoError(t, out.WriteBatch(context.Background(), service.MessageBatch{msgA, msgB}))
+
+	require.Len(t, intake.bodies, 1)
+	assert.Equal(t, []string{"gzip"}, intake.encoding)
+	assert.Equal(t, []any{
+		map[string]any{
+			"message":  "hello",
+			"status":   "info",
+			"service":  "checkout",
+			"ddsource": "redpanda-connect",
+			"ddtags":   "env:prod,tenant:acme",
+		},
+		map[string]any{
+			"message":  "plain text",
+			"ddsource": "redpanda-connect",
+			"ddtags":   "env:prod,tenant:globex",
+		},
+	}, intake.bodies[0])
+
+	// The original message must not be modified.
+	v, err := msgA.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"message": "hello", "status": "info"}, v)
+}
+
+func TestLogsOutputErrorStatus(t *testing.T) {
+	intake, url := testIntake(t)
+	intake.status = http.StatusBadRequest
+
+	conf, err := logsOutputSpec().ParseYAML(`
+api_key: fookey
+endpoint: `+url+`
+compression: none
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newLogsOutput(conf)
+	require.NoError(t, err)
+
+	err = out.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte(`hello`))})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400")
+	assert.Equal(t, []string{""}, intake.encoding)
+}
+
+func TestLogsOutputSplitRequests(t *testing.T) {
+	intake, url := testIntake(t)
+
+	conf, err := logsOutputSpec().ParseYAML(`
+api_key: fookey
+endpoint: `+url+`
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newLogsOutput(conf)
+	require.NoError(t, err)
+
+	batch := make(service.MessageBatch, 1500)
+	for i := range batch {
+		batch[i] = service.NewMessage([]byte(`hello`))
+	}
+	require.NoError(t, out.WriteBatch(context.Background(), batch))
+
+	require.Len(t, intake.bodies, 2)
+	assert.Len(t, intake.bodies[0], 1000)
+	assert.Len(t, intake.bodies[1], 500)
+
+	// Only the messages of failed requests are reattempted.
+	intake.status = http.StatusRequestEntityTooLarge
+	err = out.WriteBatch(context.Background(), batch)
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1500, batchErr.IndexedErrors())
+}
+
+func TestSplitLogRequests(t *testing.T) {
+	logsOfSize := func(n, size int) []json.RawMessage {
+		logs := make([]json.RawMessage, n)
+		for i := range logs {
+			logs[i] = make(json.RawMessage, size)
+		}
+		return logs
+	}
+
+	tests := []struct {
+		name   string
+		logs   []json.RawMessage
+		ranges []logRequestRange
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "within limits",
+			logs:   logsOfSize(3, 10),
+			ranges: []logRequestRange{{0, 3}},
+		},
+		{
+			name:   "count limit",
+			logs:   logsOfSize(2001, 10),
+			ranges: []logRequestRange{{0, 1000}, {1000, 2000}, {2000, 2001}},
+		},
+		{
+			name:   "size limit",
+			logs:   logsOfSize(5, 2*1000*1000),
+			ranges: []logRequestRange{{0, 2}, {2, 4}, {4, 5}},
+		},
+		{
+			// The first two logs with brackets and a separating comma are
+			// exactly at the limit.
+			name: "at size limit",
+			logs: []json.RawMessage{
+				make(json.RawMessage, dloMaxRequestBytes-13),
+				make(json.RawMessage, 10),
+				make(json.RawMessage, 1),
+			},
+			ranges: []logRequestRange{{0, 2}, {2, 3}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.ranges, splitLogRequests(test.logs))
+		})
+	}
+}
+
+func TestMetricsOutput(t *testing.T) {
+	intake, url := testIntake(t)
+
+	conf, err := metricsOutputSpec().ParseYAML(`
+api_key: fookey
+endpoint: `+url+`
+metric: orders.${! this.kind }
+value: ${! this.latency }
+type: count
+interval: 10
+unit: millisecond
+timestamp: ${! this.ts.or("") }
+hostname: ${! @host.or("") }
+tags: |
+  root = [ "region:" + this.region ]
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newMetricsOutput(conf)
+	require.NoError(t, err)
+	out.nowFn = func() time.Time { return time.Unix(1700000000, 0) }
+	t.Cleanup(func() { require.NoError(t, out.Close(context.Background())) })
+
+	msgA := service.NewMessage([]byte(`{"kind":"created","latency":12.5,"region":"eu","ts":1600000000}`))
+	msgA.MetaSetMut("host", "alpha")
+	msgB := service.NewMessage([]byte(`{"kind":"shipped","latency":3,"region":"us"}`))
+
+	require.NoError(t, out.WriteBatch(context.Background(), service.MessageBatch{msgA, msgB}))
+
+	require.Len(t, intake.bodies, 1)
+	assert.Equal(t, map[string]any{
+		"series": []any{
+			map[string]any{
+				"metric":    "orders.created",
+				"type":      float64(1),
+				"points":    []any{map[string]any{"timestamp": float64(1600000000), "value": 12.5}},
+				"tags":      []any{"region:eu"},
+				"unit":      "millisecond",
+				"interval":  float64(10),
+				"resources": []any{map[string]any{"name": "alpha", "type": "host"}},
+			},
+			map[string]any{
+				"metric":   "orders.shipped",
+				"type":     float64(1),
+				"points":   []any{map[string]any{"timestamp": float64(1700000000), "value": float64(3)}},
+				"tags":     []any{"region:us"},
+				"unit":     "millisecond",
+				"interval": float64(10),
+			},
+		},
+	}, intake.bodies[0])
+}
+
+func TestMetricsOutputBadValue(t *testing.T) {
+	_, url := testIntake(t)
+
+	conf, err := metricsOutputSpec().ParseYAML(`
+api_key: fookey
+endpoint: `+url+`
+metric: foo
+value: ${! content() }
+`, nil)
+	require.NoError(t, err)
+
+	out, err := newMetricsOutput(conf)
+	require.NoError(t, err)
+
+	err = out.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte(`not a number`))})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse value")
+}
+
+func TestMetricsOutputLintInterval(t *testing.T) {
+	err := service.NewStreamBuilder().AddOutputYAML(`
+datadog_metrics:
+  api_key: foo
+  metric: foo
+  type: rate
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "an interval is required")
+
+	require.NoError(t, service.NewStreamBuilder().AddOutputYAML(`
+datadog_metrics:
+  api_key: foo
+  metric: foo
+  type: rate
+  interval: 10
+`))
+}
@@ -0,0 +1,326 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const logDir = "_delta_log/"
+
+var (
+	commitKeyRegexp     = regexp.MustCompile(`^_delta_log/(\d{20})\.json$`)
+	checkpointKeyRegexp = regexp.MustCompile(`^_delta_log/(\d{20})\.checkpoint(\.\d{10}\.\d{10})?\.parquet$`)
+)
+
+func commitKey(version int64) string {
+	return fmt.Sprintf("%v%020d.json", logDir, version)
+}
+
+// action is a single action of a commit to the transaction log, of which
+// exactly one field is set.
+type action struct {
+	Protocol   *protocolAction   `json:"protocol,omitempty" parquet:"protocol,optional"`
+	MetaData   *metaDataAction   `json:"metaData,omitempty" parquet:"metaData,optional"`
+	Add        *addAction        `json:"add,omitempty" parquet:"-"`
+	CommitInfo *commitInfoAction `json:"commitInfo,omitempty" parquet:"-"`
+}
+
+type protocolAction struct {
+	MinReaderVersion int      `json:"minReaderVersion" parquet:"minReaderVersion"`
+	MinWriterVersion int      `json:"minWriterVersion" parquet:"minWriterVersion"`
+	ReaderFeatures   []string `json:"readerFeatures,omitempty" parquet:"readerFeatures,optional,list"`
+	WriterFeatures   []string `json:"writerFeatures,omitempty" parquet:"writerFeatures,optional,list"`
+}
+
+type formatSpec struct {
+	Provider string            `json:"provider" parquet:"provider"`
+	Options  map[string]string `json:"options" parquet:"options,optional"`
+}
+
+type metaDataAction struct {
+	ID               string            `json:"id" parquet:"id"`
+	Name             *string           `json:"name,omitempty" parquet:"name,optional"`
+	Description      *string           `json:"description,omitempty" parquet:"description,optional"`
+	Format           formatSpec        `json:"format" parquet:"format"`
+	SchemaString     string            `json:"schemaString" parquet:"schemaString"`
+	PartitionColumns []string          `json:"partitionColumns" parquet:"partitionColumns,list"`
+	Configuration    map[string]string `json:"configuration" parquet:"configuration,optional"`
+	CreatedTime      *int64            `json:"createdTime,omitempty" parquet:"createdTime,optional"`
+}
+
+type addAction struct {
+	Path             string             `json:"path"`
+	PartitionValues  map[string]*string `json:"partitionValues"`
+	Size             int64              `json:"size"`
+	ModificationTime int64              `json:"modificationTime"`
+	DataChange       bool               `json:"dataChange"`
+	Stats            string             `json:"stats,omitempty"`
+}
+
+type commitInfoAction struct {
+	Timestamp           int64          `json:"timestamp"`
+	Operation           string         `json:"operation"`
+	OperationParameters map[string]any `json:"operationParameters"`
+	IsBlindAppend       bool           `json:"isBlindAppend"`
+	EngineInfo          string         `json:"engineInfo"`
+}
+
+func encodeActions(actions []action) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, a := range actions {
+		b, err := json.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeActions(data []byte) ([]action, error) {
+	var actions []action
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var a action
+		if err := json.Unmarshal(line, &a); err != nil {
+			return nil, err
+		}
+		actions = append(actions, a)
+	}
+	return actions, scanner.Err()
+}
+
+//------------------------------------------------------------------------------
+
+// snapshot is the state of a table at a version that is relevant for appending
+// data files.
+type snapshot struct {
+	version  int64
+	protocol *protocolAction
+	metaData *metaDataAction
+}
+
+// apply updates the snapshot with the actions of the next commit, and returns
+// true if the commit changed the protocol or metadata of the table.
+func (s *snapshot) apply(actions []action) (changed bool) {
+	s.version++
+	for _, a := range actions {
+		if a.Protocol != nil {
+			s.protocol, changed = a.Protocol, true
+		}
+		if a.MetaData != nil {
+			s.metaData, changed = a.MetaData, true
+		}
+	}
+	return
+}
+
+type lastCheckpoint struct {
+	Version int64 `json:"version"`
+}
+
+// loadSnapshot reads the latest snapshot of a table from its transaction log,
+// and returns a snapshot with a version of -1 when the table doesn't exist.
+func loadSnapshot(ctx context.Context, store objectStore) (*snapshot, error) {
+	checkpointVersion := int64(-1)
+	if b, err := store.get(ctx, logDir+"_last_checkpoint"); err == nil {
+		var lc lastCheckpoint
+		if err := json.Unmarshal(b, &lc); err != nil {
+			return nil, fmt.Errorf("failed to parse last checkpoint: %w", err)
+		}
+		checkpointVersion = lc.Version
+	} else if !errors.Is(err, errObjectNotFound) {
+		return nil, fmt.Errorf("failed to read last checkpoint: %w", err)
+	}
+
+	startAfter := ""
+	if checkpointVersion >= 0 {
+		startAfter = fmt.Sprintf("%v%020d", logDir, checkpointVersion)
+	}
+	keys, err := store.list(ctx, logDir, startAfter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transaction log: %w", err)
+	}
+
+	var versions []int64
+	var checkpointKeys []string
+	for _, key := range keys {
+		if m := commitKeyRegexp.FindStringSubmatch(key); m != nil {
+			v, _ := strconv.ParseInt(m[1], 10, 64)
+			versions = append(versions, v)
+		}
+	}
+	slices.Sort(versions)
+	for i := 1; i < len(versions); i++ {
+		if versions[i] != versions[i-1]+1 {
+			return nil, fmt.Errorf("transaction log is missing version %v", versions[i-1]+1)
+		}
+	}
+
+	s := &snapshot{version: checkpointVersion}
+	if len(versions) > 0 {
+		if checkpointVersion >= 0 && versions[0] > checkpointVersion+1 {
+			return nil, fmt.Errorf("transaction log is missing version %v", checkpointVersion+1)
+		}
+		if checkpointVersion < 0 && versions[0] != 0 {
+			return nil, fmt.Errorf("transaction log is missing version 0")
+		}
+		s.version = versions[len(versions)-1]
+	}
+	if s.version < 0 {
+		return s, nil
+	}
+
+	// The latest protocol and metadata are found by reading commits from the
+	// newest, falling back to the checkpoint when older commits are needed.
+	for i := len(versions) - 1; i >= 0 && (s.protocol == nil || s.metaData == nil); i-- {
+		b, err := store.get(ctx, commitKey(versions[i]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read version %v: %w", versions[i], err)
+		}
+		actions, err := decodeActions(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version %v: %w", versions[i], err)
+		}
+		for _, a := range actions {
+			if a.Protocol != nil && s.protocol == nil {
+				s.protocol = a.Protocol
+			}
+			if a.MetaData != nil && s.metaData == nil {
+				s.metaData = a.MetaData
+			}
+		}
+	}
+
+	if (s.protocol == nil || s.metaData == nil) && checkpointVersion >= 0 {
+		if checkpointKeys, err = listCheckpointParts(ctx, store, checkpointVersion); err != nil {
+			return nil, err
+		}
+		for _, key := range checkpointKeys {
+			if err := readCheckpoint(ctx, store, key, s); err != nil {
+				return nil, fmt.Errorf("failed to read checkpoint %v: %w", key, err)
+			}
+		}
+	}
+
+	if s.protocol == nil || s.metaData == nil {
+		return nil, fmt.Errorf("transaction log at version %v has no protocol or metadata", s.version)
+	}
+	return s, nil
+}
+
+func listCheckpointParts(ctx context.Context, store objectStore, version int64) ([]string, error) {
+	prefix := fmt.Sprintf("%v%020d.checkpoint", logDir, version)
+	keys, err := store.list(ctx, prefix, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoint: %w", err)
+	}
+
+	var parts []string
+	for _, key := range keys {
+		if checkpointKeyRegexp.MatchString(key) {
+			parts = append(parts, key)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("checkpoint of version %v not found", version)
+	}
+	return parts, nil
+}
+
+func readCheckpoint(ctx context.Context, store objectStore, key string, s *snapshot) error {
+	b, err := store.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	rows, err := parquet.Read[action](bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return err
+	}
+	for _, a := range rows {
+		if a.Protocol != nil && s.protocol == nil {
+			s.protocol = a.Protocol
+		}
+		if a.MetaData != nil && s.metaData == nil {
+			s.metaData = a.MetaData
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// errMetadataChanged is returned when a commit fails because a concurrent
+// commit changed the protocol or metadata of the table, in which case the data
+// files of the commit must be rewritten.
+var errMetadataChanged = errors.New("the metadata of the table was changed by a concurrent commit")
+
+// commit writes actions to the transaction log as the version after the
+// snapshot. When another writer commits the same version first the snapshot is
+// brought up to date and the commit is retried at the next version, as blind
+// appends only conflict with changes to the protocol or metadata.
+func commit(ctx context.Context, store objectStore, s *snapshot, actions []action, maxAttempts int) error {
+	data, err := encodeActions(actions)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		err := store.putIfAbsent(ctx, commitKey(s.version+1), data)
+		if err == nil {
+			s.apply(actions)
+			return nil
+		}
+		if !errors.Is(err, errObjectExists) {
+			return fmt.Errorf("failed to write version %v: %w", s.version+1, err)
+		}
+
+		// Catch up with the commits of other writers.
+		for {
+			b, err := store.get(ctx, commitKey(s.version+1))
+			if errors.Is(err, errObjectNotFound) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read version %v: %w", s.version+1, err)
+			}
+			concurrent, err := decodeActions(b)
+			if err != nil {
+				return fmt.Errorf("failed to parse version %v: %w", s.version+1, err)
+			}
+			if s.apply(concurrent) {
+				return errMetadataChanged
+			}
+		}
+	}
+	return fmt.Errorf("failed to commit after %v attempts due to concurrent commits", maxAttempts)
+}
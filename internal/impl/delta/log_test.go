@@ -0,0 +1,81 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSnapshotFromCheckpoint(t *testing.T) {
+	store := &fileStore{root: t.TempDir()}
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	require.NoError(t, parquet.Write(&buf, []action{
+		{Protocol: &protocolAction{MinReaderVersion: 1, MinWriterVersion: 2}},
+		{MetaData: &metaDataAction{
+			ID:               "foo",
+			Format:           formatSpec{Provider: "parquet"},
+			SchemaString:     `{"type":"struct","fields":[{"name":"a","type":"long","nullable":true,"metadata":{}}]}`,
+			PartitionColumns: []string{},
+		}},
+	}))
+	require.NoError(t, store.put(ctx, "_delta_log/00000000000000000010.checkpoint.parquet", buf.Bytes()))
+	require.NoError(t, store.put(ctx, "_delta_log/_last_checkpoint", []byte(`{"version":10,"size":2}`)))
+
+	// Commits before the checkpoint may have been removed.
+	require.NoError(t, store.put(ctx, commitKey(11), []byte(`{"commitInfo":{"timestamp":1}}`+"\n")))
+	require.NoError(t, store.put(ctx, commitKey(12), []byte(`{"add":{"path":"a.parquet","partitionValues":{},"size":1,"modificationTime":1,"dataChange":true}}`+"\n")))
+
+	snap, err := loadSnapshot(ctx, store)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), snap.version)
+	assert.Equal(t, 2, snap.protocol.MinWriterVersion)
+	assert.Equal(t, "foo", snap.metaData.ID)
+	assert.Contains(t, snap.metaData.SchemaString, `"name":"a"`)
+}
+
+func TestLoadSnapshotMissingVersion(t *testing.T) {
+	store := &fileStore{root: t.TempDir()}
+	ctx := context.Background()
+
+	require.NoError(t, store.put(ctx, commitKey(0), []byte(`{"protocol":{"minReaderVersion":1,"minWriterVersion":2}}`+"\n")))
+	require.NoError(t, store.put(ctx, commitKey(2), []byte(`{"commitInfo":{"timestamp":1}}`+"\n")))
+
+	_, err := loadSnapshot(ctx, store)
+	require.ErrorContains(t, err, "missing version 1")
+}
+
+func TestFileStorePutIfAbsent(t *testing.T) {
+	store := &fileStore{root: t.TempDir()}
+	ctx := context.Background()
+
+	require.NoError(t, store.putIfAbsent(ctx, "_delta_log/a.json", []byte("foo")))
+	require.ErrorIs(t, store.putIfAbsent(ctx, "_delta_log/a.json", []byte("bar")), errObjectExists)
+
+	b, err := store.get(ctx, "_delta_log/a.json")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(b))
+
+	keys, err := store.list(ctx, "_delta_log/", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"_delta_log/a.json"}, keys)
+}
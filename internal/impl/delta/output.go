@@ -0,0 +1,589 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	sess "github.com/redpanda-data/connect/v4/internal/impl/aws"
+	"github.com/redpanda-data/connect/v4/internal/impl/aws/config"
+)
+
+const (
+	dloFieldPath                    = "path"
+	dloFieldAWS                     = "aws"
+	dloFieldAWSForcePathStyleURLs   = "force_path_style_urls"
+	dloFieldAzure                   = "azure"
+	dloFieldAzureStorageAccount     = "storage_account"
+	dloFieldAzureStorageAccessKey   = "storage_access_key"
+	dloFieldAzureConnectionString   = "storage_connection_string"
+	dloFieldCreateTable             = "create_table"
+	dloFieldCreateTableSchema       = "schema"
+	dloFieldCreateTableColumnName   = "name"
+	dloFieldCreateTableColumnType   = "type"
+	dloFieldCreateTableColumnNull   = "nullable"
+	dloFieldCreateTablePartitionCol = "partition_columns"
+	dloFieldCompression             = "compression"
+	dloFieldMaxCommitAttempts       = "max_commit_attempts"
+	dloFieldBatching                = "batching"
+)
+
+func deltaLakeOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.48.0").
+		Summary("Appends messages to a https://delta.io/[Delta Lake^] table stored in AWS S3, GCP Cloud Storage, Azure Blob Storage or a local filesystem.").
+		Description(`
+Each batch of messages is written as Parquet data files, one for each partition of the table, which are then appended to the table with a single commit to its transaction log. Batches are only acknowledged once their commit succeeds, and the batching policy therefore determines both the size of data files and the rate of commits.
+
+Messages must be JSON objects, and each field of an object is written to the column of the same name. Fields which aren't columns of the table are ignored, and values are converted to the types of their columns where possible. Messages which can't be converted are rejected individually.
+
+Tables may only contain columns of primitive types, and features of the Delta protocol which writers must support, such as column mapping, check constraints or generated columns, aren't supported. If the table doesn't exist then it's created with the schema of the `+"`create_table`"+` field.
+
+== Commit conflicts
+
+Commits are written with conditional writes, which fail when another writer has already committed the same version of the table. In that case the commit is retried at the next version, as appends only conflict with commits that change the schema or protocol of the table. Batches which conflict with such commits are rejected and written again with the latest schema. Object stores must therefore support conditional writes, which is the case for AWS S3 and Cloud Storage, as well as Azure Blob Storage.
+
+Data files of batches which fail to commit aren't referenced by the table, and can be removed with the `+"`VACUUM`"+` command of engines such as Spark.
+
+== Checkpoints
+
+This output doesn't write checkpoints of the transaction log, which should be written periodically by a maintenance job in order to keep reads of the table fast, for example with the `+"`OPTIMIZE`"+` command of Spark.
+`).
+		Fields(
+			service.NewStringField(dloFieldPath).
+				Description("The URL of the location of the table, with one of the schemes `s3`, `s3a`, `gs`, `az`, `abfs`, `abfss` or `file`.").
+				Example("s3://my-bucket/tables/events").
+				Example("gs://my-bucket/tables/events").
+				Example("abfss://my-container@myaccount.dfs.core.windows.net/tables/events").
+				Example("file:///var/lib/tables/events"),
+			service.NewObjectField(dloFieldAWS,
+				append(config.SessionFields(),
+					service.NewBoolField(dloFieldAWSForcePathStyleURLs).
+						Description("Forces the client API to use path style URLs, which is often required when connecting to custom endpoints.").
+						Default(false))...,
+			).
+				Description("Options for tables stored in AWS S3.").
+				Advanced(),
+			service.NewObjectField(dloFieldAzure,
+				service.NewStringField(dloFieldAzureStorageAccount).
+					Description("The storage account of tables with an `az` location. The account of `abfs` and `abfss` locations is taken from their host.").
+					Default(""),
+				service.NewStringField(dloFieldAzureStorageAccessKey).
+					Description("The access key of the storage account. When neither an access key or connection string is set the default Azure credentials are used.").
+					Default("").
+					Secret(),
+				service.NewStringField(dloFieldAzureConnectionString).
+					Description("A storage account connection string, which takes precedence over the storage account and access key.").
+					Default("").
+					Secret(),
+			).
+				Description("Options for tables stored in Azure Blob Storage.").
+				Advanced(),
+			service.NewObjectField(dloFieldCreateTable,
+				service.NewObjectListField(dloFieldCreateTableSchema,
+					service.NewStringField(dloFieldCreateTableColumnName).
+						Description("The name of the column."),
+					service.NewStringEnumField(dloFieldCreateTableColumnType, supportedTypes...).
+						Description("The type of the column."),
+					service.NewBoolField(dloFieldCreateTableColumnNull).
+						Description("Whether the column can contain null values.").
+						Default(true),
+				).
+					Description("The columns of the table.").
+					Default([]any{}),
+				service.NewStringListField(dloFieldCreateTablePartitionCol).
+					Description("The columns to partition the table by.").
+					Default([]any{}),
+			).
+				Description("The schema of the table, which is created if it doesn't exist. When no columns are specified the table must already exist."),
+			service.NewStringEnumField(dloFieldCompression, "uncompressed", "snappy", "gzip", "zstd").
+				Description("The compression codec of data files.").
+				Default("snappy").
+				Advanced(),
+			service.NewIntField(dloFieldMaxCommitAttempts).
+				Description("The maximum number of attempts to commit a batch when other writers commit to the table concurrently.").
+				Default(10).
+				Advanced(),
+			service.NewOutputMaxInFlightField().Default(4),
+			service.NewBatchPolicyField(dloFieldBatching),
+		).
+		Example("Events Table in S3", "Appends events to a table partitioned by date, which is created if it doesn't exist.", `
+output:
+  delta_lake:
+    path: s3://my-bucket/tables/events
+    create_table:
+      schema:
+        - name: id
+          type: string
+          nullable: false
+        - name: user_id
+          type: long
+        - name: payload
+          type: string
+        - name: created_at
+          type: timestamp
+        - name: date
+          type: date
+      partition_columns: [ date ]
+    batching:
+      count: 10000
+      period: 30s
+      processors:
+        - mapping: |
+            root = this
+            root.date = this.created_at.ts_format("2006-01-02")
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("delta_lake", deltaLakeOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if batchPolicy, err = conf.FieldBatchPolicy(dloFieldBatching); err != nil {
+				return
+			}
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			out, err = newDeltaLakeOutputFromConfig(conf, mgr)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type deltaLakeOutput struct {
+	path              string
+	awsConf           *service.ParsedConfig
+	storeConf         storeConfig
+	createColumns     []column
+	createPartitions  []string
+	compression       compress.Codec
+	fileSuffix        string
+	maxCommitAttempts int
+
+	mut       sync.Mutex
+	store     objectStore
+	snap      *snapshot
+	columns   []column
+	pqSchema  *parquet.Schema
+	commitMut sync.Mutex
+
+	log *service.Logger
+}
+
+func newDeltaLakeOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*deltaLakeOutput, error) {
+	o := &deltaLakeOutput{
+		awsConf: conf.Namespace(dloFieldAWS),
+		log:     mgr.Logger(),
+	}
+
+	var err error
+	if o.path, err = conf.FieldString(dloFieldPath); err != nil {
+		return nil, err
+	}
+	if o.storeConf.awsPathStyle, err = conf.FieldBool(dloFieldAWS, dloFieldAWSForcePathStyleURLs); err != nil {
+		return nil, err
+	}
+	if o.storeConf.azureAccount, err = conf.FieldString(dloFieldAzure, dloFieldAzureStorageAccount); err != nil {
+		return nil, err
+	}
+	if o.storeConf.azureKey, err = conf.FieldString(dloFieldAzure, dloFieldAzureStorageAccessKey); err != nil {
+		return nil, err
+	}
+	if o.storeConf.azureConnStr, err = conf.FieldString(dloFieldAzure, dloFieldAzureConnectionString); err != nil {
+		return nil, err
+	}
+
+	columnConfs, err := conf.FieldObjectList(dloFieldCreateTable, dloFieldCreateTableSchema)
+	if err != nil {
+		return nil, err
+	}
+	for _, cc := range columnConfs {
+		var c column
+		if c.Name, err = cc.FieldString(dloFieldCreateTableColumnName); err != nil {
+			return nil, err
+		}
+		if c.Type, err = cc.FieldString(dloFieldCreateTableColumnType); err != nil {
+			return nil, err
+		}
+		if c.Nullable, err = cc.FieldBool(dloFieldCreateTableColumnNull); err != nil {
+			return nil, err
+		}
+		o.createColumns = append(o.createColumns, c)
+	}
+	if o.createPartitions, err = conf.FieldStringList(dloFieldCreateTable, dloFieldCreateTablePartitionCol); err != nil {
+		return nil, err
+	}
+	for _, p := range o.createPartitions {
+		found := false
+		for _, c := range o.createColumns {
+			found = found || c.Name == p
+		}
+		if !found {
+			return nil, fmt.Errorf("partition column %v is not a column of the schema", p)
+		}
+	}
+
+	compression, err := conf.FieldString(dloFieldCompression)
+	if err != nil {
+		return nil, err
+	}
+	switch compression {
+	case "uncompressed":
+		o.compression, o.fileSuffix = &parquet.Uncompressed, ".parquet"
+	case "snappy":
+		o.compression, o.fileSuffix = &parquet.Snappy, ".snappy.parquet"
+	case "gzip":
+		o.compression, o.fileSuffix = &parquet.Gzip, ".gz.parquet"
+	case "zstd":
+		o.compression, o.fileSuffix = &parquet.Zstd, ".zstd.parquet"
+	}
+
+	if o.maxCommitAttempts, err = conf.FieldInt(dloFieldMaxCommitAttempts); err != nil {
+		return nil, err
+	}
+	if o.maxCommitAttempts < 1 {
+		return nil, errors.New("max_commit_attempts must be at least 1")
+	}
+	return o, nil
+}
+
+func (o *deltaLakeOutput) Connect(ctx context.Context) error {
+	o.mut.Lock()
+	defer o.mut.Unlock()
+
+	if o.store != nil {
+		return nil
+	}
+
+	storeConf := o.storeConf
+	if u, err := url.Parse(o.path); err == nil && (u.Scheme == "s3" || u.Scheme == "s3a") {
+		var err error
+		if storeConf.awsConf, err = sess.GetSession(ctx, o.awsConf); err != nil {
+			return err
+		}
+	}
+	store, err := newObjectStore(ctx, o.path, storeConf)
+	if err != nil {
+		return err
+	}
+	return o.connectStore(ctx, store)
+}
+
+func (o *deltaLakeOutput) connectStore(ctx context.Context, store objectStore) error {
+	snap, err := loadSnapshot(ctx, store)
+	if err != nil {
+		return err
+	}
+	if snap.version < 0 {
+		if len(o.createColumns) == 0 {
+			return fmt.Errorf("table %v does not exist and no schema is configured to create it with", o.path)
+		}
+		if err := o.createTable(ctx, store, snap); err != nil {
+			return err
+		}
+	}
+	if err := o.setSnapshot(snap); err != nil {
+		return err
+	}
+	o.store = store
+	return nil
+}
+
+func (o *deltaLakeOutput) createTable(ctx context.Context, store objectStore, snap *snapshot) error {
+	schemaString, err := formatSchema(o.createColumns)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	actions := []action{
+		{CommitInfo: &commitInfoAction{
+			Timestamp:           now,
+			Operation:           "CREATE TABLE",
+			OperationParameters: map[string]any{},
+			IsBlindAppend:       true,
+			EngineInfo:          engineInfo,
+		}},
+		{Protocol: &protocolAction{MinReaderVersion: 1, MinWriterVersion: 2}},
+		{MetaData: &metaDataAction{
+			ID:               uuid.NewString(),
+			Format:           formatSpec{Provider: "parquet", Options: map[string]string{}},
+			SchemaString:     schemaString,
+			PartitionColumns: append([]string{}, o.createPartitions...),
+			Configuration:    map[string]string{},
+			CreatedTime:      &now,
+		}},
+	}
+
+	err = commit(ctx, store, snap, actions, 1)
+	if errors.Is(err, errMetadataChanged) {
+		// Another writer created the table first.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	o.log.Infof("Created table %v", o.path)
+	return nil
+}
+
+const engineInfo = "Redpanda Connect"
+
+// supportedWriterFeatures are the features of the protocol that are either
+// supported or irrelevant to appends of data files.
+var supportedWriterFeatures = map[string]bool{
+	"appendOnly":          true,
+	"invariants":          true,
+	"changeDataFeed":      true,
+	"timestampNtz":        true,
+	"domainMetadata":      true,
+	"vacuumProtocolCheck": true,
+}
+
+func checkProtocol(p *protocolAction, meta *metaDataAction) error {
+	switch {
+	case p.MinWriterVersion == 7:
+		for _, f := range p.WriterFeatures {
+			if !supportedWriterFeatures[f] {
+				return fmt.Errorf("table requires writer feature %v, which is not supported", f)
+			}
+		}
+	case p.MinWriterVersion > 4:
+		return fmt.Errorf("table requires writer version %v, which is not supported", p.MinWriterVersion)
+	}
+	for k := range meta.Configuration {
+		if strings.HasPrefix(k, "delta.constraints.") {
+			return fmt.Errorf("table has check constraint %v, which is not supported", k)
+		}
+	}
+	if mode := meta.Configuration["delta.columnMapping.mode"]; mode != "" && mode != "none" {
+		return fmt.Errorf("table has column mapping mode %v, which is not supported", mode)
+	}
+	return nil
+}
+
+// setSnapshot validates that data files can be appended to the table at a
+// snapshot, and updates the schema of data files.
+func (o *deltaLakeOutput) setSnapshot(snap *snapshot) error {
+	if err := checkProtocol(snap.protocol, snap.metaData); err != nil {
+		return err
+	}
+	if snap.metaData.Format.Provider != "parquet" {
+		return fmt.Errorf("table has data file format %v, which is not supported", snap.metaData.Format.Provider)
+	}
+	columns, err := parseSchema(snap.metaData.SchemaString)
+	if err != nil {
+		return err
+	}
+	o.snap = snap
+	o.columns = columns
+	o.pqSchema = parquetSchema(columns, snap.metaData.PartitionColumns)
+	return nil
+}
+
+type partitionFile struct {
+	dir             string
+	partitionValues map[string]*string
+	rows            []any
+	stats           *fileStats
+}
+
+func (o *deltaLakeOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	o.mut.Lock()
+	store, columns, pqSchema := o.store, o.columns, o.pqSchema
+	var metaData *metaDataAction
+	if o.snap != nil {
+		metaData = o.snap.metaData
+	}
+	o.mut.Unlock()
+	if store == nil {
+		return service.ErrNotConnected
+	}
+	partitionColumns := metaData.PartitionColumns
+
+	var batchErr *service.BatchError
+	var files []*partitionFile
+	fileIndexes := map[string]int{}
+	for i, msg := range batch {
+		row, partitionValues, err := convertMessage(msg, columns, partitionColumns)
+		if err != nil {
+			if batchErr == nil {
+				batchErr = service.NewBatchError(batch, err)
+			}
+			batchErr = batchErr.Failed(i, err)
+			continue
+		}
+
+		var dir strings.Builder
+		for _, p := range partitionColumns {
+			v := "__HIVE_DEFAULT_PARTITION__"
+			if pv := partitionValues[p]; pv != nil {
+				v = escapePartitionValue(*pv)
+			}
+			fmt.Fprintf(&dir, "%v=%v/", escapePartitionValue(p), v)
+		}
+
+		idx, exists := fileIndexes[dir.String()]
+		if !exists {
+			idx = len(files)
+			fileIndexes[dir.String()] = idx
+			files = append(files, &partitionFile{
+				dir:             dir.String(),
+				partitionValues: partitionValues,
+				stats:           newFileStats(),
+			})
+		}
+		f := files[idx]
+		f.rows = append(f.rows, row)
+		f.stats.NumRecords++
+		for _, c := range columns {
+			if !isPartitionColumn(c.Name, partitionColumns) {
+				f.stats.add(row[c.Name], c)
+			}
+		}
+	}
+	if batchErr != nil {
+		return batchErr
+	}
+
+	actions := []action{{CommitInfo: &commitInfoAction{
+		Timestamp:           time.Now().UnixMilli(),
+		Operation:           "WRITE",
+		OperationParameters: map[string]any{"mode": "Append"},
+		IsBlindAppend:       true,
+		EngineInfo:          engineInfo,
+	}}}
+	for _, f := range files {
+		add, err := o.writeDataFile(ctx, store, pqSchema, columns, f)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, action{Add: add})
+	}
+
+	o.commitMut.Lock()
+	defer o.commitMut.Unlock()
+
+	// The snapshot is updated by commits, and so a copy is committed against
+	// which replaces the snapshot afterwards.
+	o.mut.Lock()
+	next := *o.snap
+	o.mut.Unlock()
+	if next.metaData != metaData {
+		return errMetadataChanged
+	}
+
+	err := commit(ctx, store, &next, actions, o.maxCommitAttempts)
+
+	o.mut.Lock()
+	defer o.mut.Unlock()
+	if errors.Is(err, errMetadataChanged) {
+		if serr := o.setSnapshot(&next); serr != nil {
+			// Writes are rejected until the table is loaded again.
+			o.store = nil
+			return fmt.Errorf("%w: %v", err, serr)
+		}
+		return err
+	}
+	o.snap = &next
+	return err
+}
+
+// convertMessage converts a message into a row of a data file, along with the
+// values of its partition columns.
+func convertMessage(msg *service.Message, columns []column, partitionColumns []string) (map[string]any, map[string]*string, error) {
+	v, err := msg.AsStructured()
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("expected message to be an object, got %T", v)
+	}
+
+	row := make(map[string]any, len(columns))
+	partitionValues := make(map[string]*string, len(partitionColumns))
+	for _, c := range columns {
+		cv, err := convertValue(obj[c.Name], c)
+		if err != nil {
+			return nil, nil, fmt.Errorf("column %v: %w", c.Name, err)
+		}
+		if isPartitionColumn(c.Name, partitionColumns) {
+			partitionValues[c.Name] = partitionValue(cv, c)
+			continue
+		}
+		row[c.Name] = cv
+	}
+	return row, partitionValues, nil
+}
+
+func (o *deltaLakeOutput) writeDataFile(ctx context.Context, store objectStore, pqSchema *parquet.Schema, columns []column, f *partitionFile) (*addAction, error) {
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[any](&buf, pqSchema, parquet.Compression(o.compression))
+	if err := writeRows(w, f.rows); err != nil {
+		return nil, fmt.Errorf("failed to encode data file: %w", err)
+	}
+
+	name := fmt.Sprintf("part-00000-%v-c000%v", uuid.NewString(), o.fileSuffix)
+	key := f.dir + name
+	if err := store.put(ctx, key, buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to write data file %v: %w", key, err)
+	}
+
+	stats, err := f.stats.json(columns)
+	if err != nil {
+		return nil, err
+	}
+	return &addAction{
+		Path:             (&url.URL{Path: key}).EscapedPath(),
+		PartitionValues:  f.partitionValues,
+		Size:             int64(buf.Len()),
+		ModificationTime: time.Now().UnixMilli(),
+		DataChange:       true,
+		Stats:            stats,
+	}, nil
+}
+
+func writeRows(w *parquet.GenericWriter[any], rows []any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("encoding panic: %v", r)
+		}
+	}()
+	if _, err = w.Write(rows); err != nil {
+		return
+	}
+	return w.Close()
+}
+
+func (o *deltaLakeOutput) Close(ctx context.Context) error {
+	return nil
+}
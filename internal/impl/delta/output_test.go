@@ -0,0 +1,277 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testOutput(t *testing.T, store objectStore, yaml string) *deltaLakeOutput {
+	t.Helper()
+
+	conf, err := deltaLakeOutputConfig().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+
+	o, err := newDeltaLakeOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.connectStore(context.Background(), store))
+	return o
+}
+
+const testConfig = `
+path: file:///unused
+create_table:
+  schema:
+    - name: id
+      type: long
+      nullable: false
+    - name: name
+      type: string
+    - name: ts
+      type: timestamp
+    - name: region
+      type: string
+  partition_columns: [ region ]
+`
+
+func readVersion(t *testing.T, store objectStore, version int64) []action {
+	t.Helper()
+
+	b, err := store.get(context.Background(), commitKey(version))
+	require.NoError(t, err)
+	actions, err := decodeActions(b)
+	require.NoError(t, err)
+	return actions
+}
+
+func TestDeltaLakeOutputWrite(t *testing.T) {
+	store := &fileStore{root: t.TempDir()}
+	o := testOutput(t, store, testConfig)
+
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"name":"foo","ts":"2025-01-01T10:00:00.0015Z","region":"eu/west"}`)),
+		service.NewMessage([]byte(`{"id":2,"name":{"first":"bar"},"region":"us"}`)),
+		service.NewMessage([]byte(`{"id":"3","ts":"2025-01-01T09:00:00Z","region":"eu/west","ignored":true}`)),
+		service.NewMessage([]byte(`{"id":4}`)),
+	}))
+
+	snap, err := loadSnapshot(context.Background(), store)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), snap.version)
+	assert.Equal(t, []string{"region"}, snap.metaData.PartitionColumns)
+
+	var adds []*addAction
+	for _, a := range readVersion(t, store, 1) {
+		if a.Add != nil {
+			adds = append(adds, a.Add)
+		}
+	}
+	require.Len(t, adds, 3)
+
+	eu := "eu/west"
+	assert.Equal(t, map[string]*string{"region": &eu}, adds[0].PartitionValues)
+	assert.Regexp(t, `^region=eu%252Fwest/part-00000-.*-c000\.snappy\.parquet$`, adds[0].Path)
+	assert.JSONEq(t, `{
+		"numRecords": 2,
+		"minValues": {"id": 1, "ts": "2025-01-01T09:00:00.000Z"},
+		"maxValues": {"id": 3, "ts": "2025-01-01T10:00:00.002Z"},
+		"nullCount": {"id": 0, "name": 1, "ts": 0}
+	}`, adds[0].Stats)
+	assert.Nil(t, adds[2].PartitionValues["region"])
+	assert.Regexp(t, `^region=__HIVE_DEFAULT_PARTITION__/`, adds[2].Path)
+
+	key, err := url.PathUnescape(adds[1].Path)
+	require.NoError(t, err)
+	b, err := store.get(context.Background(), key)
+	require.NoError(t, err)
+
+	type row struct {
+		ID   int64   `parquet:"id"`
+		Name *string `parquet:"name,optional"`
+	}
+	rows, err := parquet.Read[row](bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(2), rows[0].ID)
+	assert.Equal(t, `{"first":"bar"}`, *rows[0].Name)
+}
+
+func TestDeltaLakeOutputInvalidMessages(t *testing.T) {
+	store := &fileStore{root: t.TempDir()}
+	o := testOutput(t, store, testConfig)
+
+	err := o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1}`)),
+		service.NewMessage([]byte(`{"name":"foo"}`)),
+		service.NewMessage([]byte(`{"id":1.5}`)),
+		service.NewMessage([]byte(`[1]`)),
+	})
+
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 3, batchErr.IndexedErrors())
+
+	_, err = store.get(context.Background(), commitKey(1))
+	assert.ErrorIs(t, err, errObjectNotFound)
+}
+
+func TestDeltaLakeOutputTableNotFound(t *testing.T) {
+	conf, err := deltaLakeOutputConfig().ParseYAML(`path: file:///unused`, nil)
+	require.NoError(t, err)
+
+	o, err := newDeltaLakeOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.ErrorContains(t, o.connectStore(context.Background(), &fileStore{root: t.TempDir()}), "does not exist")
+}
+
+func TestDeltaLakeOutputConcurrentWriters(t *testing.T) {
+	store := &fileStore{root: t.TempDir()}
+	writers := []*deltaLakeOutput{
+		testOutput(t, store, testConfig),
+		testOutput(t, store, testConfig),
+		testOutput(t, store, testConfig),
+	}
+
+	var wg sync.WaitGroup
+	for i, w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				assert.NoError(t, w.WriteBatch(context.Background(), service.MessageBatch{
+					service.NewMessage(fmt.Appendf(nil, `{"id":%v,"region":"eu"}`, i*10+j)),
+				}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	snap, err := loadSnapshot(context.Background(), store)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), snap.version)
+
+	adds := 0
+	for v := int64(1); v <= snap.version; v++ {
+		for _, a := range readVersion(t, store, v) {
+			if a.Add != nil {
+				adds++
+			}
+		}
+	}
+	assert.Equal(t, 15, adds)
+}
+
+func TestDeltaLakeOutputMetadataChanged(t *testing.T) {
+	store := &fileStore{root: t.TempDir()}
+	o := testOutput(t, store, testConfig)
+
+	// Another writer adds a column to the table.
+	snap, err := loadSnapshot(context.Background(), store)
+	require.NoError(t, err)
+	meta := *snap.metaData
+	meta.SchemaString, err = formatSchema(append(o.columns, column{Name: "extra", Type: "double", Nullable: true}))
+	require.NoError(t, err)
+	require.NoError(t, commit(context.Background(), store, snap, []action{{MetaData: &meta}}, 1))
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"extra":1.5}`)),
+	}
+	require.True(t, errors.Is(o.WriteBatch(context.Background(), batch), errMetadataChanged))
+	require.NoError(t, o.WriteBatch(context.Background(), batch))
+
+	var add *addAction
+	for _, a := range readVersion(t, store, 2) {
+		if a.Add != nil {
+			add = a.Add
+		}
+	}
+	require.NotNil(t, add)
+	assert.Contains(t, add.Stats, `"extra":1.5`)
+}
+
+func TestDeltaLakeOutputUnsupportedTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol protocolAction
+		schema   string
+		config   map[string]string
+		err      string
+	}{
+		{
+			name:     "writer version",
+			protocol: protocolAction{MinReaderVersion: 2, MinWriterVersion: 5},
+			schema:   `{"type":"struct","fields":[]}`,
+			err:      "writer version 5",
+		},
+		{
+			name:     "writer feature",
+			protocol: protocolAction{MinReaderVersion: 3, MinWriterVersion: 7, WriterFeatures: []string{"appendOnly", "deletionVectors"}},
+			schema:   `{"type":"struct","fields":[]}`,
+			err:      "writer feature deletionVectors",
+		},
+		{
+			name:     "nested column",
+			protocol: protocolAction{MinReaderVersion: 1, MinWriterVersion: 2},
+			schema:   `{"type":"struct","fields":[{"name":"a","type":{"type":"struct","fields":[]},"nullable":true,"metadata":{}}]}`,
+			err:      "column a has type",
+		},
+		{
+			name:     "generated column",
+			protocol: protocolAction{MinReaderVersion: 1, MinWriterVersion: 4},
+			schema:   `{"type":"struct","fields":[{"name":"a","type":"long","nullable":true,"metadata":{"delta.generationExpression":"1"}}]}`,
+			err:      "delta.generationExpression",
+		},
+		{
+			name:     "check constraint",
+			protocol: protocolAction{MinReaderVersion: 1, MinWriterVersion: 3},
+			schema:   `{"type":"struct","fields":[]}`,
+			config:   map[string]string{"delta.constraints.positive": "a > 0"},
+			err:      "check constraint",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &fileStore{root: t.TempDir()}
+			require.NoError(t, commit(context.Background(), store, &snapshot{version: -1}, []action{
+				{Protocol: &test.protocol},
+				{MetaData: &metaDataAction{
+					ID:            "foo",
+					Format:        formatSpec{Provider: "parquet"},
+					SchemaString:  test.schema,
+					Configuration: test.config,
+				}},
+			}, 1))
+
+			conf, err := deltaLakeOutputConfig().ParseYAML(`path: file:///unused`, nil)
+			require.NoError(t, err)
+			o, err := newDeltaLakeOutputFromConfig(conf, service.MockResources())
+			require.NoError(t, err)
+			require.ErrorContains(t, o.connectStore(context.Background(), store), test.err)
+		})
+	}
+}
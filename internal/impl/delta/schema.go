@@ -0,0 +1,439 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// supportedTypes are the primitive Delta types that data files can be written
+// with.
+var supportedTypes = []string{
+	"string", "long", "integer", "short", "byte", "float", "double", "boolean", "binary", "date", "timestamp",
+}
+
+type column struct {
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Nullable bool           `json:"nullable"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+type structType struct {
+	Type   string   `json:"type"`
+	Fields []column `json:"fields"`
+}
+
+func (c column) isSupported() bool {
+	for _, t := range supportedTypes {
+		if c.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSchema parses the schema of a table, which must only consist of
+// columns of supported types.
+func parseSchema(schemaString string) ([]column, error) {
+	var raw struct {
+		Type   string            `json:"type"`
+		Fields []json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(schemaString), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse table schema: %w", err)
+	}
+
+	columns := make([]column, len(raw.Fields))
+	for i, f := range raw.Fields {
+		// Nested types are objects rather than strings, which are rejected
+		// along with any other unsupported types.
+		var c struct {
+			column
+			Type json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(f, &c); err != nil {
+			return nil, fmt.Errorf("failed to parse table schema: %w", err)
+		}
+		if err := json.Unmarshal(c.Type, &c.column.Type); err != nil || !c.column.isSupported() {
+			return nil, fmt.Errorf("column %v has type %s, which is not supported", c.Name, c.Type)
+		}
+		for k := range c.Metadata {
+			if strings.HasPrefix(k, "delta.") && k != "delta.columnMapping.id" && k != "delta.columnMapping.physicalName" {
+				return nil, fmt.Errorf("column %v has metadata %v, which is not supported", c.Name, k)
+			}
+		}
+		columns[i] = c.column
+	}
+	return columns, nil
+}
+
+func formatSchema(columns []column) (string, error) {
+	for i := range columns {
+		if columns[i].Metadata == nil {
+			columns[i].Metadata = map[string]any{}
+		}
+	}
+	b, err := json.Marshal(structType{Type: "struct", Fields: columns})
+	return string(b), err
+}
+
+// parquetSchema returns the schema of data files, which excludes the partition
+// columns of the table.
+func parquetSchema(columns []column, partitionColumns []string) *parquet.Schema {
+	group := parquet.Group{}
+	for _, c := range columns {
+		if isPartitionColumn(c.Name, partitionColumns) {
+			continue
+		}
+		var n parquet.Node
+		switch c.Type {
+		case "string":
+			n = parquet.String()
+		case "long":
+			n = parquet.Int(64)
+		case "integer":
+			n = parquet.Int(32)
+		case "short":
+			n = parquet.Int(16)
+		case "byte":
+			n = parquet.Int(8)
+		case "float":
+			n = parquet.Leaf(parquet.FloatType)
+		case "double":
+			n = parquet.Leaf(parquet.DoubleType)
+		case "boolean":
+			n = parquet.Leaf(parquet.BooleanType)
+		case "binary":
+			n = parquet.Leaf(parquet.ByteArrayType)
+		case "date":
+			n = parquet.Date()
+		case "timestamp":
+			n = parquet.Timestamp(parquet.Microsecond)
+		}
+		if c.Nullable {
+			n = parquet.Optional(n)
+		}
+		group[c.Name] = n
+	}
+	return parquet.NewSchema("", group)
+}
+
+func isPartitionColumn(name string, partitionColumns []string) bool {
+	for _, p := range partitionColumns {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+//------------------------------------------------------------------------------
+
+var errNotNullable = errors.New("value is null but the column is not nullable")
+
+// convertValue converts a value of a message into the representation of a
+// column in data files.
+func convertValue(v any, c column) (any, error) {
+	if v == nil {
+		if !c.Nullable {
+			return nil, errNotNullable
+		}
+		return nil, nil
+	}
+
+	switch c.Type {
+	case "string":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case "long":
+		return toInt(v, math.MinInt64, math.MaxInt64)
+	case "integer":
+		i, err := toInt(v, math.MinInt32, math.MaxInt32)
+		return int32(i), err
+	case "short":
+		i, err := toInt(v, math.MinInt16, math.MaxInt16)
+		return int32(i), err
+	case "byte":
+		i, err := toInt(v, math.MinInt8, math.MaxInt8)
+		return int32(i), err
+	case "float":
+		f, err := toFloat(v)
+		return float32(f), err
+	case "double":
+		return toFloat(v)
+	case "boolean":
+		switch t := v.(type) {
+		case bool:
+			return t, nil
+		case string:
+			return strconv.ParseBool(t)
+		}
+	case "binary":
+		switch t := v.(type) {
+		case []byte:
+			return t, nil
+		case string:
+			return []byte(t), nil
+		}
+	case "date":
+		t, err := toTime(v, time.DateOnly)
+		if err != nil {
+			return nil, err
+		}
+		return int32(t.Unix() / 86400), nil
+	case "timestamp":
+		t, err := toTime(v, time.RFC3339Nano)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnixMicro(), nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to %v", v, c.Type)
+}
+
+func toInt(v any, lower, upper int64) (int64, error) {
+	var i int64
+	switch t := v.(type) {
+	case int:
+		i = int64(t)
+	case int32:
+		i = int64(t)
+	case int64:
+		i = t
+	case uint64:
+		if t > math.MaxInt64 {
+			return 0, fmt.Errorf("value %v is out of range", t)
+		}
+		i = int64(t)
+	case float64:
+		if t != math.Trunc(t) {
+			return 0, fmt.Errorf("value %v is not an integer", t)
+		}
+		i = int64(t)
+	case json.Number:
+		var err error
+		if i, err = t.Int64(); err != nil {
+			return 0, err
+		}
+	case string:
+		var err error
+		if i, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("cannot convert %T to an integer", v)
+	}
+	if i < lower || i > upper {
+		return 0, fmt.Errorf("value %v is out of range", i)
+	}
+	return i, nil
+}
+
+func toFloat(v any) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case int:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	case json.Number:
+		return t.Float64()
+	case string:
+		return strconv.ParseFloat(t, 64)
+	}
+	return 0, fmt.Errorf("cannot convert %T to a float", v)
+}
+
+func toTime(v any, layout string) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC(), nil
+	case string:
+		if ts, err := time.Parse(layout, t); err == nil {
+			return ts.UTC(), nil
+		}
+		ts, err := time.Parse(time.RFC3339Nano, t)
+		return ts.UTC(), err
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T to a timestamp", v)
+}
+
+//------------------------------------------------------------------------------
+
+// partitionValue returns the serialised form of the value of a partition
+// column, as it's written to the transaction log.
+func partitionValue(v any, c column) *string {
+	if v == nil {
+		return nil
+	}
+	var s string
+	switch c.Type {
+	case "date":
+		s = time.Unix(int64(v.(int32))*86400, 0).UTC().Format(time.DateOnly)
+	case "timestamp":
+		s = time.UnixMicro(v.(int64)).UTC().Format("2006-01-02 15:04:05.999999")
+	case "binary":
+		s = string(v.([]byte))
+	case "float":
+		s = strconv.FormatFloat(float64(v.(float32)), 'g', -1, 32)
+	case "double":
+		s = strconv.FormatFloat(v.(float64), 'g', -1, 64)
+	default:
+		s = fmt.Sprint(v)
+	}
+	return &s
+}
+
+// escapePartitionValue escapes the characters of a partition value which
+// aren't permitted in the directory names of partitions, in the same way as
+// Hive.
+func escapePartitionValue(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune("\"#%'*/:=?\\{[]^", r) {
+			fmt.Fprintf(&b, "%%%02X", r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+//------------------------------------------------------------------------------
+
+// fileStats are the statistics of a data file, which engines use to skip
+// data files when reading.
+type fileStats struct {
+	NumRecords int64          `json:"numRecords"`
+	MinValues  map[string]any `json:"minValues"`
+	MaxValues  map[string]any `json:"maxValues"`
+	NullCount  map[string]any `json:"nullCount"`
+}
+
+func newFileStats() *fileStats {
+	return &fileStats{
+		MinValues: map[string]any{},
+		MaxValues: map[string]any{},
+		NullCount: map[string]any{},
+	}
+}
+
+// add updates the statistics with a value of a column. Minimum and maximum
+// values are only tracked for numeric and temporal columns.
+func (s *fileStats) add(v any, c column) {
+	if v == nil {
+		n, _ := s.NullCount[c.Name].(int64)
+		s.NullCount[c.Name] = n + 1
+		return
+	}
+	if _, exists := s.NullCount[c.Name]; !exists {
+		s.NullCount[c.Name] = int64(0)
+	}
+
+	var f float64
+	switch t := v.(type) {
+	case int32:
+		f = float64(t)
+	case int64:
+		f = float64(t)
+	case float32:
+		if math.IsNaN(float64(t)) {
+			return
+		}
+		f = float64(t)
+	case float64:
+		if math.IsNaN(t) {
+			return
+		}
+		f = t
+	default:
+		return
+	}
+
+	if cur, exists := s.MinValues[c.Name]; !exists || f < statNumber(cur) {
+		s.MinValues[c.Name] = v
+	}
+	if cur, exists := s.MaxValues[c.Name]; !exists || f > statNumber(cur) {
+		s.MaxValues[c.Name] = v
+	}
+}
+
+func statNumber(v any) float64 {
+	switch t := v.(type) {
+	case int32:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case float32:
+		return float64(t)
+	case float64:
+		return t
+	}
+	return 0
+}
+
+// json returns the statistics in the form they're written to the transaction
+// log, where dates and timestamps are formatted as strings.
+func (s *fileStats) json(columns []column) (string, error) {
+	out := *s
+	out.MinValues = map[string]any{}
+	out.MaxValues = map[string]any{}
+	for _, c := range columns {
+		if v, exists := s.MinValues[c.Name]; exists {
+			out.MinValues[c.Name] = formatStat(v, c, false)
+		}
+		if v, exists := s.MaxValues[c.Name]; exists {
+			out.MaxValues[c.Name] = formatStat(v, c, true)
+		}
+	}
+	b, err := json.Marshal(out)
+	return string(b), err
+}
+
+func formatStat(v any, c column, isMax bool) any {
+	switch c.Type {
+	case "date":
+		return time.Unix(int64(v.(int32))*86400, 0).UTC().Format(time.DateOnly)
+	case "timestamp":
+		// Timestamps are truncated to milliseconds, and so a maximum is rounded
+		// up in order to remain an upper bound.
+		ts := time.UnixMicro(v.(int64)).UTC()
+		if isMax && ts.Truncate(time.Millisecond) != ts {
+			ts = ts.Truncate(time.Millisecond).Add(time.Millisecond)
+		}
+		return ts.Format("2006-01-02T15:04:05.000Z")
+	}
+	return v
+}
@@ -0,0 +1,425 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+)
+
+var (
+	errObjectExists   = errors.New("object already exists")
+	errObjectNotFound = errors.New("object not found")
+)
+
+// objectStore provides access to the objects of a table, with keys relative to
+// the root of the table.
+type objectStore interface {
+	// put writes an object, replacing any existing object with the same key.
+	put(ctx context.Context, key string, data []byte) error
+	// putIfAbsent writes an object only if no object exists with the same key,
+	// and otherwise returns errObjectExists. Commits to the transaction log
+	// rely on this being atomic.
+	putIfAbsent(ctx context.Context, key string, data []byte) error
+	// get reads an object, and returns errObjectNotFound if it doesn't exist.
+	get(ctx context.Context, key string) ([]byte, error)
+	// list returns the keys of objects with a prefix which sort after a key,
+	// in lexicographical order.
+	list(ctx context.Context, prefix, startAfter string) ([]string, error)
+}
+
+type storeConfig struct {
+	awsConf      aws.Config
+	awsPathStyle bool
+	azureAccount string
+	azureKey     string
+	azureConnStr string
+}
+
+// newObjectStore returns the object store of a table location, which is a URL
+// with one of the schemes s3, s3a, gs, az, abfs, abfss or file.
+func newObjectStore(ctx context.Context, location string, conf storeConfig) (objectStore, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse table location: %w", err)
+	}
+	root := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "s3", "s3a":
+		client := s3.NewFromConfig(conf.awsConf, func(o *s3.Options) {
+			o.UsePathStyle = conf.awsPathStyle
+		})
+		return &s3Store{client: client, bucket: u.Host, root: root}, nil
+	case "gs":
+		client, err := storage.NewClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create storage client: %w", err)
+		}
+		return &gcsStore{bucket: client.Bucket(u.Host), root: root}, nil
+	case "az", "abfs", "abfss":
+		containerName, account := u.Host, conf.azureAccount
+		if u.User != nil {
+			// abfss://<container>@<account>.dfs.core.windows.net/<path>
+			containerName = u.User.Username()
+			account, _, _ = strings.Cut(u.Host, ".")
+		}
+		client, err := azureContainerClient(conf, account, containerName)
+		if err != nil {
+			return nil, err
+		}
+		return &azureStore{client: client, root: root}, nil
+	case "file", "":
+		return &fileStore{root: filepath.FromSlash(u.Path)}, nil
+	}
+	return nil, fmt.Errorf("table location scheme %v is not supported", u.Scheme)
+}
+
+func joinKey(root, key string) string {
+	if root == "" {
+		return key
+	}
+	return root + "/" + key
+}
+
+//------------------------------------------------------------------------------
+
+type s3Store struct {
+	client *s3.Client
+	bucket string
+	root   string
+}
+
+func (s *s3Store) put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: &s.bucket,
+		Key:    aws.String(joinKey(s.root, key)),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *s3Store) putIfAbsent(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &s.bucket,
+		Key:         aws.String(joinKey(s.root, key)),
+		Body:        bytes.NewReader(data),
+		IfNoneMatch: aws.String("*"),
+	})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return errObjectExists
+		}
+	}
+	return err
+}
+
+func (s *s3Store) get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    aws.String(joinKey(s.root, key)),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return nil, errObjectNotFound
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3Store) list(ctx context.Context, prefix, startAfter string) ([]string, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: &s.bucket,
+		Prefix: aws.String(joinKey(s.root, prefix)),
+	}
+	if startAfter != "" {
+		input.StartAfter = aws.String(joinKey(s.root, startAfter))
+	}
+
+	var keys []string
+	p := s3.NewListObjectsV2Paginator(s.client, input)
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, strings.TrimPrefix(*obj.Key, joinKey(s.root, "")))
+		}
+	}
+	return keys, nil
+}
+
+//------------------------------------------------------------------------------
+
+type gcsStore struct {
+	bucket *storage.BucketHandle
+	root   string
+}
+
+func (g *gcsStore) write(ctx context.Context, obj *storage.ObjectHandle, data []byte) error {
+	w := obj.NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (g *gcsStore) put(ctx context.Context, key string, data []byte) error {
+	return g.write(ctx, g.bucket.Object(joinKey(g.root, key)), data)
+}
+
+func (g *gcsStore) putIfAbsent(ctx context.Context, key string, data []byte) error {
+	obj := g.bucket.Object(joinKey(g.root, key)).If(storage.Conditions{DoesNotExist: true})
+	err := g.write(ctx, obj, data)
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+		return errObjectExists
+	}
+	return err
+}
+
+func (g *gcsStore) get(ctx context.Context, key string) ([]byte, error) {
+	r, err := g.bucket.Object(joinKey(g.root, key)).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, errObjectNotFound
+		}
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (g *gcsStore) list(ctx context.Context, prefix, startAfter string) ([]string, error) {
+	query := &storage.Query{Prefix: joinKey(g.root, prefix)}
+	if startAfter != "" {
+		query.StartOffset = joinKey(g.root, startAfter)
+	}
+
+	var keys []string
+	it := g.bucket.Objects(ctx, query)
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		key := strings.TrimPrefix(attrs.Name, joinKey(g.root, ""))
+		if key > startAfter {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+//------------------------------------------------------------------------------
+
+func azureContainerClient(conf storeConfig, account, containerName string) (*container.Client, error) {
+	if conf.azureConnStr != "" {
+		client, err := container.NewClientFromConnectionString(conf.azureConnStr, containerName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create container client from connection string: %w", err)
+		}
+		return client, nil
+	}
+	if account == "" {
+		return nil, errors.New("a storage account must be specified for az table locations")
+	}
+
+	containerURL := fmt.Sprintf("https://%v.blob.core.windows.net/%v", account, containerName)
+	if conf.azureKey != "" {
+		cred, err := azblob.NewSharedKeyCredential(account, conf.azureKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create shared key credential: %w", err)
+		}
+		return container.NewClientWithSharedKeyCredential(containerURL, cred, nil)
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default Azure credentials: %w", err)
+	}
+	return container.NewClient(containerURL, cred, nil)
+}
+
+type azureStore struct {
+	client *container.Client
+	root   string
+}
+
+func (a *azureStore) put(ctx context.Context, key string, data []byte) error {
+	_, err := a.client.NewBlockBlobClient(joinKey(a.root, key)).UploadBuffer(ctx, data, nil)
+	return err
+}
+
+func (a *azureStore) putIfAbsent(ctx context.Context, key string, data []byte) error {
+	etagAny := azcore.ETagAny
+	_, err := a.client.NewBlockBlobClient(joinKey(a.root, key)).UploadBuffer(ctx, data, &blockblob.UploadBufferOptions{
+		AccessConditions: &blob.AccessConditions{
+			ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfNoneMatch: &etagAny},
+		},
+	})
+	if bloberror.HasCode(err, bloberror.BlobAlreadyExists, bloberror.ConditionNotMet) {
+		return errObjectExists
+	}
+	return err
+}
+
+func (a *azureStore) get(ctx context.Context, key string) ([]byte, error) {
+	out, err := a.client.NewBlobClient(joinKey(a.root, key)).DownloadStream(ctx, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return nil, errObjectNotFound
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (a *azureStore) list(ctx context.Context, prefix, startAfter string) ([]string, error) {
+	var keys []string
+	p := a.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix: aws.String(joinKey(a.root, prefix)),
+	})
+	for p.More() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			key := strings.TrimPrefix(*item.Name, joinKey(a.root, ""))
+			if key > startAfter {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+//------------------------------------------------------------------------------
+
+type fileStore struct {
+	root string
+}
+
+func (f *fileStore) path(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(key))
+}
+
+func (f *fileStore) put(_ context.Context, key string, data []byte) error {
+	p := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0o644)
+}
+
+func (f *fileStore) putIfAbsent(_ context.Context, key string, data []byte) error {
+	p := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file which is then hard linked to the key, as
+	// linking fails atomically when the key already exists.
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Link(tmp.Name(), p); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return errObjectExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (f *fileStore) get(_ context.Context, key string) ([]byte, error) {
+	b, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errObjectNotFound
+	}
+	return b, err
+}
+
+// list only supports prefixes of keys within a single directory, which is
+// sufficient for listing the transaction log.
+func (f *fileStore) list(_ context.Context, prefix, startAfter string) ([]string, error) {
+	dir := path.Dir(prefix + "x")
+	entries, err := os.ReadDir(f.path(dir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var keys []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		key := path.Join(dir, e.Name())
+		if strings.HasPrefix(key, prefix) && key > startAfter {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
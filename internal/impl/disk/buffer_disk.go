@@ -0,0 +1,676 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	dbFieldPath          = "path"
+	dbFieldMaxSize       = "max_size"
+	dbFieldSegmentSize   = "segment_size"
+	dbFieldRetention     = "retention"
+	dbFieldSync          = "sync"
+	dbFieldSyncInterval  = "sync_interval"
+	dbSyncAlways         = "always"
+	dbSyncInterval       = "interval"
+	dbSyncNever          = "never"
+	dbCheckpointFileName = "checkpoint"
+)
+
+func diskBufferSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.48.0").
+		Summary("Stores messages in append-only segment files on disk and acknowledges them at the input level.").
+		Description(`
+Batches are appended to segment files within a directory, each record protected by a checksum, and consumed in the order they were written. Segment files are deleted once every batch within them has been successfully delivered. When the service restarts it consumes from the oldest batch that has not yet been delivered, and any trailing data that cannot be read, such as a record only partially written during a crash, is discarded.
+
+This buffer is an alternative to the `+"`memory`"+` buffer for pipelines that must absorb long outages of downstream services without growing their memory usage or losing data on restart.
+
+== Backpressure
+
+Once the total size of the segment files reaches `+"`max_size`"+` writes to the buffer are blocked, applying backpressure to the input, until enough batches have been delivered for segments to be deleted.
+
+== Delivery guarantees
+
+Messages are not acknowledged at the input level until they have been written to a segment file, and are only removed once they have been successfully delivered. This means at-least-once delivery guarantees are preserved in cases where the service is shut down unexpectedly, although batches that were delivered shortly before a crash may be delivered again. How much data can be lost when the machine itself fails depends on the `+"`sync`"+` policy, and these guarantees are not resilient to disk corruption or loss.
+
+== Batching
+
+Messages that are logically batched at the point where they are added to the buffer will continue to be associated with that batch when they are consumed.
+`).
+		Fields(
+			service.NewStringField(dbFieldPath).
+				Description("The directory to store segment files within, which will be created if it does not already exist. Each buffer must have its own directory."),
+			service.NewStringField(dbFieldMaxSize).
+				Description("The maximum total size of the segment files, after which writes are blocked.").
+				Default("1GiB").
+				Example("500MB").
+				Example("10GiB"),
+			service.NewStringField(dbFieldSegmentSize).
+				Description("The size after which a new segment file is started. Smaller segments allow disk space to be reclaimed sooner.").
+				Default("64MiB").
+				Advanced(),
+			service.NewDurationField(dbFieldRetention).
+				Description("An optional maximum age of data within the buffer. Segments where every batch is older than this are dropped without being delivered, which can be used to avoid delivering stale data after a long outage. When empty data is retained until it is delivered.").
+				Example("24h").
+				Optional(),
+			service.NewStringAnnotatedEnumField(dbFieldSync, map[string]string{
+				dbSyncAlways:   "Writes are flushed to disk before they are acknowledged, and the delivery checkpoint is flushed each time it advances. This is the safest and slowest option.",
+				dbSyncInterval: "Writes and the delivery checkpoint are flushed to disk periodically according to `sync_interval`.",
+				dbSyncNever:    "Writes are never explicitly flushed to disk, and it is up to the operating system to do so.",
+			}).
+				Description("The policy for flushing data to disk.").
+				Default(dbSyncInterval),
+			service.NewDurationField(dbFieldSyncInterval).
+				Description("The period at which data is flushed to disk when the `sync` policy is `interval`, and at which the delivery checkpoint is saved unless the policy is `always`.").
+				Default("1s").
+				Advanced(),
+		).
+		Example("Surviving Outages", "Buffer up to 50GiB of messages on disk whilst the output is unavailable, dropping data that is more than a day old.", `
+buffer:
+  disk:
+    path: ./buffer
+    max_size: 50GiB
+    retention: 24h
+`)
+}
+
+func init() {
+	err := service.RegisterBatchBuffer("disk", diskBufferSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newDiskBufferFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type diskBufferConfig struct {
+	dir          string
+	maxSize      int64
+	segmentSize  int64
+	retention    time.Duration
+	sync         string
+	syncInterval time.Duration
+}
+
+func newDiskBufferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*diskBuffer, error) {
+	var c diskBufferConfig
+	var err error
+	if c.dir, err = conf.FieldString(dbFieldPath); err != nil {
+		return nil, err
+	}
+	if c.maxSize, err = byteSizeField(conf, dbFieldMaxSize); err != nil {
+		return nil, err
+	}
+	if c.segmentSize, err = byteSizeField(conf, dbFieldSegmentSize); err != nil {
+		return nil, err
+	}
+	if conf.Contains(dbFieldRetention) {
+		if c.retention, err = conf.FieldDuration(dbFieldRetention); err != nil {
+			return nil, err
+		}
+	}
+	if c.sync, err = conf.FieldString(dbFieldSync); err != nil {
+		return nil, err
+	}
+	if c.syncInterval, err = conf.FieldDuration(dbFieldSyncInterval); err != nil {
+		return nil, err
+	}
+	if c.syncInterval <= 0 {
+		return nil, errors.New("sync_interval must be larger than zero")
+	}
+	switch c.sync {
+	case dbSyncAlways, dbSyncInterval, dbSyncNever:
+	default:
+		return nil, fmt.Errorf("sync policy not recognised: %v", c.sync)
+	}
+	return newDiskBuffer(c, mgr.Logger())
+}
+
+func byteSizeField(conf *service.ParsedConfig, name string) (int64, error) {
+	s, err := conf.FieldString(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := humanize.ParseBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %v: %w", name, err)
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%v must be larger than zero", name)
+	}
+	return int64(n), nil
+}
+
+//------------------------------------------------------------------------------
+
+type recordRef struct {
+	seg    *segment
+	offset int64
+}
+
+type diskBuffer struct {
+	conf  diskBufferConfig
+	log   *service.Logger
+	nowFn func() time.Time
+
+	cond *sync.Cond
+
+	segments  []*segment
+	writer    *os.File
+	nextID    uint64
+	totalSize int64
+
+	// The next record to read, followed by any records that were rejected and
+	// must be read again first.
+	readSeg    *segment
+	readOffset int64
+	retries    []recordRef
+
+	// All records with an ID below the watermark have been delivered, along
+	// with those in the acked set.
+	watermark uint64
+	acked     map[uint64]struct{}
+
+	unsynced         bool
+	checkpointDirty  bool
+	endOfInput       bool
+	closed           bool
+	stopBackground   chan struct{}
+	backgroundDoneWG sync.WaitGroup
+}
+
+func newDiskBuffer(conf diskBufferConfig, log *service.Logger) (*diskBuffer, error) {
+	if err := os.MkdirAll(conf.dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	d := &diskBuffer{
+		conf:           conf,
+		log:            log,
+		nowFn:          time.Now,
+		cond:           sync.NewCond(&sync.Mutex{}),
+		acked:          map[uint64]struct{}{},
+		stopBackground: make(chan struct{}),
+	}
+
+	var err error
+	if d.watermark, err = readCheckpoint(conf.dir); err != nil {
+		return nil, err
+	}
+	d.nextID = d.watermark
+
+	seqs, err := listSegments(conf.dir)
+	if err != nil {
+		return nil, err
+	}
+	nextSeq := uint64(0)
+	for _, seq := range seqs {
+		nextSeq = seq + 1
+		seg, truncated, err := scanSegment(conf.dir, seq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read segment %v: %w", seq, err)
+		}
+		if truncated > 0 {
+			d.log.Warnf("Discarded %v bytes of unreadable data from the end of segment %v", truncated, seg.path)
+		}
+		if seg.size == 0 || seg.nextID <= d.watermark {
+			if err := os.Remove(seg.path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		d.segments = append(d.segments, seg)
+		d.totalSize += seg.size
+		d.nextID = max(d.nextID, seg.nextID)
+	}
+
+	if err := d.openSegment(nextSeq); err != nil {
+		return nil, err
+	}
+	d.readSeg = d.segments[0]
+
+	d.backgroundDoneWG.Add(1)
+	go d.backgroundLoop()
+	return d, nil
+}
+
+func readCheckpoint(dir string) (uint64, error) {
+	b, err := os.ReadFile(filepath.Join(dir, dbCheckpointFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, errors.New("checkpoint file is corrupt")
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint file with the current
+// watermark.
+func (d *diskBuffer) writeCheckpoint(sync bool) error {
+	path := filepath.Join(d.conf.dir, dbCheckpointFileName)
+	tmpPath := path + ".tmp"
+
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(binary.BigEndian.AppendUint64(nil, d.watermark)); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if sync {
+		if err := f.Sync(); err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	d.checkpointDirty = false
+	return nil
+}
+
+func (d *diskBuffer) activeSegment() *segment {
+	return d.segments[len(d.segments)-1]
+}
+
+func (d *diskBuffer) openSegment(seq uint64) error {
+	seg := &segment{
+		seq:     seq,
+		path:    segmentPath(d.conf.dir, seq),
+		firstID: d.nextID,
+		nextID:  d.nextID,
+	}
+	f, err := os.OpenFile(seg.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if d.writer != nil {
+		if err := d.syncWriter(); err != nil {
+			_ = f.Close()
+			return err
+		}
+		_ = d.writer.Close()
+	}
+	d.writer = f
+	d.segments = append(d.segments, seg)
+	return nil
+}
+
+func (d *diskBuffer) syncWriter() error {
+	if !d.unsynced || d.conf.sync == dbSyncNever {
+		return nil
+	}
+	if err := d.writer.Sync(); err != nil {
+		return err
+	}
+	d.unsynced = false
+	return nil
+}
+
+func (d *diskBuffer) backgroundLoop() {
+	defer d.backgroundDoneWG.Done()
+
+	ticker := time.NewTicker(d.conf.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.stopBackground:
+			return
+		}
+
+		d.cond.L.Lock()
+		if !d.closed {
+			if d.conf.sync == dbSyncInterval {
+				if err := d.syncWriter(); err != nil {
+					d.log.Errorf("Failed to sync segment file: %v", err)
+				}
+			}
+			if d.checkpointDirty {
+				if err := d.writeCheckpoint(d.conf.sync == dbSyncInterval); err != nil {
+					d.log.Errorf("Failed to write checkpoint: %v", err)
+				}
+			}
+		}
+		d.cond.L.Unlock()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// WriteBatch appends a batch to the active segment, blocking whilst the buffer
+// is full.
+func (d *diskBuffer) WriteBatch(ctx context.Context, batch service.MessageBatch, aFn service.AckFunc) error {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		d.cond.Broadcast()
+	}()
+
+	record, err := newRecord(batch)
+	if err != nil {
+		return err
+	}
+
+	d.cond.L.Lock()
+	defer d.cond.L.Unlock()
+
+	for {
+		if d.closed {
+			return service.ErrEndOfBuffer
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// A record larger than the maximum size is still accepted once the
+		// buffer is empty, otherwise it could never be written.
+		if d.totalSize == 0 || d.totalSize+int64(len(record)) <= d.conf.maxSize {
+			break
+		}
+		// The active segment is never deleted, and so when it alone holds
+		// the remaining space it's rolled once delivered.
+		if active := d.activeSegment(); active.size > 0 && active.nextID <= d.watermark {
+			if err := d.openSegment(active.seq + 1); err != nil {
+				return err
+			}
+			d.deleteDelivered()
+			continue
+		}
+		d.cond.Wait()
+	}
+
+	if d.activeSegment().size >= d.conf.segmentSize {
+		if err := d.openSegment(d.activeSegment().seq + 1); err != nil {
+			return err
+		}
+	}
+
+	id := d.nextID
+	now := d.nowFn()
+	stampRecord(record, id, now)
+	if _, err := d.writer.Write(record); err != nil {
+		return err
+	}
+	d.unsynced = true
+	if d.conf.sync == dbSyncAlways {
+		if err := d.syncWriter(); err != nil {
+			return err
+		}
+	}
+
+	seg := d.activeSegment()
+	seg.nextID = id + 1
+	seg.size += int64(len(record))
+	seg.lastWrite = now
+	d.nextID = id + 1
+	d.totalSize += int64(len(record))
+
+	if err := aFn(ctx, nil); err != nil {
+		return err
+	}
+	d.cond.Broadcast()
+	return nil
+}
+
+func (d *diskBuffer) segmentIndex(seg *segment) int {
+	for i, s := range d.segments {
+		if s == seg {
+			return i
+		}
+	}
+	return -1
+}
+
+// expireRecords skips unread records within segments that are older than the
+// retention period, marking them as delivered.
+func (d *diskBuffer) expireRecords() {
+	if d.conf.retention <= 0 {
+		return
+	}
+	cutoff := d.nowFn().Add(-d.conf.retention)
+	for d.readSeg != d.activeSegment() && d.readSeg.lastWrite.Before(cutoff) {
+		var dropped uint64
+		for d.readOffset < d.readSeg.size {
+			h, _, err := readRecordAt(d.readSeg, d.readOffset)
+			if err != nil {
+				d.log.Errorf("Failed to read record from segment %v: %v", d.readSeg.path, err)
+				break
+			}
+			d.readOffset += recordHeaderLen + int64(h.length)
+			if d.markDelivered(h.id) {
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			d.log.Warnf("Dropped %v batches from segment %v as they exceeded the retention period", dropped, d.readSeg.path)
+		}
+		d.readSeg = d.segments[d.segmentIndex(d.readSeg)+1]
+		d.readOffset = 0
+	}
+	d.deleteDelivered()
+}
+
+func readRecordAt(seg *segment, offset int64) (recordHeader, []byte, error) {
+	f, err := seg.reader()
+	if err != nil {
+		return recordHeader{}, nil, err
+	}
+	return readRecord(f, offset)
+}
+
+// markDelivered records that a record no longer needs to be read, returning
+// false if it was already delivered.
+func (d *diskBuffer) markDelivered(id uint64) bool {
+	if id < d.watermark {
+		return false
+	}
+	if _, exists := d.acked[id]; exists {
+		return false
+	}
+	d.acked[id] = struct{}{}
+	for {
+		if _, exists := d.acked[d.watermark]; !exists {
+			break
+		}
+		delete(d.acked, d.watermark)
+		d.watermark++
+		d.checkpointDirty = true
+	}
+	return true
+}
+
+// deleteDelivered removes segments, other than the active one, where every
+// record has been delivered.
+func (d *diskBuffer) deleteDelivered() {
+	for len(d.segments) > 1 && d.segments[0].nextID <= d.watermark {
+		seg := d.segments[0]
+		seg.closeReader()
+		if err := os.Remove(seg.path); err != nil {
+			d.log.Errorf("Failed to remove segment %v: %v", seg.path, err)
+			return
+		}
+		d.segments = d.segments[1:]
+		d.totalSize -= seg.size
+		if d.readSeg == seg {
+			d.readSeg = d.segments[0]
+			d.readOffset = 0
+		}
+	}
+}
+
+func (d *diskBuffer) ack(ctx context.Context, ref recordRef, id uint64, err error) error {
+	d.cond.L.Lock()
+	defer d.cond.L.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	if err != nil {
+		d.retries = append(d.retries, ref)
+		d.cond.Broadcast()
+		return nil
+	}
+
+	d.markDelivered(id)
+	d.deleteDelivered()
+	if d.checkpointDirty && d.conf.sync == dbSyncAlways {
+		if err := d.writeCheckpoint(true); err != nil {
+			return err
+		}
+	}
+	d.cond.Broadcast()
+	return nil
+}
+
+// ReadBatch reads the next batch from the oldest segment.
+func (d *diskBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		d.cond.Broadcast()
+	}()
+
+	d.cond.L.Lock()
+	defer d.cond.L.Unlock()
+
+	for {
+		if d.closed {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		var ref recordRef
+		if len(d.retries) > 0 {
+			ref = d.retries[0]
+			d.retries = d.retries[1:]
+		} else {
+			d.expireRecords()
+			if d.readOffset >= d.readSeg.size {
+				if d.readSeg != d.activeSegment() {
+					d.readSeg = d.segments[d.segmentIndex(d.readSeg)+1]
+					d.readOffset = 0
+					continue
+				}
+				if d.endOfInput {
+					return nil, nil, service.ErrEndOfBuffer
+				}
+				d.cond.Wait()
+				continue
+			}
+			ref = recordRef{seg: d.readSeg, offset: d.readOffset}
+		}
+
+		h, body, err := readRecordAt(ref.seg, ref.offset)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read record from segment %v: %w", ref.seg.path, err)
+		}
+		if ref.seg == d.readSeg && ref.offset == d.readOffset {
+			d.readOffset += recordHeaderLen + int64(h.length)
+		}
+
+		// Records delivered before a restart may still be present within
+		// segments that were only partially delivered.
+		if h.id < d.watermark {
+			continue
+		}
+		if _, exists := d.acked[h.id]; exists {
+			continue
+		}
+
+		batch, err := readBatch(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse record from segment %v: %w", ref.seg.path, err)
+		}
+		return batch, func(ctx context.Context, err error) error {
+			return d.ack(ctx, ref, h.id, err)
+		}, nil
+	}
+}
+
+// EndOfInput signals to the buffer that the input is finished and therefore
+// once the segments are drained it should close.
+func (d *diskBuffer) EndOfInput() {
+	go func() {
+		d.cond.L.Lock()
+		defer d.cond.L.Unlock()
+
+		d.endOfInput = true
+		d.cond.Broadcast()
+	}()
+}
+
+// Close flushes any pending writes and the checkpoint to disk and closes all
+// segment files.
+func (d *diskBuffer) Close(ctx context.Context) error {
+	d.cond.L.Lock()
+	if d.closed {
+		d.cond.L.Unlock()
+		return nil
+	}
+	d.closed = true
+	close(d.stopBackground)
+
+	var errs []error
+	if d.unsynced && d.conf.sync != dbSyncNever {
+		errs = append(errs, d.writer.Sync())
+	}
+	errs = append(errs, d.writer.Close())
+	if d.checkpointDirty {
+		errs = append(errs, d.writeCheckpoint(d.conf.sync != dbSyncNever))
+	}
+	for _, seg := range d.segments {
+		seg.closeReader()
+	}
+	d.cond.Broadcast()
+	d.cond.L.Unlock()
+
+	d.backgroundDoneWG.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,281 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newDiskBufferForTest(t *testing.T, dir, extra string) *diskBuffer {
+	t.Helper()
+	conf, err := diskBufferSpec().ParseYAML("path: "+dir+"\n"+extra, nil)
+	require.NoError(t, err)
+	d, err := newDiskBufferFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = d.Close(context.Background())
+	})
+	return d
+}
+
+func writeContents(t *testing.T, d *diskBuffer, contents ...string) {
+	t.Helper()
+	for _, c := range contents {
+		msg := service.NewMessage([]byte(c))
+		msg.MetaSetMut("content", c)
+		acked := false
+		require.NoError(t, d.WriteBatch(context.Background(), service.MessageBatch{msg}, func(context.Context, error) error {
+			acked = true
+			return nil
+		}))
+		require.True(t, acked)
+	}
+}
+
+func readContent(t *testing.T, d *diskBuffer) (string, service.AckFunc) {
+	t.Helper()
+	ctx, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	batch, ackFn, err := d.ReadBatch(ctx)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	b, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	v, ok := batch[0].MetaGetMut("content")
+	require.True(t, ok)
+	require.Equal(t, string(b), v)
+	return string(b), ackFn
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+segmentSuffix))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestDiskBufferReadWrite(t *testing.T) {
+	d := newDiskBufferForTest(t, t.TempDir(), "")
+
+	writeContents(t, d, "first", "second", "third")
+
+	content, ackFirst := readContent(t, d)
+	assert.Equal(t, "first", content)
+	content, ackSecond := readContent(t, d)
+	assert.Equal(t, "second", content)
+
+	// Rejected batches are read again before new ones.
+	require.NoError(t, ackSecond(context.Background(), errors.New("nope")))
+	content, ackSecond = readContent(t, d)
+	assert.Equal(t, "second", content)
+
+	content, ackThird := readContent(t, d)
+	assert.Equal(t, "third", content)
+
+	for _, fn := range []service.AckFunc{ackFirst, ackSecond, ackThird} {
+		require.NoError(t, fn(context.Background(), nil))
+	}
+
+	d.EndOfInput()
+	_, _, err := d.ReadBatch(context.Background())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestDiskBufferBatches(t *testing.T) {
+	d := newDiskBufferForTest(t, t.TempDir(), "")
+
+	in := service.MessageBatch{
+		service.NewMessage([]byte("foo")),
+		service.NewMessage([]byte("bar")),
+		service.NewMessage(nil),
+	}
+	in[1].MetaSetMut("num", 5)
+	require.NoError(t, d.WriteBatch(context.Background(), in, func(context.Context, error) error { return nil }))
+
+	out, _, err := d.ReadBatch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+	for i, exp := range []string{"foo", "bar", ""} {
+		b, err := out[i].AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, exp, string(b))
+	}
+	v, ok := out[1].MetaGetMut("num")
+	require.True(t, ok)
+	assert.EqualValues(t, 5, v)
+}
+
+func TestDiskBufferRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	d := newDiskBufferForTest(t, dir, "segment_size: 1B")
+	writeContents(t, d, "first", "second", "third", "fourth")
+
+	for _, exp := range []string{"first", "second", "third"} {
+		content, ackFn := readContent(t, d)
+		assert.Equal(t, exp, content)
+		// Only the first is delivered, the third is delivered out of order
+		// and therefore expected to be delivered again after a restart.
+		if exp != "second" {
+			require.NoError(t, ackFn(context.Background(), nil))
+		}
+	}
+	require.NoError(t, d.Close(context.Background()))
+
+	d = newDiskBufferForTest(t, dir, "segment_size: 1B")
+	writeContents(t, d, "fifth")
+	for _, exp := range []string{"second", "third", "fourth", "fifth"} {
+		content, ackFn := readContent(t, d)
+		assert.Equal(t, exp, content)
+		require.NoError(t, ackFn(context.Background(), nil))
+	}
+
+	// Only the active segment remains once everything is delivered.
+	assert.Len(t, segmentFiles(t, dir), 1)
+}
+
+func TestDiskBufferCorruptTail(t *testing.T) {
+	dir := t.TempDir()
+
+	d := newDiskBufferForTest(t, dir, "")
+	writeContents(t, d, "first", "second")
+	require.NoError(t, d.Close(context.Background()))
+
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 1)
+	f, err := os.OpenFile(files[0], os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("a partially written record"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	d = newDiskBufferForTest(t, dir, "")
+	writeContents(t, d, "third")
+	for _, exp := range []string{"first", "second", "third"} {
+		content, _ := readContent(t, d)
+		assert.Equal(t, exp, content)
+	}
+}
+
+func TestDiskBufferBackpressure(t *testing.T) {
+	d := newDiskBufferForTest(t, t.TempDir(), `
+max_size: 150B
+segment_size: 1MB
+`)
+
+	writeContents(t, d, "first", "second")
+
+	written := make(chan error, 1)
+	go func() {
+		written <- d.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte("third"))}, func(context.Context, error) error {
+			return nil
+		})
+	}()
+
+	_, ackFirst := readContent(t, d)
+	_, ackSecond := readContent(t, d)
+
+	select {
+	case err := <-written:
+		t.Fatalf("write was not blocked: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, ackFirst(context.Background(), nil))
+	require.NoError(t, ackSecond(context.Background(), nil))
+
+	select {
+	case err := <-written:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("write remained blocked")
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	batch, _, err := d.ReadBatch(ctx)
+	require.NoError(t, err)
+	b, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "third", string(b))
+}
+
+func TestDiskBufferWriteCancelled(t *testing.T) {
+	d := newDiskBufferForTest(t, t.TempDir(), `max_size: 50B`)
+	writeContents(t, d, "first")
+
+	ctx, done := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer done()
+	err := d.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("second"))}, func(context.Context, error) error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDiskBufferRetention(t *testing.T) {
+	d := newDiskBufferForTest(t, t.TempDir(), `
+segment_size: 1B
+retention: 1h
+`)
+	now := time.Unix(1000, 0)
+	d.nowFn = func() time.Time { return now }
+
+	writeContents(t, d, "old", "older")
+	now = now.Add(2 * time.Hour)
+	writeContents(t, d, "new")
+
+	content, ackFn := readContent(t, d)
+	assert.Equal(t, "new", content)
+	require.NoError(t, ackFn(context.Background(), nil))
+	assert.Len(t, segmentFiles(t, d.conf.dir), 1)
+}
+
+func TestDiskBufferConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, test := range map[string]struct {
+		yaml   string
+		errStr string
+	}{
+		"bad max size": {
+			yaml:   "max_size: lots",
+			errStr: "failed to parse max_size",
+		},
+		"zero segment size": {
+			yaml:   "segment_size: 0B",
+			errStr: "segment_size must be larger than zero",
+		},
+		"bad sync": {
+			yaml:   "sync: sometimes",
+			errStr: "sync policy not recognised",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := diskBufferSpec().ParseYAML("path: "+dir+"\n"+test.yaml, nil)
+			require.NoError(t, err)
+			_, err = newDiskBufferFromConfig(conf, service.MockResources())
+			require.ErrorContains(t, err, test.errStr)
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// Each record within a segment file consists of a fixed size header followed
+// by the serialized batch:
+//
+//	| length uint32 | crc32 uint32 | id uint64 | timestamp int64 | batch ... |
+//
+// Where the checksum covers everything after itself.
+const recordHeaderLen = 24
+
+const segmentSuffix = ".seg"
+
+var (
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+	errCorruptRecord = errors.New("record is corrupt")
+)
+
+type recordHeader struct {
+	length    uint32
+	id        uint64
+	timestamp time.Time
+}
+
+// newRecord serializes a batch as a record, the ID and timestamp of which must
+// be set with stampRecord before it is written.
+func newRecord(batch service.MessageBatch) ([]byte, error) {
+	buf, err := appendBatch(make([]byte, recordHeaderLen), batch)
+	if err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint32(buf, uint32(len(buf)-recordHeaderLen))
+	return buf, nil
+}
+
+func stampRecord(record []byte, id uint64, ts time.Time) {
+	binary.BigEndian.PutUint64(record[8:], id)
+	binary.BigEndian.PutUint64(record[16:], uint64(ts.UnixNano()))
+	binary.BigEndian.PutUint32(record[4:], crc32.Checksum(record[8:], crcTable))
+}
+
+// readRecord reads the record at a given offset of a segment file, returning
+// its header and batch contents.
+func readRecord(r io.ReaderAt, offset int64) (recordHeader, []byte, error) {
+	var h recordHeader
+
+	header := make([]byte, recordHeaderLen)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return h, nil, err
+	}
+	h.length = binary.BigEndian.Uint32(header)
+	h.id = binary.BigEndian.Uint64(header[8:])
+	h.timestamp = time.Unix(0, int64(binary.BigEndian.Uint64(header[16:])))
+
+	body := make([]byte, h.length)
+	if _, err := r.ReadAt(body, offset+recordHeaderLen); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return h, nil, err
+	}
+
+	crc := crc32.Update(crc32.Checksum(header[8:], crcTable), crcTable, body)
+	if crc != binary.BigEndian.Uint32(header[4:]) {
+		return h, nil, errCorruptRecord
+	}
+	return h, body, nil
+}
+
+func appendBatch(buf []byte, batch service.MessageBatch) ([]byte, error) {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(batch)))
+	for _, msg := range batch {
+		metaObj := map[string]any{}
+		_ = msg.MetaWalkMut(func(key string, value any) error {
+			metaObj[key] = value
+			return nil
+		})
+		metaBytes, err := msgpack.Marshal(metaObj)
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(metaBytes)))
+		buf = append(buf, metaBytes...)
+
+		msgBytes, err := msg.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(msgBytes)))
+		buf = append(buf, msgBytes...)
+	}
+	return buf, nil
+}
+
+func readBatch(b []byte) (service.MessageBatch, error) {
+	next := func() ([]byte, error) {
+		if len(b) < 4 {
+			return nil, errCorruptRecord
+		}
+		l := binary.BigEndian.Uint32(b)
+		if uint64(len(b)-4) < uint64(l) {
+			return nil, errCorruptRecord
+		}
+		v := b[4 : 4+l]
+		b = b[4+l:]
+		return v, nil
+	}
+
+	if len(b) < 4 {
+		return nil, errCorruptRecord
+	}
+	count := binary.BigEndian.Uint32(b)
+	b = b[4:]
+
+	batch := make(service.MessageBatch, 0, count)
+	for i := uint32(0); i < count; i++ {
+		metaBytes, err := next()
+		if err != nil {
+			return nil, err
+		}
+		content, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		msg := service.NewMessage(content)
+		metaObj := map[string]any{}
+		if err := msgpack.Unmarshal(metaBytes, &metaObj); err != nil {
+			return nil, err
+		}
+		for k, v := range metaObj {
+			msg.MetaSetMut(k, v)
+		}
+		batch = append(batch, msg)
+	}
+	return batch, nil
+}
+
+//------------------------------------------------------------------------------
+
+// segment is an append-only file of records with sequential IDs.
+type segment struct {
+	seq  uint64
+	path string
+
+	firstID   uint64
+	nextID    uint64
+	size      int64
+	lastWrite time.Time
+
+	readFile *os.File
+}
+
+func segmentPath(dir string, seq uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%v", seq, segmentSuffix))
+}
+
+// listSegments returns the sequence numbers of the segment files within a
+// directory in ascending order.
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var seqs []uint64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	// Names are zero padded and therefore already sorted.
+	return seqs, nil
+}
+
+// scanSegment reads the records of an existing segment file in order to
+// determine its bounds. Any trailing data that cannot be read as a valid
+// record, which is expected after a crash mid-write, is truncated.
+func scanSegment(dir string, seq uint64) (*segment, int64, error) {
+	s := &segment{seq: seq, path: segmentPath(dir, seq)}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	first := true
+	for s.size < info.Size() {
+		h, _, err := readRecord(f, s.size)
+		if err != nil {
+			break
+		}
+		if first {
+			s.firstID = h.id
+			first = false
+		}
+		s.nextID = h.id + 1
+		s.lastWrite = h.timestamp
+		s.size += recordHeaderLen + int64(h.length)
+	}
+
+	truncated := info.Size() - s.size
+	if truncated > 0 {
+		if err := os.Truncate(s.path, s.size); err != nil {
+			return nil, 0, err
+		}
+	}
+	return s, truncated, nil
+}
+
+func (s *segment) reader() (*os.File, error) {
+	if s.readFile == nil {
+		f, err := os.Open(s.path)
+		if err != nil {
+			return nil, err
+		}
+		s.readFile = f
+	}
+	return s.readFile, nil
+}
+
+func (s *segment) closeReader() {
+	if s.readFile != nil {
+		_ = s.readFile.Close()
+		s.readFile = nil
+	}
+}
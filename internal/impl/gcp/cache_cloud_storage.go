@@ -24,19 +24,35 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/objectcache"
 )
 
 func gcpCloudStorageCacheConfig() *service.ConfigSpec {
 	spec := service.NewConfigSpec().
 		Beta().
 		Summary(`Use a Google Cloud Storage bucket as a cache.`).
-		Description(`It is not possible to atomically upload cloud storage objects exclusively when the target does not already exist, therefore this cache is not suitable for deduplication.`).
+		Description(`It is not possible to atomically upload cloud storage objects exclusively when the target does not already exist, therefore this cache is not suitable for deduplication.
+
+=== TTL
+
+Items written with a TTL, either from the ` + "`default_ttl`" + ` field or from the TTL of the cache operation, have their expiry time stored within the ` + "`" + objectcache.ExpiresAtKey + "`" + ` object metadata key. Expired items are treated as missing, but are not removed from the bucket, and therefore it is recommended to also configure a lifecycle rule on the bucket that removes stale objects.
+
+=== Local cache
+
+A local in-process layer can be enabled with the ` + "`local_cache`" + ` field, which is useful for large datasets that are read frequently, such as enrichment tables.`).
 		Field(service.NewStringField("bucket").
 			Description("The Google Cloud Storage bucket to store items in.")).
 		Field(service.NewStringField("content_type").
 			Description("Optional field to explicitly set the Content-Type.").Optional()).
 		Field(service.NewStringField("credentials_json").
-			Description("An optional field to set Google Service Account Credentials json.").Secret().Default(""))
+			Description("An optional field to set Google Service Account Credentials json.").Secret().Default("")).
+		Field(service.NewDurationField("default_ttl").
+			Description("An optional default TTL to set for items, calculated from the moment the item is cached.").
+			Optional().
+			Advanced().
+			Version("4.48.0")).
+		Field(objectcache.LocalCacheField())
 
 	return spec
 }
@@ -45,7 +61,11 @@ func init() {
 	err := service.RegisterCache(
 		"gcp_cloud_storage", gcpCloudStorageCacheConfig(),
 		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Cache, error) {
-			return newGcpCloudStorageCacheFromConfig(conf)
+			c, err := newGcpCloudStorageCacheFromConfig(conf)
+			if err != nil {
+				return nil, err
+			}
+			return objectcache.WrapFromParsed(conf, c)
 		})
 	if err != nil {
 		panic(err)
@@ -66,6 +86,15 @@ func newGcpCloudStorageCacheFromConfig(parsedConf *service.ParsedConfig) (*gcpCl
 		}
 	}
 
+	var ttl *time.Duration
+	if parsedConf.Contains("default_ttl") {
+		ttlTmp, err := parsedConf.FieldDuration("default_ttl")
+		if err != nil {
+			return nil, err
+		}
+		ttl = &ttlTmp
+	}
+
 	var opt []option.ClientOption
 	if parsedConf.Contains("credentials_json") {
 		credsJSON, err := parsedConf.FieldString("credentials_json")
@@ -86,6 +115,7 @@ func newGcpCloudStorageCacheFromConfig(parsedConf *service.ParsedConfig) (*gcpCl
 	return &gcpCloudStorageCache{
 		bucketHandle: client.Bucket(bucket),
 		contentType:  contentType,
+		ttl:          ttl,
 	}, nil
 }
 
@@ -94,10 +124,16 @@ func newGcpCloudStorageCacheFromConfig(parsedConf *service.ParsedConfig) (*gcpCl
 type gcpCloudStorageCache struct {
 	bucketHandle *storage.BucketHandle
 	contentType  string
+	ttl          *time.Duration
 }
 
 func (c *gcpCloudStorageCache) Get(ctx context.Context, key string) ([]byte, error) {
-	reader, err := c.bucketHandle.Object(key).NewReader(ctx)
+	objectHandle := c.bucketHandle.Object(key)
+
+	// Object metadata is not returned by readers and so the attributes are
+	// read first, the generation is then pinned so that the data read always
+	// matches the expiry that was checked.
+	attrs, err := objectHandle.Attrs(ctx)
 	if err != nil {
 		// Check if the object does not exist and return the proper error
 		if errors.Is(err, storage.ErrObjectNotExist) {
@@ -105,6 +141,17 @@ func (c *gcpCloudStorageCache) Get(ctx context.Context, key string) ([]byte, err
 		}
 		return nil, err
 	}
+	if objectcache.Expired(time.Now(), attrs.Metadata[objectcache.ExpiresAtKey]) {
+		return nil, service.ErrKeyNotFound
+	}
+
+	reader, err := objectHandle.Generation(attrs.Generation).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, service.ErrKeyNotFound
+		}
+		return nil, err
+	}
 
 	defer reader.Close()
 
@@ -116,37 +163,37 @@ func (c *gcpCloudStorageCache) Get(ctx context.Context, key string) ([]byte, err
 	return data, nil
 }
 
-func (c *gcpCloudStorageCache) Set(ctx context.Context, key string, value []byte, _ *time.Duration) error {
-	writer := c.bucketHandle.Object(key).NewWriter(ctx)
-
-	if c.contentType != "" {
-		writer.ContentType = c.contentType
-	}
-
-	_, err := writer.Write(value)
-	if err != nil {
-		return err
-	}
-
-	return writer.Close()
+func (c *gcpCloudStorageCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	return c.write(ctx, c.bucketHandle.Object(key), value, ttl)
 }
 
-func (c *gcpCloudStorageCache) Add(ctx context.Context, key string, value []byte, _ *time.Duration) error {
+func (c *gcpCloudStorageCache) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
 	objectHandle := c.bucketHandle.Object(key)
 
 	// Check if the object already exists
-	_, err := objectHandle.Attrs(ctx)
-	if err == nil {
+	attrs, err := objectHandle.Attrs(ctx)
+	if err == nil && !objectcache.Expired(time.Now(), attrs.Metadata[objectcache.ExpiresAtKey]) {
 		return service.ErrKeyAlreadyExists
 	}
 
+	return c.write(ctx, objectHandle, value, ttl)
+}
+
+func (c *gcpCloudStorageCache) write(ctx context.Context, objectHandle *storage.ObjectHandle, value []byte, ttl *time.Duration) error {
 	writer := objectHandle.NewWriter(ctx)
 
 	if c.contentType != "" {
 		writer.ContentType = c.contentType
 	}
 
-	_, err = writer.Write(value)
+	if ttl == nil {
+		ttl = c.ttl
+	}
+	if expiresAt, ok := objectcache.ExpiresAt(time.Now(), ttl); ok {
+		writer.Metadata = map[string]string{objectcache.ExpiresAtKey: expiresAt}
+	}
+
+	_, err := writer.Write(value)
 	if err != nil {
 		return err
 	}
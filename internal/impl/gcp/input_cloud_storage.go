@@ -43,6 +43,7 @@ type csiConfig struct {
 	Prefix          string
 	CredentialsJSON string
 	DeleteObjects   bool
+	PubSub          csiPubSubConfig
 	Codec           codec.DeprecatedFallbackCodec
 }
 
@@ -62,6 +63,9 @@ func csiConfigFromParsed(pConf *service.ParsedConfig) (conf csiConfig, err error
 	if conf.DeleteObjects, err = pConf.FieldBool(csiFieldDeleteObjects); err != nil {
 		return
 	}
+	if conf.PubSub, err = csiPubSubConfigFromParsed(pConf); err != nil {
+		return
+	}
 	return
 }
 
@@ -70,8 +74,14 @@ func csiSpec() *service.ConfigSpec {
 		Beta().
 		Version("3.43.0").
 		Categories("Services", "GCP").
-		Summary(`Downloads objects within a Google Cloud Storage bucket, optionally filtered by a prefix.`).
+		Summary(`Downloads objects within a Google Cloud Storage bucket, optionally filtered by a prefix, either by walking the objects in the bucket or by streaming upload notifications from Pub/Sub.`).
 		Description(`
+== Stream objects on upload with Pub/Sub
+
+Rather than walking the objects of a bucket and then shutting down, the input can consume https://cloud.google.com/storage/docs/pubsub-notifications[Pub/Sub notifications^] of a bucket by configuring a `+"`pubsub.subscription`"+` to its notification topic, and download objects as they are created. Notifications of `+"`OBJECT_FINALIZE`"+` events are consumed, and notifications of other events, other buckets, or objects outside of the `+"`prefix`"+` are acknowledged and ignored.
+
+A notification is only acknowledged once all messages of its object have been processed, or when its object no longer exists, and is otherwise nacked in order to be redelivered, which ensures at-least-once delivery. The acknowledgement deadline of notifications is extended automatically while their objects are processed.
+
 == Metadata
 
 This input adds the following metadata fields to each message:
@@ -108,6 +118,7 @@ By default Redpanda Connect will use a shared credentials file when connecting t
 				Description("Whether to delete downloaded objects from the bucket once they are processed.").
 				Advanced().
 				Default(false),
+			csiPubSubField(),
 		)
 }
 
@@ -123,7 +134,14 @@ func init() {
 			if rdr, err = newGCPCloudStorageInput(conf, res); err != nil {
 				return nil, err
 			}
-			return service.AutoRetryNacksBatched(rdr), nil
+
+			// When consuming Pub/Sub notifications nacks are propagated
+			// upstream, otherwise wrap our reader within a preserver in order
+			// to retry indefinitely.
+			if conf.PubSub.Subscription == "" {
+				rdr = service.AutoRetryNacksBatched(rdr)
+			}
+			return rdr, nil
 		})
 	if err != nil {
 		panic(err)
@@ -134,6 +152,11 @@ const (
 	maxGCPCloudStorageListObjectsResults = 100
 )
 
+type gcpCloudStorageObjectTargetReader interface {
+	Pop(ctx context.Context) (*gcpCloudStorageObjectTarget, error)
+	Close(ctx context.Context) error
+}
+
 type gcpCloudStorageObjectTarget struct {
 	key   string
 	ackFn func(context.Context, error) error
@@ -253,7 +276,7 @@ type gcpCloudStorageInput struct {
 	conf csiConfig
 
 	objectScannerCtor codec.DeprecatedFallbackCodec
-	keyReader         *gcpCloudStorageTargetReader
+	keyReader         gcpCloudStorageObjectTargetReader
 
 	objectMut sync.Mutex
 	object    *gcpCloudStoragePendingObject
@@ -289,8 +312,22 @@ func (g *gcpCloudStorageInput) Connect(ctx context.Context) error {
 		return err
 	}
 
-	g.keyReader, err = newGCPCloudStorageTargetReader(ctx, g.conf, g.log, g.client.Bucket(g.conf.Bucket))
-	return err
+	bucket := g.client.Bucket(g.conf.Bucket)
+	if g.conf.PubSub.Subscription != "" {
+		var r *gcpCloudStoragePubSubTargetReader
+		if r, err = newGCPCloudStoragePubSubTargetReader(g.conf, g.log, bucket); err != nil {
+			return err
+		}
+		g.keyReader = r
+		return nil
+	}
+
+	var r *gcpCloudStorageTargetReader
+	if r, err = newGCPCloudStorageTargetReader(ctx, g.conf, g.log, bucket); err != nil {
+		return err
+	}
+	g.keyReader = r
+	return nil
 }
 
 func (g *gcpCloudStorageInput) getObjectTarget(ctx context.Context) (*gcpCloudStoragePendingObject, error) {
@@ -404,6 +441,11 @@ func (g *gcpCloudStorageInput) Close(ctx context.Context) (err error) {
 		g.object = nil
 	}
 
+	if g.keyReader != nil {
+		_ = g.keyReader.Close(ctx)
+		g.keyReader = nil
+	}
+
 	if err == nil && g.client != nil {
 		err = g.client.Close()
 		g.client = nil
@@ -0,0 +1,224 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// Cloud Storage Input Pub/Sub Fields
+	csiFieldPubSub                       = "pubsub"
+	csiPubSubFieldProject                = "project"
+	csiPubSubFieldSubscription           = "subscription"
+	csiPubSubFieldEndpoint               = "endpoint"
+	csiPubSubFieldMaxOutstandingMessages = "max_outstanding_messages"
+)
+
+func csiPubSubField() *service.ConfigField {
+	return service.NewObjectField(csiFieldPubSub,
+		service.NewStringField(csiPubSubFieldProject).
+			Description("The project ID of the subscription.").
+			Default(""),
+		service.NewStringField(csiPubSubFieldSubscription).
+			Description("An optional subscription to a Pub/Sub notification topic of the bucket. When specified notifications received from the subscription control which objects are downloaded.").
+			Default(""),
+		service.NewStringField(csiPubSubFieldEndpoint).
+			Description("An optional endpoint to override the default of `pubsub.googleapis.com:443`.").
+			Default("").
+			Advanced(),
+		service.NewIntField(csiPubSubFieldMaxOutstandingMessages).
+			Description("The maximum number of notifications to hold in memory at once, which bounds the number of objects being processed.").
+			Default(100).
+			Advanced(),
+	).
+		Description("Consume Pub/Sub notifications in order to trigger object downloads. See <<stream-objects-on-upload-with-pubsub>>.").
+		Version("4.48.0")
+}
+
+type csiPubSubConfig struct {
+	Project                string
+	Subscription           string
+	Endpoint               string
+	MaxOutstandingMessages int
+}
+
+func csiPubSubConfigFromParsed(pConf *service.ParsedConfig) (conf csiPubSubConfig, err error) {
+	pConf = pConf.Namespace(csiFieldPubSub)
+	if conf.Project, err = pConf.FieldString(csiPubSubFieldProject); err != nil {
+		return
+	}
+	if conf.Subscription, err = pConf.FieldString(csiPubSubFieldSubscription); err != nil {
+		return
+	}
+	if conf.Endpoint, err = pConf.FieldString(csiPubSubFieldEndpoint); err != nil {
+		return
+	}
+	if conf.MaxOutstandingMessages, err = pConf.FieldInt(csiPubSubFieldMaxOutstandingMessages); err != nil {
+		return
+	}
+	if conf.Subscription != "" && conf.Project == "" {
+		err = errors.New("a pubsub.project must be specified along with a pubsub.subscription")
+	}
+	return
+}
+
+//------------------------------------------------------------------------------
+
+// gcsNotification is a Pub/Sub notification of a change to an object, with
+// functions for acknowledging it.
+type gcsNotification struct {
+	attributes map[string]string
+	ack        func()
+	nack       func()
+}
+
+// key returns the key of the object which was created by a notification, and
+// false if the notification isn't of a created object within the bucket and
+// prefix.
+func (n gcsNotification) key(bucket, prefix string) (string, bool) {
+	if n.attributes["eventType"] != "OBJECT_FINALIZE" || n.attributes["bucketId"] != bucket {
+		return "", false
+	}
+	key := n.attributes["objectId"]
+	if key == "" || !strings.HasPrefix(key, prefix) {
+		return "", false
+	}
+	return key, true
+}
+
+// ackFn returns a function which acknowledges the notification once its object
+// is processed, or nacks it in order for it to be redelivered. Notifications
+// of objects which no longer exist are acknowledged.
+func (n gcsNotification) ackFn(log *service.Logger, key string) service.AckFunc {
+	return func(_ context.Context, err error) error {
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			n.nack()
+			return nil
+		}
+		if err != nil {
+			log.Warnf("Skipping notification of object %v which no longer exists", key)
+		}
+		n.ack()
+		return nil
+	}
+}
+
+type gcpCloudStoragePubSubTargetReader struct {
+	conf   csiConfig
+	log    *service.Logger
+	bucket *storage.BucketHandle
+
+	notifications chan gcsNotification
+	closeFn       context.CancelFunc
+	closeOnce     sync.Once
+}
+
+func newGCPCloudStoragePubSubTargetReader(
+	conf csiConfig,
+	log *service.Logger,
+	bucket *storage.BucketHandle,
+) (*gcpCloudStoragePubSubTargetReader, error) {
+	var opt []option.ClientOption
+	if strings.TrimSpace(conf.PubSub.Endpoint) != "" {
+		opt = []option.ClientOption{option.WithEndpoint(conf.PubSub.Endpoint)}
+	}
+
+	opt, err := getClientOptionWithCredential(conf.CredentialsJSON, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := pubsub.NewClient(context.Background(), conf.PubSub.Project, opt...)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := client.Subscription(conf.PubSub.Subscription)
+	sub.ReceiveSettings.MaxOutstandingMessages = conf.PubSub.MaxOutstandingMessages
+
+	r := newGCPCloudStorageNotificationReader(conf, log, bucket)
+	subCtx, cancel := context.WithCancel(context.Background())
+	r.closeFn = cancel
+
+	go func() {
+		defer client.Close()
+		defer close(r.notifications)
+
+		rerr := sub.Receive(subCtx, func(ctx context.Context, m *pubsub.Message) {
+			select {
+			case r.notifications <- gcsNotification{attributes: m.Attributes, ack: m.Ack, nack: m.Nack}:
+			case <-ctx.Done():
+				m.Nack()
+			}
+		})
+		if rerr != nil && !errors.Is(rerr, context.Canceled) {
+			log.Errorf("Subscription error: %v", rerr)
+		}
+	}()
+	return r, nil
+}
+
+func newGCPCloudStorageNotificationReader(
+	conf csiConfig,
+	log *service.Logger,
+	bucket *storage.BucketHandle,
+) *gcpCloudStoragePubSubTargetReader {
+	return &gcpCloudStoragePubSubTargetReader{
+		conf:          conf,
+		log:           log,
+		bucket:        bucket,
+		notifications: make(chan gcsNotification),
+		closeFn:       func() {},
+	}
+}
+
+func (r *gcpCloudStoragePubSubTargetReader) Pop(ctx context.Context) (*gcpCloudStorageObjectTarget, error) {
+	for {
+		var n gcsNotification
+		var open bool
+		select {
+		case n, open = <-r.notifications:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !open {
+			return nil, service.ErrNotConnected
+		}
+
+		key, ok := n.key(r.conf.Bucket, r.conf.Prefix)
+		if !ok {
+			// Notifications of other events, such as deletions, are dropped.
+			n.ack()
+			continue
+		}
+		ackFn := deleteGCPCloudStorageObjectAckFn(r.bucket, key, r.conf.DeleteObjects, n.ackFn(r.log, key))
+		return newGCPCloudStorageObjectTarget(key, ackFn), nil
+	}
+}
+
+func (r *gcpCloudStoragePubSubTargetReader) Close(context.Context) error {
+	r.closeOnce.Do(r.closeFn)
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestCloudStoragePubSubConfig(t *testing.T) {
+	spec := service.NewConfigSpec().Field(csiPubSubField())
+
+	pConf, err := spec.ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+	conf, err := csiPubSubConfigFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, "", conf.Subscription)
+
+	pConf, err = spec.ParseYAML(`
+pubsub:
+  subscription: foo
+`, nil)
+	require.NoError(t, err)
+	_, err = csiPubSubConfigFromParsed(pConf)
+	require.Error(t, err)
+}
+
+func TestCloudStoragePubSubTargets(t *testing.T) {
+	r := newGCPCloudStorageNotificationReader(csiConfig{
+		Bucket: "foo",
+		Prefix: "bar/",
+	}, service.MockResources().Logger(), nil)
+
+	acks := make(chan string, 10)
+	notify := func(id string, attrs map[string]string) {
+		r.notifications <- gcsNotification{
+			attributes: attrs,
+			ack:        func() { acks <- "ack " + id },
+			nack:       func() { acks <- "nack " + id },
+		}
+	}
+	created := func(bucket, key string) map[string]string {
+		return map[string]string{"eventType": "OBJECT_FINALIZE", "bucketId": bucket, "objectId": key}
+	}
+
+	go func() {
+		notify("a", map[string]string{"eventType": "OBJECT_DELETE", "bucketId": "foo", "objectId": "bar/a"})
+		notify("b", created("baz", "bar/b"))
+		notify("c", created("foo", "c"))
+		notify("d", created("foo", "bar/d"))
+		notify("e", created("foo", "bar/e"))
+		notify("f", created("foo", "bar/f"))
+	}()
+
+	pop := func() *gcpCloudStorageObjectTarget {
+		ctx, done := context.WithTimeout(context.Background(), time.Second)
+		defer done()
+		target, err := r.Pop(ctx)
+		require.NoError(t, err)
+		return target
+	}
+
+	target := pop()
+	assert.Equal(t, "bar/d", target.key)
+	assert.Equal(t, "ack a", <-acks)
+	assert.Equal(t, "ack b", <-acks)
+	assert.Equal(t, "ack c", <-acks)
+
+	require.NoError(t, target.ackFn(context.Background(), nil))
+	assert.Equal(t, "ack d", <-acks)
+
+	target = pop()
+	assert.Equal(t, "bar/e", target.key)
+	require.NoError(t, target.ackFn(context.Background(), errors.New("nope")))
+	assert.Equal(t, "nack e", <-acks)
+
+	target = pop()
+	assert.Equal(t, "bar/f", target.key)
+	require.NoError(t, target.ackFn(context.Background(), fmt.Errorf("failed: %w", storage.ErrObjectNotExist)))
+	assert.Equal(t, "ack f", <-acks)
+
+	close(r.notifications)
+	_, err := r.Pop(context.Background())
+	require.ErrorIs(t, err, service.ErrNotConnected)
+}
@@ -17,8 +17,10 @@ package gcp
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"google.golang.org/api/option"
@@ -35,6 +37,10 @@ const (
 	pbiFieldMaxOutstandingMessages = "max_outstanding_messages"
 	pbiFieldMaxOutstandingBytes    = "max_outstanding_bytes"
 	pbiFieldSync                   = "sync"
+	pbiFieldExactlyOnce            = "exactly_once_delivery"
+	pbiFieldMaxExtension           = "max_extension"
+	pbiFieldMinExtensionPeriod     = "min_extension_period"
+	pbiFieldMaxExtensionPeriod     = "max_extension_period"
 	pbiFieldCreateSub              = "create_subscription"
 	pbiFieldCreateSubEnabled       = "enabled"
 	pbiFieldCreateSubTopicID       = "topic"
@@ -48,6 +54,10 @@ type pbiConfig struct {
 	MaxOutstandingMessages int
 	MaxOutstandingBytes    int
 	Sync                   bool
+	ExactlyOnce            bool
+	MaxExtension           time.Duration
+	MinExtensionPeriod     time.Duration
+	MaxExtensionPeriod     time.Duration
 	CreateEnabled          bool
 	CreateTopicID          string
 }
@@ -74,6 +84,18 @@ func pbiConfigFromParsed(pConf *service.ParsedConfig) (conf pbiConfig, err error
 	if conf.Sync, err = pConf.FieldBool(pbiFieldSync); err != nil {
 		return
 	}
+	if conf.ExactlyOnce, err = pConf.FieldBool(pbiFieldExactlyOnce); err != nil {
+		return
+	}
+	if conf.MaxExtension, err = pConf.FieldDuration(pbiFieldMaxExtension); err != nil {
+		return
+	}
+	if conf.MinExtensionPeriod, err = pConf.FieldDuration(pbiFieldMinExtensionPeriod); err != nil {
+		return
+	}
+	if conf.MaxExtensionPeriod, err = pConf.FieldDuration(pbiFieldMaxExtensionPeriod); err != nil {
+		return
+	}
 	if pConf.Contains(pbiFieldCreateSub) {
 		createConf := pConf.Namespace(pbiFieldCreateSub)
 		if conf.CreateEnabled, err = createConf.FieldBool(pbiFieldCreateSubEnabled); err != nil {
@@ -98,11 +120,18 @@ For information on how to set up credentials see https://cloud.google.com/docs/a
 
 This input adds the following metadata fields to each message:
 
+- gcp_pubsub_message_id - The ID of the message assigned by PubSub.
 - gcp_pubsub_publish_time_unix - The time at which the message was published to the topic.
 - gcp_pubsub_delivery_attempt - When dead lettering is enabled, this is set to the number of times PubSub has attempted to deliver a message.
 - All message attributes
 
 You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+
+== Exactly once delivery
+
+When the subscription has exactly once delivery enabled, `+"`exactly_once_delivery`"+` should also be enabled so that acknowledgements are confirmed by PubSub before they are considered successful. Acknowledgements that fail, for example because the lease of the message expired, are reported as errors, and the message is redelivered by PubSub.
+
+While a message is being processed its lease is extended automatically for up to `+"`max_extension`"+`, and the period of each extension can be bounded with `+"`min_extension_period`"+` and `+"`max_extension_period`"+`. When exactly once delivery is enabled PubSub requires a minimum extension period of one minute by default, in order to reduce the chance of leases expiring.
 `).
 		Fields(
 			service.NewStringField(pbiFieldProjectID).
@@ -121,6 +150,25 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 			service.NewBoolField(pbiFieldSync).
 				Description("Enable synchronous pull mode.").
 				Default(false),
+			service.NewBoolField(pbiFieldExactlyOnce).
+				Description("Whether to wait for acknowledgements to be confirmed by PubSub, which should be enabled for subscriptions with exactly once delivery. When `create_subscription` is enabled the subscription is created with exactly once delivery.").
+				Version("4.48.0").
+				Default(false),
+			service.NewDurationField(pbiFieldMaxExtension).
+				Description("The maximum period of time to extend the lease of a message for while it is being processed.").
+				Version("4.48.0").
+				Advanced().
+				Default("60m"),
+			service.NewDurationField(pbiFieldMinExtensionPeriod).
+				Description("The minimum period to extend the lease of a message by. When zero the period is determined by PubSub.").
+				Version("4.48.0").
+				Advanced().
+				Default("0s"),
+			service.NewDurationField(pbiFieldMaxExtensionPeriod).
+				Description("The maximum period to extend the lease of a message by. When zero the period is determined by PubSub.").
+				Version("4.48.0").
+				Advanced().
+				Default("0s"),
 			service.NewIntField(pbiFieldMaxOutstandingMessages).
 				Description("The maximum number of outstanding pending messages to be consumed at a given time.").
 				Default(1000), // pubsub.DefaultReceiveSettings.MaxOutstandingMessages)
@@ -171,7 +219,10 @@ func createSubscription(conf pbiConfig, client *pubsub.Client, log *service.Logg
 	}
 
 	log.Infof("Creating subscription '%v' on topic '%v'\n", conf.SubscriptionID, conf.CreateTopicID)
-	_, err = client.CreateSubscription(context.Background(), conf.SubscriptionID, pubsub.SubscriptionConfig{Topic: client.Topic(conf.CreateTopicID)})
+	_, err = client.CreateSubscription(context.Background(), conf.SubscriptionID, pubsub.SubscriptionConfig{
+		Topic:                     client.Topic(conf.CreateTopicID),
+		EnableExactlyOnceDelivery: conf.ExactlyOnce,
+	})
 	if err != nil {
 		log.Errorf("Error creating subscription %v", err)
 	}
@@ -233,6 +284,9 @@ func (c *gcpPubSubReader) Connect(ignored context.Context) error {
 	sub.ReceiveSettings.MaxOutstandingMessages = c.conf.MaxOutstandingMessages
 	sub.ReceiveSettings.MaxOutstandingBytes = c.conf.MaxOutstandingBytes
 	sub.ReceiveSettings.Synchronous = c.conf.Sync
+	sub.ReceiveSettings.MaxExtension = c.conf.MaxExtension
+	sub.ReceiveSettings.MinExtensionPeriod = c.conf.MinExtensionPeriod
+	sub.ReceiveSettings.MaxExtensionPeriod = c.conf.MaxExtensionPeriod
 
 	subCtx, cancel := context.WithCancel(context.Background())
 	msgsChan := make(chan *pubsub.Message, 1)
@@ -287,6 +341,7 @@ func (c *gcpPubSubReader) Read(ctx context.Context) (*service.Message, service.A
 	for k, v := range gmsg.Attributes {
 		part.MetaSetMut(k, v)
 	}
+	part.MetaSetMut("gcp_pubsub_message_id", gmsg.ID)
 	part.MetaSetMut("gcp_pubsub_publish_time_unix", gmsg.PublishTime.Unix())
 
 	if gmsg.DeliveryAttempt != nil {
@@ -294,10 +349,27 @@ func (c *gcpPubSubReader) Read(ctx context.Context) (*service.Message, service.A
 	}
 
 	return part, func(ctx context.Context, res error) error {
+		if !c.conf.ExactlyOnce {
+			if res != nil {
+				gmsg.Nack()
+			} else {
+				gmsg.Ack()
+			}
+			return nil
+		}
+
+		var result *pubsub.AckResult
 		if res != nil {
-			gmsg.Nack()
+			result = gmsg.NackWithResult()
 		} else {
-			gmsg.Ack()
+			result = gmsg.AckWithResult()
+		}
+		status, err := result.Get(ctx)
+		if err == nil && status != pubsub.AcknowledgeStatusSuccess {
+			err = fmt.Errorf("status %v", status)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to confirm acknowledgement of message %v: %w", gmsg.ID, err)
 		}
 		return nil
 	}, nil
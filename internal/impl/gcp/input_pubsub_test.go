@@ -0,0 +1,100 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestPubSubInputExactlyOnce(t *testing.T) {
+	srv := pstest.NewServer()
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+	t.Setenv("PUBSUB_EMULATOR_HOST", srv.Addr)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	client, err := pubsub.NewClient(ctx, "test-project")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	topic, err := client.CreateTopic(ctx, "test-topic")
+	require.NoError(t, err)
+
+	pConf, err := pbiSpec().ParseYAML(`
+project: test-project
+subscription: test-sub
+exactly_once_delivery: true
+create_subscription:
+  enabled: true
+  topic: test-topic
+`, nil)
+	require.NoError(t, err)
+	conf, err := pbiConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	reader, err := newGCPPubSubReader(conf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = reader.Close(context.Background())
+	})
+
+	subConf, err := client.Subscription("test-sub").Config(ctx)
+	require.NoError(t, err)
+	assert.True(t, subConf.EnableExactlyOnceDelivery)
+
+	msgID, err := topic.Publish(ctx, &pubsub.Message{
+		Data:       []byte("hello world"),
+		Attributes: map[string]string{"foo": "bar"},
+	}).Get(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, reader.Connect(ctx))
+	msg, ackFn, err := reader.Read(ctx)
+	require.NoError(t, err)
+
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+
+	v, ok := msg.MetaGet("gcp_pubsub_message_id")
+	require.True(t, ok)
+	assert.Equal(t, msgID, v)
+	v, ok = msg.MetaGet("foo")
+	require.True(t, ok)
+	assert.Equal(t, "bar", v)
+
+	require.NoError(t, ackFn(ctx, nil))
+	assert.Eventually(t, func() bool {
+		for _, m := range srv.Messages() {
+			if m.ID == msgID {
+				return m.Acks == 1
+			}
+		}
+		return false
+	}, time.Second*5, time.Millisecond*50)
+}
@@ -28,6 +28,8 @@ import (
 	"google.golang.org/api/option"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/rolling"
 )
 
 const (
@@ -37,6 +39,7 @@ const (
 	csoFieldContentType     = "content_type"
 	csoFieldContentEncoding = "content_encoding"
 	csoFieldChunkSize       = "chunk_size"
+	csoFieldChunkThreshold  = "chunk_threshold"
 	csoFieldMaxInFlight     = "max_in_flight"
 	csoFieldBatching        = "batching"
 	csoFieldCollisionMode   = "collision_mode"
@@ -62,9 +65,11 @@ type csoConfig struct {
 	ContentType     *service.InterpolatedString
 	ContentEncoding *service.InterpolatedString
 	ChunkSize       int
+	ChunkThreshold  int
 	CollisionMode   string
 	Timeout         time.Duration
 	CredentialsJSON string
+	Rolling         *rolling.Config
 }
 
 func csoConfigFromParsed(pConf *service.ParsedConfig) (conf csoConfig, err error) {
@@ -83,6 +88,9 @@ func csoConfigFromParsed(pConf *service.ParsedConfig) (conf csoConfig, err error
 	if conf.ChunkSize, err = pConf.FieldInt(csoFieldChunkSize); err != nil {
 		return
 	}
+	if conf.ChunkThreshold, err = pConf.FieldInt(csoFieldChunkThreshold); err != nil {
+		return
+	}
 	if conf.CollisionMode, err = pConf.FieldString(csoFieldCollisionMode); err != nil {
 		return
 	}
@@ -92,6 +100,9 @@ func csoConfigFromParsed(pConf *service.ParsedConfig) (conf csoConfig, err error
 	if conf.CredentialsJSON, err = pConf.FieldString(csoFieldCredentialsJSON); err != nil {
 		return
 	}
+	if conf.Rolling, err = rolling.ConfigFromParsed(pConf); err != nil {
+		return
+	}
 	return
 }
 
@@ -145,7 +156,31 @@ output:
       processors:
         - archive:
             format: json_array
-`+"```"+``+service.OutputPerformanceDocs(true, true)).
+`+"```"+`
+
+== Rolling files
+
+When `+"`rolling.enabled`"+` is set messages are accumulated into files which are uploaded as single objects once they are rotated, which happens when a file reaches `+"`rolling.max_bytes`"+` or `+"`rolling.max_records`"+`, or when `+"`rolling.max_age`"+` has passed since it received its first message. A file is held in memory until it's uploaded, and is only visible in the bucket once its upload completes. The `+"`timeout`"+` field applies to the upload of each file, and may need increasing for large files.
+
+The `+"`path`"+` and other interpolated fields are evaluated once per file against its first message at the time of rotation, and path collisions are resolved with the `+"`collision_mode`"+` per file:
+
+`+"```yaml"+`
+output:
+  gcp_cloud_storage:
+    bucket: TODO
+    path: events/${! now().ts_format("2006/01/02") }/${! uuid_v4() }.parquet
+    max_in_flight: 64
+    rolling:
+      enabled: true
+      format: parquet
+      max_bytes: 134217728
+      max_age: 10m
+    processors:
+      - parquet_encode:
+          infer_schema: true
+`+"```"+`
+
+Messages are only acknowledged once the file they were added to is uploaded, and a failed upload results in all messages of the file being reattempted. Since writes are blocked until a file is rotated the number of messages that can be accumulated into a file is limited by `+"`max_in_flight`"+` multiplied by the size of batches, which should be set high enough to reach the size or record limits, otherwise files are only rotated by age.`+service.OutputPerformanceDocs(true, true)).
 		Fields(
 			service.NewStringField(csoFieldBucket).
 				Description("The bucket to upload messages to."),
@@ -175,6 +210,11 @@ output:
 				Description("An optional chunk size which controls the maximum number of bytes of the object that the Writer will attempt to send to the server in a single request. If ChunkSize is set to zero, chunking will be disabled.").
 				Advanced().
 				Default(16*1024*1024), // googleapi.DefaultUploadChunkSize
+			service.NewIntField(csoFieldChunkThreshold).
+				Description("The size in bytes of objects from which they are uploaded in chunks with a resumable upload, which allows failed chunks to be retried without restarting the upload. Smaller objects are uploaded with a single request, without first being copied into a chunk buffer.").
+				Advanced().
+				Default(16*1024*1024).
+				Version("4.48.0"),
 			service.NewDurationField(csoFieldTimeout).
 				Description("The maximum period to wait on an upload before abandoning it and reattempting.").
 				Example("1s").
@@ -187,6 +227,7 @@ output:
 			service.NewOutputMaxInFlightField().
 				Description("The maximum number of message batches to have in flight at a given time. Increase this to improve throughput."),
 			service.NewBatchPolicyField(csoFieldBatching),
+			rolling.ConfigField(),
 		)
 }
 
@@ -219,6 +260,7 @@ type gcpCloudStorageOutput struct {
 	conf csoConfig
 
 	client  *storage.Client
+	roller  *rolling.Roller
 	connMut sync.RWMutex
 
 	log *service.Logger
@@ -250,6 +292,9 @@ func (g *gcpCloudStorageOutput) Connect(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if g.conf.Rolling != nil && g.roller == nil {
+		g.roller = rolling.NewRoller(g.conf.Rolling, g.uploadFile, g.log)
+	}
 	return nil
 }
 
@@ -263,106 +308,148 @@ func getClientOptionWithCredential(credentialsJSON string, opt []option.ClientOp
 func (g *gcpCloudStorageOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
 	g.connMut.RLock()
 	client := g.client
+	roller := g.roller
 	g.connMut.RUnlock()
 
 	if client == nil {
 		return service.ErrNotConnected
 	}
+	if roller != nil {
+		return roller.WriteBatch(ctx, batch)
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, g.conf.Timeout)
 	defer cancel()
 
-	return batch.WalkWithBatchedErrors(func(i int, msg *service.Message) error {
-		metadata := map[string]string{}
-		_ = msg.MetaWalk(func(k, v string) error {
-			metadata[k] = v
-			return nil
-		})
-
-		outputPath, err := g.conf.Path.TryString(msg)
+	return batch.WalkWithBatchedErrors(func(_ int, msg *service.Message) error {
+		mBytes, err := msg.AsBytes()
 		if err != nil {
-			return fmt.Errorf("path interpolation error: %w", err)
-		}
-		if g.conf.CollisionMode != GCPCloudStorageOverwriteCollisionMode {
-			_, err = client.Bucket(g.conf.Bucket).Object(outputPath).Attrs(ctx)
+			return err
 		}
+		return g.writeObject(ctx, client, msg, mBytes)
+	})
+}
 
-		isMerge := false
-		var tempPath string
-		if errors.Is(err, storage.ErrObjectNotExist) || g.conf.CollisionMode == GCPCloudStorageOverwriteCollisionMode {
-			tempPath = outputPath
-		} else {
-			isMerge = true
-
-			if g.conf.CollisionMode == GCPCloudStorageErrorIfExistsCollisionMode {
-				if err == nil {
-					err = fmt.Errorf("file at path already exists: %s", outputPath)
-				}
-				return err
-			} else if g.conf.CollisionMode == GCPCloudStorageIgnoreCollisionMode {
-				return nil
-			}
+// uploadFile uploads a rolling file as an object with fields interpolated from
+// its first message.
+func (g *gcpCloudStorageOutput) uploadFile(ctx context.Context, first *service.Message, body []byte) error {
+	g.connMut.RLock()
+	client := g.client
+	g.connMut.RUnlock()
 
-			tempUUID, err := uuid.NewV4()
-			if err != nil {
-				return err
-			}
+	if client == nil {
+		return service.ErrNotConnected
+	}
 
-			dir := path.Dir(outputPath)
-			tempFileName := tempUUID.String() + ".tmp"
-			tempPath = path.Join(dir, tempFileName)
+	ctx, cancel := context.WithTimeout(ctx, g.conf.Timeout)
+	defer cancel()
 
-			g.log.Tracef("creating temporary file for the merge %q", tempPath)
-		}
+	return g.writeObject(ctx, client, first, body)
+}
 
-		src := client.Bucket(g.conf.Bucket).Object(tempPath)
+// writeObject writes the body of an object with fields interpolated from a
+// message, resolving path collisions according to the collision mode.
+func (g *gcpCloudStorageOutput) writeObject(ctx context.Context, client *storage.Client, msg *service.Message, mBytes []byte) error {
+	metadata := map[string]string{}
+	_ = msg.MetaWalk(func(k, v string) error {
+		metadata[k] = v
+		return nil
+	})
+
+	outputPath, err := g.conf.Path.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("path interpolation error: %w", err)
+	}
+	if g.conf.CollisionMode != GCPCloudStorageOverwriteCollisionMode {
+		_, err = client.Bucket(g.conf.Bucket).Object(outputPath).Attrs(ctx)
+	}
 
-		w := src.NewWriter(ctx)
+	isMerge := false
+	var tempPath string
+	if errors.Is(err, storage.ErrObjectNotExist) || g.conf.CollisionMode == GCPCloudStorageOverwriteCollisionMode {
+		tempPath = outputPath
+	} else {
+		isMerge = true
 
-		w.ChunkSize = g.conf.ChunkSize
-		if w.ContentType, err = g.conf.ContentType.TryString(msg); err != nil {
-			return fmt.Errorf("content type interpolation error: %w", err)
-		}
-		if w.ContentEncoding, err = g.conf.ContentEncoding.TryString(msg); err != nil {
-			return fmt.Errorf("content encoding interpolation error: %w", err)
+		if g.conf.CollisionMode == GCPCloudStorageErrorIfExistsCollisionMode {
+			if err == nil {
+				err = fmt.Errorf("file at path already exists: %s", outputPath)
+			}
+			return err
+		} else if g.conf.CollisionMode == GCPCloudStorageIgnoreCollisionMode {
+			return nil
 		}
-		w.Metadata = metadata
 
-		mBytes, err := msg.AsBytes()
+		tempUUID, err := uuid.NewV4()
 		if err != nil {
 			return err
 		}
 
-		var errs error
-		if _, werr := w.Write(mBytes); werr != nil {
-			errs = multierr.Append(errs, werr)
-		}
+		dir := path.Dir(outputPath)
+		tempFileName := tempUUID.String() + ".tmp"
+		tempPath = path.Join(dir, tempFileName)
 
-		if cerr := w.Close(); cerr != nil {
-			errs = multierr.Append(errs, cerr)
-		}
+		g.log.Tracef("creating temporary file for the merge %q", tempPath)
+	}
 
-		if isMerge {
-			defer g.removeTempFile(ctx, src)
-		}
+	src := client.Bucket(g.conf.Bucket).Object(tempPath)
 
-		if errs != nil {
-			return errs
-		}
+	w := src.NewWriter(ctx)
+
+	w.ChunkSize = g.conf.ChunkSize
+	if len(mBytes) < g.conf.ChunkThreshold {
+		w.ChunkSize = 0
+	}
+	if w.ContentType, err = g.conf.ContentType.TryString(msg); err != nil {
+		return fmt.Errorf("content type interpolation error: %w", err)
+	}
+	if w.ContentEncoding, err = g.conf.ContentEncoding.TryString(msg); err != nil {
+		return fmt.Errorf("content encoding interpolation error: %w", err)
+	}
+	w.Metadata = metadata
 
-		if isMerge {
-			dst := client.Bucket(g.conf.Bucket).Object(outputPath)
+	var errs error
+	if _, werr := w.Write(mBytes); werr != nil {
+		errs = multierr.Append(errs, werr)
+	}
 
-			if aerr := g.appendToFile(ctx, src, dst); aerr != nil {
-				return aerr
-			}
+	if cerr := w.Close(); cerr != nil {
+		errs = multierr.Append(errs, cerr)
+	}
+
+	if isMerge {
+		defer g.removeTempFile(ctx, src)
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	if isMerge {
+		dst := client.Bucket(g.conf.Bucket).Object(outputPath)
+
+		if aerr := g.appendToFile(ctx, src, dst); aerr != nil {
+			return aerr
 		}
-		return nil
-	})
+	}
+	return nil
 }
 
 // Close begins cleaning up resources used by this reader asynchronously.
-func (g *gcpCloudStorageOutput) Close(context.Context) error {
+func (g *gcpCloudStorageOutput) Close(ctx context.Context) error {
+	g.connMut.Lock()
+	roller := g.roller
+	g.roller = nil
+	g.connMut.Unlock()
+
+	// Rolling files are uploaded with the client, and so must be flushed before
+	// it's closed.
+	if roller != nil {
+		if err := roller.Close(ctx); err != nil {
+			return err
+		}
+	}
+
 	g.connMut.Lock()
 	defer g.connMut.Unlock()
 
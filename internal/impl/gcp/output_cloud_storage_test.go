@@ -0,0 +1,98 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestCloudStorageOutputChunkThreshold(t *testing.T) {
+	var mut sync.Mutex
+	uploadTypes := map[string]string{}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		name := r.URL.Query().Get("name")
+		uploadType := r.URL.Query().Get("uploadType")
+		switch {
+		case uploadType == "resumable":
+			// Chunks of resumable uploads are sent to the session location.
+			w.Header().Set("Location", srv.URL+"/session?name="+name)
+			return
+		case strings.HasSuffix(r.Header.Get("Content-Range"), "/*"):
+			// Chunks other than the last are acknowledged as incomplete, as
+			// requested by the client with the X-GUploader-No-308 header.
+			w.Header().Set("X-Http-Status-Code-Override", "308")
+			return
+		case uploadType == "":
+			uploadType = "resumable"
+		}
+
+		mut.Lock()
+		uploadTypes[name] = uploadType
+		mut.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"bucket":"foo","name":"` + name + `"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	pConf, err := csoSpec().ParseYAML(`
+bucket: foo
+path: ${! content().length() }
+chunk_size: 262144
+chunk_threshold: 262144
+`, nil)
+	require.NoError(t, err)
+
+	conf, err := csoConfigFromParsed(pConf)
+	require.NoError(t, err)
+
+	g, err := newGCPCloudStorageOutput(conf, service.MockResources())
+	require.NoError(t, err)
+
+	g.client, err = storage.NewClient(context.Background(),
+		option.WithEndpoint(srv.URL+"/storage/v1/"),
+		option.WithoutAuthentication(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = g.client.Close() })
+
+	require.NoError(t, g.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage(make([]byte, 262143)),
+		service.NewMessage(make([]byte, 262144)),
+		service.NewMessage(make([]byte, 262145)),
+	}))
+
+	assert.Equal(t, map[string]string{
+		"262143": "multipart",
+		"262144": "resumable",
+		"262145": "resumable",
+	}, uploadTypes)
+}
@@ -0,0 +1,318 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	gcFieldAddress           = "address"
+	gcFieldMethod            = "method"
+	gcFieldDescriptorSetFile = "descriptor_set_file"
+	gcFieldTLS               = "tls"
+	gcFieldMetadata          = "metadata"
+	gcFieldTimeout           = "timeout"
+	gcFieldUseProtoNames     = "use_proto_names"
+	gcFieldDiscardUnknown    = "discard_unknown"
+)
+
+const grpcClientDescription = `
+The method is described either by a compiled descriptor set, which can be generated from your ` + "`.proto`" + ` files with ` + "`protoc --include_imports --descriptor_set_out=service.binpb service.proto`" + ` or ` + "`buf build -o service.binpb`" + `, or when no descriptor set is configured by querying the server using https://grpc.io/docs/guides/reflection/[gRPC server reflection^].
+
+Messages are converted into requests from JSON documents following the https://protobuf.dev/programming-guides/proto3/#json[protobuf JSON mapping^], and responses are converted back into JSON in the same way.`
+
+func grpcClientFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(gcFieldAddress).
+			Description("The address of the server to connect to, in any form supported by the https://github.com/grpc/grpc/blob/master/doc/naming.md[gRPC name resolver^].").
+			Example("localhost:50051").
+			Example("dns:///orders.acme.internal:443"),
+		service.NewStringField(gcFieldMethod).
+			Description("The fully qualified name of the method to call, in the form `package.Service/Method`.").
+			Example("acme.orders.v1.OrderService/CreateOrder"),
+		service.NewStringField(gcFieldDescriptorSetFile).
+			Description("The path of a serialized `FileDescriptorSet` containing the service definition along with all of its imports. When empty the definition is obtained from the server using reflection.").
+			Example("./service.binpb").
+			Default(""),
+		service.NewTLSToggledField(gcFieldTLS),
+		service.NewInterpolatedStringMapField(gcFieldMetadata).
+			Description("A map of metadata to add to each call.").
+			Example(map[string]any{"authorization": "Bearer ${! env(\"TOKEN\") }"}).
+			Default(map[string]any{}),
+		service.NewDurationField(gcFieldTimeout).
+			Description("The maximum length of time to wait for each call to complete.").
+			Default("5s"),
+		service.NewBoolField(gcFieldUseProtoNames).
+			Description("If `true`, responses are converted into JSON using the field names exactly as they appear in the schema rather than their lowerCamelCase form.").
+			Advanced().
+			Default(false),
+		service.NewBoolField(gcFieldDiscardUnknown).
+			Description("If `true`, fields of a message that are unknown to the schema are discarded rather than failing the call.").
+			Advanced().
+			Default(false),
+	}
+}
+
+// grpcClient calls a single method of a gRPC service, which is resolved either
+// from a descriptor set or using server reflection.
+type grpcClient struct {
+	log *service.Logger
+
+	address     string
+	tlsConf     *tls.Config
+	files       *protoregistry.Files
+	serviceName string
+	methodName  string
+	metadata    map[string]*service.InterpolatedString
+	timeout     time.Duration
+
+	marshalOpts   protojson.MarshalOptions
+	unmarshalOpts protojson.UnmarshalOptions
+
+	mut    sync.Mutex
+	conn   *grpc.ClientConn
+	method protoreflect.MethodDescriptor
+}
+
+func newGRPCClientFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*grpcClient, error) {
+	c := &grpcClient{log: mgr.Logger()}
+
+	var err error
+	if c.address, err = conf.FieldString(gcFieldAddress); err != nil {
+		return nil, err
+	}
+	fullMethod, err := conf.FieldString(gcFieldMethod)
+	if err != nil {
+		return nil, err
+	}
+	if c.serviceName, c.methodName, err = splitMethod(fullMethod); err != nil {
+		return nil, err
+	}
+	var tlsEnabled bool
+	if c.tlsConf, tlsEnabled, err = conf.FieldTLSToggled(gcFieldTLS); err != nil {
+		return nil, err
+	}
+	if !tlsEnabled {
+		c.tlsConf = nil
+	}
+	if c.metadata, err = conf.FieldInterpolatedStringMap(gcFieldMetadata); err != nil {
+		return nil, err
+	}
+	if c.timeout, err = conf.FieldDuration(gcFieldTimeout); err != nil {
+		return nil, err
+	}
+	if c.marshalOpts.UseProtoNames, err = conf.FieldBool(gcFieldUseProtoNames); err != nil {
+		return nil, err
+	}
+	if c.unmarshalOpts.DiscardUnknown, err = conf.FieldBool(gcFieldDiscardUnknown); err != nil {
+		return nil, err
+	}
+
+	descPath, err := conf.FieldString(gcFieldDescriptorSetFile)
+	if err != nil {
+		return nil, err
+	}
+	if descPath != "" {
+		if c.files, err = loadDescriptorSet(mgr, descPath); err != nil {
+			return nil, err
+		}
+		svc, err := findService(c.files, c.serviceName)
+		if err != nil {
+			return nil, err
+		}
+		if c.method, err = methodFromService(svc, c.methodName); err != nil {
+			return nil, err
+		}
+		types := dynamicpb.NewTypes(c.files)
+		c.marshalOpts.Resolver = types
+		c.unmarshalOpts.Resolver = types
+	}
+	return c, nil
+}
+
+func splitMethod(s string) (svc, method string, err error) {
+	s = strings.TrimPrefix(s, "/")
+	i := strings.LastIndex(s, "/")
+	if i <= 0 || i == len(s)-1 {
+		return "", "", fmt.Errorf("method '%v' must be in the form package.Service/Method", s)
+	}
+	return s[:i], s[i+1:], nil
+}
+
+func methodFromService(svc protoreflect.ServiceDescriptor, name string) (protoreflect.MethodDescriptor, error) {
+	m := svc.Methods().ByName(protoreflect.Name(name))
+	if m == nil {
+		return nil, fmt.Errorf("method '%v' not found within service '%v'", name, svc.FullName())
+	}
+	if m.IsStreamingClient() && m.IsStreamingServer() {
+		return nil, fmt.Errorf("method '%v' is bidirectional streaming, which is not supported", name)
+	}
+	return m, nil
+}
+
+// connect opens a client connection, resolving the method using server
+// reflection when no descriptor set was provided.
+func (c *grpcClient) connect(ctx context.Context) (*grpc.ClientConn, protoreflect.MethodDescriptor, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.conn != nil {
+		return c.conn, c.method, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if c.tlsConf != nil {
+		creds = credentials.NewTLS(c.tlsConf)
+	}
+	conn, err := grpc.NewClient(c.address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.method == nil {
+		if c.method, err = c.reflectMethod(ctx, conn); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+	}
+	c.conn = conn
+	return c.conn, c.method, nil
+}
+
+func (c *grpcClient) reflectMethod(ctx context.Context, conn *grpc.ClientConn) (protoreflect.MethodDescriptor, error) {
+	ctx, done := context.WithTimeout(ctx, c.timeout)
+	defer done()
+
+	rc := grpcreflect.NewClientAuto(ctx, conn)
+	defer rc.Reset()
+
+	svc, err := rc.ResolveService(c.serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service '%v' using server reflection: %w", c.serviceName, err)
+	}
+	return methodFromService(svc.UnwrapService(), c.methodName)
+}
+
+// invoke calls the method with the messages of a batch as requests and returns
+// the serialized responses. Client streaming methods send every message of the
+// batch within a single stream, otherwise exactly one message is expected.
+func (c *grpcClient) invoke(ctx context.Context, batch service.MessageBatch) ([][]byte, error) {
+	conn, method, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	md := metadata.MD{}
+	for k, v := range c.metadata {
+		s, err := batch.TryInterpolatedString(0, v)
+		if err != nil {
+			return nil, fmt.Errorf("metadata %v interpolation error: %w", k, err)
+		}
+		md.Set(k, s)
+	}
+	ctx, done := context.WithTimeout(metadata.NewOutgoingContext(ctx, md), c.timeout)
+	defer done()
+
+	reqs := make([]proto.Message, len(batch))
+	for i, msg := range batch {
+		b, err := msg.AsBytes()
+		if err != nil {
+			return nil, err
+		}
+		req := dynamicpb.NewMessage(method.Input())
+		if err := c.unmarshalOpts.Unmarshal(b, req); err != nil {
+			return nil, fmt.Errorf("failed to convert message into %v: %w", method.Input().FullName(), err)
+		}
+		reqs[i] = req
+	}
+
+	fullMethod := "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+	if !method.IsStreamingClient() && !method.IsStreamingServer() {
+		resp := dynamicpb.NewMessage(method.Output())
+		if err := conn.Invoke(ctx, fullMethod, reqs[0], resp); err != nil {
+			return nil, err
+		}
+		b, err := c.marshalOpts.Marshal(resp)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{b}, nil
+	}
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    string(method.Name()),
+		ClientStreams: method.IsStreamingClient(),
+		ServerStreams: method.IsStreamingServer(),
+	}, fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range reqs {
+		if err := stream.SendMsg(req); err != nil {
+			return nil, err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	var resps [][]byte
+	for {
+		resp := dynamicpb.NewMessage(method.Output())
+		if err := stream.RecvMsg(resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		b, err := c.marshalOpts.Marshal(resp)
+		if err != nil {
+			return nil, err
+		}
+		resps = append(resps, b)
+	}
+	return resps, nil
+}
+
+func (c *grpcClient) close() error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
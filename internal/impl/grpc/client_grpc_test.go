@@ -0,0 +1,333 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// startReflectionServer serves the test order service with server reflection
+// enabled, where CreateOrder and CreateOrders are handled by a grpc_server
+// input and WatchOrders streams back three results for each order.
+func startReflectionServer(t *testing.T, handler func(service.MessageBatch) error) (addr, descPath string) {
+	t.Helper()
+
+	descPath, svc := writeTestDescriptorSet(t)
+	conf, err := grpcServerInputSpec().ParseYAML(`
+descriptor_set_file: `+descPath+`
+service: acme.orders.v1.OrderService
+`, nil)
+	require.NoError(t, err)
+	in, err := newGRPCServerInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	go func() {
+		for {
+			batch, ackFn, err := in.ReadBatch(context.Background())
+			if err != nil {
+				return
+			}
+			_ = ackFn(context.Background(), handler(batch))
+		}
+	}()
+
+	watch := svc.Methods().ByName("WatchOrders")
+	desc := *in.svcDesc
+	desc.Streams = append(desc.Streams, grpc.StreamDesc{
+		StreamName:    "WatchOrders",
+		ServerStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			req := dynamicpb.NewMessage(watch.Input())
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			id := req.Get(watch.Input().Fields().ByName("id")).String()
+			for i := 1; i <= 3; i++ {
+				resp := newTestMessage(t, watch.Output(), fmt.Sprintf(`{"id":%q,"total":%v}`, id, i))
+				if err := stream.SendMsg(resp); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+
+	files, err := loadDescriptorSet(service.MockResources(), descPath)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	server.RegisterService(&desc, in)
+	reflectionv1.RegisterServerReflectionServer(server, reflection.NewServerV1(reflection.ServerOptions{
+		Services:           server,
+		DescriptorResolver: files,
+	}))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() {
+		in.shutSig.TriggerSoftStop()
+		server.Stop()
+	})
+	return lis.Addr().String(), descPath
+}
+
+func echoOrderHandler(batch service.MessageBatch) error {
+	var total int64
+	for _, msg := range batch {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return err
+		}
+		q, _ := v.(map[string]any)["quantity"].(json.Number)
+		n, _ := q.Int64()
+		total += n
+	}
+	v, err := batch[0].AsStructured()
+	if err != nil {
+		return err
+	}
+	if v.(map[string]any)["id"] == "bad" {
+		return errors.New("bad order")
+	}
+	tenant, _ := batch[0].MetaGet("x-tenant")
+	res := batch[0].Copy()
+	res.SetStructured(map[string]any{
+		"id":    tenant + "-" + v.(map[string]any)["id"].(string),
+		"total": total,
+	})
+	return service.MessageBatch{res}.AddSyncResponse()
+}
+
+func newTestClientProcessor(t *testing.T, yaml string) *grpcClientProcessor {
+	t.Helper()
+	conf, err := grpcClientProcessorSpec().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+	client, err := newGRPCClientFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	p := &grpcClientProcessor{client: client}
+	t.Cleanup(func() { _ = p.Close(context.Background()) })
+	return p
+}
+
+func batchContents(t *testing.T, batch service.MessageBatch) []string {
+	t.Helper()
+	var out []string
+	for _, msg := range batch {
+		if err := msg.GetError(); err != nil {
+			out = append(out, "error: "+err.Error())
+			continue
+		}
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		out = append(out, string(b))
+	}
+	return out
+}
+
+func TestGRPCClientProcessorUnary(t *testing.T) {
+	for _, useReflection := range []bool{false, true} {
+		t.Run(fmt.Sprintf("reflection %v", useReflection), func(t *testing.T) {
+			addr, descPath := startReflectionServer(t, echoOrderHandler)
+			if useReflection {
+				descPath = ""
+			}
+			p := newTestClientProcessor(t, `
+address: `+addr+`
+method: acme.orders.v1.OrderService/CreateOrder
+descriptor_set_file: "`+descPath+`"
+metadata:
+  x-tenant: ${! @tenant }
+`)
+
+			in := service.MessageBatch{
+				service.NewMessage([]byte(`{"id":"a","quantity":2}`)),
+				service.NewMessage([]byte(`{"id":"bad"}`)),
+				service.NewMessage([]byte(`{"id":"c","nope":true}`)),
+			}
+			in[0].MetaSetMut("tenant", "foo")
+
+			out, err := p.ProcessBatch(context.Background(), in)
+			require.NoError(t, err)
+			require.Len(t, out, 1)
+			contents := batchContents(t, out[0])
+			require.Len(t, contents, 3)
+			assert.JSONEq(t, `{"id":"foo-a","total":2}`, contents[0])
+			assert.Contains(t, contents[1], "bad order")
+			assert.Contains(t, contents[2], "failed to convert message into acme.orders.v1.Order")
+
+			tenant, _ := out[0][0].MetaGet("tenant")
+			assert.Equal(t, "foo", tenant)
+		})
+	}
+}
+
+func TestGRPCClientProcessorClientStreaming(t *testing.T) {
+	addr, _ := startReflectionServer(t, echoOrderHandler)
+	p := newTestClientProcessor(t, `
+address: `+addr+`
+method: /acme.orders.v1.OrderService/CreateOrders
+`)
+
+	out, err := p.ProcessBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"a","quantity":2}`)),
+		service.NewMessage([]byte(`{"id":"b","quantity":3}`)),
+	})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	contents := batchContents(t, out[0])
+	require.Len(t, contents, 1)
+	assert.JSONEq(t, `{"id":"-a","total":5}`, contents[0])
+}
+
+func TestGRPCClientProcessorServerStreaming(t *testing.T) {
+	addr, _ := startReflectionServer(t, echoOrderHandler)
+	p := newTestClientProcessor(t, `
+address: `+addr+`
+method: acme.orders.v1.OrderService/WatchOrders
+use_proto_names: true
+`)
+
+	out, err := p.ProcessBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":"a"}`)),
+		service.NewMessage([]byte(`{"id":"b"}`)),
+	})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	assert.Equal(t, []string{
+		`{"id":"a","total":1}`,
+		`{"id":"a","total":2}`,
+		`{"id":"a","total":3}`,
+		`{"id":"b","total":1}`,
+		`{"id":"b","total":2}`,
+		`{"id":"b","total":3}`,
+	}, compactJSON(t, batchContents(t, out[0])))
+}
+
+func compactJSON(t *testing.T, docs []string) []string {
+	t.Helper()
+	out := make([]string, len(docs))
+	for i, d := range docs {
+		var v any
+		require.NoError(t, json.Unmarshal([]byte(d), &v))
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		out[i] = string(b)
+	}
+	return out
+}
+
+func TestGRPCClientOutput(t *testing.T) {
+	received := make(chan int64, 10)
+	addr, descPath := startReflectionServer(t, func(batch service.MessageBatch) error {
+		var total int64
+		for _, msg := range batch {
+			v, err := msg.AsStructured()
+			if err != nil {
+				return err
+			}
+			q, _ := v.(map[string]any)["quantity"].(json.Number)
+			n, _ := q.Int64()
+			total += n
+		}
+		if total < 0 {
+			return errors.New("negative quantity")
+		}
+		received <- total
+		return nil
+	})
+
+	newOutput := func(method string) *grpcClientOutput {
+		conf, err := grpcClientOutputSpec().ParseYAML(`
+address: `+addr+`
+method: `+method+`
+descriptor_set_file: `+descPath+`
+`, nil)
+		require.NoError(t, err)
+		client, err := newGRPCClientFromConfig(conf, service.MockResources())
+		require.NoError(t, err)
+		out := &grpcClientOutput{client: client}
+		require.NoError(t, out.Connect(context.Background()))
+		t.Cleanup(func() { _ = out.Close(context.Background()) })
+		return out
+	}
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte(`{"quantity":2}`)),
+		service.NewMessage([]byte(`{"quantity":-1}`)),
+		service.NewMessage([]byte(`{"quantity":5}`)),
+	}
+
+	streamOut := newOutput("acme.orders.v1.OrderService/CreateOrders")
+	require.NoError(t, streamOut.WriteBatch(context.Background(), batch))
+	assert.Equal(t, int64(6), <-received)
+
+	unaryOut := newOutput("acme.orders.v1.OrderService/CreateOrder")
+	err := unaryOut.WriteBatch(context.Background(), batch)
+	require.Error(t, err)
+	assert.Equal(t, int64(2), <-received)
+	assert.Equal(t, int64(5), <-received)
+
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.IndexedErrors())
+	assert.Contains(t, err.Error(), "negative quantity")
+}
+
+func TestGRPCClientConfigErrors(t *testing.T) {
+	descPath, _ := writeTestDescriptorSet(t)
+
+	for name, test := range map[string]struct {
+		method string
+		errStr string
+	}{
+		"no method": {
+			method: "acme.orders.v1.OrderService",
+			errStr: "must be in the form package.Service/Method",
+		},
+		"unknown service": {
+			method: "acme.orders.v1.Nope/CreateOrder",
+			errStr: "unable to find service 'acme.orders.v1.Nope'",
+		},
+		"unknown method": {
+			method: "acme.orders.v1.OrderService/Nope",
+			errStr: "method 'Nope' not found",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := grpcClientProcessorSpec().ParseYAML(`
+address: localhost:50051
+method: `+test.method+`
+descriptor_set_file: `+descPath+`
+`, nil)
+			require.NoError(t, err)
+			_, err = newGRPCClientFromConfig(conf, service.MockResources())
+			require.ErrorContains(t, err, test.errStr)
+		})
+	}
+}
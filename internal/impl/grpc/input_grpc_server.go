@@ -0,0 +1,454 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	gsiFieldAddress           = "address"
+	gsiFieldDescriptorSetFile = "descriptor_set_file"
+	gsiFieldService           = "service"
+	gsiFieldMethods           = "methods"
+	gsiFieldTimeout           = "timeout"
+	gsiFieldCertFile          = "cert_file"
+	gsiFieldKeyFile           = "key_file"
+	gsiFieldUseProtoNames     = "use_proto_names"
+	gsiFieldDiscardUnknown    = "discard_unknown"
+)
+
+func grpcServerInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.48.0").
+		Summary("Receive messages from gRPC clients by serving a service described by a protobuf descriptor set.").
+		Description(`
+The service definition is loaded from a compiled descriptor set, which can be generated from your `+"`.proto`"+` files with `+"`protoc --include_imports --descriptor_set_out=service.binpb service.proto`"+` or `+"`buf build -o service.binpb`"+`.
+
+Each request is converted into a JSON document following the https://protobuf.dev/programming-guides/proto3/#json[protobuf JSON mapping^]. Unary calls produce a single message, whereas client streaming calls produce a batch containing every message sent by the client, which is dispatched once the client closes its side of the stream. Server streaming and bidirectional streaming methods are not supported.
+
+=== Responses
+
+The response returned to the client can be set using a `+"<<sync_response, `sync_response`>>"+` output or processor. The first message of the response is converted from JSON into the response type of the method, and if no response is set then an empty response message is returned. If the messages of a request are rejected by the pipeline, or the response is not ready within the configured timeout, the call fails with a corresponding gRPC status.
+
+=== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- grpc_service
+- grpc_method
+- All request metadata (only the first value of each key)
+`+"```"+`
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+`).
+		Fields(
+			service.NewStringField(gsiFieldAddress).
+				Description("The address to listen on.").
+				Default("0.0.0.0:50051"),
+			service.NewStringField(gsiFieldDescriptorSetFile).
+				Description("The path of a serialized `FileDescriptorSet` containing the service definition along with all of its imports.").
+				Example("./service.binpb"),
+			service.NewStringField(gsiFieldService).
+				Description("The fully qualified name of the service to serve.").
+				Example("acme.orders.v1.OrderService"),
+			service.NewStringListField(gsiFieldMethods).
+				Description("An optional list of method names to serve. When empty all unary and client streaming methods of the service are served, and calls to any other method return an unimplemented status.").
+				Example([]string{"CreateOrder"}).
+				Default([]string{}),
+			service.NewDurationField(gsiFieldTimeout).
+				Description("The maximum length of time to wait for a request to be processed and its response set.").
+				Default("5s"),
+			service.NewStringField(gsiFieldCertFile).
+				Description("An optional certificate file for enabling TLS.").
+				Advanced().
+				Default(""),
+			service.NewStringField(gsiFieldKeyFile).
+				Description("An optional key file for enabling TLS.").
+				Advanced().
+				Default(""),
+			service.NewBoolField(gsiFieldUseProtoNames).
+				Description("If `true`, requests are converted into JSON using the field names exactly as they appear in the schema rather than their lowerCamelCase form.").
+				Advanced().
+				Default(false),
+			service.NewBoolField(gsiFieldDiscardUnknown).
+				Description("If `true`, fields of a response that are unknown to the schema are discarded rather than failing the call.").
+				Advanced().
+				Default(false),
+		).
+		Example("Order Service", "Serve the `CreateOrder` method of an order service, writing each order to Kafka and responding with the ID assigned to it.", `
+input:
+  grpc_server:
+    address: 0.0.0.0:50051
+    descriptor_set_file: ./orders.binpb
+    service: acme.orders.v1.OrderService
+    methods: [ CreateOrder ]
+
+pipeline:
+  processors:
+    - mutation: 'meta order_id = uuid_v4()'
+
+output:
+  broker:
+    pattern: fan_out_sequential
+    outputs:
+      - kafka_franz:
+          seed_brokers: [ localhost:9092 ]
+          topic: orders
+          key: ${! @order_id }
+      - sync_response: {}
+        processors:
+          - mapping: 'root.id = @order_id'
+`)
+}
+
+func init() {
+	err := service.RegisterBatchInput("grpc_server", grpcServerInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			return newGRPCServerInputFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type grpcServerRequest struct {
+	batch   service.MessageBatch
+	resChan chan error
+}
+
+type grpcServerInput struct {
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	address  string
+	certFile string
+	keyFile  string
+	timeout  time.Duration
+	svcDesc  *grpc.ServiceDesc
+
+	marshalOpts   protojson.MarshalOptions
+	unmarshalOpts protojson.UnmarshalOptions
+
+	reqChan chan grpcServerRequest
+
+	serverMut sync.Mutex
+	server    *grpc.Server
+}
+
+func newGRPCServerInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*grpcServerInput, error) {
+	g := &grpcServerInput{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+		reqChan: make(chan grpcServerRequest),
+	}
+
+	var err error
+	if g.address, err = conf.FieldString(gsiFieldAddress); err != nil {
+		return nil, err
+	}
+	if g.timeout, err = conf.FieldDuration(gsiFieldTimeout); err != nil {
+		return nil, err
+	}
+	if g.certFile, err = conf.FieldString(gsiFieldCertFile); err != nil {
+		return nil, err
+	}
+	if g.keyFile, err = conf.FieldString(gsiFieldKeyFile); err != nil {
+		return nil, err
+	}
+	if (g.certFile == "") != (g.keyFile == "") {
+		return nil, errors.New("both cert_file and key_file must be set in order to enable TLS")
+	}
+	if g.marshalOpts.UseProtoNames, err = conf.FieldBool(gsiFieldUseProtoNames); err != nil {
+		return nil, err
+	}
+	if g.unmarshalOpts.DiscardUnknown, err = conf.FieldBool(gsiFieldDiscardUnknown); err != nil {
+		return nil, err
+	}
+
+	descPath, err := conf.FieldString(gsiFieldDescriptorSetFile)
+	if err != nil {
+		return nil, err
+	}
+	files, err := loadDescriptorSet(mgr, descPath)
+	if err != nil {
+		return nil, err
+	}
+	types := dynamicpb.NewTypes(files)
+	g.marshalOpts.Resolver = types
+	g.unmarshalOpts.Resolver = types
+
+	svcName, err := conf.FieldString(gsiFieldService)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := findService(files, svcName)
+	if err != nil {
+		return nil, err
+	}
+	methodNames, err := conf.FieldStringList(gsiFieldMethods)
+	if err != nil {
+		return nil, err
+	}
+	if g.svcDesc, err = g.serviceDesc(svc, methodNames); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// serviceDesc builds a gRPC service description with handlers for each of the
+// chosen methods of a service.
+func (g *grpcServerInput) serviceDesc(svc protoreflect.ServiceDescriptor, methodNames []string) (*grpc.ServiceDesc, error) {
+	var methods []protoreflect.MethodDescriptor
+	if len(methodNames) == 0 {
+		for i := 0; i < svc.Methods().Len(); i++ {
+			m := svc.Methods().Get(i)
+			if m.IsStreamingServer() {
+				g.log.Debugf("Skipping method %v as server streaming is not supported", m.Name())
+				continue
+			}
+			methods = append(methods, m)
+		}
+	} else {
+		for _, name := range methodNames {
+			m := svc.Methods().ByName(protoreflect.Name(name))
+			if m == nil {
+				return nil, fmt.Errorf("method '%v' not found within service '%v'", name, svc.FullName())
+			}
+			if m.IsStreamingServer() {
+				return nil, fmt.Errorf("method '%v' is server streaming, which is not supported", name)
+			}
+			methods = append(methods, m)
+		}
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("service '%v' has no methods that can be served", svc.FullName())
+	}
+
+	desc := &grpc.ServiceDesc{
+		ServiceName: string(svc.FullName()),
+		// The handlers are built dynamically and so there is no interface
+		// for the server implementation to satisfy.
+		HandlerType: (*any)(nil),
+	}
+	for _, m := range methods {
+		if m.IsStreamingClient() {
+			desc.Streams = append(desc.Streams, grpc.StreamDesc{
+				StreamName:    string(m.Name()),
+				Handler:       g.streamHandler(m),
+				ClientStreams: true,
+			})
+		} else {
+			desc.Methods = append(desc.Methods, grpc.MethodDesc{
+				MethodName: string(m.Name()),
+				Handler:    g.unaryHandler(m),
+			})
+		}
+	}
+	return desc, nil
+}
+
+func (g *grpcServerInput) unaryHandler(m protoreflect.MethodDescriptor) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+		req := dynamicpb.NewMessage(m.Input())
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		return g.handle(ctx, m, []proto.Message{req})
+	}
+}
+
+func (g *grpcServerInput) streamHandler(m protoreflect.MethodDescriptor) grpc.StreamHandler {
+	return func(_ any, stream grpc.ServerStream) error {
+		var reqs []proto.Message
+		for {
+			req := dynamicpb.NewMessage(m.Input())
+			if err := stream.RecvMsg(req); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return err
+			}
+			reqs = append(reqs, req)
+		}
+		resp, err := g.handle(stream.Context(), m, reqs)
+		if err != nil {
+			return err
+		}
+		return stream.SendMsg(resp)
+	}
+}
+
+// handle dispatches the requests of a call as a batch and waits for the
+// pipeline to acknowledge it, returning the response set by a sync_response
+// component.
+func (g *grpcServerInput) handle(ctx context.Context, m protoreflect.MethodDescriptor, reqs []proto.Message) (proto.Message, error) {
+	resp := dynamicpb.NewMessage(m.Output())
+	if len(reqs) == 0 {
+		return resp, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	batch := make(service.MessageBatch, len(reqs))
+	for i, req := range reqs {
+		b, err := g.marshalOpts.Marshal(req)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert request to JSON: %v", err)
+		}
+		msg := service.NewMessage(b)
+		for k, v := range md {
+			if len(v) > 0 {
+				msg.MetaSetMut(k, v[0])
+			}
+		}
+		msg.MetaSetMut("grpc_service", string(m.Parent().FullName()))
+		msg.MetaSetMut("grpc_method", string(m.Name()))
+		batch[i] = msg
+	}
+
+	var store *service.SyncResponseStore
+	batch[0], store = batch[0].WithSyncResponseStore()
+
+	ctx, done := context.WithTimeout(ctx, g.timeout)
+	defer done()
+
+	resChan := make(chan error, 1)
+	select {
+	case g.reqChan <- grpcServerRequest{batch: batch, resChan: resChan}:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-g.shutSig.SoftStopChan():
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+
+	select {
+	case err := <-resChan:
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	for _, resBatch := range store.Read() {
+		if len(resBatch) == 0 {
+			continue
+		}
+		b, err := resBatch[0].AsBytes()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read response: %v", err)
+		}
+		if err := g.unmarshalOpts.Unmarshal(b, resp); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert response into %v: %v", m.Output().FullName(), err)
+		}
+		break
+	}
+	return resp, nil
+}
+
+func (g *grpcServerInput) Connect(ctx context.Context) error {
+	g.serverMut.Lock()
+	defer g.serverMut.Unlock()
+	if g.server != nil {
+		return nil
+	}
+
+	var opts []grpc.ServerOption
+	if g.certFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(g.certFile, g.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS credentials: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	lis, err := net.Listen("tcp", g.address)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(opts...)
+	server.RegisterService(g.svcDesc, g)
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			g.log.Errorf("gRPC server stopped: %v", err)
+		}
+	}()
+	g.log.Infof("Receiving gRPC requests for %v at: %v", g.svcDesc.ServiceName, lis.Addr())
+
+	g.server = server
+	return nil
+}
+
+func (g *grpcServerInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	select {
+	case req := <-g.reqChan:
+		return req.batch, func(ctx context.Context, err error) error {
+			req.resChan <- err
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-g.shutSig.SoftStopChan():
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (g *grpcServerInput) Close(ctx context.Context) error {
+	g.shutSig.TriggerSoftStop()
+
+	g.serverMut.Lock()
+	server := g.server
+	g.server = nil
+	g.serverMut.Unlock()
+	if server == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+	return nil
+}
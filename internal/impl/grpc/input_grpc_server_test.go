@@ -0,0 +1,279 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const testProto = `
+syntax = "proto3";
+package acme.orders.v1;
+
+message Order {
+  string id = 1;
+  int32 quantity = 2;
+}
+
+message OrderResult {
+  string id = 1;
+  int32 total = 2;
+}
+
+service OrderService {
+  rpc CreateOrder(Order) returns (OrderResult);
+  rpc CreateOrders(stream Order) returns (OrderResult);
+  rpc WatchOrders(Order) returns (stream OrderResult);
+}
+`
+
+func writeTestDescriptorSet(t *testing.T) (string, protoreflect.ServiceDescriptor) {
+	t.Helper()
+
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{"orders.proto": testProto}),
+	}
+	fds, err := parser.ParseFiles("orders.proto")
+	require.NoError(t, err)
+
+	fd := fds[0].UnwrapFile()
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(fd)},
+	})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "orders.binpb")
+	require.NoError(t, os.WriteFile(path, b, 0o644))
+	return path, fd.Services().Get(0)
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	return addr
+}
+
+func startTestServer(t *testing.T, extra string, handler func(service.MessageBatch) error) (*grpc.ClientConn, protoreflect.ServiceDescriptor) {
+	t.Helper()
+
+	descPath, svc := writeTestDescriptorSet(t)
+	addr := freeAddress(t)
+
+	conf, err := grpcServerInputSpec().ParseYAML(`
+address: `+addr+`
+descriptor_set_file: `+descPath+`
+service: acme.orders.v1.OrderService
+`+extra, nil)
+	require.NoError(t, err)
+
+	in, err := newGRPCServerInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, in.Connect(context.Background()))
+	t.Cleanup(func() {
+		ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+		defer done()
+		_ = in.Close(ctx)
+	})
+
+	go func() {
+		for {
+			batch, ackFn, err := in.ReadBatch(context.Background())
+			if err != nil {
+				return
+			}
+			_ = ackFn(context.Background(), handler(batch))
+		}
+	}()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, svc
+}
+
+func newTestMessage(t *testing.T, md protoreflect.MessageDescriptor, jsonStr string) *dynamicpb.Message {
+	t.Helper()
+	msg := dynamicpb.NewMessage(md)
+	require.NoError(t, protojson.Unmarshal([]byte(jsonStr), msg))
+	return msg
+}
+
+func TestGRPCServerUnary(t *testing.T) {
+	conn, svc := startTestServer(t, "", func(batch service.MessageBatch) error {
+		if len(batch) != 1 {
+			return errors.New("expected a single message")
+		}
+		v, err := batch[0].AsStructured()
+		if err != nil {
+			return err
+		}
+		order := v.(map[string]any)
+		if s, _ := batch[0].MetaGet("grpc_service"); s != "acme.orders.v1.OrderService" {
+			return errors.New("unexpected service: " + s)
+		}
+		if m, _ := batch[0].MetaGet("grpc_method"); m != "CreateOrder" {
+			return errors.New("unexpected method: " + m)
+		}
+		tenant, _ := batch[0].MetaGet("x-tenant")
+		res := batch[0].Copy()
+		res.SetStructured(map[string]any{
+			"id":    tenant + "-" + order["id"].(string),
+			"total": order["quantity"],
+		})
+		return service.MessageBatch{res}.AddSyncResponse()
+	})
+
+	method := svc.Methods().ByName("CreateOrder")
+	req := newTestMessage(t, method.Input(), `{"id":"abc","quantity":3}`)
+	resp := dynamicpb.NewMessage(method.Output())
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "foo")
+	require.NoError(t, conn.Invoke(ctx, "/acme.orders.v1.OrderService/CreateOrder", req, resp))
+
+	b, err := protojson.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"foo-abc","total":3}`, string(b))
+}
+
+func TestGRPCServerClientStreaming(t *testing.T) {
+	conn, svc := startTestServer(t, "", func(batch service.MessageBatch) error {
+		var total int64
+		for _, msg := range batch {
+			v, err := msg.AsStructured()
+			if err != nil {
+				return err
+			}
+			q, err := v.(map[string]any)["quantity"].(json.Number).Int64()
+			if err != nil {
+				return err
+			}
+			total += q
+		}
+		res := batch[0].Copy()
+		res.SetStructured(map[string]any{"total": total})
+		return service.MessageBatch{res}.AddSyncResponse()
+	})
+
+	method := svc.Methods().ByName("CreateOrders")
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{
+		StreamName:    "CreateOrders",
+		ClientStreams: true,
+	}, "/acme.orders.v1.OrderService/CreateOrders")
+	require.NoError(t, err)
+
+	for _, q := range []string{`{"quantity":1}`, `{"quantity":2}`, `{"quantity":4}`} {
+		require.NoError(t, stream.SendMsg(newTestMessage(t, method.Input(), q)))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	resp := dynamicpb.NewMessage(method.Output())
+	require.NoError(t, stream.RecvMsg(resp))
+
+	b, err := protojson.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"total":7}`, string(b))
+}
+
+func TestGRPCServerNoResponse(t *testing.T) {
+	conn, svc := startTestServer(t, "", func(batch service.MessageBatch) error {
+		return nil
+	})
+
+	method := svc.Methods().ByName("CreateOrder")
+	resp := dynamicpb.NewMessage(method.Output())
+	require.NoError(t, conn.Invoke(context.Background(), "/acme.orders.v1.OrderService/CreateOrder", newTestMessage(t, method.Input(), `{"id":"abc"}`), resp))
+
+	b, err := protojson.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(b))
+}
+
+func TestGRPCServerErrors(t *testing.T) {
+	conn, svc := startTestServer(t, "methods: [ CreateOrder ]", func(batch service.MessageBatch) error {
+		return errors.New("nope")
+	})
+
+	method := svc.Methods().ByName("CreateOrder")
+	req := newTestMessage(t, method.Input(), `{"id":"abc"}`)
+
+	err := conn.Invoke(context.Background(), "/acme.orders.v1.OrderService/CreateOrder", req, dynamicpb.NewMessage(method.Output()))
+	require.Error(t, err)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "nope")
+
+	err = conn.Invoke(context.Background(), "/acme.orders.v1.OrderService/CreateOrders", req, dynamicpb.NewMessage(method.Output()))
+	require.Error(t, err)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestGRPCServerConfigErrors(t *testing.T) {
+	descPath, _ := writeTestDescriptorSet(t)
+
+	for name, test := range map[string]struct {
+		extra  string
+		errStr string
+	}{
+		"unknown service": {
+			extra:  "service: acme.orders.v1.Nope",
+			errStr: "unable to find service 'acme.orders.v1.Nope'",
+		},
+		"unknown method": {
+			extra:  "service: acme.orders.v1.OrderService\nmethods: [ Nope ]",
+			errStr: "method 'Nope' not found",
+		},
+		"server streaming": {
+			extra:  "service: acme.orders.v1.OrderService\nmethods: [ WatchOrders ]",
+			errStr: "method 'WatchOrders' is server streaming",
+		},
+		"partial tls": {
+			extra:  "service: acme.orders.v1.OrderService\ncert_file: foo.pem",
+			errStr: "both cert_file and key_file must be set",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := grpcServerInputSpec().ParseYAML("descriptor_set_file: "+descPath+"\n"+test.extra, nil)
+			require.NoError(t, err)
+			_, err = newGRPCServerInputFromConfig(conf, service.MockResources())
+			require.ErrorContains(t, err, test.errStr)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	gcoFieldBatching = "batching"
+)
+
+func grpcClientOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.48.0").
+		Summary("Sends messages to a gRPC server by calling a method.").
+		Description(grpcClientDescription+`
+
+Unary and server streaming methods are called once for each message, with any responses being discarded. Client streaming methods are called once for each batch, with every message of the batch sent within the stream, which makes it possible to control the size of each stream with a <<batching, batching policy>>. Bidirectional streaming methods are not supported.`+service.OutputPerformanceDocs(true, true)).
+		Fields(grpcClientFields()...).
+		Fields(
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(gcoFieldBatching),
+		).
+		Example("Stream Events", "Send events to a client streaming method in batches of up to 100 messages.", `
+output:
+  grpc_client:
+    address: localhost:50051
+    method: acme.events.v1.EventService/Ingest
+    descriptor_set_file: ./events.binpb
+    batching:
+      count: 100
+      period: 1s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("grpc_client", grpcClientOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(gcoFieldBatching); err != nil {
+				return
+			}
+			var client *grpcClient
+			if client, err = newGRPCClientFromConfig(conf, mgr); err != nil {
+				return
+			}
+			out = &grpcClientOutput{client: client}
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type grpcClientOutput struct {
+	client *grpcClient
+}
+
+func (g *grpcClientOutput) Connect(ctx context.Context) error {
+	_, _, err := g.client.connect(ctx)
+	return err
+}
+
+func (g *grpcClientOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	_, method, err := g.client.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	if method.IsStreamingClient() {
+		_, err := g.client.invoke(ctx, batch)
+		return err
+	}
+
+	var batchErr *service.BatchError
+	for i := range batch {
+		if _, err := g.client.invoke(ctx, batch[i:i+1]); err != nil {
+			if batchErr == nil {
+				batchErr = service.NewBatchError(batch, err)
+			}
+			batchErr.Failed(i, err)
+		}
+	}
+	if batchErr != nil {
+		return batchErr
+	}
+	return nil
+}
+
+func (g *grpcClientOutput) Close(ctx context.Context) error {
+	return g.client.close()
+}
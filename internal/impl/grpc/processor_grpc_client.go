@@ -0,0 +1,112 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func grpcClientProcessorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Integration").
+		Version("4.48.0").
+		Summary("Calls a gRPC method for messages and replaces them with the responses.").
+		Description(grpcClientDescription+`
+
+The behaviour of the processor depends on the kind of method being called:
+
+- Unary methods are called once for each message, which is replaced with the response.
+- Server streaming methods are called once for each message, which is replaced with a message for each response received.
+- Client streaming methods are called once for each batch, with every message of the batch sent within the stream, and the batch is replaced with the single response.
+
+Bidirectional streaming methods are not supported. Metadata of the original messages is kept, and when a call fails the messages are flagged with the error and left unchanged, which can be handled using xref:configuration:error_handling.adoc[error handling patterns].`).
+		Fields(grpcClientFields()...).
+		Example("Enrich Orders", "Look up the customer of each order from a gRPC service, storing the result in a field of the original document.", `
+pipeline:
+  processors:
+    - branch:
+        request_map: 'root.id = this.customer_id'
+        processors:
+          - grpc_client:
+              address: localhost:50051
+              method: acme.customers.v1.CustomerService/GetCustomer
+        result_map: 'root.customer = this'
+`)
+}
+
+func init() {
+	err := service.RegisterBatchProcessor("grpc_client", grpcClientProcessorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			client, err := newGRPCClientFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return &grpcClientProcessor{client: client}, nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type grpcClientProcessor struct {
+	client *grpcClient
+}
+
+func (g *grpcClientProcessor) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	_, method, err := g.client.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if method.IsStreamingClient() {
+		resps, err := g.client.invoke(ctx, batch)
+		if err != nil {
+			for _, msg := range batch {
+				msg.SetError(err)
+			}
+			return []service.MessageBatch{batch}, nil
+		}
+		var out service.MessageBatch
+		for _, b := range resps {
+			msg := batch[0].Copy()
+			msg.SetBytes(b)
+			out = append(out, msg)
+		}
+		return []service.MessageBatch{out}, nil
+	}
+
+	var out service.MessageBatch
+	for i, msg := range batch {
+		resps, err := g.client.invoke(ctx, batch[i:i+1])
+		if err != nil {
+			msg.SetError(err)
+			out = append(out, msg)
+			continue
+		}
+		for _, b := range resps {
+			res := msg.Copy()
+			res.SetBytes(b)
+			out = append(out, res)
+		}
+	}
+	return []service.MessageBatch{out}, nil
+}
+
+func (g *grpcClientProcessor) Close(ctx context.Context) error {
+	return g.client.close()
+}
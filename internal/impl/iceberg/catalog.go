@@ -0,0 +1,148 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/catalog/rest"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	icFieldURL          = "url"
+	icFieldWarehouse    = "warehouse"
+	icFieldToken        = "token"
+	icFieldCredential   = "credential"
+	icFieldSigV4        = "sigv4"
+	icFieldSigV4Enabled = "enabled"
+	icFieldSigV4Region  = "region"
+	icFieldSigV4Service = "service"
+	icFieldProperties   = "properties"
+)
+
+func catalogFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewStringField(icFieldURL).
+			Description("The URL of the REST catalog.").
+			Example("http://localhost:8181").
+			Example("https://glue.us-east-1.amazonaws.com/iceberg"),
+		service.NewStringField(icFieldWarehouse).
+			Description("The warehouse to request from the catalog. For AWS Glue this is the ID of the account that owns the catalog.").
+			Optional(),
+		service.NewStringField(icFieldToken).
+			Description("A bearer token to authenticate with the catalog.").
+			Secret().
+			Optional(),
+		service.NewStringField(icFieldCredential).
+			Description("A credential in the form `client_id:client_secret`, which is exchanged for a token with the OAuth endpoint of the catalog.").
+			Secret().
+			Optional(),
+		service.NewObjectField(icFieldSigV4,
+			service.NewBoolField(icFieldSigV4Enabled).
+				Description("Whether to sign requests to the catalog with AWS Signature Version 4, using credentials from the default AWS credential chain.").
+				Default(false),
+			service.NewStringField(icFieldSigV4Region).
+				Description("The AWS region to sign requests for. Defaults to the region of the default AWS configuration.").
+				Default(""),
+			service.NewStringField(icFieldSigV4Service).
+				Description("The AWS service to sign requests for.").
+				Default("glue"),
+		).
+			Description("Sign requests to catalogs hosted by AWS, such as the Iceberg REST endpoint of AWS Glue.").
+			Advanced(),
+		service.NewStringMapField(icFieldProperties).
+			Description("Additional properties of the catalog and the file IO of tables, such as the endpoint and credentials of an S3 compatible object store.").
+			Example(map[string]any{
+				"s3.endpoint":          "http://localhost:9000",
+				"s3.access-key-id":     "admin",
+				"s3.secret-access-key": "password",
+			}).
+			Advanced().
+			Optional(),
+	}
+}
+
+type catalogConfig struct {
+	url   string
+	props iceberg.Properties
+	opts  []rest.Option
+}
+
+func catalogConfigFromParsed(conf *service.ParsedConfig) (*catalogConfig, error) {
+	c := &catalogConfig{props: iceberg.Properties{}}
+
+	var err error
+	if c.url, err = conf.FieldString(icFieldURL); err != nil {
+		return nil, err
+	}
+	if conf.Contains(icFieldWarehouse) {
+		warehouse, err := conf.FieldString(icFieldWarehouse)
+		if err != nil {
+			return nil, err
+		}
+		c.opts = append(c.opts, rest.WithWarehouseLocation(warehouse))
+	}
+	if conf.Contains(icFieldToken) {
+		token, err := conf.FieldString(icFieldToken)
+		if err != nil {
+			return nil, err
+		}
+		c.opts = append(c.opts, rest.WithOAuthToken(token))
+	}
+	if conf.Contains(icFieldCredential) {
+		credential, err := conf.FieldString(icFieldCredential)
+		if err != nil {
+			return nil, err
+		}
+		c.opts = append(c.opts, rest.WithCredential(credential))
+	}
+
+	sigConf := conf.Namespace(icFieldSigV4)
+	sigEnabled, err := sigConf.FieldBool(icFieldSigV4Enabled)
+	if err != nil {
+		return nil, err
+	}
+	if sigEnabled {
+		region, err := sigConf.FieldString(icFieldSigV4Region)
+		if err != nil {
+			return nil, err
+		}
+		svc, err := sigConf.FieldString(icFieldSigV4Service)
+		if err != nil {
+			return nil, err
+		}
+		c.opts = append(c.opts, rest.WithSigV4RegionSvc(region, svc))
+	}
+
+	if conf.Contains(icFieldProperties) {
+		props, err := conf.FieldStringMap(icFieldProperties)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range props {
+			c.props[k] = v
+		}
+		c.opts = append(c.opts, rest.WithAdditionalProps(c.props))
+	}
+	return c, nil
+}
+
+func (c *catalogConfig) open(ctx context.Context) (catalog.Catalog, error) {
+	return rest.NewCatalog(ctx, "redpanda-connect", c.url, c.opts...)
+}
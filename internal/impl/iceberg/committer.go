@@ -0,0 +1,236 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const commitAttempts = 5
+
+var errCommitterStopped = errors.New("committer stopped")
+
+// partitionedRows are the rows of a batch which belong to a partition.
+type partitionedRows struct {
+	key  string
+	rows []map[string]any
+}
+
+// pendingRows are the rows of a batch awaiting a commit, converted to the
+// schema of the table with the ID schemaID.
+type pendingRows struct {
+	schemaID    int
+	arrowSchema *arrow.Schema
+	partitions  []partitionedRows
+
+	done chan error
+}
+
+// committer writes the rows of all batches received within a commit period as
+// a data file per partition, which are appended to a table in a single commit.
+// This compacts small batches into fewer, larger data files and keeps the
+// number of snapshots of the table low.
+type committer struct {
+	cat    catalog.Catalog
+	ident  table.Identifier
+	props  iceberg.Properties
+	period time.Duration
+	log    *service.Logger
+
+	pending chan *pendingRows
+}
+
+func newCommitter(cat catalog.Catalog, ident table.Identifier, props iceberg.Properties, period time.Duration, log *service.Logger) *committer {
+	return &committer{
+		cat:     cat,
+		ident:   ident,
+		props:   props,
+		period:  period,
+		log:     log,
+		pending: make(chan *pendingRows),
+	}
+}
+
+// commit blocks until the rows are written and committed to the table.
+func (c *committer) commit(ctx context.Context, p *pendingRows) error {
+	p.done = make(chan error, 1)
+	select {
+	case c.pending <- p:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-p.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *committer) loop(shutSig *shutdown.Signaller) {
+	defer shutSig.TriggerHasStopped()
+
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+
+	var batch []*pendingRows
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		ctx, done := shutSig.HardStopCtx(context.Background())
+		paths, err := c.writeFiles(ctx, batch)
+		if err == nil {
+			if err = c.commitFiles(ctx, paths); err != nil {
+				c.log.Errorf("Failed to commit %v data files: %v", len(paths), err)
+			}
+		} else {
+			c.log.Errorf("Failed to write data files: %v", err)
+		}
+		done()
+		for _, p := range batch {
+			p.done <- err
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case p := <-c.pending:
+			batch = append(batch, p)
+		case <-ticker.C:
+			flush()
+		case <-shutSig.SoftStopChan():
+			flush()
+			// Reject files which arrive after the final commit.
+			for {
+				select {
+				case p := <-c.pending:
+					p.done <- errCommitterStopped
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeFiles writes the rows of batches as a data file per partition and schema,
+// and returns the paths of the files.
+func (c *committer) writeFiles(ctx context.Context, batch []*pendingRows) ([]string, error) {
+	tbl, err := c.cat.LoadTable(ctx, c.ident, c.props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load table: %w", err)
+	}
+	fs, ok := tbl.FS().(iceio.WriteFileIO)
+	if !ok {
+		return nil, errors.New("the file IO of the table does not support writes")
+	}
+
+	type fileRows struct {
+		arrowSchema *arrow.Schema
+		rows        []map[string]any
+	}
+	var files []*fileRows
+	indexes := map[string]int{}
+	for _, p := range batch {
+		for _, part := range p.partitions {
+			key := fmt.Sprintf("%v/%v", p.schemaID, part.key)
+			idx, exists := indexes[key]
+			if !exists {
+				idx = len(files)
+				indexes[key] = idx
+				files = append(files, &fileRows{arrowSchema: p.arrowSchema})
+			}
+			files[idx].rows = append(files[idx].rows, part.rows...)
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		path, err := writeDataFile(fs, tbl.Location(), f.arrowSchema, f.rows)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// commitFiles appends the data files to the latest metadata of the table, and
+// retries commits which conflict with other writers.
+func (c *committer) commitFiles(ctx context.Context, paths []string) error {
+	var err error
+	for attempt := 0; attempt < commitAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		var tbl *table.Table
+		if tbl, err = c.cat.LoadTable(ctx, c.ident, c.props); err != nil {
+			err = fmt.Errorf("failed to load table: %w", err)
+			continue
+		}
+
+		// The partition spec may have been changed by another writer since the
+		// data files were written.
+		if _, err = newPartitionKeyFn(tbl.Spec(), tbl.Schema()); err != nil {
+			return err
+		}
+
+		txn := tbl.NewTransaction()
+		if err = addFiles(txn, paths); err != nil {
+			return fmt.Errorf("failed to add data files: %w", err)
+		}
+		if _, err = txn.Commit(ctx); err == nil {
+			return nil
+		}
+		c.log.Debugf("Commit attempt %v failed: %v", attempt+1, err)
+	}
+	return err
+}
+
+// addFiles adds data files to a transaction. The partition values of data files
+// are derived from the bounds of their source columns, which panics when the
+// bounds of a partition field differ. This can only happen when the partition
+// spec of the table changes between writing and committing the data files, in
+// which case the files are rejected instead.
+func addFiles(txn *table.Transaction, paths []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	// Paths are unique, which makes it unnecessary to check the data files of
+	// the table for duplicates.
+	return txn.AddFiles(paths, nil, true)
+}
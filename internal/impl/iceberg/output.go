@@ -0,0 +1,437 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ioFieldCatalog                = "catalog"
+	ioFieldNamespace              = "namespace"
+	ioFieldTable                  = "table"
+	ioFieldSchemaEvolution        = "schema_evolution"
+	ioFieldSchemaEvolutionEnabled = "enabled"
+	ioFieldCommitPeriod           = "commit_period"
+	ioFieldBatching               = "batching"
+)
+
+func icebergOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.48.0").
+		Summary("Writes messages to an Apache Iceberg table as Parquet data files, which are committed to the table through a REST catalog.").
+		Description(`
+The rows of all batches received within a commit period are written as a single Parquet data file per partition to the location of the table, and the data files are appended to the table in a single commit. This compacts small batches into fewer, larger data files. Batches are only acknowledged once their data files are committed, and their rows are held in memory until then. A longer commit period therefore results in fewer snapshots and larger data files at the cost of latency and memory usage.
+
+Messages must be JSON objects, and each field of an object is written to the column of the same name. Fields which aren't columns of the table are ignored unless schema evolution is enabled.
+
+The table must already exist.
+
+== Catalogs
+
+Only catalogs which implement the Iceberg REST catalog API are supported. The AWS Glue Data Catalog is supported through its Iceberg REST endpoint, in which case requests must be signed with the `+"`sigv4`"+` fields, and configs which target the Glue endpoint without signing requests are rejected. The native Glue API can't be used as it doesn't support committing to tables.
+
+== Partitioning
+
+The rows are split into a data file per partition of the partition spec of the table. The partition values of data files are derived from the bounds of their source columns, and therefore only partition fields with the `+"`identity`"+` transform and a top level source column are supported. Tables with partition fields which use any other transform, such as `+"`bucket`, `truncate`, `day` or `hour`"+`, are rejected when connecting, and data files are rejected when the partition spec of the table is changed to such a spec before they're committed.
+
+== Schema evolution
+
+When schema evolution is enabled, fields of messages which aren't columns of the table are added to the schema of the table as optional columns, with a type inferred from their values. Booleans, integers, floats and timestamps map to the corresponding Iceberg types, and strings, objects and arrays to string columns, with objects and arrays encoded as JSON.
+
+== Commit conflicts
+
+Commits which conflict with the commits of other writers are retried against the latest metadata of the table. If a commit still fails then the batches are rejected, and the data files written for them aren't cleaned up. Data files which aren't referenced by a snapshot can be removed with the `+"`remove_orphan_files`"+` procedure of engines such as Spark.
+`).
+		Fields(
+			service.NewObjectField(ioFieldCatalog, catalogFields()...).
+				Description("The catalog of the table."),
+			service.NewStringField(ioFieldNamespace).
+				Description("The namespace of the table, with levels of nested namespaces separated by dots.").
+				Example("analytics").
+				Example("analytics.events"),
+			service.NewStringField(ioFieldTable).
+				Description("The name of the table.").
+				Example("clicks"),
+			service.NewObjectField(ioFieldSchemaEvolution,
+				service.NewBoolField(ioFieldSchemaEvolutionEnabled).
+					Description("Whether to add columns to the table for fields of messages which aren't columns.").
+					Default(false),
+			).
+				Description("Options to control schema evolution of the table."),
+			service.NewDurationField(ioFieldCommitPeriod).
+				Description("The period at which the rows of batches are written as data files and committed to the table.").
+				Default("10s"),
+			service.NewOutputMaxInFlightField().Default(8),
+			service.NewBatchPolicyField(ioFieldBatching),
+		).
+		LintRule(`
+root = if this.catalog.url.or("").re_match("^https://glue(-fips)?\\.[a-z0-9-]+\\.amazonaws\\.com") && !this.catalog.sigv4.enabled.or(false) {
+  "requests to the AWS Glue Iceberg REST endpoint must be signed, set catalog.sigv4.enabled to true"
+}
+`).
+		Example("Write to a REST Catalog", "Writes events to a table of a REST catalog backed by an S3 compatible object store, with batches of up to ten thousand rows and a commit every minute.", `
+output:
+  iceberg:
+    catalog:
+      url: http://localhost:8181
+      properties:
+        s3.endpoint: http://localhost:9000
+        s3.access-key-id: admin
+        s3.secret-access-key: password
+    namespace: analytics
+    table: events
+    schema_evolution:
+      enabled: true
+    commit_period: 1m
+    batching:
+      count: 10000
+      period: 10s
+`).
+		Example("Write to AWS Glue", "Writes to a table of the Glue Data Catalog of an AWS account through the Iceberg REST endpoint of Glue.", `
+output:
+  iceberg:
+    catalog:
+      url: https://glue.us-east-1.amazonaws.com/iceberg
+      warehouse: "123456789012"
+      sigv4:
+        enabled: true
+        region: us-east-1
+    namespace: analytics
+    table: events
+    batching:
+      count: 10000
+      period: 10s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("iceberg", icebergOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if batchPolicy, err = conf.FieldBatchPolicy(ioFieldBatching); err != nil {
+				return
+			}
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			out, err = newIcebergOutputFromConfig(conf, mgr)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type icebergOutput struct {
+	catalogConf     *catalogConfig
+	ident           table.Identifier
+	schemaEvolution bool
+	commitPeriod    time.Duration
+
+	connMut   sync.RWMutex
+	cat       catalog.Catalog
+	tbl       *table.Table
+	committer *committer
+
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+}
+
+func newIcebergOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*icebergOutput, error) {
+	o := &icebergOutput{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+	}
+
+	var err error
+	if o.catalogConf, err = catalogConfigFromParsed(conf.Namespace(ioFieldCatalog)); err != nil {
+		return nil, err
+	}
+
+	namespace, err := conf.FieldString(ioFieldNamespace)
+	if err != nil {
+		return nil, err
+	}
+	tableName, err := conf.FieldString(ioFieldTable)
+	if err != nil {
+		return nil, err
+	}
+	o.ident = append(strings.Split(namespace, "."), tableName)
+
+	if o.schemaEvolution, err = conf.FieldBool(ioFieldSchemaEvolution, ioFieldSchemaEvolutionEnabled); err != nil {
+		return nil, err
+	}
+	if o.commitPeriod, err = conf.FieldDuration(ioFieldCommitPeriod); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *icebergOutput) Connect(ctx context.Context) error {
+	o.connMut.Lock()
+	defer o.connMut.Unlock()
+
+	if o.cat != nil {
+		return nil
+	}
+
+	cat, err := o.catalogConf.open(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open catalog: %w", err)
+	}
+	return o.connectCatalog(ctx, cat)
+}
+
+func (o *icebergOutput) connectCatalog(ctx context.Context, cat catalog.Catalog) error {
+	tbl, err := cat.LoadTable(ctx, o.ident, o.catalogConf.props)
+	if err != nil {
+		return fmt.Errorf("failed to load table: %w", err)
+	}
+	if _, err := newPartitionKeyFn(tbl.Spec(), tbl.Schema()); err != nil {
+		return err
+	}
+	if _, ok := tbl.FS().(iceio.WriteFileIO); !ok {
+		return errors.New("the file IO of the table does not support writes")
+	}
+
+	o.cat, o.tbl = cat, tbl
+	o.committer = newCommitter(cat, o.ident, o.catalogConf.props, o.commitPeriod, o.log)
+	go o.committer.loop(o.shutSig)
+	return nil
+}
+
+func (o *icebergOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	o.connMut.RLock()
+	tbl, c := o.tbl, o.committer
+	o.connMut.RUnlock()
+	if tbl == nil {
+		return service.ErrNotConnected
+	}
+
+	rows := make([]map[string]any, len(batch))
+	for i, msg := range batch {
+		v, err := msg.AsStructured()
+		if err != nil {
+			return fmt.Errorf("failed to parse message %v: %w", i, err)
+		}
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected message %v to be an object, got %T", i, v)
+		}
+		rows[i] = obj
+	}
+
+	if o.schemaEvolution {
+		var err error
+		if tbl, err = o.evolveSchema(ctx, tbl, rows); err != nil {
+			return err
+		}
+	}
+
+	pending, err := partitionRows(tbl, rows)
+	if err != nil {
+		return err
+	}
+	return c.commit(ctx, pending)
+}
+
+// evolveSchema adds columns to the schema of the table for the fields of rows
+// which aren't columns, and returns the updated table.
+func (o *icebergOutput) evolveSchema(ctx context.Context, tbl *table.Table, rows []map[string]any) (*table.Table, error) {
+	if len(newColumns(tbl.Schema(), rows)) == 0 {
+		return tbl, nil
+	}
+
+	o.connMut.Lock()
+	defer o.connMut.Unlock()
+
+	// Reload the table, as its schema or name mapping may have been updated
+	// by commits since it was loaded.
+	tbl, err := o.cat.LoadTable(ctx, o.ident, o.catalogConf.props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload table: %w", err)
+	}
+	o.tbl = tbl
+	columns := newColumns(tbl.Schema(), rows)
+	if len(columns) == 0 {
+		return tbl, nil
+	}
+
+	meta := tbl.Metadata()
+	schemaID := 0
+	for _, s := range meta.Schemas() {
+		schemaID = max(schemaID, s.ID+1)
+	}
+	schema, lastColumnID := evolveSchema(tbl.Schema(), schemaID, meta.LastColumnID(), columns)
+
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	o.log.Infof("Adding columns %v to table %v", names, strings.Join(o.ident, "."))
+
+	updates := []table.Update{
+		table.NewAddSchemaUpdate(schema, lastColumnID, false),
+		table.NewSetCurrentSchemaUpdate(-1),
+	}
+	if nm := tbl.NameMapping(); nm != nil {
+		b, err := json.Marshal(extendNameMapping(nm, schema))
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, table.NewSetPropertiesUpdate(iceberg.Properties{table.DefaultNameMappingKey: string(b)}))
+	}
+
+	if _, _, err = o.cat.CommitTable(ctx, tbl, []table.Requirement{
+		table.AssertCurrentSchemaID(tbl.Schema().ID),
+		table.AssertLastAssignedFieldID(meta.LastColumnID()),
+	}, updates); err != nil {
+		return nil, fmt.Errorf("failed to add columns %v: %w", names, err)
+	}
+
+	updated, err := o.cat.LoadTable(ctx, o.ident, o.catalogConf.props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload table: %w", err)
+	}
+	o.tbl = updated
+	return updated, nil
+}
+
+// partitionRows converts the rows to the schema of the table and groups them
+// by the partition they belong to.
+func partitionRows(tbl *table.Table, rows []map[string]any) (*pendingRows, error) {
+	arrowSchema, err := table.SchemaToArrowSchema(tbl.Schema(), nil, false, false)
+	if err != nil {
+		return nil, err
+	}
+	keyFn, err := newPartitionKeyFn(tbl.Spec(), tbl.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		rows[i] = normalizeRow(row, arrowSchema)
+	}
+
+	rec, err := recordFromRows(arrowSchema, rows)
+	if err != nil {
+		return nil, err
+	}
+	defer rec.Release()
+
+	pending := &pendingRows{
+		schemaID:    tbl.Schema().ID,
+		arrowSchema: arrowSchema,
+	}
+	keys, groups := groupRows(rec, keyFn)
+	for i, group := range groups {
+		groupRows := make([]map[string]any, len(group))
+		for j, idx := range group {
+			groupRows[j] = rows[idx]
+		}
+		pending.partitions = append(pending.partitions, partitionedRows{key: keys[i], rows: groupRows})
+	}
+	return pending, nil
+}
+
+// writeDataFile writes rows as a Parquet data file to the location of a table,
+// and returns the path of the file.
+func writeDataFile(fs iceio.WriteFileIO, location string, arrowSchema *arrow.Schema, rows []map[string]any) (string, error) {
+	rec, err := recordFromRows(arrowSchema, rows)
+	if err != nil {
+		return "", err
+	}
+	defer rec.Release()
+
+	path := fmt.Sprintf("%v/data/%v.parquet", strings.TrimSuffix(location, "/"), uuid.NewString())
+	if err := writeParquet(fs, path, rec); err != nil {
+		return "", fmt.Errorf("failed to write data file %v: %w", path, err)
+	}
+	return path, nil
+}
+
+func recordFromRows(schema *arrow.Schema, rows []map[string]any) (arrow.Record, error) {
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert messages to rows: %w", err)
+	}
+	return rec, nil
+}
+
+func writeParquet(fs iceio.WriteFileIO, path string, rec arrow.Record) error {
+	f, err := fs.Create(path)
+	if err != nil {
+		return err
+	}
+	w, err := pqarrow.NewFileWriter(rec.Schema(), f,
+		parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Zstd)),
+		pqarrow.DefaultWriterProps())
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := w.Write(rec); err != nil {
+		_ = w.Close()
+		return err
+	}
+	// Closing the writer also closes the file.
+	return w.Close()
+}
+
+func (o *icebergOutput) Close(ctx context.Context) error {
+	o.shutSig.TriggerSoftStop()
+
+	o.connMut.RLock()
+	connected := o.committer != nil
+	o.connMut.RUnlock()
+	if !connected {
+		return nil
+	}
+
+	select {
+	case <-o.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	sqlcat "github.com/apache/iceberg-go/catalog/sql"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "modernc.org/sqlite"
+)
+
+func testCatalog(t *testing.T) catalog.Catalog {
+	t.Helper()
+
+	dir := t.TempDir()
+	db, err := sql.Open("sqlite", filepath.Join(dir, "catalog.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	cat, err := sqlcat.NewCatalog("test", db, sqlcat.SQLite, iceberg.Properties{
+		"warehouse": "file://" + filepath.Join(dir, "warehouse"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, cat.CreateSQLTables(context.Background()))
+	require.NoError(t, cat.CreateNamespace(context.Background(), catalog.ToIdentifier("analytics"), nil))
+	return cat
+}
+
+func testOutput(t *testing.T, cat catalog.Catalog, yaml string) *icebergOutput {
+	t.Helper()
+
+	conf, err := icebergOutputConfig().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+
+	o, err := newIcebergOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.connectCatalog(context.Background(), cat))
+	t.Cleanup(func() { _ = o.Close(context.Background()) })
+	return o
+}
+
+func readTable(t *testing.T, cat catalog.Catalog) (rows []map[string]any) {
+	t.Helper()
+
+	tbl, err := cat.LoadTable(context.Background(), catalog.ToIdentifier("analytics", "events"), nil)
+	require.NoError(t, err)
+
+	arrowTbl, err := tbl.Scan().ToArrowTable(context.Background())
+	require.NoError(t, err)
+	defer arrowTbl.Release()
+
+	reader := array.NewTableReader(arrowTbl, -1)
+	defer reader.Release()
+	for reader.Next() {
+		rec := reader.Record()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			row := map[string]any{}
+			for j, field := range rec.Schema().Fields() {
+				if !rec.Column(j).IsNull(i) {
+					row[field.Name] = rec.Column(j).GetOneForMarshal(i)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func TestIcebergOutputPartitioned(t *testing.T) {
+	cat := testCatalog(t)
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "name", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{ID: 3, Name: "region", Type: iceberg.PrimitiveTypes.String},
+	)
+	spec := iceberg.NewPartitionSpec(iceberg.PartitionField{
+		SourceID: 3, FieldID: 1000, Name: "region", Transform: iceberg.IdentityTransform{},
+	})
+	_, err := cat.CreateTable(context.Background(), catalog.ToIdentifier("analytics", "events"), schema, catalog.WithPartitionSpec(&spec))
+	require.NoError(t, err)
+
+	o := testOutput(t, cat, `
+catalog:
+  url: http://localhost:8181
+namespace: analytics
+table: events
+commit_period: 10ms
+`)
+
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"name":"foo","region":"eu"}`)),
+		service.NewMessage([]byte(`{"id":2,"name":"bar","region":"us"}`)),
+		service.NewMessage([]byte(`{"id":3,"region":"eu","ignored":true}`)),
+	}))
+
+	tbl, err := cat.LoadTable(context.Background(), catalog.ToIdentifier("analytics", "events"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, tbl.CurrentSnapshot())
+	assert.Equal(t, "2", tbl.CurrentSnapshot().Summary.Properties["added-data-files"])
+
+	rows := readTable(t, cat)
+	assert.ElementsMatch(t, []any{int64(1), int64(2), int64(3)}, []any{rows[0]["id"], rows[1]["id"], rows[2]["id"]})
+	for _, row := range rows {
+		assert.NotContains(t, row, "ignored")
+		if row["id"] == int64(2) {
+			assert.Equal(t, "bar", row["name"])
+		}
+	}
+}
+
+func TestIcebergOutputCompaction(t *testing.T) {
+	cat := testCatalog(t)
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "region", Type: iceberg.PrimitiveTypes.String},
+	)
+	spec := iceberg.NewPartitionSpec(iceberg.PartitionField{
+		SourceID: 2, FieldID: 1000, Name: "region", Transform: iceberg.IdentityTransform{},
+	})
+	_, err := cat.CreateTable(context.Background(), catalog.ToIdentifier("analytics", "events"), schema, catalog.WithPartitionSpec(&spec))
+	require.NoError(t, err)
+
+	o := testOutput(t, cat, `
+catalog:
+  url: http://localhost:8181
+namespace: analytics
+table: events
+commit_period: 1h
+`)
+
+	tbl, err := cat.LoadTable(context.Background(), catalog.ToIdentifier("analytics", "events"), nil)
+	require.NoError(t, err)
+
+	var batch []*pendingRows
+	for i := 0; i < 10; i++ {
+		region := "eu"
+		if i%2 == 1 {
+			region = "us"
+		}
+		pending, err := partitionRows(tbl, []map[string]any{{"id": int64(i), "region": region}})
+		require.NoError(t, err)
+		batch = append(batch, pending)
+	}
+
+	paths, err := o.committer.writeFiles(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	require.NoError(t, o.committer.commitFiles(context.Background(), paths))
+
+	tbl, err = cat.LoadTable(context.Background(), catalog.ToIdentifier("analytics", "events"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, tbl.CurrentSnapshot())
+	assert.Equal(t, "2", tbl.CurrentSnapshot().Summary.Properties["added-data-files"])
+	assert.Len(t, readTable(t, cat), 10)
+}
+
+func TestIcebergOutputUnsupportedPartitionSpec(t *testing.T) {
+	cat := testCatalog(t)
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "ts", Type: iceberg.PrimitiveTypes.TimestampTz},
+	)
+	spec := iceberg.NewPartitionSpec(iceberg.PartitionField{
+		SourceID: 1, FieldID: 1000, Name: "ts_day", Transform: iceberg.DayTransform{},
+	})
+	_, err := cat.CreateTable(context.Background(), catalog.ToIdentifier("analytics", "events"), schema, catalog.WithPartitionSpec(&spec))
+	require.NoError(t, err)
+
+	conf, err := icebergOutputConfig().ParseYAML(`
+catalog:
+  url: http://localhost:8181
+namespace: analytics
+table: events
+`, nil)
+	require.NoError(t, err)
+
+	o, err := newIcebergOutputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.ErrorContains(t, o.connectCatalog(context.Background(), cat), "only identity transforms are supported")
+}
+
+func TestIcebergOutputPartitionSpecChanged(t *testing.T) {
+	cat := testCatalog(t)
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "region", Type: iceberg.PrimitiveTypes.String},
+	)
+	spec := iceberg.NewPartitionSpec(iceberg.PartitionField{
+		SourceID: 2, FieldID: 1000, Name: "region", Transform: iceberg.IdentityTransform{},
+	})
+	_, err := cat.CreateTable(context.Background(), catalog.ToIdentifier("analytics", "events"), schema, catalog.WithPartitionSpec(&spec))
+	require.NoError(t, err)
+
+	o := testOutput(t, cat, `
+catalog:
+  url: http://localhost:8181
+namespace: analytics
+table: events
+commit_period: 10ms
+`)
+
+	// Another writer changes the partition spec of the table to one which
+	// isn't supported after the output has connected.
+	tbl, err := cat.LoadTable(context.Background(), catalog.ToIdentifier("analytics", "events"), nil)
+	require.NoError(t, err)
+	bucketSpec := iceberg.NewPartitionSpecID(1, iceberg.PartitionField{
+		SourceID: 1, FieldID: 1001, Name: "id_bucket", Transform: iceberg.BucketTransform{NumBuckets: 4},
+	})
+	_, _, err = cat.CommitTable(context.Background(), tbl, nil, []table.Update{
+		table.NewAddPartitionSpecUpdate(&bucketSpec, false),
+		table.NewSetDefaultSpecUpdate(1),
+	})
+	require.NoError(t, err)
+
+	err = o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1,"region":"eu"}`)),
+		service.NewMessage([]byte(`{"id":2,"region":"eu"}`)),
+	})
+	require.ErrorContains(t, err, "only identity transforms are supported")
+
+	tbl, err = cat.LoadTable(context.Background(), catalog.ToIdentifier("analytics", "events"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, tbl.CurrentSnapshot())
+}
+
+func TestIcebergOutputGlueLint(t *testing.T) {
+	tests := []struct {
+		name        string
+		conf        string
+		errContains string
+	}{
+		{
+			name: "glue without sigv4",
+			conf: `
+iceberg:
+  catalog:
+    url: https://glue.us-east-1.amazonaws.com/iceberg
+  namespace: analytics
+  table: events
+`,
+			errContains: "requests to the AWS Glue Iceberg REST endpoint must be signed",
+		},
+		{
+			name: "glue with sigv4",
+			conf: `
+iceberg:
+  catalog:
+    url: https://glue.us-east-1.amazonaws.com/iceberg
+    sigv4:
+      enabled: true
+  namespace: analytics
+  table: events
+`,
+		},
+		{
+			name: "other catalog",
+			conf: `
+iceberg:
+  catalog:
+    url: http://localhost:8181
+  namespace: analytics
+  table: events
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := service.NewStreamBuilder().AddOutputYAML(test.conf)
+			if test.errContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.errContains)
+			}
+		})
+	}
+}
+
+func TestIcebergOutputSchemaEvolution(t *testing.T) {
+	cat := testCatalog(t)
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+	)
+	_, err := cat.CreateTable(context.Background(), catalog.ToIdentifier("analytics", "events"), schema)
+	require.NoError(t, err)
+
+	o := testOutput(t, cat, `
+catalog:
+  url: http://localhost:8181
+namespace: analytics
+table: events
+schema_evolution:
+  enabled: true
+commit_period: 10ms
+`)
+
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":1}`)),
+	}))
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":2,"name":"foo","score":1.5,"tags":["a","b"]}`)),
+	}))
+
+	tbl, err := cat.LoadTable(context.Background(), catalog.ToIdentifier("analytics", "events"), nil)
+	require.NoError(t, err)
+
+	name, ok := tbl.Schema().FindFieldByName("name")
+	require.True(t, ok)
+	assert.Equal(t, iceberg.PrimitiveTypes.String, name.Type)
+	score, ok := tbl.Schema().FindFieldByName("score")
+	require.True(t, ok)
+	assert.Equal(t, iceberg.PrimitiveTypes.Float64, score.Type)
+
+	rows := readTable(t, cat)
+	require.Len(t, rows, 2)
+	for _, row := range rows {
+		if row["id"] == int64(2) {
+			assert.Equal(t, "foo", row["name"])
+			assert.Equal(t, 1.5, row["score"])
+			assert.Equal(t, `["a","b"]`, row["tags"])
+		} else {
+			assert.NotContains(t, row, "name")
+		}
+	}
+}
+
+func TestIcebergOutputNotObject(t *testing.T) {
+	cat := testCatalog(t)
+
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64},
+	)
+	_, err := cat.CreateTable(context.Background(), catalog.ToIdentifier("analytics", "events"), schema)
+	require.NoError(t, err)
+
+	o := testOutput(t, cat, `
+catalog:
+  url: http://localhost:8181
+namespace: analytics
+table: events
+`)
+
+	ctx, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	require.ErrorContains(t, o.WriteBatch(ctx, service.MessageBatch{
+		service.NewMessage([]byte(`[1,2]`)),
+	}), "expected message 0 to be an object")
+}
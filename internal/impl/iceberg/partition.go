@@ -0,0 +1,105 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/iceberg-go"
+)
+
+// partitionKeyFn returns the partition key of a row of a record, where rows
+// with equal keys belong to the same partition.
+type partitionKeyFn func(rec arrow.Record, row int) string
+
+// newPartitionKeyFn returns a function that computes the partition key of rows
+// for a partition spec. Data files are committed without their partition values,
+// which are instead derived from the lower and upper bounds of the source
+// columns. The bounds are only equal when every row of a data file has the same
+// source value, and therefore only identity transforms are supported.
+func newPartitionKeyFn(spec iceberg.PartitionSpec, schema *iceberg.Schema) (partitionKeyFn, error) {
+	if spec.IsUnpartitioned() {
+		return nil, nil
+	}
+
+	var cols []string
+	for field := range spec.Fields() {
+		name, exists := schema.FindColumnName(field.SourceID)
+		if !exists {
+			return nil, fmt.Errorf("source column of partition field %v not found", field.Name)
+		}
+		if strings.Contains(name, ".") {
+			return nil, fmt.Errorf("partition field %v has nested source column %v, which is not supported", field.Name, name)
+		}
+		if _, ok := field.Transform.(iceberg.IdentityTransform); !ok {
+			return nil, fmt.Errorf("partition field %v has transform %v, only identity transforms are supported", field.Name, field.Transform)
+		}
+		cols = append(cols, name)
+	}
+
+	return func(rec arrow.Record, row int) string {
+		var key strings.Builder
+		for i, name := range cols {
+			if i > 0 {
+				key.WriteByte('/')
+			}
+			idx := rec.Schema().FieldIndices(name)
+			if len(idx) == 0 {
+				key.WriteString("null")
+				continue
+			}
+			col := rec.Column(idx[0])
+			if col.IsNull(row) {
+				key.WriteString("null")
+				continue
+			}
+			key.WriteString(strconv.Quote(col.ValueStr(row)))
+		}
+		return key.String()
+	}, nil
+}
+
+// groupRows returns the partition keys of a record along with the indexes of
+// its rows grouped by partition, in the order each partition is first seen.
+func groupRows(rec arrow.Record, keyFn partitionKeyFn) ([]string, [][]int) {
+	if keyFn == nil {
+		rows := make([]int, rec.NumRows())
+		for i := range rows {
+			rows[i] = i
+		}
+		return []string{""}, [][]int{rows}
+	}
+
+	var (
+		keys   []string
+		groups [][]int
+	)
+	indexes := map[string]int{}
+	for i := 0; i < int(rec.NumRows()); i++ {
+		key := keyFn(rec, i)
+		idx, exists := indexes[key]
+		if !exists {
+			idx = len(groups)
+			indexes[key] = idx
+			keys = append(keys, key)
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], i)
+	}
+	return keys, groups
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/iceberg-go"
+)
+
+// newColumns returns the fields of the rows which aren't columns of the
+// schema, with types inferred from their first non-null value, in the order
+// they're first seen.
+func newColumns(schema *iceberg.Schema, rows []map[string]any) []iceberg.NestedField {
+	var fields []iceberg.NestedField
+	seen := map[string]struct{}{}
+	for _, row := range rows {
+		for _, k := range sortedKeys(row) {
+			if _, exists := seen[k]; exists {
+				continue
+			}
+			if _, exists := schema.FindFieldByName(k); exists {
+				continue
+			}
+			t := inferType(row[k])
+			if t == nil {
+				continue
+			}
+			seen[k] = struct{}{}
+			fields = append(fields, iceberg.NestedField{Name: k, Type: t})
+		}
+	}
+	return fields
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// inferType returns the column type for a value, or nil when the value is
+// null. Objects and arrays are stored as JSON encoded strings, as their
+// structure can't be inferred reliably from a single value.
+func inferType(v any) iceberg.Type {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case bool:
+		return iceberg.PrimitiveTypes.Bool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return iceberg.PrimitiveTypes.Int64
+	case float32, float64:
+		return iceberg.PrimitiveTypes.Float64
+	case json.Number:
+		if strings.ContainsAny(t.String(), ".eE") {
+			return iceberg.PrimitiveTypes.Float64
+		}
+		return iceberg.PrimitiveTypes.Int64
+	case time.Time:
+		return iceberg.PrimitiveTypes.TimestampTz
+	}
+	return iceberg.PrimitiveTypes.String
+}
+
+// evolveSchema returns a schema with the new columns appended as optional
+// fields, along with the highest field ID of the schema.
+func evolveSchema(schema *iceberg.Schema, id, lastColumnID int, columns []iceberg.NestedField) (*iceberg.Schema, int) {
+	fields := schema.Fields()
+	for _, c := range columns {
+		lastColumnID++
+		c.ID = lastColumnID
+		c.Required = false
+		fields = append(fields, c)
+	}
+	return iceberg.NewSchemaWithIdentifiers(id, schema.IdentifierFieldIDs, fields...), lastColumnID
+}
+
+// extendNameMapping returns a copy of a name mapping with mappings for columns
+// of the schema which aren't mapped yet. Data files are written without field
+// IDs, and so new columns are only read from them once mapped.
+func extendNameMapping(nm iceberg.NameMapping, schema *iceberg.Schema) iceberg.NameMapping {
+	mapped := map[string]bool{}
+	for _, f := range nm {
+		for _, name := range f.Names {
+			mapped[name] = true
+		}
+	}
+
+	extended := slices.Clone(nm)
+	for _, f := range schema.Fields() {
+		if mapped[f.Name] {
+			continue
+		}
+		for _, m := range schema.NameMapping() {
+			if m.FieldID != nil && *m.FieldID == f.ID {
+				extended = append(extended, m)
+			}
+		}
+	}
+	return extended
+}
+
+// normalizeRow converts the values of a row into the representation expected
+// when decoding JSON into arrow arrays of the schema, and drops fields which
+// aren't in the schema.
+func normalizeRow(row map[string]any, schema *arrow.Schema) map[string]any {
+	out := make(map[string]any, len(schema.Fields()))
+	for _, f := range schema.Fields() {
+		if v, exists := row[f.Name]; exists {
+			out[f.Name] = normalizeValue(v, f.Type)
+		}
+	}
+	return out
+}
+
+func normalizeValue(v any, t arrow.DataType) any {
+	if v == nil {
+		return nil
+	}
+	switch at := t.(type) {
+	case *arrow.StringType, *arrow.LargeStringType:
+		if s, ok := v.(string); ok {
+			return s
+		}
+		if b, err := json.Marshal(v); err == nil {
+			return string(b)
+		}
+	case *arrow.TimestampType:
+		if ts, ok := v.(time.Time); ok {
+			if at.Unit == arrow.Nanosecond {
+				return ts.UTC().Format("2006-01-02T15:04:05.999999999")
+			}
+			return ts.UTC().Format("2006-01-02T15:04:05.999999")
+		}
+	case *arrow.Date32Type:
+		if ts, ok := v.(time.Time); ok {
+			return ts.Format("2006-01-02")
+		}
+	case *arrow.StructType:
+		if m, ok := v.(map[string]any); ok {
+			out := make(map[string]any, at.NumFields())
+			for _, f := range at.Fields() {
+				if fv, exists := m[f.Name]; exists {
+					out[f.Name] = normalizeValue(fv, f.Type)
+				}
+			}
+			return out
+		}
+	case *arrow.MapType:
+		if m, ok := v.(map[string]any); ok {
+			out := make([]any, 0, len(m))
+			for _, k := range sortedKeys(m) {
+				out = append(out, map[string]any{
+					"key":   normalizeValue(k, at.KeyType()),
+					"value": normalizeValue(m[k], at.ItemType()),
+				})
+			}
+			return out
+		}
+	case arrow.ListLikeType:
+		if arr, ok := v.([]any); ok {
+			out := make([]any, len(arr))
+			for i, e := range arr {
+				out[i] = normalizeValue(e, at.Elem())
+			}
+			return out
+		}
+	}
+	return v
+}
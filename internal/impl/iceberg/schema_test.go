@@ -0,0 +1,91 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iceberg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumns(t *testing.T) {
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+	)
+
+	columns := newColumns(schema, []map[string]any{
+		{"id": 1, "b": nil, "a": "foo"},
+		{"id": 2, "b": json.Number("1.5"), "c": true, "d": time.Now(), "e": map[string]any{"f": 1}},
+	})
+	assert.Equal(t, []iceberg.NestedField{
+		{Name: "a", Type: iceberg.PrimitiveTypes.String},
+		{Name: "b", Type: iceberg.PrimitiveTypes.Float64},
+		{Name: "c", Type: iceberg.PrimitiveTypes.Bool},
+		{Name: "d", Type: iceberg.PrimitiveTypes.TimestampTz},
+		{Name: "e", Type: iceberg.PrimitiveTypes.String},
+	}, columns)
+
+	evolved, lastColumnID := evolveSchema(schema, 1, 1, columns)
+	assert.Equal(t, 6, lastColumnID)
+	assert.Equal(t, 1, evolved.ID)
+	field, ok := evolved.FindFieldByName("e")
+	require.True(t, ok)
+	assert.Equal(t, 6, field.ID)
+	assert.False(t, field.Required)
+
+	nm := extendNameMapping(schema.NameMapping(), evolved)
+	require.Len(t, nm, 6)
+	assert.Equal(t, []string{"e"}, nm[5].Names)
+	assert.Equal(t, 6, *nm[5].FieldID)
+}
+
+func TestNormalizeRow(t *testing.T) {
+	schema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "s", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{ID: 2, Name: "ts", Type: iceberg.PrimitiveTypes.TimestampTz},
+		iceberg.NestedField{ID: 3, Name: "m", Type: &iceberg.MapType{
+			KeyID: 4, KeyType: iceberg.PrimitiveTypes.String,
+			ValueID: 5, ValueType: iceberg.PrimitiveTypes.Int64,
+		}},
+	)
+	arrowSchema, err := table.SchemaToArrowSchema(schema, nil, false, false)
+	require.NoError(t, err)
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 6, time.FixedZone("", 3600))
+	row := normalizeRow(map[string]any{
+		"s":     map[string]any{"a": 1},
+		"ts":    ts,
+		"m":     map[string]any{"b": 2, "a": 1},
+		"other": "dropped",
+	}, arrowSchema)
+	assert.Equal(t, map[string]any{
+		"s":  `{"a":1}`,
+		"ts": "2025-01-02T02:04:05",
+		"m": []any{
+			map[string]any{"key": "a", "value": 1},
+			map[string]any{"key": "b", "value": 2},
+		},
+	}, row)
+
+	rec, err := recordFromRows(arrowSchema, []map[string]any{row, normalizeRow(map[string]any{"ts": "2025-01-02T03:04:05Z"}, arrowSchema)})
+	require.NoError(t, err)
+	defer rec.Release()
+	assert.Equal(t, int64(2), rec.NumRows())
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/itchyny/gojq"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func init() {
+	if err := bloblang.RegisterMethodV2("jq",
+		bloblang.NewPluginSpec().
+			Beta().
+			Version("4.48.0").
+			Category("Object & Array Manipulation").
+			Description(`Executes a https://jqlang.github.io/jq/manual/[jq^] expression against the target value using https://github.com/itchyny/gojq[gojq^] and returns the result. When the expression emits a single value that value is returned, when it emits no values `+"`null`"+` is returned, and when it emits multiple values they are returned as an array. Wrap the expression in square brackets in order to always obtain an array.
+
+Environment variables are not exposed to expressions, and therefore `+"`env`"+` and `+"`$ENV`"+` are always empty objects. The `+"`input`"+` and `+"`inputs`"+` functions are not supported.`).
+			Example("", `root.names = this.jq(".users[] | select(.age > 30) | .name")`, [2]string{
+				`{"users":[{"name":"alice","age":35},{"name":"bob","age":25},{"name":"carol","age":40}]}`,
+				`{"names":["alice","carol"]}`,
+			}, [2]string{
+				`{"users":[{"name":"alice","age":35},{"name":"bob","age":25}]}`,
+				`{"names":"alice"}`,
+			}).
+			Example("", `root = this.jq("[.items[] | {id, total: (.price * .quantity)}]")`, [2]string{
+				`{"items":[{"id":"a","price":2,"quantity":3},{"id":"b","price":1.5,"quantity":2}]}`,
+				`[{"id":"a","total":6},{"id":"b","total":3}]`,
+			}).
+			Param(bloblang.NewStringParam("expression").Description("The jq expression to execute.")),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			expressionStr, err := args.GetString("expression")
+			if err != nil {
+				return nil, err
+			}
+			query, err := gojq.Parse(expressionStr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse jq expression: %w", err)
+			}
+			code, err := gojq.Compile(query, gojq.WithEnvironLoader(func() []string { return nil }))
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile jq expression: %w", err)
+			}
+			return func(v any) (any, error) {
+				return run(code, v)
+			}, nil
+		}); err != nil {
+		panic(err)
+	}
+}
+
+func run(code *gojq.Code, v any) (any, error) {
+	var results []any
+	iter := code.RunWithContext(context.Background(), toJQ(v))
+	for {
+		r, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := r.(error); ok {
+			if herr, ok := err.(*gojq.HaltError); ok && herr.Value() == nil {
+				break
+			}
+			return nil, err
+		}
+		results = append(results, fromJQ(r))
+	}
+	switch len(results) {
+	case 0:
+		return nil, nil
+	case 1:
+		return results[0], nil
+	}
+	return results, nil
+}
+
+// toJQ converts a Bloblang value into the subset of types supported by gojq.
+func toJQ(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[k] = toJQ(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(t))
+		for i, e := range t {
+			s[i] = toJQ(e)
+		}
+		return s
+	case int64:
+		if t >= math.MinInt && t <= math.MaxInt {
+			return int(t)
+		}
+		return big.NewInt(t)
+	case int32:
+		return int(t)
+	case uint64:
+		if t <= math.MaxInt {
+			return int(t)
+		}
+		return new(big.Int).SetUint64(t)
+	case uint32:
+		return int(t)
+	case float32:
+		return float64(t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return toJQ(i)
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	case []byte:
+		return string(t)
+	case time.Time:
+		return t.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// fromJQ converts a gojq result into a Bloblang value.
+func fromJQ(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = fromJQ(e)
+		}
+		return t
+	case []any:
+		for i, e := range t {
+			t[i] = fromJQ(e)
+		}
+		return t
+	case int:
+		return int64(t)
+	case *big.Int:
+		if t.IsInt64() {
+			return t.Int64()
+		}
+		f, _ := new(big.Float).SetInt(t).Float64()
+		return f
+	}
+	return v
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jq
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func TestJQ(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mapping string
+		target  any
+		exp     any
+		err     string
+	}{
+		{
+			name:    "single result",
+			mapping: `root = this.jq(".foo.bar")`,
+			target:  map[string]any{"foo": map[string]any{"bar": "baz"}},
+			exp:     "baz",
+		},
+		{
+			name:    "multiple results",
+			mapping: `root = this.jq(".[] | . * 2")`,
+			target:  []any{int64(1), json.Number("2"), 3.5},
+			exp:     []any{int64(2), int64(4), 7.0},
+		},
+		{
+			name:    "no results",
+			mapping: `root = this.jq(".[] | select(. > 5)")`,
+			target:  []any{int64(1), int64(2)},
+			exp:     nil,
+		},
+		{
+			name:    "array result",
+			mapping: `root = this.jq("[.[] | select(. > 1)]")`,
+			target:  []any{int64(1), int64(2), int64(3)},
+			exp:     []any{int64(2), int64(3)},
+		},
+		{
+			name:    "object construction",
+			mapping: `root = this.jq("{name: .user, tags: (.tags | join(\",\"))}")`,
+			target:  map[string]any{"user": "alice", "tags": []any{"a", []byte("b")}},
+			exp:     map[string]any{"name": "alice", "tags": "a,b"},
+		},
+		{
+			name:    "environment is hidden",
+			mapping: `root = this.jq("env | length")`,
+			target:  nil,
+			exp:     int64(0),
+		},
+		{
+			name:    "runtime error",
+			mapping: `root = this.jq(".foo + 1")`,
+			target:  map[string]any{"foo": "bar"},
+			err:     `cannot add: string ("bar") and number (1)`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(test.mapping)
+			require.NoError(t, err)
+
+			res, err := exec.Query(test.target)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.exp, res)
+		})
+	}
+}
+
+func TestJQTargetUnchanged(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.jq(".a = 2")`)
+	require.NoError(t, err)
+
+	target := map[string]any{"a": int64(1)}
+	res, err := exec.Query(target)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": int64(2)}, res)
+	assert.Equal(t, map[string]any{"a": int64(1)}, target)
+}
+
+func TestJQParseError(t *testing.T) {
+	_, err := bloblang.Parse(`root = this.jq(".foo[")`)
+	require.ErrorContains(t, err, "failed to parse jq expression")
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// Mappings with schemas that are not static, such as those obtained from
+// metadata, would otherwise need to compile the schema for each execution.
+const schemaCacheSize = 256
+
+var schemaCache, _ = lru.New[string, *gojsonschema.Schema](schemaCacheSize)
+
+// keywords maps gojsonschema error types to the JSON Schema keyword that
+// failed.
+var keywords = map[string]string{
+	"invalid_type":                    "type",
+	"number_any_of":                   "anyOf",
+	"number_one_of":                   "oneOf",
+	"number_all_of":                   "allOf",
+	"number_not":                      "not",
+	"missing_dependency":              "dependencies",
+	"array_no_additional_items":       "additionalItems",
+	"array_min_items":                 "minItems",
+	"array_max_items":                 "maxItems",
+	"unique":                          "uniqueItems",
+	"array_min_properties":            "minProperties",
+	"array_max_properties":            "maxProperties",
+	"additional_property_not_allowed": "additionalProperties",
+	"invalid_property_pattern":        "patternProperties",
+	"invalid_property_name":           "propertyNames",
+	"string_gte":                      "minLength",
+	"string_lte":                      "maxLength",
+	"multiple_of":                     "multipleOf",
+	"number_gte":                      "minimum",
+	"number_gt":                       "exclusiveMinimum",
+	"number_lte":                      "maximum",
+	"number_lt":                       "exclusiveMaximum",
+	"condition_then":                  "then",
+	"condition_else":                  "else",
+}
+
+func init() {
+	if err := bloblang.RegisterMethodV2("validate_json_schema",
+		bloblang.NewPluginSpec().
+			Beta().
+			Version("4.48.0").
+			Category("Object & Array Manipulation").
+			Description(`Validates the target value against a https://json-schema.org/[JSON Schema^] and returns an array of validation errors, which is empty when the value is valid. Each error is an object containing the `+"`instance_path`"+` of the invalid value as a JSON pointer, the JSON Schema `+"`keyword`"+` that failed, and a human readable `+"`message`"+`.
+
+The schema can either be a JSON string or an object. Compiled schemas are cached, and therefore schemas obtained dynamically, such as from metadata, are only compiled once for as long as they remain in the cache.`).
+			Example("", `root.errors = this.validate_json_schema("""{"type":"object","properties":{"age":{"type":"integer","minimum":0}},"required":["name"]}""")`, [2]string{
+				`{"name":"alice","age":30}`,
+				`{"errors":[]}`,
+			}, [2]string{
+				`{"age":-1}`,
+				`{"errors":[{"instance_path":"","keyword":"required","message":"name is required"},{"instance_path":"/age","keyword":"minimum","message":"Must be greater than or equal to 0"}]}`,
+			}).
+			Example("Branch on specific violations.", `let errors = this.validate_json_schema({"required":["id"],"properties":{"tags":{"type":"array"}}})
+root = if $errors.any(e -> e.keyword == "required") {
+  deleted()
+} else {
+  this.merge({"errors": $errors.map_each(e -> e.instance_path)})
+}`, [2]string{
+				`{"id":"foo","tags":"bar"}`,
+				`{"errors":["/tags"],"id":"foo","tags":"bar"}`,
+			}).
+			Param(bloblang.NewAnyParam("schema").Description("The JSON Schema to validate against, either as a JSON string or an object.")),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			schemaArg, err := args.Get("schema")
+			if err != nil {
+				return nil, err
+			}
+			schema, err := compileSchema(schemaArg)
+			if err != nil {
+				return nil, err
+			}
+			return func(v any) (any, error) {
+				return validate(schema, v)
+			}, nil
+		}); err != nil {
+		panic(err)
+	}
+}
+
+func compileSchema(v any) (*gojsonschema.Schema, error) {
+	var schemaStr string
+	switch t := v.(type) {
+	case string:
+		schemaStr = t
+	case []byte:
+		schemaStr = string(t)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialise schema: %w", err)
+		}
+		schemaStr = string(b)
+	}
+
+	if schema, ok := schemaCache.Get(schemaStr); ok {
+		return schema, nil
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(schemaStr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile json schema: %w", err)
+	}
+	schemaCache.Add(schemaStr, schema)
+	return schema, nil
+}
+
+func validate(schema *gojsonschema.Schema, v any) (any, error) {
+	res, err := schema.Validate(gojsonschema.NewGoLoader(v))
+	if err != nil {
+		return nil, err
+	}
+
+	resErrs := res.Errors()
+	sort.SliceStable(resErrs, func(i, j int) bool {
+		return instancePath(resErrs[i].Context()) < instancePath(resErrs[j].Context())
+	})
+
+	errs := make([]any, 0, len(resErrs))
+	for _, e := range resErrs {
+		keyword, exists := keywords[e.Type()]
+		if !exists {
+			keyword = e.Type()
+		}
+		errs = append(errs, map[string]any{
+			"instance_path": instancePath(e.Context()),
+			"keyword":       keyword,
+			"message":       e.Description(),
+		})
+	}
+	return errs, nil
+}
+
+// instancePath converts the context of an error into a JSON pointer.
+func instancePath(ctx *gojsonschema.JsonContext) string {
+	if ctx == nil {
+		return ""
+	}
+	// The first segment is always the root of the document.
+	segments := strings.Split(ctx.String("\x00"), "\x00")[1:]
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func TestValidateJSONSchema(t *testing.T) {
+	schema := `{
+  "type": "object",
+  "properties": {
+    "name": {"type": "string", "minLength": 2},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "a/b": {"enum": ["x", "y"]}
+  },
+  "required": ["name"],
+  "additionalProperties": false
+}`
+
+	testCases := []struct {
+		name   string
+		target any
+		exp    any
+	}{
+		{
+			name:   "valid",
+			target: map[string]any{"name": "alice", "tags": []any{"a"}},
+			exp:    []any{},
+		},
+		{
+			name:   "missing required",
+			target: map[string]any{"tags": []any{}},
+			exp: []any{
+				map[string]any{"instance_path": "", "keyword": "required", "message": "name is required"},
+			},
+		},
+		{
+			name: "nested errors",
+			target: map[string]any{
+				"name": "a",
+				"tags": []any{"a", int64(5)},
+				"a/b":  "z",
+				"nope": true,
+			},
+			exp: []any{
+				map[string]any{"instance_path": "", "keyword": "additionalProperties", "message": "Additional property nope is not allowed"},
+				map[string]any{"instance_path": "/a~1b", "keyword": "enum", "message": `a/b must be one of the following: "x", "y"`},
+				map[string]any{"instance_path": "/name", "keyword": "minLength", "message": "String length must be greater than or equal to 2"},
+				map[string]any{"instance_path": "/tags/1", "keyword": "type", "message": "Invalid type. Expected: string, given: integer"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(`root = this.validate_json_schema(` + "\"\"\"" + schema + "\"\"\"" + `)`)
+			require.NoError(t, err)
+
+			res, err := exec.Query(test.target)
+			require.NoError(t, err)
+			assert.Equal(t, test.exp, res)
+		})
+	}
+}
+
+func TestValidateJSONSchemaObject(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.value.validate_json_schema(this.schema)`)
+	require.NoError(t, err)
+
+	for range 2 {
+		res, err := exec.Query(map[string]any{
+			"schema": map[string]any{"type": "integer"},
+			"value":  "foo",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []any{
+			map[string]any{"instance_path": "", "keyword": "type", "message": "Invalid type. Expected: integer, given: string"},
+		}, res)
+	}
+	assert.True(t, schemaCache.Contains(`{"type":"integer"}`))
+}
+
+func TestValidateJSONSchemaInvalidSchema(t *testing.T) {
+	exec, err := bloblang.Parse(`root = this.validate_json_schema("""{"type":"nope"}""")`)
+	if err == nil {
+		_, err = exec.Query(map[string]any{})
+	}
+	require.ErrorContains(t, err, "failed to compile json schema")
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+const headerFidelityDescription = `
+
+Kafka inputs store each record header as a string holding the raw bytes of the header value, and therefore binary header values survive a round trip through a pipeline unchanged. Kafka outputs write string and byte array metadata values into headers byte for byte, arrays (such as those produced by the ` + "`multi_header`" + ` mode of the ` + "`kafka`" + ` input) as one header per element, and all other values as their string representation.`
+
+func init() {
+	asBytesSpec := bloblang.NewPluginSpec().
+		Category("Type Coercion").
+		Version("4.48.0").
+		Description("Returns the raw bytes of a string or byte array value, such as a metadata value read from a binary Kafka record header, without serialising it. Unlike `bytes`, values of any other type result in an error."+headerFidelityDescription).
+		Example("",
+			`root.raw = this.value.as_bytes().encode("hex")`,
+			[2]string{
+				`{"value":"foo"}`,
+				`{"raw":"666f6f"}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"as_bytes", asBytesSpec,
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			return func(v any) (any, error) {
+				return headerValueBytes(v)
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	asInt64Spec := bloblang.NewPluginSpec().
+		Category("Type Coercion").
+		Version("4.48.0").
+		Description("Returns a metadata value, such as one read from a Kafka record header, as a 64-bit signed integer. Numbers are converted and strings or byte arrays are parsed as decimal integers, unless `big_endian` is set, in which case they must hold exactly eight bytes which are decoded as a big-endian two's complement integer."+headerFidelityDescription).
+		Param(bloblang.NewBoolParam("big_endian").Description("Whether to decode strings and byte arrays as eight byte big-endian integers.").Default(false)).
+		Example("",
+			`root.count = this.count.as_int64()`,
+			[2]string{
+				`{"count":"1234"}`,
+				`{"count":1234}`,
+			}).
+		Example("Decode a binary header value.",
+			`root.count = this.count.decode("hex").as_int64(big_endian: true)`,
+			[2]string{
+				`{"count":"00000000000004d2"}`,
+				`{"count":1234}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"as_int64", asInt64Spec,
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			bigEndian, err := args.GetBool("big_endian")
+			if err != nil {
+				return nil, err
+			}
+			return func(v any) (any, error) {
+				return headerValueInt64(v, bigEndian)
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	asTimestampSpec := bloblang.NewPluginSpec().
+		Category("Type Coercion").
+		Version("4.48.0").
+		Description("Returns a metadata value, such as one read from a Kafka record header, as a timestamp. Timestamps are returned as they are, strings are parsed as RFC 3339 and integers, including those decoded when `big_endian` is set, are interpreted as milliseconds since the Unix epoch, which is how Kafka represents timestamps."+headerFidelityDescription).
+		Param(bloblang.NewBoolParam("big_endian").Description("Whether to decode strings and byte arrays as eight byte big-endian integers of milliseconds since the Unix epoch.").Default(false)).
+		Example("",
+			`root.created_at = this.created_at.as_timestamp().ts_format("2006-01-02T15:04:05.000Z07:00", "UTC")`,
+			[2]string{
+				`{"created_at":1700000000123}`,
+				`{"created_at":"2023-11-14T22:13:20.123Z"}`,
+			}).
+		Example("Decode a binary header value.",
+			`root.created_at = this.created_at.decode("hex").as_timestamp(big_endian: true).ts_format("2006-01-02T15:04:05.000Z07:00", "UTC")`,
+			[2]string{
+				`{"created_at":"0000018bcfe5687b"}`,
+				`{"created_at":"2023-11-14T22:13:20.123Z"}`,
+			})
+
+	if err := bloblang.RegisterMethodV2(
+		"as_timestamp", asTimestampSpec,
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			bigEndian, err := args.GetBool("big_endian")
+			if err != nil {
+				return nil, err
+			}
+			return func(v any) (any, error) {
+				return headerValueTimestamp(v, bigEndian)
+			}, nil
+		},
+	); err != nil {
+		panic(err)
+	}
+}
+
+func headerValueBytes(v any) ([]byte, error) {
+	switch t := v.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		return []byte(t), nil
+	}
+	return nil, fmt.Errorf("expected string or byte array value, got %T", v)
+}
+
+func headerValueInt64(v any, bigEndian bool) (int64, error) {
+	switch v.(type) {
+	case []byte, string:
+	default:
+		return bloblang.ValueAsInt64(v)
+	}
+
+	b, _ := headerValueBytes(v)
+	if bigEndian {
+		if len(b) != 8 {
+			return 0, fmt.Errorf("expected 8 bytes for a big-endian integer, got %v", len(b))
+		}
+		return int64(binary.BigEndian.Uint64(b)), nil
+	}
+	return strconv.ParseInt(string(b), 10, 64)
+}
+
+func headerValueTimestamp(v any, bigEndian bool) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case []byte, string:
+		if !bigEndian {
+			b, _ := headerValueBytes(v)
+			return time.Parse(time.RFC3339Nano, string(b))
+		}
+	}
+	ms, err := headerValueInt64(v, bigEndian)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
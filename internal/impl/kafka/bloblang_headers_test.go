@@ -0,0 +1,81 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestHeaderValueMethods(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+
+	tests := []struct {
+		name    string
+		mapping string
+		meta    any
+		want    any
+		wantErr string
+	}{
+		{name: "bytes from binary string", mapping: `root = @v.as_bytes()`, meta: string([]byte{0x00, 0xff}), want: []byte{0x00, 0xff}},
+		{name: "bytes from bytes", mapping: `root = @v.as_bytes()`, meta: []byte{0x00, 0xfe}, want: []byte{0x00, 0xfe}},
+		{name: "bytes from number", mapping: `root = @v.as_bytes()`, meta: int64(5), wantErr: "expected string or byte array value, got int64"},
+		{name: "int64 from string", mapping: `root = @v.as_int64()`, meta: "-42", want: int64(-42)},
+		{name: "int64 from number", mapping: `root = @v.as_int64()`, meta: int64(7), want: int64(7)},
+		{name: "int64 big endian", mapping: `root = @v.as_int64(big_endian: true)`, meta: string([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}), want: int64(-2)},
+		{name: "int64 big endian wrong size", mapping: `root = @v.as_int64(big_endian: true)`, meta: []byte{0x01}, wantErr: "expected 8 bytes for a big-endian integer, got 1"},
+		{name: "timestamp from millis", mapping: `root = @v.as_timestamp()`, meta: int64(1700000000123), want: ts},
+		{name: "timestamp from string", mapping: `root = @v.as_timestamp()`, meta: "2023-11-14T22:13:20.123Z", want: ts},
+		{name: "timestamp big endian", mapping: `root = @v.as_timestamp(big_endian: true)`, meta: []byte{0x00, 0x00, 0x01, 0x8b, 0xcf, 0xe5, 0x68, 0x7b}, want: ts},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(test.mapping)
+			require.NoError(t, err)
+
+			msg := service.NewMessage(nil)
+			msg.MetaSetMut("v", test.meta)
+
+			res, err := msg.BloblangQuery(exec)
+			if test.wantErr != "" {
+				require.ErrorContains(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if want, ok := test.want.([]byte); ok {
+				b, err := res.AsBytes()
+				require.NoError(t, err)
+				assert.Equal(t, want, b)
+				return
+			}
+
+			v, err := res.AsStructured()
+			require.NoError(t, err)
+			if want, ok := test.want.(time.Time); ok {
+				assert.True(t, want.Equal(v.(time.Time)), "%v != %v", want, v)
+				return
+			}
+			assert.Equal(t, test.want, v)
+		})
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	rtcFieldTopic       = "topic"
+	rtcFieldCreateTopic = "create_topic"
+	rtcFieldDefaultTTL  = "default_ttl"
+
+	// rtcExpiresAtHeader is the record header which holds the unix timestamp
+	// in milliseconds at which a cached value expires.
+	rtcExpiresAtHeader = "expires_at"
+)
+
+func redpandaTopicCacheConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.48.0").
+		Summary("Stores key/value pairs in a compacted Kafka topic and serves reads from a local in-memory view of the topic.").
+		Description(`
+Each cached value is written to the topic as a record keyed by the cache key, and deletions are written as tombstones. When the cache is created it consumes the topic from the beginning in order to materialise the latest value of each key in memory, similar to a KTable, and it then keeps consuming the topic in order to pick up writes from other instances. Reads block until the topic has been consumed up to the end offsets observed on startup, which means that cached values survive restarts without needing a separate key/value store.
+
+The topic should be configured with `+"`cleanup.policy=compact`"+` so that the brokers only retain the latest value of each key, which is done automatically when the topic is created via the `+"`create_topic`"+` field.
+
+=== Consistency
+
+Writes are acknowledged once they have been written to the topic, and are immediately visible to reads of the same cache. Writes of other instances are visible once they have been consumed. The `+"`add`"+` operation only checks the local view of the topic, and therefore two instances adding the same key at the same time can both succeed.
+
+=== TTLs
+
+Values written with a TTL carry an `+"`expires_at`"+` header and are treated as missing once they have expired. Expired values are not removed from the topic, and therefore the topic should also be configured with a `+"`retention.ms`"+` when all values are written with a TTL.
+`).
+		Fields(redpandaTopicCacheConfigFields()...).
+		Example("Deduplication", "Drops messages with an ID which has already been seen within the last 24 hours, where the IDs are shared between all instances of the pipeline and survive restarts.", `
+pipeline:
+  processors:
+    - dedupe:
+        cache: seen_ids
+        key: ${! @id }
+
+cache_resources:
+  - label: seen_ids
+    redpanda_topic:
+      seed_brokers: [ localhost:9092 ]
+      topic: seen_ids
+      create_topic: true
+      default_ttl: 24h
+`)
+}
+
+func redpandaTopicCacheConfigFields() []*service.ConfigField {
+	return slices.Concat(
+		FranzConnectionFields(),
+		[]*service.ConfigField{
+			service.NewStringField(rtcFieldTopic).
+				Description("The compacted topic to store cached values in."),
+			service.NewBoolField(rtcFieldCreateTopic).
+				Description("Whether to create the topic with `cleanup.policy=compact` when it does not exist. The topic is created with the default number of partitions and replication factor of the cluster.").
+				Default(false),
+			service.NewDurationField(rtcFieldDefaultTTL).
+				Description("An optional default TTL to set for values which are written without a TTL.").
+				Optional().
+				Advanced(),
+		},
+	)
+}
+
+func init() {
+	err := service.RegisterCache("redpanda_topic", redpandaTopicCacheConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Cache, error) {
+			return newRedpandaTopicCacheFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type redpandaTopicCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+	partition int32
+	offset    int64
+	deleted   bool
+}
+
+func (e *redpandaTopicCacheEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// redpandaTopicCacheView is the in-memory view of the latest value of each key
+// within a compacted topic.
+//
+// Entries carry the offset of the record they were written with, so that
+// records which are consumed after a more recent write of the same key has
+// already been applied locally are ignored. Deleted keys are kept as entries
+// until their tombstone has been consumed for the same reason.
+type redpandaTopicCacheView struct {
+	mut     sync.RWMutex
+	entries map[string]*redpandaTopicCacheEntry
+}
+
+func newRedpandaTopicCacheView() *redpandaTopicCacheView {
+	return &redpandaTopicCacheView{entries: map[string]*redpandaTopicCacheEntry{}}
+}
+
+func recordExpiresAt(r *kgo.Record) time.Time {
+	for _, h := range r.Headers {
+		if h.Key != rtcExpiresAtHeader {
+			continue
+		}
+		if ms, err := strconv.ParseInt(string(h.Value), 10, 64); err == nil {
+			return time.UnixMilli(ms)
+		}
+	}
+	return time.Time{}
+}
+
+// apply updates the view with a record which has been consumed from, or
+// written to, the topic.
+func (v *redpandaTopicCacheView) apply(r *kgo.Record) {
+	key := string(r.Key)
+
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if e, exists := v.entries[key]; exists && e.partition == r.Partition && e.offset > r.Offset {
+		return
+	}
+	if r.Value == nil {
+		v.entries[key] = &redpandaTopicCacheEntry{partition: r.Partition, offset: r.Offset, deleted: true}
+		return
+	}
+	v.entries[key] = &redpandaTopicCacheEntry{
+		value:     r.Value,
+		expiresAt: recordExpiresAt(r),
+		partition: r.Partition,
+		offset:    r.Offset,
+	}
+}
+
+// consumed updates the view with a record which has been consumed from the
+// topic, where tombstones of deleted keys are removed from the view.
+func (v *redpandaTopicCacheView) consumed(r *kgo.Record) {
+	v.apply(r)
+	if r.Value != nil {
+		return
+	}
+
+	v.mut.Lock()
+	defer v.mut.Unlock()
+	if e, exists := v.entries[string(r.Key)]; exists && e.deleted && e.partition == r.Partition && e.offset <= r.Offset {
+		delete(v.entries, string(r.Key))
+	}
+}
+
+func (v *redpandaTopicCacheView) get(key string, now time.Time) ([]byte, bool) {
+	v.mut.RLock()
+	defer v.mut.RUnlock()
+
+	e, exists := v.entries[key]
+	if !exists || e.deleted || e.expired(now) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+//------------------------------------------------------------------------------
+
+type redpandaTopicCache struct {
+	topic      string
+	defaultTTL *time.Duration
+
+	log    *service.Logger
+	client *kgo.Client
+	view   *redpandaTopicCacheView
+
+	// Serialises adds so that concurrent adds of the same key by this instance
+	// cannot both succeed.
+	addMut sync.Mutex
+
+	loaded    chan struct{}
+	closeFn   context.CancelFunc
+	closedSig chan struct{}
+}
+
+func newRedpandaTopicCacheFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*redpandaTopicCache, error) {
+	c := redpandaTopicCache{
+		log:       mgr.Logger(),
+		view:      newRedpandaTopicCacheView(),
+		loaded:    make(chan struct{}),
+		closedSig: make(chan struct{}),
+	}
+
+	clientOpts, err := FranzConnectionOptsFromConfig(conf, mgr.Logger())
+	if err != nil {
+		return nil, err
+	}
+
+	if c.topic, err = conf.FieldString(rtcFieldTopic); err != nil {
+		return nil, err
+	}
+	if c.topic == "" {
+		return nil, errors.New("a topic must be specified")
+	}
+
+	createTopic, err := conf.FieldBool(rtcFieldCreateTopic)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Contains(rtcFieldDefaultTTL) {
+		ttl, err := conf.FieldDuration(rtcFieldDefaultTTL)
+		if err != nil {
+			return nil, err
+		}
+		c.defaultTTL = &ttl
+	}
+
+	clientOpts = append(clientOpts,
+		kgo.ConsumeTopics(c.topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.DefaultProduceTopic(c.topic),
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)),
+		// Control records are kept so that the consumed offsets reach the end
+		// offsets of partitions which end with transaction markers.
+		kgo.KeepControlRecords(),
+	)
+	if c.client, err = kgo.NewClient(clientOpts...); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.closeFn = cancel
+	go c.loop(ctx, createTopic)
+	return &c, nil
+}
+
+// loadOffsets creates the topic if required and returns the start and end
+// offsets of its partitions.
+func (c *redpandaTopicCache) loadOffsets(ctx context.Context, createTopic bool) (start, end kadm.ListedOffsets, err error) {
+	adm := kadm.NewClient(c.client)
+	if createTopic {
+		_, err := adm.CreateTopic(ctx, -1, -1, map[string]*string{
+			"cleanup.policy": kadm.StringPtr("compact"),
+		}, c.topic)
+		if err != nil && !errors.Is(err, kerr.TopicAlreadyExists) {
+			return nil, nil, fmt.Errorf("failed to create topic %q: %w", c.topic, err)
+		}
+	}
+
+	if start, err = adm.ListStartOffsets(ctx, c.topic); err == nil {
+		err = start.Error()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list start offsets of topic %q: %w", c.topic, err)
+	}
+	if end, err = adm.ListEndOffsets(ctx, c.topic); err == nil {
+		err = end.Error()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list end offsets of topic %q: %w", c.topic, err)
+	}
+	return start, end, nil
+}
+
+// redpandaTopicCacheProgress tracks the offsets which must be consumed from
+// each partition of the topic in order for the view to be loaded.
+type redpandaTopicCacheProgress map[int32]int64
+
+func newRedpandaTopicCacheProgress(start, end kadm.ListedOffsets) redpandaTopicCacheProgress {
+	p := redpandaTopicCacheProgress{}
+	end.Each(func(o kadm.ListedOffset) {
+		if s, exists := start.Lookup(o.Topic, o.Partition); !exists || s.Offset < o.Offset {
+			p[o.Partition] = o.Offset
+		}
+	})
+	return p
+}
+
+// consumed marks a partition as loaded once the record at the given offset,
+// which includes control records, reaches its end offset.
+func (p redpandaTopicCacheProgress) consumed(partition int32, offset int64) {
+	if endOffset, exists := p[partition]; exists && offset+1 >= endOffset {
+		delete(p, partition)
+	}
+}
+
+func (p redpandaTopicCacheProgress) done() bool {
+	return len(p) == 0
+}
+
+func (c *redpandaTopicCache) loop(ctx context.Context, createTopic bool) {
+	defer close(c.closedSig)
+
+	boff := backoff.NewExponentialBackOff()
+	boff.MaxInterval = 30 * time.Second
+	boff.MaxElapsedTime = 0
+
+	var start, end kadm.ListedOffsets
+	for {
+		var err error
+		if start, end, err = c.loadOffsets(ctx, createTopic); err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		wait := boff.NextBackOff()
+		c.log.Errorf("Failed to load cache topic, retrying in %v: %s", wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	progress := newRedpandaTopicCacheProgress(start, end)
+	if progress.done() {
+		close(c.loaded)
+	}
+
+	for {
+		fetches := c.client.PollFetches(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			if !errors.Is(err, kgo.ErrClientClosed) {
+				c.log.Errorf("Failed to consume cache topic %q partition %d: %s", topic, partition, err)
+			}
+		})
+		fetches.EachRecord(func(r *kgo.Record) {
+			if !r.Attrs.IsControl() {
+				c.view.consumed(r)
+			}
+			if progress.done() {
+				return
+			}
+			if progress.consumed(r.Partition, r.Offset); progress.done() {
+				c.log.Debugf("Loaded cache topic %q", c.topic)
+				close(c.loaded)
+			}
+		})
+	}
+}
+
+func (c *redpandaTopicCache) waitForLoad(ctx context.Context) error {
+	select {
+	case <-c.loaded:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *redpandaTopicCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := c.waitForLoad(ctx); err != nil {
+		return nil, err
+	}
+	value, exists := c.view.get(key, time.Now())
+	if !exists {
+		return nil, service.ErrKeyNotFound
+	}
+	return value, nil
+}
+
+func (c *redpandaTopicCache) produce(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	r := &kgo.Record{Key: []byte(key), Value: value}
+	if ttl == nil {
+		ttl = c.defaultTTL
+	}
+	if value != nil && ttl != nil {
+		expiresAt := time.Now().Add(*ttl).UnixMilli()
+		r.Headers = append(r.Headers, kgo.RecordHeader{
+			Key:   rtcExpiresAtHeader,
+			Value: []byte(strconv.FormatInt(expiresAt, 10)),
+		})
+	}
+	if err := c.client.ProduceSync(ctx, r).FirstErr(); err != nil {
+		return err
+	}
+	c.view.apply(r)
+	return nil
+}
+
+func (c *redpandaTopicCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	if err := c.waitForLoad(ctx); err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return c.produce(ctx, key, value, ttl)
+}
+
+func (c *redpandaTopicCache) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	if err := c.waitForLoad(ctx); err != nil {
+		return err
+	}
+
+	c.addMut.Lock()
+	defer c.addMut.Unlock()
+
+	if _, exists := c.view.get(key, time.Now()); exists {
+		return service.ErrKeyAlreadyExists
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return c.produce(ctx, key, value, ttl)
+}
+
+func (c *redpandaTopicCache) Delete(ctx context.Context, key string) error {
+	if err := c.waitForLoad(ctx); err != nil {
+		return err
+	}
+	return c.produce(ctx, key, nil, nil)
+}
+
+func (c *redpandaTopicCache) Close(ctx context.Context) error {
+	c.closeFn()
+	select {
+	case <-c.closedSig:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	c.client.Close()
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestRedpandaTopicCacheView(t *testing.T) {
+	now := time.Now()
+	v := newRedpandaTopicCacheView()
+
+	rec := func(key, value string, offset int64) *kgo.Record {
+		r := &kgo.Record{Key: []byte(key), Partition: 0, Offset: offset}
+		if value != "" {
+			r.Value = []byte(value)
+		}
+		return r
+	}
+	assertGet := func(key, value string) {
+		t.Helper()
+		actual, exists := v.get(key, now)
+		if value == "" {
+			assert.False(t, exists, key)
+			return
+		}
+		assert.True(t, exists, key)
+		assert.Equal(t, value, string(actual), key)
+	}
+
+	// Records consumed during load
+	v.consumed(rec("foo", "a", 0))
+	v.consumed(rec("bar", "b", 1))
+	v.consumed(rec("foo", "c", 2))
+	assertGet("foo", "c")
+	assertGet("bar", "b")
+	assertGet("baz", "")
+
+	// A local write which is ahead of the consumer isn't reverted by records
+	// consumed afterwards.
+	v.apply(rec("foo", "d", 5))
+	v.consumed(rec("foo", "x", 3))
+	assertGet("foo", "d")
+	v.consumed(rec("foo", "d", 5))
+	assertGet("foo", "d")
+
+	// A local delete is kept until its tombstone has been consumed.
+	v.apply(rec("bar", "", 6))
+	assertGet("bar", "")
+	v.consumed(rec("bar", "y", 4))
+	assertGet("bar", "")
+	assert.Contains(t, v.entries, "bar")
+	v.consumed(rec("bar", "", 6))
+	assertGet("bar", "")
+	assert.NotContains(t, v.entries, "bar")
+
+	// Expired values are treated as missing.
+	expired := rec("baz", "e", 7)
+	expired.Headers = []kgo.RecordHeader{{Key: rtcExpiresAtHeader, Value: []byte(strconv.FormatInt(now.Add(-time.Second).UnixMilli(), 10))}}
+	v.consumed(expired)
+	assertGet("baz", "")
+
+	notExpired := rec("baz", "f", 8)
+	notExpired.Headers = []kgo.RecordHeader{{Key: rtcExpiresAtHeader, Value: []byte(strconv.FormatInt(now.Add(time.Hour).UnixMilli(), 10))}}
+	v.consumed(notExpired)
+	assertGet("baz", "f")
+}
+
+func TestRedpandaTopicCacheProgress(t *testing.T) {
+	listed := func(offsets map[int32]int64) kadm.ListedOffsets {
+		l := kadm.ListedOffsets{"foo": map[int32]kadm.ListedOffset{}}
+		for p, o := range offsets {
+			l["foo"][p] = kadm.ListedOffset{Topic: "foo", Partition: p, Offset: o}
+		}
+		return l
+	}
+
+	// Partition 0 is empty, partition 1 ends with a transaction marker at
+	// offset 4 and partition 2 has been compacted up to offset 10.
+	p := newRedpandaTopicCacheProgress(
+		listed(map[int32]int64{0: 0, 1: 0, 2: 10}),
+		listed(map[int32]int64{0: 0, 1: 5, 2: 12}),
+	)
+	assert.Equal(t, redpandaTopicCacheProgress{1: 5, 2: 12}, p)
+	assert.False(t, p.done())
+
+	p.consumed(2, 11)
+	assert.Equal(t, redpandaTopicCacheProgress{1: 5}, p)
+
+	p.consumed(1, 3)
+	assert.False(t, p.done())
+
+	// The commit marker is the last record of the partition.
+	p.consumed(1, 4)
+	assert.True(t, p.done())
+
+	p.consumed(3, 0)
+	assert.True(t, p.done())
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"errors"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+type offsetTranslationKey struct {
+	topic     string
+	partition int32
+	bucket    int64
+}
+
+type offsetTranslationEntry struct {
+	offset  kadm.Offset
+	addedAt time.Time
+}
+
+// offsetTranslationCache is an LRU cache with a TTL which stores the offsets
+// of the destination cluster which correspond to a given topic, partition and
+// timestamp bucket.
+type offsetTranslationCache struct {
+	ttl      time.Duration
+	bucketMs int64
+	entries  *lru.Cache[offsetTranslationKey, offsetTranslationEntry]
+
+	nowFn func() time.Time
+}
+
+func newOffsetTranslationCache(size int, ttl, bucket time.Duration) (*offsetTranslationCache, error) {
+	if bucket < time.Millisecond {
+		return nil, errors.New("timestamp bucket must be at least 1ms")
+	}
+
+	entries, err := lru.New[offsetTranslationKey, offsetTranslationEntry](size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &offsetTranslationCache{
+		ttl:      ttl,
+		bucketMs: bucket.Milliseconds(),
+		entries:  entries,
+		nowFn:    time.Now,
+	}, nil
+}
+
+// bucketStart rounds the given timestamp down to the start of its bucket.
+func (c *offsetTranslationCache) bucketStart(timestampMs int64) int64 {
+	if timestampMs <= 0 {
+		return timestampMs
+	}
+	return timestampMs - timestampMs%c.bucketMs
+}
+
+func (c *offsetTranslationCache) get(topic string, partition int32, timestampMs int64) (kadm.Offset, bool) {
+	key := offsetTranslationKey{topic: topic, partition: partition, bucket: c.bucketStart(timestampMs)}
+	e, ok := c.entries.Get(key)
+	if !ok {
+		return kadm.Offset{}, false
+	}
+	if c.ttl > 0 && c.nowFn().Sub(e.addedAt) > c.ttl {
+		c.entries.Remove(key)
+		return kadm.Offset{}, false
+	}
+	return e.offset, true
+}
+
+func (c *offsetTranslationCache) add(offset kadm.Offset, timestampMs int64) {
+	key := offsetTranslationKey{topic: offset.Topic, partition: offset.Partition, bucket: c.bucketStart(timestampMs)}
+	c.entries.Add(key, offsetTranslationEntry{offset: offset, addedAt: c.nowFn()})
+}
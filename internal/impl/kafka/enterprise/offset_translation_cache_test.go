@@ -0,0 +1,71 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestOffsetTranslationCacheBuckets(t *testing.T) {
+	c, err := newOffsetTranslationCache(10, time.Minute, time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(5000), c.bucketStart(5999))
+	assert.Equal(t, int64(0), c.bucketStart(0))
+
+	c.add(kadm.Offset{Topic: "foo", Partition: 1, At: 10}, 5000)
+
+	o, ok := c.get("foo", 1, 5999)
+	require.True(t, ok)
+	assert.Equal(t, int64(10), o.At)
+
+	_, ok = c.get("foo", 1, 6000)
+	assert.False(t, ok)
+
+	_, ok = c.get("foo", 2, 5000)
+	assert.False(t, ok)
+}
+
+func TestOffsetTranslationCacheTTL(t *testing.T) {
+	c, err := newOffsetTranslationCache(10, time.Minute, time.Second)
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	c.nowFn = func() time.Time { return now }
+
+	c.add(kadm.Offset{Topic: "foo", Partition: 1, At: 10}, 5000)
+
+	now = now.Add(30 * time.Second)
+	_, ok := c.get("foo", 1, 5000)
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get("foo", 1, 5000)
+	assert.False(t, ok)
+}
+
+func TestOffsetTranslationCacheEviction(t *testing.T) {
+	c, err := newOffsetTranslationCache(2, 0, time.Second)
+	require.NoError(t, err)
+
+	c.add(kadm.Offset{Topic: "foo", Partition: 0, At: 1}, 1000)
+	c.add(kadm.Offset{Topic: "foo", Partition: 1, At: 2}, 1000)
+	c.add(kadm.Offset{Topic: "foo", Partition: 2, At: 3}, 1000)
+
+	_, ok := c.get("foo", 0, 1000)
+	assert.False(t, ok)
+
+	_, ok = c.get("foo", 2, 1000)
+	assert.True(t, ok)
+}
@@ -0,0 +1,336 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/kafka"
+	"github.com/redpanda-data/connect/v4/internal/license"
+)
+
+const (
+	rpaiFieldTopic           = "topic"
+	rpaiFieldStartFromOldest = "start_from_oldest"
+	rpaiFieldEventTypes      = "event_types"
+)
+
+// The OCSF classes of the events which are written to the audit log.
+const (
+	ocsfClassApplicationLifecycle = 6002
+	ocsfClassAPIActivity          = 6003
+	ocsfClassAuthentication       = 3002
+	ocsfClassAuthorizeSession     = 3003
+)
+
+var auditEventTypes = []string{"admin", "api_activity", "application_lifecycle", "authentication", "authorization", "unknown"}
+
+func redpandaAuditLogInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.48.0").
+		Summary(`Consumes the audit log of a Redpanda cluster and decodes its events into structured documents.`).
+		Description(`
+Redpanda writes audit events to the `+"`_redpanda.audit_log`"+` topic in the https://schema.ocsf.io/[Open Cybersecurity Schema Framework (OCSF)^] format. This input consumes that topic and emits a structured message for each event so that audit data can be shipped to a SIEM without having to decode the records manually. Records which are not valid audit events are skipped.
+
+The type of each event is derived from its OCSF class and can be used to only consume certain events via the `+"`event_types`"+` field:
+
+`+"```text"+`
+- authentication: OCSF class 3002
+- authorization: OCSF class 3003
+- admin: OCSF class 6003 for Admin API requests
+- api_activity: OCSF class 6003 for any other API requests
+- application_lifecycle: OCSF class 6002
+- unknown: any other class
+`+"```"+`
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- kafka_key
+- kafka_topic
+- kafka_partition
+- kafka_offset
+- kafka_timestamp_unix
+- kafka_timestamp_ms
+- kafka_tombstone_message
+- audit_event_type
+- audit_class_uid
+- audit_principal
+- audit_operation
+- audit_status
+- audit_timestamp_ms
+`+"```"+`
+
+The `+"`audit_principal`"+` is the name of the user which authenticated or performed a request, `+"`audit_operation`"+` is the name of the API operation of an API request and `+"`audit_status`"+` is either `+"`success`, `failure` or `unknown`"+`. The `+"`audit_class_uid` and `audit_timestamp_ms`"+` fields are integers, where the timestamp is set to zero when an event has no time. String fields which are not present in an event are set to an empty string.
+`).
+		Fields(redpandaAuditLogInputConfigFields()...).
+		Example("Ship failed authentications", "Consumes the audit log with a consumer group and forwards failed authentication attempts to an HTTP endpoint.", `
+input:
+  redpanda_audit_log:
+    seed_brokers: [ localhost:9092 ]
+    consumer_group: audit_shipper
+    event_types: [ authentication ]
+  processors:
+    - mapping: |
+        root = if @audit_status != "failure" { deleted() }
+
+output:
+  http_client:
+    url: https://siem.example.com/ingest
+    verb: POST
+`)
+}
+
+func redpandaAuditLogInputConfigFields() []*service.ConfigField {
+	return slices.Concat(
+		kafka.FranzConnectionFields(),
+		[]*service.ConfigField{
+			service.NewStringField(rpaiFieldTopic).
+				Description("The audit log topic to consume.").
+				Default("_redpanda.audit_log").
+				Advanced(),
+			service.NewBoolField(rpaiFieldStartFromOldest).
+				Description("Determines whether to consume from the oldest available event, otherwise only events written after the input connects are consumed. Only applies when no committed offsets exist for the consumer group.").
+				Default(true),
+			service.NewStringListField(rpaiFieldEventTypes).
+				Description(fmt.Sprintf("The types of events to consume, if empty all events are consumed. Valid types are %v.", auditEventTypes)).
+				Example([]any{"authentication", "authorization"}).
+				Default([]any{}),
+		},
+		kafka.FranzReaderOrderedConfigFields(),
+		[]*service.ConfigField{
+			service.NewAutoRetryNacksToggleField(),
+		},
+	)
+}
+
+func init() {
+	err := service.RegisterBatchInput("redpanda_audit_log", redpandaAuditLogInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			if err := license.CheckRunningEnterprise(mgr); err != nil {
+				return nil, err
+			}
+
+			clientOpts, err := kafka.FranzConnectionOptsFromConfig(conf, mgr.Logger())
+			if err != nil {
+				return nil, err
+			}
+
+			topic, err := conf.FieldString(rpaiFieldTopic)
+			if err != nil {
+				return nil, err
+			}
+
+			startFromOldest, err := conf.FieldBool(rpaiFieldStartFromOldest)
+			if err != nil {
+				return nil, err
+			}
+			resetOffset := kgo.NewOffset().AtEnd()
+			if startFromOldest {
+				resetOffset = kgo.NewOffset().AtStart()
+			}
+
+			eventTypesList, err := conf.FieldStringList(rpaiFieldEventTypes)
+			if err != nil {
+				return nil, err
+			}
+			var eventTypes map[string]struct{}
+			if len(eventTypesList) > 0 {
+				eventTypes = map[string]struct{}{}
+				for _, t := range eventTypesList {
+					if !slices.Contains(auditEventTypes, t) {
+						return nil, fmt.Errorf("unknown event type %q, valid types are %v", t, auditEventTypes)
+					}
+					eventTypes[t] = struct{}{}
+				}
+			}
+
+			clientOpts = append(clientOpts,
+				kgo.ConsumeResetOffset(resetOffset),
+				kgo.ConsumeTopics(topic),
+			)
+
+			rdr, err := kafka.NewFranzReaderOrderedFromConfig(conf, mgr, func() ([]kgo.Opt, error) {
+				return clientOpts, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return service.AutoRetryNacksBatchedToggled(conf, &redpandaAuditLogInput{
+				FranzReaderOrdered: rdr,
+				eventTypes:         eventTypes,
+				mgr:                mgr,
+			})
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type redpandaAuditLogInput struct {
+	*kafka.FranzReaderOrdered
+
+	eventTypes map[string]struct{}
+
+	mgr *service.Resources
+}
+
+func (r *redpandaAuditLogInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	for {
+		batch, ack, err := r.FranzReaderOrdered.ReadBatch(ctx)
+		if err != nil {
+			return batch, ack, err
+		}
+
+		batch = slices.DeleteFunc(batch, func(msg *service.Message) bool {
+			raw, err := msg.AsBytes()
+			if err != nil || len(raw) == 0 {
+				return true
+			}
+
+			doc, event, err := parseAuditEvent(raw)
+			if err != nil {
+				r.mgr.Logger().Debugf("Failed to decode audit event: %s", err)
+				return true
+			}
+
+			if r.eventTypes != nil {
+				if _, exists := r.eventTypes[event.eventType]; !exists {
+					return true
+				}
+			}
+
+			msg.SetStructuredMut(doc)
+			msg.MetaSetMut("audit_event_type", event.eventType)
+			msg.MetaSetMut("audit_class_uid", event.classUID)
+			msg.MetaSetMut("audit_principal", event.principal)
+			msg.MetaSetMut("audit_operation", event.operation)
+			msg.MetaSetMut("audit_status", event.status)
+			msg.MetaSetMut("audit_timestamp_ms", event.timestampMs)
+			return false
+		})
+
+		if len(batch) == 0 {
+			// Acknowledge the records which were filtered out so that their
+			// offsets are committed.
+			if err := ack(ctx, nil); err != nil {
+				r.mgr.Logger().Errorf("Failed to acknowledge skipped audit log records: %s", err)
+			}
+			continue
+		}
+
+		return batch, ack, nil
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type auditEvent struct {
+	eventType   string
+	classUID    int64
+	principal   string
+	operation   string
+	status      string
+	timestampMs int64
+}
+
+type ocsfUser struct {
+	Name string `json:"name"`
+}
+
+// ocsfEvent contains the subset of OCSF event fields which are used in order to
+// populate the metadata of audit events.
+type ocsfEvent struct {
+	ClassUID *int64    `json:"class_uid"`
+	Time     int64     `json:"time"`
+	StatusID int64     `json:"status_id"`
+	User     *ocsfUser `json:"user"`
+	Actor    *struct {
+		User *ocsfUser `json:"user"`
+	} `json:"actor"`
+	API *struct {
+		Operation string `json:"operation"`
+	} `json:"api"`
+	HTTPRequest json.RawMessage `json:"http_request"`
+}
+
+// parseAuditEvent decodes an audit log record in the OCSF format.
+func parseAuditEvent(raw []byte) (map[string]any, auditEvent, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, auditEvent{}, err
+	}
+
+	var e ocsfEvent
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, auditEvent{}, err
+	}
+	if e.ClassUID == nil {
+		return nil, auditEvent{}, errors.New("missing class_uid")
+	}
+
+	event := auditEvent{
+		classUID:    *e.ClassUID,
+		timestampMs: e.Time,
+	}
+
+	switch event.classUID {
+	case ocsfClassAuthentication:
+		event.eventType = "authentication"
+	case ocsfClassAuthorizeSession:
+		event.eventType = "authorization"
+	case ocsfClassAPIActivity:
+		if len(e.HTTPRequest) > 0 {
+			event.eventType = "admin"
+		} else {
+			event.eventType = "api_activity"
+		}
+	case ocsfClassApplicationLifecycle:
+		event.eventType = "application_lifecycle"
+	default:
+		event.eventType = "unknown"
+	}
+
+	switch {
+	case e.Actor != nil && e.Actor.User != nil:
+		event.principal = e.Actor.User.Name
+	case e.User != nil:
+		event.principal = e.User.Name
+	}
+
+	if e.API != nil {
+		event.operation = e.API.Operation
+	}
+
+	switch e.StatusID {
+	case 1:
+		event.status = "success"
+	case 2:
+		event.status = "failure"
+	default:
+		event.status = "unknown"
+	}
+
+	return doc, event, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuditEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected auditEvent
+	}{
+		{
+			name:  "authentication",
+			input: `{"category_uid":3,"class_uid":3002,"severity_id":1,"time":1700533469078,"type_uid":300201,"activity_id":1,"auth_protocol":"SASL-SCRAM","status_id":2,"user":{"name":"alice","type_id":1}}`,
+			expected: auditEvent{
+				eventType:   "authentication",
+				classUID:    3002,
+				principal:   "alice",
+				status:      "failure",
+				timestampMs: 1700533469078,
+			},
+		},
+		{
+			name:  "kafka api activity",
+			input: `{"category_uid":6,"class_uid":6003,"time":1700533469079,"activity_id":3,"actor":{"authorizations":[{"decision":"authorized"}],"user":{"name":"bob","type_id":1}},"api":{"operation":"produce","service":{"name":"kafka rpc protocol"}},"resources":[{"name":"foo","type":"topic"}],"status_id":1}`,
+			expected: auditEvent{
+				eventType:   "api_activity",
+				classUID:    6003,
+				principal:   "bob",
+				operation:   "produce",
+				status:      "success",
+				timestampMs: 1700533469079,
+			},
+		},
+		{
+			name:  "admin api activity",
+			input: `{"class_uid":6003,"time":1,"actor":{"user":{"name":"admin"}},"api":{"operation":"POST","service":{"name":"Redpanda Admin HTTP Server"}},"http_request":{"http_method":"POST"},"status_id":1}`,
+			expected: auditEvent{
+				eventType:   "admin",
+				classUID:    6003,
+				principal:   "admin",
+				operation:   "POST",
+				status:      "success",
+				timestampMs: 1,
+			},
+		},
+		{
+			name:  "application lifecycle",
+			input: `{"class_uid":6002,"time":2,"activity_id":3}`,
+			expected: auditEvent{
+				eventType:   "application_lifecycle",
+				classUID:    6002,
+				status:      "unknown",
+				timestampMs: 2,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc, event, err := parseAuditEvent([]byte(test.input))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, event)
+			assert.Contains(t, doc, "class_uid")
+		})
+	}
+
+	_, _, err := parseAuditEvent([]byte(`{"foo":"bar"}`))
+	require.Error(t, err)
+
+	_, _, err = parseAuditEvent([]byte(`not json`))
+	require.Error(t, err)
+}
@@ -67,7 +67,7 @@ Records are processed and delivered from each partition in batches as received f
 
 == Metrics
 
-Emits a ` + "`redpanda_lag`" + ` metric with ` + "`topic`" + ` and ` + "`partition`" + ` labels for each consumed topic.
+Emits ` + "`redpanda_lag`, `redpanda_committed_offset` and `redpanda_end_offset`" + ` gauges with ` + "`topic`" + ` and ` + "`partition`" + ` labels for each consumed topic partition when a consumer group is specified. These are refreshed every ` + "`topic_lag_refresh_period`" + `.
 
 == Metadata
 
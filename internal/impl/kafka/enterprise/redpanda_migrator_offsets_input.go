@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"regexp"
 	"slices"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -28,6 +29,12 @@ const (
 	rmoiFieldTopics       = "topics"
 	rmoiFieldRegexpTopics = "regexp_topics"
 	rmoiFieldRackID       = "rack_id"
+
+	rmoiFieldMode             = "mode"
+	rmoiFieldSnapshotInterval = "snapshot_interval"
+
+	rmoiModeStream   = "stream"
+	rmoiModeSnapshot = "snapshot"
 )
 
 func redpandaMigratorOffsetsInputConfig() *service.ConfigSpec {
@@ -37,7 +44,13 @@ func redpandaMigratorOffsetsInputConfig() *service.ConfigSpec {
 		Version("4.45.0").
 		Summary(`Redpanda Migrator consumer group offsets input using the https://github.com/twmb/franz-go[Franz Kafka client library^].`).
 		Description(`
-TODO: Description
+Reads consumer group offset updates for the configured topics so that they can be migrated by a ` + "`redpanda_migrator_offsets`" + ` output.
+
+== Modes
+
+In ` + "`stream`" + ` mode (the default) this input consumes the ` + "`__consumer_offsets`" + ` topic and emits every offset commit as it happens.
+
+In ` + "`snapshot`" + ` mode this input instead lists the committed offsets of all consumer groups via the admin API every ` + "`snapshot_interval`" + ` and emits a batch per consumer group containing the offsets which changed since the previous snapshot. This mode is useful for managed clusters which do not allow reading internal topics. Since the admin API does not expose commit timestamps, the timestamp of the last record consumed by the group is read from each partition and used instead. Offsets are only considered migrated once the batch containing them is acknowledged, otherwise they are emitted again with the next snapshot.
 
 == Metadata
 
@@ -56,6 +69,7 @@ This input adds the following metadata fields to each message:
 - kafka_offset_partition
 - kafka_offset_commit_timestamp
 - kafka_offset_metadata
+- kafka_offset (snapshot mode only)
 ` + "```" + `
 `).
 		Fields(redpandaMigratorOffsetsInputConfigFields()...)
@@ -79,6 +93,17 @@ A list of topics to consume from. Multiple comma separated topics can be listed
 				Description("A rack specifies where the client is physically located and changes fetch requests to consume from the closest replica as opposed to the leader replica.").
 				Default("").
 				Advanced(),
+			service.NewStringAnnotatedEnumField(rmoiFieldMode, map[string]string{
+				rmoiModeStream:   "Consume offset commits from the `__consumer_offsets` topic.",
+				rmoiModeSnapshot: "Periodically list the committed offsets of all consumer groups via the admin API.",
+			}).
+				Description("Determines how consumer group offsets are obtained from the source cluster.").
+				Default(rmoiModeStream).
+				Advanced(),
+			service.NewDurationField(rmoiFieldSnapshotInterval).
+				Description("The interval at which committed consumer group offsets are listed when `mode` is set to `snapshot`.").
+				Default("1m").
+				Advanced(),
 		},
 		kafka.FranzReaderOrderedConfigFields(),
 		[]*service.ConfigField{
@@ -128,6 +153,25 @@ func init() {
 			}
 			clientOpts = append(clientOpts, kgo.Rack(rackID))
 
+			var mode string
+			if mode, err = conf.FieldString(rmoiFieldMode); err != nil {
+				return nil, err
+			}
+
+			if mode == rmoiModeSnapshot {
+				var interval time.Duration
+				if interval, err = conf.FieldDuration(rmoiFieldSnapshotInterval); err != nil {
+					return nil, err
+				}
+
+				rmoi := &redpandaMigratorOffsetsInput{
+					topicPatterns: topicPatterns,
+					topics:        topics,
+					mgr:           mgr,
+				}
+				return service.AutoRetryNacksBatchedToggled(conf, newRedpandaMigratorOffsetsSnapshotInput(clientOpts, interval, rmoi.matchesTopic, mgr))
+			}
+
 			// Configure `start_from_oldest: true`
 			clientOpts = append(clientOpts, kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
 
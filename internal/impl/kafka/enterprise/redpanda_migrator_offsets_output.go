@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +35,14 @@ const (
 	rmooFieldOffsetCommitTimestamp = "offset_commit_timestamp"
 	rmooFieldOffsetMetadata        = "offset_metadata"
 
+	rmooFieldTranslationCache                = "translation_cache"
+	rmooFieldTranslationCacheEnabled         = "enabled"
+	rmooFieldTranslationCacheSize            = "size"
+	rmooFieldTranslationCacheTTL             = "ttl"
+	rmooFieldTranslationCacheTimestampBucket = "timestamp_bucket"
+
+	rmooFieldDryRun = "dry_run"
+
 	// Deprecated fields
 	rmooFieldKafkaKey    = "kafka_key"
 	rmooFieldMaxInFlight = "max_in_flight"
@@ -65,6 +74,27 @@ func redpandaMigratorOffsetsOutputConfigFields() []*service.ConfigField {
 				Description("Kafka offset commit timestamp.").Default("${! @kafka_offset_commit_timestamp }"),
 			service.NewInterpolatedStringField(rmooFieldOffsetMetadata).
 				Description("Kafka offset metadata value.").Default(`${! @kafka_offset_metadata }`),
+			service.NewObjectField(rmooFieldTranslationCache,
+				service.NewBoolField(rmooFieldTranslationCacheEnabled).
+					Description("Whether translated offsets should be cached.").
+					Default(false),
+				service.NewIntField(rmooFieldTranslationCacheSize).
+					Description("The maximum number of topic, partition and timestamp bucket entries to keep in the cache.").
+					Default(10000),
+				service.NewDurationField(rmooFieldTranslationCacheTTL).
+					Description("The maximum amount of time a translated offset is kept in the cache.").
+					Default("1m"),
+				service.NewDurationField(rmooFieldTranslationCacheTimestampBucket).
+					Description("The width of the timestamp buckets used as cache keys. Commit timestamps are rounded down to the start of their bucket before the offsets are translated, which means that consumer groups may be rewound by up to this duration on the destination cluster.").
+					Default("1s"),
+			).
+				Description("Caches the results of the offset lookups which are performed against the destination cluster when translating consumer group offsets, so that high volume offset streams don't result in a lookup for every single record.").
+				Advanced().
+				Version("4.48.0"),
+			service.NewBoolField(rmooFieldDryRun).
+				Description("Translate consumer group offsets without committing them to the destination cluster. Each translated offset is logged along with the offset which is currently committed on the destination cluster, and a summary of the offsets which would have been committed for each consumer group is logged when the output shuts down. This can be used in order to validate a migration before cutting consumers over.").
+				Default(false).
+				Version("4.48.0"),
 
 			// Deprecated fields
 			service.NewInterpolatedStringField(rmooFieldKafkaKey).
@@ -111,9 +141,14 @@ type redpandaMigratorOffsetsWriter struct {
 	offsetCommitTimestamp *service.InterpolatedString
 	offsetMetadata        *service.InterpolatedString
 	backoffCtor           func() backoff.BackOff
+	translationCache      *offsetTranslationCache
+	dryRun                bool
 
 	connMut sync.Mutex
 	client  *kadm.Client
+	// Tracks the latest offsets which would have been committed for each
+	// group when running in dry run mode.
+	dryRunOffsets map[string]kadm.Offsets
 
 	mgr *service.Resources
 }
@@ -149,6 +184,35 @@ func newRedpandaMigratorOffsetsWriterFromConfig(conf *service.ParsedConfig, mgr
 		return nil, err
 	}
 
+	cacheConf := conf.Namespace(rmooFieldTranslationCache)
+	var cacheEnabled bool
+	if cacheEnabled, err = cacheConf.FieldBool(rmooFieldTranslationCacheEnabled); err != nil {
+		return nil, err
+	}
+	if cacheEnabled {
+		var size int
+		if size, err = cacheConf.FieldInt(rmooFieldTranslationCacheSize); err != nil {
+			return nil, err
+		}
+		var ttl, bucket time.Duration
+		if ttl, err = cacheConf.FieldDuration(rmooFieldTranslationCacheTTL); err != nil {
+			return nil, err
+		}
+		if bucket, err = cacheConf.FieldDuration(rmooFieldTranslationCacheTimestampBucket); err != nil {
+			return nil, err
+		}
+		if w.translationCache, err = newOffsetTranslationCache(size, ttl, bucket); err != nil {
+			return nil, err
+		}
+	}
+
+	if w.dryRun, err = conf.FieldBool(rmooFieldDryRun); err != nil {
+		return nil, err
+	}
+	if w.dryRun {
+		w.dryRunOffsets = map[string]kadm.Offsets{}
+	}
+
 	if w.clientOpts, err = kafka.FranzProducerLimitsOptsFromConfig(conf); err != nil {
 		return nil, err
 	}
@@ -248,29 +312,18 @@ func (w *redpandaMigratorOffsetsWriter) Write(ctx context.Context, msg *service.
 	}
 
 	updateConsumerOffsets := func() error {
-		listedOffsets, err := w.client.ListOffsetsAfterMilli(ctx, offsetCommitTimestamp, topic)
+		offset, err := w.translateOffset(ctx, topic, offsetPartition, offsetCommitTimestamp)
 		if err != nil {
-			return fmt.Errorf("failed to translate consumer offsets: %s", err)
+			return err
 		}
+		offset.Metadata = offsetMetadata
 
-		if err := listedOffsets.Error(); err != nil {
-			return fmt.Errorf("listed offsets error: %s", err)
+		if w.dryRun {
+			return w.dryRunCommit(ctx, group, offset)
 		}
 
-		offsets := listedOffsets.Offsets()
-		// Logic extracted from offsets.KeepFunc() and adjusted to set the metadata.
-		for topic, partitionOffsets := range offsets {
-			for partition, offset := range partitionOffsets {
-				if offset.Partition != offsetPartition {
-					delete(partitionOffsets, partition)
-				}
-				offset.Metadata = offsetMetadata
-				partitionOffsets[partition] = offset
-			}
-			if len(partitionOffsets) == 0 {
-				delete(offsets, topic)
-			}
-		}
+		offsets := kadm.Offsets{}
+		offsets.Add(offset)
 
 		offsetResponses, err := w.client.CommitOffsets(ctx, group, offsets)
 		if err != nil {
@@ -303,11 +356,111 @@ func (w *redpandaMigratorOffsetsWriter) Write(ctx context.Context, msg *service.
 	return nil
 }
 
+// translateOffset returns the earliest offset of the given topic partition on
+// the destination cluster whose timestamp is greater than or equal to the
+// provided commit timestamp.
+func (w *redpandaMigratorOffsetsWriter) translateOffset(ctx context.Context, topic string, partition int32, commitTimestamp int64) (kadm.Offset, error) {
+	if w.translationCache != nil {
+		commitTimestamp = w.translationCache.bucketStart(commitTimestamp)
+		if offset, ok := w.translationCache.get(topic, partition, commitTimestamp); ok {
+			return offset, nil
+		}
+	}
+
+	listedOffsets, err := w.client.ListOffsetsAfterMilli(ctx, commitTimestamp, topic)
+	if err != nil {
+		return kadm.Offset{}, fmt.Errorf("failed to translate consumer offsets: %s", err)
+	}
+
+	if err := listedOffsets.Error(); err != nil {
+		return kadm.Offset{}, fmt.Errorf("listed offsets error: %s", err)
+	}
+
+	var offset kadm.Offset
+	var found bool
+	listedOffsets.Each(func(lo kadm.ListedOffset) {
+		o := kadm.Offset{
+			Topic:       lo.Topic,
+			Partition:   lo.Partition,
+			At:          lo.Offset,
+			LeaderEpoch: lo.LeaderEpoch,
+		}
+		if w.translationCache != nil {
+			// All the partitions of the topic are listed, so we might as well
+			// cache them all.
+			w.translationCache.add(o, commitTimestamp)
+		}
+		if lo.Partition == partition {
+			offset = o
+			found = true
+		}
+	})
+	if !found {
+		return kadm.Offset{}, fmt.Errorf("partition %d of topic %q not found on destination cluster", partition, topic)
+	}
+
+	return offset, nil
+}
+
+// dryRunCommit logs the offset which would have been committed for a group
+// along with the offset which is currently committed on the destination
+// cluster and records it for the summary.
+func (w *redpandaMigratorOffsetsWriter) dryRunCommit(ctx context.Context, group string, offset kadm.Offset) error {
+	current := "none"
+	fetched, err := w.client.FetchOffsets(ctx, group)
+	if err != nil {
+		return fmt.Errorf("failed to fetch consumer offsets: %s", err)
+	}
+	if o, exists := fetched.Lookup(offset.Topic, offset.Partition); exists && o.Err == nil && o.At >= 0 {
+		current = strconv.FormatInt(o.At, 10)
+	}
+
+	w.mgr.Logger().Infof("Dry run: would commit offset %d for group %q, topic %q and partition %d (currently committed: %s)", offset.At, group, offset.Topic, offset.Partition, current)
+
+	offsets, exists := w.dryRunOffsets[group]
+	if !exists {
+		offsets = kadm.Offsets{}
+		w.dryRunOffsets[group] = offsets
+	}
+	offsets.Add(offset)
+	return nil
+}
+
+// dryRunSummary returns a line for each group describing the offsets which
+// would have been committed in dry run mode.
+func dryRunSummary(dryRunOffsets map[string]kadm.Offsets) []string {
+	groups := make([]string, 0, len(dryRunOffsets))
+	for group := range dryRunOffsets {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+
+	lines := make([]string, 0, len(groups))
+	for _, group := range groups {
+		var partitions []string
+		dryRunOffsets[group].Each(func(o kadm.Offset) {
+			partitions = append(partitions, fmt.Sprintf("%s/%d=%d", o.Topic, o.Partition, o.At))
+		})
+		slices.Sort(partitions)
+		lines = append(lines, fmt.Sprintf("group %q: %s", group, strings.Join(partitions, ", ")))
+	}
+	return lines
+}
+
 // Close underlying connections.
 func (w *redpandaMigratorOffsetsWriter) Close(ctx context.Context) error {
 	w.connMut.Lock()
 	defer w.connMut.Unlock()
 
+	if w.dryRun {
+		summary := dryRunSummary(w.dryRunOffsets)
+		w.mgr.Logger().Infof("Dry run summary: would have committed offsets for %d consumer groups", len(summary))
+		for _, line := range summary {
+			w.mgr.Logger().Infof("Dry run summary: %s", line)
+		}
+		w.dryRunOffsets = map[string]kadm.Offsets{}
+	}
+
 	if w.client == nil {
 		return nil
 	}
@@ -0,0 +1,34 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestDryRunSummary(t *testing.T) {
+	assert.Empty(t, dryRunSummary(map[string]kadm.Offsets{}))
+
+	foo := kadm.Offsets{}
+	foo.Add(kadm.Offset{Topic: "b", Partition: 0, At: 5})
+	foo.Add(kadm.Offset{Topic: "a", Partition: 1, At: 10})
+	foo.Add(kadm.Offset{Topic: "a", Partition: 1, At: 12})
+	foo.Add(kadm.Offset{Topic: "a", Partition: 0, At: 3})
+
+	bar := kadm.Offsets{}
+	bar.Add(kadm.Offset{Topic: "c", Partition: 2, At: 0})
+
+	assert.Equal(t, []string{
+		`group "bar": c/2=0`,
+		`group "foo": a/0=3, a/1=12, b/0=5`,
+	}, dryRunSummary(map[string]kadm.Offsets{"foo": foo, "bar": bar}))
+}
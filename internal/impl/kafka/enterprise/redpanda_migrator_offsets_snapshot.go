@@ -0,0 +1,367 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// timestampLookupTimeout is the maximum amount of time spent waiting for the
+// records of a single lookup round when resolving commit timestamps.
+const timestampLookupTimeout = 10 * time.Second
+
+type groupTopicPartition struct {
+	group     string
+	topic     string
+	partition int32
+}
+
+type topicPartitionOffset struct {
+	topic     string
+	partition int32
+	offset    int64
+}
+
+type offsetsSnapshotBatch struct {
+	batch   service.MessageBatch
+	offsets map[groupTopicPartition]int64
+}
+
+// redpandaMigratorOffsetsSnapshotInput periodically lists the committed
+// offsets of all consumer groups via the admin API instead of consuming the
+// `__consumer_offsets` topic and emits the offsets which changed since the
+// previous snapshot.
+type redpandaMigratorOffsetsSnapshotInput struct {
+	clientOpts []kgo.Opt
+	interval   time.Duration
+	matches    func(topic string) bool
+
+	connMut sync.Mutex
+	client  *kgo.Client
+	pending []offsetsSnapshotBatch
+	ticker  *time.Ticker
+	// Tracks the offsets which were last emitted and acknowledged for each
+	// group, topic and partition.
+	committedMut sync.Mutex
+	committed    map[groupTopicPartition]int64
+
+	mgr *service.Resources
+}
+
+func newRedpandaMigratorOffsetsSnapshotInput(clientOpts []kgo.Opt, interval time.Duration, matches func(string) bool, mgr *service.Resources) *redpandaMigratorOffsetsSnapshotInput {
+	return &redpandaMigratorOffsetsSnapshotInput{
+		clientOpts: clientOpts,
+		interval:   interval,
+		matches:    matches,
+		committed:  map[groupTopicPartition]int64{},
+		mgr:        mgr,
+	}
+}
+
+func (s *redpandaMigratorOffsetsSnapshotInput) Connect(ctx context.Context) error {
+	s.connMut.Lock()
+	defer s.connMut.Unlock()
+
+	if s.client != nil {
+		return nil
+	}
+
+	client, err := kgo.NewClient(append(s.clientOpts, kgo.FetchMaxWait(time.Second))...)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return fmt.Errorf("failed to connect to cluster: %s", err)
+	}
+
+	s.client = client
+	return nil
+}
+
+func (s *redpandaMigratorOffsetsSnapshotInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	for {
+		s.connMut.Lock()
+		if s.client == nil {
+			s.connMut.Unlock()
+			return nil, nil, service.ErrNotConnected
+		}
+
+		if len(s.pending) > 0 {
+			next := s.pending[0]
+			s.pending = s.pending[1:]
+			s.connMut.Unlock()
+
+			return next.batch, func(ctx context.Context, err error) error {
+				if err != nil {
+					// The offsets will be emitted again as part of the next snapshot.
+					return nil
+				}
+
+				s.committedMut.Lock()
+				defer s.committedMut.Unlock()
+				for gtp, offset := range next.offsets {
+					s.committed[gtp] = offset
+				}
+				return nil
+			}, nil
+		}
+
+		ticker := s.ticker
+		if ticker == nil {
+			// The first snapshot is taken straight away.
+			s.ticker = time.NewTicker(s.interval)
+		}
+		s.connMut.Unlock()
+
+		// Wait for the next tick without holding the lock so that Close isn't
+		// blocked for up to a full interval.
+		if ticker != nil {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+
+		s.connMut.Lock()
+		if s.client == nil {
+			s.connMut.Unlock()
+			return nil, nil, service.ErrNotConnected
+		}
+		batches, err := s.snapshot(ctx)
+		if err != nil {
+			s.mgr.Logger().Errorf("Failed to snapshot consumer group offsets: %s", err)
+		} else {
+			s.pending = batches
+		}
+		s.connMut.Unlock()
+	}
+}
+
+func (s *redpandaMigratorOffsetsSnapshotInput) Close(ctx context.Context) error {
+	s.connMut.Lock()
+	defer s.connMut.Unlock()
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+		s.ticker = nil
+	}
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// snapshot lists the committed offsets of all consumer groups and returns a
+// batch per group containing the offsets which changed since they were last
+// acknowledged.
+func (s *redpandaMigratorOffsetsSnapshotInput) snapshot(ctx context.Context) ([]offsetsSnapshotBatch, error) {
+	adm := kadm.NewClient(s.client)
+
+	groups, err := adm.ListGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %s", err)
+	}
+
+	fetched := adm.FetchManyOffsets(ctx, groups.Groups()...)
+
+	changed := map[groupTopicPartition]kadm.Offset{}
+	s.committedMut.Lock()
+	for group, res := range fetched {
+		if res.Err != nil {
+			s.mgr.Logger().Warnf("Failed to fetch offsets for group %q: %s", group, res.Err)
+			continue
+		}
+		res.Fetched.Each(func(o kadm.OffsetResponse) {
+			if o.Err != nil || o.At < 0 || !s.matches(o.Topic) {
+				return
+			}
+			gtp := groupTopicPartition{group: group, topic: o.Topic, partition: o.Partition}
+			if prev, exists := s.committed[gtp]; exists && prev == o.At {
+				return
+			}
+			changed[gtp] = o.Offset
+		})
+	}
+	s.committedMut.Unlock()
+
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	lookups := make([]topicPartitionOffset, 0, len(changed))
+	for gtp, o := range changed {
+		lookups = append(lookups, topicPartitionOffset{topic: gtp.topic, partition: gtp.partition, offset: o.At})
+	}
+	timestamps, err := s.lookupTimestamps(ctx, lookups)
+	if err != nil {
+		return nil, err
+	}
+
+	byGroup := map[string]*offsetsSnapshotBatch{}
+	for gtp, o := range changed {
+		ts, ok := timestamps[topicPartitionOffset{topic: gtp.topic, partition: gtp.partition, offset: o.At}]
+		if !ok {
+			s.mgr.Logger().Warnf("Skipping offset %d for group %q, topic %q and partition %d: unable to determine commit timestamp", o.At, gtp.group, gtp.topic, gtp.partition)
+			continue
+		}
+		b, exists := byGroup[gtp.group]
+		if !exists {
+			b = &offsetsSnapshotBatch{offsets: map[groupTopicPartition]int64{}}
+			byGroup[gtp.group] = b
+		}
+		b.batch = append(b.batch, newSnapshotOffsetMessage(gtp, o, ts))
+		b.offsets[gtp] = o.At
+	}
+
+	groupNames := make([]string, 0, len(byGroup))
+	for group := range byGroup {
+		groupNames = append(groupNames, group)
+	}
+	sort.Strings(groupNames)
+
+	batches := make([]offsetsSnapshotBatch, 0, len(byGroup))
+	for _, group := range groupNames {
+		batches = append(batches, *byGroup[group])
+	}
+	return batches, nil
+}
+
+// lookupTimestamps resolves the timestamp of the last record consumed before
+// each of the given committed offsets. Offsets at the start of a partition map
+// to a zero timestamp.
+//
+// Each partition can only be consumed from a single offset at a time, so the
+// lookups are performed in rounds where each partition appears at most once.
+func (s *redpandaMigratorOffsetsSnapshotInput) lookupTimestamps(ctx context.Context, lookups []topicPartitionOffset) (map[topicPartitionOffset]int64, error) {
+	results := map[topicPartitionOffset]int64{}
+
+	type topicPartition struct {
+		topic     string
+		partition int32
+	}
+
+	var rounds []map[topicPartition]int64
+	for _, l := range lookups {
+		if l.offset <= 0 {
+			results[l] = 0
+			continue
+		}
+
+		tp := topicPartition{topic: l.topic, partition: l.partition}
+		placed := false
+		for _, round := range rounds {
+			if prev, exists := round[tp]; exists {
+				if prev == l.offset {
+					placed = true
+					break
+				}
+				continue
+			}
+			round[tp] = l.offset
+			placed = true
+			break
+		}
+		if !placed {
+			rounds = append(rounds, map[topicPartition]int64{tp: l.offset})
+		}
+	}
+
+	for _, round := range rounds {
+		assignments := map[string]map[int32]kgo.Offset{}
+		removals := map[string][]int32{}
+		for tp, offset := range round {
+			if assignments[tp.topic] == nil {
+				assignments[tp.topic] = map[int32]kgo.Offset{}
+			}
+			assignments[tp.topic][tp.partition] = kgo.NewOffset().At(offset - 1)
+			removals[tp.topic] = append(removals[tp.topic], tp.partition)
+		}
+
+		s.client.AddConsumePartitions(assignments)
+
+		roundCtx, done := context.WithTimeout(ctx, timestampLookupTimeout)
+		remaining := len(round)
+		for remaining > 0 {
+			fetches := s.client.PollFetches(roundCtx)
+			if roundCtx.Err() != nil {
+				break
+			}
+			fetches.EachError(func(topic string, partition int32, err error) {
+				s.mgr.Logger().Debugf("Failed to fetch record from topic %q and partition %d: %s", topic, partition, err)
+			})
+			fetches.EachRecord(func(r *kgo.Record) {
+				tp := topicPartition{topic: r.Topic, partition: r.Partition}
+				offset, exists := round[tp]
+				if !exists || r.Offset < offset-1 {
+					return
+				}
+				key := topicPartitionOffset{topic: r.Topic, partition: r.Partition, offset: offset}
+				if _, seen := results[key]; !seen {
+					results[key] = r.Timestamp.UnixMilli()
+					remaining--
+				}
+			})
+		}
+		done()
+
+		s.client.RemoveConsumePartitions(removals)
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+//------------------------------------------------------------------------------
+
+// newSnapshotOffsetMessage creates a message which mirrors the records read
+// from the `__consumer_offsets` topic in stream mode.
+func newSnapshotOffsetMessage(gtp groupTopicPartition, o kadm.Offset, commitTimestamp int64) *service.Message {
+	key := kmsg.NewOffsetCommitKey()
+	key.Version = 1
+	key.Group = gtp.group
+	key.Topic = gtp.topic
+	key.Partition = gtp.partition
+
+	value := kmsg.NewOffsetCommitValue()
+	value.Version = 3
+	value.Offset = o.At
+	value.LeaderEpoch = o.LeaderEpoch
+	value.Metadata = o.Metadata
+	value.CommitTimestamp = commitTimestamp
+
+	msg := service.NewMessage(value.AppendTo(nil))
+	msg.MetaSetMut("kafka_key", key.AppendTo(nil))
+	msg.MetaSetMut("kafka_topic", "__consumer_offsets")
+	msg.MetaSetMut("kafka_offset_topic", gtp.topic)
+	msg.MetaSetMut("kafka_offset_group", gtp.group)
+	msg.MetaSetMut("kafka_offset_partition", gtp.partition)
+	msg.MetaSetMut("kafka_offset_commit_timestamp", commitTimestamp)
+	msg.MetaSetMut("kafka_offset_metadata", o.Metadata)
+	msg.MetaSetMut("kafka_offset", o.At)
+	return msg
+}
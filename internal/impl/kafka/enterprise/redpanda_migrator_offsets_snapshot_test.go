@@ -0,0 +1,104 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestSnapshotOffsetMessageMatchesConsumerOffsetsRecord(t *testing.T) {
+	gtp := groupTopicPartition{group: "foogroup", topic: "footopic", partition: 3}
+	msg := newSnapshotOffsetMessage(gtp, kadm.Offset{At: 42, LeaderEpoch: 2, Metadata: "meta"}, 1234)
+
+	rawKey, ok := msg.MetaGetMut("kafka_key")
+	require.True(t, ok)
+
+	key := kmsg.NewOffsetCommitKey()
+	require.NoError(t, key.ReadFrom(rawKey.([]byte)))
+	assert.Equal(t, int16(1), key.Version)
+	assert.Equal(t, "foogroup", key.Group)
+	assert.Equal(t, "footopic", key.Topic)
+	assert.Equal(t, int32(3), key.Partition)
+
+	rawValue, err := msg.AsBytes()
+	require.NoError(t, err)
+
+	value := kmsg.NewOffsetCommitValue()
+	require.NoError(t, value.ReadFrom(rawValue))
+	assert.Equal(t, int64(42), value.Offset)
+	assert.Equal(t, int32(2), value.LeaderEpoch)
+	assert.Equal(t, "meta", value.Metadata)
+	assert.Equal(t, int64(1234), value.CommitTimestamp)
+
+	for k, exp := range map[string]any{
+		"kafka_offset_topic":            "footopic",
+		"kafka_offset_group":            "foogroup",
+		"kafka_offset_partition":        int32(3),
+		"kafka_offset_commit_timestamp": int64(1234),
+		"kafka_offset_metadata":         "meta",
+		"kafka_offset":                  int64(42),
+	} {
+		v, ok := msg.MetaGetMut(k)
+		require.True(t, ok, k)
+		assert.Equal(t, exp, v, k)
+	}
+}
+
+func TestSnapshotInputCloseWhileWaitingForTick(t *testing.T) {
+	client, err := kgo.NewClient(kgo.SeedBrokers("localhost:0"))
+	require.NoError(t, err)
+
+	s := newRedpandaMigratorOffsetsSnapshotInput(nil, time.Hour, func(string) bool { return true }, service.MockResources())
+	s.client = client
+	s.ticker = time.NewTicker(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	readErr := make(chan error, 1)
+	go func() {
+		_, _, err := s.ReadBatch(ctx)
+		readErr <- err
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		_ = s.Close(context.Background())
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close blocked by a pending read")
+	}
+
+	cancel()
+	select {
+	case err := <-readErr:
+		// Depending on whether the read started before the close it either
+		// observes the cancellation or the closed client.
+		if !errors.Is(err, context.Canceled) {
+			assert.ErrorIs(t, err, service.ErrNotConnected)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("read did not return after its context was cancelled")
+	}
+}
@@ -13,12 +13,14 @@ import (
 	"fmt"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kgo"
 	franz_sr "github.com/twmb/franz-go/pkg/sr"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 
+	"github.com/redpanda-data/connect/v4/internal/asyncroutine"
 	"github.com/redpanda-data/connect/v4/internal/impl/confluent/sr"
 	"github.com/redpanda-data/connect/v4/internal/impl/kafka"
 	"github.com/redpanda-data/connect/v4/internal/license"
@@ -32,6 +34,13 @@ const (
 	rmoFieldRepFactor                    = "replication_factor"
 	rmoFieldTranslateSchemaIDs           = "translate_schema_ids"
 	rmoFieldSchemaRegistryOutputResource = "schema_registry_output_resource"
+	rmoFieldMigrateGroupACLs             = "migrate_group_acls"
+	rmoFieldPrincipalMapping             = "principal_mapping"
+
+	rmoFieldSyncTopicConfigs         = "sync_topic_configs"
+	rmoFieldSyncTopicConfigsEnabled  = "enabled"
+	rmoFieldSyncTopicConfigsInterval = "interval"
+	rmoFieldSyncTopicConfigsKeys     = "configs"
 
 	// Deprecated
 	rmoFieldRackID = "rack_id"
@@ -59,7 +68,14 @@ ACL migration adheres to the following principles:
 
 - `+"`ALLOW WRITE`"+` ACLs for topics are not migrated
 - `+"`ALLOW ALL`"+` ACLs for topics are downgraded to `+"`ALLOW READ`"+`
-- Only topic ACLs are migrated, group ACLs are not migrated
+- Group ACLs are only migrated when `+"`migrate_group_acls`"+` is set to `+"`true`"+`, in which case all consumer group ACL
+bindings of the source cluster are recreated on the destination
+- Principals can be renamed during ACL migration via the `+"`principal_mapping`"+` field
+
+When `+"`sync_topic_configs.enabled`"+` is set to `+"`true`"+` the topic configurations listed in
+`+"`sync_topic_configs.configs`"+` are periodically copied from the source topics to the migrated topics, so that changes
+made on the source cluster after a topic has been created are reflected on the destination. Configurations which are not
+explicitly set on a source topic are reset to the default of the destination cluster.
 `).
 		Fields(redpandaMigratorOutputConfigFields()...).
 		LintRule(kafka.FranzWriterConfigLints()).
@@ -102,6 +118,29 @@ func redpandaMigratorOutputConfigFields() []*service.ConfigField {
 				Description("The label of the schema_registry output to use for fetching schema IDs.").
 				Default(sroResourceDefaultLabel).
 				Advanced(),
+			service.NewBoolField(rmoFieldMigrateGroupACLs).
+				Description("Migrate consumer group ACLs from the source cluster alongside the topic ACLs.").
+				Default(false).
+				Advanced(),
+			service.NewStringMapField(rmoFieldPrincipalMapping).
+				Description("A map of source cluster principals to the principals which should be used instead when creating ACLs on the destination cluster. Principals which are not present in this map are migrated as they are.").
+				Example(map[string]any{"User:alice": "User:alice-migrated"}).
+				Default(map[string]any{}).
+				Advanced(),
+			service.NewObjectField(rmoFieldSyncTopicConfigs,
+				service.NewBoolField(rmoFieldSyncTopicConfigsEnabled).
+					Description("Whether the configurations of migrated topics should be kept in sync with the source topics.").
+					Default(false),
+				service.NewDurationField(rmoFieldSyncTopicConfigsInterval).
+					Description("The period of time between each topic configuration sync.").
+					Default("5m"),
+				service.NewStringListField(rmoFieldSyncTopicConfigsKeys).
+					Description("The topic configurations to keep in sync.").
+					Default([]any{"cleanup.policy", "compression.type", "retention.ms", "retention.bytes", "max.message.bytes", "min.compaction.lag.ms", "delete.retention.ms"}),
+			).
+				Description("Periodically copies configuration changes of the source topics to the migrated topics.").
+				Version("4.48.0").
+				Advanced(),
 
 			// Deprecated
 			service.NewStringField(rmoFieldRackID).Deprecated(),
@@ -156,6 +195,32 @@ func init() {
 				schemaRegistryOutputResource = srResourceKey(res)
 			}
 
+			var migrateGroupACLs bool
+			if migrateGroupACLs, err = conf.FieldBool(rmoFieldMigrateGroupACLs); err != nil {
+				return
+			}
+
+			var principals principalMapping
+			if principals, err = conf.FieldStringMap(rmoFieldPrincipalMapping); err != nil {
+				return
+			}
+
+			syncConf := conf.Namespace(rmoFieldSyncTopicConfigs)
+			var syncTopicConfigsEnabled bool
+			if syncTopicConfigsEnabled, err = syncConf.FieldBool(rmoFieldSyncTopicConfigsEnabled); err != nil {
+				return
+			}
+			var syncTopicConfigsInterval time.Duration
+			var syncTopicConfigsKeys []string
+			if syncTopicConfigsEnabled {
+				if syncTopicConfigsInterval, err = syncConf.FieldDuration(rmoFieldSyncTopicConfigsInterval); err != nil {
+					return
+				}
+				if syncTopicConfigsKeys, err = syncConf.FieldStringList(rmoFieldSyncTopicConfigsKeys); err != nil {
+					return
+				}
+			}
+
 			var tmpOpts, clientOpts []kgo.Opt
 
 			var connDetails *kafka.FranzConnectionDetails
@@ -177,6 +242,45 @@ func init() {
 			var schemaIDCache sync.Map
 			var topicCache sync.Map
 			var runOnce sync.Once
+
+			// Periodically applies configuration changes of the source topics
+			// to the topics which have been migrated so far.
+			var topicConfigSyncer *asyncroutine.Periodic
+			var topicConfigSyncerMut sync.Mutex
+			syncMigratedTopicConfigs := func(ctx context.Context, inputClient, outputClient *kgo.Client) {
+				var topics []string
+				topicCache.Range(func(key, _ any) bool {
+					topics = append(topics, key.(string))
+					return true
+				})
+				if len(topics) == 0 {
+					return
+				}
+
+				updated, err := syncTopicConfigs(ctx, topics, syncTopicConfigsKeys, inputClient, outputClient)
+				if updated > 0 {
+					mgr.Logger().Infof("Updated the configs of %d topics", updated)
+				}
+				if err != nil {
+					mgr.Logger().Errorf("Failed to sync topic configs: %s", err)
+				}
+			}
+			syncTopicConfigsFn := func(ctx context.Context) {
+				clientMut.Lock()
+				outputClient := client
+				clientMut.Unlock()
+				if outputClient == nil {
+					return
+				}
+
+				if err := kafka.FranzSharedClientUse(inputResource, mgr, func(details *kafka.FranzSharedClientInfo) error {
+					syncMigratedTopicConfigs(ctx, details.Client, outputClient)
+					return nil
+				}); err != nil {
+					mgr.Logger().Errorf("Failed to sync topic configs: %s", err)
+				}
+			}
+
 			output, err = kafka.NewFranzWriterFromConfig(
 				conf,
 				kafka.NewFranzWriterHooks(
@@ -191,9 +295,30 @@ func init() {
 							}
 						}
 
+						// The syncer is stopped whenever the client is yielded,
+						// and therefore it is started again when connecting.
+						if syncTopicConfigsEnabled {
+							topicConfigSyncerMut.Lock()
+							if topicConfigSyncer == nil {
+								topicConfigSyncer = asyncroutine.NewPeriodicWithContext(syncTopicConfigsInterval, syncTopicConfigsFn)
+								topicConfigSyncer.Start()
+							}
+							topicConfigSyncerMut.Unlock()
+						}
+
 						return fn(&kafka.FranzSharedClientInfo{Client: client, ConnDetails: connDetails})
 					}).WithYieldClientFn(
 					func(context.Context) error {
+						// Stop the syncer without holding the lock, as it
+						// might be waiting on the client to be accessed.
+						topicConfigSyncerMut.Lock()
+						syncer := topicConfigSyncer
+						topicConfigSyncer = nil
+						topicConfigSyncerMut.Unlock()
+						if syncer != nil {
+							syncer.Stop()
+						}
+
 						clientMut.Lock()
 						defer clientMut.Unlock()
 
@@ -232,13 +357,25 @@ func init() {
 
 									mgr.Logger().Infof("Created topic %q", topic)
 
-									if err := createACLs(ctx, topic, inputClient, outputClient); err != nil {
+									if err := createACLs(ctx, topic, principals, inputClient, outputClient); err != nil {
 										mgr.Logger().Errorf("Failed to create ACLs for topic %q: %s", topic, err)
 									}
 
 									topicCache.Store(topic, struct{}{})
 								}
 
+								if syncTopicConfigsEnabled {
+									syncMigratedTopicConfigs(ctx, inputClient, outputClient)
+								}
+
+								if migrateGroupACLs {
+									if count, err := createGroupACLs(ctx, principals, inputClient, outputClient); err != nil {
+										mgr.Logger().Errorf("Failed to migrate group ACLs: %s", err)
+									} else {
+										mgr.Logger().Infof("Migrated %d group ACLs", count)
+									}
+								}
+
 								return nil
 							})
 							if err != nil {
@@ -298,7 +435,7 @@ func init() {
 
 									mgr.Logger().Infof("Created topic %q", record.Topic)
 
-									if err := createACLs(ctx, record.Topic, details.Client, client); err != nil {
+									if err := createACLs(ctx, record.Topic, principals, details.Client, client); err != nil {
 										mgr.Logger().Errorf("Failed to create ACLs for topic %q: %s", record.Topic, err)
 									}
 
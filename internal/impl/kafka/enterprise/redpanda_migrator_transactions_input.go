@@ -0,0 +1,277 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/kafka"
+	"github.com/redpanda-data/connect/v4/internal/license"
+)
+
+const (
+	rmtiFieldTransactionalIDPattern = "transactional_id_pattern"
+
+	rmtiResourceDefaultLabel = "redpanda_migrator_transactions_input"
+)
+
+func redpandaMigratorTransactionsInputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.48.0").
+		Summary(`Redpanda Migrator transaction state input using the https://github.com/twmb/franz-go[Franz Kafka client library^].`).
+		Description(`
+Reads the ` + "`__transaction_state`" + ` topic of the source cluster and emits a message for every transactional ID update so
+that the transactional producer state can be migrated by a ` + "`redpanda_migrator_transactions`" + ` output.
+
+Each message contains a JSON document describing the transaction metadata and tombstone records, which signal that a
+transactional ID has expired, are skipped.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+` + "```text" + `
+- kafka_key
+- kafka_topic
+- kafka_partition
+- kafka_offset
+- kafka_timestamp_unix
+- kafka_timestamp_ms
+- kafka_tombstone_message
+- kafka_transactional_id
+- kafka_producer_id
+- kafka_producer_epoch
+- kafka_transaction_timeout_ms
+- kafka_transaction_state
+` + "```" + `
+`).
+		Fields(redpandaMigratorTransactionsInputConfigFields()...)
+}
+
+func redpandaMigratorTransactionsInputConfigFields() []*service.ConfigField {
+	return slices.Concat(
+		kafka.FranzConnectionFields(),
+		[]*service.ConfigField{
+			service.NewStringField(rmtiFieldTransactionalIDPattern).
+				Description("An optional regular expression which transactional IDs must match in order to be migrated.").
+				Example("^payments-.*").
+				Default(""),
+		},
+		kafka.FranzReaderOrderedConfigFields(),
+		[]*service.ConfigField{
+			service.NewAutoRetryNacksToggleField(),
+		},
+	)
+}
+
+func init() {
+	err := service.RegisterBatchInput("redpanda_migrator_transactions", redpandaMigratorTransactionsInputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			if err := license.CheckRunningEnterprise(mgr); err != nil {
+				return nil, err
+			}
+
+			clientOpts, err := kafka.FranzConnectionOptsFromConfig(conf, mgr.Logger())
+			if err != nil {
+				return nil, err
+			}
+
+			var idPattern *regexp.Regexp
+			if pattern, err := conf.FieldString(rmtiFieldTransactionalIDPattern); err != nil {
+				return nil, err
+			} else if pattern != "" {
+				if idPattern, err = regexp.Compile(pattern); err != nil {
+					return nil, fmt.Errorf("failed to compile transactional ID pattern %q: %s", pattern, err)
+				}
+			}
+
+			// Consume the whole `__transaction_state` topic from the start.
+			clientOpts = append(clientOpts,
+				kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+				kgo.ConsumeTopics("__transaction_state"),
+			)
+
+			clientLabel := mgr.Label()
+			if clientLabel == "" {
+				clientLabel = rmtiResourceDefaultLabel
+			}
+
+			rdr, err := kafka.NewFranzReaderOrderedFromConfig(conf, mgr, func() ([]kgo.Opt, error) {
+				return clientOpts, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			return service.AutoRetryNacksBatchedToggled(conf, &redpandaMigratorTransactionsInput{
+				FranzReaderOrdered: rdr,
+				idPattern:          idPattern,
+				clientLabel:        clientLabel,
+				mgr:                mgr,
+			})
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type redpandaMigratorTransactionsInput struct {
+	*kafka.FranzReaderOrdered
+
+	idPattern   *regexp.Regexp
+	clientLabel string
+
+	mgr *service.Resources
+}
+
+func (rmti *redpandaMigratorTransactionsInput) Connect(ctx context.Context) error {
+	if err := rmti.FranzReaderOrdered.Connect(ctx); err != nil {
+		return err
+	}
+
+	if err := kafka.FranzSharedClientSet(rmti.clientLabel, &kafka.FranzSharedClientInfo{
+		Client: rmti.FranzReaderOrdered.Client,
+	}, rmti.mgr); err != nil {
+		rmti.mgr.Logger().Warnf("Failed to store client connection for sharing: %s", err)
+	}
+
+	return nil
+}
+
+func (rmti *redpandaMigratorTransactionsInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	for {
+		batch, ack, err := rmti.FranzReaderOrdered.ReadBatch(ctx)
+		if err != nil {
+			return batch, ack, err
+		}
+
+		batch = slices.DeleteFunc(batch, func(msg *service.Message) bool {
+			return !rmti.decodeTransactionRecord(msg)
+		})
+
+		if len(batch) == 0 {
+			// Acknowledge the records which were filtered out so that their
+			// offsets are committed.
+			if err := ack(ctx, nil); err != nil {
+				rmti.mgr.Logger().Errorf("Failed to acknowledge skipped transaction state records: %s", err)
+			}
+			continue
+		}
+
+		return batch, ack, nil
+	}
+}
+
+// decodeTransactionRecord replaces the contents of a `__transaction_state`
+// record with a JSON document describing the transaction metadata and adds the
+// relevant metadata fields. It returns false if the record should be skipped,
+// which is the case for tombstones, records which can't be decoded and records
+// of transactional IDs which don't match the configured pattern.
+func (rmti *redpandaMigratorTransactionsInput) decodeTransactionRecord(msg *service.Message) bool {
+	var recordKey []byte
+	if key, ok := msg.MetaGetMut("kafka_key"); !ok {
+		return false
+	} else if recordKey, ok = key.([]byte); !ok {
+		return false
+	}
+
+	key := kmsg.NewTxnMetadataKey()
+	if err := key.ReadFrom(recordKey); err != nil || key.Version != 0 {
+		rmti.mgr.Logger().Debugf("Failed to decode record key: %s", err)
+		return false
+	}
+
+	if rmti.idPattern != nil && !rmti.idPattern.MatchString(key.TransactionalID) {
+		rmti.mgr.Logger().Tracef("Skipping updates for transactional ID %q", key.TransactionalID)
+		return false
+	}
+
+	recordValue, err := msg.AsBytes()
+	if err != nil || recordValue == nil {
+		// Tombstones are written when transactional IDs expire.
+		return false
+	}
+
+	value := kmsg.NewTxnMetadataValue()
+	if err := value.ReadFrom(recordValue); err != nil {
+		rmti.mgr.Logger().Debugf("Failed to decode transaction metadata value: %s", err)
+		return false
+	}
+
+	doc, err := json.Marshal(newTransactionMetadata(key, value))
+	if err != nil {
+		return false
+	}
+	msg.SetBytes(doc)
+
+	msg.MetaSetMut("kafka_transactional_id", key.TransactionalID)
+	msg.MetaSetMut("kafka_producer_id", value.ProducerID)
+	msg.MetaSetMut("kafka_producer_epoch", value.ProducerEpoch)
+	msg.MetaSetMut("kafka_transaction_timeout_ms", value.TimeoutMillis)
+	msg.MetaSetMut("kafka_transaction_state", value.State.String())
+
+	return true
+}
+
+func (rmti *redpandaMigratorTransactionsInput) Close(ctx context.Context) error {
+	_, _ = kafka.FranzSharedClientPop(rmti.clientLabel, rmti.mgr)
+
+	return rmti.FranzReaderOrdered.Close(ctx)
+}
+
+//------------------------------------------------------------------------------
+
+type transactionMetadataTopic struct {
+	Topic      string  `json:"topic"`
+	Partitions []int32 `json:"partitions"`
+}
+
+type transactionMetadata struct {
+	TransactionalID     string                     `json:"transactional_id"`
+	ProducerID          int64                      `json:"producer_id"`
+	ProducerEpoch       int16                      `json:"producer_epoch"`
+	TimeoutMillis       int32                      `json:"timeout_ms"`
+	State               string                     `json:"state"`
+	Topics              []transactionMetadataTopic `json:"topics"`
+	LastUpdateTimestamp int64                      `json:"last_update_timestamp"`
+	StartTimestamp      int64                      `json:"start_timestamp"`
+}
+
+func newTransactionMetadata(key kmsg.TxnMetadataKey, value kmsg.TxnMetadataValue) transactionMetadata {
+	md := transactionMetadata{
+		TransactionalID:     key.TransactionalID,
+		ProducerID:          value.ProducerID,
+		ProducerEpoch:       value.ProducerEpoch,
+		TimeoutMillis:       value.TimeoutMillis,
+		State:               value.State.String(),
+		Topics:              make([]transactionMetadataTopic, 0, len(value.Topics)),
+		LastUpdateTimestamp: value.LastUpdateTimestamp,
+		StartTimestamp:      value.StartTimestamp,
+	}
+	for _, t := range value.Topics {
+		md.Topics = append(md.Topics, transactionMetadataTopic{
+			Topic:      t.Topic,
+			Partitions: t.Partitions,
+		})
+	}
+	return md
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func txnStateRecord(keyVersion int16, txnID string, value *kmsg.TxnMetadataValue) *service.Message {
+	key := kmsg.NewTxnMetadataKey()
+	key.Version = keyVersion
+	key.TransactionalID = txnID
+
+	var msg *service.Message
+	if value != nil {
+		msg = service.NewMessage(value.AppendTo(nil))
+	} else {
+		msg = service.NewMessage(nil)
+	}
+	msg.MetaSetMut("kafka_key", key.AppendTo(nil))
+	return msg
+}
+
+func TestTransactionsInputDecodeRecord(t *testing.T) {
+	value := kmsg.NewTxnMetadataValue()
+	value.ProducerID = 1234
+	value.ProducerEpoch = 5
+	value.TimeoutMillis = 60000
+	value.State = kmsg.TransactionStateOngoing
+	value.Topics = []kmsg.TxnMetadataValueTopic{
+		{Topic: "foo", Partitions: []int32{0, 2}},
+	}
+	value.LastUpdateTimestamp = 1700000000100
+	value.StartTimestamp = 1700000000000
+
+	rmti := &redpandaMigratorTransactionsInput{mgr: service.MockResources()}
+
+	msg := txnStateRecord(0, "payments-1", &value)
+	require.True(t, rmti.decodeTransactionRecord(msg))
+
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "transactional_id": "payments-1",
+  "producer_id": 1234,
+  "producer_epoch": 5,
+  "timeout_ms": 60000,
+  "state": "Ongoing",
+  "topics": [{"topic": "foo", "partitions": [0, 2]}],
+  "last_update_timestamp": 1700000000100,
+  "start_timestamp": 1700000000000
+}`, string(b))
+
+	for k, exp := range map[string]any{
+		"kafka_transactional_id":       "payments-1",
+		"kafka_producer_id":            int64(1234),
+		"kafka_producer_epoch":         int16(5),
+		"kafka_transaction_timeout_ms": int32(60000),
+		"kafka_transaction_state":      "Ongoing",
+	} {
+		v, ok := msg.MetaGetMut(k)
+		require.True(t, ok, k)
+		assert.Equal(t, exp, v, k)
+	}
+}
+
+func TestTransactionsInputFilterRecords(t *testing.T) {
+	value := kmsg.NewTxnMetadataValue()
+	value.ProducerID = 1
+	value.TimeoutMillis = 1000
+
+	noKey := service.NewMessage(value.AppendTo(nil))
+
+	invalidKey := service.NewMessage(value.AppendTo(nil))
+	invalidKey.MetaSetMut("kafka_key", []byte{0x00})
+
+	invalidValue := txnStateRecord(0, "payments-1", nil)
+	invalidValue.SetBytes([]byte{0x00, 0x01})
+
+	tests := []struct {
+		name      string
+		idPattern *regexp.Regexp
+		msg       *service.Message
+		keep      bool
+	}{
+		{
+			name: "matches without pattern",
+			msg:  txnStateRecord(0, "payments-1", &value),
+			keep: true,
+		},
+		{
+			name:      "matches pattern",
+			idPattern: regexp.MustCompile("^payments-"),
+			msg:       txnStateRecord(0, "payments-1", &value),
+			keep:      true,
+		},
+		{
+			name:      "does not match pattern",
+			idPattern: regexp.MustCompile("^payments-"),
+			msg:       txnStateRecord(0, "orders-1", &value),
+		},
+		{
+			name: "tombstone",
+			msg:  txnStateRecord(0, "payments-1", nil),
+		},
+		{
+			name: "unsupported key version",
+			msg:  txnStateRecord(1, "payments-1", &value),
+		},
+		{
+			name: "missing key",
+			msg:  noKey,
+		},
+		{
+			name: "invalid key",
+			msg:  invalidKey,
+		},
+		{
+			name: "invalid value",
+			msg:  invalidValue,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rmti := &redpandaMigratorTransactionsInput{
+				idPattern: test.idPattern,
+				mgr:       service.MockResources(),
+			}
+			assert.Equal(t, test.keep, rmti.decodeTransactionRecord(test.msg))
+		})
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/kafka"
+	"github.com/redpanda-data/connect/v4/internal/license"
+)
+
+const (
+	rmtoFieldTransactionalID    = "transactional_id"
+	rmtoFieldTransactionTimeout = "transaction_timeout_ms"
+	rmtoFieldInputResource      = "input_resource"
+	rmtoFieldPrincipalMapping   = "principal_mapping"
+	rmtoFieldInitProducerIDs    = "init_producer_ids"
+)
+
+func redpandaMigratorTransactionsOutputConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Services").
+		Version("4.48.0").
+		Summary("Redpanda Migrator transaction state output using the https://github.com/twmb/franz-go[Franz Kafka client library^].").
+		Description(`
+Migrates transactional IDs read by a ` + "`redpanda_migrator_transactions`" + ` input to the destination cluster so that
+exactly-once producers can cut over without having to start from scratch.
+
+For each transactional ID this output recreates the ACLs which apply to it on the source cluster, queried via the
+` + "`redpanda_migrator_transactions`" + ` input identified by ` + "`input_resource`" + `. When ` + "`init_producer_ids`" + ` is
+enabled it also initialises the transactional ID on the destination cluster with the transaction timeout of the source
+producer, so that the transaction coordinator of the destination cluster already tracks it when the producers cut
+over.
+
+Producer IDs and epochs are assigned by the brokers and can therefore not be copied verbatim.
+`).
+		Fields(redpandaMigratorTransactionsOutputConfigFields()...)
+}
+
+func redpandaMigratorTransactionsOutputConfigFields() []*service.ConfigField {
+	return slices.Concat(
+		kafka.FranzConnectionFields(),
+		[]*service.ConfigField{
+			service.NewInterpolatedStringField(rmtoFieldTransactionalID).
+				Description("The transactional ID to migrate.").
+				Default("${! @kafka_transactional_id }"),
+			service.NewInterpolatedStringField(rmtoFieldTransactionTimeout).
+				Description("The transaction timeout in milliseconds of the transactional ID.").
+				Default("${! @kafka_transaction_timeout_ms }"),
+			service.NewStringField(rmtoFieldInputResource).
+				Description("The label of the redpanda_migrator_transactions input from which to read the ACLs of the transactional IDs.").
+				Default(rmtiResourceDefaultLabel).
+				Advanced(),
+			service.NewStringMapField(rmtoFieldPrincipalMapping).
+				Description("A map of source cluster principals to the principals which should be used instead when creating ACLs on the destination cluster. Principals which are not present in this map are migrated as they are.").
+				Example(map[string]any{"User:alice": "User:alice-migrated"}).
+				Default(map[string]any{}).
+				Advanced(),
+			service.NewBoolField(rmtoFieldInitProducerIDs).
+				Description("Initialise each transactional ID on the destination cluster.").
+				Default(false),
+		},
+	)
+}
+
+func init() {
+	err := service.RegisterOutput("redpanda_migrator_transactions", redpandaMigratorTransactionsOutputConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (
+			output service.Output,
+			maxInFlight int,
+			err error,
+		) {
+			if err = license.CheckRunningEnterprise(mgr); err != nil {
+				return
+			}
+
+			maxInFlight = 1
+
+			output, err = newRedpandaMigratorTransactionsWriterFromConfig(conf, mgr)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type migratedTransactionalID struct {
+	producerID    int64
+	producerEpoch int16
+}
+
+// redpandaMigratorTransactionsWriter implements a Redpanda Migrator transaction state writer using the franz-go library.
+type redpandaMigratorTransactionsWriter struct {
+	clientDetails      *kafka.FranzConnectionDetails
+	transactionalID    *service.InterpolatedString
+	transactionTimeout *service.InterpolatedString
+	inputResource      string
+	principals         principalMapping
+	initProducerIDs    bool
+
+	connMut sync.Mutex
+	client  *kgo.Client
+
+	// Tracks the source producer ID and epoch of the transactional IDs which
+	// have already been migrated, so that they're only migrated again when the
+	// producer state changes.
+	migrated map[string]migratedTransactionalID
+
+	mgr *service.Resources
+}
+
+func newRedpandaMigratorTransactionsWriterFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*redpandaMigratorTransactionsWriter, error) {
+	w := redpandaMigratorTransactionsWriter{
+		migrated: map[string]migratedTransactionalID{},
+		mgr:      mgr,
+	}
+
+	var err error
+	if w.clientDetails, err = kafka.FranzConnectionDetailsFromConfig(conf, mgr.Logger()); err != nil {
+		return nil, err
+	}
+
+	if w.transactionalID, err = conf.FieldInterpolatedString(rmtoFieldTransactionalID); err != nil {
+		return nil, err
+	}
+
+	if w.transactionTimeout, err = conf.FieldInterpolatedString(rmtoFieldTransactionTimeout); err != nil {
+		return nil, err
+	}
+
+	if w.inputResource, err = conf.FieldString(rmtoFieldInputResource); err != nil {
+		return nil, err
+	}
+
+	if w.principals, err = conf.FieldStringMap(rmtoFieldPrincipalMapping); err != nil {
+		return nil, err
+	}
+
+	if w.initProducerIDs, err = conf.FieldBool(rmtoFieldInitProducerIDs); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Connect to the target seed brokers.
+func (w *redpandaMigratorTransactionsWriter) Connect(ctx context.Context) error {
+	w.connMut.Lock()
+	defer w.connMut.Unlock()
+
+	if w.client != nil {
+		return nil
+	}
+
+	client, err := kgo.NewClient(w.clientDetails.FranzOpts()...)
+	if err != nil {
+		return err
+	}
+
+	// Check connectivity to cluster
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		return fmt.Errorf("failed to connect to cluster: %s", err)
+	}
+
+	w.client = client
+
+	return nil
+}
+
+// Write migrates the transactional ID referenced by a message to the output cluster.
+func (w *redpandaMigratorTransactionsWriter) Write(ctx context.Context, msg *service.Message) error {
+	w.connMut.Lock()
+	defer w.connMut.Unlock()
+
+	if w.client == nil {
+		return service.ErrNotConnected
+	}
+
+	txnID, timeoutMs, state, err := w.transactionalIDFromMessage(msg)
+	if err != nil {
+		return err
+	}
+	if prev, exists := w.migrated[txnID]; exists && prev == state {
+		return nil
+	}
+
+	if err := kafka.FranzSharedClientUse(w.inputResource, w.mgr, func(details *kafka.FranzSharedClientInfo) error {
+		return createTransactionalIDACLs(ctx, txnID, w.principals, details.Client, w.client)
+	}); err != nil {
+		return fmt.Errorf("failed to migrate ACLs for transactional ID %q: %s", txnID, err)
+	}
+
+	if w.initProducerIDs {
+		if err := initTransactionalID(ctx, w.client, txnID, timeoutMs); err != nil {
+			return fmt.Errorf("failed to initialise transactional ID %q: %s", txnID, err)
+		}
+	}
+
+	w.migrated[txnID] = state
+	w.mgr.Logger().Debugf("Migrated transactional ID %q", txnID)
+
+	return nil
+}
+
+// transactionalIDFromMessage extracts the transactional ID, the transaction
+// timeout and the source producer state from a message.
+func (w *redpandaMigratorTransactionsWriter) transactionalIDFromMessage(msg *service.Message) (txnID string, timeoutMs int32, state migratedTransactionalID, err error) {
+	if txnID, err = w.transactionalID.TryString(msg); err != nil {
+		err = fmt.Errorf("failed to extract transactional ID: %s", err)
+		return
+	}
+	if txnID == "" {
+		err = errors.New("transactional ID must not be empty")
+		return
+	}
+
+	var t string
+	if t, err = w.transactionTimeout.TryString(msg); err != nil {
+		err = fmt.Errorf("failed to extract transaction timeout: %s", err)
+		return
+	}
+	var i int64
+	if i, err = strconv.ParseInt(t, 10, 32); err != nil {
+		err = fmt.Errorf("failed to parse transaction timeout: %s", err)
+		return
+	}
+	timeoutMs = int32(i)
+
+	if v, ok := msg.MetaGetMut("kafka_producer_id"); ok {
+		state.producerID, _ = v.(int64)
+	}
+	if v, ok := msg.MetaGetMut("kafka_producer_epoch"); ok {
+		state.producerEpoch, _ = v.(int16)
+	}
+	return
+}
+
+// Close underlying connections.
+func (w *redpandaMigratorTransactionsWriter) Close(ctx context.Context) error {
+	w.connMut.Lock()
+	defer w.connMut.Unlock()
+
+	if w.client == nil {
+		return nil
+	}
+
+	w.client.Close()
+	w.client = nil
+
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// transactionalIDACLsFilter returns a filter matching all the ACLs which apply
+// to the given transactional ID. The MATCH pattern is used so that prefixed and
+// wildcard ACLs are included as well.
+func transactionalIDACLsFilter(txnID string) *kadm.ACLBuilder {
+	return kadm.NewACLs().TransactionalIDs(txnID).
+		ResourcePatternType(kadm.ACLPatternMatch).Operations().Allow().Deny().AllowHosts().DenyHosts()
+}
+
+// transactionalIDACLBuilder returns a builder which recreates the given
+// transactional ID ACL with its principal remapped. The second return value is
+// false if the ACL has a permission type which can't be migrated.
+func transactionalIDACLBuilder(acl kadm.DescribedACL, principals principalMapping) (*kadm.ACLBuilder, bool) {
+	principal := principals.remap(acl.Principal)
+	switch acl.Permission {
+	case kmsg.ACLPermissionTypeAllow:
+		return kadm.NewACLs().Allow(principal).AllowHosts(acl.Host).TransactionalIDs(acl.Name).ResourcePatternType(acl.Pattern).Operations(acl.Operation), true
+	case kmsg.ACLPermissionTypeDeny:
+		return kadm.NewACLs().Deny(principal).DenyHosts(acl.Host).TransactionalIDs(acl.Name).ResourcePatternType(acl.Pattern).Operations(acl.Operation), true
+	}
+	return nil, false
+}
+
+func createTransactionalIDACLs(ctx context.Context, txnID string, principals principalMapping, inputClient *kgo.Client, outputClient *kgo.Client) error {
+	inputAdminClient := kadm.NewClient(inputClient)
+	outputAdminClient := kadm.NewClient(outputClient)
+
+	inputACLResults, err := inputAdminClient.DescribeACLs(ctx, transactionalIDACLsFilter(txnID))
+	if err != nil {
+		return fmt.Errorf("failed to fetch ACLs: %s", err)
+	}
+
+	for _, res := range inputACLResults {
+		if res.Err != nil {
+			return fmt.Errorf("failed to fetch ACLs: %s", res.Err)
+		}
+
+		for _, acl := range res.Described {
+			builder, ok := transactionalIDACLBuilder(acl, principals)
+			if !ok {
+				continue
+			}
+
+			// Attempting to overwrite existing ACLs is idempotent and doesn't seem to raise an error.
+			if _, err := outputAdminClient.CreateACLs(ctx, builder); err != nil {
+				return fmt.Errorf("failed to create ACLs: %s", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// initTransactionalID registers a transactional ID with the transaction
+// coordinator of the given cluster.
+func initTransactionalID(ctx context.Context, client *kgo.Client, txnID string, timeoutMs int32) error {
+	resp, err := newInitTransactionalIDRequest(txnID, timeoutMs).RequestWith(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	return kerr.ErrorForCode(resp.ErrorCode)
+}
+
+// newInitTransactionalIDRequest returns an InitProducerID request which asks
+// the transaction coordinator for a fresh producer ID and epoch for the given
+// transactional ID. Source producer IDs can't be reused because they're
+// assigned by the brokers of each cluster.
+func newInitTransactionalIDRequest(txnID string, timeoutMs int32) *kmsg.InitProducerIDRequest {
+	req := kmsg.NewPtrInitProducerIDRequest()
+	req.TransactionalID = &txnID
+	req.TransactionTimeoutMillis = timeoutMs
+	req.ProducerID = -1
+	req.ProducerEpoch = -1
+	return req
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testTransactionsWriter(t *testing.T, yaml string) *redpandaMigratorTransactionsWriter {
+	t.Helper()
+
+	conf, err := redpandaMigratorTransactionsOutputConfig().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+
+	w, err := newRedpandaMigratorTransactionsWriterFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	return w
+}
+
+func TestTransactionsOutputTransactionalIDFromMessage(t *testing.T) {
+	w := testTransactionsWriter(t, `
+seed_brokers: [ localhost:9092 ]
+`)
+
+	msg := service.NewMessage(nil)
+	msg.MetaSetMut("kafka_transactional_id", "payments-1")
+	msg.MetaSetMut("kafka_transaction_timeout_ms", int32(60000))
+	msg.MetaSetMut("kafka_producer_id", int64(1234))
+	msg.MetaSetMut("kafka_producer_epoch", int16(5))
+
+	txnID, timeoutMs, state, err := w.transactionalIDFromMessage(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "payments-1", txnID)
+	assert.Equal(t, int32(60000), timeoutMs)
+	assert.Equal(t, migratedTransactionalID{producerID: 1234, producerEpoch: 5}, state)
+}
+
+func TestTransactionsOutputTransactionalIDFromMessageErrors(t *testing.T) {
+	w := testTransactionsWriter(t, `
+seed_brokers: [ localhost:9092 ]
+`)
+
+	tests := []struct {
+		name        string
+		meta        map[string]any
+		errContains string
+	}{
+		{
+			name:        "empty transactional ID",
+			meta:        map[string]any{"kafka_transactional_id": "", "kafka_transaction_timeout_ms": int32(1000)},
+			errContains: "transactional ID must not be empty",
+		},
+		{
+			name:        "missing timeout",
+			meta:        map[string]any{"kafka_transactional_id": "foo"},
+			errContains: "failed to parse transaction timeout",
+		},
+		{
+			name:        "timeout out of range",
+			meta:        map[string]any{"kafka_transactional_id": "foo", "kafka_transaction_timeout_ms": "3000000000"},
+			errContains: "failed to parse transaction timeout",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := service.NewMessage(nil)
+			for k, v := range test.meta {
+				msg.MetaSetMut(k, v)
+			}
+			_, _, _, err := w.transactionalIDFromMessage(msg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errContains)
+		})
+	}
+}
+
+func TestTransactionsOutputSkipsUnchangedProducerState(t *testing.T) {
+	w := testTransactionsWriter(t, `
+seed_brokers: [ localhost:9092 ]
+input_resource: does_not_exist
+`)
+
+	client, err := kgo.NewClient(kgo.SeedBrokers("localhost:0"))
+	require.NoError(t, err)
+	w.client = client
+	t.Cleanup(func() { _ = w.Close(context.Background()) })
+
+	w.migrated["payments-1"] = migratedTransactionalID{producerID: 1234, producerEpoch: 5}
+
+	newMsg := func(epoch int16) *service.Message {
+		msg := service.NewMessage(nil)
+		msg.MetaSetMut("kafka_transactional_id", "payments-1")
+		msg.MetaSetMut("kafka_transaction_timeout_ms", int32(60000))
+		msg.MetaSetMut("kafka_producer_id", int64(1234))
+		msg.MetaSetMut("kafka_producer_epoch", epoch)
+		return msg
+	}
+
+	// The producer state hasn't changed so nothing needs to be migrated.
+	require.NoError(t, w.Write(context.Background(), newMsg(5)))
+
+	// A new epoch requires the transactional ID to be migrated again, which
+	// fails here because the input resource doesn't exist.
+	err = w.Write(context.Background(), newMsg(6))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to migrate ACLs for transactional ID \"payments-1\"")
+	assert.Equal(t, migratedTransactionalID{producerID: 1234, producerEpoch: 5}, w.migrated["payments-1"])
+}
+
+func TestTransactionsOutputInitRequest(t *testing.T) {
+	req := newInitTransactionalIDRequest("payments-1", 60000)
+	require.NotNil(t, req.TransactionalID)
+	assert.Equal(t, "payments-1", *req.TransactionalID)
+	assert.Equal(t, int32(60000), req.TransactionTimeoutMillis)
+	assert.Equal(t, int64(-1), req.ProducerID)
+	assert.Equal(t, int16(-1), req.ProducerEpoch)
+}
+
+func TestTransactionalIDACLsFilter(t *testing.T) {
+	filter := transactionalIDACLsFilter("payments-1")
+	assert.NoError(t, filter.ValidateDescribe())
+	assert.Equal(t, kadm.NewACLs().TransactionalIDs("payments-1").
+		ResourcePatternType(kadm.ACLPatternMatch).Operations().Allow().Deny().AllowHosts().DenyHosts(), filter)
+}
+
+func TestTransactionalIDACLBuilder(t *testing.T) {
+	principals := principalMapping{"User:alice": "User:alice-migrated"}
+
+	tests := []struct {
+		name     string
+		acl      kadm.DescribedACL
+		expected *kadm.ACLBuilder
+	}{
+		{
+			name: "allow with remapped principal",
+			acl: kadm.DescribedACL{
+				Principal:  "User:alice",
+				Host:       "*",
+				Type:       kmsg.ACLResourceTypeTransactionalId,
+				Name:       "payments-1",
+				Pattern:    kadm.ACLPatternLiteral,
+				Operation:  kmsg.ACLOperationWrite,
+				Permission: kmsg.ACLPermissionTypeAllow,
+			},
+			expected: kadm.NewACLs().Allow("User:alice-migrated").AllowHosts("*").TransactionalIDs("payments-1").
+				ResourcePatternType(kadm.ACLPatternLiteral).Operations(kmsg.ACLOperationWrite),
+		},
+		{
+			name: "prefixed deny with unmapped principal",
+			acl: kadm.DescribedACL{
+				Principal:  "User:bob",
+				Host:       "10.0.0.1",
+				Type:       kmsg.ACLResourceTypeTransactionalId,
+				Name:       "payments-",
+				Pattern:    kadm.ACLPatternPrefixed,
+				Operation:  kmsg.ACLOperationDescribe,
+				Permission: kmsg.ACLPermissionTypeDeny,
+			},
+			expected: kadm.NewACLs().Deny("User:bob").DenyHosts("10.0.0.1").TransactionalIDs("payments-").
+				ResourcePatternType(kadm.ACLPatternPrefixed).Operations(kmsg.ACLOperationDescribe),
+		},
+		{
+			name: "unknown permission is skipped",
+			acl: kadm.DescribedACL{
+				Principal:  "User:alice",
+				Host:       "*",
+				Type:       kmsg.ACLResourceTypeTransactionalId,
+				Name:       "payments-1",
+				Pattern:    kadm.ACLPatternLiteral,
+				Operation:  kmsg.ACLOperationWrite,
+				Permission: kmsg.ACLPermissionTypeAny,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder, ok := transactionalIDACLBuilder(test.acl, principals)
+			if test.expected == nil {
+				assert.False(t, ok)
+				assert.Nil(t, builder)
+				return
+			}
+			require.True(t, ok)
+			assert.NoError(t, builder.ValidateCreate())
+			assert.Equal(t, test.expected, builder)
+		})
+	}
+}
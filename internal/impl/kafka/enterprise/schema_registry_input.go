@@ -214,17 +214,18 @@ func (i *schemaRegistryInput) Connect(ctx context.Context) error {
 			}
 		}
 
-		// Sort schemas by ID to ensure that schemas with references are sent in the correct order.
+		// Sort schemas by ID and then make sure that referenced schemas are sent before the schemas referencing them.
 		schemaIDs := make([]int, 0, len(schemas))
 		for id := range schemas {
 			schemaIDs = append(schemaIDs, id)
 		}
 		sort.Ints(schemaIDs)
 
-		i.schemas = make([]franz_sr.SubjectSchema, 0, len(schemas))
+		sorted := make([]franz_sr.SubjectSchema, 0, len(schemas))
 		for _, id := range schemaIDs {
-			i.schemas = append(i.schemas, schemas[id]...)
+			sorted = append(sorted, schemas[id]...)
 		}
+		i.schemas = sortSchemasByReferences(sorted)
 	}
 
 	i.connected = true
@@ -312,3 +313,44 @@ func (i *schemaRegistryInput) Close(ctx context.Context) error {
 
 	return nil
 }
+
+// sortSchemasByReferences returns the provided schemas in an order where each
+// schema is preceded by all the schemas it references, either directly or
+// transitively. Otherwise, the existing order of the schemas is retained.
+// References to schemas which aren't part of the list are ignored.
+func sortSchemasByReferences(schemas []franz_sr.SubjectSchema) []franz_sr.SubjectSchema {
+	type subjectVersion struct {
+		subject string
+		version int
+	}
+
+	bySubjectVersion := make(map[subjectVersion]int, len(schemas))
+	for idx, s := range schemas {
+		bySubjectVersion[subjectVersion{subject: s.Subject, version: s.Version}] = idx
+	}
+
+	sorted := make([]franz_sr.SubjectSchema, 0, len(schemas))
+	visited := make([]bool, len(schemas))
+
+	var visit func(idx int)
+	visit = func(idx int) {
+		if visited[idx] {
+			return
+		}
+		// Schema Registry rejects circular references, so marking the schema
+		// as visited before its references is only a safeguard.
+		visited[idx] = true
+		for _, ref := range schemas[idx].References {
+			if refIdx, ok := bySubjectVersion[subjectVersion{subject: ref.Subject, version: ref.Version}]; ok {
+				visit(refIdx)
+			}
+		}
+		sorted = append(sorted, schemas[idx])
+	}
+
+	for idx := range schemas {
+		visit(idx)
+	}
+
+	return sorted
+}
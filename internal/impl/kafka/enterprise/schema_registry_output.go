@@ -33,6 +33,7 @@ const (
 	sroFieldSubject              = "subject"
 	sroFieldBackfillDependencies = "backfill_dependencies"
 	sroFieldInputResource        = "input_resource"
+	sroFieldImportMode           = "import_mode"
 	sroFieldTLS                  = "tls"
 
 	sroResourceDefaultLabel = "schema_registry_output"
@@ -78,6 +79,11 @@ func schemaRegistryOutputConfigFields() []*service.ConfigField {
 			Description("The label of the schema_registry input from which to read source schemas.").
 			Default(sriResourceDefaultLabel).
 			Advanced(),
+		service.NewBoolField(sroFieldImportMode).
+			Description("Put each subject into `IMPORT` mode before writing to it and register schemas with their original IDs and versions, so that serialised payloads which carry embedded schema IDs remain decodable without having to translate them. Subjects are left in `IMPORT` mode and need to be switched back to `READWRITE` once the migration is complete.").
+			Default(false).
+			Advanced().
+			Version("4.48.0"),
 		service.NewTLSToggledField(sroFieldTLS),
 		service.NewOutputMaxInFlightField(),
 	},
@@ -108,6 +114,7 @@ type schemaRegistryOutput struct {
 	subject              *service.InterpolatedString
 	backfillDependencies bool
 	inputResource        srResourceKey
+	importMode           bool
 
 	client      *sr.Client
 	inputClient *sr.Client
@@ -115,6 +122,10 @@ type schemaRegistryOutput struct {
 	mgr         *service.Resources
 	// Stores <SchemaID, SchemaVersionID, Subject> as key and destination SchemaID as value.
 	schemaLineageCache sync.Map
+	// Stores <Subject, SchemaVersionID> of source schemas as key and the destination SchemaVersionID as value.
+	schemaVersionCache sync.Map
+	// Stores the subjects which have been put into IMPORT mode.
+	importSubjects sync.Map
 }
 
 func outputFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (o *schemaRegistryOutput, err error) {
@@ -139,6 +150,10 @@ func outputFromParsed(pConf *service.ParsedConfig, mgr *service.Resources) (o *s
 		return
 	}
 
+	if o.importMode, err = pConf.FieldBool(sroFieldImportMode); err != nil {
+		return
+	}
+
 	if o.backfillDependencies {
 		var res string
 		if res, err = pConf.FieldString(sroFieldInputResource); err != nil {
@@ -366,23 +381,73 @@ func (o *schemaRegistryOutput) createSchemaDeps(ctx context.Context, ss franz_sr
 	return nil
 }
 
+// schemaVersionCacheKey identifies a schema version of a subject.
+type schemaVersionCacheKey struct {
+	subject   string
+	versionID int
+}
+
 // createSchema creates and caches the provided schema.
 func (o *schemaRegistryOutput) createSchema(ctx context.Context, key schemaLineageCacheKey, ss franz_sr.SubjectSchema) (int, error) {
 	if destinationID, ok := o.schemaLineageCache.Load(key); ok {
 		return destinationID.(int), nil
 	}
 
-	// TODO: Use `CreateSchemaWithID()` when `translate_ids: false` after https://github.com/twmb/franz-go/pull/849
-	// is merged.
+	var destination franz_sr.SubjectSchema
+	var err error
+	if o.importMode {
+		if err := o.ensureImportMode(ctx, ss.Subject); err != nil {
+			return -1, err
+		}
 
-	// This should return the destination ID without an error if the schema already exists.
-	destinationID, err := o.client.CreateSchema(ctx, ss.Subject, ss.Schema)
-	if err != nil {
-		return -1, fmt.Errorf("failed to create schema for subject %q and version %d: %s", ss.Subject, ss.Version, err)
+		// Schemas keep their IDs and versions, so references don't need to be translated.
+		if destination, err = o.client.CreateSchemaWithIDAndVersion(ctx, ss.Subject, ss.Schema, ss.ID, ss.Version); err != nil {
+			return -1, fmt.Errorf("failed to import schema for subject %q and version %d: %s", ss.Subject, ss.Version, err)
+		}
+	} else {
+		// This should return the destination ID without an error if the schema already exists.
+		if destination, err = o.client.CreateSchema(ctx, ss.Subject, o.translateReferences(ss.Schema)); err != nil {
+			return -1, fmt.Errorf("failed to create schema for subject %q and version %d: %s", ss.Subject, ss.Version, err)
+		}
 	}
 
-	// Cache the schema along with the destination ID.
-	o.schemaLineageCache.Store(key, destinationID)
+	// Cache the schema along with the destination ID and version.
+	o.schemaLineageCache.Store(key, destination.ID)
+	o.schemaVersionCache.Store(schemaVersionCacheKey{subject: ss.Subject, versionID: ss.Version}, destination.Version)
 
-	return destinationID, nil
+	return destination.ID, nil
+}
+
+// ensureImportMode puts the provided subject into IMPORT mode unless this has already been done.
+func (o *schemaRegistryOutput) ensureImportMode(ctx context.Context, subject string) error {
+	if _, ok := o.importSubjects.Load(subject); ok {
+		return nil
+	}
+
+	if err := o.client.SetSubjectMode(ctx, subject, franz_sr.ModeImport); err != nil {
+		return fmt.Errorf("failed to set IMPORT mode for subject %q: %s", subject, err)
+	}
+	o.importSubjects.Store(subject, struct{}{})
+
+	return nil
+}
+
+// translateReferences returns a copy of the provided schema where the versions of the referenced schemas are replaced
+// with the versions they were assigned in the destination Schema Registry. References which haven't been migrated yet
+// are left unchanged.
+func (o *schemaRegistryOutput) translateReferences(schema franz_sr.Schema) franz_sr.Schema {
+	if len(schema.References) == 0 {
+		return schema
+	}
+
+	refs := make([]franz_sr.SchemaReference, 0, len(schema.References))
+	for _, ref := range schema.References {
+		if version, ok := o.schemaVersionCache.Load(schemaVersionCacheKey{subject: ref.Subject, versionID: ref.Version}); ok {
+			ref.Version = version.(int)
+		}
+		refs = append(refs, ref)
+	}
+	schema.References = refs
+
+	return schema
 }
@@ -152,3 +152,115 @@ subject: ${! @schema_registry_subject }
 	require.NoError(t, err)
 	assert.Equal(t, 2, destID)
 }
+
+func TestSortSchemasByReferences(t *testing.T) {
+	schemas := []sr.SubjectSchema{
+		{Subject: "baz", Version: 1, ID: 1, Schema: sr.Schema{
+			References: []sr.SchemaReference{{Name: "bar", Subject: "bar", Version: 2}},
+		}},
+		{Subject: "bar", Version: 2, ID: 2, Schema: sr.Schema{
+			References: []sr.SchemaReference{{Name: "foo", Subject: "foo", Version: 1}},
+		}},
+		{Subject: "foo", Version: 1, ID: 3},
+		{Subject: "qux", Version: 1, ID: 4, Schema: sr.Schema{
+			References: []sr.SchemaReference{{Name: "missing", Subject: "missing", Version: 1}},
+		}},
+	}
+
+	var order []string
+	for _, s := range sortSchemasByReferences(schemas) {
+		order = append(order, fmt.Sprintf("%s:%d", s.Subject, s.Version))
+	}
+	assert.Equal(t, []string{"foo:1", "bar:2", "baz:1", "qux:1"}, order)
+}
+
+func TestSchemaRegistryOutputTranslateReferences(t *testing.T) {
+	o := &schemaRegistryOutput{}
+	o.schemaVersionCache.Store(schemaVersionCacheKey{subject: "foo", versionID: 3}, 1)
+
+	schema := sr.Schema{
+		Schema: `{"name":"bar", "type": "record", "fields":[{"name":"data", "type": "foo"}]}`,
+		References: []sr.SchemaReference{
+			{Name: "foo", Subject: "foo", Version: 3},
+			{Name: "baz", Subject: "baz", Version: 2},
+		},
+	}
+
+	translated := o.translateReferences(schema)
+	assert.Equal(t, []sr.SchemaReference{
+		{Name: "foo", Subject: "foo", Version: 1},
+		{Name: "baz", Subject: "baz", Version: 2},
+	}, translated.References)
+
+	// The source schema must remain untouched.
+	assert.Equal(t, 3, schema.References[0].Version)
+}
+
+func TestSchemaRegistryOutputImportMode(t *testing.T) {
+	var (
+		modes    = map[string]string{}
+		imported []sr.SubjectSchema
+	)
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.EscapedPath()
+			var output any
+			switch {
+			case path == "/mode":
+				output = map[string]string{"mode": "READWRITE"}
+			case path == "/mode/foo" && r.Method == http.MethodPut:
+				assert.Equal(t, "true", r.URL.Query().Get("force"))
+				var body map[string]string
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				modes["foo"] = body["mode"]
+				output = body
+			case path == "/subjects/foo/versions" && r.Method == http.MethodPost:
+				var ss sr.SubjectSchema
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&ss))
+				imported = append(imported, ss)
+				output = map[string]int{"id": ss.ID}
+			case path == "/schemas/ids/42/versions":
+				output = []map[string]any{{"subject": "foo", "version": 7}}
+			case path == "/subjects/foo/versions/7":
+				output = sr.SubjectSchema{Subject: "foo", Version: 7, ID: 42, Schema: sr.Schema{Schema: `"string"`}}
+			default:
+				http.Error(w, fmt.Sprintf("path not found: %s", path), http.StatusNotFound)
+				return
+			}
+			b, err := json.Marshal(output)
+			require.NoError(t, err)
+			_, err = w.Write(b)
+			require.NoError(t, err)
+		}),
+	)
+	t.Cleanup(ts.Close)
+
+	mgr := service.MockResources()
+	license.InjectTestService(mgr)
+
+	outputConf, err := schemaRegistryOutputSpec().ParseYAML(fmt.Sprintf(`
+url: %s
+subject: ${! @schema_registry_subject }
+backfill_dependencies: false
+import_mode: true
+`, ts.URL), nil)
+	require.NoError(t, err)
+
+	writer, err := outputFromParsed(outputConf, mgr)
+	require.NoError(t, err)
+
+	ctx, done := context.WithTimeout(context.Background(), 1*time.Second)
+	t.Cleanup(done)
+	require.NoError(t, writer.Connect(ctx))
+
+	schema, err := json.Marshal(sr.SubjectSchema{Subject: "foo", Version: 7, ID: 42, Schema: sr.Schema{Schema: `"string"`}})
+	require.NoError(t, err)
+	msg := service.NewMessage(schema)
+	msg.MetaSetMut("schema_registry_subject", "foo")
+	require.NoError(t, writer.Write(ctx, msg))
+
+	assert.Equal(t, map[string]string{"foo": "IMPORT"}, modes)
+	require.Len(t, imported, 1)
+	assert.Equal(t, 42, imported[0].ID)
+	assert.Equal(t, 7, imported[0].Version)
+}
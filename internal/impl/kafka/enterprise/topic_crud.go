@@ -65,11 +65,22 @@ func createTopic(ctx context.Context, topic string, replicationFactorOverride bo
 	return nil
 }
 
-func createACLs(ctx context.Context, topic string, inputClient *kgo.Client, outputClient *kgo.Client) error {
+// principalMapping maps principals from the source cluster to the principals
+// which should be used when recreating ACLs on the destination cluster.
+type principalMapping map[string]string
+
+func (m principalMapping) remap(principal string) string {
+	if mapped, ok := m[principal]; ok {
+		return mapped
+	}
+	return principal
+}
+
+func createACLs(ctx context.Context, topic string, principals principalMapping, inputClient *kgo.Client, outputClient *kgo.Client) error {
 	inputAdminClient := kadm.NewClient(inputClient)
 	outputAdminClient := kadm.NewClient(outputClient)
 
-	// Only topic ACLs are migrated, group ACLs are not migrated.
+	// Only topic ACLs are migrated here, group ACLs are migrated separately.
 	// Users are not migrated because we can't read passwords.
 
 	aclBuilder := kadm.NewACLs().Topics(topic).
@@ -97,11 +108,12 @@ func createACLs(ctx context.Context, topic string, inputClient *kgo.Client, outp
 			// ALLOW ALL ACLs for topics are downgraded to ALLOW READ.
 			op = kmsg.ACLOperationRead
 		}
+		principal := principals.remap(acl.Principal)
 		switch acl.Permission {
 		case kmsg.ACLPermissionTypeAllow:
-			builder = builder.Allow(acl.Principal).AllowHosts(acl.Host).Topics(acl.Name).ResourcePatternType(acl.Pattern).Operations(op)
+			builder = builder.Allow(principal).AllowHosts(acl.Host).Topics(acl.Name).ResourcePatternType(acl.Pattern).Operations(op)
 		case kmsg.ACLPermissionTypeDeny:
-			builder = builder.Deny(acl.Principal).DenyHosts(acl.Host).Topics(acl.Name).ResourcePatternType(acl.Pattern).Operations(op)
+			builder = builder.Deny(principal).DenyHosts(acl.Host).Topics(acl.Name).ResourcePatternType(acl.Pattern).Operations(op)
 		}
 
 		// Attempting to overwrite existing ACLs is idempotent and doesn't seem to raise an error.
@@ -112,3 +124,160 @@ func createACLs(ctx context.Context, topic string, inputClient *kgo.Client, outp
 
 	return nil
 }
+
+// groupACLsFilter returns a filter matching all consumer group ACLs,
+// regardless of their pattern type, principal or host.
+func groupACLsFilter() *kadm.ACLBuilder {
+	return kadm.NewACLs().Groups().
+		ResourcePatternType(kadm.ACLPatternAny).Operations().Allow().Deny().AllowHosts().DenyHosts()
+}
+
+// groupACLBuilder returns a builder which recreates the given consumer group
+// ACL with its principal remapped. The second return value is false if the ACL
+// has a permission type which can't be migrated.
+func groupACLBuilder(acl kadm.DescribedACL, principals principalMapping) (*kadm.ACLBuilder, bool) {
+	principal := principals.remap(acl.Principal)
+	switch acl.Permission {
+	case kmsg.ACLPermissionTypeAllow:
+		return kadm.NewACLs().Allow(principal).AllowHosts(acl.Host).Groups(acl.Name).ResourcePatternType(acl.Pattern).Operations(acl.Operation), true
+	case kmsg.ACLPermissionTypeDeny:
+		return kadm.NewACLs().Deny(principal).DenyHosts(acl.Host).Groups(acl.Name).ResourcePatternType(acl.Pattern).Operations(acl.Operation), true
+	}
+	return nil, false
+}
+
+func createGroupACLs(ctx context.Context, principals principalMapping, inputClient *kgo.Client, outputClient *kgo.Client) (int, error) {
+	inputAdminClient := kadm.NewClient(inputClient)
+	outputAdminClient := kadm.NewClient(outputClient)
+
+	inputACLResults, err := inputAdminClient.DescribeACLs(ctx, groupACLsFilter())
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch group ACLs: %s", err)
+	}
+
+	var created int
+	for _, res := range inputACLResults {
+		if res.Err != nil {
+			return created, fmt.Errorf("failed to fetch group ACLs: %s", res.Err)
+		}
+
+		for _, acl := range res.Described {
+			builder, ok := groupACLBuilder(acl, principals)
+			if !ok {
+				continue
+			}
+
+			if _, err := outputAdminClient.CreateACLs(ctx, builder); err != nil {
+				return created, fmt.Errorf("failed to create ACLs for group %q: %s", acl.Name, err)
+			}
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// topicConfigAlterations returns the alterations which need to be applied to
+// the configuration of a destination topic in order for the given keys to
+// match the source topic. Keys which are explicitly set on the source topic are
+// set to the same value and keys which are only overridden on the destination
+// topic are reset to their default.
+func topicConfigAlterations(source, destination []kadm.Config, keys []string) []kadm.AlterConfig {
+	explicit := func(configs []kadm.Config, key string) (*string, bool) {
+		for _, c := range configs {
+			if c.Key == key {
+				if c.Source != kmsg.ConfigSourceDynamicTopicConfig || c.Sensitive {
+					return nil, false
+				}
+				return c.Value, true
+			}
+		}
+		return nil, false
+	}
+
+	var alterations []kadm.AlterConfig
+	for _, key := range keys {
+		sourceValue, sourceSet := explicit(source, key)
+		destValue, destSet := explicit(destination, key)
+
+		switch {
+		case sourceSet:
+			if destSet && ptrStringEqual(sourceValue, destValue) {
+				continue
+			}
+			alterations = append(alterations, kadm.AlterConfig{Op: kadm.SetConfig, Name: key, Value: sourceValue})
+		case destSet:
+			alterations = append(alterations, kadm.AlterConfig{Op: kadm.DeleteConfig, Name: key})
+		}
+	}
+	return alterations
+}
+
+func ptrStringEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// syncTopicConfigs applies the given configuration keys of the source topics
+// to the destination topics and returns the number of topics which were
+// updated.
+func syncTopicConfigs(ctx context.Context, topics, keys []string, inputClient *kgo.Client, outputClient *kgo.Client) (int, error) {
+	inputAdminClient := kadm.NewClient(inputClient)
+	outputAdminClient := kadm.NewClient(outputClient)
+
+	inputConfigs, err := inputAdminClient.DescribeTopicConfigs(ctx, topics...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch topic configs from source broker: %s", err)
+	}
+
+	outputConfigs, err := outputAdminClient.DescribeTopicConfigs(ctx, topics...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch topic configs from output broker: %s", err)
+	}
+
+	var updated int
+	var errs []error
+	for _, topic := range topics {
+		source, err := inputConfigs.On(topic, nil)
+		if err == nil {
+			err = source.Err
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch configs of topic %q from source broker: %s", topic, err))
+			continue
+		}
+
+		destination, err := outputConfigs.On(topic, nil)
+		if err == nil {
+			err = destination.Err
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch configs of topic %q from output broker: %s", topic, err))
+			continue
+		}
+
+		alterations := topicConfigAlterations(source.Configs, destination.Configs, keys)
+		if len(alterations) == 0 {
+			continue
+		}
+
+		resps, err := outputAdminClient.AlterTopicConfigs(ctx, alterations, topic)
+		if err == nil {
+			for _, resp := range resps {
+				if resp.Err != nil {
+					err = resp.Err
+					break
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update configs of topic %q: %s", topic, err))
+			continue
+		}
+		updated++
+	}
+
+	return updated, errors.Join(errs...)
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package enterprise
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestTopicConfigAlterations(t *testing.T) {
+	str := func(s string) *string { return &s }
+	dynamic := func(key, value string) kadm.Config {
+		return kadm.Config{Key: key, Value: str(value), Source: kmsg.ConfigSourceDynamicTopicConfig}
+	}
+	def := func(key, value string) kadm.Config {
+		return kadm.Config{Key: key, Value: str(value), Source: kmsg.ConfigSourceDefaultConfig}
+	}
+
+	source := []kadm.Config{
+		dynamic("retention.ms", "1000"),
+		dynamic("cleanup.policy", "compact"),
+		def("compression.type", "producer"),
+		def("max.message.bytes", "1048588"),
+		dynamic("segment.ms", "60000"),
+	}
+	destination := []kadm.Config{
+		dynamic("retention.ms", "2000"),
+		dynamic("cleanup.policy", "compact"),
+		dynamic("compression.type", "zstd"),
+		def("max.message.bytes", "1048588"),
+		def("segment.ms", "604800000"),
+	}
+
+	alterations := topicConfigAlterations(source, destination, []string{
+		"retention.ms", "cleanup.policy", "compression.type", "max.message.bytes", "unknown",
+	})
+	assert.Equal(t, []kadm.AlterConfig{
+		{Op: kadm.SetConfig, Name: "retention.ms", Value: str("1000")},
+		{Op: kadm.DeleteConfig, Name: "compression.type"},
+	}, alterations)
+}
+
+func TestPrincipalMappingRemap(t *testing.T) {
+	tests := []struct {
+		name      string
+		mapping   principalMapping
+		principal string
+		expected  string
+	}{
+		{
+			name:      "nil mapping",
+			principal: "User:alice",
+			expected:  "User:alice",
+		},
+		{
+			name:      "mapped principal",
+			mapping:   principalMapping{"User:alice": "User:bob"},
+			principal: "User:alice",
+			expected:  "User:bob",
+		},
+		{
+			name:      "unmapped principal",
+			mapping:   principalMapping{"User:alice": "User:bob"},
+			principal: "User:carol",
+			expected:  "User:carol",
+		},
+		{
+			name:      "mapping is not transitive",
+			mapping:   principalMapping{"User:alice": "User:bob", "User:bob": "User:carol"},
+			principal: "User:alice",
+			expected:  "User:bob",
+		},
+		{
+			name:      "principal type is part of the key",
+			mapping:   principalMapping{"User:alice": "User:bob"},
+			principal: "Group:alice",
+			expected:  "Group:alice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.mapping.remap(test.principal))
+		})
+	}
+}
+
+func TestGroupACLsFilter(t *testing.T) {
+	filter := groupACLsFilter()
+	assert.NoError(t, filter.ValidateDescribe())
+	assert.Equal(t, kadm.NewACLs().Groups().
+		ResourcePatternType(kadm.ACLPatternAny).Operations().Allow().Deny().AllowHosts().DenyHosts(), filter)
+}
+
+func TestGroupACLBuilder(t *testing.T) {
+	principals := principalMapping{"User:alice": "User:bob"}
+
+	tests := []struct {
+		name     string
+		acl      kadm.DescribedACL
+		expected *kadm.ACLBuilder
+	}{
+		{
+			name: "allow with remapped principal",
+			acl: kadm.DescribedACL{
+				Principal:  "User:alice",
+				Host:       "*",
+				Type:       kmsg.ACLResourceTypeGroup,
+				Name:       "foo",
+				Pattern:    kadm.ACLPatternLiteral,
+				Operation:  kmsg.ACLOperationRead,
+				Permission: kmsg.ACLPermissionTypeAllow,
+			},
+			expected: kadm.NewACLs().Allow("User:bob").AllowHosts("*").Groups("foo").
+				ResourcePatternType(kadm.ACLPatternLiteral).Operations(kmsg.ACLOperationRead),
+		},
+		{
+			name: "deny with unmapped principal",
+			acl: kadm.DescribedACL{
+				Principal:  "User:carol",
+				Host:       "10.0.0.1",
+				Type:       kmsg.ACLResourceTypeGroup,
+				Name:       "foo",
+				Pattern:    kadm.ACLPatternLiteral,
+				Operation:  kmsg.ACLOperationDescribe,
+				Permission: kmsg.ACLPermissionTypeDeny,
+			},
+			expected: kadm.NewACLs().Deny("User:carol").DenyHosts("10.0.0.1").Groups("foo").
+				ResourcePatternType(kadm.ACLPatternLiteral).Operations(kmsg.ACLOperationDescribe),
+		},
+		{
+			name: "prefixed pattern and all operations are preserved",
+			acl: kadm.DescribedACL{
+				Principal:  "User:alice",
+				Host:       "*",
+				Type:       kmsg.ACLResourceTypeGroup,
+				Name:       "foo-",
+				Pattern:    kadm.ACLPatternPrefixed,
+				Operation:  kmsg.ACLOperationAll,
+				Permission: kmsg.ACLPermissionTypeAllow,
+			},
+			expected: kadm.NewACLs().Allow("User:bob").AllowHosts("*").Groups("foo-").
+				ResourcePatternType(kadm.ACLPatternPrefixed).Operations(kmsg.ACLOperationAll),
+		},
+		{
+			name: "unknown permission is skipped",
+			acl: kadm.DescribedACL{
+				Principal:  "User:alice",
+				Host:       "*",
+				Type:       kmsg.ACLResourceTypeGroup,
+				Name:       "foo",
+				Pattern:    kadm.ACLPatternLiteral,
+				Operation:  kmsg.ACLOperationRead,
+				Permission: kmsg.ACLPermissionTypeUnknown,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			builder, ok := groupACLBuilder(test.acl, principals)
+			if test.expected == nil {
+				assert.False(t, ok)
+				assert.Nil(t, builder)
+				return
+			}
+			require.True(t, ok)
+			assert.NoError(t, builder.ValidateCreate())
+			assert.Equal(t, test.expected, builder)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/asyncroutine"
+)
+
+// franzLagMetrics exposes the committed offset, end offset and lag of each
+// topic partition consumed by a consumer group as gauges.
+type franzLagMetrics struct {
+	lag       *service.MetricGauge
+	committed *service.MetricGauge
+	end       *service.MetricGauge
+}
+
+func newFranzLagMetrics(m *service.Metrics, prefix string) *franzLagMetrics {
+	return &franzLagMetrics{
+		lag:       m.NewGauge(prefix+"_lag", "topic", "partition"),
+		committed: m.NewGauge(prefix+"_committed_offset", "topic", "partition"),
+		end:       m.NewGauge(prefix+"_end_offset", "topic", "partition"),
+	}
+}
+
+// update sets the gauges from the lag of a group as returned by the admin API
+// and calls onLag for every topic partition.
+func (l *franzLagMetrics) update(lags kadm.DescribedGroupLags, onLag func(topic string, partition int32, lag int64)) {
+	lags.Each(func(gl kadm.DescribedGroupLag) {
+		for _, ps := range gl.Lag {
+			for _, pl := range ps {
+				partition := strconv.Itoa(int(pl.Partition))
+
+				if pl.Commit.At >= 0 {
+					l.committed.Set(pl.Commit.At, pl.Topic, partition)
+				}
+				if pl.End.Err == nil {
+					l.end.Set(pl.End.Offset, pl.Topic, partition)
+				}
+
+				lag := pl.Lag
+				if lag < 0 {
+					lag = 0
+				}
+				l.lag.Set(lag, pl.Topic, partition)
+
+				if onLag != nil {
+					onLag(pl.Topic, pl.Partition, lag)
+				}
+			}
+		}
+	})
+}
+
+// newUpdater returns a periodic routine which refreshes the gauges from the
+// lag of the given consumer group.
+func (l *franzLagMetrics) newUpdater(client *kgo.Client, group string, period time.Duration, log *service.Logger, onLag func(topic string, partition int32, lag int64)) *asyncroutine.Periodic {
+	adminClient := kadm.NewClient(client)
+	return asyncroutine.NewPeriodicWithContext(period, func(ctx context.Context) {
+		ctx, done := context.WithTimeout(ctx, period)
+		defer done()
+
+		lags, err := adminClient.Lag(ctx, group)
+		if err != nil {
+			log.Debugf("Failed to fetch group lags: %s", err)
+		}
+		l.update(lags, onLag)
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kadm"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestFranzLagMetricsUpdate(t *testing.T) {
+	lags := kadm.DescribedGroupLags{
+		"foogroup": {
+			Group: "foogroup",
+			Lag: kadm.GroupLag{
+				"foo": {
+					0: {Topic: "foo", Partition: 0, Commit: kadm.Offset{At: 5}, End: kadm.ListedOffset{Offset: 10}, Lag: 5},
+					1: {Topic: "foo", Partition: 1, Commit: kadm.Offset{At: -1}, End: kadm.ListedOffset{Offset: 3}, Lag: -1},
+				},
+				"bar": {
+					2: {Topic: "bar", Partition: 2, Commit: kadm.Offset{At: 7}, End: kadm.ListedOffset{Offset: 7}, Lag: 0},
+				},
+			},
+		},
+	}
+
+	type topicPartition struct {
+		topic     string
+		partition int32
+	}
+	seen := map[topicPartition]int64{}
+
+	newFranzLagMetrics(service.MockResources().Metrics(), "kafka").update(lags, func(topic string, partition int32, lag int64) {
+		seen[topicPartition{topic, partition}] = lag
+	})
+
+	assert.Equal(t, map[topicPartition]int64{
+		{"foo", 0}: 5,
+		{"foo", 1}: 0,
+		{"bar", 2}: 0,
+	}, seen)
+}
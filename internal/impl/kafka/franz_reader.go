@@ -15,6 +15,8 @@
 package kafka
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -65,29 +67,41 @@ const (
 // FranzConsumerFields returns a slice of fields specifically for customising
 // consumer behaviour via the franz-go library.
 func FranzConsumerFields() []*service.ConfigField {
-	return []*service.ConfigField{
-		service.NewStringListField(kfrFieldTopics).
-			Description(`
+	return franzConsumerFields(franzTopicsField())
+}
+
+func franzTopicsField() *service.ConfigField {
+	return service.NewStringListField(kfrFieldTopics).
+		Description(`
 A list of topics to consume from. Multiple comma separated topics can be listed in a single element. When a ` + "`consumer_group`" + ` is specified partitions are automatically distributed across consumers of a topic, otherwise all partitions are consumed.
 
 Alternatively, it's possible to specify explicit partitions to consume from with a colon after the topic name, e.g. ` + "`foo:0`" + ` would consume the partition 0 of the topic foo. This syntax supports ranges, e.g. ` + "`foo:0-10`" + ` would consume partitions 0 through to 10 inclusive.
 
 Finally, it's also possible to specify an explicit offset to consume from by adding another colon after the partition, e.g. ` + "`foo:0:10`" + ` would consume the partition 0 of the topic foo starting from the offset 10. If the offset is not present (or remains unspecified) then the field ` + "`start_from_oldest`" + ` determines which offset to start from.`).
-			Example([]string{"foo", "bar"}).
-			Example([]string{"things.*"}).
-			Example([]string{"foo,bar"}).
-			Example([]string{"foo:0", "bar:1", "bar:3"}).
-			Example([]string{"foo:0,bar:1,bar:3"}).
-			Example([]string{"foo:0-5"}),
+		Example([]string{"foo", "bar"}).
+		Example([]string{"things.*"}).
+		Example([]string{"foo,bar"}).
+		Example([]string{"foo:0", "bar:1", "bar:3"}).
+		Example([]string{"foo:0,bar:1,bar:3"}).
+		Example([]string{"foo:0-5"})
+}
+
+// franzConsumerFields returns the consumer fields with the given topics field,
+// which allows inputs to customise it.
+func franzConsumerFields(topics *service.ConfigField) []*service.ConfigField {
+	return []*service.ConfigField{
+		topics,
 		service.NewBoolField(kfrFieldRegexpTopics).
 			Description("Whether listed topics should be interpreted as regular expression patterns for matching multiple topics. When topics are specified with explicit partitions this field must remain set to `false`.").
 			Default(false),
 		service.NewStringField(kfrFieldRackID).
-			Description("A rack specifies where the client is physically located and changes fetch requests to consume from the closest replica as opposed to the leader replica.").
+			Description("A rack specifies where the client is physically located and changes fetch requests to consume from the closest replica as opposed to the leader replica. When set to `auto` the rack is detected at startup from the instance metadata of the cloud provider, which is the availability zone ID on AWS, the zone on GCP and the availability zone, or fault domain when not deployed to a zone, on Azure.").
+			Example("auto").
 			Default("").
 			Advanced(),
 		service.NewStringField(kfrFieldInstanceID).
-			Description("When using a consumer group, an instance ID specifies the groups static membership, which can prevent rebalances during reconnects. When using a instance ID the client does NOT leave the group when closing. To actually leave the group one must use an external admin command to leave the group on behalf of this instance ID. This ID must be unique per consumer within the group.").
+			Description("When using a consumer group, an instance ID specifies the groups static membership, which can prevent rebalances during reconnects. When using a instance ID the client does NOT leave the group when closing. To actually leave the group one must use an external admin command to leave the group on behalf of this instance ID. This ID must be unique per consumer within the group. This is the equivalent to the Java group.instance.id setting.").
+			Example("${HOSTNAME}").
 			Default("").
 			Advanced(),
 		service.NewDurationField(kfrFieldRebalanceTimeout).
@@ -152,6 +166,9 @@ func FranzConsumerDetailsFromConfig(conf *service.ParsedConfig) (*FranzConsumerD
 	if d.RackID, err = conf.FieldString(kfrFieldRackID); err != nil {
 		return nil, err
 	}
+	if d.RackID, err = resolveRackID(d.RackID); err != nil {
+		return nil, err
+	}
 	if d.InstanceID, err = conf.FieldString(kfrFieldInstanceID); err != nil {
 		return nil, err
 	}
@@ -260,8 +277,65 @@ func FranzConsumerOptsFromConfig(conf *service.ParsedConfig) ([]kgo.Opt, error)
 	return details.FranzOpts(), nil
 }
 
+const kfrFieldTransactionalCommits = "transactional_commits"
+
+// FranzTransactionalCommitsField returns a field for opting a consumer into
+// sharing its client with transactional outputs.
+func FranzTransactionalCommitsField() *service.ConfigField {
+	return service.NewBoolField(kfrFieldTransactionalCommits).
+		Description("Share the client of this input under its label so that a `kafka_franz` or `redpanda` output which references the label within its `transactional_consumer` field commits the consumer group offsets of the records it writes within its transactions. This requires the input to have a label and a consumer group.").
+		Default(false).
+		Advanced()
+}
+
+// franzSharedClientLabelFromConfig returns the label under which the client
+// of a consumer is shared with transactional outputs, which is empty unless
+// transactional commits are enabled.
+func franzSharedClientLabelFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (string, error) {
+	enabled, err := conf.FieldBool(kfrFieldTransactionalCommits)
+	if err != nil || !enabled {
+		return "", err
+	}
+	if mgr.Label() == "" {
+		return "", errors.New("a label must be set on this input when transactional_commits is enabled")
+	}
+	return mgr.Label(), nil
+}
+
+type franzRecordOffsetKey struct{}
+
+// franzRecordOffset identifies the record that a message was consumed from by
+// an input sharing its client under a label.
+type franzRecordOffset struct {
+	label     string
+	topic     string
+	partition int32
+	offset    int64
+}
+
+// withFranzRecordOffset returns the message with the offset of the record it
+// was consumed from attached to its context. Unlike the metadata of a message
+// the context can't be modified by processors, and so transactional outputs can
+// rely on it when committing the offsets of the messages they write.
+func withFranzRecordOffset(msg *service.Message, label string, r *kgo.Record) *service.Message {
+	return msg.WithContext(context.WithValue(msg.Context(), franzRecordOffsetKey{}, franzRecordOffset{
+		label:     label,
+		topic:     r.Topic,
+		partition: r.Partition,
+		offset:    r.Offset,
+	}))
+}
+
+// franzRecordOffsetFromMessage returns the offset of the record a message was
+// consumed from, if it was added by an input sharing its client.
+func franzRecordOffsetFromMessage(msg *service.Message) (franzRecordOffset, bool) {
+	o, ok := msg.Context().Value(franzRecordOffsetKey{}).(franzRecordOffset)
+	return o, ok
+}
+
 // FranzRecordToMessageV0 converts a record into a service.Message, adding
-// metadata and other relevant information.
+// metadata and other relevant information. A W3C trace context found within
+// the record headers becomes the parent of the spans of the message.
 func FranzRecordToMessageV0(record *kgo.Record, multiHeader bool) *service.Message {
 	msg := service.NewMessage(record.Value)
 	msg.MetaSetMut("kafka_key", string(record.Key))
@@ -288,11 +362,12 @@ func FranzRecordToMessageV0(record *kgo.Record, multiHeader bool) *service.Messa
 		}
 	}
 
-	return msg
+	return withExtractedTraceContext(msg, franzHeaderCarrier{headers: &record.Headers})
 }
 
 // FranzRecordToMessageV1 converts a record into a service.Message, adding
-// metadata and other relevant information.
+// metadata and other relevant information. A W3C trace context found within
+// the record headers becomes the parent of the spans of the message.
 func FranzRecordToMessageV1(record *kgo.Record) *service.Message {
 	msg := service.NewMessage(record.Value)
 	msg.MetaSetMut("kafka_key", record.Key)
@@ -317,5 +392,5 @@ func FranzRecordToMessageV1(record *kgo.Record) *service.Message {
 		}
 	}
 
-	return msg
+	return withExtractedTraceContext(msg, franzHeaderCarrier{headers: &record.Headers})
 }
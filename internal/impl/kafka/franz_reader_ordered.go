@@ -18,7 +18,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -26,7 +25,6 @@ import (
 	"github.com/Jeffail/checkpoint"
 	"github.com/Jeffail/shutdown"
 	"github.com/cenkalti/backoff/v4"
-	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
@@ -58,7 +56,7 @@ func FranzReaderOrderedConfigFields() []*service.ConfigField {
 			Default("1MB").
 			Advanced(),
 		service.NewDurationField(kroFieldTopicLagRefreshPeriod).
-			Description("The period of time between each topic lag refresh cycle.").
+			Description("The period of time between each refresh of the consumer group lag, committed offset and end offset of each consumed topic partition.").
 			Default("5s").
 			Advanced(),
 	}
@@ -72,7 +70,7 @@ type FranzReaderOrdered struct {
 
 	partState     *partitionState
 	lagUpdater    *asyncroutine.Periodic
-	topicLagGauge *service.MetricGauge
+	lagMetrics    *franzLagMetrics
 	topicLagCache sync.Map
 	Client        *kgo.Client
 
@@ -82,6 +80,10 @@ type FranzReaderOrdered struct {
 	cacheLimit            uint64
 	readBackOff           backoff.BackOff
 
+	// When set, the client is shared under this label while connected so that
+	// outputs are able to commit offsets within their transactions.
+	sharedClientLabel string
+
 	res     *service.Resources
 	log     *service.Logger
 	shutSig *shutdown.Signaller
@@ -95,12 +97,12 @@ func NewFranzReaderOrderedFromConfig(conf *service.ParsedConfig, res *service.Re
 	readBackOff.MaxElapsedTime = 0
 
 	f := FranzReaderOrdered{
-		readBackOff:   readBackOff,
-		res:           res,
-		log:           res.Logger(),
-		shutSig:       shutdown.NewSignaller(),
-		clientOpts:    optsFn,
-		topicLagGauge: res.Metrics().NewGauge("redpanda_lag", "topic", "partition"),
+		readBackOff: readBackOff,
+		res:         res,
+		log:         res.Logger(),
+		shutSig:     shutdown.NewSignaller(),
+		clientOpts:  optsFn,
+		lagMetrics:  newFranzLagMetrics(res.Metrics(), "redpanda"),
 	}
 
 	f.consumerGroup, _ = conf.FieldString(kroFieldConsumerGroup)
@@ -140,6 +142,9 @@ func (f *FranzReaderOrdered) recordsToBatch(records []*kgo.Record) *batchWithRec
 
 		msg := FranzRecordToMessageV1(r)
 		msg.MetaSetMut("kafka_lag", lag)
+		if f.sharedClientLabel != "" {
+			msg = withFranzRecordOffset(msg, f.sharedClientLabel, r)
+		}
 
 		batch = append(batch, msg)
 
@@ -416,37 +421,25 @@ func (f *FranzReaderOrdered) Connect(ctx context.Context) error {
 		return fmt.Errorf("failed to connect to cluster: %s", err)
 	}
 
+	if f.sharedClientLabel != "" {
+		if err := FranzSharedClientSet(f.sharedClientLabel, &FranzSharedClientInfo{Client: f.Client}, f.res); err != nil {
+			f.log.Warnf("Failed to store client connection for sharing: %s", err)
+		}
+	}
+
 	if f.lagUpdater != nil {
 		f.lagUpdater.Stop()
 	}
-	adminClient := kadm.NewClient(f.Client)
-	f.lagUpdater = asyncroutine.NewPeriodicWithContext(f.topicLagRefreshPeriod, func(ctx context.Context) {
-		ctx, done := context.WithTimeout(ctx, f.topicLagRefreshPeriod)
-		defer done()
-
-		lags, err := adminClient.Lag(ctx, f.consumerGroup)
-		if err != nil {
-			f.log.Debugf("Failed to fetch group lags: %s", err)
-		}
-
-		lags.Each(func(gl kadm.DescribedGroupLag) {
-			for _, gl := range gl.Lag {
-				for _, pl := range gl {
-					lag := pl.Lag
-					if lag < 0 {
-						lag = 0
-					}
-
-					f.topicLagGauge.Set(lag, pl.Topic, strconv.Itoa(int(pl.Partition)))
-					f.topicLagCache.Store(fmt.Sprintf("%s_%d", pl.Topic, pl.Partition), lag)
-				}
-			}
-		})
+	f.lagUpdater = f.lagMetrics.newUpdater(f.Client, f.consumerGroup, f.topicLagRefreshPeriod, f.log, func(topic string, partition int32, lag int64) {
+		f.topicLagCache.Store(fmt.Sprintf("%s_%d", topic, partition), lag)
 	})
 	f.lagUpdater.Start()
 
 	go func() {
 		defer func() {
+			if f.sharedClientLabel != "" {
+				_, _ = FranzSharedClientPop(f.sharedClientLabel, f.res)
+			}
 			f.Client.Close()
 			if f.shutSig.IsSoftStopSignalled() {
 				f.shutSig.TriggerHasStopped()
@@ -29,6 +29,8 @@ import (
 	"github.com/Jeffail/shutdown"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
+
+	"github.com/redpanda-data/connect/v4/internal/asyncroutine"
 )
 
 const (
@@ -37,6 +39,8 @@ const (
 	kruFieldCommitPeriod    = "commit_period"
 	kruFieldMultiHeader     = "multi_header"
 	kruFieldBatching        = "batching"
+
+	kruFieldTopicLagRefreshPeriod = "topic_lag_refresh_period"
 )
 
 // FranzReaderUnorderedConfigFields returns config fields for customising the
@@ -61,6 +65,11 @@ func FranzReaderUnorderedConfigFields() []*service.ConfigField {
 			Description("Decode headers into lists to allow handling of multiple values with the same key").
 			Default(false).
 			Advanced(),
+		service.NewDurationField(kruFieldTopicLagRefreshPeriod).
+			Description("The period of time between each refresh of the consumer group lag, committed offset and end offset of each consumed topic partition. Only applies when a consumer group is specified.").
+			Default("5s").
+			Version("4.48.0").
+			Advanced(),
 		service.NewBatchPolicyField(kruFieldBatching).
 			Description("Allows you to configure a xref:configuration:batching.adoc[batching policy] that applies to individual topic partitions in order to batch messages together before flushing them for processing. Batching can be beneficial for performance as well as useful for windowed processing, and doing so this way preserves the ordering of topic partitions.").
 			Advanced(),
@@ -87,6 +96,18 @@ type FranzReaderUnordered struct {
 	multiHeader     bool
 	batchPolicy     service.BatchPolicy
 
+	lagMetrics            *franzLagMetrics
+	topicLagRefreshPeriod time.Duration
+
+	// When set, the client is shared under this label while connected so that
+	// outputs are able to commit offsets within their transactions.
+	sharedClientLabel string
+
+	// Exclusive offsets at which the consumption of explicit partitions ends,
+	// and whether the input should shut down once all of them are reached.
+	endOffsets      partitionEndOffsets
+	endOfInputAtEnd bool
+
 	batchChan atomic.Value
 	res       *service.Resources
 	log       *service.Logger
@@ -106,9 +127,10 @@ func (f *FranzReaderUnordered) storeBatchChan(c chan batchWithAckFn) {
 // FranzReaderUnordered reader from a parsed config.
 func NewFranzReaderUnorderedFromConfig(conf *service.ParsedConfig, res *service.Resources, opts ...kgo.Opt) (*FranzReaderUnordered, error) {
 	f := FranzReaderUnordered{
-		res:     res,
-		log:     res.Logger(),
-		shutSig: shutdown.NewSignaller(),
+		res:        res,
+		log:        res.Logger(),
+		shutSig:    shutdown.NewSignaller(),
+		lagMetrics: newFranzLagMetrics(res.Metrics(), "kafka"),
 	}
 	f.clientOpts = append(f.clientOpts, opts...)
 
@@ -131,6 +153,10 @@ func NewFranzReaderUnorderedFromConfig(conf *service.ParsedConfig, res *service.
 		return nil, err
 	}
 
+	if f.topicLagRefreshPeriod, err = conf.FieldDuration(kruFieldTopicLagRefreshPeriod); err != nil {
+		return nil, err
+	}
+
 	return &f, nil
 }
 
@@ -141,6 +167,9 @@ type msgWithRecord struct {
 
 func (f *FranzReaderUnordered) recordToMessage(record *kgo.Record) *msgWithRecord {
 	msg := FranzRecordToMessageV0(record, f.multiHeader)
+	if f.sharedClientLabel != "" {
+		msg = withFranzRecordOffset(msg, f.sharedClientLabel, record)
+	}
 
 	// The record lives on for checkpointing, but we don't need the contents
 	// going forward so discard these. This looked fine to me but could
@@ -319,6 +348,29 @@ func (p *partitionTracker) add(ctx context.Context, m *msgWithRecord, limit int)
 	return
 }
 
+// flush sends any messages pending within the batcher downstream.
+func (p *partitionTracker) flush(ctx context.Context) error {
+	if p.batcher == nil {
+		return nil
+	}
+
+	var sendBatch service.MessageBatch
+	var sendRecord *kgo.Record
+	func() {
+		p.batcherLock.Lock()
+		defer p.batcherLock.Unlock()
+
+		sendBatch, _ = p.batcher.Flush(ctx)
+		sendRecord = p.topBatchRecord
+		p.topBatchRecord = nil
+	}()
+
+	if len(sendBatch) == 0 {
+		return nil
+	}
+	return p.sendBatch(ctx, sendBatch, sendRecord)
+}
+
 func (p *partitionTracker) pauseFetch(limit int) (pauseFetch bool) {
 	p.checkpointerLock.Lock()
 	pauseFetch = p.checkpointer.Pending() >= int64(limit)
@@ -401,6 +453,22 @@ func (c *checkpointTracker) addRecord(ctx context.Context, m *msgWithRecord, lim
 	return partTracker.add(ctx, m, limit)
 }
 
+func (c *checkpointTracker) flush(ctx context.Context, topic string, partition int32) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	topicTracker := c.topics[topic]
+	if topicTracker == nil {
+		return nil
+	}
+	partTracker := topicTracker[partition]
+	if partTracker == nil {
+		return nil
+	}
+
+	return partTracker.flush(ctx)
+}
+
 func (c *checkpointTracker) pauseFetch(topic string, partition int32, limit int) bool {
 	c.mut.Lock()
 	defer c.mut.Unlock()
@@ -440,6 +508,43 @@ func (c *checkpointTracker) removeTopicPartitions(ctx context.Context, m map[str
 
 //------------------------------------------------------------------------------
 
+// partitionEndOffsets maps topic partitions to the exclusive offset at which
+// their consumption ends.
+type partitionEndOffsets map[string]map[int32]int64
+
+func (p partitionEndOffsets) get(topic string, partition int32) (int64, bool) {
+	offset, exists := p[topic][partition]
+	return offset, exists
+}
+
+func (p partitionEndOffsets) remove(topic string, partition int32) {
+	delete(p[topic], partition)
+	if len(p[topic]) == 0 {
+		delete(p, topic)
+	}
+}
+
+// completed returns whether a topic partition has an end offset which was
+// already reached, given the end offsets which are still remaining.
+func (p partitionEndOffsets) completed(topic string, partition int32, remaining partitionEndOffsets) bool {
+	if _, bounded := p.get(topic, partition); !bounded {
+		return false
+	}
+	_, pending := remaining.get(topic, partition)
+	return !pending
+}
+
+func (p partitionEndOffsets) clone() partitionEndOffsets {
+	c := make(partitionEndOffsets, len(p))
+	for topic, partitions := range p {
+		c[topic] = make(map[int32]int64, len(partitions))
+		for partition, offset := range partitions {
+			c[topic][partition] = offset
+		}
+	}
+	return c
+}
+
 // Connect to the kafka seed brokers.
 func (f *FranzReaderUnordered) Connect(ctx context.Context) error {
 	if f.getBatchChan() != nil {
@@ -464,6 +569,7 @@ func (f *FranzReaderUnordered) Connect(ctx context.Context) error {
 		}
 	}
 	checkpoints := newCheckpointTracker(f.res, batchChan, commitFn, f.batchPolicy)
+	remainingEndOffsets := f.endOffsets.clone()
 
 	var clientOpts []kgo.Opt
 	clientOpts = append(clientOpts, f.clientOpts...)
@@ -497,8 +603,26 @@ func (f *FranzReaderUnordered) Connect(ctx context.Context) error {
 		return fmt.Errorf("failed to connect to cluster: %s", err)
 	}
 
+	if f.sharedClientLabel != "" {
+		if err := FranzSharedClientSet(f.sharedClientLabel, &FranzSharedClientInfo{Client: cl}, f.res); err != nil {
+			f.log.Warnf("Failed to store client connection for sharing: %s", err)
+		}
+	}
+
+	var lagUpdater *asyncroutine.Periodic
+	if f.consumerGroup != "" {
+		lagUpdater = f.lagMetrics.newUpdater(cl, f.consumerGroup, f.topicLagRefreshPeriod, f.log, nil)
+		lagUpdater.Start()
+	}
+
 	go func() {
 		defer func() {
+			if lagUpdater != nil {
+				lagUpdater.Stop()
+			}
+			if f.sharedClientLabel != "" {
+				_, _ = FranzSharedClientPop(f.sharedClientLabel, f.res)
+			}
 			cl.Close()
 			checkpoints.close()
 			f.storeBatchChan(nil)
@@ -553,19 +677,55 @@ func (f *FranzReaderUnordered) Connect(ctx context.Context) error {
 			}
 
 			pauseTopicPartitions := map[string][]int32{}
+			completedTopicPartitions := map[string][]int32{}
 			iter := fetches.RecordIter()
 			for !iter.Done() {
 				record := iter.Next()
+
+				endOffset, bounded := remainingEndOffsets.get(record.Topic, record.Partition)
+				if bounded && record.Offset >= endOffset-1 {
+					// Offsets aren't guaranteed to be contiguous, therefore
+					// the first record at or beyond the end offset also
+					// completes the partition.
+					remainingEndOffsets.remove(record.Topic, record.Partition)
+					completedTopicPartitions[record.Topic] = append(completedTopicPartitions[record.Topic], record.Partition)
+				} else if !bounded && f.endOffsets.completed(record.Topic, record.Partition, remainingEndOffsets) {
+					continue
+				}
+				if bounded && record.Offset >= endOffset {
+					continue
+				}
+
 				if checkpoints.addRecord(closeCtx, f.recordToMessage(record), f.checkpointLimit) {
 					pauseTopicPartitions[record.Topic] = append(pauseTopicPartitions[record.Topic], record.Partition)
 				}
 			}
 
+			if len(completedTopicPartitions) > 0 {
+				cl.PauseFetchPartitions(completedTopicPartitions)
+				for topic, partitions := range completedTopicPartitions {
+					for _, partition := range partitions {
+						if err := checkpoints.flush(closeCtx, topic, partition); err != nil {
+							return
+						}
+						f.log.Debugf("Reached the end offset of topic %v, partition %v", topic, partition)
+					}
+				}
+				if f.endOfInputAtEnd && len(remainingEndOffsets) == 0 {
+					f.log.Info("Reached the end offsets of all partitions, shutting down input")
+					f.shutSig.TriggerSoftStop()
+					return
+				}
+			}
+
 			// Walk all the disabled topic partitions and check whether any of
 			// them can be resumed.
 			resumeTopicPartitions := map[string][]int32{}
 			for pausedTopic, pausedPartitions := range cl.PauseFetchPartitions(pauseTopicPartitions) {
 				for _, pausedPartition := range pausedPartitions {
+					if f.endOffsets.completed(pausedTopic, pausedPartition, remainingEndOffsets) {
+						continue
+					}
 					if !checkpoints.pauseFetch(pausedTopic, pausedPartition, f.checkpointLimit) {
 						resumeTopicPartitions[pausedTopic] = append(resumeTopicPartitions[pausedTopic], pausedPartition)
 					}
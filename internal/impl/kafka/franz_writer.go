@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"math"
 	"slices"
 	"strconv"
@@ -25,8 +26,11 @@ import (
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
 
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
 
 	"github.com/redpanda-data/connect/v4/internal/dispatch"
@@ -71,10 +75,16 @@ func FranzProducerFields() []*service.ConfigField {
 	return slices.Concat(
 		[]*service.ConfigField{
 			service.NewStringAnnotatedEnumField(kfwFieldPartitioner, map[string]string{
-				"murmur2_hash": "Kafka's default hash algorithm that uses a 32-bit murmur2 hash of the key to compute which partition the record will be on.",
-				"round_robin":  "Round-robin's messages through all available partitions. This algorithm has lower throughput and causes higher CPU load on brokers, but can be useful if you want to ensure an even distribution of records to partitions.",
-				"least_backup": "Chooses the least backed up partition (the partition with the fewest amount of buffered records). Partitions are selected per batch.",
-				"manual":       "Manually select a partition for each message, requires the field `partition` to be specified.",
+				"murmur2_hash":   "Kafka's default hash algorithm that uses a 32-bit murmur2 hash of the key to compute which partition the record will be on.",
+				"fnv1a_hash":     "Uses a 32-bit FNV-1a hash of the key to compute which partition the record will be on, which matches the default partitioner of the Sarama client and the `kafka` output.",
+				"crc32_hash":     "Uses a CRC-32 checksum of the key to compute which partition the record will be on, which matches the `consistent` partitioner of librdkafka.",
+				"jump_hash":      "Uses a jump consistent hash of the murmur2 hash of the key to compute which partition the record will be on, which minimises the number of keys that move to a different partition when the number of partitions changes.",
+				"round_robin":    "Round-robin's messages through all available partitions. This algorithm has lower throughput and causes higher CPU load on brokers, but can be useful if you want to ensure an even distribution of records to partitions.",
+				"least_backup":   "Chooses the least backed up partition (the partition with the fewest amount of buffered records). Partitions are selected per batch.",
+				"sticky":         "Chooses a random partition for each producer batch regardless of the key, which results in larger batches than `round_robin` whilst distributing records evenly over time.",
+				"uniform_bytes":  "Kafka's uniform sticky partitioner (KIP-794), which switches to a different partition once 64KiB have been produced to the current one, preferring partitions on brokers which are less backed up. Records with a key are hashed with murmur2.",
+				"manual":         "Manually select a partition for each message, requires the field `partition` to be specified.",
+				"manual_mapping": "Manually select a partition for each message with a Bloblang mapping, requires the field `partition_mapping` to be specified.",
 			}).
 				Description("Override the default murmur2 hashing partitioner.").
 				Advanced().Optional(),
@@ -177,11 +187,25 @@ func FranzProducerOptsFromConfig(conf *service.ParsedConfig) ([]kgo.Opt, error)
 		switch partStr {
 		case "murmur2_hash":
 			partitioner = kgo.StickyKeyPartitioner(nil)
+		case "fnv1a_hash":
+			partitioner = kgo.StickyKeyPartitioner(func(key []byte, n int) int {
+				return int(fnv1aPartition(key, int32(n)))
+			})
+		case "crc32_hash":
+			partitioner = kgo.StickyKeyPartitioner(kgo.SaramaHasher(crc32.ChecksumIEEE))
+		case "jump_hash":
+			partitioner = kgo.StickyKeyPartitioner(func(key []byte, n int) int {
+				return int(jumpPartition(key, int32(n)))
+			})
 		case "round_robin":
 			partitioner = kgo.RoundRobinPartitioner()
 		case "least_backup":
 			partitioner = kgo.LeastBackupPartitioner()
-		case "manual":
+		case "sticky":
+			partitioner = kgo.StickyPartitioner()
+		case "uniform_bytes":
+			partitioner = kgo.UniformBytesPartitioner(64*1024, true, true, nil)
+		case "manual", "manual_mapping":
 			partitioner = kgo.ManualPartitioner()
 		default:
 			return nil, fmt.Errorf("unknown partitioner: %v", partStr)
@@ -204,10 +228,78 @@ func FranzProducerOptsFromConfig(conf *service.ParsedConfig) ([]kgo.Opt, error)
 
 //------------------------------------------------------------------------------
 
+const (
+	// Transaction fields
+	kfwFieldTransactional         = "transactional"
+	kfwFieldTransactionalID       = "transactional_id"
+	kfwFieldTransactionalConsumer = "transactional_consumer"
+)
+
+// FranzWriterTransactionFields returns a slice of fields specifically for
+// enabling transactional writes via the franz-go library.
+func FranzWriterTransactionFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewBoolField(kfwFieldTransactional).
+			Description("Write each message batch within a Kafka transaction, which is only committed once all messages of the batch have been acknowledged by the brokers. When enabled, only a single batch is written at any given time regardless of the `max_in_flight` setting.").
+			Default(false).
+			Advanced(),
+		service.NewStringField(kfwFieldTransactionalID).
+			Description("The transactional ID of the producer, which must be unique for each running instance of this output and stable across restarts in order for the brokers to fence zombie producers. This field is required when `transactional` is enabled.").
+			Example("${HOSTNAME}-orders").
+			Default("").
+			Advanced(),
+		service.NewStringField(kfwFieldTransactionalConsumer).
+			Description("The label of a `kafka_franz` or `redpanda` input with a consumer group, whose offsets for the messages of each batch are committed within the same transaction as the batch itself. This enables exactly-once delivery between topics of the same cluster, provided that the input is configured with the same label, has `transactional_commits` enabled and that consumers of the output topics only read committed records. The offsets are committed through this output, and therefore the input must connect to the same `seed_brokers`. Offsets are tracked independently of the metadata of messages, and so aren't affected by processors modifying fields such as `kafka_partition`, but messages that aren't derived from a message consumed by the input don't commit any offsets.").
+			Example("kafka_in").
+			Default("").
+			Advanced(),
+	}
+}
+
+// FranzWriterTransactionOptsFromConfig returns a slice of franz-go client opts
+// for enabling transactional writes from a parsed config.
+func FranzWriterTransactionOptsFromConfig(conf *service.ParsedConfig) ([]kgo.Opt, error) {
+	transactional, err := conf.FieldBool(kfwFieldTransactional)
+	if err != nil || !transactional {
+		return nil, err
+	}
+
+	txnID, err := conf.FieldString(kfwFieldTransactionalID)
+	if err != nil {
+		return nil, err
+	}
+	if txnID == "" {
+		return nil, errors.New("a transactional_id must be specified when transactional is enabled")
+	}
+
+	return []kgo.Opt{kgo.TransactionalID(txnID)}, nil
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	kfwFieldKeyLanes = "key_lanes"
+)
+
+// FranzWriterKeyLanesFields returns a slice of fields specifically for
+// preserving the order of records with the same key across parallel writes.
+func FranzWriterKeyLanesFields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewIntField(kfwFieldKeyLanes).
+			Description("When greater than zero, the records of each batch are hashed by topic and key into this number of lanes, and batches which share a lane are written one at a time in the order in which they were received by the output. This preserves the ordering of records with the same key when `max_in_flight` is greater than one, whilst batches with unrelated keys are still written in parallel. Records without a key are not ordered. Ordering is not preserved when a failed batch is retried after subsequent batches have been written.").
+			Default(0).
+			Version("4.48.0").
+			Advanced(),
+	}
+}
+
+//------------------------------------------------------------------------------
+
 const (
 	kfwFieldTopic       = "topic"
 	kfwFieldKey         = "key"
 	kfwFieldPartition   = "partition"
+	kfwFieldPartMapping = "partition_mapping"
 	kfwFieldMetadata    = "metadata"
 	kfwFieldTimestamp   = "timestamp"
 	kfwFieldTimestampMs = "timestamp_ms"
@@ -225,8 +317,15 @@ func FranzWriterConfigFields() []*service.ConfigField {
 			Description("An optional explicit partition to set for each message. This field is only relevant when the `partitioner` is set to `manual`. The provided interpolation string must be a valid integer.").
 			Example(`${! meta("partition") }`).
 			Optional(),
+		service.NewBloblangField(kfwFieldPartMapping).
+			Description("A Bloblang mapping which is executed for each message and must return the partition to write the message to as an integer. This field is only relevant when the `partitioner` is set to `manual_mapping`.").
+			Example(`root = match @tenant { "acme" => 0, "globex" => 1, _ => 2 }`).
+			Example(`root = this.tenant_id % 12`).
+			Version("4.48.0").
+			Optional().
+			Advanced(),
 		service.NewMetadataFilterField(kfwFieldMetadata).
-			Description("Determine which (if any) metadata values should be added to messages as headers.").
+			Description("Determine which (if any) metadata values should be added to messages as headers. String and byte array values are written verbatim, arrays are written as one header per element and all other values are written as their string representation.").
 			Optional(),
 		service.NewInterpolatedStringField(kfwFieldTimestamp).
 			Description("An optional timestamp to set for each message. When left empty, the current timestamp is used.").
@@ -241,6 +340,7 @@ func FranzWriterConfigFields() []*service.ConfigField {
 			Example(`${! metadata("kafka_timestamp_ms") }`).
 			Optional().
 			Advanced(),
+		injectTraceContextField(),
 	}
 }
 
@@ -249,7 +349,12 @@ func FranzWriterConfigLints() string {
 	return `root = match {
   this.partitioner == "manual" && this.partition.or("") == "" => "a partition must be specified when the partitioner is set to manual"
   this.partitioner != "manual" && this.partition.or("") != "" => "a partition cannot be specified unless the partitioner is set to manual"
+  this.partitioner == "manual_mapping" && this.partition_mapping.or("") == "" => "a partition_mapping must be specified when the partitioner is set to manual_mapping"
+  this.partitioner != "manual_mapping" && this.partition_mapping.or("") != "" => "a partition_mapping cannot be specified unless the partitioner is set to manual_mapping"
   this.timestamp.or("") != "" && this.timestamp_ms.or("") != "" => "both timestamp and timestamp_ms cannot be specified simultaneously"
+  this.transactional.or(false) && this.transactional_id.or("") == "" => "a transactional_id must be specified when transactional is enabled"
+  this.transactional.or(false) && !this.idempotent_write.or(true) => "idempotent_write cannot be disabled when transactional is enabled"
+  !this.transactional.or(false) && this.transactional_consumer.or("") != "" => "a transactional_consumer can only be specified when transactional is enabled"
 }`
 }
 
@@ -257,6 +362,9 @@ type franzWriterHooks struct {
 	accessClientFn func(context.Context, FranzSharedClientUseFn) error
 	yieldClientFn  func(context.Context) error
 	writeHookFn    func(ctx context.Context, client *kgo.Client, records []*kgo.Record) error
+
+	transactionalConsumer   string
+	transactionalConsumerFn func(context.Context, FranzSharedClientUseFn) error
 }
 
 // NewFranzWriterHooks creates a new franzWriterHooks instance with a hook function that's executed to fetch the client.
@@ -276,15 +384,45 @@ func (h franzWriterHooks) WithWriteHookFn(fn func(ctx context.Context, client *k
 	return h
 }
 
+// WithTransactionalConsumerFn adds a hook function that's executed in order to
+// access the consumer client whose group offsets should be committed within the
+// transaction of each message batch, where only the offsets of messages
+// consumed by the input with the given label are committed.
+func (h franzWriterHooks) WithTransactionalConsumerFn(label string, fn func(context.Context, FranzSharedClientUseFn) error) franzWriterHooks {
+	h.transactionalConsumer = label
+	h.transactionalConsumerFn = fn
+	return h
+}
+
+// WithTransactionalConsumerFromConfig adds a hook function for accessing the
+// shared client of the transactional consumer specified in a parsed config, if
+// any.
+func (h franzWriterHooks) WithTransactionalConsumerFromConfig(conf *service.ParsedConfig, res *service.Resources) (franzWriterHooks, error) {
+	label, err := conf.FieldString(kfwFieldTransactionalConsumer)
+	if err != nil || label == "" {
+		return h, err
+	}
+	return h.WithTransactionalConsumerFn(label, func(_ context.Context, fn FranzSharedClientUseFn) error {
+		if err := FranzSharedClientUse(label, res, fn); err != nil {
+			return fmt.Errorf("failed to access transactional consumer %q: %w", label, err)
+		}
+		return nil
+	}), nil
+}
+
 // FranzWriter implements a Kafka writer using the franz-go library.
 type FranzWriter struct {
 	Topic         *service.InterpolatedString
 	Key           *service.InterpolatedString
 	Partition     *service.InterpolatedString
+	PartMapping   *bloblang.Executor
 	Timestamp     *service.InterpolatedString
 	IsTimestampMs bool
 	MetaFilter    *service.MetadataFilter
+	InjectTrace   bool
+	Transactional bool
 	hooks         franzWriterHooks
+	keyLanes      *keyLanes
 }
 
 // NewFranzWriterFromConfig uses a parsed config to extract customisation for writing data to a Kafka broker. A closure
@@ -311,12 +449,24 @@ func NewFranzWriterFromConfig(conf *service.ParsedConfig, hooks franzWriterHooks
 		}
 	}
 
+	if conf.Contains(kfwFieldPartMapping) {
+		if w.PartMapping, err = conf.FieldBloblang(kfwFieldPartMapping); err != nil {
+			return nil, err
+		}
+	}
+
 	if conf.Contains(kfwFieldMetadata) {
 		if w.MetaFilter, err = conf.FieldMetadataFilter(kfwFieldMetadata); err != nil {
 			return nil, err
 		}
 	}
 
+	if conf.Contains(fieldInjectTraceContext) {
+		if w.InjectTrace, err = conf.FieldBool(fieldInjectTraceContext); err != nil {
+			return nil, err
+		}
+	}
+
 	if conf.Contains(kfwFieldTimestamp) && conf.Contains(kfwFieldTimestampMs) {
 		return nil, errors.New("cannot specify both timestamp and timestamp_ms fields")
 	}
@@ -334,6 +484,25 @@ func NewFranzWriterFromConfig(conf *service.ParsedConfig, hooks franzWriterHooks
 		w.IsTimestampMs = true
 	}
 
+	if conf.Contains(kfwFieldTransactional) {
+		if w.Transactional, err = conf.FieldBool(kfwFieldTransactional); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Contains(kfwFieldKeyLanes) {
+		var lanes int
+		if lanes, err = conf.FieldInt(kfwFieldKeyLanes); err != nil {
+			return nil, err
+		}
+		if lanes < 0 {
+			return nil, errors.New("key_lanes must not be negative")
+		}
+		if lanes > 0 {
+			w.keyLanes = newKeyLanes(lanes)
+		}
+	}
+
 	return &w, nil
 }
 
@@ -351,6 +520,10 @@ func (w *FranzWriter) BatchToRecords(ctx context.Context, b service.MessageBatch
 	if w.Partition != nil {
 		partitionExecutor = b.InterpolationExecutor(w.Partition)
 	}
+	var partMappingExecutor *service.MessageBatchBloblangExecutor
+	if w.PartMapping != nil {
+		partMappingExecutor = b.BloblangExecutor(w.PartMapping)
+	}
 	var timestampExecutor *service.MessageBatchInterpolationExecutor
 	if w.Timestamp != nil {
 		timestampExecutor = b.InterpolationExecutor(w.Timestamp)
@@ -383,13 +556,39 @@ func (w *FranzWriter) BatchToRecords(ctx context.Context, b service.MessageBatch
 			}
 			record.Partition = int32(partInt)
 		}
-		_ = w.MetaFilter.Walk(msg, func(key, value string) error {
-			record.Headers = append(record.Headers, kgo.RecordHeader{
-				Key:   key,
-				Value: []byte(value),
-			})
+		if partMappingExecutor != nil {
+			partMsg, err := partMappingExecutor.Query(i)
+			if err != nil {
+				return nil, fmt.Errorf("partition mapping error: %w", err)
+			}
+			if partMsg == nil {
+				return nil, errors.New("partition mapping error: mapping deleted the root")
+			}
+			partValue, err := partMsg.AsStructured()
+			if err != nil {
+				return nil, fmt.Errorf("partition mapping result error: %w", err)
+			}
+			partInt, err := bloblang.ValueAsInt64(partValue)
+			if err != nil {
+				return nil, fmt.Errorf("partition mapping result error: %w", err)
+			}
+			if partInt < 0 || partInt > math.MaxInt32 {
+				return nil, fmt.Errorf("partition mapping result %v is not a valid partition", partInt)
+			}
+			record.Partition = int32(partInt)
+		}
+		_ = w.MetaFilter.WalkMut(msg, func(key string, value any) error {
+			for _, v := range metadataHeaderValues(value) {
+				record.Headers = append(record.Headers, kgo.RecordHeader{
+					Key:   key,
+					Value: v,
+				})
+			}
 			return nil
 		})
+		if w.InjectTrace {
+			injectTraceContext(msg, franzHeaderCarrier{headers: &record.Headers})
+		}
 		if timestampExecutor != nil {
 			if tsStr, err := timestampExecutor.TryString(i); err != nil {
 				return nil, fmt.Errorf("timestamp interpolation error: %w", err)
@@ -418,47 +617,234 @@ func (w *FranzWriter) Connect(ctx context.Context) error {
 		if err := details.Client.Ping(ctx); err != nil {
 			return fmt.Errorf("failed to connect to cluster: %s", err)
 		}
-		return nil
+		if w.hooks.transactionalConsumerFn == nil {
+			return nil
+		}
+		return w.hooks.transactionalConsumerFn(ctx, func(consumer *FranzSharedClientInfo) error {
+			return checkSameCluster(details.Client, consumer.Client)
+		})
 	})
 }
 
+// checkSameCluster returns an error unless the producer and consumer clients
+// connect to the same seed brokers. Transactional offset commits are sent to the
+// group coordinator of the producer cluster, and so would fail for consumer
+// groups of another cluster.
+func checkSameCluster(producer, consumer franzOptValuer) error {
+	producerSeeds, _ := producer.OptValue(kgo.SeedBrokers).([]string)
+	consumerSeeds, _ := consumer.OptValue(kgo.SeedBrokers).([]string)
+
+	producerSeeds = slices.Sorted(slices.Values(producerSeeds))
+	consumerSeeds = slices.Sorted(slices.Values(consumerSeeds))
+	if !slices.Equal(producerSeeds, consumerSeeds) {
+		return fmt.Errorf("the transactional consumer must connect to the same seed brokers as this output, got %v and %v", consumerSeeds, producerSeeds)
+	}
+	return nil
+}
+
 // WriteBatch attempts to write a batch of messages to the target topics.
 func (w *FranzWriter) WriteBatch(ctx context.Context, b service.MessageBatch) error {
 	if len(b) == 0 {
 		return nil
 	}
-	return w.hooks.accessClientFn(ctx, func(details *FranzSharedClientInfo) error {
-		records, err := w.BatchToRecords(ctx, b)
+
+	records, err := w.BatchToRecords(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	if w.keyLanes != nil {
+		release, err := w.keyLanes.acquire(ctx, records)
 		if err != nil {
 			return err
 		}
+		defer release()
+	}
 
+	span := startBatchSpan(ctx, "kafka_produce", b)
+	err = w.hooks.accessClientFn(ctx, func(details *FranzSharedClientInfo) error {
 		if w.hooks.writeHookFn != nil {
 			if err := w.hooks.writeHookFn(ctx, details.Client, records); err != nil {
 				return fmt.Errorf("on write hook failed: %s", err)
 			}
 		}
 
-		var (
-			wg      sync.WaitGroup
-			results = make(kgo.ProduceResults, 0, len(records))
-			promise = func(r *kgo.Record, err error) {
-				results = append(results, kgo.ProduceResult{Record: r, Err: err})
-				wg.Done()
-			}
-		)
+		if w.Transactional {
+			return w.writeTransaction(ctx, details.Client, b, records)
+		}
+		return produceRecords(ctx, details.Client, b, records)
+	})
+	endBatchSpan(span, err)
+	return err
+}
+
+// franzOptValuer is implemented by *kgo.Client.
+type franzOptValuer interface {
+	OptValue(opt any) any
+}
+
+// franzProducer is the subset of *kgo.Client used for producing records.
+type franzProducer interface {
+	Produce(ctx context.Context, r *kgo.Record, promise func(*kgo.Record, error))
+}
+
+// franzTransactionalProducer is the subset of *kgo.Client used for producing
+// records and committing offsets within transactions.
+type franzTransactionalProducer interface {
+	franzProducer
+	franzOptValuer
+	kmsg.Requestor
+	BeginTransaction() error
+	EndTransaction(ctx context.Context, commit kgo.TransactionEndTry) error
+	ProducerID(ctx context.Context) (int64, int16, error)
+}
+
+// franzGroupConsumer is the subset of *kgo.Client used for obtaining the
+// consumer group of a client.
+type franzGroupConsumer interface {
+	franzOptValuer
+	GroupMetadata() (string, int32)
+}
 
-		wg.Add(len(records))
-		for i, r := range records {
-			details.Client.Produce(ctx, r, promise)
-			dispatch.TriggerSignal(b[i].Context())
+func produceRecords(ctx context.Context, client franzProducer, b service.MessageBatch, records []*kgo.Record) error {
+	var (
+		wg      sync.WaitGroup
+		results = make(kgo.ProduceResults, 0, len(records))
+		promise = func(r *kgo.Record, err error) {
+			results = append(results, kgo.ProduceResult{Record: r, Err: err})
+			wg.Done()
 		}
-		wg.Wait()
+	)
 
-		// TODO: This is very cool and allows us to easily return granular errors,
-		// so we should honor travis by doing it.
-		return results.FirstErr()
-	})
+	wg.Add(len(records))
+	for i, r := range records {
+		client.Produce(ctx, r, promise)
+		dispatch.TriggerSignal(b[i].Context())
+	}
+	wg.Wait()
+
+	// TODO: This is very cool and allows us to easily return granular errors,
+	// so we should honor travis by doing it.
+	return results.FirstErr()
+}
+
+// writeTransaction writes a batch of records within a single transaction and,
+// when a transactional consumer is configured, also commits the consumer group
+// offsets of the batch as part of it.
+func (w *FranzWriter) writeTransaction(ctx context.Context, client franzTransactionalProducer, b service.MessageBatch, records []*kgo.Record) error {
+	if err := client.BeginTransaction(); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	abort := func(err error) error {
+		if abortErr := client.EndTransaction(ctx, kgo.TryAbort); abortErr != nil {
+			return fmt.Errorf("%w (failed to abort transaction: %v)", err, abortErr)
+		}
+		return err
+	}
+
+	if err := produceRecords(ctx, client, b, records); err != nil {
+		return abort(err)
+	}
+
+	if w.hooks.transactionalConsumerFn != nil {
+		if err := w.hooks.transactionalConsumerFn(ctx, func(details *FranzSharedClientInfo) error {
+			return commitTransactionOffsets(ctx, client, details.Client, transactionOffsets(b, w.hooks.transactionalConsumer))
+		}); err != nil {
+			return abort(fmt.Errorf("failed to commit offsets within transaction: %w", err))
+		}
+	}
+
+	if err := client.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		return abort(fmt.Errorf("failed to commit transaction: %w", err))
+	}
+	return nil
+}
+
+// transactionOffsets returns the offsets to commit for each topic partition
+// consumed by the input with the given label for the messages of a batch, which
+// is the offset following the last record read.
+func transactionOffsets(b service.MessageBatch, label string) map[string]map[int32]int64 {
+	offsets := map[string]map[int32]int64{}
+	for _, msg := range b {
+		o, ok := franzRecordOffsetFromMessage(msg)
+		if !ok || o.label != label {
+			continue
+		}
+		if offsets[o.topic] == nil {
+			offsets[o.topic] = map[int32]int64{}
+		}
+		if prev, exists := offsets[o.topic][o.partition]; !exists || prev < o.offset+1 {
+			offsets[o.topic][o.partition] = o.offset + 1
+		}
+	}
+	return offsets
+}
+
+// commitTransactionOffsets adds the consumer group of the given consumer client
+// to the current transaction of the producer client and commits the offsets of
+// the given offsets within it. The group member ID and generation of the
+// consumer are provided so that the brokers are able to fence zombie consumers.
+func commitTransactionOffsets(ctx context.Context, producer franzTransactionalProducer, consumer franzGroupConsumer, offsets map[string]map[int32]int64) error {
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	group, _ := consumer.OptValue(kgo.ConsumerGroup).(string)
+	if group == "" {
+		return errors.New("the transactional consumer does not use a consumer group")
+	}
+	memberID, generation := consumer.GroupMetadata()
+
+	txnID, _ := producer.OptValue(kgo.TransactionalID).(string)
+	producerID, producerEpoch, err := producer.ProducerID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch producer ID: %w", err)
+	}
+
+	addReq := kmsg.NewPtrAddOffsetsToTxnRequest()
+	addReq.TransactionalID = txnID
+	addReq.ProducerID = producerID
+	addReq.ProducerEpoch = producerEpoch
+	addReq.Group = group
+	addResp, err := addReq.RequestWith(ctx, producer)
+	if err != nil {
+		return err
+	}
+	if err := kerr.ErrorForCode(addResp.ErrorCode); err != nil {
+		return err
+	}
+
+	commitReq := kmsg.NewPtrTxnOffsetCommitRequest()
+	commitReq.TransactionalID = txnID
+	commitReq.Group = group
+	commitReq.ProducerID = producerID
+	commitReq.ProducerEpoch = producerEpoch
+	commitReq.Generation = generation
+	commitReq.MemberID = memberID
+	for topic, partitions := range offsets {
+		reqTopic := kmsg.NewTxnOffsetCommitRequestTopic()
+		reqTopic.Topic = topic
+		for partition, offset := range partitions {
+			reqPartition := kmsg.NewTxnOffsetCommitRequestTopicPartition()
+			reqPartition.Partition = partition
+			reqPartition.Offset = offset
+			reqTopic.Partitions = append(reqTopic.Partitions, reqPartition)
+		}
+		commitReq.Topics = append(commitReq.Topics, reqTopic)
+	}
+	commitResp, err := commitReq.RequestWith(ctx, producer)
+	if err != nil {
+		return err
+	}
+	for _, t := range commitResp.Topics {
+		for _, p := range t.Partitions {
+			if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+				return fmt.Errorf("topic %q partition %d: %w", t.Topic, p.Partition, err)
+			}
+		}
+	}
+	return nil
 }
 
 // Close calls into the provided yield client func.
@@ -0,0 +1,110 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// keyLanes serialises the writes of message batches which contain records of
+// the same topic and key, so that these records are written in the order in
+// which their batches were received whilst batches with unrelated keys can be
+// written in parallel.
+//
+// Records are hashed into a fixed number of lanes, each of which is a chain of
+// channels where every batch waits for the batch which entered the lane before
+// it to be released.
+type keyLanes struct {
+	mut   sync.Mutex
+	tails []chan struct{}
+}
+
+func newKeyLanes(n int) *keyLanes {
+	return &keyLanes{tails: make([]chan struct{}, n)}
+}
+
+func (l *keyLanes) lanesOf(records []*kgo.Record) []int {
+	var lanes []int
+	for _, r := range records {
+		if r.Key == nil {
+			continue
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(r.Topic))
+		_, _ = h.Write(r.Key)
+		lanes = append(lanes, int(h.Sum32()%uint32(len(l.tails))))
+	}
+	slices.Sort(lanes)
+	return slices.Compact(lanes)
+}
+
+// acquire blocks until every batch which previously entered the lanes of the
+// given records has been released, and returns a function which must be called
+// once the records have been written in order to release the lanes.
+//
+// When the context is cancelled the lanes are still released in order once the
+// batches ahead have been released.
+func (l *keyLanes) acquire(ctx context.Context, records []*kgo.Record) (func(), error) {
+	lanes := l.lanesOf(records)
+	if len(lanes) == 0 {
+		return func() {}, nil
+	}
+
+	mine := make(chan struct{})
+	prev := make([]chan struct{}, 0, len(lanes))
+
+	l.mut.Lock()
+	for _, lane := range lanes {
+		if l.tails[lane] != nil {
+			prev = append(prev, l.tails[lane])
+		}
+		l.tails[lane] = mine
+	}
+	l.mut.Unlock()
+
+	var releaseOnce sync.Once
+	release := func() {
+		releaseOnce.Do(func() {
+			l.mut.Lock()
+			for _, lane := range lanes {
+				if l.tails[lane] == mine {
+					l.tails[lane] = nil
+				}
+			}
+			l.mut.Unlock()
+			close(mine)
+		})
+	}
+
+	for i, c := range prev {
+		select {
+		case <-c:
+		case <-ctx.Done():
+			go func() {
+				for _, c := range prev[i:] {
+					<-c
+				}
+				release()
+			}()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
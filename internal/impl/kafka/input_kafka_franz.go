@@ -21,6 +21,14 @@ import (
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
+const (
+	kfiFieldPartitions            = "partitions"
+	kfiFieldPartitionsTopic       = "topic"
+	kfiFieldPartitionsPartition   = "partition"
+	kfiFieldPartitionsStartOffset = "start_offset"
+	kfiFieldPartitionsEndOffset   = "end_offset"
+)
+
 func franzKafkaInputConfig() *service.ConfigSpec {
 	return service.NewConfigSpec().
 		Beta().
@@ -30,8 +38,14 @@ func franzKafkaInputConfig() *service.ConfigSpec {
 		Description(`
 When a consumer group is specified this input consumes one or more topics where partitions will automatically balance across any other connected clients with the same consumer group. When a consumer group is not specified topics can either be consumed in their entirety or with explicit partitions.
 
+Explicit partitions can also be consumed within a range of offsets with the ` + "`partitions`" + ` field, which is useful for replaying or backfilling data. Once all of the listed partitions have been consumed up to their ` + "`end_offset`" + ` the input shuts down, which results in the pipeline terminating gracefully.
+
 This input often out-performs the traditional ` + "`kafka`" + ` input as well as providing more useful logs and error messages.
 
+== Metrics
+
+Emits ` + "`kafka_lag`, `kafka_committed_offset` and `kafka_end_offset`" + ` gauges with ` + "`topic`" + ` and ` + "`partition`" + ` labels for each consumed topic partition when a consumer group is specified. These are refreshed every ` + "`topic_lag_refresh_period`" + `.
+
 == Metadata
 
 This input adds the following metadata fields to each message:
@@ -46,15 +60,29 @@ This input adds the following metadata fields to each message:
 - kafka_tombstone_message
 - All record headers
 ` + "```" + `
+
+Record header values are stored as strings holding the raw bytes of each header, and therefore binary values are preserved exactly. When written by an output such as ` + "`kafka_franz` or `redpanda`" + ` these values are copied into headers byte for byte. Within Bloblang a header can be read as a byte array with ` + "`@my_header.as_bytes()`" + `, or as other types with ` + "`@my_header.as_int64()` and `@my_header.as_timestamp()`" + `, where binary encoded integers and timestamps can be decoded with ` + "`as_int64(big_endian: true)` and `as_timestamp(big_endian: true)`" + `.
+
+== Tracing
+
+Records carrying a W3C trace context within their ` + "`traceparent` and `tracestate`" + ` headers have the spans of their messages created as children of that context, continuing the trace of the producer. Outputs such as ` + "`kafka_franz`" + ` can add these headers to the records they write with the field ` + "`inject_trace_context`" + `, which allows a single trace to follow data through multiple pipelines.
 `).
 		Fields(FranzKafkaInputConfigFields()...).
 		LintRule(`
-let has_topic_partitions = this.topics.any(t -> t.contains(":"))
-root = if $has_topic_partitions {
+let has_topic_partitions = this.topics.or([]).any(t -> t.contains(":")) || this.partitions.or([]).length() > 0
+root = if this.topics.or([]).length() == 0 && this.partitions.or([]).length() == 0 {
+  "at least one topic or partition must be specified"
+} else if this.partitions.or([]).any(p -> p.end_offset.or(-1) >= 0 && p.start_offset.or(-1) >= 0 && p.end_offset <= p.start_offset) {
+  "the end_offset of a partition must be greater than its start_offset"
+} else if $has_topic_partitions {
   if this.consumer_group.or("") != "" {
     "this input does not support both a consumer group and explicit topic partitions"
   } else if this.regexp_topics {
     "this input does not support both regular expression topics and explicit topic partitions"
+  } else if this.instance_id.or("") != "" {
+    "an instance ID can only be used in combination with a consumer group"
+  } else if this.transactional_commits.or(false) {
+    "transactional commits can only be used in combination with a consumer group"
   }
 } else {
   if this.consumer_group.or("") == "" {
@@ -69,9 +97,31 @@ root = if $has_topic_partitions {
 func FranzKafkaInputConfigFields() []*service.ConfigField {
 	return slices.Concat(
 		FranzConnectionFields(),
-		FranzConsumerFields(),
+		// Topics can be omitted when explicit partitions are listed instead.
+		franzConsumerFields(franzTopicsField().Default([]any{})),
+		[]*service.ConfigField{
+			service.NewObjectListField(kfiFieldPartitions,
+				service.NewStringField(kfiFieldPartitionsTopic).
+					Description("The topic to consume from."),
+				service.NewIntField(kfiFieldPartitionsPartition).
+					Description("The partition of the topic to consume from."),
+				service.NewIntField(kfiFieldPartitionsStartOffset).
+					Description("The offset to start consuming from. When omitted the field `start_from_oldest` determines which offset to start from.").
+					Optional(),
+				service.NewIntField(kfiFieldPartitionsEndOffset).
+					Description("The offset at which to stop consuming, exclusive. When omitted the partition is consumed indefinitely.").
+					Optional(),
+			).
+				Description("A list of explicit topic partitions to consume from, each optionally restricted to a range of offsets. This field cannot be combined with a consumer group. When every listed partition has an `end_offset` and no other topics are consumed the input shuts down once all of them have been reached.").
+				Example([]any{
+					map[string]any{"topic": "foo", "partition": 3, "start_offset": 1000, "end_offset": 2000},
+				}).
+				Default([]any{}).
+				Advanced(),
+		},
 		FranzReaderUnorderedConfigFields(),
 		[]*service.ConfigField{
+			FranzTransactionalCommitsField(),
 			service.NewAutoRetryNacksToggleField(),
 		},
 	)
@@ -86,15 +136,29 @@ func init() {
 			}
 			clientOpts := append([]kgo.Opt{}, tmpOpts...)
 
-			if tmpOpts, err = FranzConsumerOptsFromConfig(conf); err != nil {
+			consumerDetails, err := FranzConsumerDetailsFromConfig(conf)
+			if err != nil {
 				return nil, err
 			}
-			clientOpts = append(clientOpts, tmpOpts...)
+
+			endOffsets, allBounded, err := franzPartitionsFromConfig(conf, consumerDetails)
+			if err != nil {
+				return nil, err
+			}
+			clientOpts = append(clientOpts, consumerDetails.FranzOpts()...)
 
 			rdr, err := NewFranzReaderUnorderedFromConfig(conf, mgr, clientOpts...)
 			if err != nil {
 				return nil, err
 			}
+			rdr.endOffsets = endOffsets
+			rdr.endOfInputAtEnd = allBounded && len(consumerDetails.Topics) == 0
+
+			// Share the client so that it can be used by transactional outputs
+			// for committing offsets.
+			if rdr.sharedClientLabel, err = franzSharedClientLabelFromConfig(conf, mgr); err != nil {
+				return nil, err
+			}
 
 			return service.AutoRetryNacksBatchedToggled(conf, rdr)
 		})
@@ -102,3 +166,63 @@ func init() {
 		panic(err)
 	}
 }
+
+// franzPartitionsFromConfig adds the explicit partitions listed in a parsed
+// config to the consumer details and returns their end offsets, along with
+// whether all explicitly consumed partitions have one.
+func franzPartitionsFromConfig(conf *service.ParsedConfig, details *FranzConsumerDetails) (partitionEndOffsets, bool, error) {
+	partConfs, err := conf.FieldObjectList(kfiFieldPartitions)
+	if err != nil {
+		return nil, false, err
+	}
+
+	endOffsets := partitionEndOffsets{}
+	for _, pConf := range partConfs {
+		topic, err := pConf.FieldString(kfiFieldPartitionsTopic)
+		if err != nil {
+			return nil, false, err
+		}
+		partition, err := pConf.FieldInt(kfiFieldPartitionsPartition)
+		if err != nil {
+			return nil, false, err
+		}
+
+		offset := details.InitialOffset
+		if pConf.Contains(kfiFieldPartitionsStartOffset) {
+			startOffset, err := pConf.FieldInt(kfiFieldPartitionsStartOffset)
+			if err != nil {
+				return nil, false, err
+			}
+			offset = kgo.NewOffset().At(int64(startOffset))
+		}
+
+		if details.TopicPartitions == nil {
+			details.TopicPartitions = map[string]map[int32]kgo.Offset{}
+		}
+		if details.TopicPartitions[topic] == nil {
+			details.TopicPartitions[topic] = map[int32]kgo.Offset{}
+		}
+		details.TopicPartitions[topic][int32(partition)] = offset
+
+		if pConf.Contains(kfiFieldPartitionsEndOffset) {
+			endOffset, err := pConf.FieldInt(kfiFieldPartitionsEndOffset)
+			if err != nil {
+				return nil, false, err
+			}
+			if endOffsets[topic] == nil {
+				endOffsets[topic] = map[int32]int64{}
+			}
+			endOffsets[topic][int32(partition)] = int64(endOffset)
+		}
+	}
+
+	allBounded := len(details.TopicPartitions) > 0
+	for topic, partitions := range details.TopicPartitions {
+		for partition := range partitions {
+			if _, bounded := endOffsets.get(topic, partition); !bounded {
+				allBounded = false
+			}
+		}
+	}
+	return endOffsets, allBounded, nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestKafkaFranzInputBadParams(t *testing.T) {
+	testCases := []struct {
+		name        string
+		conf        string
+		errContains string
+	}{
+		{
+			name: "instance id with a consumer group",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topics: [ foo ]
+  consumer_group: bar
+  instance_id: baz
+`,
+		},
+		{
+			name: "instance id with explicit partitions",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topics: [ foo:0 ]
+  instance_id: baz
+`,
+			errContains: "an instance ID can only be used in combination with a consumer group",
+		},
+		{
+			name: "transactional commits with explicit partitions",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topics: [ foo:0 ]
+  transactional_commits: true
+`,
+			errContains: "transactional commits can only be used in combination with a consumer group",
+		},
+		{
+			name: "redpanda transactional commits with explicit partitions",
+			conf: `
+redpanda:
+  seed_brokers: [ foo:1234 ]
+  topics: [ foo:0 ]
+  transactional_commits: true
+`,
+			errContains: "transactional commits can only be used in combination with a consumer group",
+		},
+		{
+			name: "redpanda instance id with explicit partitions",
+			conf: `
+redpanda:
+  seed_brokers: [ foo:1234 ]
+  topics: [ foo:0 ]
+  instance_id: baz
+`,
+			errContains: "an instance ID can only be used in combination with a consumer group",
+		},
+		{
+			name: "explicit partitions without topics",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  partitions:
+    - { topic: foo, partition: 3, start_offset: 1000, end_offset: 2000 }
+`,
+		},
+		{
+			name: "no topics or partitions",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  consumer_group: bar
+`,
+			errContains: "at least one topic or partition must be specified",
+		},
+		{
+			name: "explicit partitions with a consumer group",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  consumer_group: bar
+  partitions:
+    - { topic: foo, partition: 3 }
+`,
+			errContains: "this input does not support both a consumer group and explicit topic partitions",
+		},
+		{
+			name: "explicit partitions with an empty offset range",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  partitions:
+    - { topic: foo, partition: 3, start_offset: 1000, end_offset: 1000 }
+`,
+			errContains: "the end_offset of a partition must be greater than its start_offset",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := service.NewStreamBuilder().AddInputYAML(test.conf)
+			if test.errContains == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.errContains)
+			}
+		})
+	}
+}
+
+func TestKafkaFranzInputPartitions(t *testing.T) {
+	testCases := []struct {
+		name       string
+		conf       string
+		partitions map[string]map[int32]kgo.Offset
+		endOffsets partitionEndOffsets
+		allBounded bool
+	}{
+		{
+			name: "all partitions bounded",
+			conf: `
+partitions:
+  - { topic: foo, partition: 0, start_offset: 10, end_offset: 20 }
+  - { topic: foo, partition: 1, end_offset: 5 }
+`,
+			partitions: map[string]map[int32]kgo.Offset{
+				"foo": {0: kgo.NewOffset().At(10), 1: kgo.NewOffset().AtStart()},
+			},
+			endOffsets: partitionEndOffsets{"foo": {0: 20, 1: 5}},
+			allBounded: true,
+		},
+		{
+			name: "unbounded topic partition",
+			conf: `
+topics: [ bar:2 ]
+partitions:
+  - { topic: foo, partition: 0, end_offset: 20 }
+`,
+			partitions: map[string]map[int32]kgo.Offset{
+				"foo": {0: kgo.NewOffset().AtStart()},
+				"bar": {2: kgo.NewOffset().At(-2)},
+			},
+			endOffsets: partitionEndOffsets{"foo": {0: 20}},
+			allBounded: false,
+		},
+	}
+
+	spec := service.NewConfigSpec().Fields(FranzKafkaInputConfigFields()...)
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := spec.ParseYAML(test.conf, nil)
+			require.NoError(t, err)
+
+			details, err := FranzConsumerDetailsFromConfig(conf)
+			require.NoError(t, err)
+
+			endOffsets, allBounded, err := franzPartitionsFromConfig(conf, details)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.partitions, details.TopicPartitions)
+			assert.Equal(t, test.endOffsets, endOffsets)
+			assert.Equal(t, test.allBounded, allBounded)
+		})
+	}
+}
+
+func TestPartitionEndOffsetsCompleted(t *testing.T) {
+	endOffsets := partitionEndOffsets{"foo": {0: 10, 1: 20}}
+	remaining := endOffsets.clone()
+
+	assert.False(t, endOffsets.completed("foo", 0, remaining))
+	remaining.remove("foo", 0)
+	assert.True(t, endOffsets.completed("foo", 0, remaining))
+	assert.False(t, endOffsets.completed("foo", 1, remaining))
+	assert.False(t, endOffsets.completed("bar", 0, remaining))
+
+	remaining.remove("foo", 1)
+	assert.Empty(t, remaining)
+	assert.Len(t, endOffsets["foo"], 2)
+}
+
+func TestKafkaFranzInputTransactionalCommitsRequiresLabel(t *testing.T) {
+	for _, input := range []string{"kafka_franz", "redpanda"} {
+		t.Run(input, func(t *testing.T) {
+			builder := service.NewStreamBuilder()
+			require.NoError(t, builder.AddInputYAML(input+`:
+  seed_brokers: [ foo:1234 ]
+  topics: [ foo ]
+  consumer_group: bar
+  transactional_commits: true
+`))
+			require.NoError(t, builder.AddOutputYAML(`drop: {}`))
+
+			strm, err := builder.Build()
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = strm.Run(ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "a label must be set on this input when transactional_commits is enabled")
+		})
+	}
+}
@@ -62,7 +62,7 @@ Records are processed and delivered from each partition in batches as received f
 
 == Metrics
 
-Emits a ` + "`redpanda_lag`" + ` metric with ` + "`topic`" + ` and ` + "`partition`" + ` labels for each consumed topic.
+Emits ` + "`redpanda_lag`, `redpanda_committed_offset` and `redpanda_end_offset`" + ` gauges with ` + "`topic`" + ` and ` + "`partition`" + ` labels for each consumed topic partition when a consumer group is specified. These are refreshed every ` + "`topic_lag_refresh_period`" + `.
 
 == Metadata
 
@@ -79,6 +79,12 @@ This input adds the following metadata fields to each message:
 - kafka_tombstone_message
 - All record headers
 ` + "```" + `
+
+Record header values are stored as strings holding the raw bytes of each header, and therefore binary values are preserved exactly. When written by an output such as ` + "`kafka_franz` or `redpanda`" + ` these values are copied into headers byte for byte. Within Bloblang a header can be read as a byte array with ` + "`@my_header.as_bytes()`" + `, or as other types with ` + "`@my_header.as_int64()` and `@my_header.as_timestamp()`" + `, where binary encoded integers and timestamps can be decoded with ` + "`as_int64(big_endian: true)` and `as_timestamp(big_endian: true)`" + `.
+
+== Tracing
+
+When a record has ` + "`traceparent` and `tracestate`" + ` headers the spans of its message continue the W3C trace context they describe, so that traces started by a producer extend into this pipeline.
 `).
 		Fields(redpandaInputConfigFields()...).
 		LintRule(`
@@ -88,6 +94,10 @@ root = if $has_topic_partitions {
     "this input does not support both a consumer group and explicit topic partitions"
   } else if this.regexp_topics {
     "this input does not support both regular expression topics and explicit topic partitions"
+  } else if this.instance_id.or("") != "" {
+    "an instance ID can only be used in combination with a consumer group"
+  } else if this.transactional_commits.or(false) {
+    "transactional commits can only be used in combination with a consumer group"
   }
 } else {
   if this.consumer_group.or("") == "" {
@@ -103,6 +113,7 @@ func redpandaInputConfigFields() []*service.ConfigField {
 		FranzConsumerFields(),
 		FranzReaderOrderedConfigFields(),
 		[]*service.ConfigField{
+			FranzTransactionalCommitsField(),
 			service.NewAutoRetryNacksToggleField(),
 		},
 	)
@@ -129,6 +140,12 @@ func init() {
 				return nil, err
 			}
 
+			// Share the client so that it can be used by transactional outputs
+			// for committing offsets.
+			if rdr.sharedClientLabel, err = franzSharedClientLabelFromConfig(conf, mgr); err != nil {
+				return nil, err
+			}
+
 			return service.AutoRetryNacksBatchedToggled(conf, rdr)
 		})
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 	"github.com/IBM/sarama"
 
 	"github.com/Jeffail/checkpoint"
+	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
@@ -78,6 +79,8 @@ This input adds the following metadata fields to each message:
 - kafka_tombstone_message
 - All existing message headers (version 0.11+)
 
+Record header values are stored as strings holding the raw bytes of each header, or as arrays of such strings when `+"`multi_header`"+` is enabled, and therefore binary values are preserved exactly. Within Bloblang a header can be read as a byte array with `+"`@my_header.as_bytes()`"+`, or as other types with `+"`@my_header.as_int64()` and `@my_header.as_timestamp()`"+`.
+
 The field `+"`kafka_lag`"+` is the calculated difference between the high water mark offset of the partition at the time of ingestion and the current message offset.
 
 You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
@@ -128,7 +131,7 @@ Unfortunately this error message will appear for a wide range of connection prob
 				Advanced().
 				Optional(),
 			service.NewStringField(iskFieldRackID).
-				Description("A rack identifier for this client.").
+				Description("A rack identifier for this client. When set to `auto` the rack is detected at startup from the instance metadata of the cloud provider, which is the availability zone ID on AWS, the zone on GCP and the availability zone, or fault domain when not deployed to a zone, on Azure.").
 				Advanced().Default(""),
 			service.NewBoolField(iskFieldStartFromOldest).
 				Description("Determines whether to consume from the oldest available offset, otherwise messages are consumed from the latest offset. The setting is applied when creating a new consumer group or the saved offset no longer exists.").
@@ -427,7 +430,11 @@ func dataToPart(highestOffset int64, data *sarama.ConsumerMessage, multiHeader b
 	part.MetaSetMut("kafka_timestamp_unix", data.Timestamp.Unix())
 	part.MetaSetMut("kafka_tombstone_message", data.Value == nil)
 
-	return part
+	carrier := propagation.MapCarrier{}
+	for _, hdr := range data.Headers {
+		carrier[strings.ToLower(string(hdr.Key))] = string(hdr.Value)
+	}
+	return withExtractedTraceContext(part, carrier)
 }
 
 //------------------------------------------------------------------------------
@@ -474,6 +481,9 @@ func (k *kafkaReader) saramaConfigFromParsed(conf *service.ParsedConfig) (*saram
 	if config.RackID, err = conf.FieldString(iskFieldRackID); err != nil {
 		return nil, err
 	}
+	if config.RackID, err = resolveRackID(config.RackID); err != nil {
+		return nil, err
+	}
 
 	config.Net.DialTimeout = time.Second
 	config.Consumer.Return.Errors = true
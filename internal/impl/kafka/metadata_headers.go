@@ -0,0 +1,40 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+// metadataHeaderValues converts a metadata value into the raw values of one or
+// more record headers. Byte arrays and strings are written verbatim so that
+// binary header values survive a round trip, arrays (such as those produced by
+// the multi_header mode of inputs) are expanded into a header per element, and
+// all other types are serialised with their Bloblang string representation.
+func metadataHeaderValues(v any) [][]byte {
+	switch t := v.(type) {
+	case []byte:
+		return [][]byte{t}
+	case string:
+		return [][]byte{[]byte(t)}
+	case []any:
+		values := make([][]byte, 0, len(t))
+		for _, e := range t {
+			values = append(values, metadataHeaderValues(e)...)
+		}
+		return values
+	}
+	return [][]byte{[]byte(bloblang.ValueToString(v))}
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataHeaderValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input any
+		want  [][]byte
+	}{
+		{name: "string", input: "foo", want: [][]byte{[]byte("foo")}},
+		{name: "binary bytes", input: []byte{0x00, 0xff, 0xfe}, want: [][]byte{{0x00, 0xff, 0xfe}}},
+		{name: "binary string", input: string([]byte{0x00, 0xff}), want: [][]byte{{0x00, 0xff}}},
+		{name: "int64", input: int64(-42), want: [][]byte{[]byte("-42")}},
+		{name: "bool", input: true, want: [][]byte{[]byte("true")}},
+		{
+			name:  "timestamp",
+			input: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+			want:  [][]byte{[]byte("2024-01-02T03:04:05.000000006Z")},
+		},
+		{
+			name:  "array",
+			input: []any{"a", []byte("b"), int64(3)},
+			want:  [][]byte{[]byte("a"), []byte("b"), []byte("3")},
+		},
+		{name: "empty array", input: []any{}, want: [][]byte{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, metadataHeaderValues(test.input))
+		})
+	}
+}
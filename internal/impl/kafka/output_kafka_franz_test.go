@@ -15,10 +15,18 @@
 package kafka
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
@@ -67,6 +75,81 @@ kafka_franz:
 `,
 			errContains: "a partition cannot be specified unless the partitioner is set to manual",
 		},
+		{
+			name: "manual_mapping partitioner with a partition_mapping",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topic: foo
+  partitioner: manual_mapping
+  partition_mapping: 'root = 0'
+`,
+		},
+		{
+			name: "manual_mapping partitioner with no partition_mapping",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topic: foo
+  partitioner: manual_mapping
+`,
+			errContains: "a partition_mapping must be specified when the partitioner is set to manual_mapping",
+		},
+		{
+			name: "partition_mapping without manual_mapping partitioner",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topic: foo
+  partitioner: manual
+  partition: '${! meta("foo") }'
+  partition_mapping: 'root = 0'
+`,
+			errContains: "a partition_mapping cannot be specified unless the partitioner is set to manual_mapping",
+		},
+		{
+			name: "transactional with a transactional id",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topic: foo
+  transactional: true
+  transactional_id: bar
+  transactional_consumer: baz
+`,
+		},
+		{
+			name: "transactional without a transactional id",
+			conf: `
+redpanda:
+  seed_brokers: [ foo:1234 ]
+  topic: foo
+  transactional: true
+`,
+			errContains: "a transactional_id must be specified when transactional is enabled",
+		},
+		{
+			name: "transactional without idempotent writes",
+			conf: `
+kafka_franz:
+  seed_brokers: [ foo:1234 ]
+  topic: foo
+  transactional: true
+  transactional_id: bar
+  idempotent_write: false
+`,
+			errContains: "idempotent_write cannot be disabled when transactional is enabled",
+		},
+		{
+			name: "transactional consumer without transactional",
+			conf: `
+redpanda:
+  seed_brokers: [ foo:1234 ]
+  topic: foo
+  transactional_consumer: baz
+`,
+			errContains: "a transactional_consumer can only be specified when transactional is enabled",
+		},
 	}
 
 	for _, test := range testCases {
@@ -82,3 +165,342 @@ kafka_franz:
 		})
 	}
 }
+
+func TestFranzWriterPartitionMapping(t *testing.T) {
+	conf, err := franzKafkaOutputConfig().ParseYAML(`
+seed_brokers: [ foo:1234 ]
+topic: foo
+partitioner: manual_mapping
+partition_mapping: 'root = if @tenant == "acme" { 0 } else if @tenant == "globex" { 1 } else { this.id % 4 + 2 }'
+`, nil)
+	require.NoError(t, err)
+
+	w, err := NewFranzWriterFromConfig(conf, NewFranzWriterHooks(nil))
+	require.NoError(t, err)
+
+	newMsg := func(tenant, content string) *service.Message {
+		msg := service.NewMessage([]byte(content))
+		msg.MetaSetMut("tenant", tenant)
+		return msg
+	}
+
+	records, err := w.BatchToRecords(context.Background(), service.MessageBatch{
+		newMsg("acme", `{"id":7}`),
+		newMsg("globex", `{"id":7}`),
+		newMsg("initech", `{"id":7}`),
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, int32(0), records[0].Partition)
+	assert.Equal(t, int32(1), records[1].Partition)
+	assert.Equal(t, int32(5), records[2].Partition)
+
+	_, err = w.BatchToRecords(context.Background(), service.MessageBatch{
+		newMsg("initech", `{"id":-7}`),
+	})
+	require.ErrorContains(t, err, "is not a valid partition")
+
+	_, err = w.BatchToRecords(context.Background(), service.MessageBatch{
+		newMsg("initech", `not json`),
+	})
+	require.ErrorContains(t, err, "partition mapping error")
+}
+
+func TestFranzWriterTransactionOffsets(t *testing.T) {
+	newMsg := func(label, topic string, partition int32, offset int64) *service.Message {
+		return withFranzRecordOffset(service.NewMessage(nil), label, &kgo.Record{
+			Topic:     topic,
+			Partition: partition,
+			Offset:    offset,
+		})
+	}
+
+	modified := newMsg("foo", "foo", 2, 20)
+	modified.MetaSetMut("kafka_topic", "baz")
+	modified.MetaSetMut("kafka_partition", 5)
+
+	batch := service.MessageBatch{
+		newMsg("foo", "foo", 0, 10),
+		newMsg("foo", "foo", 0, 12),
+		newMsg("foo", "foo", 0, 11),
+		newMsg("foo", "foo", 1, 3),
+		newMsg("foo", "bar", 2, 7),
+		newMsg("other", "bar", 3, 7),
+		modified,
+		service.NewMessage(nil),
+	}
+
+	assert.Equal(t, map[string]map[int32]int64{
+		"foo": {0: 13, 1: 4, 2: 21},
+		"bar": {2: 8},
+	}, transactionOffsets(batch, "foo"))
+}
+
+func franzOptKey(opt any) uintptr {
+	return reflect.ValueOf(opt).Pointer()
+}
+
+type fakeTransactionalProducer struct {
+	opts          map[uintptr]any
+	produceErr    error
+	commitErrCode int16
+
+	events   []string
+	requests []kmsg.Request
+}
+
+func (f *fakeTransactionalProducer) Produce(_ context.Context, r *kgo.Record, promise func(*kgo.Record, error)) {
+	f.events = append(f.events, "produce "+r.Topic)
+	promise(r, f.produceErr)
+}
+
+func (f *fakeTransactionalProducer) OptValue(opt any) any {
+	return f.opts[franzOptKey(opt)]
+}
+
+func (f *fakeTransactionalProducer) BeginTransaction() error {
+	f.events = append(f.events, "begin")
+	return nil
+}
+
+func (f *fakeTransactionalProducer) EndTransaction(_ context.Context, commit kgo.TransactionEndTry) error {
+	if commit {
+		f.events = append(f.events, "commit")
+	} else {
+		f.events = append(f.events, "abort")
+	}
+	return nil
+}
+
+func (*fakeTransactionalProducer) ProducerID(context.Context) (int64, int16, error) {
+	return 5, 1, nil
+}
+
+func (f *fakeTransactionalProducer) Request(_ context.Context, req kmsg.Request) (kmsg.Response, error) {
+	f.requests = append(f.requests, req)
+	switch r := req.(type) {
+	case *kmsg.AddOffsetsToTxnRequest:
+		f.events = append(f.events, "add offsets "+r.Group)
+		return kmsg.NewPtrAddOffsetsToTxnResponse(), nil
+	case *kmsg.TxnOffsetCommitRequest:
+		f.events = append(f.events, "commit offsets "+r.Group)
+		resp := kmsg.NewPtrTxnOffsetCommitResponse()
+		for _, t := range r.Topics {
+			respTopic := kmsg.NewTxnOffsetCommitResponseTopic()
+			respTopic.Topic = t.Topic
+			for _, p := range t.Partitions {
+				respPartition := kmsg.NewTxnOffsetCommitResponseTopicPartition()
+				respPartition.Partition = p.Partition
+				respPartition.ErrorCode = f.commitErrCode
+				respTopic.Partitions = append(respTopic.Partitions, respPartition)
+			}
+			resp.Topics = append(resp.Topics, respTopic)
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("unexpected request %T", req)
+}
+
+func newFakeTransactionalProducer() *fakeTransactionalProducer {
+	return &fakeTransactionalProducer{
+		opts: map[uintptr]any{
+			franzOptKey(kgo.TransactionalID): "txn",
+		},
+	}
+}
+
+func TestFranzWriterWriteTransaction(t *testing.T) {
+	consumer, err := kgo.NewClient(kgo.SeedBrokers("localhost:9092"), kgo.ConsumerGroup("group"))
+	require.NoError(t, err)
+	t.Cleanup(consumer.Close)
+
+	consumerHooks := franzWriterHooks{}.WithTransactionalConsumerFn("foo", func(_ context.Context, fn FranzSharedClientUseFn) error {
+		return fn(&FranzSharedClientInfo{Client: consumer})
+	})
+
+	batch := service.MessageBatch{
+		withFranzRecordOffset(service.NewMessage(nil), "foo", &kgo.Record{Topic: "in", Partition: 1, Offset: 10}),
+		withFranzRecordOffset(service.NewMessage(nil), "foo", &kgo.Record{Topic: "in", Partition: 1, Offset: 11}),
+	}
+	records := []*kgo.Record{{Topic: "out"}, {Topic: "out"}}
+
+	tests := []struct {
+		name          string
+		hooks         franzWriterHooks
+		produceErr    error
+		commitErrCode int16
+		errContains   string
+		events        []string
+	}{
+		{
+			name:   "without transactional consumer",
+			events: []string{"begin", "produce out", "produce out", "commit"},
+		},
+		{
+			name:   "with transactional consumer",
+			hooks:  consumerHooks,
+			events: []string{"begin", "produce out", "produce out", "add offsets group", "commit offsets group", "commit"},
+		},
+		{
+			name:        "produce error",
+			hooks:       consumerHooks,
+			produceErr:  errors.New("nope"),
+			errContains: "nope",
+			events:      []string{"begin", "produce out", "produce out", "abort"},
+		},
+		{
+			name:          "offset commit error",
+			hooks:         consumerHooks,
+			commitErrCode: kerr.IllegalGeneration.Code,
+			errContains:   "failed to commit offsets within transaction",
+			events:        []string{"begin", "produce out", "produce out", "add offsets group", "commit offsets group", "abort"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			producer := newFakeTransactionalProducer()
+			producer.produceErr = test.produceErr
+			producer.commitErrCode = test.commitErrCode
+
+			w := &FranzWriter{Transactional: true, hooks: test.hooks}
+			err := w.writeTransaction(context.Background(), producer, batch, records)
+			if test.errContains != "" {
+				require.ErrorContains(t, err, test.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, test.events, producer.events)
+		})
+	}
+}
+
+func TestFranzWriterCommitTransactionOffsets(t *testing.T) {
+	consumer, err := kgo.NewClient(kgo.SeedBrokers("localhost:9092"), kgo.ConsumerGroup("group"))
+	require.NoError(t, err)
+	t.Cleanup(consumer.Close)
+
+	producer := newFakeTransactionalProducer()
+	require.NoError(t, commitTransactionOffsets(context.Background(), producer, consumer, map[string]map[int32]int64{
+		"foo": {0: 13},
+	}))
+	require.Len(t, producer.requests, 2)
+
+	addReq := producer.requests[0].(*kmsg.AddOffsetsToTxnRequest)
+	assert.Equal(t, "txn", addReq.TransactionalID)
+	assert.Equal(t, "group", addReq.Group)
+	assert.Equal(t, int64(5), addReq.ProducerID)
+	assert.Equal(t, int16(1), addReq.ProducerEpoch)
+
+	commitReq := producer.requests[1].(*kmsg.TxnOffsetCommitRequest)
+	assert.Equal(t, "txn", commitReq.TransactionalID)
+	assert.Equal(t, "group", commitReq.Group)
+	memberID, generation := consumer.GroupMetadata()
+	assert.Equal(t, memberID, commitReq.MemberID)
+	assert.Equal(t, generation, commitReq.Generation)
+	require.Len(t, commitReq.Topics, 1)
+	assert.Equal(t, "foo", commitReq.Topics[0].Topic)
+	require.Len(t, commitReq.Topics[0].Partitions, 1)
+	assert.Equal(t, int32(0), commitReq.Topics[0].Partitions[0].Partition)
+	assert.Equal(t, int64(13), commitReq.Topics[0].Partitions[0].Offset)
+
+	// Nothing is sent without offsets to commit.
+	producer = newFakeTransactionalProducer()
+	require.NoError(t, commitTransactionOffsets(context.Background(), producer, consumer, map[string]map[int32]int64{}))
+	assert.Empty(t, producer.requests)
+
+	// Consumers without a group can't commit offsets.
+	noGroup, err := kgo.NewClient(kgo.SeedBrokers("localhost:9092"))
+	require.NoError(t, err)
+	t.Cleanup(noGroup.Close)
+	require.ErrorContains(t, commitTransactionOffsets(context.Background(), producer, noGroup, map[string]map[int32]int64{
+		"foo": {0: 13},
+	}), "does not use a consumer group")
+}
+
+func TestFranzWriterCheckSameCluster(t *testing.T) {
+	producer, err := kgo.NewClient(kgo.SeedBrokers("foo:9092", "bar:9092"))
+	require.NoError(t, err)
+	t.Cleanup(producer.Close)
+
+	sameCluster, err := kgo.NewClient(kgo.SeedBrokers("bar:9092", "foo:9092"))
+	require.NoError(t, err)
+	t.Cleanup(sameCluster.Close)
+	require.NoError(t, checkSameCluster(producer, sameCluster))
+
+	otherCluster, err := kgo.NewClient(kgo.SeedBrokers("baz:9092"))
+	require.NoError(t, err)
+	t.Cleanup(otherCluster.Close)
+	require.ErrorContains(t, checkSameCluster(producer, otherCluster), "must connect to the same seed brokers")
+}
+
+func TestFranzWriterKeyLanes(t *testing.T) {
+	lanes := newKeyLanes(64)
+
+	recordsA := []*kgo.Record{{Topic: "foo", Key: []byte("a")}}
+	recordsB := []*kgo.Record{{Topic: "foo", Key: []byte("a")}, {Topic: "foo"}}
+	recordsC := []*kgo.Record{{Topic: "foo", Key: []byte("c")}}
+	require.NotEqual(t, lanes.lanesOf(recordsA), lanes.lanesOf(recordsC))
+	assert.Equal(t, lanes.lanesOf(recordsA), lanes.lanesOf(recordsB))
+
+	releaseA, err := lanes.acquire(context.Background(), recordsA)
+	require.NoError(t, err)
+
+	acquiredB := make(chan func())
+	go func() {
+		release, err := lanes.acquire(context.Background(), recordsB)
+		assert.NoError(t, err)
+		acquiredB <- release
+	}()
+
+	// Unrelated keys are not blocked.
+	releaseC, err := lanes.acquire(context.Background(), recordsC)
+	require.NoError(t, err)
+	releaseC()
+
+	select {
+	case <-acquiredB:
+		t.Fatal("batch with the same key acquired the lane before it was released")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	releaseA()
+
+	select {
+	case releaseB := <-acquiredB:
+		releaseB()
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the lane to be released")
+	}
+}
+
+func TestFranzWriterKeyLanesCancelled(t *testing.T) {
+	lanes := newKeyLanes(8)
+	records := []*kgo.Record{{Topic: "foo", Key: []byte("a")}}
+
+	releaseA, err := lanes.acquire(context.Background(), records)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = lanes.acquire(ctx, records)
+	require.ErrorIs(t, err, context.Canceled)
+
+	acquired := make(chan func())
+	go func() {
+		release, err := lanes.acquire(context.Background(), records)
+		assert.NoError(t, err)
+		acquired <- release
+	}()
+
+	// The cancelled batch releases its turn once the batch ahead of it is
+	// released.
+	releaseA()
+
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the lane to be released")
+	}
+}
@@ -50,6 +50,7 @@ func redpandaOutputConfigFields() []*service.ConfigField {
 				Default(256),
 		},
 		FranzProducerFields(),
+		FranzWriterTransactionFields(),
 	)
 }
 
@@ -78,40 +79,53 @@ func init() {
 			}
 			clientOpts = append(clientOpts, tmpOpts...)
 
+			if tmpOpts, err = FranzWriterTransactionOptsFromConfig(conf); err != nil {
+				return
+			}
+			if len(tmpOpts) > 0 {
+				// Transactions are sequential per producer.
+				maxInFlight = 1
+			}
+			clientOpts = append(clientOpts, tmpOpts...)
+
 			clientOpts = append(clientOpts, kgo.AllowAutoTopicCreation()) // TODO: Configure this?
 
 			var client *kgo.Client
 			var clientMut sync.Mutex
 
-			output, err = NewFranzWriterFromConfig(
-				conf,
-				NewFranzWriterHooks(
-					func(_ context.Context, fn FranzSharedClientUseFn) error {
-						clientMut.Lock()
-						defer clientMut.Unlock()
-
-						if client == nil {
-							var err error
-							if client, err = kgo.NewClient(clientOpts...); err != nil {
-								return err
-							}
-						}
-						return fn(&FranzSharedClientInfo{
-							Client:      client,
-							ConnDetails: connDetails,
-						})
-					}).WithYieldClientFn(
-					func(context.Context) error {
-						clientMut.Lock()
-						defer clientMut.Unlock()
-
-						if client == nil {
-							return nil
+			hooks := NewFranzWriterHooks(
+				func(_ context.Context, fn FranzSharedClientUseFn) error {
+					clientMut.Lock()
+					defer clientMut.Unlock()
+
+					if client == nil {
+						var err error
+						if client, err = kgo.NewClient(clientOpts...); err != nil {
+							return err
 						}
-						client.Close()
-						client = nil
+					}
+					return fn(&FranzSharedClientInfo{
+						Client:      client,
+						ConnDetails: connDetails,
+					})
+				}).WithYieldClientFn(
+				func(context.Context) error {
+					clientMut.Lock()
+					defer clientMut.Unlock()
+
+					if client == nil {
 						return nil
-					}))
+					}
+					client.Close()
+					client = nil
+					return nil
+				})
+
+			if hooks, err = hooks.WithTransactionalConsumerFromConfig(conf, mgr); err != nil {
+				return
+			}
+
+			output, err = NewFranzWriterFromConfig(conf, hooks)
 			return
 		})
 	if err != nil {
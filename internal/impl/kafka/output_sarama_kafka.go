@@ -28,7 +28,6 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"golang.org/x/sync/syncmap"
 
-	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
@@ -72,7 +71,7 @@ The config field `+"`ack_replicas`"+` determines whether we wait for acknowledge
 
 Both the `+"`key` and `topic`"+` fields can be dynamically set using function interpolations described in xref:configuration:interpolation.adoc#bloblang-queries[Bloblang queries].
 
-xref:configuration:metadata.adoc[Metadata] will be added to each message sent as headers (version 0.11+), but can be restricted using the field `+"<<metadata, `metadata`>>"+`.
+xref:configuration:metadata.adoc[Metadata] will be added to each message sent as headers (version 0.11+), but can be restricted using the field `+"<<metadata, `metadata`>>"+`. String and byte array values are written verbatim, arrays are written as one header per element and all other values are written as their string representation.
 
 == Strict ordering and retries
 
@@ -141,6 +140,7 @@ Unfortunately this error message will appear for a wide range of connection prob
 			service.NewMetadataExcludeFilterField(oskFieldMetadata).
 				Description("Specify criteria for which metadata values are sent with messages as headers."),
 			service.NewInjectTracingSpanMappingField(),
+			injectTraceContextField(),
 			service.NewOutputMaxInFlightField(),
 			service.NewBoolField(oskFieldIdempotentWrite).
 				Description("Enable the idempotent write producer option. This requires the `IDEMPOTENT_WRITE` permission on `CLUSTER` and can be disabled if this permission is not available.").
@@ -216,6 +216,7 @@ type kafkaWriter struct {
 	isTimestampMs bool
 	staticHeaders map[string]string
 	metaFilter    *service.MetadataExcludeFilter
+	injectTrace   bool
 	retryAsBatch  bool
 
 	customTopicCreation bool
@@ -262,6 +263,10 @@ func NewKafkaWriterFromParsed(conf *service.ParsedConfig, mgr *service.Resources
 		return nil, err
 	}
 
+	if k.injectTrace, err = conf.FieldBool(fieldInjectTraceContext); err != nil {
+		return nil, err
+	}
+
 	if k.key, err = conf.FieldInterpolatedString(oskFieldKey); err != nil {
 		return nil, err
 	}
@@ -390,11 +395,13 @@ func strToPartitioner(str string) (sarama.PartitionerConstructor, error) {
 func (k *kafkaWriter) buildSystemHeaders(part *service.Message) []sarama.RecordHeader {
 	if k.saramConf.Version.IsAtLeast(sarama.V0_11_0_0) {
 		out := []sarama.RecordHeader{}
-		_ = k.metaFilter.Walk(part, func(k, v string) error {
-			out = append(out, sarama.RecordHeader{
-				Key:   []byte(k),
-				Value: []byte(bloblang.ValueToString(v)),
-			})
+		_ = k.metaFilter.WalkMut(part, func(k string, v any) error {
+			for _, hv := range metadataHeaderValues(v) {
+				out = append(out, sarama.RecordHeader{
+					Key:   []byte(k),
+					Value: hv,
+				})
+			}
 			return nil
 		})
 		return out
@@ -516,7 +523,7 @@ func (k *kafkaWriter) Connect(ctx context.Context) error {
 
 // WriteBatch will attempt to write a message to Kafka, wait for
 // acknowledgement, and returns an error if applicable.
-func (k *kafkaWriter) WriteBatch(ctx context.Context, msg service.MessageBatch) error {
+func (k *kafkaWriter) WriteBatch(ctx context.Context, msg service.MessageBatch) (err error) {
 	k.connMut.RLock()
 	producer := k.producer
 	k.connMut.RUnlock()
@@ -525,6 +532,11 @@ func (k *kafkaWriter) WriteBatch(ctx context.Context, msg service.MessageBatch)
 		return service.ErrNotConnected
 	}
 
+	span := startBatchSpan(ctx, "kafka_produce", msg)
+	defer func() {
+		endBatchSpan(span, err)
+	}()
+
 	topicExecutor := msg.InterpolationExecutor(k.topic)
 	keyExecutor := msg.InterpolationExecutor(k.key)
 	var partitionExecutor *service.MessageBatchInterpolationExecutor
@@ -566,6 +578,9 @@ func (k *kafkaWriter) WriteBatch(ctx context.Context, msg service.MessageBatch)
 			Headers:  append(k.buildSystemHeaders(msg[i]), userDefinedHeaders...),
 			Metadata: i, // Store the original index for later reference.
 		}
+		if k.injectTrace && k.saramConf.Version.IsAtLeast(sarama.V0_11_0_0) {
+			injectTraceContext(msg[i], saramaHeaderCarrier{headers: &nextMsg.Headers})
+		}
 		if len(key) > 0 {
 			nextMsg.Key = sarama.ByteEncoder(key)
 		}
@@ -613,7 +628,7 @@ func (k *kafkaWriter) WriteBatch(ctx context.Context, msg service.MessageBatch)
 		msgs = append(msgs, nextMsg)
 	}
 
-	err := producer.SendMessages(msgs)
+	err = producer.SendMessages(msgs)
 	for err != nil {
 		if pErrs, ok := err.(sarama.ProducerErrors); !k.retryAsBatch && ok {
 			if len(pErrs) == 0 {
@@ -0,0 +1,171 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	krpFieldKey        = "key"
+	krpFieldPartitions = "partitions"
+	krpFieldAlgorithm  = "algorithm"
+)
+
+func kafkaRepartitionProcessorConfig() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Integration").
+		Version("4.48.0").
+		Summary("Computes the partition of a Kafka topic which a message key is assigned to and stores it in the `kafka_repartition` metadata field.").
+		Description(`
+The partition is computed with the same hashing algorithms as Kafka producers use, which makes it possible to preserve co-partitioning guarantees when migrating data between clusters or topics with different partition counts. Combine this processor with an output configured with a `+"`manual`"+` partitioner and a `+"`partition`"+` of `+"`${! @kafka_repartition }`"+` in order to write messages to the computed partitions. The `+"`kafka_partition`"+` metadata field is left unchanged, as it identifies the partition that a message was consumed from.
+
+Note that messages with an empty key are assigned a partition based on the hash of an empty key, unlike producers which distribute records without a key across all partitions.
+`).
+		Fields(
+			service.NewInterpolatedStringField(krpFieldKey).
+				Description("The key to compute the partition of.").
+				Default("${! @kafka_key }"),
+			service.NewIntField(krpFieldPartitions).
+				Description("The number of partitions of the destination topic.").
+				Example(12),
+			service.NewStringAnnotatedEnumField(krpFieldAlgorithm, map[string]string{
+				"murmur2": "The 32-bit murmur2 hash used by the default partitioner of the Java client, librdkafka's `murmur2_random` partitioner and the `murmur2_hash` partitioner of the `kafka_franz` and `redpanda` outputs.",
+				"fnv1a":   "The 32-bit FNV-1a hash used by the default partitioner of the Sarama client and the `kafka` output.",
+				"jump":    "A jump consistent hash of the murmur2 hash, which minimises the number of keys that move to a different partition when the number of partitions changes. Only use this algorithm when the keys of a topic are always partitioned by this processor.",
+			}).
+				Description("The hashing algorithm used to map keys to partitions.").
+				Default("murmur2"),
+		).
+		Example("Migrate to more partitions", "Preserves the partitioning scheme of the Java client when writing records to a destination topic with a different number of partitions.", `
+pipeline:
+  processors:
+    - kafka_repartition:
+        partitions: 24
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: ${! @kafka_topic }
+    key: ${! @kafka_key }
+    partitioner: manual
+    partition: ${! @kafka_repartition }
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor("kafka_repartition", kafkaRepartitionProcessorConfig(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newKafkaRepartitionProcessorFromConfig(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type kafkaRepartitionProcessor struct {
+	key        *service.InterpolatedString
+	partitions int32
+	partition  func(key []byte, partitions int32) int32
+}
+
+func newKafkaRepartitionProcessorFromConfig(conf *service.ParsedConfig) (*kafkaRepartitionProcessor, error) {
+	p := kafkaRepartitionProcessor{}
+
+	var err error
+	if p.key, err = conf.FieldInterpolatedString(krpFieldKey); err != nil {
+		return nil, err
+	}
+
+	partitions, err := conf.FieldInt(krpFieldPartitions)
+	if err != nil {
+		return nil, err
+	}
+	if partitions < 1 {
+		return nil, errors.New("partitions must be greater than zero")
+	}
+	p.partitions = int32(partitions)
+
+	algorithm, err := conf.FieldString(krpFieldAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	switch algorithm {
+	case "murmur2":
+		p.partition = murmur2Partition
+	case "fnv1a":
+		p.partition = fnv1aPartition
+	case "jump":
+		p.partition = jumpPartition
+	default:
+		return nil, fmt.Errorf("unknown algorithm: %v", algorithm)
+	}
+
+	return &p, nil
+}
+
+func (p *kafkaRepartitionProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	key, err := p.key.TryBytes(msg)
+	if err != nil {
+		return nil, fmt.Errorf("key interpolation error: %w", err)
+	}
+	msg.MetaSetMut("kafka_repartition", p.partition(key, p.partitions))
+	return service.MessageBatch{msg}, nil
+}
+
+func (p *kafkaRepartitionProcessor) Close(ctx context.Context) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func murmur2Sum(key []byte) uint32 {
+	h := newMurmur2Hash32()
+	_, _ = h.Write(key)
+	return h.Sum32()
+}
+
+func murmur2Partition(key []byte, partitions int32) int32 {
+	return int32(murmur2Sum(key)&0x7fffffff) % partitions
+}
+
+func fnv1aPartition(key []byte, partitions int32) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+	partition := int32(h.Sum32()) % partitions
+	if partition < 0 {
+		partition = -partition
+	}
+	return partition
+}
+
+// jumpPartition implements the jump consistent hash algorithm described in
+// https://arxiv.org/abs/1406.2294.
+func jumpPartition(key []byte, partitions int32) int32 {
+	k := uint64(murmur2Sum(key))
+	var b, j int64 = -1, 0
+	for j < int64(partitions) {
+		b = j
+		k = k*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((k>>33)+1)))
+	}
+	return int32(b)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestKafkaRepartitionAlgorithms(t *testing.T) {
+	franzPartitioner := kgo.StickyKeyPartitioner(nil).ForTopic("foo")
+	saramaPartitioner := sarama.NewHashPartitioner("foo")
+
+	for _, partitions := range []int32{1, 3, 12, 100} {
+		for i := range 1000 {
+			key := []byte(fmt.Sprintf("key-%d", i))
+
+			assert.Equal(t, int32(franzPartitioner.Partition(&kgo.Record{Key: key}, int(partitions))), murmur2Partition(key, partitions))
+
+			exp, err := saramaPartitioner.Partition(&sarama.ProducerMessage{Key: sarama.ByteEncoder(key)}, partitions)
+			require.NoError(t, err)
+			assert.Equal(t, exp, fnv1aPartition(key, partitions))
+
+			// Keys either remain on their partition or move to the new
+			// partition when a partition is added.
+			before, after := jumpPartition(key, partitions), jumpPartition(key, partitions+1)
+			assert.True(t, after == before || after == partitions, "key %s moved from %d to %d", key, before, after)
+		}
+	}
+}
+
+func TestKafkaRepartitionProcessor(t *testing.T) {
+	pConf, err := kafkaRepartitionProcessorConfig().ParseYAML(`
+key: ${! @id }
+partitions: 12
+`, nil)
+	require.NoError(t, err)
+
+	proc, err := newKafkaRepartitionProcessorFromConfig(pConf)
+	require.NoError(t, err)
+
+	msg := service.NewMessage([]byte("hello"))
+	msg.MetaSetMut("id", "foo")
+	msg.MetaSetMut("kafka_partition", 3)
+
+	batch, err := proc.Process(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	partition, ok := batch[0].MetaGetMut("kafka_repartition")
+	require.True(t, ok)
+	assert.Equal(t, murmur2Partition([]byte("foo"), 12), partition)
+
+	// The partition the message was consumed from is left unchanged.
+	partition, ok = batch[0].MetaGetMut("kafka_partition")
+	require.True(t, ok)
+	assert.Equal(t, 3, partition)
+
+	pConf, err = kafkaRepartitionProcessorConfig().ParseYAML(`partitions: 0`, nil)
+	require.NoError(t, err)
+	_, err = newKafkaRepartitionProcessorFromConfig(pConf)
+	require.Error(t, err)
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rackIDAuto is the rack ID value which enables the detection of the rack from
+// the metadata service of the cloud provider the client is running on.
+const rackIDAuto = "auto"
+
+const rackDetectTimeout = 2 * time.Second
+
+// rackDetector detects the zone of the instance the client is running on from
+// the metadata service of a cloud provider.
+type rackDetector struct {
+	name   string
+	detect func(ctx context.Context, client *http.Client, baseURL string) (string, error)
+	url    string
+}
+
+var rackDetectors = []rackDetector{
+	{name: "aws", detect: detectAWSRack, url: "http://169.254.169.254"},
+	{name: "gcp", detect: detectGCPRack, url: "http://metadata.google.internal"},
+	{name: "azure", detect: detectAzureRack, url: "http://169.254.169.254"},
+}
+
+// resolveRackID returns the given rack ID unless it's set to `auto`, in which
+// case the rack is detected from cloud instance metadata.
+func resolveRackID(rackID string) (string, error) {
+	if rackID != rackIDAuto {
+		return rackID, nil
+	}
+
+	ctx, done := context.WithTimeout(context.Background(), rackDetectTimeout)
+	defer done()
+
+	rack, err := detectRackID(ctx, &http.Client{}, rackDetectors)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect rack ID: %w", err)
+	}
+	return rack, nil
+}
+
+// detectRackID queries all detectors in parallel and returns the first rack
+// which is detected.
+func detectRackID(ctx context.Context, client *http.Client, detectors []rackDetector) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		rack string
+		err  error
+	}
+	results := make(chan result, len(detectors))
+	for _, d := range detectors {
+		go func() {
+			rack, err := d.detect(ctx, client, d.url)
+			if err == nil && rack == "" {
+				err = errors.New("empty zone")
+			}
+			if err != nil {
+				err = fmt.Errorf("%v: %w", d.name, err)
+			}
+			results <- result{rack: rack, err: err}
+		}()
+	}
+
+	var errs []error
+	for range detectors {
+		res := <-results
+		if res.err == nil {
+			return res.rack, nil
+		}
+		errs = append(errs, res.err)
+	}
+	return "", errors.Join(errs...)
+}
+
+func metadataRequest(ctx context.Context, client *http.Client, method, url string, headers map[string]string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %v", resp.StatusCode)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// detectAWSRack returns the availability zone ID via IMDSv2, which unlike the
+// zone name is consistent across AWS accounts.
+func detectAWSRack(ctx context.Context, client *http.Client, baseURL string) (string, error) {
+	token, err := metadataRequest(ctx, client, http.MethodPut, baseURL+"/latest/api/token", map[string]string{
+		"X-aws-ec2-metadata-token-ttl-seconds": "60",
+	})
+	if err != nil {
+		return "", err
+	}
+	return metadataRequest(ctx, client, http.MethodGet, baseURL+"/latest/meta-data/placement/availability-zone-id", map[string]string{
+		"X-aws-ec2-metadata-token": token,
+	})
+}
+
+// detectGCPRack returns the zone of the instance, which the metadata server
+// provides in the form `projects/<project number>/zones/<zone>`.
+func detectGCPRack(ctx context.Context, client *http.Client, baseURL string) (string, error) {
+	zone, err := metadataRequest(ctx, client, http.MethodGet, baseURL+"/computeMetadata/v1/instance/zone", map[string]string{
+		"Metadata-Flavor": "Google",
+	})
+	if err != nil {
+		return "", err
+	}
+	return zone[strings.LastIndex(zone, "/")+1:], nil
+}
+
+// detectAzureRack returns the availability zone of the instance, or its fault
+// domain when the instance isn't deployed to an availability zone.
+func detectAzureRack(ctx context.Context, client *http.Client, baseURL string) (string, error) {
+	body, err := metadataRequest(ctx, client, http.MethodGet, baseURL+"/metadata/instance/compute?api-version=2021-02-01", map[string]string{
+		"Metadata": "true",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var compute struct {
+		Zone                string `json:"zone"`
+		PlatformFaultDomain string `json:"platformFaultDomain"`
+	}
+	if err := json.Unmarshal([]byte(body), &compute); err != nil {
+		return "", err
+	}
+	if compute.Zone != "" {
+		return compute.Zone, nil
+	}
+	if _, err := strconv.Atoi(compute.PlatformFaultDomain); err != nil {
+		return "", fmt.Errorf("invalid fault domain %q", compute.PlatformFaultDomain)
+	}
+	return compute.PlatformFaultDomain, nil
+}
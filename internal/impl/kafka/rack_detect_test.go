@@ -0,0 +1,97 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectRackID(t *testing.T) {
+	aws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("footoken"))
+		case r.URL.Path == "/latest/meta-data/placement/availability-zone-id" && r.Header.Get("X-aws-ec2-metadata-token") == "footoken":
+			_, _ = w.Write([]byte("use1-az1"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(aws.Close)
+
+	gcp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/computeMetadata/v1/instance/zone" || r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("projects/123/zones/us-central1-a\n"))
+	}))
+	t.Cleanup(gcp.Close)
+
+	azureBody := `{"zone":"2","platformFaultDomain":"0"}`
+	azure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata/instance/compute" || r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(azureBody))
+	}))
+	t.Cleanup(azure.Close)
+
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(unreachable.Close)
+
+	detectors := func(awsURL, gcpURL, azureURL string) []rackDetector {
+		return []rackDetector{
+			{name: "aws", detect: detectAWSRack, url: awsURL},
+			{name: "gcp", detect: detectGCPRack, url: gcpURL},
+			{name: "azure", detect: detectAzureRack, url: azureURL},
+		}
+	}
+
+	ctx := context.Background()
+
+	rack, err := detectRackID(ctx, http.DefaultClient, detectors(aws.URL, unreachable.URL, unreachable.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "use1-az1", rack)
+
+	rack, err = detectRackID(ctx, http.DefaultClient, detectors(unreachable.URL, gcp.URL, unreachable.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "us-central1-a", rack)
+
+	rack, err = detectRackID(ctx, http.DefaultClient, detectors(unreachable.URL, unreachable.URL, azure.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "2", rack)
+
+	azureBody = `{"zone":"","platformFaultDomain":"1"}`
+	rack, err = detectRackID(ctx, http.DefaultClient, detectors(unreachable.URL, unreachable.URL, azure.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "1", rack)
+
+	_, err = detectRackID(ctx, http.DefaultClient, detectors(unreachable.URL, unreachable.URL, unreachable.URL))
+	require.Error(t, err)
+
+	rack, err = resolveRackID("foo")
+	require.NoError(t, err)
+	assert.Equal(t, "foo", rack)
+}
@@ -15,9 +15,15 @@
 package kafka
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
 
@@ -59,6 +65,12 @@ func SASLFields() *service.ConfigField {
 		service.NewStringField("token").
 			Description("The token to use for a single session's OAUTHBEARER authentication.").
 			Default(""),
+		service.NewStringField("token_source").
+			Description("An alternative to a static `token` for OAUTHBEARER authentication, which is obtained each time a connection authenticates. A source prefixed with `file:` reads the token from a file, which is read again whenever it changes, and a source prefixed with `exec:` runs a shell command and uses its output as the token.").
+			Example("file:/var/run/secrets/kafka/token").
+			Example("exec:gcloud auth print-identity-token").
+			Default("").
+			Version("4.48.0"),
 		service.NewStringMapField("extensions").
 			Description("Key/value pairs to add to OAUTHBEARER authentication requests.").
 			Optional(),
@@ -156,7 +168,34 @@ func oauthSaslFromConfig(c *service.ParsedConfig) (sasl.Mechanism, error) {
 			return nil, err
 		}
 	}
+
+	var source string
+	if c.Contains("token_source") {
+		if source, err = c.FieldString("token_source"); err != nil {
+			return nil, err
+		}
+	}
+	if source == "" {
+		return oauth.Oauth(func(c context.Context) (oauth.Auth, error) {
+			return oauth.Auth{
+				Token:      token,
+				Extensions: extensions,
+			}, nil
+		}), nil
+	}
+	if token != "" {
+		return nil, errors.New("token and token_source cannot be specified simultaneously")
+	}
+
+	tokenFn, err := oauthTokenSourceFn(source)
+	if err != nil {
+		return nil, err
+	}
 	return oauth.Oauth(func(c context.Context) (oauth.Auth, error) {
+		token, err := tokenFn(c)
+		if err != nil {
+			return oauth.Auth{}, err
+		}
 		return oauth.Auth{
 			Token:      token,
 			Extensions: extensions,
@@ -164,6 +203,71 @@ func oauthSaslFromConfig(c *service.ParsedConfig) (sasl.Mechanism, error) {
 	}), nil
 }
 
+func oauthTokenSourceFn(source string) (func(context.Context) (string, error), error) {
+	switch {
+	case strings.HasPrefix(source, "file:"):
+		return (&fileTokenSource{path: strings.TrimPrefix(source, "file:")}).token, nil
+	case strings.HasPrefix(source, "exec:"):
+		command := strings.TrimPrefix(source, "exec:")
+		return func(ctx context.Context) (string, error) {
+			return execTokenSource(ctx, command)
+		}, nil
+	}
+	return nil, fmt.Errorf("token_source %q must be prefixed with either file: or exec:", source)
+}
+
+// fileTokenSource reads an OAUTHBEARER token from a file, which is only read
+// again once its modification time or size changes.
+type fileTokenSource struct {
+	path string
+
+	mut     sync.Mutex
+	modTime time.Time
+	size    int64
+	cached  string
+}
+
+func (f *fileTokenSource) token(context.Context) (string, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	if f.cached != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.cached, nil
+	}
+
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("token file %v is empty", f.path)
+	}
+
+	f.modTime, f.size, f.cached = info.ModTime(), info.Size(), token
+	return token, nil
+}
+
+func execTokenSource(ctx context.Context, command string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", errors.New("token command did not print a token")
+	}
+	return token, nil
+}
+
 func scram256SaslFromConfig(c *service.ParsedConfig) (sasl.Mechanism, error) {
 	username, err := c.FieldString("username")
 	if err != nil {
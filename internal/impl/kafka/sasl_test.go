@@ -16,10 +16,14 @@ package kafka_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/sasl"
 
 	"github.com/redpanda-data/connect/v4/internal/impl/kafka"
 
@@ -150,3 +154,55 @@ sasl:
 	conf := &sarama.Config{}
 	require.Error(t, kafka.ApplySaramaSASLFromParsed(pConf, service.MockResources(), conf))
 }
+
+func TestFranzOAuthBearerTokenSource(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("foo\n"), 0o600))
+
+	mechanism := func(source string) (sasl.Mechanism, error) {
+		pConf, err := service.NewConfigSpec().Field(kafka.SASLFields()).ParseYAML(`
+sasl:
+  - mechanism: OAUTHBEARER
+    token_source: '`+source+`'
+`, nil)
+		require.NoError(t, err)
+
+		mechanisms, err := kafka.SASLMechanismsFromConfig(pConf)
+		if err != nil {
+			return nil, err
+		}
+		require.Len(t, mechanisms, 1)
+		return mechanisms[0], nil
+	}
+	authBytes := func(m sasl.Mechanism) (string, error) {
+		_, b, err := m.Authenticate(context.Background(), "localhost:9092")
+		return string(b), err
+	}
+
+	fileMech, err := mechanism("file:" + tokenPath)
+	require.NoError(t, err)
+	b, err := authBytes(fileMech)
+	require.NoError(t, err)
+	require.Contains(t, b, "auth=Bearer foo\x01")
+
+	// The file is read again once it changes.
+	require.NoError(t, os.WriteFile(tokenPath, []byte("barbaz"), 0o600))
+	require.NoError(t, os.Chtimes(tokenPath, time.Now(), time.Now().Add(time.Minute)))
+	b, err = authBytes(fileMech)
+	require.NoError(t, err)
+	require.Contains(t, b, "auth=Bearer barbaz\x01")
+
+	execMech, err := mechanism("exec:echo qux")
+	require.NoError(t, err)
+	b, err = authBytes(execMech)
+	require.NoError(t, err)
+	require.Contains(t, b, "auth=Bearer qux\x01")
+
+	failingMech, err := mechanism("exec:exit 1")
+	require.NoError(t, err)
+	_, err = authBytes(failingMech)
+	require.Error(t, err)
+
+	_, err = mechanism("nope:foo")
+	require.Error(t, err)
+}
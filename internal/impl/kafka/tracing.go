@@ -0,0 +1,169 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const fieldInjectTraceContext = "inject_trace_context"
+
+func injectTraceContextField() *service.ConfigField {
+	return service.NewBoolField(fieldInjectTraceContext).
+		Description("Whether to add the W3C trace context of each message as `traceparent` and `tracestate` headers, allowing consumers to continue the trace of a message. Any existing headers of the same names added from metadata are replaced.").
+		Version("4.48.0").
+		Default(false).
+		Advanced()
+}
+
+// The W3C trace context propagator is used explicitly rather than the global
+// propagator as record headers are a wire format shared with other services.
+var traceContextPropagator = propagation.TraceContext{}
+
+// franzHeaderCarrier adapts the headers of a franz-go record to the
+// propagation.TextMapCarrier interface.
+type franzHeaderCarrier struct {
+	headers *[]kgo.RecordHeader
+}
+
+func (c franzHeaderCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if strings.EqualFold(h.Key, key) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c franzHeaderCarrier) Set(key, value string) {
+	headers := (*c.headers)[:0]
+	for _, h := range *c.headers {
+		if !strings.EqualFold(h.Key, key) {
+			headers = append(headers, h)
+		}
+	}
+	*c.headers = append(headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+}
+
+func (c franzHeaderCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = h.Key
+	}
+	return keys
+}
+
+// saramaHeaderCarrier adapts the headers of a sarama message to the
+// propagation.TextMapCarrier interface.
+type saramaHeaderCarrier struct {
+	headers *[]sarama.RecordHeader
+}
+
+func (c saramaHeaderCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if strings.EqualFold(string(h.Key), key) {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c saramaHeaderCarrier) Set(key, value string) {
+	headers := (*c.headers)[:0]
+	for _, h := range *c.headers {
+		if !strings.EqualFold(string(h.Key), key) {
+			headers = append(headers, h)
+		}
+	}
+	*c.headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c saramaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(*c.headers))
+	for i, h := range *c.headers {
+		keys[i] = string(h.Key)
+	}
+	return keys
+}
+
+// withExtractedTraceContext returns the message with the trace context found
+// within the provided carrier, if any, attached as the remote parent of the
+// spans subsequently created for it.
+func withExtractedTraceContext(msg *service.Message, carrier propagation.TextMapCarrier) *service.Message {
+	ctx := traceContextPropagator.Extract(msg.Context(), carrier)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return msg
+	}
+	return msg.WithContext(ctx)
+}
+
+// injectTraceContext adds the trace context of a message to the provided
+// carrier, if it has one.
+func injectTraceContext(msg *service.Message, carrier propagation.TextMapCarrier) {
+	traceContextPropagator.Inject(msg.Context(), carrier)
+}
+
+// startBatchSpan starts a span representing a single operation performed on a
+// batch of messages, such as producing it to a broker. As the messages of a
+// batch may belong to any number of traces the span is the root of its own
+// trace and is linked to the span of each message instead.
+func startBatchSpan(ctx context.Context, operationName string, b service.MessageBatch) trace.Span {
+	var prov trace.TracerProvider
+	links := make([]trace.Link, 0, len(b))
+	for _, msg := range b {
+		span := trace.SpanFromContext(msg.Context())
+		if !span.SpanContext().IsValid() {
+			continue
+		}
+		if prov == nil && span.IsRecording() {
+			prov = span.TracerProvider()
+		}
+		links = append(links, trace.Link{SpanContext: span.SpanContext()})
+	}
+	if prov == nil {
+		return trace.SpanFromContext(context.Background())
+	}
+
+	_, span := prov.Tracer("benthos").Start(ctx, operationName,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.Int("messaging.batch.message_count", len(b)),
+		),
+	)
+	return span
+}
+
+// endBatchSpan ends a span started with startBatchSpan, recording the error of
+// the operation if it failed.
+func endBatchSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
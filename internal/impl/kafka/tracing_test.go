@@ -0,0 +1,176 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	testTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testSpanID      = "00f067aa0ba902b7"
+	testTraceParent = "00-" + testTraceID + "-" + testSpanID + "-01"
+)
+
+func TestFranzRecordTraceContextExtraction(t *testing.T) {
+	record := &kgo.Record{
+		Topic: "foo",
+		Value: []byte("hello"),
+		Headers: []kgo.RecordHeader{
+			{Key: "Traceparent", Value: []byte(testTraceParent)},
+			{Key: "tracestate", Value: []byte("vendor=value")},
+		},
+	}
+
+	for name, msg := range map[string]*service.Message{
+		"v0": FranzRecordToMessageV0(record, false),
+		"v1": FranzRecordToMessageV1(record),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sc := trace.SpanContextFromContext(msg.Context())
+			require.True(t, sc.IsValid())
+			assert.True(t, sc.IsRemote())
+			assert.Equal(t, testTraceID, sc.TraceID().String())
+			assert.Equal(t, testSpanID, sc.SpanID().String())
+			assert.Equal(t, "vendor=value", sc.TraceState().String())
+
+			// Headers remain available as metadata.
+			v, _ := msg.MetaGet("Traceparent")
+			assert.Equal(t, testTraceParent, v)
+		})
+	}
+
+	msg := FranzRecordToMessageV1(&kgo.Record{
+		Value:   []byte("hello"),
+		Headers: []kgo.RecordHeader{{Key: "traceparent", Value: []byte("not a trace")}},
+	})
+	assert.False(t, trace.SpanContextFromContext(msg.Context()).IsValid())
+}
+
+func TestSaramaMessageTraceContextExtraction(t *testing.T) {
+	msg := dataToPart(0, &sarama.ConsumerMessage{
+		Value: []byte("hello"),
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte("traceparent"), Value: []byte(testTraceParent)},
+		},
+		Timestamp: time.Unix(10, 0),
+	}, false)
+
+	sc := trace.SpanContextFromContext(msg.Context())
+	require.True(t, sc.IsValid())
+	assert.Equal(t, testTraceID, sc.TraceID().String())
+	assert.Equal(t, testSpanID, sc.SpanID().String())
+}
+
+func TestFranzWriterInjectTraceContext(t *testing.T) {
+	conf, err := franzKafkaOutputConfig().ParseYAML(`
+seed_brokers: [ foo:1234 ]
+topic: foo
+inject_trace_context: true
+metadata:
+  include_patterns: [ ".*" ]
+`, nil)
+	require.NoError(t, err)
+
+	w, err := NewFranzWriterFromConfig(conf, NewFranzWriterHooks(nil))
+	require.NoError(t, err)
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "foo")
+	defer span.End()
+
+	traced := service.NewMessage([]byte("traced")).WithContext(ctx)
+	traced.MetaSetMut("traceparent", testTraceParent)
+	traced.MetaSetMut("other", "bar")
+
+	records, err := w.BatchToRecords(context.Background(), service.MessageBatch{
+		traced,
+		service.NewMessage([]byte("untraced")),
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	headers := map[string][]string{}
+	for _, h := range records[0].Headers {
+		headers[h.Key] = append(headers[h.Key], string(h.Value))
+	}
+	sc := span.SpanContext()
+	assert.Equal(t, map[string][]string{
+		"traceparent": {"00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-01"},
+		"other":       {"bar"},
+	}, headers)
+
+	assert.Empty(t, records[1].Headers)
+}
+
+func TestSaramaHeaderCarrier(t *testing.T) {
+	headers := []sarama.RecordHeader{
+		{Key: []byte("TraceParent"), Value: []byte("old")},
+		{Key: []byte("foo"), Value: []byte("bar")},
+	}
+	c := saramaHeaderCarrier{headers: &headers}
+
+	assert.Equal(t, "old", c.Get("traceparent"))
+	c.Set("traceparent", "new")
+	assert.Equal(t, []sarama.RecordHeader{
+		{Key: []byte("foo"), Value: []byte("bar")},
+		{Key: []byte("traceparent"), Value: []byte("new")},
+	}, headers)
+	assert.Equal(t, []string{"foo", "traceparent"}, c.Keys())
+}
+
+func TestBatchSpanLinks(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctxA, spanA := tp.Tracer("test").Start(context.Background(), "a")
+	ctxB, spanB := tp.Tracer("test").Start(context.Background(), "b")
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("a")).WithContext(ctxA),
+		service.NewMessage([]byte("b")).WithContext(ctxB),
+		service.NewMessage([]byte("c")),
+	}
+
+	span := startBatchSpan(context.Background(), "kafka_produce", batch)
+	endBatchSpan(span, nil)
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, "kafka_produce", ended[0].Name())
+	assert.Equal(t, trace.SpanKindProducer, ended[0].SpanKind())
+
+	links := ended[0].Links()
+	require.Len(t, links, 2)
+	assert.Equal(t, spanA.SpanContext(), links[0].SpanContext)
+	assert.Equal(t, spanB.SpanContext(), links[1].SpanContext)
+	assert.NotEqual(t, spanA.SpanContext().TraceID(), ended[0].SpanContext().TraceID())
+
+	// Without any recording spans no batch span is created.
+	span = startBatchSpan(context.Background(), "kafka_produce", service.MessageBatch{service.NewMessage(nil)})
+	assert.False(t, span.IsRecording())
+}
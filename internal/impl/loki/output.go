@@ -0,0 +1,444 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loki
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	loFieldURL         = "url"
+	loFieldLabels      = "labels"
+	loFieldLine        = "line"
+	loFieldTimestamp   = "timestamp"
+	loFieldTenantID    = "tenant_id"
+	loFieldCompression = "compression"
+	loFieldTimeout     = "timeout"
+	loFieldTLS         = "tls"
+	loFieldBatching    = "batching"
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.48.0").
+		Categories("Services").
+		Summary("Pushes messages as log lines to https://grafana.com/oss/loki/[Grafana Loki^].").
+		Description(`
+Messages of a batch are grouped into streams by the labels returned by the `+"`labels`"+` mapping and sent to the Loki push API in a single request per tenant. Loki rejects entries of a stream that are older than the newest entry it has already received for that stream (unless out-of-order writes are enabled), and therefore the entries of each stream are sorted by their timestamp before being sent. Ordering across batches is only preserved when `+"`max_in_flight`"+` is 1, which is the default.
+
+Labels should have a low cardinality as each distinct set of labels creates a new stream within Loki. High cardinality values such as IDs are best left within the log line itself.
+
+== Multi-tenancy
+
+When `+"`tenant_id`"+` is set its value is sent as the `+"`X-Scope-OrgID`"+` header, which identifies the tenant that entries are written to when Loki runs in multi-tenant mode. The tenant is resolved for each message and messages of a batch belonging to different tenants are sent in separate requests.
+`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewURLField(loFieldURL).
+				Description("The URL of the Loki push API.").
+				Example("http://localhost:3100/loki/api/v1/push"),
+			service.NewBloblangField(loFieldLabels).
+				Description("A Bloblang mapping executed for each message that must return an object of labels identifying the stream the message belongs to. At least one label is required.").
+				Example(`root.app = "checkout"
+root.level = this.level.or("info")`).
+				Example(`root.topic = @kafka_topic`),
+			service.NewInterpolatedStringField(loFieldLine).
+				Description("The log line to write for each message.").
+				Default("${! content() }"),
+			service.NewBloblangField(loFieldTimestamp).
+				Description("An optional Bloblang mapping that returns the timestamp of each entry, either as a timestamp value, a unix timestamp in seconds or an RFC 3339 string. When not set the time at which a batch is written is used for all of its messages.").
+				Example(`root = this.created_at`).
+				Example(`root = @kafka_timestamp_ms.number() / 1000`).
+				Optional(),
+			service.NewInterpolatedStringField(loFieldTenantID).
+				Description("An optional tenant ID sent as the `X-Scope-OrgID` header.").
+				Example("team-a").
+				Example(`${! @tenant }`).
+				Optional(),
+			service.NewStringEnumField(loFieldCompression, "none", "gzip").
+				Description("The compression algorithm to use for request payloads.").
+				Default("gzip").
+				Advanced(),
+			service.NewDurationField(loFieldTimeout).
+				Description("The maximum period to wait for a request to complete.").
+				Default("30s").
+				Advanced(),
+			service.NewTLSToggledField(loFieldTLS),
+		).
+		Fields(service.NewHTTPRequestAuthSignerFields()...).
+		Fields(
+			service.NewOutputMaxInFlightField().
+				Description("The maximum number of batches to have in flight at a given time. Batches sent in parallel can deliver the entries of a stream out of order, which Loki rejects unless out-of-order writes are enabled, and therefore this should only be increased when they are.").
+				Default(1),
+			service.NewBatchPolicyField(loFieldBatching),
+		).
+		Example("Kafka to Loki", "Forward logs consumed from Kafka into Loki streams labelled by service and level, using the record timestamp for each entry.", `
+input:
+  redpanda:
+    seed_brokers: [ localhost:9092 ]
+    topics: [ app_logs ]
+    consumer_group: loki_forwarder
+
+output:
+  loki:
+    url: http://localhost:3100/loki/api/v1/push
+    labels: |
+      root.service = this.service
+      root.level = this.level.or("info")
+    line: ${! this.message }
+    timestamp: root = @kafka_timestamp_ms.number() / 1000
+    tenant_id: platform
+    batching:
+      count: 1000
+      period: 1s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("loki", outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(loFieldBatching); err != nil {
+				return
+			}
+			out, err = newOutput(conf, mgr)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type output struct {
+	url       string
+	labels    *bloblang.Executor
+	line      *service.InterpolatedString
+	timestamp *bloblang.Executor
+	tenantID  *service.InterpolatedString
+	gzip      bool
+
+	client    *http.Client
+	reqSigner func(fs.FS, *http.Request) error
+	fs        fs.FS
+
+	nowFn func() time.Time
+}
+
+func newOutput(conf *service.ParsedConfig, mgr *service.Resources) (*output, error) {
+	o := &output{
+		client: &http.Client{},
+		fs:     mgr.FS(),
+		nowFn:  time.Now,
+	}
+
+	var err error
+	if o.url, err = conf.FieldString(loFieldURL); err != nil {
+		return nil, err
+	}
+	if o.labels, err = conf.FieldBloblang(loFieldLabels); err != nil {
+		return nil, err
+	}
+	if o.line, err = conf.FieldInterpolatedString(loFieldLine); err != nil {
+		return nil, err
+	}
+	if conf.Contains(loFieldTimestamp) {
+		if o.timestamp, err = conf.FieldBloblang(loFieldTimestamp); err != nil {
+			return nil, err
+		}
+	}
+	if conf.Contains(loFieldTenantID) {
+		if o.tenantID, err = conf.FieldInterpolatedString(loFieldTenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	compression, err := conf.FieldString(loFieldCompression)
+	if err != nil {
+		return nil, err
+	}
+	o.gzip = compression == "gzip"
+
+	if o.client.Timeout, err = conf.FieldDuration(loFieldTimeout); err != nil {
+		return nil, err
+	}
+
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(loFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	if tlsEnabled {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConf
+		o.client.Transport = transport
+	}
+
+	if o.reqSigner, err = conf.HTTPRequestAuthSignerFromParsed(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+//------------------------------------------------------------------------------
+
+type entry struct {
+	ts   time.Time
+	line string
+}
+
+type stream struct {
+	labels  map[string]string
+	entries []entry
+}
+
+// MarshalJSON encodes a stream in the format expected by the push API, where
+// each entry is a tuple of a nanosecond timestamp string and the line.
+func (s *stream) MarshalJSON() ([]byte, error) {
+	values := make([][2]string, len(s.entries))
+	for i, e := range s.entries {
+		values[i] = [2]string{strconv.FormatInt(e.ts.UnixNano(), 10), e.line}
+	}
+	return json.Marshal(struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}{Stream: s.labels, Values: values})
+}
+
+// tenantStreams holds the streams of a batch that belong to a single tenant,
+// in the order in which they were first seen.
+type tenantStreams struct {
+	tenant  string
+	streams []*stream
+	byKey   map[string]*stream
+}
+
+func labelsFor(exec *service.MessageBatchBloblangExecutor, i int) (map[string]string, error) {
+	msg, err := exec.Query(i)
+	if err != nil {
+		return nil, fmt.Errorf("labels mapping failed: %w", err)
+	}
+	if msg == nil {
+		return nil, errors.New("labels mapping deleted the message")
+	}
+	v, err := msg.AsStructured()
+	if err != nil {
+		return nil, fmt.Errorf("labels mapping failed: %w", err)
+	}
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("labels mapping returned %T, expected an object", v)
+	}
+	if len(obj) == 0 {
+		return nil, errors.New("labels mapping must return at least one label")
+	}
+	labels := make(map[string]string, len(obj))
+	for k, v := range obj {
+		labels[k] = bloblang.ValueToString(v)
+	}
+	return labels, nil
+}
+
+func timestampFor(exec *service.MessageBatchBloblangExecutor, i int, now time.Time) (time.Time, error) {
+	if exec == nil {
+		return now, nil
+	}
+	msg, err := exec.Query(i)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp mapping failed: %w", err)
+	}
+	if msg == nil {
+		return now, nil
+	}
+	// Mappings that return strings produce raw message contents rather than a
+	// structured value.
+	v, err := msg.AsStructured()
+	if err != nil {
+		if v, err = msg.AsBytes(); err != nil {
+			return time.Time{}, fmt.Errorf("timestamp mapping failed: %w", err)
+		}
+	}
+	ts, err := bloblang.ValueAsTimestamp(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp mapping failed: %w", err)
+	}
+	return ts, nil
+}
+
+func labelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(labels[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+func (o *output) groupBatch(batch service.MessageBatch) ([]*tenantStreams, error) {
+	now := o.nowFn()
+
+	labelsExec := batch.BloblangExecutor(o.labels)
+	lineExec := batch.InterpolationExecutor(o.line)
+	var tsExec *service.MessageBatchBloblangExecutor
+	if o.timestamp != nil {
+		tsExec = batch.BloblangExecutor(o.timestamp)
+	}
+	var tenantExec *service.MessageBatchInterpolationExecutor
+	if o.tenantID != nil {
+		tenantExec = batch.InterpolationExecutor(o.tenantID)
+	}
+
+	var tenants []*tenantStreams
+	byTenant := map[string]*tenantStreams{}
+	for i := range batch {
+		tenant := ""
+		if tenantExec != nil {
+			var err error
+			if tenant, err = tenantExec.TryString(i); err != nil {
+				return nil, fmt.Errorf("tenant_id interpolation error: %w", err)
+			}
+		}
+		labels, err := labelsFor(labelsExec, i)
+		if err != nil {
+			return nil, err
+		}
+		ts, err := timestampFor(tsExec, i, now)
+		if err != nil {
+			return nil, err
+		}
+		line, err := lineExec.TryString(i)
+		if err != nil {
+			return nil, fmt.Errorf("line interpolation error: %w", err)
+		}
+
+		t, exists := byTenant[tenant]
+		if !exists {
+			t = &tenantStreams{tenant: tenant, byKey: map[string]*stream{}}
+			byTenant[tenant] = t
+			tenants = append(tenants, t)
+		}
+		key := labelsKey(labels)
+		s, exists := t.byKey[key]
+		if !exists {
+			s = &stream{labels: labels}
+			t.byKey[key] = s
+			t.streams = append(t.streams, s)
+		}
+		s.entries = append(s.entries, entry{ts: ts, line: line})
+	}
+
+	for _, t := range tenants {
+		for _, s := range t.streams {
+			slices.SortStableFunc(s.entries, func(a, b entry) int {
+				return a.ts.Compare(b.ts)
+			})
+		}
+	}
+	return tenants, nil
+}
+
+func (o *output) push(ctx context.Context, t *tenantStreams) error {
+	var body bytes.Buffer
+	var w io.Writer = &body
+	var gz *gzip.Writer
+	if o.gzip {
+		gz = gzip.NewWriter(&body)
+		w = gz
+	}
+	if err := json.NewEncoder(w).Encode(map[string]any{"streams": t.streams}); err != nil {
+		return fmt.Errorf("failed to marshal streams: %w", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to compress streams: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url, &body)
+	if err != nil {
+		return fmt.Errorf("failed to construct HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if t.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", t.tenant)
+	}
+	if err := o.reqSigner(o.fs, req); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("HTTP request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (*output) Connect(context.Context) error {
+	return nil
+}
+
+func (o *output) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	tenants, err := o.groupBatch(batch)
+	if err != nil {
+		return err
+	}
+	for _, t := range tenants {
+		if err := o.push(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *output) Close(context.Context) error {
+	o.client.CloseIdleConnections()
+	return nil
+}
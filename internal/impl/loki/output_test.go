@@ -0,0 +1,189 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loki
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type pushRequest struct {
+	tenant string
+	user   string
+	body   any
+}
+
+type fakeLoki struct {
+	mut      sync.Mutex
+	requests []pushRequest
+}
+
+func (f *fakeLoki) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+
+	var v any
+	if err := json.NewDecoder(body).Decode(&v); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	user, _, _ := r.BasicAuth()
+	f.requests = append(f.requests, pushRequest{
+		tenant: r.Header.Get("X-Scope-OrgID"),
+		user:   user,
+		body:   v,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func testOutput(t *testing.T, extra string) (*output, *fakeLoki) {
+	t.Helper()
+
+	loki := &fakeLoki{}
+	srv := httptest.NewServer(loki)
+	t.Cleanup(srv.Close)
+
+	conf, err := outputSpec().ParseYAML(`
+url: `+srv.URL+`/loki/api/v1/push
+`+extra, nil)
+	require.NoError(t, err)
+
+	out, err := newOutput(conf, service.MockResources())
+	require.NoError(t, err)
+	out.nowFn = func() time.Time { return time.Unix(1700000000, 0) }
+	t.Cleanup(func() { require.NoError(t, out.Close(context.Background())) })
+	return out, loki
+}
+
+func TestOutputStreamsSortedByTimestamp(t *testing.T) {
+	out, loki := testOutput(t, `
+labels: |
+  root.app = this.app
+line: ${! this.msg }
+timestamp: root = this.ts
+basic_auth:
+  enabled: true
+  username: foo
+  password: bar
+`)
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte(`{"app":"a","msg":"third","ts":30}`)),
+		service.NewMessage([]byte(`{"app":"b","msg":"only","ts":20}`)),
+		service.NewMessage([]byte(`{"app":"a","msg":"first","ts":10}`)),
+		service.NewMessage([]byte(`{"app":"a","msg":"second","ts":"1970-01-01T00:00:20Z"}`)),
+	}
+	require.NoError(t, out.WriteBatch(context.Background(), batch))
+
+	require.Len(t, loki.requests, 1)
+	assert.Equal(t, "", loki.requests[0].tenant)
+	assert.Equal(t, "foo", loki.requests[0].user)
+	assert.Equal(t, map[string]any{
+		"streams": []any{
+			map[string]any{
+				"stream": map[string]any{"app": "a"},
+				"values": []any{
+					[]any{"10000000000", "first"},
+					[]any{"20000000000", "second"},
+					[]any{"30000000000", "third"},
+				},
+			},
+			map[string]any{
+				"stream": map[string]any{"app": "b"},
+				"values": []any{
+					[]any{"20000000000", "only"},
+				},
+			},
+		},
+	}, loki.requests[0].body)
+}
+
+func TestOutputTenants(t *testing.T) {
+	out, loki := testOutput(t, `
+labels: 'root.level = @level'
+tenant_id: ${! @tenant }
+compression: none
+`)
+
+	newMsg := func(content, tenant, level string) *service.Message {
+		m := service.NewMessage([]byte(content))
+		m.MetaSetMut("tenant", tenant)
+		m.MetaSetMut("level", level)
+		return m
+	}
+	require.NoError(t, out.WriteBatch(context.Background(), service.MessageBatch{
+		newMsg("a", "team-a", "info"),
+		newMsg("b", "team-b", "info"),
+		newMsg("c", "team-a", "error"),
+	}))
+
+	require.Len(t, loki.requests, 2)
+	assert.Equal(t, "team-a", loki.requests[0].tenant)
+	assert.Equal(t, map[string]any{
+		"streams": []any{
+			map[string]any{
+				"stream": map[string]any{"level": "info"},
+				"values": []any{[]any{"1700000000000000000", "a"}},
+			},
+			map[string]any{
+				"stream": map[string]any{"level": "error"},
+				"values": []any{[]any{"1700000000000000000", "c"}},
+			},
+		},
+	}, loki.requests[0].body)
+	assert.Equal(t, "team-b", loki.requests[1].tenant)
+	assert.Equal(t, map[string]any{
+		"streams": []any{
+			map[string]any{
+				"stream": map[string]any{"level": "info"},
+				"values": []any{[]any{"1700000000000000000", "b"}},
+			},
+		},
+	}, loki.requests[1].body)
+}
+
+func TestOutputLabelErrors(t *testing.T) {
+	out, loki := testOutput(t, `
+labels: 'root = this.labels'
+`)
+
+	for _, content := range []string{`{"labels":{}}`, `{"labels":"nope"}`, `not json`} {
+		err := out.WriteBatch(context.Background(), service.MessageBatch{service.NewMessage([]byte(content))})
+		require.Error(t, err, content)
+	}
+	assert.Empty(t, loki.requests)
+}
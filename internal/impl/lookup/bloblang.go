@@ -0,0 +1,53 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"fmt"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func init() {
+	lookupSpec := bloblang.NewPluginSpec().
+		Beta().
+		Category("General").
+		Version("4.48.0").
+		Description("Returns the row of a lookup table loaded by a `lookup_table` processor, or `null` if the key does not exist. An error is returned if the table has not been loaded.").
+		Param(bloblang.NewStringParam("table").Description("The name of the table.")).
+		Param(bloblang.NewAnyParam("key").Description("The key of the row to obtain."))
+
+	if err := bloblang.RegisterFunctionV2("lookup", lookupSpec, func(args *bloblang.ParsedParams) (bloblang.Function, error) {
+		name, err := args.GetString("table")
+		if err != nil {
+			return nil, err
+		}
+		key, err := args.Get("key")
+		if err != nil {
+			return nil, err
+		}
+		keyStr := keyString(key)
+		return func() (any, error) {
+			t, ok := getTable(name)
+			if !ok {
+				return nil, fmt.Errorf("lookup table %v does not exist", name)
+			}
+			v, _ := t.get(keyStr)
+			return v, nil
+		}, nil
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func newTestProcessor(t *testing.T, yaml string) *lookupTableProcessor {
+	t.Helper()
+	conf, err := lookupTableProcessorSpec().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+	p, err := newLookupTableFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = p.Close(context.Background())
+	})
+	return p
+}
+
+func processString(t *testing.T, p *lookupTableProcessor, s string) string {
+	t.Helper()
+	batch, err := p.Process(context.Background(), service.NewMessage([]byte(s)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	b, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		output map[string]any
+		err    string
+	}{
+		{
+			name:   "csv",
+			format: "csv",
+			input:  "id,name\n1,foo\n2,bar\n",
+			output: map[string]any{
+				"1": map[string]any{"id": "1", "name": "foo"},
+				"2": map[string]any{"id": "2", "name": "bar"},
+			},
+		},
+		{
+			name:   "csv missing key",
+			format: "csv",
+			input:  "name\nfoo\n",
+			err:    "key column id not found in headers",
+		},
+		{
+			name:   "json object",
+			format: "json",
+			input:  `{"a":1,"b":{"c":2}}`,
+			output: map[string]any{"a": 1.0, "b": map[string]any{"c": 2.0}},
+		},
+		{
+			name:   "json array",
+			format: "json",
+			input:  `[{"id":1,"name":"foo"},{"id":"x","name":"bar"}]`,
+			output: map[string]any{
+				"1": map[string]any{"id": 1.0, "name": "foo"},
+				"x": map[string]any{"id": "x", "name": "bar"},
+			},
+		},
+		{
+			name:   "json array missing key",
+			format: "json",
+			input:  `[{"name":"foo"}]`,
+			err:    "index 0: key field id not found",
+		},
+		{
+			name:   "ndjson",
+			format: "ndjson",
+			input:  "{\"id\":1,\"name\":\"foo\"}\n\n{\"id\":2,\"name\":\"bar\"}\n",
+			output: map[string]any{
+				"1": map[string]any{"id": 1.0, "name": "foo"},
+				"2": map[string]any{"id": 2.0, "name": "bar"},
+			},
+		},
+		{
+			name:   "ndjson not object",
+			format: "ndjson",
+			input:  "{\"id\":1}\n[]\n",
+			err:    "line 2: expected an object, got []interface {}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := parseTable(test.format, "id", []byte(test.input))
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.output, m)
+		})
+	}
+}
+
+func TestLookupTableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "customers.csv")
+	require.NoError(t, os.WriteFile(path, []byte("id,name\n1,foo\n2,bar\n"), 0o644))
+
+	p := newTestProcessor(t, `
+name: file_customers
+format: csv
+file:
+  path: `+path+`
+refresh_interval: 10ms
+mapping: 'root.name = lookup("file_customers", this.id).name'
+`)
+
+	assert.Equal(t, `{"name":"foo"}`, processString(t, p, `{"id":1}`))
+	assert.Equal(t, `{"name":"bar"}`, processString(t, p, `{"id":"2"}`))
+	assert.Equal(t, `{"name":null}`, processString(t, p, `{"id":3}`))
+
+	require.NoError(t, os.WriteFile(path, []byte("id,name\n1,baz\n3,qux\n"), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+
+	assert.Eventually(t, func() bool {
+		return processString(t, p, `{"id":3}`) == `{"name":"qux"}`
+	}, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, `{"name":"baz"}`, processString(t, p, `{"id":1}`))
+}
+
+func TestLookupTableHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "bar", r.Header.Get("X-Foo"))
+		_, _ = w.Write([]byte(`[{"code":"gb","name":"United Kingdom"},{"code":"fr","name":"France"}]`))
+	}))
+	defer srv.Close()
+
+	p := newTestProcessor(t, `
+name: http_countries
+key: code
+http:
+  url: `+srv.URL+`
+  headers:
+    X-Foo: bar
+`)
+	assert.Nil(t, p.mapping)
+	assert.Equal(t, `{"code":"fr"}`, processString(t, p, `{"code":"fr"}`))
+
+	exec, err := bloblang.Parse(`root = lookup("http_countries", this.code).name`)
+	require.NoError(t, err)
+	v, err := exec.Query(map[string]any{"code": "gb"})
+	require.NoError(t, err)
+	assert.Equal(t, "United Kingdom", v)
+}
+
+func TestLookupTableHTTPErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	conf, err := lookupTableProcessorSpec().ParseYAML(`
+name: http_broken
+http:
+  url: `+srv.URL+`
+`, nil)
+	require.NoError(t, err)
+	_, err = newLookupTableFromConfig(conf, service.MockResources())
+	require.ErrorContains(t, err, "failed to load lookup table http_broken: unexpected status code 500")
+
+	_, exists := getTable("http_broken")
+	assert.False(t, exists)
+}
+
+func TestLookupTableSQL(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "lookup.db")
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER, name TEXT);
+INSERT INTO users VALUES (1, 'foo'), (2, 'bar');`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	p := newTestProcessor(t, `
+name: sql_users
+sql:
+  driver: sqlite
+  dsn: `+dsn+`
+  query: SELECT id, name FROM users
+mapping: 'root = lookup("sql_users", this.user_id)'
+`)
+	assert.Equal(t, `{"id":2,"name":"bar"}`, processString(t, p, `{"user_id":2}`))
+}
+
+func TestLookupTableShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "table.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"a":"b"}`), 0o644))
+
+	conf, err := lookupTableProcessorSpec().ParseYAML(`
+name: shared
+file:
+  path: `+path+`
+`, nil)
+	require.NoError(t, err)
+
+	p1, err := newLookupTableFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	p2, err := newLookupTableFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	assert.Same(t, p1.table, p2.table)
+
+	require.NoError(t, p1.Close(context.Background()))
+	_, exists := getTable("shared")
+	assert.True(t, exists)
+
+	require.NoError(t, p2.Close(context.Background()))
+	_, exists = getTable("shared")
+	assert.False(t, exists)
+
+	exec, err := bloblang.Parse(`root = lookup("shared", "a")`)
+	require.NoError(t, err)
+	_, err = exec.Query(nil)
+	require.ErrorContains(t, err, "lookup table shared does not exist")
+}
+
+func TestLookupTableSources(t *testing.T) {
+	for name, yaml := range map[string]string{
+		"none": `name: foo`,
+		"multiple": `
+name: foo
+file:
+  path: ./foo.json
+http:
+  url: http://localhost
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := lookupTableProcessorSpec().ParseYAML(yaml, nil)
+			require.NoError(t, err)
+			_, err = newLookupTableFromConfig(conf, service.MockResources())
+			require.EqualError(t, err, "exactly one of file, http or sql must be specified")
+		})
+	}
+}
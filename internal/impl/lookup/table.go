@@ -0,0 +1,421 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lookup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// tableLoader loads the full contents of a lookup table.
+type tableLoader interface {
+	// load returns the table contents, or a nil map and no error when the
+	// source has not changed since the last successful load.
+	load(ctx context.Context) (map[string]any, error)
+	close() error
+}
+
+// table is a named lookup table that is shared by all processors that
+// reference it, and is periodically reloaded in the background.
+type table struct {
+	name   string
+	loader tableLoader
+	log    *service.Logger
+	data   atomic.Pointer[map[string]any]
+	refs   int
+
+	shutSig chan struct{}
+	done    chan struct{}
+}
+
+func (t *table) get(key string) (any, bool) {
+	data := t.data.Load()
+	if data == nil {
+		return nil, false
+	}
+	v, ok := (*data)[key]
+	return v, ok
+}
+
+func (t *table) reload(ctx context.Context) error {
+	data, err := t.loader.load(ctx)
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		t.data.Store(&data)
+	}
+	return nil
+}
+
+func (t *table) loop(interval time.Duration) {
+	defer close(t.done)
+	if interval <= 0 {
+		<-t.shutSig
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-t.shutSig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.reload(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				t.log.Errorf("Failed to reload lookup table %v, keeping previous contents: %v", t.name, err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+var tables = struct {
+	sync.Mutex
+	m map[string]*table
+}{m: map[string]*table{}}
+
+// acquireTable returns the table of a given name, creating and loading it if
+// it does not already exist. Each call must be paired with releaseTable.
+func acquireTable(ctx context.Context, name string, interval time.Duration, log *service.Logger, newLoader func() (tableLoader, error)) (*table, error) {
+	tables.Lock()
+	defer tables.Unlock()
+
+	if t, exists := tables.m[name]; exists {
+		t.refs++
+		return t, nil
+	}
+
+	loader, err := newLoader()
+	if err != nil {
+		return nil, err
+	}
+	t := &table{
+		name:    name,
+		loader:  loader,
+		log:     log,
+		refs:    1,
+		shutSig: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := t.reload(ctx); err != nil {
+		_ = loader.close()
+		return nil, fmt.Errorf("failed to load lookup table %v: %w", name, err)
+	}
+	go t.loop(interval)
+
+	tables.m[name] = t
+	return t, nil
+}
+
+// releaseTable drops a reference to a table, and stops it once no references
+// remain.
+func releaseTable(ctx context.Context, t *table) error {
+	tables.Lock()
+	t.refs--
+	last := t.refs == 0
+	if last {
+		delete(tables.m, t.name)
+	}
+	tables.Unlock()
+
+	if !last {
+		return nil
+	}
+	close(t.shutSig)
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return t.loader.close()
+}
+
+func getTable(name string) (*table, bool) {
+	tables.Lock()
+	defer tables.Unlock()
+	t, ok := tables.m[name]
+	return t, ok
+}
+
+//------------------------------------------------------------------------------
+
+// parseTable parses a table from raw data of a given format. Rows of csv,
+// ndjson and json array data are indexed by the value of the key field,
+// whereas json objects are used as the table directly.
+func parseTable(format, key string, data []byte) (map[string]any, error) {
+	switch format {
+	case "csv":
+		return parseCSVTable(key, data)
+	case "ndjson":
+		m := map[string]any{}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, 64*1024*1024)
+		for i := 0; scanner.Scan(); i++ {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var row any
+			if err := json.Unmarshal(line, &row); err != nil {
+				return nil, fmt.Errorf("line %v: %w", i+1, err)
+			}
+			if err := addRow(m, key, row); err != nil {
+				return nil, fmt.Errorf("line %v: %w", i+1, err)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case "json":
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		switch t := v.(type) {
+		case map[string]any:
+			return t, nil
+		case []any:
+			m := make(map[string]any, len(t))
+			for i, row := range t {
+				if err := addRow(m, key, row); err != nil {
+					return nil, fmt.Errorf("index %v: %w", i, err)
+				}
+			}
+			return m, nil
+		}
+		return nil, fmt.Errorf("expected an object or array, got %T", v)
+	}
+	return nil, fmt.Errorf("unsupported format: %v", format)
+}
+
+func parseCSVTable(key string, data []byte) (map[string]any, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+
+	headers, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return map[string]any{}, nil
+		}
+		return nil, err
+	}
+	keyIndex := -1
+	for i, h := range headers {
+		if h == key {
+			keyIndex = i
+		}
+	}
+	if keyIndex < 0 {
+		return nil, fmt.Errorf("key column %v not found in headers", key)
+	}
+
+	m := map[string]any{}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(headers))
+		for i, h := range headers {
+			if i < len(record) {
+				row[h] = record[i]
+			}
+		}
+		m[record[keyIndex]] = row
+	}
+}
+
+func addRow(m map[string]any, key string, row any) error {
+	obj, ok := row.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected an object, got %T", row)
+	}
+	k, exists := obj[key]
+	if !exists {
+		return fmt.Errorf("key field %v not found", key)
+	}
+	m[keyString(k)] = obj
+	return nil
+}
+
+// keyString converts a value into the string form used to index tables.
+func keyString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+//------------------------------------------------------------------------------
+
+type fileLoader struct {
+	fs      *service.FS
+	path    string
+	format  string
+	key     string
+	modTime time.Time
+}
+
+func (f *fileLoader) load(context.Context) (map[string]any, error) {
+	info, err := f.fs.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if !f.modTime.IsZero() && info.ModTime().Equal(f.modTime) {
+		return nil, nil
+	}
+
+	file, err := f.fs.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseTable(f.format, f.key, data)
+	if err != nil {
+		return nil, err
+	}
+	f.modTime = info.ModTime()
+	return m, nil
+}
+
+func (f *fileLoader) close() error {
+	return nil
+}
+
+type httpLoader struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+	format  string
+	key     string
+}
+
+func (h *httpLoader) load(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status code %v: %s", res.StatusCode, data)
+	}
+	return parseTable(h.format, h.key, data)
+}
+
+func (h *httpLoader) close() error {
+	return nil
+}
+
+type sqlLoader struct {
+	db    *sql.DB
+	query string
+	key   string
+}
+
+func (s *sqlLoader) load(ctx context.Context) (map[string]any, error) {
+	rows, err := s.db.QueryContext(ctx, s.query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]any{}
+	values := make([]any, len(columns))
+	valuePtrs := make([]any, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, c := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[c] = string(b)
+			} else {
+				row[c] = values[i]
+			}
+		}
+		if err := addRow(m, s.key, row); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *sqlLoader) close() error {
+	return s.db.Close()
+}
@@ -25,6 +25,60 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 )
 
+// geoipLookups are the types of lookup that can be made against a database,
+// keyed by the name of the database type.
+var geoipLookups = map[string]struct {
+	entity string
+	fn     func(*geoip2.Reader, net.IP) (any, error)
+}{
+	"city": {"city", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.City(ip)
+	}},
+	"country": {"country", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.Country(ip)
+	}},
+	"asn": {"ASN", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.ASN(ip)
+	}},
+	"enterprise": {"enterprise", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.Enterprise(ip)
+	}},
+	"anonymous_ip": {"anonymous IP", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.AnonymousIP(ip)
+	}},
+	"connection_type": {"connection type", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.ConnectionType(ip)
+	}},
+	"domain": {"domain", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.Domain(ip)
+	}},
+	"isp": {"ISP", func(db *geoip2.Reader, ip net.IP) (any, error) {
+		return db.ISP(ip)
+	}},
+}
+
+// lookupIP looks up an IP address with a lookup function and returns the
+// result as a generic structured value.
+func lookupIP(db *geoip2.Reader, s string, fn func(*geoip2.Reader, net.IP) (any, error)) (any, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("value %v does not appear to be a valid v4 or v6 IP address", s)
+	}
+	v, err := fn(db, ip)
+	if err != nil {
+		return nil, err
+	}
+	jBytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(jBytes))
+	dec.UseNumber()
+	var gV any
+	err = dec.Decode(&gV)
+	return gV, err
+}
+
 func registerMaxmindMethodSpec(name, entity string, fn func(*geoip2.Reader, net.IP) (any, error)) {
 	if err := bloblang.RegisterMethodV2(name,
 		bloblang.NewPluginSpec().
@@ -42,23 +96,7 @@ func registerMaxmindMethodSpec(name, entity string, fn func(*geoip2.Reader, net.
 				return nil, err
 			}
 			return bloblang.StringMethod(func(s string) (any, error) {
-				ip := net.ParseIP(s)
-				if ip == nil {
-					return nil, fmt.Errorf("value %v does not appear to be a valid v4 or v6 IP address", s)
-				}
-				v, err := fn(db, ip)
-				if err != nil {
-					return nil, err
-				}
-				jBytes, err := json.Marshal(v)
-				if err != nil {
-					return nil, err
-				}
-				dec := json.NewDecoder(bytes.NewReader(jBytes))
-				dec.UseNumber()
-				var gV any
-				err = dec.Decode(&gV)
-				return gV, err
+				return lookupIP(db, s, fn)
 			}), nil
 		}); err != nil {
 		panic(err)
@@ -66,35 +104,7 @@ func registerMaxmindMethodSpec(name, entity string, fn func(*geoip2.Reader, net.
 }
 
 func init() {
-	registerMaxmindMethodSpec("geoip_city", "city", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.City(ip)
-	})
-
-	registerMaxmindMethodSpec("geoip_country", "country", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.Country(ip)
-	})
-
-	registerMaxmindMethodSpec("geoip_asn", "ASN", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.ASN(ip)
-	})
-
-	registerMaxmindMethodSpec("geoip_enterprise", "enterprise", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.Enterprise(ip)
-	})
-
-	registerMaxmindMethodSpec("geoip_anonymous_ip", "anonymous IP", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.AnonymousIP(ip)
-	})
-
-	registerMaxmindMethodSpec("geoip_connection_type", "connection type", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.ConnectionType(ip)
-	})
-
-	registerMaxmindMethodSpec("geoip_domain", "domain", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.Domain(ip)
-	})
-
-	registerMaxmindMethodSpec("geoip_isp", "ISP", func(db *geoip2.Reader, ip net.IP) (any, error) {
-		return db.ISP(ip)
-	})
+	for name, l := range geoipLookups {
+		registerMaxmindMethodSpec("geoip_"+name, l.entity, l.fn)
+	}
 }
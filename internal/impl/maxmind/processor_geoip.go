@@ -0,0 +1,262 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maxmind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/oschwald/geoip2-golang"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	gpFieldPath           = "path"
+	gpFieldDatabase       = "database"
+	gpFieldIP             = "ip"
+	gpFieldResultMap      = "result_map"
+	gpFieldReloadInterval = "reload_interval"
+)
+
+func geoipProcessorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Integration").
+		Version("4.48.0").
+		Summary("Enriches messages with the location or network information of an IP address from a MaxMind or DB-IP database file.").
+		Description(`
+The IP address of each message is extracted with the `+"`ip`"+` query and looked up against an https://www.maxmind.com/en/home[mmdb database file^], such as the GeoIP2 and GeoLite2 databases of MaxMind or the IP to City and IP to ASN databases of DB-IP. The result of the lookup is then mapped into the message with `+"`result_map`"+`, where `+"`this`"+` refers to the result of the lookup and `+"`root`"+` refers to the message being enriched.
+
+Messages where the IP address could not be extracted or looked up are flagged as errors, which can be handled with xref:configuration:error_handling.adoc[error handling], and are otherwise unchanged.
+
+== Database reloads
+
+Database files are usually replaced periodically with updated versions. When `+"`reload_interval`"+` is set the modification time of the file is checked at that interval, and the database is reopened when it has changed. Lookups continue to use the previous version of the database until the new one has been opened successfully.
+`).
+		Fields(
+			service.NewStringField(gpFieldPath).
+				Description("The path of the mmdb database file.").
+				Example("./GeoLite2-City.mmdb"),
+			service.NewStringEnumField(gpFieldDatabase, "city", "country", "asn", "enterprise", "anonymous_ip", "connection_type", "domain", "isp").
+				Description("The type of the database, which determines the information returned by lookups. The city and ASN databases of DB-IP use the types `city` and `asn` respectively.").
+				Default("city"),
+			service.NewBloblangField(gpFieldIP).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang query] that extracts the IP address to look up from each message.").
+				Example("this.client.ip").
+				Example(`@http_server_remote_addr.split(":").index(0)`),
+			service.NewBloblangField(gpFieldResultMap).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that maps the result of the lookup into the message, where `this` refers to the result of the lookup.").
+				Example(`root.geo.city = this.City.Names.en
+root.geo.country = this.Country.IsoCode
+root.geo.location = [ this.Location.Longitude, this.Location.Latitude ]`).
+				Example(`root.client.as_org = this.AutonomousSystemOrganization`).
+				Default("root.geoip = this"),
+			service.NewDurationField(gpFieldReloadInterval).
+				Description("The interval at which to check whether the database file has changed and reload it, where zero disables reloads. For more information refer to <<database-reloads, database reloads>>.").
+				Default("1m"),
+		).
+		Example("City Enrichment", "Add the city and country of the client IP address of each message.", `
+pipeline:
+  processors:
+    - geoip:
+        path: ./GeoLite2-City.mmdb
+        database: city
+        ip: this.client_ip
+        result_map: |
+          root.geo.city = this.City.Names.en
+          root.geo.country = this.Country.IsoCode
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor("geoip", geoipProcessorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newGeoIPProcessorFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type geoipProcessor struct {
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	path      string
+	lookupFn  func(*geoip2.Reader, string) (any, error)
+	ip        *bloblang.Executor
+	resultMap *bloblang.Executor
+
+	dbMut   sync.RWMutex
+	db      *geoip2.Reader
+	modTime time.Time
+}
+
+func newGeoIPProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*geoipProcessor, error) {
+	g := &geoipProcessor{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+	}
+
+	var err error
+	if g.path, err = conf.FieldString(gpFieldPath); err != nil {
+		return nil, err
+	}
+
+	database, err := conf.FieldString(gpFieldDatabase)
+	if err != nil {
+		return nil, err
+	}
+	lookup, exists := geoipLookups[database]
+	if !exists {
+		return nil, fmt.Errorf("unrecognised database type: %v", database)
+	}
+	g.lookupFn = func(db *geoip2.Reader, ip string) (any, error) {
+		return lookupIP(db, ip, lookup.fn)
+	}
+
+	if g.ip, err = conf.FieldBloblang(gpFieldIP); err != nil {
+		return nil, err
+	}
+	if g.resultMap, err = conf.FieldBloblang(gpFieldResultMap); err != nil {
+		return nil, err
+	}
+
+	reloadInterval, err := conf.FieldDuration(gpFieldReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := g.reload(); err != nil {
+		return nil, err
+	}
+	if reloadInterval > 0 {
+		go g.reloadLoop(reloadInterval)
+	} else {
+		g.shutSig.TriggerHasStopped()
+	}
+	return g, nil
+}
+
+// reload opens the database file when it has been modified since it was last
+// opened, and returns whether it was reopened.
+func (g *geoipProcessor) reload() (bool, error) {
+	info, err := os.Stat(g.path)
+	if err != nil {
+		return false, err
+	}
+
+	g.dbMut.RLock()
+	unchanged := g.db != nil && info.ModTime().Equal(g.modTime)
+	g.dbMut.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	db, err := geoip2.Open(g.path)
+	if err != nil {
+		return false, err
+	}
+
+	g.dbMut.Lock()
+	oldDB := g.db
+	g.db = db
+	g.modTime = info.ModTime()
+	g.dbMut.Unlock()
+
+	if oldDB != nil {
+		_ = oldDB.Close()
+	}
+	return true, nil
+}
+
+func (g *geoipProcessor) reloadLoop(interval time.Duration) {
+	defer g.shutSig.TriggerHasStopped()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := g.reload()
+			if err != nil {
+				g.log.Errorf("Failed to reload GeoIP database %v: %v", g.path, err)
+			} else if reloaded {
+				g.log.Infof("Reloaded GeoIP database %v", g.path)
+			}
+		case <-g.shutSig.SoftStopChan():
+			return
+		}
+	}
+}
+
+func (g *geoipProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	res, err := msg.BloblangQuery(g.ip)
+	if err != nil {
+		return nil, fmt.Errorf("ip query failed: %w", err)
+	}
+	if res == nil {
+		return nil, errors.New("ip query deleted the message")
+	}
+	ipBytes, err := res.AsBytes()
+	if err != nil {
+		return nil, fmt.Errorf("ip query failed: %w", err)
+	}
+	ip := string(ipBytes)
+
+	g.dbMut.RLock()
+	result, err := g.lookupFn(g.db, ip)
+	g.dbMut.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resultMsg := msg.Copy()
+	resultMsg.SetStructuredMut(result)
+
+	if msg, err = msg.BloblangMutateFrom(g.resultMap, resultMsg); err != nil {
+		return nil, fmt.Errorf("result map failed: %w", err)
+	}
+	if msg == nil {
+		return nil, nil
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (g *geoipProcessor) Close(ctx context.Context) error {
+	g.shutSig.TriggerSoftStop()
+	select {
+	case <-g.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	g.dbMut.Lock()
+	defer g.dbMut.Unlock()
+	if g.db == nil {
+		return nil
+	}
+	err := g.db.Close()
+	g.db = nil
+	return err
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maxmind
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testGeoIPProcessor(t *testing.T, conf string) *geoipProcessor {
+	t.Helper()
+
+	pConf, err := geoipProcessorSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	p, err := newGeoIPProcessorFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	return p
+}
+
+func TestGeoIPProcessor(t *testing.T) {
+	p := testGeoIPProcessor(t, `
+path: ./testdata/GeoIP2-City-Test.mmdb
+database: city
+ip: this.client_ip
+result_map: |
+  root.geo.city = this.City.Names.en
+  root.geo.country = this.Country.IsoCode
+`)
+
+	batch, err := p.Process(context.Background(), service.NewMessage([]byte(`{"client_ip":"81.2.69.192","id":1}`)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	mBytes, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"client_ip":"81.2.69.192","id":1,"geo":{"city":"London","country":"GB"}}`, string(mBytes))
+
+	_, err = p.Process(context.Background(), service.NewMessage([]byte(`{"client_ip":"not an ip"}`)))
+	require.ErrorContains(t, err, "valid v4 or v6 IP address")
+}
+
+func TestGeoIPProcessorDefaultResultMap(t *testing.T) {
+	p := testGeoIPProcessor(t, `
+path: ./testdata/GeoLite2-ASN-Test.mmdb
+database: asn
+ip: meta("ip")
+`)
+
+	msg := service.NewMessage([]byte(`{}`))
+	msg.MetaSetMut("ip", "214.0.0.0")
+
+	batch, err := p.Process(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	structured, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, "DoD Network Information Center", structured.(map[string]any)["geoip"].(map[string]any)["AutonomousSystemOrganization"])
+}
+
+func TestGeoIPProcessorReload(t *testing.T) {
+	copyFile := func(from, to string) {
+		data, err := os.ReadFile(from)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(to+".tmp", data, 0o644))
+		require.NoError(t, os.Rename(to+".tmp", to))
+	}
+
+	path := filepath.Join(t.TempDir(), "db.mmdb")
+	copyFile("./testdata/GeoIP2-Country-Test.mmdb", path)
+
+	p := testGeoIPProcessor(t, `
+path: `+path+`
+database: country
+ip: this.ip
+result_map: root.country = this.Country.IsoCode
+reload_interval: 10ms
+`)
+
+	lookup := func() any {
+		batch, err := p.Process(context.Background(), service.NewMessage([]byte(`{"ip":"81.2.69.192"}`)))
+		require.NoError(t, err)
+		structured, err := batch[0].AsStructured()
+		require.NoError(t, err)
+		return structured.(map[string]any)["country"]
+	}
+	assert.Equal(t, "GB", lookup())
+
+	// The city database also contains countries, but with a distinct
+	// modification time.
+	copyFile("./testdata/GeoIP2-City-Test.mmdb", path)
+	modTime := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	assert.Eventually(t, func() bool {
+		p.dbMut.RLock()
+		defer p.dbMut.RUnlock()
+		return p.modTime.Equal(modTime) && p.db.Metadata().DatabaseType == "GeoIP2-City"
+	}, time.Second*5, time.Millisecond*10)
+	assert.Equal(t, "GB", lookup())
+}
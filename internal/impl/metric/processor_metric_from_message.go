@@ -0,0 +1,543 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/beorn7/perks/quantile"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	mfmFieldName                   = "name"
+	mfmFieldType                   = "type"
+	mfmFieldValue                  = "value"
+	mfmFieldLabels                 = "labels"
+	mfmFieldBuckets                = "buckets"
+	mfmFieldQuantiles              = "quantiles"
+	mfmFieldQuantilesQuantile      = "quantile"
+	mfmFieldQuantilesError         = "error"
+	mfmFieldCardinality            = "cardinality"
+	mfmFieldCardinalityMaxSeries   = "max_series"
+	mfmFieldCardinalityOverflow    = "overflow"
+	mfmFieldCardinalityHashBuckets = "hash_buckets"
+)
+
+const (
+	overflowDrop = "drop"
+	overflowHash = "hash"
+)
+
+// Matches the default buckets of the Prometheus client library.
+var defaultBuckets = []any{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0}
+
+func metricFromMessageSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("Utility").
+		Beta().
+		Version("4.48.0").
+		Summary("Emit custom metrics derived from messages, including histograms and summaries, with labels extracted from each message.").
+		Description(`
+This processor evaluates the `+"`value`"+` and `+"`labels`"+` fields for each message and records the result to a metric according to the <<types, type>>. Messages pass through this processor unchanged, and any message for which a metric cannot be recorded is logged and otherwise ignored.
+
+Custom metrics are emitted along with Redpanda Connect internal metrics, where you can customize where metrics are sent and which names are emitted. For more information see the xref:components:metrics/about.adoc[metrics docs].
+
+== Cardinality
+
+Label values extracted from messages can take an unbounded number of values, and every distinct combination of them creates a new series within the metrics destination. The field `+"`cardinality.max_series`"+` caps the number of distinct label combinations recorded by this processor, and once the cap is reached new combinations are handled according to `+"`cardinality.overflow`"+`:
+
+- `+"`drop`"+`: The metric is not recorded for the message.
+- `+"`hash`"+`: All label values are replaced with an identifier `+"`overflow_<n>`"+`, where `+"`n`"+` is derived from a hash of the original values and is lower than `+"`cardinality.hash_buckets`"+`. The number of series is therefore capped at `+"`max_series + hash_buckets`"+`.`).
+		Footnotes(`
+== Types
+
+=== `+"`counter`"+`
+
+Increments a counter by exactly 1, the contents of `+"`value`"+` are ignored by this type.
+
+=== `+"`counter_by`"+`
+
+Increments a counter by the contents of `+"`value`"+`, which must be a positive number.
+
+=== `+"`gauge`"+`
+
+Sets a gauge to the contents of `+"`value`"+`, which must be a positive number.
+
+=== `+"`timing`"+`
+
+Records the contents of `+"`value`"+` as a timing, which must be a positive integer. It is recommended that timing values are recorded in nanoseconds in order to be consistent with standard Redpanda Connect timing metrics.
+
+=== `+"`histogram`"+`
+
+Observes the contents of `+"`value`"+`, which must be a positive number, within the buckets configured with `+"`buckets`"+`. The histogram is emitted as a counter `+"`<name>_bucket`"+` with an additional `+"`le`"+` label for each bucket and `+"`+Inf`"+`, along with the counters `+"`<name>_sum`"+` and `+"`<name>_count`"+`, following the conventions of Prometheus histograms.
+
+=== `+"`summary`"+`
+
+Observes the contents of `+"`value`"+`, which must be a positive number, and emits the quantiles configured with `+"`quantiles`"+` as a gauge `+"`<name>`"+` with an additional `+"`quantile`"+` label, along with the counters `+"`<name>_sum`"+` and `+"`<name>_count`"+`. Quantiles are calculated over all values observed since the processor started.`).
+		Example(
+			"Request latency histogram",
+			"In this example we record the latency of requests described by each message as a histogram labelled by the endpoint and status of the request. Endpoints are extracted from messages and could be unbounded, and so we drop label combinations beyond the first 500.",
+			`
+pipeline:
+  processors:
+    - metric_from_message:
+        name: request_latency_seconds
+        type: histogram
+        value: ${! this.latency_ms / 1000 }
+        buckets: [ 0.01, 0.05, 0.1, 0.5, 1, 5 ]
+        labels:
+          endpoint: ${! this.endpoint }
+          status: ${! this.status.string() }
+        cardinality:
+          max_series: 500
+          overflow: drop
+`,
+		).
+		Fields(
+			service.NewStringField(mfmFieldName).
+				Description("The name of the metric to create, this must be unique across all Redpanda Connect components otherwise it will overwrite those other metrics."),
+			service.NewStringEnumField(mfmFieldType, "counter", "counter_by", "gauge", "timing", "histogram", "summary").
+				Description("The metric <<types, type>> to create."),
+			service.NewInterpolatedStringField(mfmFieldValue).
+				Description("For some metric types specifies a value to set, increment or observe.").
+				Default(""),
+			service.NewInterpolatedStringMapField(mfmFieldLabels).
+				Description("A map of label names and values that can be used to enrich metrics. Labels are not supported by some metric destinations, in which case the metrics series are combined.").
+				Example(map[string]any{
+					"type":  `${! this.doc.type }`,
+					"topic": `${! @kafka_topic }`,
+				}).
+				Optional(),
+			service.NewFloatListField(mfmFieldBuckets).
+				Description("The upper bounds of the buckets of a `histogram`, which must be in increasing order.").
+				Default(defaultBuckets),
+			service.NewObjectListField(mfmFieldQuantiles,
+				service.NewFloatField(mfmFieldQuantilesQuantile).
+					Description("The quantile to calculate, which must be between 0 and 1."),
+				service.NewFloatField(mfmFieldQuantilesError).
+					Description("The permissible margin of error of the quantile."),
+			).
+				Description("The quantiles calculated by a `summary`.").
+				Default([]any{
+					map[string]any{"quantile": 0.5, "error": 0.05},
+					map[string]any{"quantile": 0.9, "error": 0.01},
+					map[string]any{"quantile": 0.99, "error": 0.001},
+				}).
+				Advanced(),
+			service.NewObjectField(mfmFieldCardinality,
+				service.NewIntField(mfmFieldCardinalityMaxSeries).
+					Description("The maximum number of distinct label combinations to record, where zero means no limit.").
+					Default(1000),
+				service.NewStringAnnotatedEnumField(mfmFieldCardinalityOverflow, map[string]string{
+					overflowDrop: "Do not record the metric for label combinations beyond the limit.",
+					overflowHash: "Record the metric for label combinations beyond the limit with all label values replaced by a hash bucket identifier.",
+				}).
+					Description("How to handle label combinations beyond the limit.").
+					Default(overflowDrop),
+				service.NewIntField(mfmFieldCardinalityHashBuckets).
+					Description("The number of hash buckets that label combinations beyond the limit are spread across when `overflow` is `hash`.").
+					Default(10),
+			).
+				Description("Limits the number of series created by label values extracted from messages. For more information refer to <<cardinality, cardinality>>.").
+				Advanced(),
+		)
+}
+
+func init() {
+	err := service.RegisterBatchProcessor("metric_from_message", metricFromMessageSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
+			return newMetricFromMessageFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type metricFromMessageProc struct {
+	log *service.Logger
+
+	value       *service.InterpolatedString
+	labelValues []*service.InterpolatedString
+	guard       *cardinalityGuard
+
+	record func(value string, labelValues []string) error
+}
+
+func newMetricFromMessageFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*metricFromMessageProc, error) {
+	name, err := conf.FieldString(mfmFieldName)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, errors.New("metric name must not be empty")
+	}
+
+	typeStr, err := conf.FieldString(mfmFieldType)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &metricFromMessageProc{
+		log: mgr.Logger(),
+	}
+	if p.value, err = conf.FieldInterpolatedString(mfmFieldValue); err != nil {
+		return nil, err
+	}
+
+	var labelNames []string
+	if conf.Contains(mfmFieldLabels) {
+		labels, err := conf.FieldInterpolatedStringMap(mfmFieldLabels)
+		if err != nil {
+			return nil, err
+		}
+		for k := range labels {
+			labelNames = append(labelNames, k)
+		}
+		sort.Strings(labelNames)
+		for _, k := range labelNames {
+			p.labelValues = append(p.labelValues, labels[k])
+		}
+	}
+
+	if p.guard, err = cardinalityGuardFromParsed(conf.Namespace(mfmFieldCardinality), mgr.Logger()); err != nil {
+		return nil, err
+	}
+
+	metrics := mgr.Metrics()
+	switch typeStr {
+	case "counter":
+		ctr := metrics.NewCounter(name, labelNames...)
+		p.record = func(_ string, labelValues []string) error {
+			ctr.Incr(1, labelValues...)
+			return nil
+		}
+	case "counter_by":
+		ctr := metrics.NewCounter(name, labelNames...)
+		p.record = func(value string, labelValues []string) error {
+			f, err := parsePositiveFloat(value)
+			if err != nil {
+				return err
+			}
+			ctr.IncrFloat64(f, labelValues...)
+			return nil
+		}
+	case "gauge":
+		gge := metrics.NewGauge(name, labelNames...)
+		p.record = func(value string, labelValues []string) error {
+			f, err := parsePositiveFloat(value)
+			if err != nil {
+				return err
+			}
+			gge.SetFloat64(f, labelValues...)
+			return nil
+		}
+	case "timing":
+		tmr := metrics.NewTimer(name, labelNames...)
+		p.record = func(value string, labelValues []string) error {
+			i, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			if i < 0 {
+				return fmt.Errorf("value %d is negative", i)
+			}
+			tmr.Timing(i, labelValues...)
+			return nil
+		}
+	case "histogram":
+		buckets, err := conf.FieldFloatList(mfmFieldBuckets)
+		if err != nil {
+			return nil, err
+		}
+		h, err := newHistogram(metrics, name, labelNames, buckets)
+		if err != nil {
+			return nil, err
+		}
+		p.record = h.record
+	case "summary":
+		quantileConfs, err := conf.FieldObjectList(mfmFieldQuantiles)
+		if err != nil {
+			return nil, err
+		}
+		targets := map[float64]float64{}
+		for _, qConf := range quantileConfs {
+			q, err := qConf.FieldFloat(mfmFieldQuantilesQuantile)
+			if err != nil {
+				return nil, err
+			}
+			if q <= 0 || q >= 1 {
+				return nil, fmt.Errorf("quantile %v must be between 0 and 1", q)
+			}
+			if targets[q], err = qConf.FieldFloat(mfmFieldQuantilesError); err != nil {
+				return nil, err
+			}
+		}
+		p.record = newSummary(metrics, name, labelNames, targets).record
+	default:
+		return nil, fmt.Errorf("metric type unrecognised: %v", typeStr)
+	}
+	return p, nil
+}
+
+func parsePositiveFloat(value string) (float64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f < 0 {
+		return 0, fmt.Errorf("value %v is negative", f)
+	}
+	return f, nil
+}
+
+func (p *metricFromMessageProc) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	for i := range batch {
+		value, err := batch.TryInterpolatedString(i, p.value)
+		if err != nil {
+			p.log.Errorf("Value interpolation error: %v", err)
+			continue
+		}
+
+		labelValues, err := p.evalLabels(batch, i)
+		if err != nil {
+			p.log.Errorf("Label interpolation error: %v", err)
+			continue
+		}
+
+		var ok bool
+		if labelValues, ok = p.guard.apply(labelValues); !ok {
+			continue
+		}
+		if err := p.record(value, labelValues); err != nil {
+			p.log.Errorf("Failed to record metric: %v", err)
+		}
+	}
+	return []service.MessageBatch{batch}, nil
+}
+
+func (p *metricFromMessageProc) evalLabels(batch service.MessageBatch, index int) ([]string, error) {
+	labelValues := make([]string, len(p.labelValues))
+	for i, l := range p.labelValues {
+		v, err := batch.TryInterpolatedString(index, l)
+		if err != nil {
+			return nil, err
+		}
+		labelValues[i] = v
+	}
+	return labelValues, nil
+}
+
+func (p *metricFromMessageProc) Close(ctx context.Context) error {
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type histogram struct {
+	buckets    []float64
+	bucketStrs []string
+
+	bucketCtr *service.MetricCounter
+	sumCtr    *service.MetricCounter
+	countCtr  *service.MetricCounter
+}
+
+func newHistogram(metrics *service.Metrics, name string, labelNames []string, buckets []float64) (*histogram, error) {
+	if len(buckets) == 0 {
+		return nil, errors.New("at least one histogram bucket must be specified")
+	}
+	h := &histogram{
+		buckets:   buckets,
+		bucketCtr: metrics.NewCounter(name+"_bucket", append(slices.Clone(labelNames), "le")...),
+		sumCtr:    metrics.NewCounter(name+"_sum", labelNames...),
+		countCtr:  metrics.NewCounter(name+"_count", labelNames...),
+	}
+	for i, b := range buckets {
+		if i > 0 && b <= buckets[i-1] {
+			return nil, errors.New("histogram buckets must be in increasing order")
+		}
+		h.bucketStrs = append(h.bucketStrs, strconv.FormatFloat(b, 'g', -1, 64))
+	}
+	return h, nil
+}
+
+func (h *histogram) record(value string, labelValues []string) error {
+	f, err := parsePositiveFloat(value)
+	if err != nil {
+		return err
+	}
+
+	withLE := append(slices.Clone(labelValues), "+Inf")
+	h.bucketCtr.Incr(1, withLE...)
+	for i, b := range h.buckets {
+		if f <= b {
+			withLE[len(withLE)-1] = h.bucketStrs[i]
+			h.bucketCtr.Incr(1, withLE...)
+		}
+	}
+	h.sumCtr.IncrFloat64(f, labelValues...)
+	h.countCtr.Incr(1, labelValues...)
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type summary struct {
+	targets      map[float64]float64
+	quantiles    []float64
+	quantileStrs []string
+
+	quantileGge *service.MetricGauge
+	sumCtr      *service.MetricCounter
+	countCtr    *service.MetricCounter
+
+	mut     sync.Mutex
+	streams map[string]*quantile.Stream
+}
+
+func newSummary(metrics *service.Metrics, name string, labelNames []string, targets map[float64]float64) *summary {
+	s := &summary{
+		targets:     targets,
+		quantileGge: metrics.NewGauge(name, append(slices.Clone(labelNames), "quantile")...),
+		sumCtr:      metrics.NewCounter(name+"_sum", labelNames...),
+		countCtr:    metrics.NewCounter(name+"_count", labelNames...),
+		streams:     map[string]*quantile.Stream{},
+	}
+	for q := range targets {
+		s.quantiles = append(s.quantiles, q)
+	}
+	sort.Float64s(s.quantiles)
+	for _, q := range s.quantiles {
+		s.quantileStrs = append(s.quantileStrs, strconv.FormatFloat(q, 'g', -1, 64))
+	}
+	return s
+}
+
+func (s *summary) record(value string, labelValues []string) error {
+	f, err := parsePositiveFloat(value)
+	if err != nil {
+		return err
+	}
+
+	withQuantile := append(slices.Clone(labelValues), "")
+	key := strings.Join(labelValues, "\x00")
+
+	s.mut.Lock()
+	stream, exists := s.streams[key]
+	if !exists {
+		stream = quantile.NewTargeted(s.targets)
+		s.streams[key] = stream
+	}
+	stream.Insert(f)
+	for i, q := range s.quantiles {
+		withQuantile[len(withQuantile)-1] = s.quantileStrs[i]
+		s.quantileGge.SetFloat64(stream.Query(q), withQuantile...)
+	}
+	s.mut.Unlock()
+
+	s.sumCtr.IncrFloat64(f, labelValues...)
+	s.countCtr.Incr(1, labelValues...)
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type cardinalityGuard struct {
+	log *service.Logger
+
+	maxSeries   int
+	overflow    string
+	hashBuckets int
+
+	mut       sync.Mutex
+	seen      map[string]struct{}
+	capLogged bool
+}
+
+func cardinalityGuardFromParsed(conf *service.ParsedConfig, log *service.Logger) (*cardinalityGuard, error) {
+	g := &cardinalityGuard{
+		log:  log,
+		seen: map[string]struct{}{},
+	}
+
+	var err error
+	if g.maxSeries, err = conf.FieldInt(mfmFieldCardinalityMaxSeries); err != nil {
+		return nil, err
+	}
+	if g.maxSeries < 0 {
+		return nil, fmt.Errorf("field %v must not be negative", mfmFieldCardinalityMaxSeries)
+	}
+	if g.overflow, err = conf.FieldString(mfmFieldCardinalityOverflow); err != nil {
+		return nil, err
+	}
+	if g.hashBuckets, err = conf.FieldInt(mfmFieldCardinalityHashBuckets); err != nil {
+		return nil, err
+	}
+	if g.overflow == overflowHash && g.hashBuckets <= 0 {
+		return nil, fmt.Errorf("field %v must be greater than zero", mfmFieldCardinalityHashBuckets)
+	}
+	return g, nil
+}
+
+// apply returns the label values a metric should be recorded with, or false
+// when the metric should not be recorded at all.
+func (g *cardinalityGuard) apply(labelValues []string) ([]string, bool) {
+	if g.maxSeries == 0 || len(labelValues) == 0 {
+		return labelValues, true
+	}
+
+	key := strings.Join(labelValues, "\x00")
+
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	if _, exists := g.seen[key]; exists {
+		return labelValues, true
+	}
+	if len(g.seen) < g.maxSeries {
+		g.seen[key] = struct{}{}
+		return labelValues, true
+	}
+
+	if !g.capLogged {
+		g.capLogged = true
+		g.log.Warnf("Reached the limit of %v distinct label combinations, further combinations are handled with the %v policy", g.maxSeries, g.overflow)
+	}
+	if g.overflow == overflowDrop {
+		return nil, false
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	bucket := "overflow_" + strconv.FormatUint(uint64(h.Sum32()%uint32(g.hashBuckets)), 10)
+
+	hashed := make([]string, len(labelValues))
+	for i := range hashed {
+		hashed[i] = bucket
+	}
+	return hashed, true
+}
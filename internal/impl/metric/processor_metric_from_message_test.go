@@ -0,0 +1,303 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+type recordedMetrics struct {
+	mut    sync.Mutex
+	values map[string]float64
+}
+
+func (r *recordedMetrics) add(name string, labelKeys, labelValues []string, fn func(float64) float64) {
+	var labels []string
+	for i, k := range labelKeys {
+		if k == "label" || k == "path" {
+			// Added to all component metrics.
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("%v=%v", k, labelValues[i]))
+	}
+	key := name + "{" + strings.Join(labels, ",") + "}"
+
+	r.mut.Lock()
+	r.values[key] = fn(r.values[key])
+	r.mut.Unlock()
+}
+
+func (r *recordedMetrics) get() map[string]float64 {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.values
+}
+
+type recordedStat struct {
+	set func(fn func(float64) float64)
+}
+
+func (s recordedStat) Incr(count int64) {
+	s.IncrFloat64(float64(count))
+}
+
+func (s recordedStat) IncrFloat64(count float64) {
+	s.set(func(v float64) float64 { return v + count })
+}
+
+func (s recordedStat) Timing(delta int64) {
+	s.set(func(v float64) float64 { return float64(delta) })
+}
+
+func (s recordedStat) Set(value int64) {
+	s.SetFloat64(float64(value))
+}
+
+func (s recordedStat) SetFloat64(value float64) {
+	s.set(func(float64) float64 { return value })
+}
+
+type recordingExporter struct {
+	r *recordedMetrics
+}
+
+func (e recordingExporter) stat(name string, labelKeys, labelValues []string) recordedStat {
+	return recordedStat{set: func(fn func(float64) float64) {
+		e.r.add(name, labelKeys, labelValues, fn)
+	}}
+}
+
+func (e recordingExporter) NewCounterCtor(name string, labelKeys ...string) service.MetricsExporterCounterCtor {
+	return func(labelValues ...string) service.MetricsExporterCounter {
+		return e.stat(name, labelKeys, labelValues)
+	}
+}
+
+func (e recordingExporter) NewTimerCtor(name string, labelKeys ...string) service.MetricsExporterTimerCtor {
+	return func(labelValues ...string) service.MetricsExporterTimer {
+		return e.stat(name, labelKeys, labelValues)
+	}
+}
+
+func (e recordingExporter) NewGaugeCtor(name string, labelKeys ...string) service.MetricsExporterGaugeCtor {
+	return func(labelValues ...string) service.MetricsExporterGauge {
+		return e.stat(name, labelKeys, labelValues)
+	}
+}
+
+func (e recordingExporter) Close(context.Context) error {
+	return nil
+}
+
+// runProcessor processes the messages with a metric_from_message processor
+// configured from the provided YAML, and returns the custom metrics recorded.
+func runProcessor(t *testing.T, procConf string, msgs ...string) map[string]float64 {
+	t.Helper()
+
+	r := &recordedMetrics{values: map[string]float64{}}
+
+	env := service.NewEnvironment()
+	require.NoError(t, env.RegisterMetricsExporter("recording", service.NewConfigSpec(),
+		func(*service.ParsedConfig, *service.Logger) (service.MetricsExporter, error) {
+			return recordingExporter{r: r}, nil
+		}))
+
+	builder := env.NewStreamBuilder()
+	require.NoError(t, builder.SetMetricsYAML(`
+recording: {}
+mapping: 'root = if !this.has_prefix("test_") { deleted() }'
+`))
+	require.NoError(t, builder.AddProcessorYAML("metric_from_message:\n"+procConf))
+
+	produce, err := builder.AddBatchProducerFunc()
+	require.NoError(t, err)
+	require.NoError(t, builder.AddConsumerFunc(func(context.Context, *service.Message) error {
+		return nil
+	}))
+
+	stream, err := builder.Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	go func() {
+		var batch service.MessageBatch
+		for _, m := range msgs {
+			batch = append(batch, service.NewMessage([]byte(m)))
+		}
+		assert.NoError(t, produce(ctx, batch))
+		assert.NoError(t, stream.StopWithin(5*time.Second))
+	}()
+	require.NoError(t, stream.Run(ctx))
+
+	return r.get()
+}
+
+func TestMetricFromMessageCounter(t *testing.T) {
+	values := runProcessor(t, `
+  name: test_counter
+  type: counter_by
+  value: ${! this.n }
+  labels:
+    kind: ${! this.kind }
+`,
+		`{"kind":"a","n":1}`,
+		`{"kind":"b","n":2.5}`,
+		`{"kind":"a","n":3}`,
+		`{"kind":"a","n":-1}`,
+	)
+
+	assert.Equal(t, map[string]float64{
+		"test_counter{kind=a}": 4,
+		"test_counter{kind=b}": 2.5,
+	}, values)
+}
+
+func TestMetricFromMessageHistogram(t *testing.T) {
+	values := runProcessor(t, `
+  name: test_latency
+  type: histogram
+  value: ${! this.latency }
+  buckets: [ 0.1, 1, 10 ]
+  labels:
+    endpoint: ${! this.endpoint }
+`,
+		`{"endpoint":"/a","latency":0.05}`,
+		`{"endpoint":"/a","latency":0.5}`,
+		`{"endpoint":"/a","latency":20}`,
+		`{"endpoint":"/b","latency":1}`,
+	)
+
+	assert.Equal(t, map[string]float64{
+		"test_latency_bucket{endpoint=/a,le=0.1}":  1,
+		"test_latency_bucket{endpoint=/a,le=1}":    2,
+		"test_latency_bucket{endpoint=/a,le=10}":   2,
+		"test_latency_bucket{endpoint=/a,le=+Inf}": 3,
+		"test_latency_sum{endpoint=/a}":            20.55,
+		"test_latency_count{endpoint=/a}":          3,
+		"test_latency_bucket{endpoint=/b,le=1}":    1,
+		"test_latency_bucket{endpoint=/b,le=10}":   1,
+		"test_latency_bucket{endpoint=/b,le=+Inf}": 1,
+		"test_latency_sum{endpoint=/b}":            1,
+		"test_latency_count{endpoint=/b}":          1,
+	}, values)
+}
+
+func TestMetricFromMessageSummary(t *testing.T) {
+	var msgs []string
+	for i := 1; i <= 100; i++ {
+		msgs = append(msgs, fmt.Sprintf(`{"v":%v}`, i))
+	}
+
+	values := runProcessor(t, `
+  name: test_summary
+  type: summary
+  value: ${! this.v }
+  quantiles:
+    - quantile: 0.5
+      error: 0
+    - quantile: 0.99
+      error: 0
+`, msgs...)
+
+	assert.Equal(t, map[string]float64{
+		"test_summary{quantile=0.5}":  50,
+		"test_summary{quantile=0.99}": 99,
+		"test_summary_sum{}":          5050,
+		"test_summary_count{}":        100,
+	}, values)
+}
+
+func TestMetricFromMessageCardinality(t *testing.T) {
+	msgs := []string{
+		`{"user":"a"}`,
+		`{"user":"b"}`,
+		`{"user":"c"}`,
+		`{"user":"a"}`,
+		`{"user":"d"}`,
+	}
+
+	values := runProcessor(t, `
+  name: test_users
+  type: counter
+  labels:
+    user: ${! this.user }
+  cardinality:
+    max_series: 2
+    overflow: drop
+`, msgs...)
+
+	assert.Equal(t, map[string]float64{
+		"test_users{user=a}": 2,
+		"test_users{user=b}": 1,
+	}, values)
+
+	values = runProcessor(t, `
+  name: test_users
+  type: counter
+  labels:
+    user: ${! this.user }
+  cardinality:
+    max_series: 2
+    overflow: hash
+    hash_buckets: 1
+`, msgs...)
+
+	assert.Equal(t, map[string]float64{
+		"test_users{user=a}":          2,
+		"test_users{user=b}":          1,
+		"test_users{user=overflow_0}": 2,
+	}, values)
+}
+
+func TestMetricFromMessageBadConfig(t *testing.T) {
+	for name, conf := range map[string]string{
+		"empty name": `
+name: ""
+type: counter
+`,
+		"unordered buckets": `
+name: foo
+type: histogram
+buckets: [ 1, 0.5 ]
+`,
+		"bad quantile": `
+name: foo
+type: summary
+quantiles: [ { quantile: 1.5, error: 0.1 } ]
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			pConf, err := metricFromMessageSpec().ParseYAML(conf, nil)
+			require.NoError(t, err)
+
+			_, err = newMetricFromMessageFromConfig(pConf, service.MockResources())
+			require.Error(t, err)
+		})
+	}
+}
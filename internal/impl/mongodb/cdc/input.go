@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -59,13 +60,24 @@ func spec() *service.ConfigSpec {
 		Summary(`Streams changes from a MongoDB replica set.`).
 		Description(`Read from a MongoDB replica set using https://www.mongodb.com/docs/manual/changeStreams/[^Change Streams]. It's only possible to watch for changes when using a sharded MongoDB or a MongoDB cluster running as a replica set.
 
-By default MongoDB does not propagate changes in all cases. In order to capture all changes (including deletes) in a MongoDB cluster one needs to enable pre and post image saving and the collection needs to also enable saving these pre and post images. For more information see https://www.mongodb.com/docs/manual/changeStreams/#change-streams-with-document-pre--and-post-images[^MongoDB documentation].`).
+The scope of the change stream depends on the `+"`database`"+` and `+"`collections`"+` fields. When both are specified only the listed collections of the database are watched, when only a database is specified all of its collections are watched, and when neither is specified all databases of the deployment are watched with the exception of the `+"`admin`"+`, `+"`config`"+` and `+"`local`"+` databases.
+
+By default MongoDB does not propagate changes in all cases. In order to capture all changes (including deletes) in a MongoDB cluster one needs to enable pre and post image saving and the collection needs to also enable saving these pre and post images. For more information see https://www.mongodb.com/docs/manual/changeStreams/#change-streams-with-document-pre--and-post-images[^MongoDB documentation].
+
+== Metadata
+
+Each message has the following metadata fields:
+
+- operation: The type of the change, which is one of `+"`read`"+` for snapshot reads, `+"`insert`"+`, `+"`replace`"+`, `+"`update`"+` or `+"`delete`"+`.
+- database: The database of the changed document.
+- collection: The collection of the changed document.`).
 		Fields(
 			service.NewStringField(fieldClientURL).
 				Description("The URL of the target MongoDB server.").
 				Example("mongodb://localhost:27017"),
 			service.NewStringField(fieldClientDatabase).
-				Description("The name of the target MongoDB database."),
+				Description("The name of the MongoDB database to stream changes from. If empty, changes to all databases of the deployment are streamed.").
+				Default(""),
 			service.NewStringField(fieldClientUsername).
 				Description("The username to connect to the database.").
 				Default(""),
@@ -74,7 +86,8 @@ By default MongoDB does not propagate changes in all cases. In order to capture
 				Default("").
 				Secret(),
 			service.NewStringListField(fieldCollections).
-				Description("The collections to stream changes from."),
+				Description("The collections of the `"+fieldClientDatabase+"` to stream changes from. If empty, changes to all collections of the database are streamed.").
+				Default([]any{}),
 			service.NewStringField(fieldCheckpointKey).
 				Description("Checkpoint cache key name.").
 				Default("mongodb_cdc_checkpoint"),
@@ -158,8 +171,8 @@ func newMongoCDC(conf *service.ParsedConfig, res *service.Resources) (i service.
 	if cdc.collections, err = conf.FieldStringList(fieldCollections); err != nil {
 		return
 	}
-	if len(cdc.collections) == 0 {
-		return nil, errors.New("at least one collection must be specified")
+	if dbName == "" && len(cdc.collections) > 0 {
+		return nil, fmt.Errorf("`%s` can only be specified along with a `%s`", fieldCollections, fieldClientDatabase)
 	}
 	var snapshotEnabled bool
 	if snapshotEnabled, err = conf.FieldBool(fieldStreamSnapshot); err != nil {
@@ -246,7 +259,9 @@ func newMongoCDC(conf *service.ParsedConfig, res *service.Resources) (i service.
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to mongo: %w", err)
 	}
-	cdc.db = cdc.client.Database(dbName)
+	if dbName != "" {
+		cdc.db = cdc.client.Database(dbName)
+	}
 	return service.AutoRetryNacksBatchedToggled(conf, cdc)
 }
 
@@ -256,7 +271,9 @@ type mongoBatch struct {
 }
 
 type mongoCDC struct {
-	client      *mongo.Client
+	client *mongo.Client
+	// The database to stream changes from, which is nil when streaming the
+	// whole deployment.
 	db          *mongo.Database
 	collections []string
 	logger      *service.Logger
@@ -300,7 +317,7 @@ func (m *mongoCDC) Connect(ctx context.Context) error {
 	if err := m.client.Ping(ctx, nil); err != nil {
 		return fmt.Errorf("unable to ping mongodb: %w", err)
 	}
-	r := m.db.RunCommand(ctx, bson.M{"buildInfo": 1})
+	r := m.commandDB().RunCommand(ctx, bson.M{"buildInfo": 1})
 	if r.Err() != nil {
 		return fmt.Errorf("failure to determine mongodb version: %w", r.Err())
 	}
@@ -324,7 +341,7 @@ func (m *mongoCDC) Connect(ctx context.Context) error {
 		return fmt.Errorf("unable to load checkpoints from cache: %w", err)
 	}
 	// Set the stream start when starting fresh to be the current oplog end time.
-	r = m.db.RunCommand(ctx, bson.M{"hello": 1})
+	r = m.commandDB().RunCommand(ctx, bson.M{"hello": 1})
 	if r.Err() != nil {
 		return fmt.Errorf("unable to determine replication info (is your mongodb instance running as a replication set?): %w", r.Err())
 	}
@@ -375,10 +392,16 @@ func (m *mongoCDC) Connect(ctx context.Context) error {
 		cp := checkpoint.NewCapped[bson.Raw](int64(m.checkpointLimit))
 		if !skipSnapshot {
 			g, gctx := errgroup.WithContext(ctx)
-			for _, name := range m.collections {
-				coll := m.db.Collection(name)
-				g.Go(func() error { return m.readSnapshot(gctx, coll, cp) })
-			}
+			g.Go(func() error {
+				colls, err := m.snapshotCollections(gctx)
+				if err != nil {
+					return err
+				}
+				for _, coll := range colls {
+					g.Go(func() error { return m.readSnapshot(gctx, coll, cp) })
+				}
+				return nil
+			})
 			if err := g.Wait(); err != nil {
 				select {
 				case m.errorChan <- fmt.Errorf("error reading MongoDB snapshot: %w", err):
@@ -410,6 +433,62 @@ func (m *mongoCDC) Connect(ctx context.Context) error {
 	return nil
 }
 
+// commandDB returns the database to run commands against.
+func (m *mongoCDC) commandDB() *mongo.Database {
+	if m.db != nil {
+		return m.db
+	}
+	return m.client.Database("admin")
+}
+
+// snapshotCollections returns the collections within the scope of the change
+// stream.
+func (m *mongoCDC) snapshotCollections(ctx context.Context) ([]*mongo.Collection, error) {
+	if m.db != nil && len(m.collections) > 0 {
+		var colls []*mongo.Collection
+		for _, name := range m.collections {
+			colls = append(colls, m.db.Collection(name))
+		}
+		return colls, nil
+	}
+
+	dbs := []*mongo.Database{m.db}
+	if m.db == nil {
+		names, err := m.client.ListDatabaseNames(ctx, bson.D{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list databases: %w", err)
+		}
+		dbs = nil
+		for _, name := range names {
+			if !isSystemDatabase(name) {
+				dbs = append(dbs, m.client.Database(name))
+			}
+		}
+	}
+
+	var colls []*mongo.Collection
+	for _, db := range dbs {
+		names, err := db.ListCollectionNames(ctx, bson.D{{Key: "type", Value: "collection"}})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list collections of database %s: %w", db.Name(), err)
+		}
+		for _, name := range names {
+			if !strings.HasPrefix(name, "system.") {
+				colls = append(colls, db.Collection(name))
+			}
+		}
+	}
+	return colls, nil
+}
+
+func isSystemDatabase(name string) bool {
+	switch name {
+	case "admin", "config", "local":
+		return true
+	}
+	return false
+}
+
 func (m *mongoCDC) readSnapshot(ctx context.Context, coll *mongo.Collection, cp *checkpoint.Capped[bson.Raw]) (err error) {
 	if m.snapshotParallelism == 0 {
 		return nil
@@ -448,14 +527,14 @@ func (m *mongoCDC) computeSplitPoints(ctx context.Context, coll *mongo.Collectio
 	}
 	chunkSize := max(int(size)/m.snapshotParallelism, 16*humanize.MiByte)
 	command := bson.D{
-		{Key: "splitVector", Value: fmt.Sprintf("%s.%s", m.db.Name(), coll.Name())},
+		{Key: "splitVector", Value: fmt.Sprintf("%s.%s", coll.Database().Name(), coll.Name())},
 		{Key: "keyPattern", Value: bson.D{{Key: "_id", Value: 1}}},
 		{Key: "min", Value: bson.D{{Key: "_id", Value: bson.MinKey{}}}},
 		{Key: "max", Value: bson.D{{Key: "_id", Value: bson.MaxKey{}}}},
 		{Key: "maxChunkSizeBytes", Value: chunkSize},
 	}
 	var result bson.D
-	if err := m.db.RunCommand(ctx, command).Decode(&result); err != nil {
+	if err := coll.Database().RunCommand(ctx, command).Decode(&result); err != nil {
 		return nil, err
 	}
 	splitKeys, ok := bsonGetPath(result, "splitKeys").(bson.A)
@@ -559,7 +638,7 @@ func (m *mongoCDC) readSnapshotRange(ctx context.Context, coll *mongo.Collection
 		if err := cursor.Decode(&doc); err != nil {
 			return fmt.Errorf("unable to decode document: %w", err)
 		}
-		msg, err := m.newMongoDBCDCMessage(doc, "read", coll.Name())
+		msg, err := m.newMongoDBCDCMessage(doc, "read", coll.Database().Name(), coll.Name())
 		if err != nil {
 			return fmt.Errorf("unable to create message from document: %w", err)
 		}
@@ -591,10 +670,20 @@ func (m *mongoCDC) readSnapshotRange(ctx context.Context, coll *mongo.Collection
 }
 
 func (m *mongoCDC) readFromStream(ctx context.Context, cp *checkpoint.Capped[bson.Raw], opts *options.ChangeStreamOptionsBuilder) error {
-	filter := []bson.M{{"$match": bson.M{
-		"ns.coll": bson.M{"$in": slices.Clone(m.collections)},
-	}}}
-	stream, err := m.db.Watch(ctx, filter, opts)
+	var stream *mongo.ChangeStream
+	var err error
+	switch {
+	case m.db == nil:
+		stream, err = m.client.Watch(ctx, []bson.M{{"$match": bson.M{
+			"ns.db": bson.M{"$nin": []string{"admin", "config", "local"}},
+		}}}, opts)
+	case len(m.collections) == 0:
+		stream, err = m.db.Watch(ctx, []bson.M{}, opts)
+	default:
+		stream, err = m.db.Watch(ctx, []bson.M{{"$match": bson.M{
+			"ns.coll": bson.M{"$in": slices.Clone(m.collections)},
+		}}}, opts)
+	}
 	if err != nil {
 		return fmt.Errorf("error opening change stream: %w", err)
 	}
@@ -633,11 +722,15 @@ func (m *mongoCDC) readFromStream(ctx context.Context, cp *checkpoint.Capped[bso
 		if !ok {
 			return fmt.Errorf("invalid ns data: %T", data["ns"])
 		}
+		db, ok := bsonGetPath(ns, "db").(string)
+		if !ok {
+			return fmt.Errorf("unable to extract database from change stream, got: %s", data)
+		}
 		coll, ok := bsonGetPath(ns, "coll").(string)
 		if !ok {
 			return fmt.Errorf("unable to extract collection from change stream, got: %s", data)
 		}
-		msg, err := m.newMongoDBCDCMessage(doc, opType, coll)
+		msg, err := m.newMongoDBCDCMessage(doc, opType, db, coll)
 		if err != nil {
 			return fmt.Errorf("unable to create message from change stream event: %w", err)
 		}
@@ -673,7 +766,7 @@ func (m *mongoCDC) readFromStream(ctx context.Context, cp *checkpoint.Capped[bso
 	return stream.Err()
 }
 
-func (m *mongoCDC) newMongoDBCDCMessage(doc any, operationType, collectionName string) (msg *service.Message, err error) {
+func (m *mongoCDC) newMongoDBCDCMessage(doc any, operationType, databaseName, collectionName string) (msg *service.Message, err error) {
 	var b []byte
 	if doc != nil {
 		b, err = bson.MarshalExtJSON(doc, m.marshalCanonical, false)
@@ -685,6 +778,7 @@ func (m *mongoCDC) newMongoDBCDCMessage(doc any, operationType, collectionName s
 	}
 	msg = service.NewMessage(b)
 	msg.MetaSetMut("operation", operationType)
+	msg.MetaSetMut("database", databaseName)
 	msg.MetaSetMut("collection", collectionName)
 	return msg, nil
 }
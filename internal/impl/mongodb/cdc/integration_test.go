@@ -334,10 +334,10 @@ mongodb_cdc:
       ]`, output.MessagesJSON(t))
 		}
 		require.JSONEq(t, `[
-    {"operation": "insert", "collection": "foo"},
-    {"operation": "replace", "collection": "foo"},
-    {"operation": "update", "collection": "foo"},
-    {"operation": "delete", "collection": "foo"}
+    {"operation": "insert", "database": "test", "collection": "foo"},
+    {"operation": "replace", "database": "test", "collection": "foo"},
+    {"operation": "update", "database": "test", "collection": "foo"},
+    {"operation": "delete", "database": "test", "collection": "foo"}
 ]`, output.MetadataJSON(t))
 	}
 	t.Run("Normal", func(t *testing.T) { runTest(t, false) })
@@ -386,6 +386,7 @@ read_until:
 	// Sanity check to make sure we got past the snapshot phase
 	require.Contains(t, output.Metadata(t), map[string]any{
 		"operation":  "insert",
+		"database":   "test",
 		"collection": "foo",
 	})
 }
@@ -437,7 +438,7 @@ read_until:
 		}
 		require.Empty(t, expected)
 		for _, meta := range output.Metadata(t) {
-			require.Equal(t, map[string]any{"operation": "read", "collection": "foo"}, meta)
+			require.Equal(t, map[string]any{"operation": "read", "database": "test", "collection": "foo"}, meta)
 		}
 	}
 	t.Run("AutoBuckets", func(t *testing.T) { runTest(t, true) })
@@ -547,7 +548,7 @@ mongodb_cdc:
 	stream.Stop(t)
 	wait()
 	require.JSONEq(t, `[{"_id":1,"data":"hello"}, {"_id":3,"data":"hello"}]`, output.MessagesJSON(t))
-	require.JSONEq(t, `[{"operation":"read","collection":"foo"}, {"operation":"insert","collection":"foo"}]`, output.MetadataJSON(t))
+	require.JSONEq(t, `[{"operation": "read", "database": "test", "collection": "foo"}, {"operation": "insert", "database": "test", "collection": "foo"}]`, output.MetadataJSON(t))
 }
 
 func TestIntegrationMongoCDCMultipleCollections(t *testing.T) {
@@ -588,9 +589,9 @@ mongodb_cdc:
 		map[string]any{"_id": json.Number("3"), "data": "!"},
 	}, msgs[0:3])
 	require.ElementsMatch(t, []map[string]any{
-		{"operation": "read", "collection": "foo"},
-		{"operation": "read", "collection": "bar"},
-		{"operation": "read", "collection": "qux"},
+		{"operation": "read", "database": "test", "collection": "foo"},
+		{"operation": "read", "database": "test", "collection": "bar"},
+		{"operation": "read", "database": "test", "collection": "qux"},
 	}, metas[0:3])
 	// Changes must be in order
 	require.Equal(t, []any{
@@ -599,8 +600,61 @@ mongodb_cdc:
 		map[string]any{"_id": json.Number("6"), "data": "!"},
 	}, msgs[3:6])
 	require.Equal(t, []map[string]any{
-		{"operation": "insert", "collection": "foo"},
-		{"operation": "insert", "collection": "bar"},
-		{"operation": "insert", "collection": "qux"},
+		{"operation": "insert", "database": "test", "collection": "foo"},
+		{"operation": "insert", "database": "test", "collection": "bar"},
+		{"operation": "insert", "database": "test", "collection": "qux"},
 	}, metas[3:6])
 }
+
+func TestIntegrationMongoCDCWholeDatabase(t *testing.T) {
+	stream, db, output := setup(t, `
+mongodb_cdc:
+  url: '$URI'
+  database: '$DATABASE'
+  stream_snapshot: true
+  checkpoint_cache: '$CACHE'
+  json_marshal_mode: relaxed
+`)
+	db.CreateCollection(t, "foo")
+	db.CreateCollection(t, "bar")
+	db.InsertOne(t, "foo", bson.M{"_id": 1, "data": "hello"})
+	db.InsertOne(t, "bar", bson.M{"_id": 2, "data": "world"})
+	wait := stream.RunAsync(t)
+	time.Sleep(time.Second)
+	db.InsertOne(t, "foo", bson.M{"_id": 3, "data": "hello"})
+	db.InsertOne(t, "bar", bson.M{"_id": 4, "data": "world"})
+	time.Sleep(time.Second)
+	stream.Stop(t)
+	wait()
+	metas := output.Metadata(t)
+	require.Len(t, metas, 4)
+	require.ElementsMatch(t, []map[string]any{
+		{"operation": "read", "database": "test", "collection": "foo"},
+		{"operation": "read", "database": "test", "collection": "bar"},
+	}, metas[0:2])
+	require.Equal(t, []map[string]any{
+		{"operation": "insert", "database": "test", "collection": "foo"},
+		{"operation": "insert", "database": "test", "collection": "bar"},
+	}, metas[2:4])
+}
+
+func TestIntegrationMongoCDCWholeDeployment(t *testing.T) {
+	stream, db, output := setup(t, `
+mongodb_cdc:
+  url: '$URI'
+  checkpoint_cache: '$CACHE'
+  json_marshal_mode: relaxed
+`)
+	other := &databaseHelper{db.Client().Database("other")}
+	db.CreateCollection(t, "foo")
+	other.CreateCollection(t, "bar")
+	wait := stream.RunAsync(t)
+	time.Sleep(time.Second)
+	db.InsertOne(t, "foo", bson.M{"_id": 1, "data": "hello"})
+	other.InsertOne(t, "bar", bson.M{"_id": 2, "data": "world"})
+	time.Sleep(time.Second)
+	stream.Stop(t)
+	wait()
+	require.JSONEq(t, `[{"_id":1,"data":"hello"}, {"_id":2,"data":"world"}]`, output.MessagesJSON(t))
+	require.JSONEq(t, `[{"operation": "insert", "database": "test", "collection": "foo"}, {"operation": "insert", "database": "other", "collection": "bar"}]`, output.MetadataJSON(t))
+}
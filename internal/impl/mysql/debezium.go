@@ -0,0 +1,70 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/v4/blob/main/licenses/rcl.md
+
+package mysql
+
+import (
+	"time"
+)
+
+var debeziumOperations = map[MessageOperation]string{
+	MessageOperationRead:   "r",
+	MessageOperationInsert: "c",
+	MessageOperationUpdate: "u",
+	MessageOperationDelete: "d",
+}
+
+// debeziumEnvelope wraps a message event in the change event envelope of the
+// Debezium MySQL connector, so that consumers of Debezium change events can
+// process the messages of this input without modification.
+//
+// See: https://debezium.io/documentation/reference/stable/connectors/mysql.html#mysql-change-event-values
+func debeziumEnvelope(me MessageEvent, database string, now time.Time) map[string]any {
+	source := map[string]any{
+		"connector": "mysql",
+		"db":        database,
+		"table":     me.Table,
+		"server_id": me.ServerID,
+		"ts_ms":     int64(0),
+		"snapshot":  "false",
+		"gtid":      nil,
+		"file":      "",
+		"pos":       int64(0),
+	}
+	if me.Operation == MessageOperationRead {
+		source["snapshot"] = "true"
+	}
+	if !me.Timestamp.IsZero() {
+		source["ts_ms"] = me.Timestamp.UnixMilli()
+	}
+	if me.GTID != "" {
+		source["gtid"] = me.GTID
+	}
+	if me.Position != nil {
+		source["file"] = me.Position.Name
+		source["pos"] = int64(me.Position.Pos)
+	}
+
+	var before, after map[string]any
+	switch me.Operation {
+	case MessageOperationDelete:
+		before = me.Row
+	case MessageOperationUpdate:
+		before, after = me.Before, me.Row
+	default:
+		after = me.Row
+	}
+
+	return map[string]any{
+		"before": before,
+		"after":  after,
+		"source": source,
+		"op":     debeziumOperations[me.Operation],
+		"ts_ms":  now.UnixMilli(),
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/v4/blob/main/licenses/rcl.md
+
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebeziumEnvelope(t *testing.T) {
+	now := time.UnixMilli(1700000005000)
+	binlogTime := time.UnixMilli(1700000000000)
+
+	before := map[string]any{"id": 1, "name": "foo"}
+	after := map[string]any{"id": 1, "name": "bar"}
+
+	tests := []struct {
+		name     string
+		event    MessageEvent
+		expected map[string]any
+	}{
+		{
+			name: "snapshot read",
+			event: MessageEvent{
+				Row:       after,
+				Table:     "users",
+				Operation: MessageOperationRead,
+			},
+			expected: map[string]any{
+				"before": map[string]any(nil),
+				"after":  after,
+				"source": map[string]any{
+					"connector": "mysql",
+					"db":        "shop",
+					"table":     "users",
+					"server_id": uint32(0),
+					"ts_ms":     int64(0),
+					"snapshot":  "true",
+					"gtid":      nil,
+					"file":      "",
+					"pos":       int64(0),
+				},
+				"op":    "r",
+				"ts_ms": now.UnixMilli(),
+			},
+		},
+		{
+			name: "update",
+			event: MessageEvent{
+				Row:       after,
+				Before:    before,
+				Table:     "users",
+				Operation: MessageOperationUpdate,
+				Position:  &position{Name: "binlog.000003", Pos: 1234},
+				Timestamp: binlogTime,
+				ServerID:  7,
+				GTID:      "3e11fa47-71ca-11e1-9e33-c80aa9429562:23",
+			},
+			expected: map[string]any{
+				"before": before,
+				"after":  after,
+				"source": map[string]any{
+					"connector": "mysql",
+					"db":        "shop",
+					"table":     "users",
+					"server_id": uint32(7),
+					"ts_ms":     binlogTime.UnixMilli(),
+					"snapshot":  "false",
+					"gtid":      "3e11fa47-71ca-11e1-9e33-c80aa9429562:23",
+					"file":      "binlog.000003",
+					"pos":       int64(1234),
+				},
+				"op":    "u",
+				"ts_ms": now.UnixMilli(),
+			},
+		},
+		{
+			name: "delete",
+			event: MessageEvent{
+				Row:       before,
+				Table:     "users",
+				Operation: MessageOperationDelete,
+				Position:  &position{Name: "binlog.000003", Pos: 2345},
+				Timestamp: binlogTime,
+				ServerID:  7,
+			},
+			expected: map[string]any{
+				"before": before,
+				"after":  map[string]any(nil),
+				"source": map[string]any{
+					"connector": "mysql",
+					"db":        "shop",
+					"table":     "users",
+					"server_id": uint32(7),
+					"ts_ms":     binlogTime.UnixMilli(),
+					"snapshot":  "false",
+					"gtid":      nil,
+					"file":      "binlog.000003",
+					"pos":       int64(2345),
+				},
+				"op":    "d",
+				"ts_ms": now.UnixMilli(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, debeziumEnvelope(test.event, "shop", now))
+		})
+	}
+}
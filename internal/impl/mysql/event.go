@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-mysql-org/go-mysql/mysql"
 )
@@ -38,6 +39,20 @@ type MessageEvent struct {
 	Table     string           `json:"table"`
 	Operation MessageOperation `json:"operation"`
 	Position  *position        `json:"position"`
+
+	// Before is the state of the row prior to an update.
+	Before map[string]any `json:"before"`
+	// Timestamp is the time at which the event was written to the binlog,
+	// which is zero for messages read from a snapshot.
+	Timestamp time.Time `json:"timestamp"`
+	// ServerID is the ID of the server which wrote the event to the binlog.
+	ServerID uint32 `json:"server_id"`
+	// GTID is the ID of the transaction of the event, if GTIDs are enabled.
+	GTID string `json:"gtid"`
+	// GTIDSet is the set of transactions which have been executed prior to
+	// the transaction of the event, which is only tracked when checkpointing
+	// by GTID.
+	GTIDSet string `json:"gtid_set"`
 }
 
 func binlogPositionToString(pos position) string {
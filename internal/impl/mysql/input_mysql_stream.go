@@ -22,9 +22,10 @@ import (
 	"github.com/Jeffail/checkpoint"
 	"github.com/Jeffail/shutdown"
 	"github.com/go-mysql-org/go-mysql/canal"
+	"github.com/go-mysql-org/go-mysql/mysql"
 	"github.com/go-mysql-org/go-mysql/replication"
 	"github.com/go-mysql-org/go-mysql/schema"
-	"github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/redpanda-data/benthos/v4/public/service"
 	"golang.org/x/sync/errgroup"
 
@@ -40,6 +41,9 @@ const (
 	fieldCheckpointKey        = "checkpoint_key"
 	fieldCheckpointCache      = "checkpoint_cache"
 	fieldCheckpointLimit      = "checkpoint_limit"
+	fieldCheckpointMode       = "checkpoint_mode"
+	fieldFlavor               = "flavor"
+	fieldMessageFormat        = "message_format"
 
 	shutdownTimeout = 5 * time.Second
 )
@@ -57,6 +61,16 @@ This input adds the following metadata fields to each message:
 - operation
 - table
 - binlog_position
+- gtid (only present when GTIDs are enabled on the server)
+- gtid_set (only present when `+"`"+fieldCheckpointMode+"`"+` is `+"`gtid`"+`)
+
+== Checkpointing
+
+The position of the latest message which has been successfully delivered is stored in the `+"`"+fieldCheckpointCache+"`"+`. By default this is the binlog file and offset, which is specific to the server that is being streamed from. When `+"`"+fieldCheckpointMode+"`"+` is set to `+"`gtid`"+` the set of executed global transaction identifiers (GTIDs) is stored instead, which allows the stream to resume from a different server of the replication topology after a failover. This requires `+"`gtid_mode=ON`"+` on MySQL servers. Note that the checkpoint of a message is the GTID set prior to its transaction, and therefore the last transaction may be redelivered after a restart.
+
+== Debezium format
+
+When `+"`"+fieldMessageFormat+"`"+` is set to `+"`debezium`"+` each message is a change event with the same envelope as the https://debezium.io/documentation/reference/stable/connectors/mysql.html#mysql-change-event-values[Debezium MySQL connector^], consisting of the `+"`before`"+` and `+"`after`"+` states of the row, the `+"`op`"+` (`+"`r`"+` for snapshot reads, `+"`c`"+` for inserts, `+"`u`"+` for updates and `+"`d`"+` for deletes), the `+"`source`"+` of the change and a `+"`ts_ms`"+` timestamp. Column values are encoded as JSON values rather than with the Debezium schema specific encodings.
 `).
 	Fields(
 		service.NewStringField(fieldMySQLDSN).
@@ -70,6 +84,26 @@ This input adds the following metadata fields to each message:
 		service.NewStringField(fieldCheckpointKey).
 			Description("The key to use to store the snapshot position in `"+fieldCheckpointCache+"`. An alternative key can be provided if multiple CDC inputs share the same cache.").
 			Default("mysql_binlog_position"),
+		service.NewStringAnnotatedEnumField(fieldCheckpointMode, map[string]string{
+			"binlog_position": "Store the binlog file and offset of the latest delivered message.",
+			"gtid":            "Store the set of executed GTIDs prior to the transaction of the latest delivered message.",
+		}).
+			Description("The type of position to store in the `"+fieldCheckpointCache+"`. Checkpoints of one type cannot be resumed from with another, and therefore a different `"+fieldCheckpointKey+"` should be used when changing this field.").
+			Default("binlog_position").
+			Version("4.48.0").
+			Advanced(),
+		service.NewStringEnumField(fieldFlavor, mysql.MySQLFlavor, mysql.MariaDBFlavor).
+			Description("The flavor of the database server, which determines the format of GTIDs.").
+			Default(mysql.MySQLFlavor).
+			Version("4.48.0").
+			Advanced(),
+		service.NewStringAnnotatedEnumField(fieldMessageFormat, map[string]string{
+			"row":      "Each message is the state of the row after the change, or before the change for deletes.",
+			"debezium": "Each message is a change event in the envelope format of the Debezium MySQL connector.",
+		}).
+			Description("The format of the emitted messages.").
+			Default("row").
+			Version("4.48.0"),
 		service.NewIntField(fieldSnapshotMaxBatchSize).
 			Description("The maximum number of rows to be streamed in a single batch when taking a snapshot.").
 			Default(1000),
@@ -93,10 +127,18 @@ type mysqlStreamInput struct {
 	mutex sync.Mutex
 	// canal stands for mysql binlog listener connection
 	canal             *canal.Canal
-	mysqlConfig       *mysql.Config
+	mysqlConfig       *mysqldriver.Config
 	binLogCache       string
 	binLogCacheKey    string
 	currentBinlogName string
+	flavor            string
+	gtidCheckpoints   bool
+	debeziumFormat    bool
+
+	// The GTID of the current transaction, and the set of GTIDs executed prior
+	// to it when checkpointing by GTID.
+	currentGTID    string
+	currentGTIDSet string
 
 	dsn            string
 	tables         []string
@@ -113,7 +155,7 @@ type mysqlStreamInput struct {
 
 	rawMessageEvents chan MessageEvent
 	msgChan          chan asyncMessage
-	cp               *checkpoint.Capped[*position]
+	cp               *checkpoint.Capped[*string]
 
 	shutSig *shutdown.Signaller
 }
@@ -136,7 +178,7 @@ func newMySQLStreamInput(conf *service.ParsedConfig, res *service.Resources) (s
 		return nil, err
 	}
 
-	i.mysqlConfig, err = mysql.ParseDSN(i.dsn)
+	i.mysqlConfig, err = mysqldriver.ParseDSN(i.dsn)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing mysql DSN: %v", err)
 	}
@@ -167,7 +209,23 @@ func newMySQLStreamInput(conf *service.ParsedConfig, res *service.Resources) (s
 		return nil, err
 	}
 
-	i.cp = checkpoint.NewCapped[*position](int64(i.checkPointLimit))
+	var checkpointMode string
+	if checkpointMode, err = conf.FieldString(fieldCheckpointMode); err != nil {
+		return nil, err
+	}
+	i.gtidCheckpoints = checkpointMode == "gtid"
+
+	if i.flavor, err = conf.FieldString(fieldFlavor); err != nil {
+		return nil, err
+	}
+
+	var messageFormat string
+	if messageFormat, err = conf.FieldString(fieldMessageFormat); err != nil {
+		return nil, err
+	}
+	i.debeziumFormat = messageFormat == "debezium"
+
+	i.cp = checkpoint.NewCapped[*string](int64(i.checkPointLimit))
 
 	i.tablesFilterMap = map[string]bool{}
 	for _, table := range i.tables {
@@ -212,6 +270,7 @@ func (i *mysqlStreamInput) Connect(ctx context.Context) error {
 	canalConfig.Addr = i.mysqlConfig.Addr
 	canalConfig.User = i.mysqlConfig.User
 	canalConfig.Password = i.mysqlConfig.Passwd
+	canalConfig.Flavor = i.flavor
 	// resetting dump path since we are doing snapshot manually
 	// this is required since canal will try to prepare dumper on init stage
 	canalConfig.Dump.ExecutionPath = ""
@@ -239,18 +298,21 @@ func (i *mysqlStreamInput) Connect(ctx context.Context) error {
 
 	i.canal = c
 
-	pos, err := i.getCachedBinlogPosition(ctx)
+	start, err := i.getCachedCheckpoint(ctx)
 	if err != nil {
-		return fmt.Errorf("unable to get cached binlog position: %s", err)
+		return fmt.Errorf("unable to get cached checkpoint: %s", err)
 	}
 	// create snapshot instance if we were requested and haven't finished it before.
 	var snapshot *Snapshot
-	if i.streamSnapshot && pos == nil {
+	if i.streamSnapshot && start == nil {
 		db, err := sql.Open("mysql", i.dsn)
 		if err != nil {
 			return fmt.Errorf("failed to connect to MySQL server: %s", err)
 		}
 		snapshot = NewSnapshot(i.logger, db)
+		if i.gtidCheckpoints {
+			snapshot.gtidFlavor = i.flavor
+		}
 	}
 
 	// Reset the shutSig
@@ -265,7 +327,7 @@ func (i *mysqlStreamInput) Connect(ctx context.Context) error {
 			return nil
 		})
 		wg.Go(func() error { return i.readMessages(ctx) })
-		wg.Go(func() error { return i.startMySQLSync(ctx, pos, snapshot) })
+		wg.Go(func() error { return i.startMySQLSync(ctx, start, snapshot) })
 		if err := wg.Wait(); err != nil && !errors.Is(err, context.Canceled) {
 			i.logger.Errorf("error during MySQL CDC: %s", err)
 		} else {
@@ -277,7 +339,20 @@ func (i *mysqlStreamInput) Connect(ctx context.Context) error {
 	return nil
 }
 
-func (i *mysqlStreamInput) startMySQLSync(ctx context.Context, pos *position, snapshot *Snapshot) error {
+// startPosition is the position to start streaming the binlog from, where the
+// GTID set takes precedence when present.
+type startPosition struct {
+	pos     *position
+	gtidSet mysql.GTIDSet
+}
+
+func (i *mysqlStreamInput) startMySQLSync(ctx context.Context, start *startPosition, snapshot *Snapshot) error {
+	var pos *position
+	var gtidSet mysql.GTIDSet
+	if start != nil {
+		pos, gtidSet = start.pos, start.gtidSet
+	}
+
 	// If we are given a snapshot, then we need to read it.
 	if snapshot != nil {
 		startPos, err := snapshot.prepareSnapshot(ctx)
@@ -296,17 +371,30 @@ func (i *mysqlStreamInput) startMySQLSync(ctx context.Context, pos *position, sn
 		if err = snapshot.close(); err != nil {
 			return fmt.Errorf("unable to close snapshot: %w", err)
 		}
-		pos = startPos
-	} else if pos == nil {
+		pos, gtidSet = startPos, snapshot.gtidSet
+	} else if i.gtidCheckpoints && gtidSet == nil {
+		var err error
+		if gtidSet, err = i.canal.GetMasterGTIDSet(); err != nil {
+			return fmt.Errorf("unable to get start GTID set: %w", err)
+		}
+	} else if !i.gtidCheckpoints && pos == nil {
 		coords, err := i.canal.GetMasterPos()
 		if err != nil {
 			return fmt.Errorf("unable to get start binlog position: %w", err)
 		}
 		pos = &coords
 	}
+	i.canal.SetEventHandler(i)
+	if gtidSet != nil {
+		i.logger.Infof("starting MySQL CDC stream from GTID set %s", gtidSet)
+		i.currentGTIDSet = gtidSet.String()
+		if err := i.canal.StartFromGTID(gtidSet); err != nil {
+			return fmt.Errorf("failed to start streaming: %w", err)
+		}
+		return nil
+	}
 	i.logger.Infof("starting MySQL CDC stream from binlog %s at offset %d", pos.Name, pos.Pos)
 	i.currentBinlogName = pos.Name
-	i.canal.SetEventHandler(i)
 	if err := i.canal.RunFrom(*pos); err != nil {
 		return fmt.Errorf("failed to start streaming: %w", err)
 	}
@@ -502,7 +590,11 @@ func (i *mysqlStreamInput) readMessages(ctx context.Context) error {
 				return fmt.Errorf("failed to flush periodic batch: %w", err)
 			}
 		case me := <-i.rawMessageEvents:
-			row, err := json.Marshal(me.Row)
+			var value any = me.Row
+			if i.debeziumFormat {
+				value = debeziumEnvelope(me, i.mysqlConfig.DBName, time.Now())
+			}
+			row, err := json.Marshal(value)
 			if err != nil {
 				return fmt.Errorf("failed to serialize row: %w", err)
 			}
@@ -513,6 +605,12 @@ func (i *mysqlStreamInput) readMessages(ctx context.Context) error {
 			if me.Position != nil {
 				mb.MetaSet("binlog_position", binlogPositionToString(*me.Position))
 			}
+			if me.GTID != "" {
+				mb.MetaSet("gtid", me.GTID)
+			}
+			if me.GTIDSet != "" {
+				mb.MetaSet("gtid_set", me.GTIDSet)
+			}
 
 			if i.batchPolicy.Add(mb) {
 				nextTimedBatchChan = nil
@@ -535,25 +633,25 @@ func (i *mysqlStreamInput) readMessages(ctx context.Context) error {
 
 func (i *mysqlStreamInput) flushBatch(
 	ctx context.Context,
-	checkpointer *checkpoint.Capped[*position],
+	checkpointer *checkpoint.Capped[*string],
 	batch service.MessageBatch,
 ) error {
 	if len(batch) == 0 {
 		return nil
 	}
 
+	checkpointKey := "binlog_position"
+	if i.gtidCheckpoints {
+		checkpointKey = "gtid_set"
+	}
+
 	lastMsg := batch[len(batch)-1]
-	strPosition, ok := lastMsg.MetaGet("binlog_position")
-	var binLogPos *position
-	if ok {
-		pos, err := parseBinlogPosition(strPosition)
-		if err != nil {
-			return err
-		}
-		binLogPos = &pos
+	var checkpointPos *string
+	if strPosition, ok := lastMsg.MetaGet(checkpointKey); ok {
+		checkpointPos = &strPosition
 	}
 
-	resolveFn, err := checkpointer.Track(ctx, binLogPos, int64(len(batch)))
+	resolveFn, err := checkpointer.Track(ctx, checkpointPos, int64(len(batch)))
 	if err != nil {
 		return fmt.Errorf("failed to track checkpoint for batch: %w", err)
 	}
@@ -572,7 +670,7 @@ func (i *mysqlStreamInput) flushBatch(
 			if offset == nil {
 				return nil
 			}
-			return i.setCachedBinlogPosition(ctx, *offset)
+			return i.setCachedCheckpoint(ctx, *offset)
 		},
 	}
 	select {
@@ -618,7 +716,7 @@ func (i *mysqlStreamInput) Close(ctx context.Context) error {
 
 // ---- cache methods start ----
 
-func (i *mysqlStreamInput) getCachedBinlogPosition(ctx context.Context) (*position, error) {
+func (i *mysqlStreamInput) getCachedCheckpoint(ctx context.Context) (*startPosition, error) {
 	var (
 		cacheVal []byte
 		cErr     error
@@ -635,17 +733,24 @@ func (i *mysqlStreamInput) getCachedBinlogPosition(ctx context.Context) (*positi
 	} else if cacheVal == nil {
 		return nil, nil
 	}
+	if i.gtidCheckpoints {
+		gtidSet, err := mysql.ParseGTIDSet(i.flavor, string(cacheVal))
+		if err != nil {
+			return nil, fmt.Errorf("invalid GTID set checkpoint: %w", err)
+		}
+		return &startPosition{gtidSet: gtidSet}, nil
+	}
 	pos, err := parseBinlogPosition(string(cacheVal))
-	return &pos, err
+	return &startPosition{pos: &pos}, err
 }
 
-func (i *mysqlStreamInput) setCachedBinlogPosition(ctx context.Context, binLogPos position) error {
+func (i *mysqlStreamInput) setCachedCheckpoint(ctx context.Context, checkpoint string) error {
 	var cErr error
 	if err := i.res.AccessCache(ctx, i.binLogCache, func(c service.Cache) {
 		cErr = c.Set(
 			ctx,
 			i.binLogCacheKey,
-			[]byte(binlogPositionToString(binLogPos)),
+			[]byte(checkpoint),
 			nil,
 		)
 	}); err != nil {
@@ -666,6 +771,20 @@ func (i *mysqlStreamInput) OnRotate(eh *replication.EventHeader, re *replication
 	return nil
 }
 
+func (i *mysqlStreamInput) OnGTID(eh *replication.EventHeader, ge mysql.BinlogGTIDEvent) error {
+	gtid, err := ge.GTIDNext()
+	if err != nil {
+		return err
+	}
+	i.currentGTID = gtid.String()
+	if i.gtidCheckpoints {
+		if synced := i.canal.SyncedGTIDSet(); synced != nil {
+			i.currentGTIDSet = synced.String()
+		}
+	}
+	return nil
+}
+
 func (i *mysqlStreamInput) OnRow(e *canal.RowsEvent) error {
 	if _, ok := i.tablesFilterMap[e.Table.Name]; !ok {
 		return nil
@@ -685,25 +804,45 @@ func (i *mysqlStreamInput) OnRow(e *canal.RowsEvent) error {
 
 func (i *mysqlStreamInput) onMessage(e *canal.RowsEvent, initValue, incrementValue int) error {
 	for pi := initValue; pi < len(e.Rows); pi += incrementValue {
-		message := map[string]any{}
-		for i, v := range e.Rows[pi] {
-			col := e.Table.Columns[i]
-			v, err := mapMessageColumn(v, col)
-			if err != nil {
-				return err
-			}
-			message[col.Name] = v
+		message, err := mapMessageRow(e.Table, e.Rows[pi])
+		if err != nil {
+			return err
 		}
-		i.rawMessageEvents <- MessageEvent{
+		me := MessageEvent{
 			Row:       message,
 			Operation: MessageOperation(e.Action),
 			Table:     e.Table.Name,
 			Position:  &position{Name: i.currentBinlogName, Pos: e.Header.LogPos},
+			Timestamp: time.Unix(int64(e.Header.Timestamp), 0),
+			ServerID:  e.Header.ServerID,
+			GTID:      i.currentGTID,
+		}
+		if i.gtidCheckpoints {
+			me.GTIDSet = i.currentGTIDSet
+		}
+		if i.debeziumFormat && e.Action == canal.UpdateAction {
+			if me.Before, err = mapMessageRow(e.Table, e.Rows[pi-1]); err != nil {
+				return err
+			}
 		}
+		i.rawMessageEvents <- me
 	}
 	return nil
 }
 
+func mapMessageRow(table *schema.Table, row []any) (map[string]any, error) {
+	message := map[string]any{}
+	for i, v := range row {
+		col := table.Columns[i]
+		v, err := mapMessageColumn(v, col)
+		if err != nil {
+			return nil, err
+		}
+		message[col.Name] = v
+	}
+	return message, nil
+}
+
 func mapMessageColumn(v any, col schema.TableColumn) (any, error) {
 	if v == nil {
 		return v, nil
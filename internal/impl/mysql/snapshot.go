@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-mysql-org/go-mysql/mysql"
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
@@ -26,6 +27,11 @@ type Snapshot struct {
 	lockConn     *sql.Conn
 	snapshotConn *sql.Conn
 
+	// When set, the executed GTID set of the server is captured along with
+	// the binlog position of the snapshot.
+	gtidFlavor string
+	gtidSet    mysql.GTIDSet
+
 	logger *service.Logger
 }
 
@@ -107,6 +113,9 @@ func (s *Snapshot) prepareSnapshot(ctx context.Context) (*position, error) {
 
 	// Get binary log position (while locked)
 	pos, err := s.getCurrentBinlogPosition(ctx)
+	if err == nil && s.gtidFlavor != "" {
+		s.gtidSet, err = s.getCurrentGTIDSet(ctx)
+	}
 	if err != nil {
 		// Make sure to release the lock if we fail
 		if _, eErr := s.lockConn.ExecContext(ctx, "UNLOCK TABLES"); eErr != nil {
@@ -223,6 +232,19 @@ func (s *Snapshot) getCurrentBinlogPosition(ctx context.Context) (position, erro
 	}, nil
 }
 
+func (s *Snapshot) getCurrentGTIDSet(ctx context.Context) (mysql.GTIDSet, error) {
+	query := "SELECT @@GLOBAL.gtid_executed"
+	if s.gtidFlavor == mysql.MariaDBFlavor {
+		query = "SELECT @@GLOBAL.gtid_current_pos"
+	}
+
+	var gtidSet string
+	if err := s.snapshotConn.QueryRowContext(ctx, query).Scan(&gtidSet); err != nil {
+		return nil, err
+	}
+	return mysql.ParseGTIDSet(s.gtidFlavor, gtidSet)
+}
+
 func (s *Snapshot) releaseSnapshot(_ context.Context) error {
 	if s.tx != nil {
 		if err := s.tx.Commit(); err != nil {
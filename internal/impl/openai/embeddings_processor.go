@@ -11,7 +11,9 @@ package openai
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/pkoukk/tiktoken-go"
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
 	oai "github.com/sashabaranov/go-openai"
@@ -20,12 +22,18 @@ import (
 )
 
 const (
-	oepFieldTextMapping = "text_mapping"
-	oepFieldDims        = "dimensions"
+	oepFieldTextMapping       = "text_mapping"
+	oepFieldDims              = "dimensions"
+	oepFieldMaxBatchInputs    = "max_batch_inputs"
+	oepFieldMaxBatchTokens    = "max_batch_tokens"
+	oepFieldChunking          = "chunking"
+	oepChunkingFieldEnabled   = "enabled"
+	oepChunkingFieldMaxTokens = "max_tokens"
+	oepChunkingFieldOverlap   = "overlap"
 )
 
 func init() {
-	err := service.RegisterProcessor(
+	err := service.RegisterBatchProcessor(
 		"openai_embeddings",
 		embeddingProcessorConfig(),
 		makeEmbeddingsProcessor,
@@ -42,7 +50,19 @@ func embeddingProcessorConfig() *service.ConfigSpec {
 		Description(`
 This processor sends text strings to the OpenAI API, which generates vector embeddings. By default, the processor submits the entire payload of each message as a string, unless you use the `+"`"+oepFieldTextMapping+"`"+` configuration field to customize it.
 
-To learn more about vector embeddings, see the https://platform.openai.com/docs/guides/embeddings[OpenAI API documentation^].`).
+The texts of all messages within a batch are sent in as few requests as possible, within the limits set by the `+"`"+oepFieldMaxBatchInputs+"` and `"+oepFieldMaxBatchTokens+"`"+` fields. Tokens are counted with the tokenizer of the model, or the `+"`cl100k_base`"+` tokenizer when the model is not recognized.
+
+To learn more about vector embeddings, see the https://platform.openai.com/docs/guides/embeddings[OpenAI API documentation^].
+
+== Chunking
+
+Texts that exceed the input limit of a model are rejected by the API. When `+"`"+oepFieldChunking+"."+oepChunkingFieldEnabled+"`"+` is `+"`true`"+`, texts longer than `+"`"+oepFieldChunking+"."+oepChunkingFieldMaxTokens+"`"+` tokens are instead split into overlapping chunks, and the processor emits a copy of the message for each chunk containing the embedding of that chunk. The following metadata fields are added to every message when chunking is enabled:
+
+`+"```text"+`
+- embeddings_chunk_index
+- embeddings_chunk_count
+- embeddings_chunk_text
+`+"```"+``).
 		Version("4.32.0").
 		Fields(
 			baseConfigFieldsWithModels(
@@ -58,6 +78,34 @@ To learn more about vector embeddings, see the https://platform.openai.com/docs/
 			service.NewIntField(oepFieldDims).
 				Description("The number of dimensions the resulting output embeddings should have. Only supported in `text-embedding-3` and later models.").
 				Optional(),
+			service.NewIntField(oepFieldMaxBatchInputs).
+				Description("The maximum number of texts to send in a single request.").
+				Default(2048).
+				Advanced().
+				Version("4.48.0").
+				LintRule(`root = if this < 1 { ["field must be greater than or equal to 1"] }`),
+			service.NewIntField(oepFieldMaxBatchTokens).
+				Description("The maximum total number of tokens to send in a single request. A text that exceeds this limit on its own is sent in a request of its own.").
+				Default(300000).
+				Advanced().
+				Version("4.48.0").
+				LintRule(`root = if this < 1 { ["field must be greater than or equal to 1"] }`),
+			service.NewObjectField(oepFieldChunking,
+				service.NewBoolField(oepChunkingFieldEnabled).
+					Description("Whether to split texts that exceed `"+oepChunkingFieldMaxTokens+"` tokens into chunks.").
+					Default(false),
+				service.NewIntField(oepChunkingFieldMaxTokens).
+					Description("The maximum number of tokens within a chunk.").
+					Default(8191).
+					LintRule(`root = if this < 1 { ["field must be greater than or equal to 1"] }`),
+				service.NewIntField(oepChunkingFieldOverlap).
+					Description("The number of tokens at the end of each chunk to repeat at the start of the next chunk.").
+					Default(0).
+					LintRule(`root = if this < 0 { ["field must be greater than or equal to 0"] }`),
+			).
+				Description("Split long texts into overlapping chunks, emitting a message for each chunk.").
+				Advanced().
+				Version("4.48.0"),
 		).
 		Example(
 			"Store embedding vectors in Pinecone",
@@ -78,10 +126,46 @@ output:
     host: "${PINECONE_HOST}"
     api_key: "${PINECONE_API_KEY}"
     id: "root = uuid_v4()"
-    vector_mapping: "root = this"`)
+    vector_mapping: "root = this"`).
+		Example(
+			"Chunk documents for retrieval",
+			"Compute embeddings for documents of any length in batches of 100, storing each chunk along with its text within xref:components:outputs/qdrant.adoc[Qdrant].",
+			`
+input:
+  broker:
+    inputs:
+      - file:
+          paths: [ ./docs/*.md ]
+          scanner:
+            to_the_end: {}
+        processors:
+          - mapping: 'root.text = content().string()'
+    batching:
+      count: 100
+      period: 1s
+pipeline:
+  processors:
+  - openai_embeddings:
+      model: text-embedding-3-small
+      api_key: "${OPENAI_API_KEY}"
+      text_mapping: "root = this.text"
+      chunking:
+        enabled: true
+        max_tokens: 512
+        overlap: 64
+output:
+  qdrant:
+    grpc_host: localhost:6334
+    collection_name: docs
+    id: 'root = uuid_v4()'
+    vector_mapping: 'root = this'
+    payload_mapping: |
+      root.path = @path
+      root.chunk = @embeddings_chunk_index
+      root.text = @embeddings_chunk_text`)
 }
 
-func makeEmbeddingsProcessor(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+func makeEmbeddingsProcessor(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchProcessor, error) {
 	if err := license.CheckRunningEnterprise(mgr); err != nil {
 		return nil, err
 	}
@@ -90,67 +174,199 @@ func makeEmbeddingsProcessor(conf *service.ParsedConfig, mgr *service.Resources)
 	if err != nil {
 		return nil, err
 	}
-	var t *bloblang.Executor
+	p := &embeddingsProcessor{baseProcessor: b}
 	if conf.Contains(oepFieldTextMapping) {
-		t, err = conf.FieldBloblang(oepFieldTextMapping)
-		if err != nil {
+		if p.text, err = conf.FieldBloblang(oepFieldTextMapping); err != nil {
 			return nil, err
 		}
 	}
-	var dims *int
 	if conf.Contains(oepFieldDims) {
 		v, err := conf.FieldInt(oepFieldDims)
 		if err != nil {
 			return nil, err
 		}
-		dims = &v
+		p.dimensions = &v
+	}
+	if p.maxBatchInputs, err = conf.FieldInt(oepFieldMaxBatchInputs); err != nil {
+		return nil, err
+	}
+	if p.maxBatchTokens, err = conf.FieldInt(oepFieldMaxBatchTokens); err != nil {
+		return nil, err
+	}
+	chunkConf := conf.Namespace(oepFieldChunking)
+	if p.chunking, err = chunkConf.FieldBool(oepChunkingFieldEnabled); err != nil {
+		return nil, err
 	}
-	return &embeddingsProcessor{b, t, dims}, nil
+	if p.chunkMaxTokens, err = chunkConf.FieldInt(oepChunkingFieldMaxTokens); err != nil {
+		return nil, err
+	}
+	if p.chunkOverlap, err = chunkConf.FieldInt(oepChunkingFieldOverlap); err != nil {
+		return nil, err
+	}
+	if p.chunking && p.chunkOverlap >= p.chunkMaxTokens {
+		return nil, fmt.Errorf("field `%s.%s` must be less than `%s.%s`", oepFieldChunking, oepChunkingFieldOverlap, oepFieldChunking, oepChunkingFieldMaxTokens)
+	}
+	if p.tokenizer, err = tokenizerForModel(p.model); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 type embeddingsProcessor struct {
 	*baseProcessor
 
-	text       *bloblang.Executor
-	dimensions *int
+	text           *bloblang.Executor
+	dimensions     *int
+	maxBatchInputs int
+	maxBatchTokens int
+	chunking       bool
+	chunkMaxTokens int
+	chunkOverlap   int
+	tokenizer      *tiktoken.Tiktoken
 }
 
-func (p *embeddingsProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
-	var body oai.EmbeddingRequestStrings
-	body.Model = oai.EmbeddingModel(p.model)
-	if p.dimensions != nil {
-		body.Dimensions = *p.dimensions
-	}
-	if p.text != nil {
-		s, err := msg.BloblangQuery(p.text)
+// embeddingInput is a text to generate an embedding for, which is either the
+// whole text of a message or a chunk of it.
+type embeddingInput struct {
+	msg        *service.Message
+	err        error
+	text       string
+	tokens     int
+	chunkIndex int
+	chunkCount int
+	embedding  []float32
+}
+
+func (p *embeddingsProcessor) ProcessBatch(ctx context.Context, batch service.MessageBatch) ([]service.MessageBatch, error) {
+	var inputs []*embeddingInput
+	for _, msg := range batch {
+		text, err := p.computeText(msg)
 		if err != nil {
-			return nil, fmt.Errorf("%s execution error: %w", oepFieldTextMapping, err)
+			inputs = append(inputs, &embeddingInput{msg: msg, err: err})
+			continue
 		}
-		r, err := s.AsBytes()
-		if err != nil {
-			return nil, fmt.Errorf("%s extraction error: %w", oepFieldTextMapping, err)
+		tokens := p.tokenizer.EncodeOrdinary(text)
+		if !p.chunking || len(tokens) <= p.chunkMaxTokens {
+			inputs = append(inputs, &embeddingInput{msg: msg, text: text, tokens: len(tokens), chunkCount: 1})
+			continue
+		}
+		chunks := chunkTokens(tokens, p.chunkMaxTokens, p.chunkOverlap)
+		for i, chunk := range chunks {
+			inputs = append(inputs, &embeddingInput{
+				msg:        msg,
+				text:       strings.ToValidUTF8(p.tokenizer.Decode(chunk), ""),
+				tokens:     len(chunk),
+				chunkIndex: i,
+				chunkCount: len(chunks),
+			})
+		}
+	}
+
+	var pending []*embeddingInput
+	var pendingTokens int
+	for _, in := range inputs {
+		if in.err != nil {
+			continue
+		}
+		if len(pending) > 0 && (len(pending) >= p.maxBatchInputs || pendingTokens+in.tokens > p.maxBatchTokens) {
+			p.embedOrFail(ctx, pending)
+			pending, pendingTokens = nil, 0
+		}
+		pending = append(pending, in)
+		pendingTokens += in.tokens
+	}
+	if len(pending) > 0 {
+		p.embedOrFail(ctx, pending)
+	}
+
+	out := make(service.MessageBatch, 0, len(inputs))
+	for _, in := range inputs {
+		msg := in.msg.Copy()
+		if in.err != nil {
+			msg.SetError(in.err)
+			out = append(out, msg)
+			continue
+		}
+		data := make([]any, len(in.embedding))
+		for i, f := range in.embedding {
+			data[i] = f
+		}
+		msg.SetStructuredMut(data)
+		if p.chunking {
+			msg.MetaSetMut("embeddings_chunk_index", in.chunkIndex)
+			msg.MetaSetMut("embeddings_chunk_count", in.chunkCount)
+			msg.MetaSetMut("embeddings_chunk_text", in.text)
 		}
-		body.Input = append(body.Input, string(r))
-	} else {
+		out = append(out, msg)
+	}
+	return []service.MessageBatch{out}, nil
+}
+
+func (p *embeddingsProcessor) computeText(msg *service.Message) (string, error) {
+	if p.text == nil {
 		b, err := msg.AsBytes()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
-		body.Input = append(body.Input, string(b))
+		return string(b), nil
+	}
+	s, err := msg.BloblangQuery(p.text)
+	if err != nil {
+		return "", fmt.Errorf("%s execution error: %w", oepFieldTextMapping, err)
+	}
+	r, err := s.AsBytes()
+	if err != nil {
+		return "", fmt.Errorf("%s extraction error: %w", oepFieldTextMapping, err)
+	}
+	return string(r), nil
+}
+
+// embedOrFail generates the embeddings of the inputs with a single request, and
+// when it fails sets the error on the inputs so that only the messages of the
+// failed request are flagged.
+func (p *embeddingsProcessor) embedOrFail(ctx context.Context, inputs []*embeddingInput) {
+	if err := p.embed(ctx, inputs); err != nil {
+		for _, in := range inputs {
+			in.err = err
+		}
+	}
+}
+
+// embed generates the embeddings of the inputs with a single request.
+func (p *embeddingsProcessor) embed(ctx context.Context, inputs []*embeddingInput) error {
+	var body oai.EmbeddingRequestStrings
+	body.Model = oai.EmbeddingModel(p.model)
+	if p.dimensions != nil {
+		body.Dimensions = *p.dimensions
+	}
+	for _, in := range inputs {
+		body.Input = append(body.Input, in.text)
 	}
 	resp, err := p.client.CreateEmbeddings(ctx, body)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if len(resp.Data) != len(inputs) {
+		return fmt.Errorf("expected %d embeddings in response, got: %d", len(inputs), len(resp.Data))
 	}
-	if len(resp.Data) != 1 {
-		return nil, fmt.Errorf("expected a single embeddings response, got: %d", len(resp.Data))
+	for _, embd := range resp.Data {
+		if embd.Index < 0 || embd.Index >= len(inputs) {
+			return fmt.Errorf("embeddings response contained unexpected index: %d", embd.Index)
+		}
+		inputs[embd.Index].embedding = embd.Embedding
 	}
-	embd := resp.Data[0]
-	data := make([]any, len(embd.Embedding))
-	for i, f := range embd.Embedding {
-		data[i] = f
+	return nil
+}
+
+// chunkTokens splits tokens into chunks of at most size tokens, where each
+// chunk begins with the last overlap tokens of the previous chunk.
+func chunkTokens(tokens []int, size, overlap int) [][]int {
+	var chunks [][]int
+	for start := 0; ; start += size - overlap {
+		end := min(start+size, len(tokens))
+		chunks = append(chunks, tokens[start:end])
+		if end == len(tokens) {
+			return chunks
+		}
 	}
-	msg = msg.Copy()
-	msg.SetStructuredMut(data)
-	return service.MessageBatch{msg}, nil
 }
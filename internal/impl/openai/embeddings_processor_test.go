@@ -10,6 +10,8 @@ package openai
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/go-faker/faker/v4"
@@ -44,35 +46,189 @@ func (m *mockEmbeddingsClient) CreateEmbeddings(ctx context.Context, genericBody
 	return
 }
 
-func TestEmbedding(t *testing.T) {
-	text, err := bloblang.GlobalEnvironment().Parse(`content().string()`)
-	assert.NoError(t, err)
-	p := embeddingsProcessor{
+func newEmbeddingsProcessorForTest(t *testing.T, client client, text *bloblang.Executor) *embeddingsProcessor {
+	t.Helper()
+	tokenizer, err := tokenizerForModel("text-embedding-ada-002")
+	require.NoError(t, err)
+	return &embeddingsProcessor{
 		baseProcessor: &baseProcessor{
-			client: &mockEmbeddingsClient{},
+			client: client,
 			model:  "text-embedding-ada-002",
 		},
-		text: text,
+		text:           text,
+		maxBatchInputs: 2048,
+		maxBatchTokens: 300000,
+		tokenizer:      tokenizer,
 	}
+}
+
+func TestEmbedding(t *testing.T) {
+	text, err := bloblang.GlobalEnvironment().Parse(`content().string()`)
+	assert.NoError(t, err)
+	p := newEmbeddingsProcessorForTest(t, &mockEmbeddingsClient{}, text)
 	input := service.NewMessage([]byte(faker.Paragraph(options.WithGenerateUniqueValues(true))))
-	output, err := p.Process(context.Background(), input)
+	output, err := p.ProcessBatch(context.Background(), service.MessageBatch{input})
 	assert.NoError(t, err)
-	assert.Len(t, output, 1)
-	msg := output[0]
+	require.Len(t, output, 1)
+	assert.Len(t, output[0], 1)
+	msg := output[0][0]
 	require.NoError(t, msg.GetError())
 }
 
 func TestEmbeddingInterpolationError(t *testing.T) {
 	text, err := bloblang.GlobalEnvironment().Parse(`throw("kaboom!")`)
 	assert.NoError(t, err)
-	p := embeddingsProcessor{
-		baseProcessor: &baseProcessor{
-			client: &mockEmbeddingsClient{},
-			model:  "text-embedding-ada-002",
-		},
-		text: text,
-	}
+	p := newEmbeddingsProcessorForTest(t, &mockEmbeddingsClient{}, text)
 	input := service.NewMessage([]byte(faker.Paragraph(options.WithGenerateUniqueValues(true))))
-	_, err = p.Process(context.Background(), input)
-	assert.Error(t, err)
+	output, err := p.ProcessBatch(context.Background(), service.MessageBatch{input})
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	require.Len(t, output[0], 1)
+	assert.Error(t, output[0][0].GetError())
+}
+
+type recordingEmbeddingsClient struct {
+	mockEmbeddingsClient
+	requests [][]string
+}
+
+func (r *recordingEmbeddingsClient) CreateEmbeddings(ctx context.Context, genericBody oai.EmbeddingRequestConverter) (oai.EmbeddingResponse, error) {
+	r.requests = append(r.requests, genericBody.(oai.EmbeddingRequestStrings).Input)
+	return r.mockEmbeddingsClient.CreateEmbeddings(ctx, genericBody)
+}
+
+func TestEmbeddingBatching(t *testing.T) {
+	text, err := bloblang.GlobalEnvironment().Parse(`root = if content().string() == "bad" { throw("kaboom!") } else { content() }`)
+	require.NoError(t, err)
+	client := &recordingEmbeddingsClient{}
+	p := newEmbeddingsProcessorForTest(t, client, text)
+	p.maxBatchInputs = 2
+
+	var batch service.MessageBatch
+	for _, s := range []string{"a", "bad", "b", "c", "d", "e"} {
+		batch = append(batch, service.NewMessage([]byte(s)))
+	}
+	output, err := p.ProcessBatch(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	require.Len(t, output[0], 6)
+
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, client.requests)
+	for i, s := range []string{"a", "bad", "b", "c", "d", "e"} {
+		msg := output[0][i]
+		if s == "bad" {
+			require.Error(t, msg.GetError())
+			continue
+		}
+		require.NoError(t, msg.GetError())
+		v, err := msg.AsStructured()
+		require.NoError(t, err)
+		assert.Equal(t, []any{float32(s[0])}, v)
+	}
+}
+
+type failingEmbeddingsClient struct {
+	mockEmbeddingsClient
+	failOn string
+}
+
+func (f *failingEmbeddingsClient) CreateEmbeddings(ctx context.Context, genericBody oai.EmbeddingRequestConverter) (oai.EmbeddingResponse, error) {
+	if slices.Contains(genericBody.(oai.EmbeddingRequestStrings).Input, f.failOn) {
+		return oai.EmbeddingResponse{}, errors.New("too many requests")
+	}
+	return f.mockEmbeddingsClient.CreateEmbeddings(ctx, genericBody)
+}
+
+func TestEmbeddingBatchingRequestError(t *testing.T) {
+	p := newEmbeddingsProcessorForTest(t, &failingEmbeddingsClient{failOn: "c"}, nil)
+	p.maxBatchInputs = 2
+
+	var batch service.MessageBatch
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		batch = append(batch, service.NewMessage([]byte(s)))
+	}
+	output, err := p.ProcessBatch(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	require.Len(t, output[0], 5)
+
+	// Only the messages of the failed request are flagged.
+	for i, s := range []string{"a", "b", "c", "d", "e"} {
+		msg := output[0][i]
+		if s == "c" || s == "d" {
+			require.ErrorContains(t, msg.GetError(), "too many requests")
+			continue
+		}
+		require.NoError(t, msg.GetError())
+		v, err := msg.AsStructured()
+		require.NoError(t, err)
+		assert.Equal(t, []any{float32(s[0])}, v)
+	}
+}
+
+func TestEmbeddingBatchingTokenLimit(t *testing.T) {
+	client := &recordingEmbeddingsClient{}
+	p := newEmbeddingsProcessorForTest(t, client, nil)
+	p.maxBatchTokens = 5
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte("one two three")),
+		service.NewMessage([]byte("four five")),
+		service.NewMessage([]byte("six seven eight nine ten eleven")),
+		service.NewMessage([]byte("twelve")),
+	}
+	_, err := p.ProcessBatch(context.Background(), batch)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"one two three", "four five"},
+		{"six seven eight nine ten eleven"},
+		{"twelve"},
+	}, client.requests)
+}
+
+func TestEmbeddingChunking(t *testing.T) {
+	client := &recordingEmbeddingsClient{}
+	p := newEmbeddingsProcessorForTest(t, client, nil)
+	p.chunking = true
+	p.chunkMaxTokens = 4
+	p.chunkOverlap = 1
+
+	batch := service.MessageBatch{
+		service.NewMessage([]byte(" a b c d e f g h")),
+		service.NewMessage([]byte(" short")),
+	}
+	output, err := p.ProcessBatch(context.Background(), batch)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	require.Len(t, output[0], 4)
+
+	assert.Equal(t, [][]string{{" a b c d", " d e f g", " g h", " short"}}, client.requests)
+	for i, exp := range []struct {
+		index, count int
+		text         string
+	}{
+		{0, 3, " a b c d"},
+		{1, 3, " d e f g"},
+		{2, 3, " g h"},
+		{0, 1, " short"},
+	} {
+		msg := output[0][i]
+		require.NoError(t, msg.GetError())
+		v, ok := msg.MetaGetMut("embeddings_chunk_index")
+		require.True(t, ok)
+		assert.Equal(t, exp.index, v)
+		v, ok = msg.MetaGetMut("embeddings_chunk_count")
+		require.True(t, ok)
+		assert.Equal(t, exp.count, v)
+		v, ok = msg.MetaGetMut("embeddings_chunk_text")
+		require.True(t, ok)
+		assert.Equal(t, exp.text, v)
+	}
+}
+
+func TestChunkTokens(t *testing.T) {
+	tokens := []int{0, 1, 2, 3, 4, 5, 6}
+	assert.Equal(t, [][]int{{0, 1, 2, 3, 4, 5, 6}}, chunkTokens(tokens, 7, 2))
+	assert.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}, {6}}, chunkTokens(tokens, 3, 0))
+	assert.Equal(t, [][]int{{0, 1, 2}, {2, 3, 4}, {4, 5, 6}}, chunkTokens(tokens, 3, 1))
 }
@@ -0,0 +1,30 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package openai
+
+import (
+	"github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+func init() {
+	// Use the encodings embedded within the binary rather than downloading
+	// them at runtime.
+	tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+}
+
+// tokenizerForModel returns the tokenizer of an OpenAI model, falling back to
+// the cl100k_base encoding for models that aren't recognized, such as those
+// of other OpenAI compatible services.
+func tokenizerForModel(model string) (*tiktoken.Tiktoken, error) {
+	if t, err := tiktoken.EncodingForModel(model); err == nil {
+		return t, nil
+	}
+	return tiktoken.GetEncoding(tiktoken.MODEL_CL100K_BASE)
+}
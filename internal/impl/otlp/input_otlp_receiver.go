@@ -0,0 +1,461 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	oriFieldGRPCAddress = "grpc_address"
+	oriFieldHTTPAddress = "http_address"
+	oriFieldTimeout     = "timeout"
+	oriFieldCertFile    = "cert_file"
+	oriFieldKeyFile     = "key_file"
+)
+
+func otlpReceiverInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.48.0").
+		Summary("Receive OpenTelemetry logs, traces and metrics by serving the OTLP gRPC and HTTP protocols.").
+		Description(`
+This input allows Redpanda Connect to act as a stage of a telemetry pipeline, receiving data from OpenTelemetry SDKs or collectors configured with an OTLP exporter. Both the gRPC and HTTP (protobuf and JSON encoded) variants of the protocol are supported, and either can be disabled by setting its address to an empty string.
+
+Each export request is converted into a batch of structured messages, with a message for each log record, span or metric. A request is only acknowledged once its batch has been processed by the pipeline, and requests that cannot be delivered are rejected with a retryable status so that clients can send them again.
+
+== Message structure
+
+Logs are converted into objects with the fields `+"`time_unix_nano`, `observed_time_unix_nano`, `severity_number`, `severity_text`, `body`, `attributes`, `flags`, `trace_id` and `span_id`"+`.
+
+Spans are converted into objects with the fields `+"`trace_id`, `span_id`, `parent_span_id`, `trace_state`, `name`, `kind`, `start_time_unix_nano`, `end_time_unix_nano`, `attributes`, `events`, `links` and `status`"+`.
+
+Metrics are converted into objects with the fields `+"`name`, `description`, `unit`, `type` and `data_points`"+`, where the type is one of `+"`gauge`, `sum`, `histogram`, `exponential_histogram` or `summary`"+`. Sums and histograms also include the fields `+"`aggregation_temporality` and `is_monotonic`"+` where relevant.
+
+Attributes are converted into objects, trace and span IDs into hex encoded strings, and enumerations such as the span kind into the name of their value (e.g. `+"`SPAN_KIND_SERVER`"+`).
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- otlp_signal (logs, traces or metrics)
+- otlp_scope_name
+- otlp_scope_version
+- All attributes of the resource that produced the signal
+`+"```"+`
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+`).
+		Fields(
+			service.NewStringField(oriFieldGRPCAddress).
+				Description("The address to serve the OTLP gRPC protocol on. Set to an empty string in order to disable it.").
+				Default("0.0.0.0:4317"),
+			service.NewStringField(oriFieldHTTPAddress).
+				Description("The address to serve the OTLP HTTP protocol on. Set to an empty string in order to disable it.").
+				Default("0.0.0.0:4318"),
+			service.NewDurationField(oriFieldTimeout).
+				Description("The maximum length of time to wait for a request to be processed by the pipeline.").
+				Default("5s"),
+			service.NewStringField(oriFieldCertFile).
+				Description("An optional certificate file for enabling TLS on both servers.").
+				Advanced().
+				Default(""),
+			service.NewStringField(oriFieldKeyFile).
+				Description("An optional key file for enabling TLS on both servers.").
+				Advanced().
+				Default(""),
+		).
+		LintRule(`root = if this.grpc_address.or("0.0.0.0:4317") == "" && this.http_address.or("0.0.0.0:4318") == "" { "at least one of grpc_address and http_address must be set" }`).
+		Example("Route Error Logs", "Receive logs from OpenTelemetry SDKs and write those of services in production with a severity of error or above to Kafka.", `
+input:
+  otlp_receiver: {}
+
+pipeline:
+  processors:
+    - mapping: |
+        root = if @otlp_signal != "logs" || metadata("deployment.environment") != "production" || this.severity_number < 17 {
+          deleted()
+        }
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: error_logs
+    key: ${! metadata("service.name") }
+`)
+}
+
+func init() {
+	err := service.RegisterBatchInput("otlp_receiver", otlpReceiverInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			return newOTLPReceiverInputFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+var (
+	errReceiverShutdown = errors.New("server is shutting down")
+	errReceiverTimeout  = errors.New("timed out waiting for the request to be processed")
+)
+
+type otlpReceiverRequest struct {
+	batch   service.MessageBatch
+	resChan chan error
+}
+
+type otlpReceiverInput struct {
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	grpcAddress string
+	httpAddress string
+	certFile    string
+	keyFile     string
+	timeout     time.Duration
+
+	reqChan chan otlpReceiverRequest
+
+	serverMut  sync.Mutex
+	grpcServer *grpc.Server
+	httpServer *http.Server
+
+	// The addresses being listened on, which differ from the configured
+	// addresses when a random port is chosen.
+	grpcListenAddr net.Addr
+	httpListenAddr net.Addr
+}
+
+func newOTLPReceiverInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*otlpReceiverInput, error) {
+	r := &otlpReceiverInput{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+		reqChan: make(chan otlpReceiverRequest),
+	}
+
+	var err error
+	if r.grpcAddress, err = conf.FieldString(oriFieldGRPCAddress); err != nil {
+		return nil, err
+	}
+	if r.httpAddress, err = conf.FieldString(oriFieldHTTPAddress); err != nil {
+		return nil, err
+	}
+	if r.grpcAddress == "" && r.httpAddress == "" {
+		return nil, errors.New("at least one of grpc_address and http_address must be set")
+	}
+	if r.timeout, err = conf.FieldDuration(oriFieldTimeout); err != nil {
+		return nil, err
+	}
+	if r.certFile, err = conf.FieldString(oriFieldCertFile); err != nil {
+		return nil, err
+	}
+	if r.keyFile, err = conf.FieldString(oriFieldKeyFile); err != nil {
+		return nil, err
+	}
+	if (r.certFile == "") != (r.keyFile == "") {
+		return nil, errors.New("both cert_file and key_file must be set in order to enable TLS")
+	}
+	return r, nil
+}
+
+// dispatch sends a batch down the pipeline and waits for it to be
+// acknowledged.
+func (r *otlpReceiverInput) dispatch(ctx context.Context, batch service.MessageBatch) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, done := context.WithTimeout(ctx, r.timeout)
+	defer done()
+
+	resChan := make(chan error, 1)
+	select {
+	case r.reqChan <- otlpReceiverRequest{batch: batch, resChan: resChan}:
+	case <-ctx.Done():
+		return errReceiverTimeout
+	case <-r.shutSig.SoftStopChan():
+		return errReceiverShutdown
+	}
+
+	select {
+	case err := <-resChan:
+		return err
+	case <-ctx.Done():
+		return errReceiverTimeout
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func dispatchStatus(err error) error {
+	switch {
+	case errors.Is(err, errReceiverTimeout):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+type logsServer struct {
+	collogspb.UnimplementedLogsServiceServer
+	r *otlpReceiverInput
+}
+
+func (s *logsServer) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	if err := s.r.dispatch(ctx, logsToBatch(req)); err != nil {
+		return nil, dispatchStatus(err)
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+type traceServer struct {
+	coltracepb.UnimplementedTraceServiceServer
+	r *otlpReceiverInput
+}
+
+func (s *traceServer) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	if err := s.r.dispatch(ctx, tracesToBatch(req)); err != nil {
+		return nil, dispatchStatus(err)
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+type metricsServer struct {
+	colmetricspb.UnimplementedMetricsServiceServer
+	r *otlpReceiverInput
+}
+
+func (s *metricsServer) Export(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) (*colmetricspb.ExportMetricsServiceResponse, error) {
+	if err := s.r.dispatch(ctx, metricsToBatch(req)); err != nil {
+		return nil, dispatchStatus(err)
+	}
+	return &colmetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// httpHandler serves an OTLP/HTTP endpoint, where requests are decoded into
+// the provided request type and converted into a batch.
+func httpHandler[T proto.Message](r *otlpReceiverInput, newReq func() T, toBatch func(T) service.MessageBatch, resp proto.Message) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var body io.Reader = req.Body
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(req.Body)
+			if err != nil {
+				http.Error(w, "failed to decompress request: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer gz.Close()
+			body = gz
+		}
+		b, err := io.ReadAll(body)
+		if err != nil {
+			http.Error(w, "failed to read request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		isJSON := mediaType == "application/json"
+
+		otlpReq := newReq()
+		if isJSON {
+			err = protojson.Unmarshal(b, otlpReq)
+		} else {
+			err = proto.Unmarshal(b, otlpReq)
+		}
+		if err != nil {
+			http.Error(w, "failed to parse request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := r.dispatch(req.Context(), toBatch(otlpReq)); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		var respBytes []byte
+		if isJSON {
+			respBytes, err = protojson.Marshal(resp)
+			w.Header().Set("Content-Type", "application/json")
+		} else {
+			respBytes, err = proto.Marshal(resp)
+			w.Header().Set("Content-Type", "application/x-protobuf")
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(respBytes)
+	}
+}
+
+func (r *otlpReceiverInput) httpMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/logs", httpHandler(r,
+		func() *collogspb.ExportLogsServiceRequest { return &collogspb.ExportLogsServiceRequest{} },
+		logsToBatch, &collogspb.ExportLogsServiceResponse{}))
+	mux.HandleFunc("/v1/traces", httpHandler(r,
+		func() *coltracepb.ExportTraceServiceRequest { return &coltracepb.ExportTraceServiceRequest{} },
+		tracesToBatch, &coltracepb.ExportTraceServiceResponse{}))
+	mux.HandleFunc("/v1/metrics", httpHandler(r,
+		func() *colmetricspb.ExportMetricsServiceRequest { return &colmetricspb.ExportMetricsServiceRequest{} },
+		metricsToBatch, &colmetricspb.ExportMetricsServiceResponse{}))
+	return mux
+}
+
+//------------------------------------------------------------------------------
+
+func (r *otlpReceiverInput) Connect(ctx context.Context) error {
+	r.serverMut.Lock()
+	defer r.serverMut.Unlock()
+	if r.grpcServer != nil || r.httpServer != nil {
+		return nil
+	}
+
+	var grpcLis, httpLis net.Listener
+	var err error
+	if r.grpcAddress != "" {
+		if grpcLis, err = net.Listen("tcp", r.grpcAddress); err != nil {
+			return err
+		}
+	}
+	if r.httpAddress != "" {
+		if httpLis, err = net.Listen("tcp", r.httpAddress); err != nil {
+			if grpcLis != nil {
+				_ = grpcLis.Close()
+			}
+			return err
+		}
+	}
+
+	if grpcLis != nil {
+		var opts []grpc.ServerOption
+		if r.certFile != "" {
+			creds, err := credentials.NewServerTLSFromFile(r.certFile, r.keyFile)
+			if err != nil {
+				_ = grpcLis.Close()
+				if httpLis != nil {
+					_ = httpLis.Close()
+				}
+				return fmt.Errorf("failed to load TLS credentials: %w", err)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+
+		server := grpc.NewServer(opts...)
+		collogspb.RegisterLogsServiceServer(server, &logsServer{r: r})
+		coltracepb.RegisterTraceServiceServer(server, &traceServer{r: r})
+		colmetricspb.RegisterMetricsServiceServer(server, &metricsServer{r: r})
+		go func() {
+			if err := server.Serve(grpcLis); err != nil {
+				r.log.Errorf("OTLP gRPC server stopped: %v", err)
+			}
+		}()
+		r.log.Infof("Receiving OTLP gRPC requests at: %v", grpcLis.Addr())
+		r.grpcServer = server
+		r.grpcListenAddr = grpcLis.Addr()
+	}
+
+	if httpLis != nil {
+		server := &http.Server{Handler: r.httpMux()}
+		go func() {
+			var err error
+			if r.certFile != "" {
+				err = server.ServeTLS(httpLis, r.certFile, r.keyFile)
+			} else {
+				err = server.Serve(httpLis)
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				r.log.Errorf("OTLP HTTP server stopped: %v", err)
+			}
+		}()
+		r.log.Infof("Receiving OTLP HTTP requests at: %v", httpLis.Addr())
+		r.httpServer = server
+		r.httpListenAddr = httpLis.Addr()
+	}
+	return nil
+}
+
+func (r *otlpReceiverInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	select {
+	case req := <-r.reqChan:
+		return req.batch, func(ctx context.Context, err error) error {
+			req.resChan <- err
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-r.shutSig.SoftStopChan():
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (r *otlpReceiverInput) Close(ctx context.Context) error {
+	r.shutSig.TriggerSoftStop()
+
+	r.serverMut.Lock()
+	grpcServer, httpServer := r.grpcServer, r.httpServer
+	r.grpcServer, r.httpServer = nil, nil
+	r.serverMut.Unlock()
+
+	var err error
+	if httpServer != nil {
+		err = httpServer.Shutdown(ctx)
+	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+			return ctx.Err()
+		}
+	}
+	return err
+}
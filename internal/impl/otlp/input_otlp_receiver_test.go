@@ -0,0 +1,381 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func strAttr(k, v string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: k, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}}
+}
+
+func testReceiver(t *testing.T, extra string) *otlpReceiverInput {
+	t.Helper()
+
+	conf, err := otlpReceiverInputSpec().ParseYAML(`
+grpc_address: 127.0.0.1:0
+http_address: 127.0.0.1:0
+timeout: 1s
+`+extra, nil)
+	require.NoError(t, err)
+
+	r, err := newOTLPReceiverInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, r.Connect(context.Background()))
+	t.Cleanup(func() {
+		ctx, done := context.WithTimeout(context.Background(), time.Second)
+		defer done()
+		_ = r.Close(ctx)
+	})
+	return r
+}
+
+// readAndAck reads a single batch from the receiver and acknowledges it with
+// the provided error.
+func readAndAck(t *testing.T, r *otlpReceiverInput, ackErr error) service.MessageBatch {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	defer done()
+
+	batch, ackFn, err := r.ReadBatch(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ackFn(ctx, ackErr))
+	return batch
+}
+
+func TestOTLPReceiverGRPCLogs(t *testing.T) {
+	r := testReceiver(t, "")
+
+	conn, err := grpc.NewClient(r.grpcListenAddr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	req := &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{strAttr("service.name", "checkout")}},
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope: &commonpb.InstrumentationScope{Name: "app.logger", Version: "1.2.3"},
+				LogRecords: []*logspb.LogRecord{
+					{
+						TimeUnixNano:   1700000000000000000,
+						SeverityNumber: logspb.SeverityNumber_SEVERITY_NUMBER_ERROR,
+						SeverityText:   "ERROR",
+						Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "payment failed"}},
+						Attributes: []*commonpb.KeyValue{
+							strAttr("order.id", "abc"),
+							{Key: "retries", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 3}}},
+						},
+						TraceId: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+						SpanId:  []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+					},
+					{
+						SeverityNumber: logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+						Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "ok"}},
+					},
+				},
+			}},
+		}},
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := collogspb.NewLogsServiceClient(conn).Export(context.Background(), req)
+		errChan <- err
+	}()
+
+	batch := readAndAck(t, r, nil)
+	require.NoError(t, <-errChan)
+	require.Len(t, batch, 2)
+
+	v, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"time_unix_nano":          int64(1700000000000000000),
+		"observed_time_unix_nano": int64(0),
+		"severity_number":         int64(17),
+		"severity_text":           "ERROR",
+		"body":                    "payment failed",
+		"attributes":              map[string]any{"order.id": "abc", "retries": int64(3)},
+		"flags":                   int64(0),
+		"trace_id":                "0102030405060708090a0b0c0d0e0f10",
+		"span_id":                 "0102030405060708",
+	}, v)
+
+	for _, msg := range batch {
+		signal, _ := msg.MetaGet("otlp_signal")
+		assert.Equal(t, "logs", signal)
+		svc, _ := msg.MetaGet("service.name")
+		assert.Equal(t, "checkout", svc)
+		scope, _ := msg.MetaGet("otlp_scope_name")
+		assert.Equal(t, "app.logger", scope)
+		version, _ := msg.MetaGet("otlp_scope_version")
+		assert.Equal(t, "1.2.3", version)
+	}
+}
+
+func TestOTLPReceiverGRPCNack(t *testing.T) {
+	r := testReceiver(t, "")
+
+	conn, err := grpc.NewClient(r.grpcListenAddr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := coltracepb.NewTraceServiceClient(conn).Export(context.Background(), &coltracepb.ExportTraceServiceRequest{
+			ResourceSpans: []*tracepb.ResourceSpans{{
+				ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{Name: "foo"}}}},
+			}},
+		})
+		errChan <- err
+	}()
+
+	readAndAck(t, r, errors.New("nope"))
+	err = <-errChan
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestOTLPReceiverHTTPTracesJSON(t *testing.T) {
+	r := testReceiver(t, "")
+
+	req := &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{strAttr("service.name", "api")}},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{
+					TraceId:           []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99},
+					SpanId:            []byte{0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11, 0x11},
+					ParentSpanId:      []byte{0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22, 0x22},
+					Name:              "GET /orders",
+					Kind:              tracepb.Span_SPAN_KIND_SERVER,
+					StartTimeUnixNano: 100,
+					EndTimeUnixNano:   200,
+					Events: []*tracepb.Span_Event{{
+						Name:         "cache_miss",
+						TimeUnixNano: 150,
+					}},
+					Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: "boom"},
+				}},
+			}},
+		}},
+	}
+	b, err := protojson.Marshal(req)
+	require.NoError(t, err)
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	_, err = gz.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	resChan := make(chan result, 1)
+	go func() {
+		httpReq, err := http.NewRequest(http.MethodPost, "http://"+r.httpListenAddr.String()+"/v1/traces", &gzipped)
+		if err != nil {
+			resChan <- result{err: err}
+			return
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Content-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(httpReq)
+		resChan <- result{resp: resp, err: err}
+	}()
+
+	batch := readAndAck(t, r, nil)
+	res := <-resChan
+	require.NoError(t, res.err)
+	defer res.resp.Body.Close()
+	assert.Equal(t, http.StatusOK, res.resp.StatusCode)
+	assert.Equal(t, "application/json", res.resp.Header.Get("Content-Type"))
+
+	require.Len(t, batch, 1)
+	v, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"trace_id":             "aabbccddeeff00112233445566778899",
+		"span_id":              "1111111111111111",
+		"parent_span_id":       "2222222222222222",
+		"trace_state":          "",
+		"name":                 "GET /orders",
+		"kind":                 "SPAN_KIND_SERVER",
+		"start_time_unix_nano": int64(100),
+		"end_time_unix_nano":   int64(200),
+		"attributes":           map[string]any{},
+		"events": []any{map[string]any{
+			"name":           "cache_miss",
+			"time_unix_nano": int64(150),
+			"attributes":     map[string]any{},
+		}},
+		"links":  []any{},
+		"status": map[string]any{"code": "STATUS_CODE_ERROR", "message": "boom"},
+	}, v)
+
+	signal, _ := batch[0].MetaGet("otlp_signal")
+	assert.Equal(t, "traces", signal)
+}
+
+func TestOTLPReceiverHTTPMetricsProtobuf(t *testing.T) {
+	r := testReceiver(t, "")
+
+	sum := 12.5
+	req := &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Metrics: []*metricspb.Metric{
+					{
+						Name: "requests",
+						Unit: "1",
+						Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+							AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+							IsMonotonic:            true,
+							DataPoints: []*metricspb.NumberDataPoint{{
+								Attributes:   []*commonpb.KeyValue{strAttr("route", "/orders")},
+								TimeUnixNano: 10,
+								Value:        &metricspb.NumberDataPoint_AsInt{AsInt: 42},
+							}},
+						}},
+					},
+					{
+						Name: "latency",
+						Unit: "ms",
+						Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+							AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+							DataPoints: []*metricspb.HistogramDataPoint{{
+								TimeUnixNano:   10,
+								Count:          3,
+								Sum:            &sum,
+								BucketCounts:   []uint64{1, 2},
+								ExplicitBounds: []float64{5},
+							}},
+						}},
+					},
+				},
+			}},
+		}},
+	}
+	b, err := proto.Marshal(req)
+	require.NoError(t, err)
+
+	errChan := make(chan error, 1)
+	go func() {
+		resp, err := http.Post("http://"+r.httpListenAddr.String()+"/v1/metrics", "application/x-protobuf", bytes.NewReader(b))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				err = errors.New(resp.Status)
+			}
+		}
+		errChan <- err
+	}()
+
+	batch := readAndAck(t, r, nil)
+	require.NoError(t, <-errChan)
+	require.Len(t, batch, 2)
+
+	v, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":                    "requests",
+		"description":             "",
+		"unit":                    "1",
+		"type":                    "sum",
+		"aggregation_temporality": "AGGREGATION_TEMPORALITY_CUMULATIVE",
+		"is_monotonic":            true,
+		"data_points": []any{map[string]any{
+			"attributes":           map[string]any{"route": "/orders"},
+			"start_time_unix_nano": int64(0),
+			"time_unix_nano":       int64(10),
+			"flags":                int64(0),
+			"value":                int64(42),
+		}},
+	}, v)
+
+	v, err = batch[1].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":                    "latency",
+		"description":             "",
+		"unit":                    "ms",
+		"type":                    "histogram",
+		"aggregation_temporality": "AGGREGATION_TEMPORALITY_DELTA",
+		"data_points": []any{map[string]any{
+			"attributes":           map[string]any{},
+			"start_time_unix_nano": int64(0),
+			"time_unix_nano":       int64(10),
+			"flags":                int64(0),
+			"count":                int64(3),
+			"sum":                  12.5,
+			"bucket_counts":        []any{int64(1), int64(2)},
+			"explicit_bounds":      []any{float64(5)},
+		}},
+	}, v)
+}
+
+func TestOTLPReceiverHTTPBadRequest(t *testing.T) {
+	r := testReceiver(t, "")
+
+	resp, err := http.Post("http://"+r.httpListenAddr.String()+"/v1/logs", "application/json", bytes.NewReader([]byte(`not json`)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get("http://" + r.httpListenAddr.String() + "/v1/logs")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestOTLPReceiverNoAddresses(t *testing.T) {
+	conf, err := otlpReceiverInputSpec().ParseYAML(`
+grpc_address: ""
+http_address: ""
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newOTLPReceiverInputFromConfig(conf, service.MockResources())
+	require.Error(t, err)
+}
@@ -0,0 +1,338 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	omFieldProtocol           = "protocol"
+	omFieldAddress            = "address"
+	omFieldTLS                = "tls"
+	omFieldHeaders            = "headers"
+	omFieldCompression        = "compression"
+	omFieldInterval           = "interval"
+	omFieldTimeout            = "timeout"
+	omFieldTemporality        = "temporality"
+	omFieldResourceAttributes = "resource_attributes"
+	omFieldHistogramBuckets   = "histogram_buckets"
+)
+
+func otlpMetricsSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.48.0").
+		Summary("Push metrics to an https://opentelemetry.io/docs/collector/[OpenTelemetry collector^] or any other endpoint supporting the OTLP protocol.").
+		Description(`
+Metrics are aggregated in memory and exported periodically, with counters exported as monotonic sums, timings as histograms measured in seconds and gauges as gauges. Metric labels are exported as attributes of their data points, and the attributes of `+"`resource_attributes`"+` identify the resource that produced them.
+
+The temporality of sums and histograms can be either cumulative, where each export contains the totals since the process started, or delta, where each export contains only the changes since the previous export. Backends such as Prometheus expect cumulative temporality, whereas others such as Datadog prefer delta. Gauges are always exported with cumulative temporality.
+`).
+		Fields(
+			service.NewStringEnumField(omFieldProtocol, "grpc", "http").
+				Description("The OTLP transport protocol to use.").
+				Default("grpc"),
+			service.NewStringField(omFieldAddress).
+				Description("The address of the OTLP endpoint, without a scheme.").
+				Example("localhost:4318").
+				Default("localhost:4317"),
+			service.NewTLSToggledField(omFieldTLS),
+			service.NewStringMapField(omFieldHeaders).
+				Description("A map of headers to add to each export request, which can be used for authentication.").
+				Example(map[string]any{"Authorization": "Bearer ${TOKEN}"}).
+				Default(map[string]any{}).
+				Advanced(),
+			service.NewStringEnumField(omFieldCompression, "none", "gzip").
+				Description("The compression algorithm to use for export requests.").
+				Default("gzip").
+				Advanced(),
+			service.NewDurationField(omFieldInterval).
+				Description("The period between exports.").
+				Default("10s"),
+			service.NewDurationField(omFieldTimeout).
+				Description("The maximum period to wait for an export to complete.").
+				Default("10s").
+				Advanced(),
+			service.NewStringAnnotatedEnumField(omFieldTemporality, map[string]string{
+				"cumulative": "Sums and histograms contain the totals since the process started.",
+				"delta":      "Sums and histograms contain the changes since the previous export.",
+			}).
+				Description("The aggregation temporality of exported sums and histograms.").
+				Default("cumulative"),
+			service.NewStringMapField(omFieldResourceAttributes).
+				Description("A map of attributes identifying the resource that produces the metrics. When `service.name` is not set it defaults to `benthos`, along with `service.version` set to the version of the running engine.").
+				Example(map[string]any{
+					"service.name":           "order-pipeline",
+					"deployment.environment": "production",
+				}).
+				Default(map[string]any{}),
+			service.NewFloatListField(omFieldHistogramBuckets).
+				Description("The bucket boundaries of timing histograms in seconds.").
+				Default([]any{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0}).
+				Advanced(),
+		)
+}
+
+func init() {
+	err := service.RegisterMetricsExporter(
+		"open_telemetry", otlpMetricsSpec(),
+		func(conf *service.ParsedConfig, log *service.Logger) (service.MetricsExporter, error) {
+			return newOTLPMetricsFromConfig(conf, log)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type otlpMetrics struct {
+	log      *service.Logger
+	provider *sdkmetric.MeterProvider
+	meter    metric.Meter
+	buckets  []float64
+}
+
+// deltaTemporality selects delta temporality for monotonic instruments, and
+// cumulative temporality for all others as recommended by the OTLP exporter
+// specification.
+func deltaTemporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindCounter,
+		sdkmetric.InstrumentKindHistogram,
+		sdkmetric.InstrumentKindObservableCounter:
+		return metricdata.DeltaTemporality
+	}
+	return metricdata.CumulativeTemporality
+}
+
+func newOTLPMetricsFromConfig(conf *service.ParsedConfig, log *service.Logger) (*otlpMetrics, error) {
+	protocol, err := conf.FieldString(omFieldProtocol)
+	if err != nil {
+		return nil, err
+	}
+	address, err := conf.FieldString(omFieldAddress)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(omFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := conf.FieldStringMap(omFieldHeaders)
+	if err != nil {
+		return nil, err
+	}
+	compression, err := conf.FieldString(omFieldCompression)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := conf.FieldDuration(omFieldInterval)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := conf.FieldDuration(omFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	temporality, err := conf.FieldString(omFieldTemporality)
+	if err != nil {
+		return nil, err
+	}
+	resAttrs, err := conf.FieldStringMap(omFieldResourceAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &otlpMetrics{log: log}
+	if m.buckets, err = conf.FieldFloatList(omFieldHistogramBuckets); err != nil {
+		return nil, err
+	}
+
+	temporalitySelector := sdkmetric.DefaultTemporalitySelector
+	if temporality == "delta" {
+		temporalitySelector = deltaTemporality
+	}
+
+	var exporter sdkmetric.Exporter
+	if protocol == "http" {
+		exporter, err = newOTLPMetricsHTTPExporter(address, tlsConf, tlsEnabled, headers, compression, timeout, temporalitySelector)
+	} else {
+		exporter, err = newOTLPMetricsGRPCExporter(address, tlsConf, tlsEnabled, headers, compression, timeout, temporalitySelector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(resAttrs)+2)
+	for k, v := range resAttrs {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	if _, ok := resAttrs[string(semconv.ServiceNameKey)]; !ok {
+		attrs = append(attrs, semconv.ServiceNameKey.String("benthos"))
+		if _, ok := resAttrs[string(semconv.ServiceVersionKey)]; !ok {
+			attrs = append(attrs, semconv.ServiceVersionKey.String(conf.EngineVersion()))
+		}
+	}
+
+	m.provider = sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter,
+			sdkmetric.WithInterval(interval),
+			sdkmetric.WithTimeout(timeout),
+		)),
+	)
+	m.meter = m.provider.Meter("benthos")
+	return m, nil
+}
+
+func newOTLPMetricsGRPCExporter(address string, tlsConf *tls.Config, tlsEnabled bool, headers map[string]string, compression string, timeout time.Duration, temporality sdkmetric.TemporalitySelector) (sdkmetric.Exporter, error) {
+	opts := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(address),
+		otlpmetricgrpc.WithHeaders(headers),
+		otlpmetricgrpc.WithTimeout(timeout),
+		otlpmetricgrpc.WithTemporalitySelector(temporality),
+	}
+	if tlsEnabled {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConf)))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	if compression == "gzip" {
+		opts = append(opts, otlpmetricgrpc.WithCompressor("gzip"))
+	}
+	return otlpmetricgrpc.New(context.Background(), opts...)
+}
+
+func newOTLPMetricsHTTPExporter(address string, tlsConf *tls.Config, tlsEnabled bool, headers map[string]string, compression string, timeout time.Duration, temporality sdkmetric.TemporalitySelector) (sdkmetric.Exporter, error) {
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(address),
+		otlpmetrichttp.WithHeaders(headers),
+		otlpmetrichttp.WithTimeout(timeout),
+		otlpmetrichttp.WithTemporalitySelector(temporality),
+	}
+	if tlsEnabled {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConf))
+	} else {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if compression == "gzip" {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	} else {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.NoCompression))
+	}
+	return otlpmetrichttp.New(context.Background(), opts...)
+}
+
+func labelAttributes(labelKeys, labelValues []string) metric.MeasurementOption {
+	attrs := make([]attribute.KeyValue, 0, len(labelKeys))
+	for i, k := range labelKeys {
+		if i < len(labelValues) {
+			attrs = append(attrs, attribute.String(k, labelValues[i]))
+		}
+	}
+	return metric.WithAttributeSet(attribute.NewSet(attrs...))
+}
+
+func (m *otlpMetrics) NewCounterCtor(name string, labelKeys ...string) service.MetricsExporterCounterCtor {
+	counter, err := m.meter.Float64Counter(name)
+	if err != nil {
+		m.log.Errorf("Failed to create counter %v: %v", name, err)
+		counter = noop.Float64Counter{}
+	}
+	return func(labelValues ...string) service.MetricsExporterCounter {
+		return &otlpCounter{counter: counter, attrs: labelAttributes(labelKeys, labelValues)}
+	}
+}
+
+func (m *otlpMetrics) NewTimerCtor(name string, labelKeys ...string) service.MetricsExporterTimerCtor {
+	histogram, err := m.meter.Float64Histogram(name,
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(m.buckets...),
+	)
+	if err != nil {
+		m.log.Errorf("Failed to create histogram %v: %v", name, err)
+		histogram = noop.Float64Histogram{}
+	}
+	return func(labelValues ...string) service.MetricsExporterTimer {
+		return &otlpTimer{histogram: histogram, attrs: labelAttributes(labelKeys, labelValues)}
+	}
+}
+
+func (m *otlpMetrics) NewGaugeCtor(name string, labelKeys ...string) service.MetricsExporterGaugeCtor {
+	gauge, err := m.meter.Float64Gauge(name)
+	if err != nil {
+		m.log.Errorf("Failed to create gauge %v: %v", name, err)
+		gauge = noop.Float64Gauge{}
+	}
+	return func(labelValues ...string) service.MetricsExporterGauge {
+		return &otlpGauge{gauge: gauge, attrs: labelAttributes(labelKeys, labelValues)}
+	}
+}
+
+func (m *otlpMetrics) Close(ctx context.Context) error {
+	return m.provider.Shutdown(ctx)
+}
+
+//------------------------------------------------------------------------------
+
+type otlpCounter struct {
+	counter metric.Float64Counter
+	attrs   metric.MeasurementOption
+}
+
+func (c *otlpCounter) Incr(count int64) {
+	c.counter.Add(context.Background(), float64(count), c.attrs)
+}
+
+func (c *otlpCounter) IncrFloat64(count float64) {
+	c.counter.Add(context.Background(), count, c.attrs)
+}
+
+type otlpTimer struct {
+	histogram metric.Float64Histogram
+	attrs     metric.MeasurementOption
+}
+
+func (t *otlpTimer) Timing(delta int64) {
+	t.histogram.Record(context.Background(), time.Duration(delta).Seconds(), t.attrs)
+}
+
+type otlpGauge struct {
+	gauge metric.Float64Gauge
+	attrs metric.MeasurementOption
+}
+
+func (g *otlpGauge) Set(value int64) {
+	g.gauge.Record(context.Background(), float64(value), g.attrs)
+}
+
+func (g *otlpGauge) SetFloat64(value float64) {
+	g.gauge.Record(context.Background(), value, g.attrs)
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestOTLPMetricsExport(t *testing.T) {
+	for _, test := range []struct {
+		protocol    string
+		temporality string
+	}{
+		{protocol: "grpc", temporality: "cumulative"},
+		{protocol: "http", temporality: "delta"},
+	} {
+		t.Run(test.protocol, func(t *testing.T) {
+			r := testReceiver(t, "")
+
+			addr := r.grpcListenAddr.String()
+			if test.protocol == "http" {
+				addr = r.httpListenAddr.String()
+			}
+			conf, err := otlpMetricsSpec().ParseYAML(fmt.Sprintf(`
+protocol: %v
+address: %v
+interval: 1h
+temporality: %v
+resource_attributes:
+  deployment.environment: test
+`, test.protocol, addr, test.temporality), nil)
+			require.NoError(t, err)
+
+			m, err := newOTLPMetricsFromConfig(conf, service.MockResources().Logger())
+			require.NoError(t, err)
+
+			counter := m.NewCounterCtor("input_received", "label")
+			counter("a").Incr(3)
+			counter("a").Incr(2)
+			counter("b").Incr(1)
+			m.NewTimerCtor("output_latency_ns")().Timing(int64(20 * time.Millisecond))
+			m.NewGaugeCtor("buffer_size")().Set(7)
+
+			// Shutting down the provider flushes all metrics.
+			closeErr := make(chan error, 1)
+			go func() {
+				closeErr <- m.Close(context.Background())
+			}()
+
+			batch := readAndAck(t, r, nil)
+			require.NoError(t, <-closeErr)
+
+			metrics := map[string]map[string]any{}
+			for _, msg := range batch {
+				v, err := msg.AsStructured()
+				require.NoError(t, err)
+				obj := v.(map[string]any)
+				metrics[obj["name"].(string)] = obj
+
+				env, _ := msg.MetaGet("deployment.environment")
+				assert.Equal(t, "test", env)
+				svc, _ := msg.MetaGet("service.name")
+				assert.Equal(t, "benthos", svc)
+			}
+			require.Len(t, metrics, 3)
+
+			expTemporality := "AGGREGATION_TEMPORALITY_CUMULATIVE"
+			if test.temporality == "delta" {
+				expTemporality = "AGGREGATION_TEMPORALITY_DELTA"
+			}
+
+			sum := metrics["input_received"]
+			assert.Equal(t, "sum", sum["type"])
+			assert.Equal(t, true, sum["is_monotonic"])
+			assert.Equal(t, expTemporality, sum["aggregation_temporality"])
+			values := map[string]any{}
+			for _, dp := range sum["data_points"].([]any) {
+				dpObj := dp.(map[string]any)
+				values[dpObj["attributes"].(map[string]any)["label"].(string)] = dpObj["value"]
+			}
+			assert.Equal(t, map[string]any{"a": 5.0, "b": 1.0}, values)
+
+			hist := metrics["output_latency_ns"]
+			assert.Equal(t, "histogram", hist["type"])
+			assert.Equal(t, "s", hist["unit"])
+			assert.Equal(t, expTemporality, hist["aggregation_temporality"])
+			dp := hist["data_points"].([]any)[0].(map[string]any)
+			assert.Equal(t, int64(1), dp["count"])
+			assert.InDelta(t, 0.02, dp["sum"], 0.0001)
+
+			gauge := metrics["buffer_size"]
+			assert.Equal(t, "gauge", gauge["type"])
+			assert.Equal(t, 7.0, gauge["data_points"].([]any)[0].(map[string]any)["value"])
+		})
+	}
+}
@@ -0,0 +1,542 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ooeFieldProtocol           = "protocol"
+	ooeFieldAddress            = "address"
+	ooeFieldSignal             = "signal"
+	ooeFieldResourceAttributes = "resource_attributes"
+	ooeFieldHeaders            = "headers"
+	ooeFieldCompression        = "compression"
+	ooeFieldTimeout            = "timeout"
+	ooeFieldTLS                = "tls"
+	ooeFieldBatching           = "batching"
+)
+
+func otlpExporterOutputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.48.0").
+		Summary("Send structured messages as OpenTelemetry logs, traces or metrics to an OTLP endpoint such as a collector.").
+		Description(`
+Messages are expected to be in the format produced by the `+"`otlp_receiver`"+` input, and therefore telemetry received by that input can be processed and forwarded to another collector without any conversion. The signal of each message is determined by the `+"`signal`"+` field, which by default reads the `+"`otlp_signal`"+` metadata field added by the receiver.
+
+Log messages that are not objects are sent as log records with the raw contents of the message as their body, which allows arbitrary logs to be shipped to a collector.
+
+== Resources and scopes
+
+Messages of a batch are grouped into resources and instrumentation scopes before being sent, with one request per signal. The attributes of the resource of a message are taken from the metadata fields selected by `+"`resource_attributes`"+`, and the name and version of its scope from the metadata fields `+"`otlp_scope_name` and `otlp_scope_version`"+`.
+`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewStringEnumField(ooeFieldProtocol, "grpc", "http").
+				Description("The OTLP transport protocol to use.").
+				Default("grpc"),
+			service.NewStringField(ooeFieldAddress).
+				Description("The address of the OTLP endpoint. When using the `http` protocol the signal specific paths `/v1/logs`, `/v1/traces` and `/v1/metrics` are appended to the address.").
+				Example("localhost:4317").
+				Example("otel-collector.example.com:4318"),
+			service.NewInterpolatedStringField(ooeFieldSignal).
+				Description("The signal of each message, which must resolve to one of `logs`, `traces` or `metrics`.").
+				Example("logs").
+				Default("${! @otlp_signal }"),
+			service.NewMetadataFilterField(ooeFieldResourceAttributes).
+				Description("Determine which (if any) metadata values should be added as attributes of the resource of each message. Metadata fields prefixed with `otlp_` are never added.").
+				Example(map[string]any{"include_patterns": []any{".*"}}).
+				Optional(),
+			service.NewStringMapField(ooeFieldHeaders).
+				Description("A map of headers to add to each request, which can be used for authentication.").
+				Example(map[string]any{"Authorization": "Bearer ${TOKEN}"}).
+				Default(map[string]any{}),
+			service.NewStringEnumField(ooeFieldCompression, "none", "gzip").
+				Description("The compression algorithm to use for requests.").
+				Default("gzip").
+				Advanced(),
+			service.NewDurationField(ooeFieldTimeout).
+				Description("The maximum period to wait for a request to complete.").
+				Default("10s").
+				Advanced(),
+			service.NewTLSToggledField(ooeFieldTLS),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(ooeFieldBatching),
+		).
+		Example("Telemetry Relay", "Receive telemetry from applications, drop the health check spans and attach the environment to all resources before forwarding it to a collector.", `
+input:
+  otlp_receiver: {}
+
+pipeline:
+  processors:
+    - mapping: |
+        root = if @otlp_signal == "traces" && this.name == "GET /healthz" { deleted() }
+        meta "deployment.environment" = "production"
+
+output:
+  otlp_exporter:
+    address: otel-collector:4317
+    resource_attributes:
+      include_patterns: [ ".*" ]
+    batching:
+      count: 500
+      period: 1s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput("otlp_exporter", otlpExporterOutputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPolicy service.BatchPolicy, maxInFlight int, err error) {
+			if maxInFlight, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if batchPolicy, err = conf.FieldBatchPolicy(ooeFieldBatching); err != nil {
+				return
+			}
+			out, err = newOTLPExporterOutputFromConfig(conf, mgr)
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type otlpExporterOutput struct {
+	log *service.Logger
+
+	protocol   string
+	address    string
+	signal     *service.InterpolatedString
+	resFilter  *service.MetadataFilter
+	headers    map[string]string
+	gzip       bool
+	timeout    time.Duration
+	tlsConf    *tls.Config
+	tlsEnabled bool
+	callOpts   []grpc.CallOption
+
+	connMut    sync.RWMutex
+	conn       *grpc.ClientConn
+	httpClient *http.Client
+}
+
+func newOTLPExporterOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*otlpExporterOutput, error) {
+	o := &otlpExporterOutput{log: mgr.Logger()}
+
+	var err error
+	if o.protocol, err = conf.FieldString(ooeFieldProtocol); err != nil {
+		return nil, err
+	}
+	if o.address, err = conf.FieldString(ooeFieldAddress); err != nil {
+		return nil, err
+	}
+	if o.signal, err = conf.FieldInterpolatedString(ooeFieldSignal); err != nil {
+		return nil, err
+	}
+	if conf.Contains(ooeFieldResourceAttributes) {
+		if o.resFilter, err = conf.FieldMetadataFilter(ooeFieldResourceAttributes); err != nil {
+			return nil, err
+		}
+	}
+	if o.headers, err = conf.FieldStringMap(ooeFieldHeaders); err != nil {
+		return nil, err
+	}
+	compression, err := conf.FieldString(ooeFieldCompression)
+	if err != nil {
+		return nil, err
+	}
+	o.gzip = compression == "gzip"
+	if o.gzip {
+		o.callOpts = append(o.callOpts, grpc.UseCompressor(grpcgzip.Name))
+	}
+	if o.timeout, err = conf.FieldDuration(ooeFieldTimeout); err != nil {
+		return nil, err
+	}
+	if o.tlsConf, o.tlsEnabled, err = conf.FieldTLSToggled(ooeFieldTLS); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *otlpExporterOutput) Connect(ctx context.Context) error {
+	o.connMut.Lock()
+	defer o.connMut.Unlock()
+
+	if o.protocol == "http" {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if o.tlsEnabled {
+			transport.TLSClientConfig = o.tlsConf
+		}
+		o.httpClient = &http.Client{Transport: transport}
+		return nil
+	}
+
+	creds := insecure.NewCredentials()
+	if o.tlsEnabled {
+		creds = credentials.NewTLS(o.tlsConf)
+	}
+	conn, err := grpc.NewClient(o.address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	o.conn = conn
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+// signalGroups groups the items of a signal by their resource and scope,
+// retaining the order in which they were added.
+type signalGroups[T any] struct {
+	resources []*resourceGroup[T]
+	index     map[string]*resourceGroup[T]
+}
+
+type resourceGroup[T any] struct {
+	resource *resourcepb.Resource
+	scopes   []*scopeGroup[T]
+	index    map[string]*scopeGroup[T]
+}
+
+type scopeGroup[T any] struct {
+	scope *commonpb.InstrumentationScope
+	items []T
+}
+
+func (g *signalGroups[T]) add(resource *resourcepb.Resource, scope *commonpb.InstrumentationScope, item T) error {
+	resKey, err := proto.MarshalOptions{Deterministic: true}.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	if g.index == nil {
+		g.index = map[string]*resourceGroup[T]{}
+	}
+	rg, exists := g.index[string(resKey)]
+	if !exists {
+		rg = &resourceGroup[T]{resource: resource, index: map[string]*scopeGroup[T]{}}
+		g.index[string(resKey)] = rg
+		g.resources = append(g.resources, rg)
+	}
+
+	scopeKey := scope.GetName() + "\x00" + scope.GetVersion()
+	sg, exists := rg.index[scopeKey]
+	if !exists {
+		sg = &scopeGroup[T]{scope: scope}
+		rg.index[scopeKey] = sg
+		rg.scopes = append(rg.scopes, sg)
+	}
+	sg.items = append(sg.items, item)
+	return nil
+}
+
+func (o *otlpExporterOutput) messageResource(msg *service.Message) (*resourcepb.Resource, error) {
+	attrs := map[string]any{}
+	if err := o.resFilter.WalkMut(msg, func(key string, value any) error {
+		if !strings.HasPrefix(key, "otlp_") {
+			attrs[key] = value
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &resourcepb.Resource{Attributes: mapToAttributes(attrs)}, nil
+}
+
+func messageScope(msg *service.Message) *commonpb.InstrumentationScope {
+	name, _ := msg.MetaGet(metaScopeName)
+	version, _ := msg.MetaGet(metaScopeVersion)
+	return &commonpb.InstrumentationScope{Name: name, Version: version}
+}
+
+func (o *otlpExporterOutput) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	var logs signalGroups[*logspb.LogRecord]
+	var traces signalGroups[*tracepb.Span]
+	var metrics signalGroups[*metricspb.Metric]
+
+	for i, msg := range batch {
+		signal, err := batch.TryInterpolatedString(i, o.signal)
+		if err != nil {
+			return fmt.Errorf("signal interpolation error: %w", err)
+		}
+		resource, err := o.messageResource(msg)
+		if err != nil {
+			return err
+		}
+		scope := messageScope(msg)
+
+		switch signal {
+		case signalLogs:
+			var lr *logspb.LogRecord
+			if v, err := msg.AsStructured(); err == nil {
+				if _, isObj := v.(map[string]any); isObj {
+					if lr, err = recordToLogRecord(v); err != nil {
+						return fmt.Errorf("message %v: %w", i, err)
+					}
+				}
+			}
+			if lr == nil {
+				b, err := msg.AsBytes()
+				if err != nil {
+					return err
+				}
+				lr = &logspb.LogRecord{
+					ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+					Body:                 goToAnyValue(string(b)),
+				}
+			}
+			err = logs.add(resource, scope, lr)
+		case signalTraces:
+			var v any
+			if v, err = msg.AsStructured(); err != nil {
+				return fmt.Errorf("message %v: %w", i, err)
+			}
+			var span *tracepb.Span
+			if span, err = recordToSpan(v); err != nil {
+				return fmt.Errorf("message %v: %w", i, err)
+			}
+			err = traces.add(resource, scope, span)
+		case signalMetrics:
+			var v any
+			if v, err = msg.AsStructured(); err != nil {
+				return fmt.Errorf("message %v: %w", i, err)
+			}
+			var m *metricspb.Metric
+			if m, err = recordToMetric(v); err != nil {
+				return fmt.Errorf("message %v: %w", i, err)
+			}
+			err = metrics.add(resource, scope, m)
+		default:
+			return fmt.Errorf("message %v: unrecognised signal: %v", i, signal)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(logs.resources) > 0 {
+		if err := o.exportLogs(ctx, &logs); err != nil {
+			return err
+		}
+	}
+	if len(traces.resources) > 0 {
+		if err := o.exportTraces(ctx, &traces); err != nil {
+			return err
+		}
+	}
+	if len(metrics.resources) > 0 {
+		if err := o.exportMetrics(ctx, &metrics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+func (o *otlpExporterOutput) exportLogs(ctx context.Context, g *signalGroups[*logspb.LogRecord]) error {
+	req := &collogspb.ExportLogsServiceRequest{}
+	for _, rg := range g.resources {
+		rl := &logspb.ResourceLogs{Resource: rg.resource}
+		for _, sg := range rg.scopes {
+			rl.ScopeLogs = append(rl.ScopeLogs, &logspb.ScopeLogs{Scope: sg.scope, LogRecords: sg.items})
+		}
+		req.ResourceLogs = append(req.ResourceLogs, rl)
+	}
+
+	resp := &collogspb.ExportLogsServiceResponse{}
+	err := o.export(ctx, "/v1/logs", req, resp, func(ctx context.Context, conn *grpc.ClientConn) (err error) {
+		resp, err = collogspb.NewLogsServiceClient(conn).Export(ctx, req, o.callOpts...)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	o.logPartialSuccess("log records", resp.GetPartialSuccess().GetRejectedLogRecords(), resp.GetPartialSuccess().GetErrorMessage())
+	return nil
+}
+
+func (o *otlpExporterOutput) exportTraces(ctx context.Context, g *signalGroups[*tracepb.Span]) error {
+	req := &coltracepb.ExportTraceServiceRequest{}
+	for _, rg := range g.resources {
+		rs := &tracepb.ResourceSpans{Resource: rg.resource}
+		for _, sg := range rg.scopes {
+			rs.ScopeSpans = append(rs.ScopeSpans, &tracepb.ScopeSpans{Scope: sg.scope, Spans: sg.items})
+		}
+		req.ResourceSpans = append(req.ResourceSpans, rs)
+	}
+
+	resp := &coltracepb.ExportTraceServiceResponse{}
+	err := o.export(ctx, "/v1/traces", req, resp, func(ctx context.Context, conn *grpc.ClientConn) (err error) {
+		resp, err = coltracepb.NewTraceServiceClient(conn).Export(ctx, req, o.callOpts...)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	o.logPartialSuccess("spans", resp.GetPartialSuccess().GetRejectedSpans(), resp.GetPartialSuccess().GetErrorMessage())
+	return nil
+}
+
+func (o *otlpExporterOutput) exportMetrics(ctx context.Context, g *signalGroups[*metricspb.Metric]) error {
+	req := &colmetricspb.ExportMetricsServiceRequest{}
+	for _, rg := range g.resources {
+		rm := &metricspb.ResourceMetrics{Resource: rg.resource}
+		for _, sg := range rg.scopes {
+			rm.ScopeMetrics = append(rm.ScopeMetrics, &metricspb.ScopeMetrics{Scope: sg.scope, Metrics: sg.items})
+		}
+		req.ResourceMetrics = append(req.ResourceMetrics, rm)
+	}
+
+	resp := &colmetricspb.ExportMetricsServiceResponse{}
+	err := o.export(ctx, "/v1/metrics", req, resp, func(ctx context.Context, conn *grpc.ClientConn) (err error) {
+		resp, err = colmetricspb.NewMetricsServiceClient(conn).Export(ctx, req, o.callOpts...)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	o.logPartialSuccess("data points", resp.GetPartialSuccess().GetRejectedDataPoints(), resp.GetPartialSuccess().GetErrorMessage())
+	return nil
+}
+
+func (o *otlpExporterOutput) logPartialSuccess(kind string, rejected int64, msg string) {
+	if rejected > 0 || msg != "" {
+		o.log.Warnf("OTLP endpoint rejected %v %v: %v", rejected, kind, msg)
+	}
+}
+
+// export sends a request either with the provided gRPC call or as an HTTP
+// request to the provided path, decoding the response into resp.
+func (o *otlpExporterOutput) export(ctx context.Context, path string, req, resp proto.Message, grpcCall func(context.Context, *grpc.ClientConn) error) error {
+	o.connMut.RLock()
+	conn, client := o.conn, o.httpClient
+	o.connMut.RUnlock()
+	if conn == nil && client == nil {
+		return service.ErrNotConnected
+	}
+
+	ctx, done := context.WithTimeout(ctx, o.timeout)
+	defer done()
+
+	if conn != nil {
+		if len(o.headers) > 0 {
+			ctx = metadata.NewOutgoingContext(ctx, metadata.New(o.headers))
+		}
+		return grpcCall(ctx, conn)
+	}
+	return o.postHTTP(ctx, client, path, req, resp)
+}
+
+func (o *otlpExporterOutput) postHTTP(ctx context.Context, client *http.Client, path string, req, resp proto.Message) error {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if o.gzip {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(b); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	}
+
+	scheme := "http"
+	if o.tlsEnabled {
+		scheme = "https"
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, scheme+"://"+o.address+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	for k, v := range o.headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	if o.gzip {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	respBytes, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		if mediaType, _, _ := mime.ParseMediaType(httpResp.Header.Get("Content-Type")); mediaType == "text/plain" {
+			return fmt.Errorf("unexpected response status %v: %s", httpResp.Status, bytes.TrimSpace(respBytes))
+		}
+		return fmt.Errorf("unexpected response status: %v", httpResp.Status)
+	}
+	if len(respBytes) > 0 {
+		if err := proto.Unmarshal(respBytes, resp); err != nil {
+			o.log.Debugf("Failed to parse OTLP response: %v", err)
+		}
+	}
+	return nil
+}
+
+func (o *otlpExporterOutput) Close(ctx context.Context) error {
+	o.connMut.Lock()
+	defer o.connMut.Unlock()
+
+	var err error
+	if o.conn != nil {
+		err = o.conn.Close()
+		o.conn = nil
+	}
+	if o.httpClient != nil {
+		o.httpClient.CloseIdleConnections()
+		o.httpClient = nil
+	}
+	return err
+}
@@ -0,0 +1,292 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testExporter(t *testing.T, conf string) *otlpExporterOutput {
+	t.Helper()
+
+	pConf, err := otlpExporterOutputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	o, err := newOTLPExporterOutputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, o.Connect(context.Background()))
+	t.Cleanup(func() {
+		_ = o.Close(context.Background())
+	})
+	return o
+}
+
+func structuredMessage(v map[string]any, meta map[string]any) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(v)
+	for k, v := range meta {
+		msg.MetaSetMut(k, v)
+	}
+	return msg
+}
+
+// roundTrip writes a batch with the exporter and returns the batch received by
+// the receiver.
+func roundTrip(t *testing.T, r *otlpReceiverInput, o *otlpExporterOutput, batch service.MessageBatch) service.MessageBatch {
+	t.Helper()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- o.WriteBatch(context.Background(), batch)
+	}()
+
+	received := readAndAck(t, r, nil)
+	require.NoError(t, <-errChan)
+	return received
+}
+
+func TestOTLPExporterRoundTrip(t *testing.T) {
+	span := map[string]any{
+		"trace_id":             "aabbccddeeff00112233445566778899",
+		"span_id":              "1111111111111111",
+		"parent_span_id":       "2222222222222222",
+		"trace_state":          "",
+		"name":                 "GET /orders",
+		"kind":                 "SPAN_KIND_SERVER",
+		"start_time_unix_nano": int64(100),
+		"end_time_unix_nano":   int64(200),
+		"attributes":           map[string]any{"http.status_code": int64(500)},
+		"events": []any{map[string]any{
+			"name":           "cache_miss",
+			"time_unix_nano": int64(150),
+			"attributes":     map[string]any{},
+		}},
+		"links": []any{map[string]any{
+			"trace_id":    "00112233445566778899aabbccddeeff",
+			"span_id":     "3333333333333333",
+			"trace_state": "",
+			"attributes":  map[string]any{},
+		}},
+		"status": map[string]any{"code": "STATUS_CODE_ERROR", "message": "boom"},
+	}
+	metric := map[string]any{
+		"name":        "latency",
+		"description": "Request latency",
+		"unit":        "ms",
+		"type":        "summary",
+		"data_points": []any{map[string]any{
+			"attributes":           map[string]any{"route": "/orders"},
+			"start_time_unix_nano": int64(0),
+			"time_unix_nano":       int64(10),
+			"flags":                int64(0),
+			"count":                int64(3),
+			"sum":                  12.5,
+			"quantile_values": []any{
+				map[string]any{"quantile": 0.5, "value": 4.0},
+				map[string]any{"quantile": 0.99, "value": 6.5},
+			},
+		}},
+	}
+
+	for _, protocol := range []string{"grpc", "http"} {
+		t.Run(protocol, func(t *testing.T) {
+			r := testReceiver(t, "")
+
+			addr := r.grpcListenAddr.String()
+			if protocol == "http" {
+				addr = r.httpListenAddr.String()
+			}
+			o := testExporter(t, fmt.Sprintf(`
+protocol: %v
+address: %v
+resource_attributes:
+  include_prefixes: [ "service." ]
+`, protocol, addr))
+
+			received := roundTrip(t, r, o, service.MessageBatch{
+				structuredMessage(span, map[string]any{
+					"otlp_signal":        "traces",
+					"otlp_scope_name":    "http.server",
+					"otlp_scope_version": "0.1.0",
+					"service.name":       "api",
+					"kafka_key":          "ignored",
+				}),
+			})
+			require.Len(t, received, 1)
+
+			v, err := received[0].AsStructured()
+			require.NoError(t, err)
+			assert.Equal(t, span, v)
+
+			_, exists := received[0].MetaGet("kafka_key")
+			assert.False(t, exists)
+			svc, _ := received[0].MetaGet("service.name")
+			assert.Equal(t, "api", svc)
+			scope, _ := received[0].MetaGet("otlp_scope_name")
+			assert.Equal(t, "http.server", scope)
+			version, _ := received[0].MetaGet("otlp_scope_version")
+			assert.Equal(t, "0.1.0", version)
+
+			received = roundTrip(t, r, o, service.MessageBatch{
+				structuredMessage(metric, map[string]any{"otlp_signal": "metrics"}),
+			})
+			require.Len(t, received, 1)
+
+			v, err = received[0].AsStructured()
+			require.NoError(t, err)
+			assert.Equal(t, metric, v)
+		})
+	}
+}
+
+func TestOTLPExporterLogsGrouping(t *testing.T) {
+	r := testReceiver(t, "")
+	o := testExporter(t, fmt.Sprintf(`
+address: %v
+signal: logs
+resource_attributes:
+  include_patterns: [ ".*" ]
+`, r.grpcListenAddr))
+
+	batch := service.MessageBatch{
+		structuredMessage(map[string]any{
+			"time_unix_nano":  int64(1),
+			"severity_number": "SEVERITY_NUMBER_WARN",
+			"body":            map[string]any{"msg": "first"},
+			"trace_id":        "0102030405060708090a0b0c0d0e0f10",
+		}, map[string]any{"service.name": "a"}),
+		structuredMessage(map[string]any{
+			"time_unix_nano":  int64(2),
+			"severity_number": int64(9),
+			"body":            "second",
+		}, map[string]any{"service.name": "b"}),
+		service.NewMessage([]byte("third")),
+	}
+	batch[2].MetaSetMut("service.name", "a")
+
+	received := roundTrip(t, r, o, batch)
+	require.Len(t, received, 3)
+
+	var bodies, services []any
+	for _, msg := range received {
+		v, err := msg.AsStructured()
+		require.NoError(t, err)
+		bodies = append(bodies, v.(map[string]any)["body"])
+		svc, _ := msg.MetaGetMut("service.name")
+		services = append(services, svc)
+	}
+
+	// Messages are grouped by their resource, retaining the order in which
+	// each resource was first seen.
+	assert.Equal(t, []any{map[string]any{"msg": "first"}, "third", "second"}, bodies)
+	assert.Equal(t, []any{"a", "a", "b"}, services)
+
+	v, err := received[0].AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, int64(13), v.(map[string]any)["severity_number"])
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", v.(map[string]any)["trace_id"])
+}
+
+func TestOTLPExporterConversionErrors(t *testing.T) {
+	o := testExporter(t, `
+protocol: http
+address: localhost:4318
+`)
+
+	tests := []struct {
+		name   string
+		msg    *service.Message
+		errStr string
+	}{
+		{
+			name:   "unknown signal",
+			msg:    structuredMessage(map[string]any{}, map[string]any{"otlp_signal": "profiles"}),
+			errStr: "unrecognised signal: profiles",
+		},
+		{
+			name:   "bad trace id",
+			msg:    structuredMessage(map[string]any{"trace_id": "abc"}, map[string]any{"otlp_signal": "traces"}),
+			errStr: "field trace_id",
+		},
+		{
+			name:   "bad span kind",
+			msg:    structuredMessage(map[string]any{"kind": "SPAN_KIND_NOPE"}, map[string]any{"otlp_signal": "traces"}),
+			errStr: "field kind: unrecognised value: SPAN_KIND_NOPE",
+		},
+		{
+			name:   "missing metric type",
+			msg:    structuredMessage(map[string]any{"name": "foo"}, map[string]any{"otlp_signal": "metrics"}),
+			errStr: "a metric type is required",
+		},
+		{
+			name: "bad data point",
+			msg: structuredMessage(map[string]any{
+				"name":        "foo",
+				"type":        "gauge",
+				"data_points": []any{map[string]any{"value": "nope"}},
+			}, map[string]any{"otlp_signal": "metrics"}),
+			errStr: "field value",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := o.WriteBatch(context.Background(), service.MessageBatch{test.msg})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errStr)
+		})
+	}
+}
+
+func TestOTLPExporterHTTPHeadersAndErrors(t *testing.T) {
+	var reqs []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs = append(reqs, r)
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+
+	o := testExporter(t, fmt.Sprintf(`
+protocol: http
+address: %v
+signal: logs
+compression: none
+headers:
+  Authorization: Bearer foo
+`, srv.Listener.Addr()))
+
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	defer done()
+
+	err := o.WriteBatch(ctx, service.MessageBatch{service.NewMessage([]byte("hello"))})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quota exceeded")
+
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "/v1/logs", reqs[0].URL.Path)
+	assert.Equal(t, "Bearer foo", reqs[0].Header.Get("Authorization"))
+	assert.Equal(t, "application/x-protobuf", reqs[0].Header.Get("Content-Type"))
+	assert.Empty(t, reqs[0].Header.Get("Content-Encoding"))
+}
@@ -0,0 +1,836 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// The metadata keys added to messages converted from OTLP signals in addition
+// to the attributes of the resource that produced them.
+const (
+	metaSignal       = "otlp_signal"
+	metaScopeName    = "otlp_scope_name"
+	metaScopeVersion = "otlp_scope_version"
+)
+
+const (
+	signalLogs    = "logs"
+	signalTraces  = "traces"
+	signalMetrics = "metrics"
+)
+
+func anyValueToGo(v *commonpb.AnyValue) any {
+	switch t := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return t.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return t.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return t.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return t.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return t.BytesValue
+	case *commonpb.AnyValue_ArrayValue:
+		arr := make([]any, len(t.ArrayValue.GetValues()))
+		for i, e := range t.ArrayValue.GetValues() {
+			arr[i] = anyValueToGo(e)
+		}
+		return arr
+	case *commonpb.AnyValue_KvlistValue:
+		return attributesToMap(t.KvlistValue.GetValues())
+	}
+	return nil
+}
+
+func attributesToMap(kvs []*commonpb.KeyValue) map[string]any {
+	m := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		m[kv.GetKey()] = anyValueToGo(kv.GetValue())
+	}
+	return m
+}
+
+func hexID(id []byte) string {
+	if len(id) == 0 {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
+
+// newSignalMessage creates a message from a structured record, adding the
+// attributes of its resource and details of its scope as metadata.
+func newSignalMessage(signal string, res *resourcepb.Resource, scope *commonpb.InstrumentationScope, record map[string]any) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(record)
+	for _, kv := range res.GetAttributes() {
+		msg.MetaSetMut(kv.GetKey(), anyValueToGo(kv.GetValue()))
+	}
+	msg.MetaSetMut(metaSignal, signal)
+	if name := scope.GetName(); name != "" {
+		msg.MetaSetMut(metaScopeName, name)
+	}
+	if version := scope.GetVersion(); version != "" {
+		msg.MetaSetMut(metaScopeVersion, version)
+	}
+	return msg
+}
+
+//------------------------------------------------------------------------------
+
+func logsToBatch(req *collogspb.ExportLogsServiceRequest) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, lr := range sl.GetLogRecords() {
+				record := map[string]any{
+					"time_unix_nano":          int64(lr.GetTimeUnixNano()),
+					"observed_time_unix_nano": int64(lr.GetObservedTimeUnixNano()),
+					"severity_number":         int64(lr.GetSeverityNumber()),
+					"severity_text":           lr.GetSeverityText(),
+					"body":                    anyValueToGo(lr.GetBody()),
+					"attributes":              attributesToMap(lr.GetAttributes()),
+					"flags":                   int64(lr.GetFlags()),
+				}
+				if id := hexID(lr.GetTraceId()); id != "" {
+					record["trace_id"] = id
+				}
+				if id := hexID(lr.GetSpanId()); id != "" {
+					record["span_id"] = id
+				}
+				batch = append(batch, newSignalMessage(signalLogs, rl.GetResource(), sl.GetScope(), record))
+			}
+		}
+	}
+	return batch
+}
+
+//------------------------------------------------------------------------------
+
+func tracesToBatch(req *coltracepb.ExportTraceServiceRequest) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				events := make([]any, len(span.GetEvents()))
+				for i, e := range span.GetEvents() {
+					events[i] = map[string]any{
+						"name":           e.GetName(),
+						"time_unix_nano": int64(e.GetTimeUnixNano()),
+						"attributes":     attributesToMap(e.GetAttributes()),
+					}
+				}
+				links := make([]any, len(span.GetLinks()))
+				for i, l := range span.GetLinks() {
+					links[i] = map[string]any{
+						"trace_id":    hexID(l.GetTraceId()),
+						"span_id":     hexID(l.GetSpanId()),
+						"trace_state": l.GetTraceState(),
+						"attributes":  attributesToMap(l.GetAttributes()),
+					}
+				}
+				record := map[string]any{
+					"trace_id":             hexID(span.GetTraceId()),
+					"span_id":              hexID(span.GetSpanId()),
+					"trace_state":          span.GetTraceState(),
+					"name":                 span.GetName(),
+					"kind":                 span.GetKind().String(),
+					"start_time_unix_nano": int64(span.GetStartTimeUnixNano()),
+					"end_time_unix_nano":   int64(span.GetEndTimeUnixNano()),
+					"attributes":           attributesToMap(span.GetAttributes()),
+					"events":               events,
+					"links":                links,
+					"status": map[string]any{
+						"code":    span.GetStatus().GetCode().String(),
+						"message": span.GetStatus().GetMessage(),
+					},
+				}
+				if id := hexID(span.GetParentSpanId()); id != "" {
+					record["parent_span_id"] = id
+				}
+				batch = append(batch, newSignalMessage(signalTraces, rs.GetResource(), ss.GetScope(), record))
+			}
+		}
+	}
+	return batch
+}
+
+//------------------------------------------------------------------------------
+
+func uint64sToAny(s []uint64) []any {
+	out := make([]any, len(s))
+	for i, v := range s {
+		out[i] = int64(v)
+	}
+	return out
+}
+
+func float64sToAny(s []float64) []any {
+	out := make([]any, len(s))
+	for i, v := range s {
+		out[i] = v
+	}
+	return out
+}
+
+func setOptionalFloat(m map[string]any, key string, v *float64) {
+	if v != nil {
+		m[key] = *v
+	}
+}
+
+func numberDataPoints(dps []*metricspb.NumberDataPoint) []any {
+	out := make([]any, len(dps))
+	for i, dp := range dps {
+		p := map[string]any{
+			"attributes":           attributesToMap(dp.GetAttributes()),
+			"start_time_unix_nano": int64(dp.GetStartTimeUnixNano()),
+			"time_unix_nano":       int64(dp.GetTimeUnixNano()),
+			"flags":                int64(dp.GetFlags()),
+		}
+		switch v := dp.GetValue().(type) {
+		case *metricspb.NumberDataPoint_AsInt:
+			p["value"] = v.AsInt
+		case *metricspb.NumberDataPoint_AsDouble:
+			p["value"] = v.AsDouble
+		}
+		out[i] = p
+	}
+	return out
+}
+
+func histogramDataPoints(dps []*metricspb.HistogramDataPoint) []any {
+	out := make([]any, len(dps))
+	for i, dp := range dps {
+		p := map[string]any{
+			"attributes":           attributesToMap(dp.GetAttributes()),
+			"start_time_unix_nano": int64(dp.GetStartTimeUnixNano()),
+			"time_unix_nano":       int64(dp.GetTimeUnixNano()),
+			"flags":                int64(dp.GetFlags()),
+			"count":                int64(dp.GetCount()),
+			"bucket_counts":        uint64sToAny(dp.GetBucketCounts()),
+			"explicit_bounds":      float64sToAny(dp.GetExplicitBounds()),
+		}
+		setOptionalFloat(p, "sum", dp.Sum)
+		setOptionalFloat(p, "min", dp.Min)
+		setOptionalFloat(p, "max", dp.Max)
+		out[i] = p
+	}
+	return out
+}
+
+func exponentialHistogramDataPoints(dps []*metricspb.ExponentialHistogramDataPoint) []any {
+	buckets := func(b *metricspb.ExponentialHistogramDataPoint_Buckets) map[string]any {
+		return map[string]any{
+			"offset":        int64(b.GetOffset()),
+			"bucket_counts": uint64sToAny(b.GetBucketCounts()),
+		}
+	}
+	out := make([]any, len(dps))
+	for i, dp := range dps {
+		p := map[string]any{
+			"attributes":           attributesToMap(dp.GetAttributes()),
+			"start_time_unix_nano": int64(dp.GetStartTimeUnixNano()),
+			"time_unix_nano":       int64(dp.GetTimeUnixNano()),
+			"flags":                int64(dp.GetFlags()),
+			"count":                int64(dp.GetCount()),
+			"scale":                int64(dp.GetScale()),
+			"zero_count":           int64(dp.GetZeroCount()),
+			"zero_threshold":       dp.GetZeroThreshold(),
+			"positive":             buckets(dp.GetPositive()),
+			"negative":             buckets(dp.GetNegative()),
+		}
+		setOptionalFloat(p, "sum", dp.Sum)
+		setOptionalFloat(p, "min", dp.Min)
+		setOptionalFloat(p, "max", dp.Max)
+		out[i] = p
+	}
+	return out
+}
+
+func summaryDataPoints(dps []*metricspb.SummaryDataPoint) []any {
+	out := make([]any, len(dps))
+	for i, dp := range dps {
+		quantiles := make([]any, len(dp.GetQuantileValues()))
+		for j, q := range dp.GetQuantileValues() {
+			quantiles[j] = map[string]any{
+				"quantile": q.GetQuantile(),
+				"value":    q.GetValue(),
+			}
+		}
+		out[i] = map[string]any{
+			"attributes":           attributesToMap(dp.GetAttributes()),
+			"start_time_unix_nano": int64(dp.GetStartTimeUnixNano()),
+			"time_unix_nano":       int64(dp.GetTimeUnixNano()),
+			"flags":                int64(dp.GetFlags()),
+			"count":                int64(dp.GetCount()),
+			"sum":                  dp.GetSum(),
+			"quantile_values":      quantiles,
+		}
+	}
+	return out
+}
+
+func metricToMap(m *metricspb.Metric) map[string]any {
+	record := map[string]any{
+		"name":        m.GetName(),
+		"description": m.GetDescription(),
+		"unit":        m.GetUnit(),
+	}
+	switch d := m.GetData().(type) {
+	case *metricspb.Metric_Gauge:
+		record["type"] = "gauge"
+		record["data_points"] = numberDataPoints(d.Gauge.GetDataPoints())
+	case *metricspb.Metric_Sum:
+		record["type"] = "sum"
+		record["aggregation_temporality"] = d.Sum.GetAggregationTemporality().String()
+		record["is_monotonic"] = d.Sum.GetIsMonotonic()
+		record["data_points"] = numberDataPoints(d.Sum.GetDataPoints())
+	case *metricspb.Metric_Histogram:
+		record["type"] = "histogram"
+		record["aggregation_temporality"] = d.Histogram.GetAggregationTemporality().String()
+		record["data_points"] = histogramDataPoints(d.Histogram.GetDataPoints())
+	case *metricspb.Metric_ExponentialHistogram:
+		record["type"] = "exponential_histogram"
+		record["aggregation_temporality"] = d.ExponentialHistogram.GetAggregationTemporality().String()
+		record["data_points"] = exponentialHistogramDataPoints(d.ExponentialHistogram.GetDataPoints())
+	case *metricspb.Metric_Summary:
+		record["type"] = "summary"
+		record["data_points"] = summaryDataPoints(d.Summary.GetDataPoints())
+	}
+	return record
+}
+
+func metricsToBatch(req *colmetricspb.ExportMetricsServiceRequest) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, rm := range req.GetResourceMetrics() {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				batch = append(batch, newSignalMessage(signalMetrics, rm.GetResource(), sm.GetScope(), metricToMap(m)))
+			}
+		}
+	}
+	return batch
+}
+
+//------------------------------------------------------------------------------
+
+func goToAnyValue(v any) *commonpb.AnyValue {
+	switch t := v.(type) {
+	case nil:
+		return &commonpb.AnyValue{}
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: t}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: t}}
+	case []byte:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: t}}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(t)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: t}}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+		}
+		f, _ := t.Float64()
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+	case []any:
+		arr := make([]*commonpb.AnyValue, len(t))
+		for i, e := range t {
+			arr[i] = goToAnyValue(e)
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: arr}}}
+	case map[string]any:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: mapToAttributes(t)}}}
+	}
+	if i, err := bloblang.ValueAsInt64(v); err == nil {
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: bloblang.ValueToString(v)}}
+}
+
+func mapToAttributes(m map[string]any) []*commonpb.KeyValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]*commonpb.KeyValue, len(keys))
+	for i, k := range keys {
+		kvs[i] = &commonpb.KeyValue{Key: k, Value: goToAnyValue(m[k])}
+	}
+	return kvs
+}
+
+// recordReader extracts the fields of a structured record in the format
+// produced by the otlp_receiver input, retaining the first error encountered so
+// that conversions can be written without checking each field.
+type recordReader struct {
+	m   map[string]any
+	err error
+}
+
+func newRecordReader(v any) (*recordReader, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected object, got %T", v)
+	}
+	return &recordReader{m: m}, nil
+}
+
+func (r *recordReader) fail(key string, err error) {
+	if r.err == nil {
+		r.err = fmt.Errorf("field %v: %w", key, err)
+	}
+}
+
+func (r *recordReader) int64(key string) int64 {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return 0
+	}
+	i, err := bloblang.ValueAsInt64(v)
+	if err != nil {
+		r.fail(key, err)
+	}
+	return i
+}
+
+func (r *recordReader) uint64(key string) uint64 {
+	return uint64(r.int64(key))
+}
+
+func (r *recordReader) float64(key string) float64 {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return 0
+	}
+	f, err := bloblang.ValueAsFloat64(v)
+	if err != nil {
+		r.fail(key, err)
+	}
+	return f
+}
+
+func (r *recordReader) optionalFloat64(key string) *float64 {
+	if v, exists := r.m[key]; !exists || v == nil {
+		return nil
+	}
+	f := r.float64(key)
+	return &f
+}
+
+func (r *recordReader) bool(key string) bool {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return false
+	}
+	b, err := bloblang.ValueAsBool(v)
+	if err != nil {
+		r.fail(key, err)
+	}
+	return b
+}
+
+func (r *recordReader) string(key string) string {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return ""
+	}
+	return bloblang.ValueToString(v)
+}
+
+func (r *recordReader) attributes(key string) []*commonpb.KeyValue {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		r.fail(key, fmt.Errorf("expected object, got %T", v))
+		return nil
+	}
+	return mapToAttributes(m)
+}
+
+func (r *recordReader) hexID(key string, size int) []byte {
+	s := r.string(key)
+	if s == "" {
+		return nil
+	}
+	id, err := hex.DecodeString(s)
+	if err == nil && len(id) != size {
+		err = fmt.Errorf("expected %v bytes, got %v", size, len(id))
+	}
+	if err != nil {
+		r.fail(key, err)
+	}
+	return id
+}
+
+// enum parses an enumeration from either the name of a value or its number.
+func (r *recordReader) enum(key string, values map[string]int32) int32 {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return 0
+	}
+	if s, ok := v.(string); ok {
+		if e, ok := values[s]; ok {
+			return e
+		}
+		r.fail(key, fmt.Errorf("unrecognised value: %v", s))
+		return 0
+	}
+	return int32(r.int64(key))
+}
+
+func (r *recordReader) objects(key string) []*recordReader {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		r.fail(key, fmt.Errorf("expected array, got %T", v))
+		return nil
+	}
+	readers := make([]*recordReader, 0, len(arr))
+	for i, e := range arr {
+		er, err := newRecordReader(e)
+		if err != nil {
+			r.fail(fmt.Sprintf("%v.%v", key, i), err)
+			return nil
+		}
+		readers = append(readers, er)
+	}
+	return readers
+}
+
+func (r *recordReader) object(key string) *recordReader {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return &recordReader{m: map[string]any{}}
+	}
+	or, err := newRecordReader(v)
+	if err != nil {
+		r.fail(key, err)
+		return &recordReader{m: map[string]any{}}
+	}
+	return or
+}
+
+// collect returns the first error encountered by either the reader or any of
+// the provided child readers.
+func (r *recordReader) collect(children ...*recordReader) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, c := range children {
+		if c.err != nil {
+			return c.err
+		}
+	}
+	return nil
+}
+
+func (r *recordReader) uint64s(key string) []uint64 {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		r.fail(key, fmt.Errorf("expected array, got %T", v))
+		return nil
+	}
+	out := make([]uint64, len(arr))
+	for i, e := range arr {
+		n, err := bloblang.ValueAsInt64(e)
+		if err != nil {
+			r.fail(key, err)
+			return nil
+		}
+		out[i] = uint64(n)
+	}
+	return out
+}
+
+func (r *recordReader) float64s(key string) []float64 {
+	v, exists := r.m[key]
+	if !exists || v == nil {
+		return nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		r.fail(key, fmt.Errorf("expected array, got %T", v))
+		return nil
+	}
+	out := make([]float64, len(arr))
+	for i, e := range arr {
+		f, err := bloblang.ValueAsFloat64(e)
+		if err != nil {
+			r.fail(key, err)
+			return nil
+		}
+		out[i] = f
+	}
+	return out
+}
+
+//------------------------------------------------------------------------------
+
+func recordToLogRecord(v any) (*logspb.LogRecord, error) {
+	r, err := newRecordReader(v)
+	if err != nil {
+		return nil, err
+	}
+	lr := &logspb.LogRecord{
+		TimeUnixNano:         r.uint64("time_unix_nano"),
+		ObservedTimeUnixNano: r.uint64("observed_time_unix_nano"),
+		SeverityNumber:       logspb.SeverityNumber(r.enum("severity_number", logspb.SeverityNumber_value)),
+		SeverityText:         r.string("severity_text"),
+		Attributes:           r.attributes("attributes"),
+		Flags:                uint32(r.int64("flags")),
+		TraceId:              r.hexID("trace_id", 16),
+		SpanId:               r.hexID("span_id", 8),
+	}
+	if body, exists := r.m["body"]; exists {
+		lr.Body = goToAnyValue(body)
+	}
+	return lr, r.collect()
+}
+
+func recordToSpan(v any) (*tracepb.Span, error) {
+	r, err := newRecordReader(v)
+	if err != nil {
+		return nil, err
+	}
+	status := r.object("status")
+	span := &tracepb.Span{
+		TraceId:           r.hexID("trace_id", 16),
+		SpanId:            r.hexID("span_id", 8),
+		ParentSpanId:      r.hexID("parent_span_id", 8),
+		TraceState:        r.string("trace_state"),
+		Name:              r.string("name"),
+		Kind:              tracepb.Span_SpanKind(r.enum("kind", tracepb.Span_SpanKind_value)),
+		StartTimeUnixNano: r.uint64("start_time_unix_nano"),
+		EndTimeUnixNano:   r.uint64("end_time_unix_nano"),
+		Attributes:        r.attributes("attributes"),
+		Status: &tracepb.Status{
+			Code:    tracepb.Status_StatusCode(status.enum("code", tracepb.Status_StatusCode_value)),
+			Message: status.string("message"),
+		},
+	}
+	children := []*recordReader{status}
+
+	events := r.objects("events")
+	for _, e := range events {
+		span.Events = append(span.Events, &tracepb.Span_Event{
+			Name:         e.string("name"),
+			TimeUnixNano: e.uint64("time_unix_nano"),
+			Attributes:   e.attributes("attributes"),
+		})
+	}
+	links := r.objects("links")
+	for _, l := range links {
+		span.Links = append(span.Links, &tracepb.Span_Link{
+			TraceId:    l.hexID("trace_id", 16),
+			SpanId:     l.hexID("span_id", 8),
+			TraceState: l.string("trace_state"),
+			Attributes: l.attributes("attributes"),
+		})
+	}
+	children = append(children, events...)
+	children = append(children, links...)
+	return span, r.collect(children...)
+}
+
+func readNumberDataPoints(r *recordReader) ([]*metricspb.NumberDataPoint, []*recordReader) {
+	readers := r.objects("data_points")
+	dps := make([]*metricspb.NumberDataPoint, len(readers))
+	for i, p := range readers {
+		dp := &metricspb.NumberDataPoint{
+			Attributes:        p.attributes("attributes"),
+			StartTimeUnixNano: p.uint64("start_time_unix_nano"),
+			TimeUnixNano:      p.uint64("time_unix_nano"),
+			Flags:             uint32(p.int64("flags")),
+		}
+		switch n := p.m["value"].(type) {
+		case float32, float64:
+			dp.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: p.float64("value")}
+		case json.Number:
+			if i, err := n.Int64(); err == nil {
+				dp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: i}
+			} else {
+				dp.Value = &metricspb.NumberDataPoint_AsDouble{AsDouble: p.float64("value")}
+			}
+		default:
+			dp.Value = &metricspb.NumberDataPoint_AsInt{AsInt: p.int64("value")}
+		}
+		dps[i] = dp
+	}
+	return dps, readers
+}
+
+func readHistogramDataPoints(r *recordReader) ([]*metricspb.HistogramDataPoint, []*recordReader) {
+	readers := r.objects("data_points")
+	dps := make([]*metricspb.HistogramDataPoint, len(readers))
+	for i, p := range readers {
+		dps[i] = &metricspb.HistogramDataPoint{
+			Attributes:        p.attributes("attributes"),
+			StartTimeUnixNano: p.uint64("start_time_unix_nano"),
+			TimeUnixNano:      p.uint64("time_unix_nano"),
+			Flags:             uint32(p.int64("flags")),
+			Count:             p.uint64("count"),
+			Sum:               p.optionalFloat64("sum"),
+			Min:               p.optionalFloat64("min"),
+			Max:               p.optionalFloat64("max"),
+			BucketCounts:      p.uint64s("bucket_counts"),
+			ExplicitBounds:    p.float64s("explicit_bounds"),
+		}
+	}
+	return dps, readers
+}
+
+func readExponentialHistogramDataPoints(r *recordReader) ([]*metricspb.ExponentialHistogramDataPoint, []*recordReader) {
+	readers := r.objects("data_points")
+	dps := make([]*metricspb.ExponentialHistogramDataPoint, len(readers))
+	children := append([]*recordReader{}, readers...)
+	for i, p := range readers {
+		positive, negative := p.object("positive"), p.object("negative")
+		children = append(children, positive, negative)
+		dps[i] = &metricspb.ExponentialHistogramDataPoint{
+			Attributes:        p.attributes("attributes"),
+			StartTimeUnixNano: p.uint64("start_time_unix_nano"),
+			TimeUnixNano:      p.uint64("time_unix_nano"),
+			Flags:             uint32(p.int64("flags")),
+			Count:             p.uint64("count"),
+			Sum:               p.optionalFloat64("sum"),
+			Min:               p.optionalFloat64("min"),
+			Max:               p.optionalFloat64("max"),
+			Scale:             int32(p.int64("scale")),
+			ZeroCount:         p.uint64("zero_count"),
+			ZeroThreshold:     p.float64("zero_threshold"),
+			Positive: &metricspb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       int32(positive.int64("offset")),
+				BucketCounts: positive.uint64s("bucket_counts"),
+			},
+			Negative: &metricspb.ExponentialHistogramDataPoint_Buckets{
+				Offset:       int32(negative.int64("offset")),
+				BucketCounts: negative.uint64s("bucket_counts"),
+			},
+		}
+	}
+	return dps, children
+}
+
+func readSummaryDataPoints(r *recordReader) ([]*metricspb.SummaryDataPoint, []*recordReader) {
+	readers := r.objects("data_points")
+	dps := make([]*metricspb.SummaryDataPoint, len(readers))
+	children := append([]*recordReader{}, readers...)
+	for i, p := range readers {
+		quantiles := p.objects("quantile_values")
+		children = append(children, quantiles...)
+		dp := &metricspb.SummaryDataPoint{
+			Attributes:        p.attributes("attributes"),
+			StartTimeUnixNano: p.uint64("start_time_unix_nano"),
+			TimeUnixNano:      p.uint64("time_unix_nano"),
+			Flags:             uint32(p.int64("flags")),
+			Count:             p.uint64("count"),
+			Sum:               p.float64("sum"),
+		}
+		for _, q := range quantiles {
+			dp.QuantileValues = append(dp.QuantileValues, &metricspb.SummaryDataPoint_ValueAtQuantile{
+				Quantile: q.float64("quantile"),
+				Value:    q.float64("value"),
+			})
+		}
+		dps[i] = dp
+	}
+	return dps, children
+}
+
+func recordToMetric(v any) (*metricspb.Metric, error) {
+	r, err := newRecordReader(v)
+	if err != nil {
+		return nil, err
+	}
+	m := &metricspb.Metric{
+		Name:        r.string("name"),
+		Description: r.string("description"),
+		Unit:        r.string("unit"),
+	}
+	temporality := func() metricspb.AggregationTemporality {
+		return metricspb.AggregationTemporality(r.enum("aggregation_temporality", metricspb.AggregationTemporality_value))
+	}
+
+	var children []*recordReader
+	switch t := r.string("type"); t {
+	case "gauge":
+		var dps []*metricspb.NumberDataPoint
+		dps, children = readNumberDataPoints(r)
+		m.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: dps}}
+	case "sum":
+		var dps []*metricspb.NumberDataPoint
+		dps, children = readNumberDataPoints(r)
+		m.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+			AggregationTemporality: temporality(),
+			IsMonotonic:            r.bool("is_monotonic"),
+			DataPoints:             dps,
+		}}
+	case "histogram":
+		var dps []*metricspb.HistogramDataPoint
+		dps, children = readHistogramDataPoints(r)
+		m.Data = &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+			AggregationTemporality: temporality(),
+			DataPoints:             dps,
+		}}
+	case "exponential_histogram":
+		var dps []*metricspb.ExponentialHistogramDataPoint
+		dps, children = readExponentialHistogramDataPoints(r)
+		m.Data = &metricspb.Metric_ExponentialHistogram{ExponentialHistogram: &metricspb.ExponentialHistogram{
+			AggregationTemporality: temporality(),
+			DataPoints:             dps,
+		}}
+	case "summary":
+		var dps []*metricspb.SummaryDataPoint
+		dps, children = readSummaryDataPoints(r)
+		m.Data = &metricspb.Metric_Summary{Summary: &metricspb.Summary{DataPoints: dps}}
+	case "":
+		return nil, errors.New("field type: a metric type is required")
+	default:
+		return nil, fmt.Errorf("field type: unrecognised metric type: %v", t)
+	}
+	return m, r.collect(children...)
+}
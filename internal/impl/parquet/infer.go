@@ -0,0 +1,304 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type inferredKind int
+
+const (
+	inferredNull inferredKind = iota
+	inferredBool
+	inferredInt
+	inferredFloat
+	inferredString
+	inferredObject
+	inferredArray
+	// inferredJSON is a field with values of conflicting structures, which is
+	// encoded as a JSON string.
+	inferredJSON
+)
+
+// inferredType is the type of a field inferred from the values of a batch.
+type inferredType struct {
+	kind     inferredKind
+	optional bool
+	fields   map[string]*inferredType
+	elem     *inferredType
+}
+
+func inferValueType(v any) *inferredType {
+	switch t := v.(type) {
+	case nil:
+		return &inferredType{kind: inferredNull, optional: true}
+	case bool:
+		return &inferredType{kind: inferredBool}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return &inferredType{kind: inferredInt}
+	case float32, float64:
+		return &inferredType{kind: inferredFloat}
+	case string, []byte:
+		return &inferredType{kind: inferredString}
+	case map[string]any:
+		return inferObjectType(t)
+	case []any:
+		it := &inferredType{kind: inferredArray}
+		for _, e := range t {
+			if e == nil {
+				continue
+			}
+			it.elem = widenType(it.elem, inferValueType(e))
+		}
+		if it.elem != nil && (it.elem.kind == inferredArray || it.elem.kind == inferredNull) {
+			// Nested lists aren't inferred, as they can't be represented
+			// without the list logical type.
+			it.elem = &inferredType{kind: inferredJSON}
+		}
+		return it
+	}
+	return &inferredType{kind: inferredJSON}
+}
+
+func inferObjectType(obj map[string]any) *inferredType {
+	it := &inferredType{kind: inferredObject, fields: make(map[string]*inferredType, len(obj))}
+	for k, v := range obj {
+		it.fields[k] = inferValueType(v)
+	}
+	return it
+}
+
+// widenType returns a type which can represent the values of both types. Ints
+// are widened to floats, and values of conflicting structures to JSON strings.
+func widenType(a, b *inferredType) *inferredType {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	optional := a.optional || b.optional
+	switch {
+	case a.kind == inferredNull:
+		b.optional = true
+		return b
+	case b.kind == inferredNull:
+		a.optional = true
+		return a
+	case a.kind == b.kind:
+	case (a.kind == inferredInt && b.kind == inferredFloat) || (a.kind == inferredFloat && b.kind == inferredInt):
+		return &inferredType{kind: inferredFloat, optional: optional}
+	case a.kind == inferredString && b.kind <= inferredString, b.kind == inferredString && a.kind <= inferredString:
+		return &inferredType{kind: inferredString, optional: optional}
+	default:
+		return &inferredType{kind: inferredJSON, optional: optional}
+	}
+
+	switch a.kind {
+	case inferredObject:
+		for k, bf := range b.fields {
+			af, exists := a.fields[k]
+			if !exists {
+				bf.optional = true
+			}
+			a.fields[k] = widenType(af, bf)
+		}
+		for k, af := range a.fields {
+			if _, exists := b.fields[k]; !exists {
+				af.optional = true
+			}
+		}
+	case inferredArray:
+		a.elem = widenType(a.elem, b.elem)
+	}
+	a.optional = optional
+	return a
+}
+
+// inferSchemaFromBatch infers a schema from the rows of a batch, where columns
+// of overrides take precedence over inferred columns of the same name.
+func inferSchemaFromBatch(rows []any, overrides parquet.Group, encodingFn encodingFn) (*inferredType, *parquet.Schema) {
+	var root *inferredType
+	for _, row := range rows {
+		root = widenType(root, inferObjectType(row.(map[string]any)))
+	}
+	if root == nil {
+		root = &inferredType{kind: inferredObject, fields: map[string]*inferredType{}}
+	}
+	for k := range overrides {
+		delete(root.fields, k)
+	}
+
+	group := inferredGroup(root, encodingFn)
+	for k, n := range overrides {
+		group[k] = n
+	}
+	return root, parquet.NewSchema("", group)
+}
+
+func inferredGroup(it *inferredType, encodingFn encodingFn) parquet.Group {
+	group := parquet.Group{}
+	for k, f := range it.fields {
+		n := inferredNode(f, encodingFn)
+		if f.kind == inferredArray {
+			n = parquet.Repeated(n)
+		} else if f.optional {
+			n = parquet.Optional(n)
+		}
+		group[k] = n
+	}
+	return group
+}
+
+func inferredNode(it *inferredType, encodingFn encodingFn) parquet.Node {
+	switch it.kind {
+	case inferredBool:
+		return encodingFn(parquet.Leaf(parquet.BooleanType))
+	case inferredInt:
+		return encodingFn(parquet.Int(64))
+	case inferredFloat:
+		return encodingFn(parquet.Leaf(parquet.DoubleType))
+	case inferredObject:
+		return inferredGroup(it, encodingFn)
+	case inferredArray:
+		if it.elem == nil {
+			return encodingFn(parquet.String())
+		}
+		return inferredNode(it.elem, encodingFn)
+	}
+	return encodingFn(parquet.String())
+}
+
+// overrideCoercion returns the type that values of an override column are
+// converted into, which is nil when values are written unchanged.
+func overrideCoercion(n parquet.Node) *inferredType {
+	if !n.Leaf() {
+		return nil
+	}
+	switch n.Type().Kind() {
+	case parquet.ByteArray:
+		if lt := n.Type().LogicalType(); lt != nil && lt.UTF8 != nil {
+			return &inferredType{kind: inferredString}
+		}
+	case parquet.Double:
+		return &inferredType{kind: inferredFloat}
+	}
+	return nil
+}
+
+// coerceInferred converts the values of a row into the types of an inferred
+// schema, dropping null elements of lists, which can't be represented.
+func coerceInferred(v any, it *inferredType) any {
+	if v == nil || it == nil {
+		return v
+	}
+	switch it.kind {
+	case inferredFloat:
+		switch t := v.(type) {
+		case int64:
+			return float64(t)
+		case int:
+			return float64(t)
+		}
+	case inferredString, inferredJSON, inferredNull:
+		switch t := v.(type) {
+		case string:
+			return t
+		case []byte:
+			return string(t)
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		return string(b)
+	case inferredObject:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		for k, f := range it.fields {
+			if fv, exists := obj[k]; exists {
+				obj[k] = coerceInferred(fv, f)
+			}
+		}
+		return obj
+	case inferredArray:
+		arr, ok := v.([]any)
+		if !ok {
+			return v
+		}
+		elems := make([]any, 0, len(arr))
+		for _, e := range arr {
+			if e != nil {
+				elems = append(elems, coerceInferred(e, it.elem))
+			}
+		}
+		return elems
+	}
+	return v
+}
+
+func (it *inferredType) String() string {
+	var b strings.Builder
+	it.format(&b)
+	return b.String()
+}
+
+func (it *inferredType) format(b *strings.Builder) {
+	switch it.kind {
+	case inferredBool:
+		b.WriteString("BOOLEAN")
+	case inferredInt:
+		b.WriteString("INT64")
+	case inferredFloat:
+		b.WriteString("DOUBLE")
+	case inferredObject:
+		keys := make([]string, 0, len(it.fields))
+		for k := range it.fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(k)
+			b.WriteString(": ")
+			it.fields[k].format(b)
+		}
+		b.WriteByte('}')
+	case inferredArray:
+		b.WriteByte('[')
+		if it.elem != nil {
+			it.elem.format(b)
+		} else {
+			b.WriteString("UTF8")
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteString("UTF8")
+	}
+	if it.optional && it.kind != inferredArray {
+		b.WriteByte('?')
+	}
+}
@@ -17,7 +17,9 @@ package parquet
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/parquet-go/parquet-go"
 	"github.com/parquet-go/parquet-go/compress"
@@ -31,6 +33,10 @@ func parquetEncodeProcessorConfig() *service.ConfigSpec {
 		Categories("Parsing").
 		Summary("Encodes https://parquet.apache.org/docs/[Parquet files^] from a batch of structured messages.").
 		Field(parquetSchemaConfig()).
+		Field(service.NewBoolField("infer_schema").
+			Description("Whether to infer the schema from the structure of the messages of the first batch processed. Columns of the `schema` field take precedence over inferred columns of the same name, which allows the types of specific columns to be overridden. See <<schema-inference>>.").
+			Default(false).
+			Version("4.48.0")).
 		Field(service.NewStringEnumField("default_compression",
 			"uncompressed", "snappy", "gzip", "brotli", "zstd", "lz4raw",
 		).
@@ -45,6 +51,18 @@ func parquetEncodeProcessorConfig() *service.ConfigSpec {
 			Version("4.11.0")).
 		Description(`
 This processor uses https://github.com/parquet-go/parquet-go[https://github.com/parquet-go/parquet-go^], which is itself experimental. Therefore changes could be made into how this processor functions outside of major version releases.
+
+== Schema inference
+
+When `+"`infer_schema`"+` is enabled the schema is inferred from the first batch of messages processed, and is then used for all subsequent batches. Each field of the messages becomes a column, with a type determined by the values of the field across the messages of the batch:
+
+- Booleans become `+"`BOOLEAN`"+` columns, integers `+"`INT64`"+` columns and other numbers `+"`DOUBLE`"+` columns. Fields with both integers and other numbers are widened to `+"`DOUBLE`"+`.
+- Strings become `+"`UTF8`"+` columns, as do fields with both strings and other scalar values, which are converted to strings.
+- Objects become groups, with the fields of all objects merged.
+- Arrays become repeated columns of the type inferred from their elements. Null elements are dropped, and nested arrays are encoded as JSON strings.
+- Fields with values of conflicting structures, such as both objects and arrays, become `+"`UTF8`"+` columns of JSON encoded values.
+
+Fields which are null or missing in any message of the batch are optional. Fields of later batches which aren't part of the inferred schema are dropped, and therefore the first batch should be representative of all messages.
 `).
 		Version("4.4.0").
 		// TODO: Add an example that demonstrates error handling
@@ -68,7 +86,20 @@ output:
               - name: content
                 type: BYTE_ARRAY
             default_compression: zstd
-`)
+`).
+		Example("Inferring a Schema",
+			"In this example the schema is inferred from the first batch of messages, apart from the column `id`, which is always encoded as a string.",
+			`
+pipeline:
+  processors:
+    - parquet_encode:
+        infer_schema: true
+        schema:
+          - name: id
+            type: UTF8
+        default_compression: zstd
+`).
+		LintRule(`root = if !this.infer_schema.or(false) && this.schema.or([]).length() == 0 { [ "a schema must be specified when infer_schema is disabled" ] }`)
 }
 
 func init() {
@@ -101,7 +132,7 @@ func parquetSchemaConfig() *service.ConfigField {
 				"type": "BYTE_ARRAY",
 			},
 		}),
-	).Description("Parquet schema.")
+	).Description("Parquet schema. When `infer_schema` is enabled these columns override inferred columns of the same name.").Optional()
 }
 
 type encodingFn func(n parquet.Node) parquet.Node
@@ -177,10 +208,21 @@ func parquetGroupFromConfig(columnConfs []*service.ParsedConfig, encodingFn enco
 //------------------------------------------------------------------------------
 
 func newParquetEncodeProcessorFromConfig(conf *service.ParsedConfig, logger *service.Logger) (*parquetEncodeProcessor, error) {
-	schemaConfs, err := conf.FieldObjectList("schema")
+	var schemaConfs []*service.ParsedConfig
+	if conf.Contains("schema") {
+		var err error
+		if schemaConfs, err = conf.FieldObjectList("schema"); err != nil {
+			return nil, err
+		}
+	}
+
+	inferSchema, err := conf.FieldBool("infer_schema")
 	if err != nil {
 		return nil, err
 	}
+	if !inferSchema && len(schemaConfs) == 0 {
+		return nil, errors.New("a schema must be specified when infer_schema is disabled")
+	}
 
 	customEncoding, err := conf.FieldString("default_encoding")
 	if err != nil {
@@ -222,13 +264,29 @@ func newParquetEncodeProcessorFromConfig(conf *service.ParsedConfig, logger *ser
 	default:
 		return nil, fmt.Errorf("default_compression type %v not recognised", compressStr)
 	}
-	return newParquetEncodeProcessor(logger, schema, compressDefault)
+	proc, err := newParquetEncodeProcessor(logger, schema, compressDefault)
+	if err != nil {
+		return nil, err
+	}
+	if inferSchema {
+		proc.schema = nil
+		proc.inferOverrides = node
+		proc.inferEncodingFn = encoding
+	}
+	return proc, nil
 }
 
 type parquetEncodeProcessor struct {
 	logger          *service.Logger
 	schema          *parquet.Schema
 	compressionType compress.Codec
+
+	// When inferring the schema it is nil until the first batch is processed.
+	inferOverrides  parquet.Group
+	inferEncodingFn encodingFn
+	inferMut        sync.Mutex
+	inferred        *inferredType
+	coercions       map[string]*inferredType
 }
 
 func newParquetEncodeProcessor(logger *service.Logger, schema *parquet.Schema, compressionType compress.Codec) (*parquetEncodeProcessor, error) {
@@ -267,9 +325,6 @@ func (s *parquetEncodeProcessor) ProcessBatch(ctx context.Context, batch service
 		return nil, nil
 	}
 
-	buf := bytes.NewBuffer(nil)
-	pWtr := parquet.NewGenericWriter[any](buf, s.schema, parquet.Compression(s.compressionType))
-
 	batch = batch.Copy()
 	rows := make([]any, len(batch))
 	for i, m := range batch {
@@ -284,6 +339,13 @@ func (s *parquetEncodeProcessor) ProcessBatch(ctx context.Context, batch service
 		}
 	}
 
+	schema := s.schema
+	if s.inferEncodingFn != nil {
+		schema = s.inferSchema(rows)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	pWtr := parquet.NewGenericWriter[any](buf, schema, parquet.Compression(s.compressionType))
 	if err := writeWithoutPanic(pWtr, rows); err != nil {
 		return nil, err
 	}
@@ -296,6 +358,40 @@ func (s *parquetEncodeProcessor) ProcessBatch(ctx context.Context, batch service
 	return []service.MessageBatch{{outMsg}}, nil
 }
 
+// inferSchema returns the schema inferred from the first batch, and converts
+// the values of rows into the inferred types.
+func (s *parquetEncodeProcessor) inferSchema(rows []any) *parquet.Schema {
+	s.inferMut.Lock()
+	if s.schema == nil {
+		s.inferred, s.schema = inferSchemaFromBatch(rows, s.inferOverrides, s.inferEncodingFn)
+		s.coercions = make(map[string]*inferredType, len(s.inferred.fields)+len(s.inferOverrides))
+		for k, f := range s.inferred.fields {
+			s.coercions[k] = f
+		}
+		for k, n := range s.inferOverrides {
+			if c := overrideCoercion(n); c != nil {
+				s.coercions[k] = c
+			}
+		}
+		if s.logger != nil {
+			s.logger.Infof("Inferred parquet schema: %v", s.inferred)
+		}
+	}
+	schema, coercions := s.schema, s.coercions
+	s.inferMut.Unlock()
+
+	for i, row := range rows {
+		obj := row.(map[string]any)
+		for k, f := range coercions {
+			if v, exists := obj[k]; exists {
+				obj[k] = coerceInferred(v, f)
+			}
+		}
+		rows[i] = obj
+	}
+	return schema
+}
+
 func (s *parquetEncodeProcessor) Close(ctx context.Context) error {
 	return nil
 }
@@ -440,3 +440,75 @@ schema:
 	}
 	wg.Wait()
 }
+
+func TestParquetEncodeInferSchema(t *testing.T) {
+	encodeConf, err := parquetEncodeProcessorConfig().ParseYAML(`
+infer_schema: true
+schema:
+  - { name: override, type: UTF8, optional: true }
+`, nil)
+	require.NoError(t, err)
+
+	encodeProc, err := newParquetEncodeProcessorFromConfig(encodeConf, nil)
+	require.NoError(t, err)
+
+	decodeConf, err := parquetDecodeProcessorConfig().ParseYAML(``, nil)
+	require.NoError(t, err)
+
+	decodeProc, err := newParquetDecodeProcessorFromConfig(decodeConf, nil)
+	require.NoError(t, err)
+
+	roundTrip := func(inputs ...string) []string {
+		t.Helper()
+
+		var batch service.MessageBatch
+		for _, in := range inputs {
+			batch = append(batch, service.NewMessage([]byte(in)))
+		}
+		encodedBatches, err := encodeProc.ProcessBatch(context.Background(), batch)
+		require.NoError(t, err)
+		require.Len(t, encodedBatches, 1)
+		require.Len(t, encodedBatches[0], 1)
+
+		decodedBatch, err := decodeProc.Process(context.Background(), encodedBatches[0][0])
+		require.NoError(t, err)
+
+		var outputs []string
+		for _, msg := range decodedBatch {
+			b, err := msg.AsBytes()
+			require.NoError(t, err)
+			outputs = append(outputs, string(b))
+		}
+		return outputs
+	}
+
+	outputs := roundTrip(
+		`{"id":1,"score":1,"name":"foo","tags":["a",null],"mixed":true,"nested":{"a":1},"override":"x"}`,
+		`{"id":2,"score":1.5,"mixed":"bar","nested":{"b":[1,2]},"extra":null,"override":2}`,
+	)
+	require.Len(t, outputs, 2)
+	assert.JSONEq(t, `{"id":1,"score":1,"name":"foo","tags":["a"],"mixed":"true","nested":{"a":1,"b":[]},"extra":null,"override":"x"}`, outputs[0])
+	assert.JSONEq(t, `{"id":2,"score":1.5,"name":null,"tags":[],"mixed":"bar","nested":{"a":null,"b":[1,2]},"extra":null,"override":"2"}`, outputs[1])
+
+	assert.Equal(t, `{extra: UTF8?, id: INT64, mixed: UTF8, name: UTF8?, nested: {a: INT64?, b: [INT64]}, score: DOUBLE, tags: [UTF8]}`, encodeProc.inferred.String())
+
+	// The schema is inferred only once, and so later batches keep it.
+	outputs = roundTrip(`{"id":3,"score":2,"new":"dropped","nested":{"a":5}}`)
+	require.Len(t, outputs, 1)
+	assert.JSONEq(t, `{"id":3,"score":2,"name":null,"tags":[],"mixed":"","nested":{"a":5,"b":[]},"extra":null,"override":null}`, outputs[0])
+
+	_, err = encodeProc.ProcessBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"id":4,"nested":{"a":{"c":1}}}`)),
+	})
+	require.Error(t, err)
+}
+
+func TestParquetEncodeNoSchema(t *testing.T) {
+	encodeConf, err := parquetEncodeProcessorConfig().ParseYAML(`
+default_compression: zstd
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newParquetEncodeProcessorFromConfig(encodeConf, nil)
+	require.ErrorContains(t, err, "a schema must be specified")
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	psFieldBatchCount = "batch_count"
+	psFieldBuffer     = "buffer"
+)
+
+func parquetScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Summary("Consume the rows of https://parquet.apache.org/docs/[Parquet files^] as structured messages.").
+		Description(`
+Rows are decoded in the same way as the `+"xref:components:processors/parquet_decode.adoc[`parquet_decode` processor]"+`, but are read incrementally, and so only the row groups being decoded are held in memory rather than the entire decoded file.
+
+Parquet files are read from their footer, and therefore require random access. When the source of a file, such as an object of an `+"`aws_s3`"+` input, doesn't support random access it's first buffered according to the `+"`buffer`"+` field, whereas files of the `+"`file`"+` input are read directly.`).
+		Fields(
+			service.NewIntField(psFieldBatchCount).
+				Description("The number of rows to yield in each batch, or zero to yield each row group of a file as a batch.").
+				Default(1),
+			service.NewStringAnnotatedEnumField(psFieldBuffer, map[string]string{
+				"disk":   "Files are copied to a temporary file which is removed once consumed.",
+				"memory": "Files are read into memory in full.",
+			}).
+				Description("How to buffer files of which the source doesn't support random access.").
+				Default("disk").
+				Advanced(),
+		).
+		Example("Reading Parquet Files from AWS S3", "In this example we consume the rows of Parquet files from AWS S3 in batches of 100 as they're written, by listening onto an SQS queue for upload events.", `
+input:
+  aws_s3:
+    bucket: TODO
+    prefix: foos/
+    scanner:
+      parquet:
+        batch_count: 100
+    sqs:
+      url: TODO
+`).
+		Version("4.48.0")
+}
+
+func init() {
+	err := service.RegisterBatchScannerCreator("parquet", parquetScannerSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchScannerCreator, error) {
+			return parquetScannerFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func parquetScannerFromParsed(conf *service.ParsedConfig) (*parquetScannerCreator, error) {
+	c := &parquetScannerCreator{}
+	var err error
+	if c.batchCount, err = conf.FieldInt(psFieldBatchCount); err != nil {
+		return nil, err
+	}
+	if c.batchCount < 0 {
+		return nil, fmt.Errorf("%v must be zero or greater, got %v", psFieldBatchCount, c.batchCount)
+	}
+	if c.buffer, err = conf.FieldString(psFieldBuffer); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type parquetScannerCreator struct {
+	batchCount int
+	buffer     string
+}
+
+func (c *parquetScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, details *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	s := &parquetScanner{r: rdr, batchCount: c.batchCount}
+
+	ra, size, err := s.randomAccess(c.buffer)
+	if err != nil {
+		_ = s.Close(context.Background())
+		return nil, err
+	}
+
+	if s.file, err = parquet.OpenFile(ra, size); err != nil {
+		_ = s.Close(context.Background())
+		return nil, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+	if c.batchCount > 0 {
+		if s.rdr, err = newReaderWithoutPanic(s.file); err != nil {
+			_ = s.Close(context.Background())
+			return nil, err
+		}
+	}
+	return service.AutoAggregateBatchScannerAcks(s, aFn), nil
+}
+
+func (c *parquetScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type parquetScanner struct {
+	r          io.ReadCloser
+	tmpFile    *os.File
+	batchCount int
+
+	file *parquet.File
+	rdr  *parquet.GenericReader[any]
+
+	// The index of the next row group to read when yielding row groups.
+	rowGroup int
+}
+
+// randomAccess returns the file as an io.ReaderAt along with its size, which
+// requires buffering it when the source doesn't support random access.
+func (s *parquetScanner) randomAccess(buffer string) (io.ReaderAt, int64, error) {
+	if ra, ok := s.r.(io.ReaderAt); ok {
+		if st, ok := s.r.(interface{ Stat() (fs.FileInfo, error) }); ok {
+			info, err := st.Stat()
+			if err != nil {
+				return nil, 0, err
+			}
+			return ra, info.Size(), nil
+		}
+		if sk, ok := s.r.(io.Seeker); ok {
+			size, err := sk.Seek(0, io.SeekEnd)
+			if err != nil {
+				return nil, 0, err
+			}
+			return ra, size, nil
+		}
+	}
+
+	if buffer == "memory" {
+		b, err := io.ReadAll(s.r)
+		if err != nil {
+			return nil, 0, err
+		}
+		return bytes.NewReader(b), int64(len(b)), nil
+	}
+
+	var err error
+	if s.tmpFile, err = os.CreateTemp("", "parquet-scanner-*"); err != nil {
+		return nil, 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	size, err := io.Copy(s.tmpFile, s.r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to buffer file: %w", err)
+	}
+	return s.tmpFile, size, nil
+}
+
+func (s *parquetScanner) NextBatch(ctx context.Context) (service.MessageBatch, error) {
+	if s.file == nil {
+		return nil, io.EOF
+	}
+
+	var rows []any
+	if s.rdr != nil {
+		rows = make([]any, s.batchCount)
+		n, err := readWithoutPanic(s.rdr, rows)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, io.EOF
+		}
+		rows = rows[:n]
+	} else {
+		rowGroups := s.file.RowGroups()
+		for len(rows) == 0 {
+			if s.rowGroup >= len(rowGroups) {
+				return nil, io.EOF
+			}
+			rg := rowGroups[s.rowGroup]
+			s.rowGroup++
+
+			var err error
+			if rows, err = readRowGroupWithoutPanic(rg); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	batch := make(service.MessageBatch, len(rows))
+	for i, row := range rows {
+		batch[i] = service.NewMessage(nil)
+		batch[i].SetStructuredMut(row)
+	}
+	return batch, nil
+}
+
+func readRowGroupWithoutPanic(rg parquet.RowGroup) (rows []any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decoding panic: %v", r)
+		}
+	}()
+
+	rdr := parquet.NewGenericRowGroupReader[any](rg)
+	defer rdr.Close()
+
+	rows = make([]any, rg.NumRows())
+	n, err := rdr.Read(rows)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return rows[:n], nil
+}
+
+func (s *parquetScanner) Close(ctx context.Context) error {
+	if s.rdr != nil {
+		_ = s.rdr.Close()
+		s.rdr = nil
+	}
+	s.file = nil
+	if s.tmpFile != nil {
+		_ = s.tmpFile.Close()
+		_ = os.Remove(s.tmpFile.Name())
+		s.tmpFile = nil
+	}
+	if s.r == nil {
+		return nil
+	}
+	err := s.r.Close()
+	s.r = nil
+	return err
+}
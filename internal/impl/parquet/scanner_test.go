@@ -0,0 +1,154 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// sequentialReader hides the random access of a reader.
+type sequentialReader struct {
+	io.Reader
+}
+
+func (sequentialReader) Close() error {
+	return nil
+}
+
+func testParquetScannerFile(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[simpleData](&buf, parquet.MaxRowsPerRowGroup(3))
+	_, err := w.Write([]simpleData{
+		{ID: 1, Value: "foo 1"},
+		{ID: 2, Value: "foo 2"},
+		{ID: 3, Value: "foo 3"},
+		{ID: 4, Value: "foo 4"},
+		{ID: 5, Value: "foo 5"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func scanParquet(t *testing.T, conf string, rdr io.ReadCloser) (batches [][]any) {
+	t.Helper()
+
+	pConf, err := parquetScannerSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	creator, err := parquetScannerFromParsed(pConf)
+	require.NoError(t, err)
+
+	scanner, err := creator.Create(rdr, func(context.Context, error) error { return nil }, service.NewScannerSourceDetails())
+	require.NoError(t, err)
+
+	for {
+		batch, _, err := scanner.NextBatch(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		var rows []any
+		for _, m := range batch {
+			v, err := m.AsStructured()
+			require.NoError(t, err)
+			rows = append(rows, v)
+		}
+		batches = append(batches, rows)
+	}
+	require.NoError(t, scanner.Close(context.Background()))
+	return
+}
+
+func row(id int64) any {
+	return map[string]any{"ID": id, "Value": fmt.Sprintf("foo %v", id)}
+}
+
+func TestParquetScanner(t *testing.T) {
+	pqBytes := testParquetScannerFile(t)
+
+	tmpPath := filepath.Join(t.TempDir(), "foo.parquet")
+	require.NoError(t, os.WriteFile(tmpPath, pqBytes, 0o644))
+
+	tests := []struct {
+		name     string
+		conf     string
+		open     func() io.ReadCloser
+		expected [][]any
+	}{
+		{
+			name: "rows from file",
+			conf: `{}`,
+			open: func() io.ReadCloser {
+				f, err := os.Open(tmpPath)
+				require.NoError(t, err)
+				return f
+			},
+			expected: [][]any{{row(1)}, {row(2)}, {row(3)}, {row(4)}, {row(5)}},
+		},
+		{
+			name: "batches buffered on disk",
+			conf: `batch_count: 2`,
+			open: func() io.ReadCloser {
+				return sequentialReader{bytes.NewReader(pqBytes)}
+			},
+			expected: [][]any{{row(1), row(2)}, {row(3), row(4)}, {row(5)}},
+		},
+		{
+			name: "row groups buffered in memory",
+			conf: `
+batch_count: 0
+buffer: memory
+`,
+			open: func() io.ReadCloser {
+				return sequentialReader{bytes.NewReader(pqBytes)}
+			},
+			expected: [][]any{{row(1), row(2), row(3)}, {row(4), row(5)}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, scanParquet(t, test.conf, test.open()))
+		})
+	}
+}
+
+func TestParquetScannerInvalid(t *testing.T) {
+	pConf, err := parquetScannerSpec().ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+
+	creator, err := parquetScannerFromParsed(pConf)
+	require.NoError(t, err)
+
+	_, err = creator.Create(sequentialReader{bytes.NewReader([]byte("not parquet"))}, nil, service.NewScannerSourceDetails())
+	require.Error(t, err)
+}
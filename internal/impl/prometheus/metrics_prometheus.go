@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"regexp"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -46,6 +48,11 @@ const (
 	pmFieldPushInterval                = "push_interval"
 	pmFieldPushJobName                 = "push_job_name"
 	pmFieldFileOutputPath              = "file_output_path"
+	pmFieldPathLabels                  = "path_labels"
+	pmFieldPathLabelsEnabled           = "enabled"
+	pmFieldPathLabelsAllowList         = "allow_list"
+	pmFieldPathLabelsMaxPaths          = "max_paths"
+	pmFieldPathLabelsOverflowValue     = "overflow_value"
 )
 
 func configSpec() *service.ConfigSpec {
@@ -59,7 +66,13 @@ The field `+"`push_url`"+` is optional and when set will trigger a push of metri
 
 The Push Gateway is useful for when Redpanda Connect instances are short lived. Do not include the "/metrics/jobs/..." path in the push URL.
 
-If the Push Gateway requires HTTP Basic Authentication it can be configured with `+"`push_basic_auth`.").
+If the Push Gateway requires HTTP Basic Authentication it can be configured with `+"`push_basic_auth`."+`
+
+== Path labels
+
+Metrics emitted by components are labelled with a `+"`path`"+` describing where the component sits within the config, such as `+"`root.input`"+` or `+"`root.pipeline.processors.0`"+`. Configs with many components can therefore produce a large number of series, which can be kept in check with the `+"`path_labels`"+` field.
+
+Setting `+"`path_labels.allow_list`"+` restricts the paths that are exported as they are, and any path that does not match is replaced with `+"`path_labels.overflow_value`"+`. Setting `+"`path_labels.max_paths`"+` caps the number of distinct paths exported, and once the cap is reached any new path is also replaced with `+"`path_labels.overflow_value`"+`. Series of components that share the overflow value are combined, and therefore gauges of those components overwrite each other.`).
 		Fields(
 			service.NewBoolField(pmFieldUseHistogramTiming).
 				Description("Whether to export timing metrics as a histogram, if `false` a summary is used instead. When exporting histogram timings the delta values are converted from nanoseconds into seconds in order to better fit within bucket definitions. For more information on histograms and summaries refer to: https://prometheus.io/docs/practices/histograms/.").
@@ -126,6 +139,24 @@ If the Push Gateway requires HTTP Basic Authentication it can be configured with
 				Description("An optional file path to write all prometheus metrics on service shutdown.").
 				Advanced().
 				Default(""),
+			service.NewObjectField(pmFieldPathLabels,
+				service.NewBoolField(pmFieldPathLabelsEnabled).
+					Description("Whether to include the `path` label on metrics. When `false` the label is removed from all metrics.").
+					Default(true),
+				service.NewStringListField(pmFieldPathLabelsAllowList).
+					Description("An optional list of regular expressions, a path is exported as it is only when it matches at least one of them. When empty all paths are allowed.").
+					Example([]any{`^root\.input`, `^root\.output`}).
+					Default([]any{}),
+				service.NewIntField(pmFieldPathLabelsMaxPaths).
+					Description("The maximum number of distinct paths to export, where zero means no limit.").
+					Default(0),
+				service.NewStringField(pmFieldPathLabelsOverflowValue).
+					Description("The value of the `path` label for paths that are not allowed or that exceed `max_paths`.").
+					Default("other"),
+			).
+				Description("Controls the cardinality of the `path` label added to component metrics. For more information refer to <<path-labels, path labels>>.").
+				Advanced().
+				Version("4.48.0"),
 		)
 }
 
@@ -258,6 +289,8 @@ type metrics struct {
 	pusher *push.Pusher
 	reg    *prometheus.Registry
 
+	paths *pathLabels
+
 	counters   map[string]*promCounterVec
 	gauges     map[string]*promGaugeVec
 	timers     map[string]*promTimingVec
@@ -360,12 +393,122 @@ func fromParsed(conf *service.ParsedConfig, log *service.Logger) (p *metrics, er
 		}
 	}
 
+	if p.paths, err = pathLabelsFromParsed(conf.Namespace(pmFieldPathLabels), log); err != nil {
+		return nil, err
+	}
+
 	p.fileOutputPath, _ = conf.FieldString(pmFieldFileOutputPath)
 	return p, nil
 }
 
 //------------------------------------------------------------------------------
 
+const pathLabelName = "path"
+
+type pathLabels struct {
+	log *service.Logger
+
+	enabled   bool
+	allowList []*regexp.Regexp
+	maxPaths  int
+	overflow  string
+
+	mut       sync.Mutex
+	seen      map[string]struct{}
+	capLogged bool
+}
+
+func pathLabelsFromParsed(conf *service.ParsedConfig, log *service.Logger) (*pathLabels, error) {
+	l := &pathLabels{
+		log:  log,
+		seen: map[string]struct{}{},
+	}
+
+	var err error
+	if l.enabled, err = conf.FieldBool(pmFieldPathLabelsEnabled); err != nil {
+		return nil, err
+	}
+
+	allowList, err := conf.FieldStringList(pmFieldPathLabelsAllowList)
+	if err != nil {
+		return nil, err
+	}
+	for _, pattern := range allowList {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile path allow list pattern '%v': %w", pattern, err)
+		}
+		l.allowList = append(l.allowList, re)
+	}
+
+	if l.maxPaths, err = conf.FieldInt(pmFieldPathLabelsMaxPaths); err != nil {
+		return nil, err
+	}
+	if l.maxPaths < 0 {
+		return nil, fmt.Errorf("field %v must not be negative", pmFieldPathLabelsMaxPaths)
+	}
+
+	if l.overflow, err = conf.FieldString(pmFieldPathLabelsOverflowValue); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// apply returns the label names a metric should be registered with along with
+// a function that converts the label values of the metric to match them.
+func (l *pathLabels) apply(labelNames []string) ([]string, func([]string) []string) {
+	idx := slices.Index(labelNames, pathLabelName)
+	if idx < 0 || (l.enabled && len(l.allowList) == 0 && l.maxPaths == 0) {
+		return labelNames, func(labelValues []string) []string {
+			return labelValues
+		}
+	}
+
+	if !l.enabled {
+		return slices.Delete(slices.Clone(labelNames), idx, idx+1), func(labelValues []string) []string {
+			if idx >= len(labelValues) {
+				return labelValues
+			}
+			return slices.Delete(slices.Clone(labelValues), idx, idx+1)
+		}
+	}
+
+	return labelNames, func(labelValues []string) []string {
+		if idx >= len(labelValues) {
+			return labelValues
+		}
+		labelValues = slices.Clone(labelValues)
+		labelValues[idx] = l.value(labelValues[idx])
+		return labelValues
+	}
+}
+
+func (l *pathLabels) value(path string) string {
+	if len(l.allowList) > 0 && !slices.ContainsFunc(l.allowList, func(re *regexp.Regexp) bool {
+		return re.MatchString(path)
+	}) {
+		return l.overflow
+	}
+
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if _, exists := l.seen[path]; exists {
+		return path
+	}
+	if l.maxPaths > 0 && len(l.seen) >= l.maxPaths {
+		if !l.capLogged {
+			l.capLogged = true
+			l.log.Warnf("Reached the limit of %v distinct metric paths, further paths such as '%v' are labelled as '%v'", l.maxPaths, path, l.overflow)
+		}
+		return l.overflow
+	}
+	l.seen[path] = struct{}{}
+	return path
+}
+
+//------------------------------------------------------------------------------
+
 func (p *metrics) HandlerFunc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		promhttp.HandlerFor(p.reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
@@ -380,6 +523,8 @@ func (p *metrics) NewCounterCtor(path string, labelNames ...string) service.Metr
 		}
 	}
 
+	labelNames, mapValues := p.paths.apply(labelNames)
+
 	var pv *promCounterVec
 
 	p.mut.Lock()
@@ -406,7 +551,7 @@ func (p *metrics) NewCounterCtor(path string, labelNames ...string) service.Metr
 		}
 	}
 	return func(labelValues ...string) service.MetricsExporterCounter {
-		return pv.With(labelValues...)
+		return pv.With(mapValues(labelValues)...)
 	}
 }
 
@@ -418,8 +563,9 @@ func (p *metrics) NewTimerCtor(path string, labelNames ...string) service.Metric
 		}
 	}
 
+	labelNames, mapValues := p.paths.apply(labelNames)
 	if p.useHistogramTiming {
-		return p.getTimerHistVec(path, labelNames...)
+		return p.getTimerHistVec(path, labelNames, mapValues)
 	}
 
 	var pv *promTimingVec
@@ -449,11 +595,11 @@ func (p *metrics) NewTimerCtor(path string, labelNames ...string) service.Metric
 		}
 	}
 	return func(labelValues ...string) service.MetricsExporterTimer {
-		return pv.With(labelValues...)
+		return pv.With(mapValues(labelValues)...)
 	}
 }
 
-func (p *metrics) getTimerHistVec(path string, labelNames ...string) service.MetricsExporterTimerCtor {
+func (p *metrics) getTimerHistVec(path string, labelNames []string, mapValues func([]string) []string) service.MetricsExporterTimerCtor {
 	var pv *promTimingHistVec
 
 	p.mut.Lock()
@@ -481,7 +627,7 @@ func (p *metrics) getTimerHistVec(path string, labelNames ...string) service.Met
 		}
 	}
 	return func(labelValues ...string) service.MetricsExporterTimer {
-		return pv.With(labelValues...)
+		return pv.With(mapValues(labelValues)...)
 	}
 }
 
@@ -493,6 +639,8 @@ func (p *metrics) NewGaugeCtor(path string, labelNames ...string) service.Metric
 		}
 	}
 
+	labelNames, mapValues := p.paths.apply(labelNames)
+
 	var pv *promGaugeVec
 
 	p.mut.Lock()
@@ -519,7 +667,7 @@ func (p *metrics) NewGaugeCtor(path string, labelNames ...string) service.Metric
 		}
 	}
 	return func(labelValues ...string) service.MetricsExporterGauge {
-		return pv.With(labelValues...)
+		return pv.With(mapValues(labelValues)...)
 	}
 }
 
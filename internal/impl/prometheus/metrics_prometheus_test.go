@@ -192,6 +192,87 @@ use_histogram_timing: true
 	assert.Contains(t, body, "\ntimertwo_sum{label3=\"value4\",label4=\"value5\"} 1.4e-08")
 }
 
+func TestPrometheusPathLabelsDisabled(t *testing.T) {
+	nm := promFromYAML(t, `
+path_labels:
+  enabled: false
+`)
+
+	ctr := nm.NewCounterCtor("counterone", "label", "path")
+	ctr("foo", "root.input").Incr(10)
+	ctr("foo", "root.output").Incr(11)
+
+	tmr := nm.NewTimerCtor("timerone", "path", "label")
+	tmr("root.input", "bar").Timing(13)
+
+	body := getPage(t, nm.HandlerFunc())
+
+	assert.Contains(t, body, "\ncounterone{label=\"foo\"} 21")
+	assert.Contains(t, body, "\ntimerone_sum{label=\"bar\"} 13")
+	assert.NotContains(t, body, "path=")
+}
+
+func TestPrometheusPathLabelsAllowList(t *testing.T) {
+	nm := promFromYAML(t, `
+use_histogram_timing: true
+path_labels:
+  allow_list: [ '^root\.input', '^root\.output$' ]
+`)
+
+	ctr := nm.NewCounterCtor("counterone", "label", "path")
+	ctr("", "root.input").Incr(1)
+	ctr("", "root.output").Incr(2)
+	ctr("", "root.pipeline.processors.0").Incr(3)
+	ctr("", "root.pipeline.processors.1").Incr(4)
+
+	tmr := nm.NewTimerCtor("timerone", "path")
+	tmr("root.pipeline.processors.0").Timing(1_000_000_000)
+
+	body := getPage(t, nm.HandlerFunc())
+
+	assert.Contains(t, body, "\ncounterone{label=\"\",path=\"root.input\"} 1")
+	assert.Contains(t, body, "\ncounterone{label=\"\",path=\"root.output\"} 2")
+	assert.Contains(t, body, "\ncounterone{label=\"\",path=\"other\"} 7")
+	assert.Contains(t, body, "\ntimerone_sum{path=\"other\"} 1")
+}
+
+func TestPrometheusPathLabelsMaxPaths(t *testing.T) {
+	nm := promFromYAML(t, `
+path_labels:
+  max_paths: 2
+  overflow_value: overflow
+`)
+
+	ctr := nm.NewCounterCtor("counterone", "path")
+	ctr("root.input").Incr(1)
+	ctr("root.pipeline.processors.0").Incr(2)
+	ctr("root.pipeline.processors.1").Incr(3)
+	ctr("root.output").Incr(4)
+
+	gge := nm.NewGaugeCtor("gaugeone", "path")
+	gge("root.input").Set(5)
+	gge("root.output").Set(6)
+
+	body := getPage(t, nm.HandlerFunc())
+
+	assert.Contains(t, body, "\ncounterone{path=\"root.input\"} 1")
+	assert.Contains(t, body, "\ncounterone{path=\"root.pipeline.processors.0\"} 2")
+	assert.Contains(t, body, "\ncounterone{path=\"overflow\"} 7")
+	assert.Contains(t, body, "\ngaugeone{path=\"root.input\"} 5")
+	assert.Contains(t, body, "\ngaugeone{path=\"overflow\"} 6")
+}
+
+func TestPrometheusPathLabelsBadPattern(t *testing.T) {
+	pConf, err := configSpec().ParseYAML(`
+path_labels:
+  allow_list: [ '(' ]
+`, nil)
+	require.NoError(t, err)
+
+	_, err = fromParsed(pConf, nil)
+	require.Error(t, err)
+}
+
 func TestPrometheusWithFileOutputPath(t *testing.T) {
 	fPath := t.TempDir() + "/benthos_metrics.prom"
 
@@ -17,6 +17,7 @@ package protobuf
 import (
 	"fmt"
 
+	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/desc/protoparse"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/dynamicpb"
@@ -41,20 +42,46 @@ func RegistriesFromMap(filesMap map[string]string) (*protoregistry.Files, *proto
 	}
 
 	files, types := &protoregistry.Files{}, &protoregistry.Types{}
-	for _, v := range fds {
-		if err := files.RegisterFile(v.UnwrapFile()); err != nil {
-			return nil, nil, fmt.Errorf("failed to register file '%v': %w", v.GetName(), err)
+	seen := map[string]struct{}{}
+
+	var registerFile func(fd *desc.FileDescriptor) error
+	registerFile = func(fd *desc.FileDescriptor) error {
+		if _, exists := seen[fd.GetName()]; exists {
+			return nil
 		}
-		for _, t := range v.GetMessageTypes() {
-			if err := types.RegisterMessage(dynamicpb.NewMessageType(t.UnwrapMessage())); err != nil {
-				return nil, nil, fmt.Errorf("failed to register type '%v': %w", t.GetName(), err)
-			}
-			for _, nt := range t.GetNestedMessageTypes() {
-				if err := types.RegisterMessage(dynamicpb.NewMessageType(nt.UnwrapMessage())); err != nil {
-					return nil, nil, fmt.Errorf("failed to register type '%v': %w", nt.GetFullyQualifiedName(), err)
-				}
+		seen[fd.GetName()] = struct{}{}
+
+		// Imported files, such as the well-known google.protobuf types, are
+		// registered as well so that their messages can be resolved when
+		// (un)marshalling, e.g. from within a google.protobuf.Any.
+		for _, dep := range fd.GetDependencies() {
+			if err := registerFile(dep); err != nil {
+				return err
 			}
 		}
+
+		if err := files.RegisterFile(fd.UnwrapFile()); err != nil {
+			return fmt.Errorf("failed to register file '%v': %w", fd.GetName(), err)
+		}
+		return registerMessageTypes(types, fd.GetMessageTypes())
+	}
+
+	for _, v := range fds {
+		if err := registerFile(v); err != nil {
+			return nil, nil, err
+		}
 	}
 	return files, types, nil
 }
+
+func registerMessageTypes(types *protoregistry.Types, msgs []*desc.MessageDescriptor) error {
+	for _, t := range msgs {
+		if err := types.RegisterMessage(dynamicpb.NewMessageType(t.UnwrapMessage())); err != nil {
+			return fmt.Errorf("failed to register type '%v': %w", t.GetFullyQualifiedName(), err)
+		}
+		if err := registerMessageTypes(types, t.GetNestedMessageTypes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protobuf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	franz_sr "github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/confluent/sr"
+)
+
+// descriptorLoader loads the protobuf files and types used by an operator.
+type descriptorLoader func(ctx context.Context) (*protoregistry.Files, *protoregistry.Types, error)
+
+// schemaRegistryLoader returns a loader that obtains the latest schema of a
+// subject from a schema registry, along with all of the schemas it
+// references.
+func schemaRegistryLoader(client *sr.Client, subject string) descriptorLoader {
+	return func(ctx context.Context) (*protoregistry.Files, *protoregistry.Types, error) {
+		info, err := client.GetSchemaBySubjectAndVersion(ctx, subject, nil, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to load latest schema for subject %q: %w", subject, err)
+		}
+
+		regMap := map[string]string{
+			".": info.Schema.Schema,
+		}
+		if err := client.WalkReferences(ctx, info.References, func(ctx context.Context, name string, si franz_sr.Schema) error {
+			regMap[name] = si.Schema
+			return nil
+		}); err != nil {
+			return nil, nil, err
+		}
+
+		files, types, err := RegistriesFromMap(regMap)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse schema %v of subject %q: %w", info.ID, subject, err)
+		}
+		return files, types, nil
+	}
+}
+
+// bsrRequestTimeout bounds requests to a Buf Schema Registry, including those
+// made during refreshes, which aren't otherwise subject to a deadline.
+const bsrRequestTimeout = 30 * time.Second
+
+// bsrLoader obtains the file descriptors of a module from a Buf Schema
+// Registry, see https://buf.build/bufbuild/registry.
+type bsrLoader struct {
+	client  *http.Client
+	baseURL string
+	owner   string
+	module  string
+	ref     string
+	apiKey  string
+	message string
+}
+
+// newBSRLoader creates a loader for a module reference of the form
+// <remote>/<owner>/<module>[:<ref>], such as buf.build/acme/weather:main.
+func newBSRLoader(moduleRef, apiKey, message string) (*bsrLoader, error) {
+	name, ref, _ := strings.Cut(moduleRef, ":")
+	parts := strings.Split(name, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("module reference %q must be of the form <remote>/<owner>/<module>[:<ref>]", moduleRef)
+	}
+	return &bsrLoader{
+		client:  &http.Client{Timeout: bsrRequestTimeout},
+		baseURL: "https://" + parts[0],
+		owner:   parts[1],
+		module:  parts[2],
+		ref:     ref,
+		apiKey:  apiKey,
+		message: message,
+	}, nil
+}
+
+type bsrRequest struct {
+	ResourceRef struct {
+		Name struct {
+			Owner  string `json:"owner"`
+			Module string `json:"module"`
+			Ref    string `json:"ref,omitempty"`
+		} `json:"name"`
+	} `json:"resourceRef"`
+	IncludeTypes []string `json:"includeTypes,omitempty"`
+}
+
+type bsrResponse struct {
+	FileDescriptorSet json.RawMessage `json:"fileDescriptorSet"`
+}
+
+type bsrError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (b *bsrLoader) load(ctx context.Context) (*protoregistry.Files, *protoregistry.Types, error) {
+	var body bsrRequest
+	body.ResourceRef.Name.Owner = b.owner
+	body.ResourceRef.Name.Module = b.module
+	body.ResourceRef.Name.Ref = b.ref
+	if b.message != "" {
+		body.IncludeTypes = []string{b.message}
+	}
+	reqBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		b.baseURL+"/buf.registry.module.v1.FileDescriptorSetService/GetFileDescriptorSet",
+		bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	res, err := b.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	resBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		var e bsrError
+		if err := json.Unmarshal(resBytes, &e); err == nil && e.Message != "" {
+			return nil, nil, fmt.Errorf("buf schema registry returned %s: %s", e.Code, e.Message)
+		}
+		return nil, nil, fmt.Errorf("buf schema registry returned unexpected status code %d: %s", res.StatusCode, resBytes)
+	}
+
+	var resBody bsrResponse
+	if err := json.Unmarshal(resBytes, &resBody); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse buf schema registry response: %w", err)
+	}
+	if len(resBody.FileDescriptorSet) == 0 {
+		return nil, nil, errors.New("buf schema registry response did not contain a file descriptor set")
+	}
+	var fds descriptorpb.FileDescriptorSet
+	if err := protojson.Unmarshal(resBody.FileDescriptorSet, &fds); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse file descriptor set: %w", err)
+	}
+	return registriesFromFileDescriptorSet(&fds)
+}
+
+func registriesFromFileDescriptorSet(fds *descriptorpb.FileDescriptorSet) (*protoregistry.Files, *protoregistry.Types, error) {
+	files, err := protodesc.NewFiles(fds)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to register file descriptors: %w", err)
+	}
+
+	types := &protoregistry.Types{}
+	var registerMessages func(msgs protoreflect.MessageDescriptors) error
+	registerMessages = func(msgs protoreflect.MessageDescriptors) error {
+		for i := 0; i < msgs.Len(); i++ {
+			md := msgs.Get(i)
+			if err := types.RegisterMessage(dynamicpb.NewMessageType(md)); err != nil {
+				return fmt.Errorf("failed to register type '%v': %w", md.FullName(), err)
+			}
+			if err := registerMessages(md.Messages()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		err = registerMessages(fd.Messages())
+		return err == nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, types, nil
+}
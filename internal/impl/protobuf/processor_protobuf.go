@@ -21,6 +21,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 
@@ -29,6 +32,8 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/redpanda-data/connect/v4/internal/impl/confluent/sr"
 )
 
 const (
@@ -37,6 +42,17 @@ const (
 	fieldImportPaths    = "import_paths"
 	fieldDiscardUnknown = "discard_unknown"
 	fieldUseProtoNames  = "use_proto_names"
+
+	fieldSchemaRegistry                = "schema_registry"
+	fieldSchemaRegistryURL             = "url"
+	fieldSchemaRegistrySubject         = "subject"
+	fieldSchemaRegistryRefreshInterval = "refresh_interval"
+	fieldSchemaRegistryTLS             = "tls"
+
+	fieldBSR                = "bsr"
+	fieldBSRModule          = "module"
+	fieldBSRAPIKey          = "api_key"
+	fieldBSRRefreshInterval = "refresh_interval"
 )
 
 func protobufProcessorSpec() *service.ConfigSpec {
@@ -59,6 +75,12 @@ Converts protobuf messages into a generic JSON structure. This makes it easier t
 === `+"`from_json`"+`
 
 Attempts to create a target protobuf message from a generic JSON structure.
+
+== Schema sources
+
+By default definitions are parsed from the .proto files found within `+"`import_paths`"+`. Alternatively, definitions can be loaded when the processor starts from either the latest schema of a subject within a schema registry with the `+"`schema_registry`"+` field, or from a module within a https://buf.build/docs/bsr/[Buf Schema Registry^] with the `+"`bsr`"+` field. Only one source can be used by a processor.
+
+Remote sources can be refreshed periodically by setting a `+"`refresh_interval`"+`, in which case the definitions are reloaded in the background once the first message after each interval has been processed. The previous definitions continue to be used until a refresh completes, and when a refresh fails (or takes longer than a minute) the error is logged and the previous definitions remain in use.
 `).Fields(
 		service.NewStringEnumField(fieldOperator, "to_json", "from_json").
 			Description("The <<operators, operator>> to execute"),
@@ -73,6 +95,41 @@ Attempts to create a target protobuf message from a generic JSON structure.
 		service.NewStringListField(fieldImportPaths).
 			Description("A list of directories containing .proto files, including all definitions required for parsing the target message. If left empty the current directory is used. Each directory listed will be walked with all found .proto files imported.").
 			Default([]string{}),
+		service.NewObjectField(fieldSchemaRegistry,
+			slices.Concat(
+				[]*service.ConfigField{
+					service.NewURLField(fieldSchemaRegistryURL).Description("The base URL of the schema registry service."),
+					service.NewStringField(fieldSchemaRegistrySubject).
+						Description("The subject to load the latest schema of. All schemas referenced by it are loaded as well."),
+					service.NewDurationField(fieldSchemaRegistryRefreshInterval).
+						Description("The period after which the latest schema is loaded again. If not specified the schema is only loaded once.").
+						Optional(),
+					service.NewTLSField(fieldSchemaRegistryTLS),
+				},
+				service.NewHTTPRequestAuthSignerFields(),
+			)...,
+		).
+			Description("Load definitions from the latest protobuf schema of a subject within a schema registry.").
+			Optional().
+			Advanced().
+			Version("4.48.0"),
+		service.NewObjectField(fieldBSR,
+			service.NewStringField(fieldBSRModule).
+				Description("The module to load definitions from, in the form `<remote>/<owner>/<module>`, optionally followed by a colon and a label, commit or tag. When no reference is given the default label of the module is used.").
+				Example("buf.build/acme/weather").
+				Example("buf.build/acme/weather:v1.2.0"),
+			service.NewStringField(fieldBSRAPIKey).
+				Description("An API token used to authenticate with the registry, which is required for private modules.").
+				Default("").
+				Secret(),
+			service.NewDurationField(fieldBSRRefreshInterval).
+				Description("The period after which the module is loaded again. If not specified the module is only loaded once.").
+				Optional(),
+		).
+			Description("Load definitions from a module within a Buf Schema Registry.").
+			Optional().
+			Advanced().
+			Version("4.48.0"),
 	).Example(
 		"JSON to Protobuf", `
 If we have the following protobuf definition within a directory called `+"`testing/schema`"+`:
@@ -166,19 +223,10 @@ func init() {
 
 type protobufOperator func(part *service.Message) error
 
-func newProtobufToJSONOperator(f fs.FS, msg string, importPaths []string, useProtoNames bool) (protobufOperator, error) {
-	if msg == "" {
-		return nil, errors.New("message field must not be empty")
-	}
-
-	descriptors, types, err := loadDescriptors(f, importPaths)
-	if err != nil {
-		return nil, err
-	}
-
+func newProtobufToJSONOperator(descriptors *protoregistry.Files, types *protoregistry.Types, msg string, useProtoNames bool) (protobufOperator, error) {
 	d, err := descriptors.FindDescriptorByName(protoreflect.FullName(msg))
 	if err != nil {
-		return nil, fmt.Errorf("unable to find message '%v' definition within '%v'", msg, importPaths)
+		return nil, fmt.Errorf("unable to find message '%v' definition", msg)
 	}
 
 	md, ok := d.(protoreflect.MessageDescriptor)
@@ -211,23 +259,10 @@ func newProtobufToJSONOperator(f fs.FS, msg string, importPaths []string, usePro
 	}, nil
 }
 
-func newProtobufFromJSONOperator(f fs.FS, msg string, importPaths []string, discardUnknown bool) (protobufOperator, error) {
-	if msg == "" {
-		return nil, errors.New("message field must not be empty")
-	}
-
-	_, types, err := loadDescriptors(f, importPaths)
-	if err != nil {
-		return nil, err
-	}
-
-	types.RangeMessages(func(mt protoreflect.MessageType) bool {
-		return true
-	})
-
+func newProtobufFromJSONOperator(types *protoregistry.Types, msg string, discardUnknown bool) (protobufOperator, error) {
 	md, err := types.FindMessageByName(protoreflect.FullName(msg))
 	if err != nil {
-		return nil, fmt.Errorf("unable to find message '%v' definition within '%v'", msg, importPaths)
+		return nil, fmt.Errorf("unable to find message '%v' definition", msg)
 	}
 
 	return func(part *service.Message) error {
@@ -256,12 +291,12 @@ func newProtobufFromJSONOperator(f fs.FS, msg string, importPaths []string, disc
 	}, nil
 }
 
-func strToProtobufOperator(f fs.FS, opStr, message string, importPaths []string, discardUnknown, useProtoNames bool) (protobufOperator, error) {
+func strToProtobufOperator(files *protoregistry.Files, types *protoregistry.Types, opStr, message string, discardUnknown, useProtoNames bool) (protobufOperator, error) {
 	switch opStr {
 	case "to_json":
-		return newProtobufToJSONOperator(f, message, importPaths, useProtoNames)
+		return newProtobufToJSONOperator(files, types, message, useProtoNames)
 	case "from_json":
-		return newProtobufFromJSONOperator(f, message, importPaths, discardUnknown)
+		return newProtobufFromJSONOperator(types, message, discardUnknown)
 	}
 	return nil, fmt.Errorf("operator not recognised: %v", opStr)
 }
@@ -295,14 +330,33 @@ func loadDescriptors(f fs.FS, importPaths []string) (*protoregistry.Files, *prot
 //------------------------------------------------------------------------------
 
 type protobufProc struct {
-	operator protobufOperator
-	log      *service.Logger
+	log *service.Logger
+
+	newOperator func(files *protoregistry.Files, types *protoregistry.Types) (protobufOperator, error)
+
+	// When set the operator is periodically rebuilt from the loader.
+	loader          descriptorLoader
+	refreshInterval time.Duration
+
+	mut         sync.Mutex
+	operator    protobufOperator
+	nextRefresh time.Time
+	refreshing  bool
+
+	closeCtx  context.Context
+	closeFn   context.CancelFunc
+	refreshWG sync.WaitGroup
 }
 
+// refreshTimeout bounds each attempt at loading definitions from a remote
+// source.
+const refreshTimeout = time.Minute
+
 func newProtobuf(conf *service.ParsedConfig, mgr *service.Resources) (*protobufProc, error) {
 	p := &protobufProc{
 		log: mgr.Logger(),
 	}
+	p.closeCtx, p.closeFn = context.WithCancel(context.Background())
 
 	operatorStr, err := conf.FieldString(fieldOperator)
 	if err != nil {
@@ -313,6 +367,9 @@ func newProtobuf(conf *service.ParsedConfig, mgr *service.Resources) (*protobufP
 	if message, err = conf.FieldString(fieldMessage); err != nil {
 		return nil, err
 	}
+	if message == "" {
+		return nil, errors.New("message field must not be empty")
+	}
 
 	var importPaths []string
 	if importPaths, err = conf.FieldStringList(fieldImportPaths); err != nil {
@@ -329,14 +386,132 @@ func newProtobuf(conf *service.ParsedConfig, mgr *service.Resources) (*protobufP
 		return nil, err
 	}
 
-	if p.operator, err = strToProtobufOperator(mgr.FS(), operatorStr, message, importPaths, discardUnknown, useProtoNames); err != nil {
+	p.newOperator = func(files *protoregistry.Files, types *protoregistry.Types) (protobufOperator, error) {
+		return strToProtobufOperator(files, types, operatorStr, message, discardUnknown, useProtoNames)
+	}
+
+	if conf.Contains(fieldSchemaRegistry) && conf.Contains(fieldBSR) {
+		return nil, fmt.Errorf("only one of %v or %v can be specified", fieldSchemaRegistry, fieldBSR)
+	}
+	if (conf.Contains(fieldSchemaRegistry) || conf.Contains(fieldBSR)) && len(importPaths) > 0 {
+		return nil, fmt.Errorf("%v cannot be specified alongside a remote schema source", fieldImportPaths)
+	}
+
+	switch {
+	case conf.Contains(fieldSchemaRegistry):
+		srConf := conf.Namespace(fieldSchemaRegistry)
+		url, err := srConf.FieldString(fieldSchemaRegistryURL)
+		if err != nil {
+			return nil, err
+		}
+		reqSigner, err := srConf.HTTPRequestAuthSignerFromParsed()
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig, err := srConf.FieldTLS(fieldSchemaRegistryTLS)
+		if err != nil {
+			return nil, err
+		}
+		client, err := sr.NewClient(url, reqSigner, tlsConfig, mgr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create schema registry client: %w", err)
+		}
+		subject, err := srConf.FieldString(fieldSchemaRegistrySubject)
+		if err != nil {
+			return nil, err
+		}
+		if srConf.Contains(fieldSchemaRegistryRefreshInterval) {
+			if p.refreshInterval, err = srConf.FieldDuration(fieldSchemaRegistryRefreshInterval); err != nil {
+				return nil, err
+			}
+		}
+		p.loader = schemaRegistryLoader(client, subject)
+	case conf.Contains(fieldBSR):
+		bConf := conf.Namespace(fieldBSR)
+		module, err := bConf.FieldString(fieldBSRModule)
+		if err != nil {
+			return nil, err
+		}
+		apiKey, err := bConf.FieldString(fieldBSRAPIKey)
+		if err != nil {
+			return nil, err
+		}
+		if bConf.Contains(fieldBSRRefreshInterval) {
+			if p.refreshInterval, err = bConf.FieldDuration(fieldBSRRefreshInterval); err != nil {
+				return nil, err
+			}
+		}
+		loader, err := newBSRLoader(module, apiKey, message)
+		if err != nil {
+			return nil, err
+		}
+		p.loader = loader.load
+	default:
+		files, types, err := loadDescriptors(mgr.FS(), importPaths)
+		if err != nil {
+			return nil, err
+		}
+		if p.operator, err = p.newOperator(files, types); err != nil {
+			return nil, err
+		}
+		return p, nil
+	}
+
+	operator, err := p.loadOperator(context.Background())
+	if err != nil {
 		return nil, err
 	}
+	p.operator = operator
+	if p.refreshInterval > 0 {
+		p.nextRefresh = time.Now().Add(p.refreshInterval)
+	}
 	return p, nil
 }
 
+func (p *protobufProc) loadOperator(ctx context.Context) (protobufOperator, error) {
+	ctx, done := context.WithTimeout(ctx, refreshTimeout)
+	defer done()
+
+	files, types, err := p.loader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return p.newOperator(files, types)
+}
+
+// refresh reloads the definitions in the background and swaps the operator
+// once they are loaded, the previous operator is used until then.
+func (p *protobufProc) refresh() {
+	defer p.refreshWG.Done()
+
+	operator, err := p.loadOperator(p.closeCtx)
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	p.refreshing = false
+	p.nextRefresh = time.Now().Add(p.refreshInterval)
+	if err != nil {
+		p.log.Errorf("Failed to refresh protobuf definitions, continuing with previous definitions: %v", err)
+		return
+	}
+	p.operator = operator
+}
+
+func (p *protobufProc) getOperator() protobufOperator {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	if p.loader != nil && p.refreshInterval > 0 && !p.refreshing && !time.Now().Before(p.nextRefresh) && p.closeCtx.Err() == nil {
+		p.refreshing = true
+		p.refreshWG.Add(1)
+		go p.refresh()
+	}
+	return p.operator
+}
+
 func (p *protobufProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
-	if err := p.operator(msg); err != nil {
+	if err := p.getOperator()(msg); err != nil {
 		p.log.Debugf("Operator failed: %v", err)
 		return nil, err
 	}
@@ -344,5 +519,10 @@ func (p *protobufProc) Process(ctx context.Context, msg *service.Message) (servi
 }
 
 func (p *protobufProc) Close(context.Context) error {
+	p.mut.Lock()
+	p.closeFn()
+	p.mut.Unlock()
+
+	p.refreshWG.Wait()
 	return nil
 }
@@ -16,13 +16,25 @@ package protobuf
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
@@ -271,3 +283,191 @@ import_paths: [ %v ]
 		})
 	}
 }
+
+func TestProtobufSchemaRegistry(t *testing.T) {
+	var mut sync.Mutex
+	personSchema := `syntax = "proto3";
+package testing;
+import "common.proto";
+message Person {
+  string name = 1;
+  testing.Address address = 2;
+}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		var res map[string]any
+		switch r.URL.Path {
+		case "/subjects/person/versions/latest", "/subjects/person/versions/-1":
+			res = map[string]any{
+				"subject": "person", "version": 1, "id": 2, "schemaType": "PROTOBUF",
+				"schema":     personSchema,
+				"references": []any{map[string]any{"name": "common.proto", "subject": "common", "version": 1}},
+			}
+		case "/subjects/common/versions/1":
+			res = map[string]any{
+				"subject": "common", "version": 1, "id": 1, "schemaType": "PROTOBUF",
+				"schema": `syntax = "proto3";
+package testing;
+message Address {
+  string city = 1;
+}`,
+			}
+		default:
+			http.Error(w, `{"error_code":40401,"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	conf, err := protobufProcessorSpec().ParseYAML(fmt.Sprintf(`
+operator: from_json
+message: testing.Person
+schema_registry:
+  url: %v
+  subject: person
+  refresh_interval: 1ms
+`, srv.URL), nil)
+	require.NoError(t, err)
+
+	proc, err := newProtobuf(conf, service.MockResources())
+	require.NoError(t, err)
+
+	msgs, err := proc.Process(context.Background(), service.NewMessage([]byte(`{"name":"foo","address":{"city":"bar"}}`)))
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	_, err = proc.Process(context.Background(), service.NewMessage([]byte(`{"name":"foo","age":10}`)))
+	require.ErrorContains(t, err, `unknown field "age"`)
+
+	mut.Lock()
+	personSchema = `syntax = "proto3";
+package testing;
+import "common.proto";
+message Person {
+  string name = 1;
+  testing.Address address = 2;
+  int32 age = 3;
+}`
+	mut.Unlock()
+
+	assert.Eventually(t, func() bool {
+		_, err := proc.Process(context.Background(), service.NewMessage([]byte(`{"name":"foo","age":10}`)))
+		return err == nil
+	}, time.Second, time.Millisecond*5)
+
+	require.NoError(t, proc.Close(context.Background()))
+}
+
+func TestProtobufRefreshInBackground(t *testing.T) {
+	var loads atomic.Int32
+	loadStarted := make(chan struct{}, 1)
+
+	proc := &protobufProc{
+		log: service.MockResources().Logger(),
+		loader: func(ctx context.Context) (*protoregistry.Files, *protoregistry.Types, error) {
+			loads.Add(1)
+			loadStarted <- struct{}{}
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		},
+		refreshInterval: time.Millisecond,
+		operator: func(*service.Message) error {
+			return nil
+		},
+		nextRefresh: time.Now(),
+	}
+	proc.closeCtx, proc.closeFn = context.WithCancel(context.Background())
+
+	// The loader blocks until the processor is closed, messages must continue
+	// to be processed with the previous definitions in the meantime.
+	for i := 0; i < 5; i++ {
+		_, err := proc.Process(context.Background(), service.NewMessage([]byte(`{}`)))
+		require.NoError(t, err)
+	}
+
+	select {
+	case <-loadStarted:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for refresh")
+	}
+	assert.Equal(t, int32(1), loads.Load())
+
+	require.NoError(t, proc.Close(context.Background()))
+}
+
+func TestProtobufSchemaSourceConflicts(t *testing.T) {
+	for name, yaml := range map[string]string{
+		"registry and bsr": `
+operator: to_json
+message: testing.Person
+schema_registry:
+  url: http://localhost:8081
+  subject: foo
+bsr:
+  module: buf.build/acme/weather
+`,
+		"registry and import paths": `
+operator: to_json
+message: testing.Person
+import_paths: [ ./foo ]
+bsr:
+  module: buf.build/acme/weather
+`,
+		"bad module": `
+operator: to_json
+message: testing.Person
+bsr:
+  module: acme/weather
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := protobufProcessorSpec().ParseYAML(yaml, nil)
+			require.NoError(t, err)
+			_, err = newProtobuf(conf, service.MockResources())
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestProtobufBSRLoader(t *testing.T) {
+	fdp := protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto)
+	fdsBytes, err := protojson.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fdp}})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buf.registry.module.v1.FileDescriptorSetService/GetFileDescriptorSet", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"unauthenticated","message":"bad token"}`))
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+  "resourceRef": {"name": {"owner": "acme", "module": "weather", "ref": "v1"}},
+  "includeTypes": ["google.protobuf.FileDescriptorSet"]
+}`, string(body))
+		_, _ = fmt.Fprintf(w, `{"fileDescriptorSet":%s,"commit":{"id":"abc"}}`, fdsBytes)
+	}))
+	defer srv.Close()
+
+	loader, err := newBSRLoader("buf.build/acme/weather:v1", "secret", "google.protobuf.FileDescriptorSet")
+	require.NoError(t, err)
+	assert.Equal(t, "https://buf.build", loader.baseURL)
+	assert.Equal(t, bsrRequestTimeout, loader.client.Timeout)
+	loader.baseURL = srv.URL
+
+	files, types, err := loader.load(context.Background())
+	require.NoError(t, err)
+
+	op, err := strToProtobufOperator(files, types, "from_json", "google.protobuf.FileDescriptorSet", false, false)
+	require.NoError(t, err)
+	require.NoError(t, op(service.NewMessage([]byte(`{"file":[{"name":"foo.proto"}]}`))))
+
+	loader.apiKey = "wrong"
+	_, _, err = loader.load(context.Background())
+	require.EqualError(t, err, "buf schema registry returned unauthenticated: bad token")
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	rlfFieldResource   = "resource"
+	rlfFieldThrottled  = "throttled"
+	rlfFieldRetryAfter = "retry_after"
+)
+
+func rateLimitFeedbackProcessorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.48.0").
+		Summary("Reports the outcome of requests to an `adaptive` rate limit resource.").
+		Description(`
+Each message is reported to the rate limit as the outcome of a single request, and is otherwise left unchanged. Messages where the `+"`throttled`"+` mapping resolves to `+"`true`"+` are reported as throttled, messages flagged with an error are reported as failures, and all other messages are reported as successes.
+
+When a message is throttled the `+"`retry_after`"+` field is resolved in order to obtain a delay to pause all requests for. The delay may be a number of seconds or an HTTP date, as used by the `+"`Retry-After`"+` header, or a duration string such as `+"`500ms`"+`.
+
+This processor should be placed after the components that use the rate limit, and before any error handling that would remove error flags from messages.`).
+		Fields(
+			service.NewStringField(rlfFieldResource).
+				Description("The label of an `adaptive` rate limit resource to report to."),
+			service.NewBloblangField(rlfFieldThrottled).
+				Description("A mapping that resolves to `true` when the request of a message was throttled.").
+				Default(`root = @http_status_code == 429`),
+			service.NewInterpolatedStringField(rlfFieldRetryAfter).
+				Description("The delay to pause requests for when a message was throttled. When empty the requests are not paused.").
+				Default(`${! metadata("retry-after").or("") }`),
+		)
+}
+
+func init() {
+	err := service.RegisterProcessor("rate_limit_feedback", rateLimitFeedbackProcessorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newRateLimitFeedbackFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type rateLimitFeedbackProcessor struct {
+	mgr        *service.Resources
+	resource   string
+	throttled  *bloblang.Executor
+	retryAfter *service.InterpolatedString
+}
+
+func newRateLimitFeedbackFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*rateLimitFeedbackProcessor, error) {
+	p := &rateLimitFeedbackProcessor{mgr: mgr}
+
+	var err error
+	if p.resource, err = conf.FieldString(rlfFieldResource); err != nil {
+		return nil, err
+	}
+	if !mgr.HasRateLimit(p.resource) {
+		return nil, fmt.Errorf("rate limit resource '%v' was not found", p.resource)
+	}
+	if p.throttled, err = conf.FieldBloblang(rlfFieldThrottled); err != nil {
+		return nil, err
+	}
+	if p.retryAfter, err = conf.FieldInterpolatedString(rlfFieldRetryAfter); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *rateLimitFeedbackProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	rl := getAdaptiveRateLimit(p.mgr, p.resource)
+	if rl == nil {
+		return nil, fmt.Errorf("rate limit resource '%v' is not an adaptive rate limit", p.resource)
+	}
+
+	var throttled bool
+	res, err := msg.BloblangQuery(p.throttled)
+	if err != nil {
+		return nil, fmt.Errorf("throttled mapping failed: %w", err)
+	}
+	if res != nil {
+		v, err := res.AsStructured()
+		if err != nil {
+			return nil, fmt.Errorf("throttled mapping failed: %w", err)
+		}
+		var ok bool
+		if throttled, ok = v.(bool); !ok {
+			return nil, fmt.Errorf("throttled mapping returned non-boolean value: %T", v)
+		}
+	}
+
+	if !throttled {
+		rl.reportOutcome(msg.GetError())
+		return service.MessageBatch{msg}, nil
+	}
+
+	s, err := p.retryAfter.TryString(msg)
+	if err != nil {
+		return nil, fmt.Errorf("retry_after interpolation failed: %w", err)
+	}
+	delay, err := parseRetryAfter(s, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	rl.reportThrottled(delay)
+	return service.MessageBatch{msg}, nil
+}
+
+// parseRetryAfter parses a delay from either the number of seconds or HTTP
+// date formats of a Retry-After header, or from a duration string.
+func parseRetryAfter(s string, now time.Time) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return t.Sub(now), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse retry delay '%v'", s)
+	}
+	return d, nil
+}
+
+func (p *rateLimitFeedbackProcessor) Close(context.Context) error {
+	return nil
+}
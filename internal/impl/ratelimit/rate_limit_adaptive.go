@@ -0,0 +1,318 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	arlFieldCount              = "count"
+	arlFieldInterval           = "interval"
+	arlFieldMinCount           = "min_count"
+	arlFieldDecreaseFactor     = "decrease_factor"
+	arlFieldIncrease           = "increase"
+	arlFieldErrorRateThreshold = "error_rate_threshold"
+
+	// The minimum number of outcomes reported within an interval before the
+	// error rate is considered.
+	arlMinErrorSamples = 10
+)
+
+func adaptiveRateLimitSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Version("4.48.0").
+		Summary("A rate limit that adjusts the number of requests allowed per interval based on feedback from the services being called.").
+		Description(`
+This rate limit starts by allowing `+"`count`"+` requests per interval, and adjusts this rate using feedback reported by a `+"<<rate_limit_feedback, `rate_limit_feedback`>>"+` processor placed after the components that use it:
+
+- When a request is throttled, for example with a 429 response, the rate is multiplied by `+"`decrease_factor`"+`, at most once per interval.
+- When a throttled response includes a retry delay, such as a `+"`Retry-After`"+` header, all requests are paused until the delay has elapsed.
+- When the proportion of failed requests within an interval exceeds `+"`error_rate_threshold`"+`, the rate is decreased as if a request was throttled. At least ten outcomes must be reported within an interval for the error rate to be considered.
+- After each interval where requests were made without any being throttled, the rate is increased by `+"`increase`"+` until it reaches `+"`count`"+` again.
+
+The state of this rate limit is local to each instance of Redpanda Connect.
+
+== Reporting Feedback
+
+Feedback is reported by referencing the label of the rate limit resource from a `+"`rate_limit_feedback`"+` processor, which is typically placed immediately after the processor using the rate limit:
+
+`+"```yaml"+`
+pipeline:
+  processors:
+    - http:
+        url: https://api.example.com/enrich
+        verb: POST
+        rate_limit: api_limit
+        extract_headers:
+          include_patterns: [ '^retry-after$' ]
+    - rate_limit_feedback:
+        resource: api_limit
+
+rate_limit_resources:
+  - label: api_limit
+    adaptive:
+      count: 100
+      interval: 1s
+`+"```"+`
+`).
+		Fields(
+			service.NewIntField(arlFieldCount).
+				Description("The maximum number of requests to allow for a given period of time, which is also the starting rate.").
+				Default(1000).
+				LintRule(`root = if this <= 0 { [ "count must be larger than zero" ] }`),
+			service.NewDurationField(arlFieldInterval).
+				Description("The time window to limit requests by.").
+				Default("1s"),
+			service.NewIntField(arlFieldMinCount).
+				Description("The minimum number of requests to allow per interval regardless of feedback.").
+				Default(1).
+				Advanced(),
+			service.NewFloatField(arlFieldDecreaseFactor).
+				Description("The factor to multiply the rate by when requests are throttled, which must be between 0 and 1.").
+				Default(0.5).
+				Advanced(),
+			service.NewIntField(arlFieldIncrease).
+				Description("The number of requests to add to the rate after each interval without throttling. When zero a tenth of `count` is used.").
+				Default(0).
+				Advanced(),
+			service.NewFloatField(arlFieldErrorRateThreshold).
+				Description("The proportion of failed requests within an interval above which the rate is decreased. Set to `1` in order to ignore errors.").
+				Default(0.5).
+				Advanced(),
+		)
+}
+
+func init() {
+	err := service.RegisterRateLimit("adaptive", adaptiveRateLimitSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.RateLimit, error) {
+			rl, err := newAdaptiveRateLimitFromConfig(conf)
+			if err != nil {
+				return nil, err
+			}
+			registerAdaptiveRateLimit(mgr, mgr.Label(), rl)
+			return rl, nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type adaptiveRateLimitKey struct {
+	label string
+}
+
+// adaptiveRateLimitRef holds the current adaptive rate limit of a given label,
+// which allows feedback processors to find it even when the resource is
+// replaced.
+type adaptiveRateLimitRef struct {
+	mut sync.Mutex
+	rl  *adaptiveRateLimit
+}
+
+func registerAdaptiveRateLimit(mgr *service.Resources, label string, rl *adaptiveRateLimit) {
+	if label == "" {
+		return
+	}
+	v, _ := mgr.GetOrSetGeneric(adaptiveRateLimitKey{label: label}, &adaptiveRateLimitRef{})
+	ref := v.(*adaptiveRateLimitRef)
+
+	ref.mut.Lock()
+	ref.rl = rl
+	ref.mut.Unlock()
+
+	rl.ref = ref
+}
+
+func getAdaptiveRateLimit(mgr *service.Resources, label string) *adaptiveRateLimit {
+	v, ok := mgr.GetGeneric(adaptiveRateLimitKey{label: label})
+	if !ok {
+		return nil
+	}
+	ref := v.(*adaptiveRateLimitRef)
+
+	ref.mut.Lock()
+	defer ref.mut.Unlock()
+	return ref.rl
+}
+
+//------------------------------------------------------------------------------
+
+type adaptiveRateLimit struct {
+	maxRate        float64
+	minRate        float64
+	interval       time.Duration
+	decreaseFactor float64
+	increase       float64
+	errThreshold   float64
+
+	ref   *adaptiveRateLimitRef
+	nowFn func() time.Time
+
+	mut         sync.Mutex
+	rate        float64
+	windowStart time.Time
+	used        int
+	pausedUntil time.Time
+	successes   int
+	failures    int
+	throttled   bool
+}
+
+func newAdaptiveRateLimitFromConfig(conf *service.ParsedConfig) (*adaptiveRateLimit, error) {
+	count, err := conf.FieldInt(arlFieldCount)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := conf.FieldDuration(arlFieldInterval)
+	if err != nil {
+		return nil, err
+	}
+	minCount, err := conf.FieldInt(arlFieldMinCount)
+	if err != nil {
+		return nil, err
+	}
+	decreaseFactor, err := conf.FieldFloat(arlFieldDecreaseFactor)
+	if err != nil {
+		return nil, err
+	}
+	increase, err := conf.FieldInt(arlFieldIncrease)
+	if err != nil {
+		return nil, err
+	}
+	errThreshold, err := conf.FieldFloat(arlFieldErrorRateThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	if count <= 0 {
+		return nil, errors.New("count must be larger than zero")
+	}
+	if interval <= 0 {
+		return nil, errors.New("interval must be larger than zero")
+	}
+	if minCount <= 0 || minCount > count {
+		return nil, errors.New("min_count must be larger than zero and no larger than count")
+	}
+	if decreaseFactor <= 0 || decreaseFactor >= 1 {
+		return nil, errors.New("decrease_factor must be between 0 and 1")
+	}
+	if increase < 0 {
+		return nil, errors.New("increase must not be negative")
+	}
+	if increase == 0 {
+		increase = max(1, count/10)
+	}
+
+	return &adaptiveRateLimit{
+		maxRate:        float64(count),
+		minRate:        float64(minCount),
+		interval:       interval,
+		decreaseFactor: decreaseFactor,
+		increase:       float64(increase),
+		errThreshold:   errThreshold,
+		nowFn:          time.Now,
+		rate:           float64(count),
+	}, nil
+}
+
+func (a *adaptiveRateLimit) Access(context.Context) (time.Duration, error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	now := a.nowFn()
+	if now.Before(a.pausedUntil) {
+		return a.pausedUntil.Sub(now), nil
+	}
+
+	if elapsed := now.Sub(a.windowStart); elapsed >= a.interval {
+		a.endWindow()
+		a.windowStart = now
+	}
+
+	if a.used >= int(math.Floor(a.rate)) {
+		return a.windowStart.Add(a.interval).Sub(now), nil
+	}
+	a.used++
+	return 0, nil
+}
+
+// endWindow adjusts the rate based on the feedback received during the current
+// window and resets it.
+func (a *adaptiveRateLimit) endWindow() {
+	if total := a.successes + a.failures; !a.throttled && total >= arlMinErrorSamples &&
+		float64(a.failures)/float64(total) > a.errThreshold {
+		a.decrease()
+	} else if !a.throttled && a.used > 0 {
+		a.rate = min(a.maxRate, a.rate+a.increase)
+	}
+	a.used = 0
+	a.successes = 0
+	a.failures = 0
+	a.throttled = false
+}
+
+func (a *adaptiveRateLimit) decrease() {
+	a.rate = max(a.minRate, a.rate*a.decreaseFactor)
+}
+
+// reportThrottled decreases the rate, at most once per interval, and pauses all
+// requests for the retry delay when it is larger than zero.
+func (a *adaptiveRateLimit) reportThrottled(retryAfter time.Duration) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if !a.throttled {
+		a.throttled = true
+		a.decrease()
+	}
+	if retryAfter > 0 {
+		if until := a.nowFn().Add(retryAfter); until.After(a.pausedUntil) {
+			a.pausedUntil = until
+		}
+	}
+}
+
+func (a *adaptiveRateLimit) reportOutcome(err error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if err != nil {
+		a.failures++
+	} else {
+		a.successes++
+	}
+}
+
+func (a *adaptiveRateLimit) Close(context.Context) error {
+	if a.ref == nil {
+		return nil
+	}
+	a.ref.mut.Lock()
+	if a.ref.rl == a {
+		a.ref.rl = nil
+	}
+	a.ref.mut.Unlock()
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.now = f.now.Add(d)
+}
+
+func newAdaptiveForTest(t *testing.T, yaml string) (*adaptiveRateLimit, *fakeClock) {
+	t.Helper()
+	conf, err := adaptiveRateLimitSpec().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+	rl, err := newAdaptiveRateLimitFromConfig(conf)
+	require.NoError(t, err)
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	rl.nowFn = clock.Now
+	return rl, clock
+}
+
+// accessAll consumes all requests allowed within the current interval and
+// returns how many were allowed.
+func accessAll(t *testing.T, rl *adaptiveRateLimit) int {
+	t.Helper()
+	n := 0
+	for {
+		d, err := rl.Access(context.Background())
+		require.NoError(t, err)
+		if d > 0 {
+			return n
+		}
+		n++
+	}
+}
+
+func TestAdaptiveRateLimitStatic(t *testing.T) {
+	rl, clock := newAdaptiveForTest(t, `
+count: 10
+interval: 1s
+`)
+
+	assert.Equal(t, 10, accessAll(t, rl))
+
+	clock.Advance(400 * time.Millisecond)
+	d, err := rl.Access(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 600*time.Millisecond, d)
+
+	clock.Advance(600 * time.Millisecond)
+	assert.Equal(t, 10, accessAll(t, rl))
+}
+
+func TestAdaptiveRateLimitThrottled(t *testing.T) {
+	rl, clock := newAdaptiveForTest(t, `
+count: 20
+interval: 1s
+increase: 5
+`)
+
+	assert.Equal(t, 20, accessAll(t, rl))
+
+	// Several throttles within an interval only decrease the rate once.
+	rl.reportThrottled(0)
+	rl.reportThrottled(0)
+	clock.Advance(time.Second)
+	assert.Equal(t, 10, accessAll(t, rl))
+
+	rl.reportThrottled(0)
+	clock.Advance(time.Second)
+	assert.Equal(t, 5, accessAll(t, rl))
+
+	// The rate then recovers with each interval without throttling.
+	clock.Advance(time.Second)
+	assert.Equal(t, 10, accessAll(t, rl))
+	clock.Advance(time.Second)
+	assert.Equal(t, 15, accessAll(t, rl))
+	clock.Advance(time.Second)
+	assert.Equal(t, 20, accessAll(t, rl))
+	clock.Advance(time.Second)
+	assert.Equal(t, 20, accessAll(t, rl))
+}
+
+func TestAdaptiveRateLimitMinCount(t *testing.T) {
+	rl, clock := newAdaptiveForTest(t, `
+count: 4
+interval: 1s
+min_count: 2
+`)
+
+	for i := 0; i < 3; i++ {
+		accessAll(t, rl)
+		rl.reportThrottled(0)
+		clock.Advance(time.Second)
+	}
+	assert.Equal(t, 2, accessAll(t, rl))
+}
+
+func TestAdaptiveRateLimitRetryAfter(t *testing.T) {
+	rl, clock := newAdaptiveForTest(t, `
+count: 10
+interval: 1s
+`)
+
+	d, err := rl.Access(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	rl.reportThrottled(3 * time.Second)
+
+	clock.Advance(time.Second)
+	d, err = rl.Access(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, d)
+
+	clock.Advance(2 * time.Second)
+	assert.Equal(t, 5, accessAll(t, rl))
+}
+
+func TestAdaptiveRateLimitErrorRate(t *testing.T) {
+	rl, clock := newAdaptiveForTest(t, `
+count: 20
+interval: 1s
+error_rate_threshold: 0.5
+`)
+
+	// Too few samples to be considered.
+	accessAll(t, rl)
+	for i := 0; i < 5; i++ {
+		rl.reportOutcome(errors.New("nope"))
+	}
+	clock.Advance(time.Second)
+	assert.Equal(t, 20, accessAll(t, rl))
+
+	for i := 0; i < 6; i++ {
+		rl.reportOutcome(errors.New("nope"))
+	}
+	for i := 0; i < 4; i++ {
+		rl.reportOutcome(nil)
+	}
+	clock.Advance(time.Second)
+	assert.Equal(t, 10, accessAll(t, rl))
+}
+
+func TestAdaptiveRateLimitConfigErrors(t *testing.T) {
+	for name, test := range map[string]struct {
+		yaml   string
+		errStr string
+	}{
+		"min count too large": {
+			yaml:   "count: 5\nmin_count: 6",
+			errStr: "min_count must be larger than zero",
+		},
+		"bad decrease factor": {
+			yaml:   "decrease_factor: 1.5",
+			errStr: "decrease_factor must be between 0 and 1",
+		},
+		"zero interval": {
+			yaml:   "interval: 0s",
+			errStr: "interval must be larger than zero",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf, err := adaptiveRateLimitSpec().ParseYAML(test.yaml, nil)
+			require.NoError(t, err)
+			_, err = newAdaptiveRateLimitFromConfig(conf)
+			require.ErrorContains(t, err, test.errStr)
+		})
+	}
+}
+
+func TestRateLimitFeedbackProcessor(t *testing.T) {
+	rl, _ := newAdaptiveForTest(t, `
+count: 10
+interval: 1s
+`)
+	mgr := service.MockResources(service.MockResourcesOptAddRateLimit("api", func(context.Context) (time.Duration, error) {
+		return 0, nil
+	}))
+	registerAdaptiveRateLimit(mgr, "api", rl)
+
+	conf, err := rateLimitFeedbackProcessorSpec().ParseYAML(`resource: api`, nil)
+	require.NoError(t, err)
+	p, err := newRateLimitFeedbackFromConfig(conf, mgr)
+	require.NoError(t, err)
+
+	ok := service.NewMessage([]byte("ok"))
+	ok.MetaSetMut("http_status_code", 200)
+
+	failed := service.NewMessage([]byte("failed"))
+	failed.MetaSetMut("http_status_code", 500)
+	failed.SetError(errors.New("nope"))
+
+	throttled := service.NewMessage([]byte("throttled"))
+	throttled.MetaSetMut("http_status_code", 429)
+	throttled.MetaSetMut("retry-after", "2")
+
+	for _, msg := range []*service.Message{ok, failed, throttled} {
+		out, err := p.Process(context.Background(), msg)
+		require.NoError(t, err)
+		require.Len(t, out, 1)
+		assert.Same(t, msg, out[0])
+	}
+
+	assert.Equal(t, 1, rl.successes)
+	assert.Equal(t, 1, rl.failures)
+	assert.True(t, rl.throttled)
+	assert.Equal(t, 5.0, rl.rate)
+	assert.Equal(t, rl.nowFn().Add(2*time.Second), rl.pausedUntil)
+
+	require.NoError(t, rl.Close(context.Background()))
+	_, err = p.Process(context.Background(), ok)
+	require.ErrorContains(t, err, "is not an adaptive rate limit")
+}
+
+func TestRateLimitFeedbackMissingResource(t *testing.T) {
+	conf, err := rateLimitFeedbackProcessorSpec().ParseYAML(`resource: nope`, nil)
+	require.NoError(t, err)
+	_, err = newRateLimitFeedbackFromConfig(conf, service.MockResources())
+	require.ErrorContains(t, err, "rate limit resource 'nope' was not found")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for input, exp := range map[string]time.Duration{
+		"":      0,
+		"3":     3 * time.Second,
+		"0.5":   500 * time.Millisecond,
+		"250ms": 250 * time.Millisecond,
+		now.Add(time.Minute).Format(http.TimeFormat): time.Minute,
+	} {
+		d, err := parseRetryAfter(input, now)
+		require.NoError(t, err, input)
+		assert.Equal(t, exp, d, input)
+	}
+
+	_, err := parseRetryAfter("soon", now)
+	require.ErrorContains(t, err, "failed to parse retry delay 'soon'")
+}
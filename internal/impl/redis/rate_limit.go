@@ -25,9 +25,15 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
+const (
+	rlAlgorithmFixedWindow = "fixed_window"
+	rlAlgorithmTokenBucket = "token_bucket"
+)
+
 func redisRatelimitConfig() *service.ConfigSpec {
 	spec := service.NewConfigSpec().
-		Summary(`A rate limit implementation using Redis. It works by using a simple token bucket algorithm to limit the number of requests to a given count within a given time period. The rate limit is shared across all instances of Redpanda Connect that use the same Redis instance, which must all have a consistent count and interval.`).
+		Summary(`A rate limit implementation using Redis. It limits the number of requests to a given count within a given time period. The rate limit is shared across all instances of Redpanda Connect that use the same Redis instance, which must all have a consistent count, interval and algorithm.`).
+		Description(`All state is modified atomically by Lua scripts executed within Redis, which makes this rate limit suitable for horizontally scaled pipelines that must collectively respect the quota of a third party API.`).
 		Version("4.12.0")
 
 	for _, f := range clientFields() {
@@ -41,7 +47,15 @@ func redisRatelimitConfig() *service.ConfigSpec {
 			Description("The time window to limit requests by.").
 			Default("1s")).
 		Field(service.NewStringField("key").
-			Description("The key to use for the rate limit."))
+			Description("The key to use for the rate limit.")).
+		Field(service.NewStringAnnotatedEnumField("algorithm", map[string]string{
+			rlAlgorithmFixedWindow: "Counts requests within a window that starts with the first request and lasts for the interval, after which the count is reset. Bursts of up to twice the count are possible across the boundary of two windows.",
+			rlAlgorithmTokenBucket: "A bucket holding up to `count` tokens is refilled continuously at a rate of `count` tokens per interval, and each request takes a token. This smooths requests over time whilst still allowing bursts of up to `count` requests after a quiet period. Refills are timed using the clock of the Redis server, which requires Redis 3.2 or later.",
+		}).
+			Description("The algorithm used to limit requests.").
+			Default(rlAlgorithmFixedWindow).
+			Version("4.48.0").
+			Advanced())
 
 	return spec
 }
@@ -90,16 +104,42 @@ func newRedisRatelimitFromConfig(conf *service.ParsedConfig) (*redisRatelimit, e
 		return nil, err
 	}
 
+	algorithm, err := conf.FieldString("algorithm")
+	if err != nil {
+		return nil, err
+	}
+
 	if count <= 0 {
 		return nil, errors.New("count must be larger than zero")
 	}
 
+	var accessScript *redis.Script
+	switch algorithm {
+	case rlAlgorithmFixedWindow:
+		accessScript = fixedWindowScript
+	case rlAlgorithmTokenBucket:
+		if interval <= 0 {
+			return nil, errors.New("interval must be larger than zero")
+		}
+		accessScript = tokenBucketScript
+	default:
+		return nil, fmt.Errorf("algorithm not recognised: %v", algorithm)
+	}
+
 	return &redisRatelimit{
-		size:   count,
-		period: interval,
-		client: client,
-		key:    key,
-		accessScript: redis.NewScript(`
+		size:         count,
+		period:       interval,
+		client:       client,
+		key:          key,
+		accessScript: accessScript,
+	}, nil
+}
+
+// Both scripts take the count and the interval in milliseconds as arguments
+// and return the number of milliseconds to wait before the next attempt, or
+// zero if the request may proceed.
+var (
+	fixedWindowScript = redis.NewScript(`
 local current = redis.call("INCR",KEYS[1])
 
 if current == 1 then
@@ -111,9 +151,44 @@ if current > tonumber(ARGV[1]) then
 end
 
 return 0
-`),
-	}, nil
-}
+`)
+
+	// The bucket is stored as a hash of the remaining tokens and the time they
+	// were last refilled. The time is taken from Redis itself so that the
+	// clocks of instances sharing the bucket are irrelevant, which requires
+	// effects replication on versions of Redis prior to 5.
+	tokenBucketScript = redis.NewScript(`
+redis.replicate_commands()
+
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * capacity / interval)
+
+local wait = 0
+if tokens < 1 then
+	wait = math.ceil((1 - tokens) * interval / capacity)
+else
+	tokens = tokens - 1
+end
+
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], interval)
+
+return wait
+`)
+)
 
 //------------------------------------------------------------------------------
 
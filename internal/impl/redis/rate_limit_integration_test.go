@@ -76,6 +76,10 @@ func TestIntegrationRedisRateLimit(t *testing.T) {
 	t.Run("testRedisRateLimitRefresh", func(t *testing.T) {
 		testRedisRateLimitRefresh(t, urlStr)
 	})
+
+	t.Run("testRedisRateLimitTokenBucket", func(t *testing.T) {
+		testRedisRateLimitTokenBucket(t, urlStr)
+	})
 }
 
 func testRedisRateLimitBasic(t *testing.T, url string) {
@@ -165,3 +169,48 @@ url: `+url, nil)
 		t.Errorf("Period beyond interval: %v", period)
 	}
 }
+
+func testRedisRateLimitTokenBucket(t *testing.T, url string) {
+	conf, err := redisRatelimitConfig().ParseYAML(`
+key: rate_limit_token_bucket
+count: 10
+interval: 1s
+algorithm: token_bucket
+url: `+url, nil)
+	require.NoError(t, err)
+
+	// Two limiters sharing the same key behave as two instances of Redpanda
+	// Connect sharing a bucket.
+	rlA, err := newRedisRatelimitFromConfig(conf)
+	require.NoError(t, err)
+	rlB, err := newRedisRatelimitFromConfig(conf)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		period, err := rlA.Access(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), period)
+
+		period, err = rlB.Access(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), period)
+	}
+
+	// A single token is refilled every 100ms.
+	period, err := rlA.Access(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, period, time.Duration(0))
+	assert.LessOrEqual(t, period, 100*time.Millisecond)
+
+	<-time.After(period + 10*time.Millisecond)
+
+	period, err = rlB.Access(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), period)
+
+	period, err = rlA.Access(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, period, time.Duration(0))
+}
@@ -45,3 +45,25 @@ key: asdf`, nil)
 	_, err = redisRatelimitConfig().ParseYAML(`url: redis://localhost:6379`, nil)
 	require.Error(t, err)
 }
+
+func TestRedisRateLimitTokenBucketConfErrors(t *testing.T) {
+	conf, err := redisRatelimitConfig().ParseYAML(`
+url: redis://localhost:6379
+interval: 0s
+algorithm: token_bucket
+key: asdf`, nil)
+	require.NoError(t, err)
+
+	_, err = newRedisRatelimitFromConfig(conf)
+	require.ErrorContains(t, err, "interval must be larger than zero")
+
+	conf, err = redisRatelimitConfig().ParseYAML(`
+url: redis://localhost:6379
+algorithm: token_bucket
+key: asdf`, nil)
+	require.NoError(t, err)
+
+	rl, err := newRedisRatelimitFromConfig(conf)
+	require.NoError(t, err)
+	require.Equal(t, tokenBucketScript, rl.accessScript)
+}
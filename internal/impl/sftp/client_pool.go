@@ -65,6 +65,18 @@ func (c *clientPool) Remove(path string) error {
 	})
 }
 
+func (c *clientPool) MkdirAll(path string) error {
+	return clientPoolDo(c, func(client *sftp.Client) error {
+		return client.MkdirAll(path)
+	})
+}
+
+func (c *clientPool) Rename(oldPath, newPath string) error {
+	return clientPoolDo(c, func(client *sftp.Client) error {
+		return client.Rename(oldPath, newPath)
+	})
+}
+
 func (c *clientPool) Close() error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"sync"
 	"time"
 
@@ -34,6 +35,7 @@ const (
 	siFieldCredentials         = "credentials"
 	siFieldPaths               = "paths"
 	siFieldDeleteOnFinish      = "delete_on_finish"
+	siFieldMoveOnFinish        = "move_on_finish"
 	siFieldWatcher             = "watcher"
 	siFieldWatcherEnabled      = "enabled"
 	siFieldWatcherMinimumAge   = "minimum_age"
@@ -70,6 +72,15 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 				Description("Whether to delete files from the server once they are processed.").
 				Advanced().
 				Default(false),
+			service.NewInterpolatedStringField(siFieldMoveOnFinish).
+				Description("An optional path to move files to once they are processed, where any missing directories of the path are created. This field is interpolated with a message that only contains the metadata field `sftp_path`, and cannot be combined with `delete_on_finish`. Files should be moved to a path that isn't matched by `paths`, otherwise they may be consumed again.").
+				Examples(
+					`/archive/${! @sftp_path.filepath_split().index(-1) }`,
+					`${! @sftp_path.filepath_split().index(0) }done/${! @sftp_path.filepath_split().index(-1) }`,
+				).
+				Version("4.48.0").
+				Advanced().
+				Optional(),
 			service.NewObjectField(siFieldWatcher,
 				service.NewBoolField(siFieldWatcherEnabled).
 					Description("Whether file watching is enabled.").
@@ -87,7 +98,8 @@ You can access these metadata fields using xref:configuration:interpolation.adoc
 					Default(""),
 			).Description("An experimental mode whereby the input will periodically scan the target paths for new files and consume them, when all files are consumed the input will continue polling for new files.").
 				Version("3.42.0"),
-		)
+		).
+		LintRule(`root = if this.delete_on_finish.or(false) && this.move_on_finish.or("") != "" { "move_on_finish cannot be combined with delete_on_finish" }`)
 }
 
 func init() {
@@ -115,6 +127,7 @@ type sftpReader struct {
 	creds          credentials
 	scannerCtor    codec.DeprecatedFallbackCodec
 	deleteOnFinish bool
+	moveOnFinish   *service.InterpolatedString
 
 	watcherEnabled      bool
 	watcherCache        string
@@ -151,6 +164,14 @@ func newSFTPReaderFromParsed(conf *service.ParsedConfig, mgr *service.Resources)
 	if s.deleteOnFinish, err = conf.FieldBool(siFieldDeleteOnFinish); err != nil {
 		return
 	}
+	if conf.Contains(siFieldMoveOnFinish) {
+		if s.deleteOnFinish {
+			return nil, fmt.Errorf("%v cannot be combined with %v", siFieldMoveOnFinish, siFieldDeleteOnFinish)
+		}
+		if s.moveOnFinish, err = conf.FieldInterpolatedString(siFieldMoveOnFinish); err != nil {
+			return
+		}
+	}
 
 	{
 		wConf := conf.Namespace(siFieldWatcher)
@@ -307,11 +328,30 @@ func (s *sftpReader) newCodecAckFn(path string) service.AckFunc {
 				return fmt.Errorf("remove %v: %w", path, err)
 			}
 		}
+		if s.moveOnFinish != nil {
+			if err := s.moveFile(path); err != nil {
+				return fmt.Errorf("move %v: %w", path, err)
+			}
+		}
 
 		return nil
 	}
 }
 
+func (s *sftpReader) moveFile(filePath string) error {
+	msg := service.NewMessage(nil)
+	msg.MetaSetMut("sftp_path", filePath)
+
+	dest, err := s.moveOnFinish.TryString(msg)
+	if err != nil {
+		return fmt.Errorf("destination interpolation: %w", err)
+	}
+	if err := s.client.MkdirAll(path.Dir(dest)); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	return s.client.Rename(filePath, dest)
+}
+
 func (s *sftpReader) Close(ctx context.Context) error {
 	s.stateLock.Lock()
 	defer s.stateLock.Unlock()
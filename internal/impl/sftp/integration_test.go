@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"strings"
@@ -189,6 +190,126 @@ cache_resources:
 	}, time.Second*10, time.Millisecond*100)
 }
 
+func TestIntegrationSFTPMoveOnFinish(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	resource := setupDockerPool(t)
+
+	client, err := getClient(resource)
+	require.NoError(t, err)
+
+	writeSFTPFile(t, client, "/upload/1.txt", "data-1")
+	writeSFTPFile(t, client, "/upload/2.txt", "data-2")
+
+	config := `
+output:
+  drop: {}
+
+input:
+  sftp:
+    address: localhost:$PORT
+    paths:
+      - /upload/*.txt
+    credentials:
+      username: foo
+      password: pass
+    move_on_finish: /upload/done/${! @sftp_path.filepath_split().index(-1) }
+    watcher:
+      enabled: true
+      poll_interval: 100ms
+      cache: files_memory
+
+cache_resources:
+  - label: files_memory
+    memory:
+      default_ttl: 900s
+`
+	config = strings.NewReplacer(
+		"$PORT", resource.GetPort("22/tcp"),
+	).Replace(config)
+
+	builder := service.NewStreamBuilder()
+	require.NoError(t, builder.SetYAML(config))
+	stream, err := builder.Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- stream.Run(ctx) }()
+	defer func() {
+		cancel()
+		err := <-runErr
+		if err != context.Canceled {
+			require.NoError(t, err, "stream.Run() failed")
+		}
+	}()
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		files, err := client.Glob("/upload/*.txt")
+		assert.NoError(c, err)
+		assert.Empty(c, files)
+
+		files, err = client.Glob("/upload/done/*.txt")
+		assert.NoError(c, err)
+		assert.ElementsMatch(c, []string{"/upload/done/1.txt", "/upload/done/2.txt"}, files)
+	}, time.Second*10, time.Millisecond*100)
+}
+
+func TestIntegrationSFTPAtomicRotation(t *testing.T) {
+	integration.CheckSkip(t)
+	t.Parallel()
+
+	resource := setupDockerPool(t)
+
+	client, err := getClient(resource)
+	require.NoError(t, err)
+
+	config := `
+input:
+  generate:
+    count: 6
+    interval: ""
+    mapping: 'root = "hello world"'
+
+output:
+  sftp:
+    address: localhost:$PORT
+    path: /upload/rotated/${! timestamp_unix_nano() }.txt
+    codec: lines
+    atomic_writes: true
+    rotation:
+      max_size: 24
+    credentials:
+      username: foo
+      password: pass
+`
+	config = strings.NewReplacer(
+		"$PORT", resource.GetPort("22/tcp"),
+	).Replace(config)
+
+	builder := service.NewStreamBuilder()
+	require.NoError(t, builder.SetYAML(config))
+	stream, err := builder.Build()
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Run(context.Background()))
+
+	files, err := client.ReadDir("/upload/rotated")
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+	for _, f := range files {
+		assert.False(t, strings.HasPrefix(f.Name(), "."), "temporary file %v was not renamed", f.Name())
+
+		file, err := client.Open("/upload/rotated/" + f.Name())
+		require.NoError(t, err)
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		assert.Equal(t, "hello world\nhello world\n", string(data))
+	}
+}
+
 func setupDockerPool(t *testing.T) *dockertest.Resource {
 	t.Helper()
 
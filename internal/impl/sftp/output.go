@@ -22,8 +22,10 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/pkg/sftp"
+	"github.com/rs/xid"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
@@ -32,6 +34,10 @@ const (
 	soFieldAddress     = "address"
 	soFieldCredentials = "credentials"
 	soFieldPath        = "path"
+	soFieldAtomic      = "atomic_writes"
+	soFieldRotation    = "rotation"
+	soFieldRotationMax = "max_size"
+	soFieldRotationAge = "max_age"
 )
 
 func sftpOutputSpec() *service.ConfigSpec {
@@ -40,7 +46,17 @@ func sftpOutputSpec() *service.ConfigSpec {
 		Categories("Network").
 		Version("3.39.0").
 		Summary(`Writes files to an SFTP server.`).
-		Description(`In order to have a different path for each object you should use function interpolations described xref:configuration:interpolation.adoc#bloblang-queries[here].`+service.OutputPerformanceDocs(true, false)).
+		Description(`In order to have a different path for each object you should use function interpolations described xref:configuration:interpolation.adoc#bloblang-queries[here].
+
+== Atomic writes
+
+When `+"`atomic_writes`"+` is enabled each file is written to a hidden temporary file within the same directory, which is renamed to the target path once the file is complete. This ensures that other clients polling the directory never observe partially written files. Files written with an appending codec are complete once they are rotated, the path of messages changes or the output is closed, and the renamed file replaces any existing file at the path rather than being appended to it.
+
+Messages written with an appending codec are acknowledged once they are written to the temporary file. When a completed file cannot be renamed its temporary file is left in place and the rename is attempted again when the output is closed, at which point any files that still cannot be renamed are reported as an error. Messages written without an appending codec are only acknowledged once their file has been renamed.
+
+== Rotation
+
+Files written with an appending codec can be rotated once they reach a size or age with the `+"`rotation`"+` fields. When rotation is enabled the `+"`path`"+` is only evaluated for the first message of each file, and therefore it should contain an interpolation that is unique to each file, such as `+"`${! timestamp_unix_nano() }`"+`. The age of a file is checked when messages are written to it.`+service.OutputPerformanceDocs(true, false)).
 		Fields(
 			service.NewStringField(soFieldAddress).
 				Description("The address of the server to connect to."),
@@ -58,8 +74,26 @@ func sftpOutputSpec() *service.ConfigSpec {
 				Default("all-bytes"),
 			service.NewObjectField(soFieldCredentials, credentialsFields()...).
 				Description("The credentials to use to log into the target server."),
+			service.NewBoolField(soFieldAtomic).
+				Description("Whether to write files to a temporary path and rename them to their target path once they are complete. For more information refer to <<atomic-writes, atomic writes>>.").
+				Version("4.48.0").
+				Advanced().
+				Default(false),
+			service.NewObjectField(soFieldRotation,
+				service.NewIntField(soFieldRotationMax).
+					Description("The size in bytes a file can reach before it is rotated, where zero means no limit.").
+					Default(0),
+				service.NewDurationField(soFieldRotationAge).
+					Description("The period of time since a file was created before it is rotated, where zero means no limit.").
+					Examples("1h", "24h").
+					Default("0s"),
+			).
+				Description("Rotate files written with an appending codec once they reach a size or age. For more information refer to <<rotation, rotation>>.").
+				Version("4.48.0").
+				Advanced(),
 			service.NewOutputMaxInFlightField(),
-		)
+		).
+		LintRule(`root = if this.codec.or("all-bytes") == "all-bytes" && (this.rotation.max_size.or(0) > 0 || this.rotation.max_age.or("0s") != "0s") { "rotation requires an appending codec" }`)
 }
 
 func init() {
@@ -88,11 +122,25 @@ type sftpWriter struct {
 	path       *service.InterpolatedString
 	suffixFn   codecSuffixFn
 	appendMode bool
+	atomic     bool
+	maxSize    int64
+	maxAge     time.Duration
+
+	handleMut     sync.Mutex
+	client        *sftp.Client
+	handlePath    string
+	handleTmpPath string
+	handleSize    int64
+	handleOpened  time.Time
+	handle        io.WriteCloser
+
+	// Temporary files of appended files that could not be renamed to their
+	// target path, which are retried and reported when closing.
+	unfinished []pendingRename
+}
 
-	handleMut  sync.Mutex
-	client     *sftp.Client
-	handlePath string
-	handle     io.WriteCloser
+type pendingRename struct {
+	from, to string
 }
 
 func newWriterFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (s *sftpWriter, err error) {
@@ -118,10 +166,30 @@ func newWriterFromParsed(conf *service.ParsedConfig, mgr *service.Resources) (s
 	if s.creds, err = credentialsFromParsed(conf.Namespace(soFieldCredentials)); err != nil {
 		return
 	}
+	if s.atomic, err = conf.FieldBool(soFieldAtomic); err != nil {
+		return
+	}
+
+	rConf := conf.Namespace(soFieldRotation)
+	maxSize, err := rConf.FieldInt(soFieldRotationMax)
+	if err != nil {
+		return nil, err
+	}
+	s.maxSize = int64(maxSize)
+	if s.maxAge, err = rConf.FieldDuration(soFieldRotationAge); err != nil {
+		return nil, err
+	}
+	if s.rotating() && !s.appendMode {
+		return nil, errors.New("rotation requires an appending codec")
+	}
 
 	return s, nil
 }
 
+func (s *sftpWriter) rotating() bool {
+	return s.maxSize > 0 || s.maxAge > 0
+}
+
 func (s *sftpWriter) Connect(ctx context.Context) (err error) {
 	s.handleMut.Lock()
 	defer s.handleMut.Unlock()
@@ -145,14 +213,73 @@ func (s *sftpWriter) writeTo(wtr io.Writer, p *service.Message) error {
 	if _, err := wtr.Write(mBytes); err != nil {
 		return err
 	}
+	s.handleSize += int64(len(mBytes))
 	if addSuffix {
 		if _, err := wtr.Write(suffix); err != nil {
 			return err
 		}
+		s.handleSize += int64(len(suffix))
 	}
 	return nil
 }
 
+// closeHandle closes the currently open file and, when writing atomically,
+// renames it to its target path. Errors are only returned when writing
+// atomically, otherwise the file has already been written in place and a
+// failure to close it is logged.
+func (s *sftpWriter) closeHandle() error {
+	if s.handle == nil {
+		return nil
+	}
+
+	handle, path, tmpPath := s.handle, s.handlePath, s.handleTmpPath
+	s.handle = nil
+	s.handlePath = ""
+	s.handleTmpPath = ""
+	s.handleSize = 0
+
+	if err := handle.Close(); err != nil {
+		if tmpPath == "" {
+			s.log.With("error", err).Error("Failed to close written file")
+			return nil
+		}
+		return fmt.Errorf("close %v: %w", tmpPath, err)
+	}
+	if tmpPath == "" {
+		return nil
+	}
+	return s.rename(tmpPath, path)
+}
+
+// finishAppended closes the currently open file of an appending codec, the
+// messages of which have already been acknowledged. When writing atomically and
+// the file cannot be renamed to its target path the temporary file is left in
+// place, and the rename is attempted again when the output is closed.
+func (s *sftpWriter) finishAppended() {
+	pending := pendingRename{from: s.handleTmpPath, to: s.handlePath}
+	if err := s.closeHandle(); err != nil {
+		s.log.With("error", err).Errorf("Failed to finish written file, leaving temporary file %v in place", pending.from)
+		s.unfinished = append(s.unfinished, pending)
+	}
+}
+
+func (s *sftpWriter) rename(from, to string) error {
+	// The POSIX rename extension replaces any existing file atomically, but is
+	// not supported by all servers.
+	if err := s.client.PosixRename(from, to); err == nil {
+		return nil
+	}
+	if err := s.client.Rename(from, to); err != nil {
+		return fmt.Errorf("rename %v to %v: %w", from, to, err)
+	}
+	return nil
+}
+
+func (s *sftpWriter) shouldRotate() bool {
+	return (s.maxSize > 0 && s.handleSize >= s.maxSize) ||
+		(s.maxAge > 0 && time.Since(s.handleOpened) >= s.maxAge)
+}
+
 func (s *sftpWriter) Write(ctx context.Context, msg *service.Message) error {
 	s.handleMut.Lock()
 	defer s.handleMut.Unlock()
@@ -161,6 +288,13 @@ func (s *sftpWriter) Write(ctx context.Context, msg *service.Message) error {
 		return service.ErrNotConnected
 	}
 
+	if s.handle != nil && s.rotating() {
+		if !s.shouldRotate() {
+			return s.writeTo(s.handle, msg)
+		}
+		s.finishAppended()
+	}
+
 	path, err := s.path.TryString(msg)
 	if err != nil {
 		return fmt.Errorf("path interpolation error: %w", err)
@@ -170,16 +304,10 @@ func (s *sftpWriter) Write(ctx context.Context, msg *service.Message) error {
 		// TODO: Detect underlying connection failure here and drop client.
 		return s.writeTo(s.handle, msg)
 	}
-	if s.handle != nil {
-		if err := s.handle.Close(); err != nil {
-			s.log.With("error", err).Error("Failed to close written file")
-		}
-		s.handle = nil
-		s.handlePath = ""
-	}
+	s.finishAppended()
 
 	flag := os.O_CREATE | os.O_WRONLY
-	if s.appendMode {
+	if s.appendMode && !s.atomic {
 		flag |= os.O_APPEND
 	} else {
 		flag |= os.O_TRUNC
@@ -192,7 +320,12 @@ func (s *sftpWriter) Write(ctx context.Context, msg *service.Message) error {
 		return err
 	}
 
-	handle, err := s.client.OpenFile(path, flag)
+	writePath := path
+	if s.atomic {
+		writePath = filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+"."+xid.New().String()+".tmp")
+	}
+
+	handle, err := s.client.OpenFile(writePath, flag)
 	if err != nil {
 		if errors.Is(err, sftp.ErrSshFxConnectionLost) {
 			return service.ErrNotConnected
@@ -200,17 +333,33 @@ func (s *sftpWriter) Write(ctx context.Context, msg *service.Message) error {
 		return err
 	}
 
+	s.handle = handle
+	s.handlePath = path
+	if s.atomic {
+		s.handleTmpPath = writePath
+	}
+	s.handleOpened = time.Now()
+
 	if err := s.writeTo(handle, msg); err != nil {
 		_ = handle.Close()
+		if s.atomic {
+			_ = s.client.Remove(writePath)
+		}
+		s.handle = nil
+		s.handlePath = ""
+		s.handleTmpPath = ""
+		s.handleSize = 0
 		return err
 	}
 
-	if s.appendMode {
-		s.handle = handle
-		s.handlePath = path
-	} else {
-		if err := handle.Close(); err != nil {
-			s.log.With("error", err).Error("Failed to close written file")
+	if !s.appendMode {
+		// The message is only acknowledged once the file has been renamed,
+		// otherwise it is written again to a new temporary file.
+		if err := s.closeHandle(); err != nil {
+			if s.atomic {
+				_ = s.client.Remove(writePath)
+			}
+			return err
 		}
 	}
 	return nil
@@ -220,17 +369,24 @@ func (s *sftpWriter) Close(ctx context.Context) error {
 	s.handleMut.Lock()
 	defer s.handleMut.Unlock()
 
-	if s.handle != nil {
-		if err := s.handle.Close(); err != nil {
-			s.log.With("error", err).Error("Failed to close written file")
+	s.finishAppended()
+
+	var err error
+	for _, p := range s.unfinished {
+		if rErr := s.rename(p.from, p.to); rErr != nil {
+			err = errors.Join(err, rErr)
 		}
-		s.handle = nil
 	}
+	s.unfinished = nil
+
 	if s.client != nil {
 		if err := s.client.Close(); err != nil {
 			s.log.With("error", err).Error("Failed to close client")
 		}
 		s.client = nil
 	}
+	if err != nil {
+		return fmt.Errorf("failed to finish files with acknowledged messages, their temporary files have been left in place: %w", err)
+	}
 	return nil
 }
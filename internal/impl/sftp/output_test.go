@@ -0,0 +1,167 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sftp
+
+import (
+	"context"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+// memSFTPDialer returns a function that connects clients to an in-memory
+// server, where all clients share the same files.
+func memSFTPDialer(t *testing.T) func() *sftp.Client {
+	handlers := sftp.InMemHandler()
+	return func() *sftp.Client {
+		t.Helper()
+
+		serverConn, clientConn := net.Pipe()
+		server := sftp.NewRequestServer(serverConn, handlers)
+		go func() {
+			_ = server.Serve()
+		}()
+		t.Cleanup(func() {
+			_ = server.Close()
+		})
+
+		client, err := sftp.NewClientPipe(clientConn, clientConn)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = client.Close()
+		})
+		return client
+	}
+}
+
+func testAtomicWriter(t *testing.T, client *sftp.Client, codec string) *sftpWriter {
+	t.Helper()
+
+	pathStr, err := service.NewInterpolatedString(`/out/${! @path }`)
+	require.NoError(t, err)
+
+	w := &sftpWriter{
+		log:    service.MockResources().Logger(),
+		mgr:    service.MockResources(),
+		path:   pathStr,
+		atomic: true,
+		client: client,
+	}
+	w.suffixFn, w.appendMode, err = codecGetWriter(codec)
+	require.NoError(t, err)
+	return w
+}
+
+func testPathMessage(content, p string) *service.Message {
+	msg := service.NewMessage([]byte(content))
+	msg.MetaSetMut("path", p)
+	return msg
+}
+
+func listFiles(t *testing.T, client *sftp.Client) (names []string) {
+	t.Helper()
+
+	infos, err := client.ReadDir("/out")
+	require.NoError(t, err)
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+	return
+}
+
+func readFile(t *testing.T, client *sftp.Client, p string) string {
+	t.Helper()
+
+	f, err := client.Open(p)
+	require.NoError(t, err)
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestSFTPOutputAtomicAppendRenameRetriedOnClose(t *testing.T) {
+	ctx := context.Background()
+	dial := memSFTPDialer(t)
+	client := dial()
+	w := testAtomicWriter(t, dial(), "lines")
+
+	// A directory at the target path prevents the file from being renamed.
+	require.NoError(t, client.MkdirAll("/out/a.txt"))
+
+	require.NoError(t, w.Write(ctx, testPathMessage("foo", "a.txt")))
+	require.NoError(t, w.Write(ctx, testPathMessage("bar", "a.txt")))
+	require.NoError(t, w.Write(ctx, testPathMessage("baz", "b.txt")))
+
+	require.Len(t, w.unfinished, 1)
+	assert.Equal(t, "/out/a.txt", w.unfinished[0].to)
+	assert.Contains(t, listFiles(t, client), path.Base(w.unfinished[0].from))
+
+	require.NoError(t, client.RemoveDirectory("/out/a.txt"))
+	require.NoError(t, w.Close(ctx))
+
+	assert.Equal(t, []string{"a.txt", "b.txt"}, listFiles(t, client))
+	assert.Equal(t, "foo\nbar\n", readFile(t, client, "/out/a.txt"))
+	assert.Equal(t, "baz\n", readFile(t, client, "/out/b.txt"))
+}
+
+func TestSFTPOutputAtomicAppendRenameFailureReportedOnClose(t *testing.T) {
+	ctx := context.Background()
+	dial := memSFTPDialer(t)
+	client := dial()
+	w := testAtomicWriter(t, dial(), "lines")
+
+	require.NoError(t, client.MkdirAll("/out/a.txt"))
+
+	require.NoError(t, w.Write(ctx, testPathMessage("foo", "a.txt")))
+	require.NoError(t, w.Write(ctx, testPathMessage("bar", "b.txt")))
+	require.ErrorContains(t, w.Close(ctx), "failed to finish files with acknowledged messages")
+
+	files := listFiles(t, client)
+	require.Len(t, files, 2)
+	assert.Equal(t, "b.txt", files[1])
+	assert.Equal(t, "foo\n", readFile(t, client, "/out/"+files[0]))
+}
+
+func TestSFTPOutputAtomicRenameFailureNotAcked(t *testing.T) {
+	ctx := context.Background()
+	dial := memSFTPDialer(t)
+	client := dial()
+	w := testAtomicWriter(t, dial(), "all-bytes")
+
+	require.NoError(t, client.MkdirAll("/out/a.txt"))
+
+	require.Error(t, w.Write(ctx, testPathMessage("foo", "a.txt")))
+	assert.Empty(t, listFiles(t, client))
+
+	require.NoError(t, client.RemoveDirectory("/out/a.txt"))
+	require.NoError(t, w.Write(ctx, testPathMessage("foo", "a.txt")))
+	require.NoError(t, w.Close(ctx))
+
+	assert.Equal(t, []string{"a.txt"}, listFiles(t, client))
+	assert.Equal(t, "foo", readFile(t, client, "/out/a.txt"))
+}
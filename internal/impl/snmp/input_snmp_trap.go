@@ -0,0 +1,488 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snmp
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	stiFieldAddress   = "address"
+	stiFieldCommunity = "community"
+	stiFieldUsers     = "users"
+	stiFieldMIBPaths  = "mib_paths"
+
+	stiFieldUserName           = "username"
+	stiFieldUserAuthProtocol   = "auth_protocol"
+	stiFieldUserAuthPassphrase = "auth_passphrase"
+	stiFieldUserPrivProtocol   = "priv_protocol"
+	stiFieldUserPrivPassphrase = "priv_passphrase"
+)
+
+var authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+	"none":   gosnmp.NoAuth,
+	"md5":    gosnmp.MD5,
+	"sha":    gosnmp.SHA,
+	"sha224": gosnmp.SHA224,
+	"sha256": gosnmp.SHA256,
+	"sha384": gosnmp.SHA384,
+	"sha512": gosnmp.SHA512,
+}
+
+var privProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+	"none":    gosnmp.NoPriv,
+	"des":     gosnmp.DES,
+	"aes":     gosnmp.AES,
+	"aes192":  gosnmp.AES192,
+	"aes256":  gosnmp.AES256,
+	"aes192c": gosnmp.AES192C,
+	"aes256c": gosnmp.AES256C,
+}
+
+func snmpTrapInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.48.0").
+		Summary("Receives SNMP traps and informs over UDP.").
+		Description(`
+Each trap or inform received becomes a JSON message describing its variable bindings:
+
+`+"```json"+`
+{
+  "version": "v2c",
+  "pdu_type": "trap",
+  "community": "public",
+  "trap_oid": ".1.3.6.1.6.3.1.1.5.3",
+  "trap_name": "IF-MIB::linkDown",
+  "variables": [
+    {
+      "oid": ".1.3.6.1.2.1.2.2.1.1.3",
+      "name": "IF-MIB::ifIndex.3",
+      "type": "Integer",
+      "value": 3
+    }
+  ]
+}
+`+"```"+`
+
+SNMP v2c traps are authenticated with the `+"`community`"+` string, and SNMP v3 traps with the `+"`users`"+` of the user-based security model. SNMP v3 traps from unknown users are rejected, and therefore v3 traps are only received when at least one user is configured. SNMP v1 traps are also received, in which case the fields `+"`enterprise`"+`, `+"`generic_trap`"+` and `+"`specific_trap`"+` are added to messages.
+
+Informs are acknowledged as soon as they are received, and traps are not acknowledged at all, therefore messages that are in flight when Redpanda Connect shuts down are lost.
+
+== MIB files
+
+OIDs are translated into names using the MIB modules found at `+"`mib_paths`"+`. Names are only added for OIDs with a known prefix, in which case the remaining sub-identifiers of the OID, such as the index of a table row, are appended to the name. The nodes of the core SNMPv2-SMI and SNMPv2-MIB modules, as well as the standard traps, are always known.
+
+Only the OID assignments of MIB modules are interpreted, the types and textual conventions they define are ignored.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- snmp_source_address
+- snmp_source_port
+- snmp_version
+`+"```"+`
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+`).
+		Fields(
+			service.NewStringField(stiFieldAddress).
+				Description("The UDP address to listen for traps on.").
+				Default("0.0.0.0:162"),
+			service.NewStringField(stiFieldCommunity).
+				Description("The community string that SNMP v1 and v2c traps must match. When empty traps with any community are received.").
+				Default(""),
+			service.NewObjectListField(stiFieldUsers,
+				service.NewStringField(stiFieldUserName).
+					Description("The name of the user."),
+				service.NewStringEnumField(stiFieldUserAuthProtocol, "none", "md5", "sha", "sha224", "sha256", "sha384", "sha512").
+					Description("The protocol used to authenticate traps from the user.").
+					Default("none"),
+				service.NewStringField(stiFieldUserAuthPassphrase).
+					Description("The passphrase used to authenticate traps from the user.").
+					Secret().
+					Default(""),
+				service.NewStringEnumField(stiFieldUserPrivProtocol, "none", "des", "aes", "aes192", "aes256", "aes192c", "aes256c").
+					Description("The protocol used to decrypt traps from the user.").
+					Default("none"),
+				service.NewStringField(stiFieldUserPrivPassphrase).
+					Description("The passphrase used to decrypt traps from the user.").
+					Secret().
+					Default(""),
+			).
+				Description("The users of the SNMP v3 user-based security model that traps are accepted from.").
+				Default([]any{}),
+			service.NewStringListField(stiFieldMIBPaths).
+				Description("A list of MIB files, or directories containing MIB files, used to translate OIDs into names. For more information refer to <<mib-files, MIB files>>.").
+				Example([]any{"/usr/share/snmp/mibs"}).
+				Default([]any{}),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Network Events", "Receive traps from network devices and write the interfaces that went down to Kafka.", `
+input:
+  snmp_trap:
+    address: 0.0.0.0:1162
+    community: ${SNMP_COMMUNITY}
+    mib_paths: [ /usr/share/snmp/mibs ]
+
+pipeline:
+  processors:
+    - mapping: |
+        root = if this.trap_name != "IF-MIB::linkDown" { deleted() }
+        root.device = @snmp_source_address
+        root.interface = this.variables.filter(v -> v.name.or("").has_prefix("IF-MIB::ifIndex.")).index(0).value
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: interface_down
+`)
+}
+
+func init() {
+	err := service.RegisterInput("snmp_trap", snmpTrapInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			i, err := newSNMPTrapInputFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, i)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type snmpTrapInput struct {
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	address   string
+	community string
+	params    *gosnmp.GoSNMP
+	mibs      *mibTree
+
+	msgChan chan *service.Message
+
+	connMut    sync.Mutex
+	conn       *net.UDPConn
+	listenAddr net.Addr
+}
+
+func newSNMPTrapInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*snmpTrapInput, error) {
+	s := &snmpTrapInput{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+		msgChan: make(chan *service.Message),
+	}
+
+	var err error
+	if s.address, err = conf.FieldString(stiFieldAddress); err != nil {
+		return nil, err
+	}
+	if s.community, err = conf.FieldString(stiFieldCommunity); err != nil {
+		return nil, err
+	}
+
+	// Traps of all versions are decoded with the same parameters, the version
+	// must be v3 in order for the credentials of v3 traps to be checked, and
+	// an empty users table rejects all v3 traps.
+	s.params = &gosnmp.GoSNMP{
+		Version:                     gosnmp.Version3,
+		SecurityModel:               gosnmp.UserSecurityModel,
+		TrapSecurityParametersTable: gosnmp.NewSnmpV3SecurityParametersTable(gosnmp.Logger{}),
+	}
+
+	userConfs, err := conf.FieldObjectList(stiFieldUsers)
+	if err != nil {
+		return nil, err
+	}
+	for _, uConf := range userConfs {
+		usm, err := usmFromParsed(uConf)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.params.TrapSecurityParametersTable.Add(usm.UserName, usm); err != nil {
+			return nil, fmt.Errorf("user %v: %w", usm.UserName, err)
+		}
+	}
+
+	mibPaths, err := conf.FieldStringList(stiFieldMIBPaths)
+	if err != nil {
+		return nil, err
+	}
+	if s.mibs, err = newMIBTree(mibPaths); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func usmFromParsed(conf *service.ParsedConfig) (*gosnmp.UsmSecurityParameters, error) {
+	usm := &gosnmp.UsmSecurityParameters{}
+
+	var err error
+	if usm.UserName, err = conf.FieldString(stiFieldUserName); err != nil {
+		return nil, err
+	}
+
+	authProtocol, err := conf.FieldString(stiFieldUserAuthProtocol)
+	if err != nil {
+		return nil, err
+	}
+	usm.AuthenticationProtocol = authProtocols[authProtocol]
+	if usm.AuthenticationPassphrase, err = conf.FieldString(stiFieldUserAuthPassphrase); err != nil {
+		return nil, err
+	}
+
+	privProtocol, err := conf.FieldString(stiFieldUserPrivProtocol)
+	if err != nil {
+		return nil, err
+	}
+	usm.PrivacyProtocol = privProtocols[privProtocol]
+	if usm.PrivacyPassphrase, err = conf.FieldString(stiFieldUserPrivPassphrase); err != nil {
+		return nil, err
+	}
+
+	if usm.PrivacyProtocol != gosnmp.NoPriv && usm.AuthenticationProtocol == gosnmp.NoAuth {
+		return nil, fmt.Errorf("user %v: a privacy protocol requires an authentication protocol", usm.UserName)
+	}
+	return usm, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (s *snmpTrapInput) Connect(ctx context.Context) error {
+	s.connMut.Lock()
+	defer s.connMut.Unlock()
+	if s.conn != nil {
+		return nil
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", s.address)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	go s.loop(conn)
+	s.log.Infof("Receiving SNMP traps at: %v", conn.LocalAddr())
+	s.conn = conn
+	s.listenAddr = conn.LocalAddr()
+	return nil
+}
+
+func (s *snmpTrapInput) loop(conn *net.UDPConn) {
+	defer s.shutSig.TriggerHasStopped()
+
+	buf := make([]byte, 65535)
+	for {
+		n, remote, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if s.shutSig.IsSoftStopSignalled() || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			s.log.Errorf("Failed to read SNMP trap: %v", err)
+			continue
+		}
+
+		packet, err := s.params.UnmarshalTrap(append([]byte(nil), buf[:n]...), true)
+		if err != nil {
+			s.log.Debugf("Rejected SNMP trap from %v: %v", remote, err)
+			continue
+		}
+		if packet.Version != gosnmp.Version3 && s.community != "" && packet.Community != s.community {
+			s.log.Debugf("Rejected SNMP trap from %v: community does not match", remote)
+			continue
+		}
+
+		msg, err := s.trapToMessage(packet, remote)
+		if err != nil {
+			s.log.Errorf("Failed to decode SNMP trap from %v: %v", remote, err)
+			continue
+		}
+
+		if packet.PDUType == gosnmp.InformRequest {
+			s.respondToInform(conn, packet, remote)
+		}
+
+		select {
+		case s.msgChan <- msg:
+		case <-s.shutSig.SoftStopChan():
+			return
+		}
+	}
+}
+
+func (s *snmpTrapInput) respondToInform(conn *net.UDPConn, packet *gosnmp.SnmpPacket, remote *net.UDPAddr) {
+	packet.PDUType = gosnmp.GetResponse
+	packet.Error = gosnmp.NoError
+	packet.ErrorIndex = 0
+
+	resBytes, err := packet.MarshalMsg()
+	if err != nil {
+		s.log.Errorf("Failed to encode SNMP inform response: %v", err)
+		return
+	}
+	if _, err := conn.WriteToUDP(resBytes, remote); err != nil {
+		s.log.Errorf("Failed to send SNMP inform response to %v: %v", remote, err)
+	}
+}
+
+func snmpVersionString(v gosnmp.SnmpVersion) string {
+	switch v {
+	case gosnmp.Version1:
+		return "v1"
+	case gosnmp.Version2c:
+		return "v2c"
+	case gosnmp.Version3:
+		return "v3"
+	}
+	return "unknown"
+}
+
+// snmpTrapOID is the variable of v2c and v3 traps that identifies the trap.
+const snmpTrapOID = ".1.3.6.1.6.3.1.1.4.1.0"
+
+func (s *snmpTrapInput) trapToMessage(packet *gosnmp.SnmpPacket, remote *net.UDPAddr) (*service.Message, error) {
+	obj := map[string]any{
+		"version": snmpVersionString(packet.Version),
+	}
+	if packet.PDUType == gosnmp.InformRequest {
+		obj["pdu_type"] = "inform"
+	} else {
+		obj["pdu_type"] = "trap"
+	}
+
+	if packet.Version == gosnmp.Version3 {
+		if usm, ok := packet.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok {
+			obj["username"] = usm.UserName
+		}
+		if packet.ContextName != "" {
+			obj["context_name"] = packet.ContextName
+		}
+	} else {
+		obj["community"] = packet.Community
+	}
+
+	if packet.Version == gosnmp.Version1 {
+		obj["enterprise"] = packet.Enterprise
+		obj["agent_address"] = packet.AgentAddress
+		obj["generic_trap"] = packet.GenericTrap
+		obj["specific_trap"] = packet.SpecificTrap
+		obj["uptime"] = packet.Timestamp
+	}
+
+	variables := make([]any, 0, len(packet.Variables))
+	for _, pdu := range packet.Variables {
+		v := map[string]any{
+			"oid":   pdu.Name,
+			"type":  pdu.Type.String(),
+			"value": snmpValue(pdu),
+		}
+		if name := s.mibs.translate(pdu.Name); name != "" {
+			v["name"] = name
+		}
+		if pdu.Name == snmpTrapOID {
+			if trapOID, ok := pdu.Value.(string); ok {
+				obj["trap_oid"] = trapOID
+				if name := s.mibs.translate(trapOID); name != "" {
+					obj["trap_name"] = name
+				}
+			}
+		}
+		variables = append(variables, v)
+	}
+	obj["variables"] = variables
+
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(obj)
+	msg.MetaSetMut("snmp_source_address", remote.IP.String())
+	msg.MetaSetMut("snmp_source_port", strconv.Itoa(remote.Port))
+	msg.MetaSetMut("snmp_version", snmpVersionString(packet.Version))
+	return msg, nil
+}
+
+// snmpValue converts the value of a variable binding into a value that can be
+// serialised as JSON.
+func snmpValue(pdu gosnmp.SnmpPDU) any {
+	switch pdu.Type {
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return nil
+	case gosnmp.Integer:
+		return gosnmp.ToBigInt(pdu.Value).Int64()
+	case gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32:
+		return gosnmp.ToBigInt(pdu.Value).Uint64()
+	}
+	switch v := pdu.Value.(type) {
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return hex.EncodeToString(v)
+	default:
+		return v
+	}
+}
+
+func (s *snmpTrapInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	select {
+	case msg := <-s.msgChan:
+		return msg, func(ctx context.Context, err error) error {
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-s.shutSig.SoftStopChan():
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (s *snmpTrapInput) Close(ctx context.Context) error {
+	s.shutSig.TriggerSoftStop()
+
+	s.connMut.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.connMut.Unlock()
+	if conn == nil {
+		return nil
+	}
+
+	err := conn.Close()
+	select {
+	case <-s.shutSig.HasStoppedChan():
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snmp
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testSNMPTrapInput(t *testing.T, conf string) *snmpTrapInput {
+	t.Helper()
+
+	pConf, err := snmpTrapInputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	s, err := newSNMPTrapInputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Connect(context.Background()))
+	t.Cleanup(func() {
+		require.NoError(t, s.Close(context.Background()))
+	})
+	return s
+}
+
+func sendTestTrap(t *testing.T, s *snmpTrapInput, client *gosnmp.GoSNMP) {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(s.listenAddr.String())
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	client.Target = host
+	client.Port = uint16(portNum)
+	client.Timeout = time.Second
+	require.NoError(t, client.Connect())
+	defer client.Conn.Close()
+
+	_, err = client.SendTrap(gosnmp.SnmpTrap{
+		Variables: []gosnmp.SnmpPDU{
+			{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1234)},
+			{Name: snmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.3"},
+			{Name: ".1.3.6.1.2.1.2.2.1.1.3", Type: gosnmp.Integer, Value: 3},
+			{Name: ".1.3.6.1.2.1.2.2.1.2.3", Type: gosnmp.OctetString, Value: []byte("eth0")},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func readTestTrap(t *testing.T, s *snmpTrapInput) *service.Message {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	msg, ackFn, err := s.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ackFn(ctx, nil))
+	return msg
+}
+
+func TestSNMPTrapInputV2c(t *testing.T) {
+	s := testSNMPTrapInput(t, `
+address: 127.0.0.1:0
+community: secret
+`)
+
+	sendTestTrap(t, s, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "wrong"})
+	sendTestTrap(t, s, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "secret"})
+
+	msg := readTestTrap(t, s)
+
+	v, exists := msg.MetaGet("snmp_version")
+	assert.True(t, exists)
+	assert.Equal(t, "v2c", v)
+	v, exists = msg.MetaGet("snmp_source_address")
+	assert.True(t, exists)
+	assert.Equal(t, "127.0.0.1", v)
+
+	mBytes, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "version": "v2c",
+  "pdu_type": "trap",
+  "community": "secret",
+  "trap_oid": ".1.3.6.1.6.3.1.1.5.3",
+  "trap_name": "IF-MIB::linkDown",
+  "variables": [
+    {"oid": ".1.3.6.1.2.1.1.3.0", "name": "SNMPv2-MIB::sysUpTime.0", "type": "TimeTicks", "value": 1234},
+    {"oid": ".1.3.6.1.6.3.1.1.4.1.0", "name": "SNMPv2-MIB::snmpTrapOID.0", "type": "ObjectIdentifier", "value": ".1.3.6.1.6.3.1.1.5.3"},
+    {"oid": ".1.3.6.1.2.1.2.2.1.1.3", "name": "SNMPv2-SMI::mib-2.2.2.1.1.3", "type": "Integer", "value": 3},
+    {"oid": ".1.3.6.1.2.1.2.2.1.2.3", "name": "SNMPv2-SMI::mib-2.2.2.1.2.3", "type": "OctetString", "value": "eth0"}
+  ]
+}`, string(mBytes))
+}
+
+func TestSNMPTrapInputV3(t *testing.T) {
+	s := testSNMPTrapInput(t, `
+address: 127.0.0.1:0
+users:
+  - username: alice
+    auth_protocol: sha256
+    auth_passphrase: alicepassword
+    priv_protocol: aes
+    priv_passphrase: aliceprivacy
+`)
+
+	newClient := func(user, authPass string) *gosnmp.GoSNMP {
+		return &gosnmp.GoSNMP{
+			Version:       gosnmp.Version3,
+			SecurityModel: gosnmp.UserSecurityModel,
+			MsgFlags:      gosnmp.AuthPriv,
+			SecurityParameters: &gosnmp.UsmSecurityParameters{
+				UserName:                 user,
+				AuthoritativeEngineID:    "8000000001020304",
+				AuthenticationProtocol:   gosnmp.SHA256,
+				AuthenticationPassphrase: authPass,
+				PrivacyProtocol:          gosnmp.AES,
+				PrivacyPassphrase:        "aliceprivacy",
+			},
+		}
+	}
+
+	sendTestTrap(t, s, newClient("mallory", "alicepassword"))
+	sendTestTrap(t, s, newClient("alice", "wrongpassword"))
+	sendTestTrap(t, s, newClient("alice", "alicepassword"))
+
+	msg := readTestTrap(t, s)
+
+	structured, err := msg.AsStructured()
+	require.NoError(t, err)
+
+	obj := structured.(map[string]any)
+	assert.Equal(t, "v3", obj["version"])
+	assert.Equal(t, "alice", obj["username"])
+	assert.Equal(t, "IF-MIB::linkDown", obj["trap_name"])
+	assert.Len(t, obj["variables"], 4)
+}
+
+func TestSNMPTrapInputUserValidation(t *testing.T) {
+	pConf, err := snmpTrapInputSpec().ParseYAML(`
+users:
+  - username: bob
+    priv_protocol: aes
+    priv_passphrase: bobprivacy
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newSNMPTrapInputFromConfig(pConf, service.MockResources())
+	require.ErrorContains(t, err, "requires an authentication protocol")
+}
@@ -0,0 +1,324 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snmp
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mibMacros are the macros of SMIv1 and SMIv2 that assign an OID to a name.
+var mibMacros = map[string]struct{}{
+	"OBJECT-TYPE":        {},
+	"OBJECT-IDENTITY":    {},
+	"MODULE-IDENTITY":    {},
+	"NOTIFICATION-TYPE":  {},
+	"OBJECT-GROUP":       {},
+	"NOTIFICATION-GROUP": {},
+	"MODULE-COMPLIANCE":  {},
+	"AGENT-CAPABILITIES": {},
+}
+
+// builtinMIBDefs are the nodes defined by the core SMI modules, which allows
+// the OIDs of standard traps to be resolved without loading any MIB files.
+var builtinMIBDefs = []mibDef{
+	{module: "SNMPv2-SMI", name: "org", parent: "iso", subIDs: []int{3}},
+	{module: "SNMPv2-SMI", name: "dod", parent: "org", subIDs: []int{6}},
+	{module: "SNMPv2-SMI", name: "internet", parent: "dod", subIDs: []int{1}},
+	{module: "SNMPv2-SMI", name: "directory", parent: "internet", subIDs: []int{1}},
+	{module: "SNMPv2-SMI", name: "mgmt", parent: "internet", subIDs: []int{2}},
+	{module: "SNMPv2-SMI", name: "mib-2", parent: "mgmt", subIDs: []int{1}},
+	{module: "SNMPv2-SMI", name: "transmission", parent: "mib-2", subIDs: []int{10}},
+	{module: "SNMPv2-SMI", name: "experimental", parent: "internet", subIDs: []int{3}},
+	{module: "SNMPv2-SMI", name: "private", parent: "internet", subIDs: []int{4}},
+	{module: "SNMPv2-SMI", name: "enterprises", parent: "private", subIDs: []int{1}},
+	{module: "SNMPv2-SMI", name: "security", parent: "internet", subIDs: []int{5}},
+	{module: "SNMPv2-SMI", name: "snmpV2", parent: "internet", subIDs: []int{6}},
+	{module: "SNMPv2-SMI", name: "snmpDomains", parent: "snmpV2", subIDs: []int{1}},
+	{module: "SNMPv2-SMI", name: "snmpProxys", parent: "snmpV2", subIDs: []int{2}},
+	{module: "SNMPv2-SMI", name: "snmpModules", parent: "snmpV2", subIDs: []int{3}},
+	{module: "SNMPv2-MIB", name: "system", parent: "mib-2", subIDs: []int{1}},
+	{module: "SNMPv2-MIB", name: "sysDescr", parent: "system", subIDs: []int{1}},
+	{module: "SNMPv2-MIB", name: "sysObjectID", parent: "system", subIDs: []int{2}},
+	{module: "SNMPv2-MIB", name: "sysUpTime", parent: "system", subIDs: []int{3}},
+	{module: "SNMPv2-MIB", name: "sysContact", parent: "system", subIDs: []int{4}},
+	{module: "SNMPv2-MIB", name: "sysName", parent: "system", subIDs: []int{5}},
+	{module: "SNMPv2-MIB", name: "sysLocation", parent: "system", subIDs: []int{6}},
+	{module: "SNMPv2-MIB", name: "snmpMIB", parent: "snmpModules", subIDs: []int{1}},
+	{module: "SNMPv2-MIB", name: "snmpMIBObjects", parent: "snmpMIB", subIDs: []int{1}},
+	{module: "SNMPv2-MIB", name: "snmpTrap", parent: "snmpMIBObjects", subIDs: []int{4}},
+	{module: "SNMPv2-MIB", name: "snmpTrapOID", parent: "snmpTrap", subIDs: []int{1}},
+	{module: "SNMPv2-MIB", name: "snmpTrapEnterprise", parent: "snmpTrap", subIDs: []int{3}},
+	{module: "SNMPv2-MIB", name: "snmpTraps", parent: "snmpMIBObjects", subIDs: []int{5}},
+	{module: "SNMPv2-MIB", name: "coldStart", parent: "snmpTraps", subIDs: []int{1}},
+	{module: "SNMPv2-MIB", name: "warmStart", parent: "snmpTraps", subIDs: []int{2}},
+	{module: "SNMPv2-MIB", name: "authenticationFailure", parent: "snmpTraps", subIDs: []int{5}},
+	{module: "IF-MIB", name: "linkDown", parent: "snmpTraps", subIDs: []int{3}},
+	{module: "IF-MIB", name: "linkUp", parent: "snmpTraps", subIDs: []int{4}},
+}
+
+// mibDef is an assignment of an OID to a name within a MIB module, relative
+// to a parent node or, when parent is empty, absolute.
+type mibDef struct {
+	module string
+	name   string
+	parent string
+	subIDs []int
+}
+
+// mibTree translates numeric OIDs into the names assigned to them by MIB
+// modules.
+type mibTree struct {
+	names map[string]string
+}
+
+func newMIBTree(paths []string) (*mibTree, error) {
+	defs := append([]mibDef{}, builtinMIBDefs...)
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			fileDefs, err := parseMIB(string(data))
+			if err != nil {
+				return fmt.Errorf("failed to parse MIB file %v: %w", path, err)
+			}
+			defs = append(defs, fileDefs...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	byName := map[string]mibDef{}
+	for _, d := range defs {
+		byName[d.name] = d
+	}
+
+	roots := map[string][]int{
+		"ccitt":           {0},
+		"iso":             {1},
+		"joint-iso-ccitt": {2},
+	}
+	resolved := map[string][]int{}
+	var resolve func(name string, depth int) ([]int, bool)
+	resolve = func(name string, depth int) ([]int, bool) {
+		if oid, exists := roots[name]; exists {
+			return oid, true
+		}
+		if oid, exists := resolved[name]; exists {
+			return oid, true
+		}
+		d, exists := byName[name]
+		if !exists || depth > 128 {
+			return nil, false
+		}
+		var oid []int
+		if d.parent != "" {
+			parentOID, ok := resolve(d.parent, depth+1)
+			if !ok {
+				return nil, false
+			}
+			oid = append(oid, parentOID...)
+		}
+		oid = append(oid, d.subIDs...)
+		resolved[name] = oid
+		return oid, true
+	}
+
+	t := &mibTree{names: map[string]string{}}
+	for _, d := range defs {
+		oid, ok := resolve(d.name, 0)
+		if !ok {
+			continue
+		}
+		t.names[oidString(oid)] = d.module + "::" + d.name
+	}
+	return t, nil
+}
+
+func oidString(oid []int) string {
+	parts := make([]string, len(oid))
+	for i, id := range oid {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ".")
+}
+
+// translate returns the name of the longest known prefix of an OID followed
+// by the remaining sub-identifiers, or an empty string when no prefix is
+// known.
+func (t *mibTree) translate(oid string) string {
+	oid = strings.TrimPrefix(oid, ".")
+	prefix, suffix := oid, ""
+	for {
+		if name, exists := t.names[prefix]; exists {
+			return name + suffix
+		}
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			return ""
+		}
+		prefix, suffix = prefix[:i], prefix[i:]+suffix
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// parseMIB extracts the OID assignments of a MIB module. Only the parts of the
+// SMI grammar that assign OIDs are interpreted, everything else is skipped.
+func parseMIB(data string) ([]mibDef, error) {
+	tokens := tokenizeMIB(data)
+
+	var module string
+	var defs []mibDef
+	for i := 0; i < len(tokens); i++ {
+		if i+2 < len(tokens) && tokens[i+1] == "DEFINITIONS" && tokens[i+2] == "::=" {
+			module = tokens[i]
+			continue
+		}
+		if !isMIBValueName(tokens[i]) || i+1 >= len(tokens) {
+			continue
+		}
+
+		var isAssignment bool
+		if _, isAssignment = mibMacros[tokens[i+1]]; !isAssignment {
+			isAssignment = i+2 < len(tokens) && tokens[i+1] == "OBJECT" && tokens[i+2] == "IDENTIFIER"
+		}
+		if !isAssignment {
+			continue
+		}
+
+		// Find the value of the assignment, which is the first ::= that
+		// follows the name.
+		j := i + 1
+		for j < len(tokens) && tokens[j] != "::=" {
+			j++
+		}
+		if j+1 >= len(tokens) || tokens[j+1] != "{" {
+			// SMIv1 TRAP-TYPE values and malformed assignments are skipped.
+			i = j
+			continue
+		}
+		k := j + 2
+		for k < len(tokens) && tokens[k] != "}" {
+			k++
+		}
+		if k >= len(tokens) {
+			return nil, fmt.Errorf("unterminated OID value for %v", tokens[i])
+		}
+
+		d, err := parseMIBOIDValue(tokens[j+2 : k])
+		if err != nil {
+			return nil, fmt.Errorf("invalid OID value for %v: %w", tokens[i], err)
+		}
+		d.module = module
+		d.name = tokens[i]
+		defs = append(defs, d)
+		i = k
+	}
+	return defs, nil
+}
+
+// parseMIBOIDValue parses the components of an OID value such as
+// `{ ifEntry 1 }` or `{ iso(1) org(3) 6 }`.
+func parseMIBOIDValue(components []string) (mibDef, error) {
+	var d mibDef
+	for i := 0; i < len(components); i++ {
+		c := components[i]
+		if id, err := strconv.Atoi(c); err == nil {
+			d.subIDs = append(d.subIDs, id)
+			continue
+		}
+		if i+3 < len(components) && components[i+1] == "(" && components[i+3] == ")" {
+			id, err := strconv.Atoi(components[i+2])
+			if err != nil {
+				return d, fmt.Errorf("invalid sub-identifier %v", components[i+2])
+			}
+			d.subIDs = append(d.subIDs, id)
+			i += 3
+			continue
+		}
+		if i != 0 || !isMIBValueName(c) {
+			return d, fmt.Errorf("unexpected component %v", c)
+		}
+		d.parent = c
+	}
+	if d.parent == "" && len(d.subIDs) == 0 {
+		return d, fmt.Errorf("empty value")
+	}
+	return d, nil
+}
+
+func isMIBValueName(s string) bool {
+	return s != "" && unicode.IsLower(rune(s[0]))
+}
+
+// tokenizeMIB splits a MIB module into identifiers, numbers and the symbols
+// `::=`, `{`, `}`, `(`, `)`, `,` and `;`, dropping comments and strings.
+func tokenizeMIB(data string) []string {
+	var tokens []string
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '-' && i+1 < len(data) && data[i+1] == '-':
+			// Comments end at either the end of the line or the next --.
+			i += 2
+			for i < len(data) && data[i] != '\n' {
+				if data[i] == '-' && i+1 < len(data) && data[i+1] == '-' {
+					i += 2
+					break
+				}
+				i++
+			}
+		case c == '"':
+			i++
+			for i < len(data) && data[i] != '"' {
+				i++
+			}
+			i++
+		case strings.HasPrefix(data[i:], "::="):
+			tokens = append(tokens, "::=")
+			i += 3
+		case strings.IndexByte("{}(),;", c) >= 0:
+			tokens = append(tokens, string(c))
+			i++
+		case isMIBIdentChar(c):
+			j := i
+			for j < len(data) && (isMIBIdentChar(data[j]) || (data[j] == '-' && j+1 < len(data) && data[j+1] != '-')) {
+				j++
+			}
+			tokens = append(tokens, data[i:j])
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+func isMIBIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snmp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMIB = `
+ACME-MIB DEFINITIONS ::= BEGIN
+
+IMPORTS
+    MODULE-IDENTITY, OBJECT-TYPE, NOTIFICATION-TYPE, Integer32, enterprises
+        FROM SNMPv2-SMI; -- core definitions
+
+acmeMIB MODULE-IDENTITY
+    LAST-UPDATED "202501010000Z"
+    ORGANIZATION "Acme -- not a comment"
+    CONTACT-INFO "ops@example.com"
+    DESCRIPTION  "The MIB module of ::= { nothing 1 } Acme devices."
+    ::= { enterprises 99999 }
+
+acmeObjects   OBJECT IDENTIFIER ::= { acmeMIB 1 }
+acmeTraps     OBJECT IDENTIFIER ::= { acmeMIB 2 }
+
+AcmeEntry ::= SEQUENCE {
+    acmeIndex Integer32,
+    acmeTemp  Integer32
+}
+
+acmeTable OBJECT-TYPE
+    SYNTAX      SEQUENCE OF AcmeEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "A table."
+    ::= { acmeObjects 1 }
+
+acmeEntry OBJECT-TYPE
+    SYNTAX      AcmeEntry
+    MAX-ACCESS  not-accessible
+    STATUS      current
+    DESCRIPTION "A row."
+    INDEX       { acmeIndex }
+    ::= { acmeTable 1 }
+
+acmeTemp OBJECT-TYPE
+    SYNTAX      INTEGER { cold(1), hot(2) }
+    MAX-ACCESS  read-only
+    STATUS      current
+    DESCRIPTION "A temperature."
+    DEFVAL      { cold }
+    ::= { acmeEntry 2 }
+
+acmeOverheat NOTIFICATION-TYPE
+    OBJECTS     { acmeTemp }
+    STATUS      current
+    DESCRIPTION "Too hot."
+    ::= { acmeTraps 1 }
+
+acmeLegacy OBJECT IDENTIFIER ::= { iso(1) org(3) dod(6) 42 }
+
+END
+`
+
+func TestParseMIB(t *testing.T) {
+	defs, err := parseMIB(testMIB)
+	require.NoError(t, err)
+
+	assert.Equal(t, []mibDef{
+		{module: "ACME-MIB", name: "acmeMIB", parent: "enterprises", subIDs: []int{99999}},
+		{module: "ACME-MIB", name: "acmeObjects", parent: "acmeMIB", subIDs: []int{1}},
+		{module: "ACME-MIB", name: "acmeTraps", parent: "acmeMIB", subIDs: []int{2}},
+		{module: "ACME-MIB", name: "acmeTable", parent: "acmeObjects", subIDs: []int{1}},
+		{module: "ACME-MIB", name: "acmeEntry", parent: "acmeTable", subIDs: []int{1}},
+		{module: "ACME-MIB", name: "acmeTemp", parent: "acmeEntry", subIDs: []int{2}},
+		{module: "ACME-MIB", name: "acmeOverheat", parent: "acmeTraps", subIDs: []int{1}},
+		{module: "ACME-MIB", name: "acmeLegacy", subIDs: []int{1, 3, 6, 42}},
+	}, defs)
+}
+
+func TestParseMIBErrors(t *testing.T) {
+	_, err := parseMIB(`FOO DEFINITIONS ::= BEGIN foo OBJECT IDENTIFIER ::= { bar 1`)
+	require.Error(t, err)
+
+	_, err = parseMIB(`FOO DEFINITIONS ::= BEGIN foo OBJECT IDENTIFIER ::= { bar baz } END`)
+	require.Error(t, err)
+}
+
+func TestMIBTreeTranslate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ACME-MIB.txt"), []byte(testMIB), 0o644))
+
+	tree, err := newMIBTree([]string{dir})
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		oid  string
+		name string
+	}{
+		{oid: ".1.3.6.1.4.1.99999.1.1.1.2.7", name: "ACME-MIB::acmeTemp.7"},
+		{oid: ".1.3.6.1.4.1.99999.2.1", name: "ACME-MIB::acmeOverheat"},
+		{oid: "1.3.6.1.4.1.99999.3", name: "ACME-MIB::acmeMIB.3"},
+		{oid: ".1.3.6.42.1", name: "ACME-MIB::acmeLegacy.1"},
+		{oid: ".1.3.6.1.2.1.1.3.0", name: "SNMPv2-MIB::sysUpTime.0"},
+		{oid: ".1.3.6.1.6.3.1.1.5.3", name: "IF-MIB::linkDown"},
+		{oid: ".2.5", name: ""},
+	} {
+		assert.Equal(t, test.name, tree.translate(test.oid), test.oid)
+	}
+
+	_, err = newMIBTree([]string{filepath.Join(dir, "does-not-exist")})
+	require.Error(t, err)
+}
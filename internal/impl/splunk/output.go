@@ -14,10 +14,17 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
 
@@ -34,6 +41,11 @@ const (
 	soFieldEventIndex      = "event_index"
 	soFieldTLS             = "tls"
 	soFieldBatching        = "batching"
+	soFieldAck             = "indexer_acknowledgment"
+	soFieldAckEnabled      = "enabled"
+	soFieldAckChannel      = "channel"
+	soFieldAckPollInterval = "poll_interval"
+	soFieldAckTimeout      = "timeout"
 
 	// Deprecated fields
 	soFieldSkipCertVerify = "skip_cert_verify"
@@ -51,16 +63,43 @@ func outputSpec() *service.ConfigSpec {
 		Version("4.30.0").
 		Categories("Services").
 		Summary(`Publishes messages to a Splunk HTTP Endpoint Collector (HEC).`).
-		Description(service.OutputPerformanceDocs(true, true)).
+		Description(`
+Messages that are JSON objects containing an `+"`event`"+` field are sent as they are, all other messages are wrapped within the `+"`event`"+` field of a new object. The `+"`host`, `source`, `sourcetype` and `index`"+` of each event can be set with interpolation functions, in which case they are resolved separately for each message of a batch.
+
+== Indexer acknowledgment
+
+By default a batch is acknowledged once the HEC endpoint has accepted it, which does not guarantee that the events have been indexed. When `+"`indexer_acknowledgment.enabled`"+` is set to `+"`true`"+` each request is sent on a channel and the output polls the acknowledgment endpoint of the HEC until Splunk confirms that the events have been indexed, only then is the batch acknowledged upstream. If the confirmation is not received within `+"`indexer_acknowledgment.timeout`"+` the batch is sent again, which provides at-least-once delivery guarantees.
+
+Indexer acknowledgment must also be enabled for the HEC token being used.
+`+service.OutputPerformanceDocs(true, true)).
 		Fields(
 			service.NewStringField(soFieldURL).Description("Full HTTP Endpoint Collector (HEC) URL.").Example("https://foobar.splunkcloud.com/services/collector/event"),
 			service.NewStringField(soFieldToken).Description("A bot token used for authentication.").Secret(),
 			service.NewBoolField(soFieldGzip).Description("Enable gzip compression").Default(false),
-			service.NewStringField(soFieldEventHost).Description("Set the host value to assign to the event data. Overrides existing host field if present.").Optional(),
-			service.NewStringField(soFieldEventSource).Description("Set the source value to assign to the event data. Overrides existing source field if present.").Optional(),
-			service.NewStringField(soFieldEventSourceType).Description("Set the sourcetype value to assign to the event data. Overrides existing sourcetype field if present.").Optional(),
-			service.NewStringField(soFieldEventIndex).Description("Set the index value to assign to the event data. Overrides existing index field if present.").Optional(),
+			service.NewInterpolatedStringField(soFieldEventHost).Description("Set the host value to assign to the event data. Overrides existing host field if present.").Optional(),
+			service.NewInterpolatedStringField(soFieldEventSource).Description("Set the source value to assign to the event data. Overrides existing source field if present.").Optional(),
+			service.NewInterpolatedStringField(soFieldEventSourceType).Description("Set the sourcetype value to assign to the event data. Overrides existing sourcetype field if present.").Optional(),
+			service.NewInterpolatedStringField(soFieldEventIndex).Description("Set the index value to assign to the event data. Overrides existing index field if present.").Optional(),
 			service.NewTLSToggledField(soFieldTLS),
+			service.NewObjectField(soFieldAck,
+				service.NewBoolField(soFieldAckEnabled).
+					Description("Whether to wait for Splunk to confirm that events have been indexed before acknowledging a batch.").
+					Default(false),
+				service.NewStringField(soFieldAckChannel).
+					Description("The GUID of the channel to send events on. When left empty a random channel is generated when the output is created.").
+					Default("").
+					Advanced(),
+				service.NewDurationField(soFieldAckPollInterval).
+					Description("The period to wait between requests to the acknowledgment endpoint.").
+					Default("1s").
+					Advanced(),
+				service.NewDurationField(soFieldAckTimeout).
+					Description("The maximum period to wait for events to be indexed before the batch is sent again.").
+					Default("1m").
+					Advanced(),
+			).
+				Description("Wait for events to be indexed before acknowledging them.").
+				Version("4.48.0"),
 			service.NewOutputMaxInFlightField(),
 			service.NewBatchPolicyField(soFieldBatching),
 
@@ -120,10 +159,16 @@ type output struct {
 	url                string
 	token              string
 	useGzipCompression bool
-	eventHost          string
-	eventSource        string
-	eventSourceType    string
-	eventIndex         string
+	eventHost          *service.InterpolatedString
+	eventSource        *service.InterpolatedString
+	eventSourceType    *service.InterpolatedString
+	eventIndex         *service.InterpolatedString
+
+	ackEnabled      bool
+	ackURL          string
+	ackChannel      string
+	ackPollInterval time.Duration
+	ackTimeout      time.Duration
 
 	client http.Client
 	log    *service.Logger
@@ -146,20 +191,47 @@ func outputFromParsed(pConf *service.ParsedConfig, log *service.Logger) (o *outp
 		return
 	}
 
-	if o.eventHost, err = pConf.FieldString(soFieldEventHost); err != nil {
-		return
-	}
-
-	if o.eventSource, err = pConf.FieldString(soFieldEventSource); err != nil {
-		return
+	for _, f := range []struct {
+		name   string
+		target **service.InterpolatedString
+	}{
+		{soFieldEventHost, &o.eventHost},
+		{soFieldEventSource, &o.eventSource},
+		{soFieldEventSourceType, &o.eventSourceType},
+		{soFieldEventIndex, &o.eventIndex},
+	} {
+		if !pConf.Contains(f.name) {
+			continue
+		}
+		if *f.target, err = pConf.FieldInterpolatedString(f.name); err != nil {
+			return
+		}
 	}
 
-	if o.eventSourceType, err = pConf.FieldString(soFieldEventSourceType); err != nil {
+	ackConf := pConf.Namespace(soFieldAck)
+	if o.ackEnabled, err = ackConf.FieldBool(soFieldAckEnabled); err != nil {
 		return
 	}
-
-	if o.eventIndex, err = pConf.FieldString(soFieldEventIndex); err != nil {
-		return
+	if o.ackEnabled {
+		if o.ackChannel, err = ackConf.FieldString(soFieldAckChannel); err != nil {
+			return
+		}
+		if o.ackChannel == "" {
+			var id uuid.UUID
+			if id, err = uuid.NewV4(); err != nil {
+				return
+			}
+			o.ackChannel = id.String()
+		}
+		if o.ackPollInterval, err = ackConf.FieldDuration(soFieldAckPollInterval); err != nil {
+			return
+		}
+		if o.ackTimeout, err = ackConf.FieldDuration(soFieldAckTimeout); err != nil {
+			return
+		}
+		if o.ackURL, err = ackURLFromEventURL(o.url); err != nil {
+			return
+		}
 	}
 
 	var tlsConf *tls.Config
@@ -184,6 +256,24 @@ func outputFromParsed(pConf *service.ParsedConfig, log *service.Logger) (o *outp
 	return
 }
 
+// ackURLFromEventURL derives the URL of the HEC acknowledgment endpoint from
+// the URL events are sent to.
+func ackURLFromEventURL(eventURL string) (string, error) {
+	u, err := url.Parse(eventURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %v: %w", soFieldURL, err)
+	}
+	prefix := u.Path
+	if i := strings.Index(prefix, "/services/collector"); i >= 0 {
+		prefix = prefix[:i]
+	} else {
+		prefix = ""
+	}
+	u.Path = prefix + "/services/collector/ack"
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
 //------------------------------------------------------------------------------
 
 func (o *output) Connect(_ context.Context) error { return nil }
@@ -192,6 +282,9 @@ func (o *output) WriteBatch(ctx context.Context, b service.MessageBatch) (err er
 	header := make(http.Header)
 	header.Set("Content-Type", "application/json")
 	header.Set("Authorization", "Splunk "+o.token)
+	if o.ackEnabled {
+		header.Set("X-Splunk-Request-Channel", o.ackChannel)
+	}
 
 	var payload bytes.Buffer
 	var payloadWriter io.Writer = &payload
@@ -204,7 +297,7 @@ func (o *output) WriteBatch(ctx context.Context, b service.MessageBatch) (err er
 	}
 	encoder := json.NewEncoder(payloadWriter)
 
-	for _, msg := range b {
+	for i, msg := range b {
 		data, err := msg.AsStructuredMut()
 		if err != nil {
 			rawData, err := msg.AsBytes()
@@ -222,17 +315,25 @@ func (o *output) WriteBatch(ctx context.Context, b service.MessageBatch) (err er
 			dataObj = map[string]any{"event": data}
 		}
 
-		if o.eventHost != "" {
-			dataObj["host"] = o.eventHost
-		}
-		if o.eventSource != "" {
-			dataObj["source"] = o.eventSource
-		}
-		if o.eventSourceType != "" {
-			dataObj["sourcetype"] = o.eventSourceType
-		}
-		if o.eventIndex != "" {
-			dataObj["index"] = o.eventIndex
+		for _, f := range []struct {
+			key   string
+			value *service.InterpolatedString
+		}{
+			{"host", o.eventHost},
+			{"source", o.eventSource},
+			{"sourcetype", o.eventSourceType},
+			{"index", o.eventIndex},
+		} {
+			if f.value == nil {
+				continue
+			}
+			v, err := b.TryInterpolatedString(i, f.value)
+			if err != nil {
+				return fmt.Errorf("%v interpolation error: %w", f.key, err)
+			}
+			if v != "" {
+				dataObj[f.key] = v
+			}
 		}
 
 		err = encoder.Encode(dataObj)
@@ -270,7 +371,80 @@ func (o *output) WriteBatch(ctx context.Context, b service.MessageBatch) (err er
 		return fmt.Errorf("HTTP request returned status: %d", resp.StatusCode)
 	}
 
-	return
+	if !o.ackEnabled {
+		return
+	}
+
+	var hecResp struct {
+		AckID *int64 `json:"ackId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&hecResp); err != nil {
+		return fmt.Errorf("failed to parse HEC response: %s", err)
+	}
+	if hecResp.AckID == nil {
+		return errors.New("HEC response did not contain an ackId, indexer acknowledgment may not be enabled for the token")
+	}
+	return o.waitForAck(ctx, *hecResp.AckID)
+}
+
+// waitForAck polls the acknowledgment endpoint of the HEC until the provided
+// ack ID has been indexed or the ack timeout is reached.
+func (o *output) waitForAck(ctx context.Context, ackID int64) error {
+	ctx, done := context.WithTimeout(ctx, o.ackTimeout)
+	defer done()
+
+	ticker := time.NewTicker(o.ackPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for indexer acknowledgment of ackId %d: %w", ackID, ctx.Err())
+		}
+
+		acked, err := o.checkAck(ctx, ackID)
+		if err != nil {
+			o.log.Debugf("Failed to check indexer acknowledgment status: %v", err)
+			continue
+		}
+		if acked {
+			return nil
+		}
+	}
+}
+
+func (o *output) checkAck(ctx context.Context, ackID int64) (bool, error) {
+	body, err := json.Marshal(map[string]any{"acks": []int64{ackID}})
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.ackURL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to construct HTTP request: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Splunk "+o.token)
+	req.Header.Set("X-Splunk-Request-Channel", o.ackChannel)
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute http request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("HTTP request returned status: %d", resp.StatusCode)
+	}
+
+	var ackResp struct {
+		Acks map[string]bool `json:"acks"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ackResp); err != nil {
+		return false, fmt.Errorf("failed to parse acknowledgment response: %s", err)
+	}
+	return ackResp.Acks[strconv.FormatInt(ackID, 10)], nil
 }
 
 func (o *output) Close(_ context.Context) error { return nil }
@@ -0,0 +1,202 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package splunk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeHEC struct {
+	t *testing.T
+
+	mut        sync.Mutex
+	events     []map[string]any
+	channels   []string
+	ackPolls   int
+	ackAfter   int
+	ackEnabled bool
+}
+
+func (f *fakeHEC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	assert.Equal(f.t, "Splunk footoken", r.Header.Get("Authorization"))
+	f.channels = append(f.channels, r.Header.Get("X-Splunk-Request-Channel"))
+
+	switch r.URL.Path {
+	case "/services/collector/event":
+		dec := json.NewDecoder(r.Body)
+		for {
+			var e map[string]any
+			if err := dec.Decode(&e); err == io.EOF {
+				break
+			} else if !assert.NoError(f.t, err) {
+				return
+			}
+			f.events = append(f.events, e)
+		}
+		if f.ackEnabled {
+			_, _ = w.Write([]byte(`{"text":"Success","code":0,"ackId":7}`))
+		} else {
+			_, _ = w.Write([]byte(`{"text":"Success","code":0}`))
+		}
+	case "/services/collector/ack":
+		var req struct {
+			Acks []int64 `json:"acks"`
+		}
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(f.t, []int64{7}, req.Acks)
+		f.ackPolls++
+		if f.ackPolls >= f.ackAfter {
+			_, _ = w.Write([]byte(`{"acks":{"7":true}}`))
+		} else {
+			_, _ = w.Write([]byte(`{"acks":{"7":false}}`))
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestOutput(t *testing.T, serverURL, extra string) *output {
+	t.Helper()
+
+	conf, err := outputSpec().ParseYAML(`
+url: `+serverURL+`/services/collector/event
+token: footoken
+`+extra, nil)
+	require.NoError(t, err)
+
+	o, err := outputFromParsed(conf, service.MockResources().Logger())
+	require.NoError(t, err)
+	return o
+}
+
+func TestOutputInterpolatedEventFields(t *testing.T) {
+	hec := &fakeHEC{t: t}
+	srv := httptest.NewServer(hec)
+	t.Cleanup(srv.Close)
+
+	o := newTestOutput(t, srv.URL, `
+event_host: ${! @host }
+event_index: ${! @tenant }_logs
+event_sourcetype: _json
+`)
+
+	msgA := service.NewMessage([]byte(`{"tenant":"acme","msg":"a"}`))
+	msgA.MetaSetMut("host", "alpha")
+	msgA.MetaSetMut("tenant", "acme")
+	msgB := service.NewMessage([]byte(`not json`))
+	msgB.MetaSetMut("host", "beta")
+	msgB.MetaSetMut("tenant", "globex")
+
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{msgA, msgB}))
+
+	require.Len(t, hec.events, 2)
+	assert.Equal(t, map[string]any{
+		"event":      map[string]any{"tenant": "acme", "msg": "a"},
+		"host":       "alpha",
+		"index":      "acme_logs",
+		"sourcetype": "_json",
+	}, hec.events[0])
+	assert.Equal(t, map[string]any{
+		"event":      "not json",
+		"host":       "beta",
+		"index":      "globex_logs",
+		"sourcetype": "_json",
+	}, hec.events[1])
+	assert.Equal(t, []string{""}, hec.channels)
+}
+
+func TestOutputIndexerAcknowledgment(t *testing.T) {
+	hec := &fakeHEC{t: t, ackEnabled: true, ackAfter: 3}
+	srv := httptest.NewServer(hec)
+	t.Cleanup(srv.Close)
+
+	o := newTestOutput(t, srv.URL, `
+indexer_acknowledgment:
+  enabled: true
+  channel: 0c4a1a6e-0d0e-4f5e-9c4b-6a1d3c1e2f00
+  poll_interval: 10ms
+`)
+
+	require.NoError(t, o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"event":"hello"}`)),
+	}))
+
+	assert.Equal(t, 3, hec.ackPolls)
+	for _, c := range hec.channels {
+		assert.Equal(t, "0c4a1a6e-0d0e-4f5e-9c4b-6a1d3c1e2f00", c)
+	}
+}
+
+func TestOutputIndexerAcknowledgmentTimeout(t *testing.T) {
+	hec := &fakeHEC{t: t, ackEnabled: true, ackAfter: 1000}
+	srv := httptest.NewServer(hec)
+	t.Cleanup(srv.Close)
+
+	o := newTestOutput(t, srv.URL, `
+indexer_acknowledgment:
+  enabled: true
+  poll_interval: 10ms
+  timeout: 100ms
+`)
+	assert.NotEmpty(t, o.ackChannel)
+
+	start := time.Now()
+	err := o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"event":"hello"}`)),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out waiting for indexer acknowledgment")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestOutputIndexerAcknowledgmentMissingAckID(t *testing.T) {
+	hec := &fakeHEC{t: t}
+	srv := httptest.NewServer(hec)
+	t.Cleanup(srv.Close)
+
+	o := newTestOutput(t, srv.URL, `
+indexer_acknowledgment:
+  enabled: true
+`)
+
+	err := o.WriteBatch(context.Background(), service.MessageBatch{
+		service.NewMessage([]byte(`{"event":"hello"}`)),
+	})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "did not contain an ackId"), err.Error())
+}
+
+func TestAckURLFromEventURL(t *testing.T) {
+	for in, exp := range map[string]string{
+		"https://foo.splunkcloud.com/services/collector/event": "https://foo.splunkcloud.com/services/collector/ack",
+		"https://foo:8088/services/collector/raw?channel=x":    "https://foo:8088/services/collector/ack",
+		"https://foo/proxy/services/collector":                 "https://foo/proxy/services/collector/ack",
+		"http://localhost:8088/custom":                         "http://localhost:8088/services/collector/ack",
+	} {
+		act, err := ackURLFromEventURL(in)
+		require.NoError(t, err)
+		assert.Equal(t, exp, act, in)
+	}
+}
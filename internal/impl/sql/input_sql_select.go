@@ -17,23 +17,41 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 
+	"github.com/Jeffail/checkpoint"
 	"github.com/Jeffail/shutdown"
 
 	"github.com/redpanda-data/benthos/v4/public/bloblang"
 	"github.com/redpanda-data/benthos/v4/public/service"
 )
 
+const (
+	ssiFieldIncremental             = "incremental"
+	ssiFieldIncrementalColumn       = "column"
+	ssiFieldIncrementalCache        = "cache"
+	ssiFieldIncrementalCacheKey     = "cache_key"
+	ssiFieldIncrementalPollInterval = "poll_interval"
+	ssiFieldIncrementalOverlap      = "overlap"
+)
+
 func sqlSelectInputConfig() *service.ConfigSpec {
 	spec := service.NewConfigSpec().
 		Beta().
 		Categories("Services").
 		Summary("Executes a select query and creates a message for each row received.").
-		Description(`Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a xref:components:inputs/sequence.adoc[sequence] to execute).`).
+		Description(`Once the rows from the query are exhausted this input shuts down, allowing the pipeline to gracefully terminate (or the next input in a xref:components:inputs/sequence.adoc[sequence] to execute).
+
+== Incremental polling
+
+When the ` + "`incremental`" + ` field is set this input no longer shuts down once the rows are exhausted. Instead, the rows are ordered by a watermark column, such as a timestamp or sequence, and the table is polled periodically for rows with a watermark greater than the last one consumed. The watermark of the latest acknowledged row is stored in a cache resource, so that polling resumes where it left off when the pipeline restarts.
+
+Rows that are committed with a watermark lower than one already consumed, which can happen when timestamps are assigned before long-running transactions commit, are missed unless an ` + "`overlap`" + ` is configured. The overlap causes each poll to re-read rows with a timestamp within the overlap window of the watermark, and therefore results in duplicate messages which can be removed with the xref:components:processors/dedupe.adoc[` + "`dedupe`" + ` processor].`).
 		Field(driverField).
 		Field(dsnField).
 		Field(service.NewStringField("table").
@@ -60,6 +78,27 @@ func sqlSelectInputConfig() *service.ConfigSpec {
 			Description("An optional suffix to append to the select query.").
 			Optional().
 			Advanced()).
+		Field(service.NewObjectField(ssiFieldIncremental,
+			service.NewStringField(ssiFieldIncrementalColumn).
+				Description("The watermark column, which must be selected by the query and increase for new rows. The column must either be a timestamp, an integer, a float or a string which sorts in the order that rows are added.").
+				Example("updated_at").
+				Example("id"),
+			service.NewStringField(ssiFieldIncrementalCache).
+				Description("A xref:components:caches/about.adoc[cache resource] to store the watermark of the latest acknowledged row in."),
+			service.NewStringField(ssiFieldIncrementalCacheKey).
+				Description("The key to store the watermark under in the cache. Defaults to the name of the table.").
+				Optional(),
+			service.NewDurationField(ssiFieldIncrementalPollInterval).
+				Description("The period to wait between polls once the rows of the previous poll are exhausted.").
+				Default("5s"),
+			service.NewDurationField(ssiFieldIncrementalOverlap).
+				Description("A window by which each poll re-reads rows with a timestamp watermark lower than the latest consumed, in order to consume late rows. Ignored for watermarks that aren't timestamps.").
+				Default("0s").
+				Example("30s"),
+		).
+			Description("Continuously poll the table for new rows by tracking a watermark column.").
+			Optional().
+			Version("4.48.0")).
 		Field(service.NewAutoRetryNacksToggleField())
 
 	for _, f := range connFields() {
@@ -83,6 +122,28 @@ input:
       root = [
         now().ts_unix() - 3600
       ]
+`,
+		).
+		Example("Poll a Table for New Rows (MySQL)",
+			`
+Here we poll a table every ten seconds for rows which were updated since the last poll, storing the watermark of the latest row in a Redis cache:`,
+			`
+input:
+  sql_select:
+    driver: mysql
+    dsn: foouser:foopassword@tcp(localhost:3306)/foodb?parseTime=true
+    table: footable
+    columns: [ '*' ]
+    incremental:
+      column: updated_at
+      cache: watermarks
+      poll_interval: 10s
+      overlap: 1m
+
+cache_resources:
+  - label: watermarks
+    redis:
+      url: redis://localhost:6379
 `,
 		)
 	return spec
@@ -116,14 +177,64 @@ type sqlSelectInput struct {
 	where       string
 	argsMapping *bloblang.Executor
 
+	incremental *sqlSelectIncremental
+
 	connSettings *connSettings
 
+	mgr     *service.Resources
 	logger  *service.Logger
 	shutSig *shutdown.Signaller
 }
 
+type sqlSelectIncremental struct {
+	column       string
+	cache        string
+	cacheKey     string
+	pollInterval time.Duration
+	overlap      time.Duration
+
+	// The watermark of the latest row read, and the time of the latest poll.
+	latest   *watermark
+	lastPoll time.Time
+
+	checkpointer *checkpoint.Capped[watermark]
+}
+
+func sqlSelectIncrementalFromParsed(conf *service.ParsedConfig, table string) (*sqlSelectIncremental, error) {
+	if !conf.Contains(ssiFieldIncremental, ssiFieldIncrementalColumn) {
+		return nil, nil
+	}
+	conf = conf.Namespace(ssiFieldIncremental)
+
+	i := &sqlSelectIncremental{
+		cacheKey:     table,
+		checkpointer: checkpoint.NewCapped[watermark](1024),
+	}
+
+	var err error
+	if i.column, err = conf.FieldString(ssiFieldIncrementalColumn); err != nil {
+		return nil, err
+	}
+	if i.cache, err = conf.FieldString(ssiFieldIncrementalCache); err != nil {
+		return nil, err
+	}
+	if conf.Contains(ssiFieldIncrementalCacheKey) {
+		if i.cacheKey, err = conf.FieldString(ssiFieldIncrementalCacheKey); err != nil {
+			return nil, err
+		}
+	}
+	if i.pollInterval, err = conf.FieldDuration(ssiFieldIncrementalPollInterval); err != nil {
+		return nil, err
+	}
+	if i.overlap, err = conf.FieldDuration(ssiFieldIncrementalOverlap); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
 func newSQLSelectInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*sqlSelectInput, error) {
 	s := &sqlSelectInput{
+		mgr:     mgr,
 		logger:  mgr.Logger(),
 		shutSig: shutdown.NewSignaller(),
 	}
@@ -167,6 +278,16 @@ func newSQLSelectInputFromConfig(conf *service.ParsedConfig, mgr *service.Resour
 		s.builder = s.builder.PlaceholderFormat(squirrel.Colon)
 	}
 
+	if s.incremental, err = sqlSelectIncrementalFromParsed(conf, tableStr); err != nil {
+		return nil, err
+	}
+	if s.incremental != nil {
+		if !mgr.HasCache(s.incremental.cache) {
+			return nil, fmt.Errorf("cache resource %v was not found", s.incremental.cache)
+		}
+		s.builder = s.builder.OrderBy(s.incremental.column)
+	}
+
 	if conf.Contains("prefix") {
 		prefixStr, err := conf.FieldString("prefix")
 		if err != nil {
@@ -209,29 +330,15 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 
 	s.connSettings.apply(ctx, db, s.logger)
 
-	var args []any
-	if s.argsMapping != nil {
-		var iargs any
-		if iargs, err = s.argsMapping.Query(nil); err != nil {
-			return
-		}
-
-		var ok bool
-		if args, ok = iargs.([]any); !ok {
-			err = fmt.Errorf("mapping returned non-array result: %T", iargs)
+	if s.incremental != nil {
+		if err = s.loadWatermark(ctx); err != nil {
 			return
 		}
 	}
 
-	queryBuilder := s.builder
-	if s.where != "" {
-		queryBuilder = queryBuilder.Where(s.where, args...)
-	}
 	var rows *sql.Rows
-	if rows, err = queryBuilder.RunWith(db).Query(); err != nil {
+	if rows, err = s.query(db); err != nil {
 		return
-	} else if err = rows.Err(); err != nil {
-		s.logger.With("err", err).Warn("unexpected error while execute raw select")
 	}
 
 	s.db = db
@@ -255,6 +362,87 @@ func (s *sqlSelectInput) Connect(ctx context.Context) (err error) {
 	return nil
 }
 
+func (s *sqlSelectInput) loadWatermark(ctx context.Context) error {
+	var wBytes []byte
+	var cacheErr error
+	if err := s.mgr.AccessCache(ctx, s.incremental.cache, func(c service.Cache) {
+		wBytes, cacheErr = c.Get(ctx, s.incremental.cacheKey)
+	}); err != nil {
+		return err
+	}
+	if errors.Is(cacheErr, service.ErrKeyNotFound) {
+		return nil
+	}
+	if cacheErr != nil {
+		return fmt.Errorf("failed to obtain watermark: %w", cacheErr)
+	}
+
+	w, err := parseWatermark(wBytes)
+	if err != nil {
+		return err
+	}
+	s.incremental.latest = &w
+	return nil
+}
+
+func (s *sqlSelectInput) query(db *sql.DB) (*sql.Rows, error) {
+	var args []any
+	if s.argsMapping != nil {
+		iargs, err := s.argsMapping.Query(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if args, ok = iargs.([]any); !ok {
+			return nil, fmt.Errorf("mapping returned non-array result: %T", iargs)
+		}
+	}
+
+	queryBuilder := s.builder
+	if s.where != "" {
+		queryBuilder = queryBuilder.Where(s.where, args...)
+	}
+	if s.incremental != nil {
+		if s.incremental.latest != nil {
+			arg, err := s.incremental.latest.arg(s.incremental.overlap)
+			if err != nil {
+				return nil, err
+			}
+			queryBuilder = queryBuilder.Where(s.incremental.column+" > ?", arg)
+		}
+		s.incremental.lastPoll = time.Now()
+	}
+
+	rows, err := queryBuilder.RunWith(db).Query()
+	if err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		s.logger.With("err", err).Warn("unexpected error while execute raw select")
+	}
+	return rows, nil
+}
+
+// poll waits for the poll interval to elapse since the previous poll and then
+// queries for rows beyond the latest watermark.
+func (s *sqlSelectInput) poll(ctx context.Context) error {
+	select {
+	case <-time.After(time.Until(s.incremental.lastPoll.Add(s.incremental.pollInterval))):
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.shutSig.HardStopChan():
+		return service.ErrEndOfInput
+	}
+
+	rows, err := s.query(s.db)
+	if err != nil {
+		return err
+	}
+	s.rows = rows
+	return nil
+}
+
 func (s *sqlSelectInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
 	s.dbMut.Lock()
 	defer s.dbMut.Unlock()
@@ -263,18 +451,27 @@ func (s *sqlSelectInput) Read(ctx context.Context) (*service.Message, service.Ac
 		return nil, nil, service.ErrNotConnected
 	}
 
-	if s.rows == nil {
-		return nil, nil, service.ErrEndOfInput
-	}
-
-	if !s.rows.Next() {
-		err := s.rows.Err()
-		if err == nil {
-			err = service.ErrEndOfInput
+	for {
+		if s.rows == nil {
+			if s.incremental == nil || s.db == nil {
+				return nil, nil, service.ErrEndOfInput
+			}
+			if err := s.poll(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+		if s.rows.Next() {
+			break
 		}
+		err := s.rows.Err()
 		_ = s.rows.Close()
 		s.rows = nil
-		return nil, nil, err
+		if err != nil {
+			return nil, nil, err
+		}
+		if s.incremental == nil {
+			return nil, nil, service.ErrEndOfInput
+		}
 	}
 
 	obj, err := sqlRowToMap(s.rows)
@@ -284,6 +481,10 @@ func (s *sqlSelectInput) Read(ctx context.Context) (*service.Message, service.Ac
 		return nil, nil, err
 	}
 
+	if s.incremental != nil {
+		return s.trackWatermark(ctx, obj)
+	}
+
 	msg := service.NewMessage(nil)
 	msg.SetStructuredMut(obj)
 	return msg, func(ctx context.Context, err error) error {
@@ -293,6 +494,39 @@ func (s *sqlSelectInput) Read(ctx context.Context) (*service.Message, service.Ac
 	}, nil
 }
 
+func (s *sqlSelectInput) trackWatermark(ctx context.Context, obj map[string]any) (*service.Message, service.AckFunc, error) {
+	v, exists := obj[s.incremental.column]
+	if !exists {
+		return nil, nil, fmt.Errorf("watermark column %v was not selected", s.incremental.column)
+	}
+	w, err := newWatermark(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release, err := s.incremental.checkpointer.Track(ctx, w, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.incremental.latest = &w
+
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(obj)
+	return msg, func(ctx context.Context, err error) error {
+		highest := release()
+		if highest == nil {
+			return nil
+		}
+		var setErr error
+		if err := s.mgr.AccessCache(ctx, s.incremental.cache, func(c service.Cache) {
+			setErr = c.Set(ctx, s.incremental.cacheKey, highest.bytes(), nil)
+		}); err != nil {
+			return err
+		}
+		return setErr
+	}, nil
+}
+
 func (s *sqlSelectInput) Close(ctx context.Context) error {
 	s.shutSig.TriggerHardStop()
 	s.dbMut.Lock()
@@ -16,8 +16,13 @@ package sql
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/redpanda-data/benthos/v4/public/service"
@@ -43,3 +48,108 @@ args_mapping: 'root = [ this.id ]'
 	require.NoError(t, err)
 	require.NoError(t, selectInput.Close(context.Background()))
 }
+
+func TestSQLSelectInputIncremental(t *testing.T) {
+	ctx, done := context.WithTimeout(context.Background(), time.Second*30)
+	defer done()
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "foo.db") + "?_pragma=journal_mode(wal)"
+	db, err := sql.Open("sqlite", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	_, err = db.Exec(`CREATE TABLE things (id INTEGER PRIMARY KEY, name TEXT)`)
+	require.NoError(t, err)
+	insert := func(id int) {
+		_, err := db.Exec(`INSERT INTO things (id, name) VALUES (?, ?)`, id, fmt.Sprintf("name%v", id))
+		require.NoError(t, err)
+	}
+	for _, id := range []int{2, 1, 3} {
+		insert(id)
+	}
+
+	res := service.MockResources(service.MockResourcesOptAddCache("watermarks"))
+
+	newInput := func() *sqlSelectInput {
+		conf, err := sqlSelectInputConfig().ParseYAML(`
+driver: sqlite
+dsn: `+dsn+`
+table: things
+columns: [ id, name ]
+incremental:
+  column: id
+  cache: watermarks
+  poll_interval: 10ms
+`, nil)
+		require.NoError(t, err)
+
+		i, err := newSQLSelectInputFromConfig(conf, res)
+		require.NoError(t, err)
+		require.NoError(t, i.Connect(ctx))
+		t.Cleanup(func() {
+			_ = i.Close(context.Background())
+		})
+		return i
+	}
+
+	readIDs := func(i *sqlSelectInput, n int) (ids []int64) {
+		for range n {
+			msg, ackFn, err := i.Read(ctx)
+			require.NoError(t, err)
+			v, err := msg.AsStructured()
+			require.NoError(t, err)
+			ids = append(ids, v.(map[string]any)["id"].(int64))
+			require.NoError(t, ackFn(ctx, nil))
+		}
+		return
+	}
+
+	getWatermark := func() string {
+		var b []byte
+		var cErr error
+		require.NoError(t, res.AccessCache(ctx, "watermarks", func(c service.Cache) {
+			b, cErr = c.Get(ctx, "things")
+		}))
+		require.NoError(t, cErr)
+		return string(b)
+	}
+
+	input := newInput()
+	assert.Equal(t, []int64{1, 2, 3}, readIDs(input, 3))
+	assert.Equal(t, `{"type":"int","value":"3"}`, getWatermark())
+
+	insert(4)
+	insert(5)
+	assert.Equal(t, []int64{4, 5}, readIDs(input, 2))
+	assert.Equal(t, `{"type":"int","value":"5"}`, getWatermark())
+	require.NoError(t, input.Close(ctx))
+
+	insert(6)
+	input = newInput()
+	assert.Equal(t, []int64{6}, readIDs(input, 1))
+	assert.Equal(t, `{"type":"int","value":"6"}`, getWatermark())
+}
+
+func TestWatermarkOverlap(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	w, err := newWatermark(ts)
+	require.NoError(t, err)
+
+	w, err = parseWatermark(w.bytes())
+	require.NoError(t, err)
+
+	v, err := w.arg(time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, ts.Add(-time.Minute), v)
+
+	w, err = newWatermark(int64(10))
+	require.NoError(t, err)
+	v, err = w.arg(time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), v)
+
+	_, err = newWatermark(nil)
+	require.Error(t, err)
+}
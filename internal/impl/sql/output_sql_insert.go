@@ -17,6 +17,7 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -60,6 +61,7 @@ func sqlInsertOutputConfig() *service.ConfigSpec {
 			Optional().
 			Advanced().
 			Example([]string{"DELAYED", "IGNORE"})).
+		Field(upsertField()).
 		Field(service.NewIntField("max_in_flight").
 			Description("The maximum number of inserts to run in parallel.").
 			Default(64))
@@ -119,6 +121,7 @@ type sqlInsertOutput struct {
 	dbMut   sync.RWMutex
 
 	useTxStmt     bool
+	mergeStmt     string
 	argsMapping   *bloblang.Executor
 	argsConverter argsConverter
 
@@ -203,14 +206,39 @@ func newSQLInsertOutputFromConfig(conf *service.ParsedConfig, mgr *service.Resou
 		s.builder = s.builder.Suffix(suffixStr)
 	}
 
+	var options []string
 	if conf.Contains("options") {
-		options, err := conf.FieldStringList("options")
-		if err != nil {
+		if options, err = conf.FieldStringList("options"); err != nil {
 			return nil, err
 		}
 		s.builder = s.builder.Options(options...)
 	}
 
+	upsert, err := upsertConfigFromParsed(conf, columns)
+	if err != nil {
+		return nil, err
+	}
+	if upsert != nil {
+		if conf.Contains("suffix") {
+			return nil, errors.New("a suffix cannot be combined with upsert")
+		}
+		if upsert.usesMerge(s.driver) {
+			if conf.Contains("prefix") || len(options) > 0 {
+				return nil, fmt.Errorf("a prefix or options cannot be combined with upsert for the %v driver", s.driver)
+			}
+			if s.mergeStmt, err = upsert.mergeStatement(s.driver, tableStr, columns); err != nil {
+				return nil, err
+			}
+			s.useTxStmt = true
+		} else {
+			suffixStr, err := upsert.suffix(s.driver)
+			if err != nil {
+				return nil, err
+			}
+			s.builder = s.builder.Suffix(suffixStr)
+		}
+	}
+
 	if s.connSettings, err = connSettingsFromParsed(conf, mgr); err != nil {
 		return nil, err
 	}
@@ -257,9 +285,11 @@ func (s *sqlInsertOutput) WriteBatch(ctx context.Context, batch service.MessageB
 		if tx, err = s.db.Begin(); err != nil {
 			return err
 		}
-		sqlStr, _, err := insertBuilder.ToSql()
-		if err != nil {
-			return err
+		sqlStr := s.mergeStmt
+		if sqlStr == "" {
+			if sqlStr, _, err = insertBuilder.ToSql(); err != nil {
+				return err
+			}
 		}
 		if stmt, err = tx.Prepare(sqlStr); err != nil {
 			_ = tx.Rollback()
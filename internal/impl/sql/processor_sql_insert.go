@@ -17,6 +17,7 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -61,7 +62,8 @@ If the insert fails to execute then the message will still remain unchanged and
 			Description("A list of keyword options to add before the INTO clause of the query.").
 			Optional().
 			Advanced().
-			Example([]string{"DELAYED", "IGNORE"}))
+			Example([]string{"DELAYED", "IGNORE"})).
+		Field(upsertField())
 
 	for _, f := range connFields() {
 		spec = spec.Field(f)
@@ -109,6 +111,7 @@ type sqlInsertProcessor struct {
 	dbMut   sync.RWMutex
 
 	useTxStmt     bool
+	mergeStmt     string
 	argsMapping   *bloblang.Executor
 	argsConverter argsConverter
 
@@ -192,14 +195,39 @@ func NewSQLInsertProcessorFromConfig(conf *service.ParsedConfig, mgr *service.Re
 		s.builder = s.builder.Suffix(suffixStr)
 	}
 
+	var options []string
 	if conf.Contains("options") {
-		options, err := conf.FieldStringList("options")
-		if err != nil {
+		if options, err = conf.FieldStringList("options"); err != nil {
 			return nil, err
 		}
 		s.builder = s.builder.Options(options...)
 	}
 
+	upsert, err := upsertConfigFromParsed(conf, columns)
+	if err != nil {
+		return nil, err
+	}
+	if upsert != nil {
+		if conf.Contains("suffix") {
+			return nil, errors.New("a suffix cannot be combined with upsert")
+		}
+		if upsert.usesMerge(driverStr) {
+			if conf.Contains("prefix") || len(options) > 0 {
+				return nil, fmt.Errorf("a prefix or options cannot be combined with upsert for the %v driver", driverStr)
+			}
+			if s.mergeStmt, err = upsert.mergeStatement(driverStr, tableStr, columns); err != nil {
+				return nil, err
+			}
+			s.useTxStmt = true
+		} else {
+			suffixStr, err := upsert.suffix(driverStr)
+			if err != nil {
+				return nil, err
+			}
+			s.builder = s.builder.Suffix(suffixStr)
+		}
+	}
+
 	connSettings, err := connSettingsFromParsed(conf, mgr)
 	if err != nil {
 		return nil, err
@@ -236,9 +264,11 @@ func (s *sqlInsertProcessor) ProcessBatch(ctx context.Context, batch service.Mes
 		if tx, err = s.db.Begin(); err != nil {
 			return nil, err
 		}
-		sqlStr, _, err := insertBuilder.ToSql()
-		if err != nil {
-			return nil, err
+		sqlStr := s.mergeStmt
+		if sqlStr == "" {
+			if sqlStr, _, err = insertBuilder.ToSql(); err != nil {
+				return nil, err
+			}
 		}
 		if stmt, err = tx.Prepare(sqlStr); err != nil {
 			_ = tx.Rollback()
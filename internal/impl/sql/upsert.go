@@ -0,0 +1,182 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sqlInsertFieldUpsert                = "upsert"
+	sqlInsertFieldUpsertConflictColumns = "conflict_columns"
+	sqlInsertFieldUpsertUpdateColumns   = "update_columns"
+)
+
+func upsertField() *service.ConfigField {
+	return service.NewObjectField(sqlInsertFieldUpsert,
+		service.NewStringListField(sqlInsertFieldUpsertConflictColumns).
+			Description("The columns of a primary key or unique constraint which identify whether a row already exists. Upserts are only enabled when at least one column is specified. With the `mysql` driver the conflicting unique key is determined by the database, but these columns are still excluded from the update.").
+			Example([]string{"id"}),
+		service.NewStringListField(sqlInsertFieldUpsertUpdateColumns).
+			Description("The columns to update when a row already exists. When empty all columns that aren't conflict columns are updated.").
+			Example([]string{"name", "updated_at"}).
+			Optional(),
+	).
+		Description(`Update existing rows rather than failing when an inserted row conflicts with a primary key or unique constraint. The statement is generated for the dialect of the driver:
+
+- ` + "`postgres` and `sqlite`: `INSERT ... ON CONFLICT (...) DO UPDATE SET ...`" + `
+- ` + "`mysql`: `INSERT ... ON DUPLICATE KEY UPDATE ...`" + `
+- ` + "`mssql` and `oracle`: `MERGE INTO ...`" + `, executed once per message within a transaction
+
+Other drivers don't support this field. With the ` + "`postgres`" + ` and ` + "`sqlite`" + ` drivers a batch must not contain multiple messages with the same conflict columns, as a single statement can't update a row twice.`).
+		Optional().
+		Advanced().
+		Version("4.48.0")
+}
+
+type upsertConfig struct {
+	conflictColumns []string
+	updateColumns   []string
+}
+
+func upsertConfigFromParsed(conf *service.ParsedConfig, columns []string) (*upsertConfig, error) {
+	if !conf.Contains(sqlInsertFieldUpsert) {
+		return nil, nil
+	}
+	conf = conf.Namespace(sqlInsertFieldUpsert)
+
+	var u upsertConfig
+	var err error
+	if u.conflictColumns, err = conf.FieldStringList(sqlInsertFieldUpsertConflictColumns); err != nil {
+		return nil, err
+	}
+	if len(u.conflictColumns) == 0 {
+		return nil, nil
+	}
+	if conf.Contains(sqlInsertFieldUpsertUpdateColumns) {
+		if u.updateColumns, err = conf.FieldStringList(sqlInsertFieldUpsertUpdateColumns); err != nil {
+			return nil, err
+		}
+	}
+	if len(u.updateColumns) == 0 {
+		for _, c := range columns {
+			if !slices.Contains(u.conflictColumns, c) {
+				u.updateColumns = append(u.updateColumns, c)
+			}
+		}
+	}
+
+	for _, c := range u.conflictColumns {
+		if !slices.Contains(columns, c) {
+			return nil, fmt.Errorf("conflict column %v is not an inserted column", c)
+		}
+	}
+	for _, c := range u.updateColumns {
+		if !slices.Contains(columns, c) {
+			return nil, fmt.Errorf("update column %v is not an inserted column", c)
+		}
+		if slices.Contains(u.conflictColumns, c) {
+			return nil, fmt.Errorf("update column %v is also a conflict column", c)
+		}
+	}
+	return &u, nil
+}
+
+// usesMerge returns true when the driver lacks an insert clause for resolving
+// conflicts and upserts are instead implemented with a MERGE statement.
+func (u *upsertConfig) usesMerge(driver string) bool {
+	return driver == "mssql" || driver == "oracle"
+}
+
+// suffix returns the clause which is appended to an insert statement in order
+// to update conflicting rows.
+func (u *upsertConfig) suffix(driver string) (string, error) {
+	var sets []string
+	switch driver {
+	case "postgres", "sqlite":
+		if len(u.updateColumns) == 0 {
+			return fmt.Sprintf("ON CONFLICT (%v) DO NOTHING", strings.Join(u.conflictColumns, ", ")), nil
+		}
+		for _, c := range u.updateColumns {
+			sets = append(sets, fmt.Sprintf("%v = EXCLUDED.%v", c, c))
+		}
+		return fmt.Sprintf("ON CONFLICT (%v) DO UPDATE SET %v", strings.Join(u.conflictColumns, ", "), strings.Join(sets, ", ")), nil
+	case "mysql":
+		if len(u.updateColumns) == 0 {
+			// MySQL has no DO NOTHING equivalent that doesn't also ignore
+			// unrelated errors, so assign a conflict column to itself instead.
+			return fmt.Sprintf("ON DUPLICATE KEY UPDATE %v = %v", u.conflictColumns[0], u.conflictColumns[0]), nil
+		}
+		for _, c := range u.updateColumns {
+			sets = append(sets, fmt.Sprintf("%v = VALUES(%v)", c, c))
+		}
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", "), nil
+	}
+	return "", fmt.Errorf("upserts are not supported by the %v driver", driver)
+}
+
+// mergeStatement returns a MERGE statement which upserts a single row, with a
+// placeholder for each of the columns in order.
+func (u *upsertConfig) mergeStatement(driver, table string, columns []string) (string, error) {
+	var source string
+	switch driver {
+	case "mssql":
+		placeholders := make([]string, len(columns))
+		for i := range columns {
+			placeholders[i] = "?"
+		}
+		source = fmt.Sprintf("(VALUES (%v)) AS source (%v)", strings.Join(placeholders, ", "), strings.Join(columns, ", "))
+		table += " AS target"
+	case "oracle":
+		selects := make([]string, len(columns))
+		for i, c := range columns {
+			selects[i] = ":" + strconv.Itoa(i+1) + " AS " + c
+		}
+		source = fmt.Sprintf("(SELECT %v FROM dual) source", strings.Join(selects, ", "))
+		table += " target"
+	default:
+		return "", fmt.Errorf("merge statements are not supported by the %v driver", driver)
+	}
+
+	on := make([]string, len(u.conflictColumns))
+	for i, c := range u.conflictColumns {
+		on[i] = fmt.Sprintf("target.%v = source.%v", c, c)
+	}
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = "source." + c
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "MERGE INTO %v USING %v ON (%v)", table, source, strings.Join(on, " AND "))
+	if len(u.updateColumns) > 0 {
+		sets := make([]string, len(u.updateColumns))
+		for i, c := range u.updateColumns {
+			sets[i] = fmt.Sprintf("target.%v = source.%v", c, c)
+		}
+		fmt.Fprintf(&b, " WHEN MATCHED THEN UPDATE SET %v", strings.Join(sets, ", "))
+	}
+	fmt.Fprintf(&b, " WHEN NOT MATCHED THEN INSERT (%v) VALUES (%v)", strings.Join(columns, ", "), strings.Join(values, ", "))
+	if driver == "mssql" {
+		// SQL Server requires MERGE statements to be terminated.
+		b.WriteString(";")
+	}
+	return b.String(), nil
+}
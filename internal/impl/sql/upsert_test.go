@@ -0,0 +1,174 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func TestSQLInsertUpsertStatements(t *testing.T) {
+	tests := []struct {
+		name      string
+		driver    string
+		upsert    string
+		statement string
+	}{
+		{
+			name:      "postgres",
+			driver:    "postgres",
+			upsert:    `{ conflict_columns: [ id ] }`,
+			statement: "INSERT INTO foo (id,name,age) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, age = EXCLUDED.age",
+		},
+		{
+			name:      "sqlite update columns",
+			driver:    "sqlite",
+			upsert:    `{ conflict_columns: [ id ], update_columns: [ age ] }`,
+			statement: "INSERT INTO foo (id,name,age) VALUES (?,?,?) ON CONFLICT (id) DO UPDATE SET age = EXCLUDED.age",
+		},
+		{
+			name:      "postgres all conflict columns",
+			driver:    "postgres",
+			upsert:    `{ conflict_columns: [ id, name, age ] }`,
+			statement: "INSERT INTO foo (id,name,age) VALUES ($1,$2,$3) ON CONFLICT (id, name, age) DO NOTHING",
+		},
+		{
+			name:      "mysql",
+			driver:    "mysql",
+			upsert:    `{ conflict_columns: [ id ] }`,
+			statement: "INSERT INTO foo (id,name,age) VALUES (?,?,?) ON DUPLICATE KEY UPDATE name = VALUES(name), age = VALUES(age)",
+		},
+		{
+			name:      "mysql all conflict columns",
+			driver:    "mysql",
+			upsert:    `{ conflict_columns: [ id, name, age ] }`,
+			statement: "INSERT INTO foo (id,name,age) VALUES (?,?,?) ON DUPLICATE KEY UPDATE id = id",
+		},
+		{
+			name:      "mssql",
+			driver:    "mssql",
+			upsert:    `{ conflict_columns: [ id ] }`,
+			statement: "MERGE INTO foo AS target USING (VALUES (?, ?, ?)) AS source (id, name, age) ON (target.id = source.id) WHEN MATCHED THEN UPDATE SET target.name = source.name, target.age = source.age WHEN NOT MATCHED THEN INSERT (id, name, age) VALUES (source.id, source.name, source.age);",
+		},
+		{
+			name:      "oracle",
+			driver:    "oracle",
+			upsert:    `{ conflict_columns: [ id, name ] }`,
+			statement: "MERGE INTO foo target USING (SELECT :1 AS id, :2 AS name, :3 AS age FROM dual) source ON (target.id = source.id AND target.name = source.name) WHEN MATCHED THEN UPDATE SET target.age = source.age WHEN NOT MATCHED THEN INSERT (id, name, age) VALUES (source.id, source.name, source.age)",
+		},
+		{
+			name:      "oracle all conflict columns",
+			driver:    "oracle",
+			upsert:    `{ conflict_columns: [ id, name, age ] }`,
+			statement: "MERGE INTO foo target USING (SELECT :1 AS id, :2 AS name, :3 AS age FROM dual) source ON (target.id = source.id AND target.name = source.name AND target.age = source.age) WHEN NOT MATCHED THEN INSERT (id, name, age) VALUES (source.id, source.name, source.age)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := sqlInsertOutputConfig().ParseYAML(`
+driver: `+test.driver+`
+dsn: foo
+table: foo
+columns: [ id, name, age ]
+args_mapping: 'root = [ this.id, this.name, this.age ]'
+upsert: `+test.upsert+`
+`, nil)
+			require.NoError(t, err)
+
+			s, err := newSQLInsertOutputFromConfig(conf, service.MockResources())
+			require.NoError(t, err)
+
+			if s.mergeStmt != "" {
+				assert.True(t, s.useTxStmt)
+				assert.Equal(t, test.statement, s.mergeStmt)
+				return
+			}
+			sqlStr, _, err := s.builder.Values(1, 2, 3).ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, test.statement, sqlStr)
+		})
+	}
+}
+
+func TestSQLInsertUpsertErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		errContains string
+	}{
+		{
+			name: "unsupported driver",
+			config: `
+driver: clickhouse
+upsert: { conflict_columns: [ id ] }
+`,
+			errContains: "not supported by the clickhouse driver",
+		},
+		{
+			name: "unknown conflict column",
+			config: `
+driver: postgres
+upsert: { conflict_columns: [ nope ] }
+`,
+			errContains: "conflict column nope is not an inserted column",
+		},
+		{
+			name: "update conflict column",
+			config: `
+driver: postgres
+upsert: { conflict_columns: [ id ], update_columns: [ id ] }
+`,
+			errContains: "update column id is also a conflict column",
+		},
+		{
+			name: "suffix",
+			config: `
+driver: mysql
+suffix: ON DUPLICATE KEY UPDATE name = name
+upsert: { conflict_columns: [ id ] }
+`,
+			errContains: "suffix cannot be combined with upsert",
+		},
+		{
+			name: "merge prefix",
+			config: `
+driver: mssql
+prefix: WITH foo AS (SELECT 1)
+upsert: { conflict_columns: [ id ] }
+`,
+			errContains: "cannot be combined with upsert for the mssql driver",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf, err := sqlInsertOutputConfig().ParseYAML(test.config+`
+dsn: foo
+table: foo
+columns: [ id, name, age ]
+args_mapping: 'root = [ this.id, this.name, this.age ]'
+`, nil)
+			require.NoError(t, err)
+
+			_, err = newSQLInsertOutputFromConfig(conf, service.MockResources())
+			require.ErrorContains(t, err, test.errContains)
+		})
+	}
+}
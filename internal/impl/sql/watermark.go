@@ -0,0 +1,112 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// watermark is the value of a watermark column in the serialised form it is
+// stored in a cache, which retains the type of the column so that the value
+// can be compared against the column in a query.
+type watermark struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+const (
+	watermarkTypeInt       = "int"
+	watermarkTypeUint      = "uint"
+	watermarkTypeFloat     = "float"
+	watermarkTypeTimestamp = "timestamp"
+	watermarkTypeString    = "string"
+)
+
+func newWatermark(v any) (watermark, error) {
+	switch t := v.(type) {
+	case int:
+		return watermark{Type: watermarkTypeInt, Value: strconv.FormatInt(int64(t), 10)}, nil
+	case int8:
+		return watermark{Type: watermarkTypeInt, Value: strconv.FormatInt(int64(t), 10)}, nil
+	case int16:
+		return watermark{Type: watermarkTypeInt, Value: strconv.FormatInt(int64(t), 10)}, nil
+	case int32:
+		return watermark{Type: watermarkTypeInt, Value: strconv.FormatInt(int64(t), 10)}, nil
+	case int64:
+		return watermark{Type: watermarkTypeInt, Value: strconv.FormatInt(t, 10)}, nil
+	case uint:
+		return watermark{Type: watermarkTypeUint, Value: strconv.FormatUint(uint64(t), 10)}, nil
+	case uint8:
+		return watermark{Type: watermarkTypeUint, Value: strconv.FormatUint(uint64(t), 10)}, nil
+	case uint16:
+		return watermark{Type: watermarkTypeUint, Value: strconv.FormatUint(uint64(t), 10)}, nil
+	case uint32:
+		return watermark{Type: watermarkTypeUint, Value: strconv.FormatUint(uint64(t), 10)}, nil
+	case uint64:
+		return watermark{Type: watermarkTypeUint, Value: strconv.FormatUint(t, 10)}, nil
+	case float32:
+		return watermark{Type: watermarkTypeFloat, Value: strconv.FormatFloat(float64(t), 'g', -1, 32)}, nil
+	case float64:
+		return watermark{Type: watermarkTypeFloat, Value: strconv.FormatFloat(t, 'g', -1, 64)}, nil
+	case time.Time:
+		return watermark{Type: watermarkTypeTimestamp, Value: t.Format(time.RFC3339Nano)}, nil
+	case string:
+		return watermark{Type: watermarkTypeString, Value: t}, nil
+	case nil:
+		return watermark{}, fmt.Errorf("watermark column is null")
+	}
+	return watermark{}, fmt.Errorf("watermark column has unsupported type %T", v)
+}
+
+func parseWatermark(b []byte) (watermark, error) {
+	var w watermark
+	if err := json.Unmarshal(b, &w); err != nil {
+		return w, fmt.Errorf("failed to parse watermark: %w", err)
+	}
+	if _, err := w.arg(0); err != nil {
+		return w, err
+	}
+	return w, nil
+}
+
+func (w watermark) bytes() []byte {
+	b, _ := json.Marshal(w)
+	return b
+}
+
+// arg returns the watermark as a query argument, with timestamps moved back by
+// the overlap.
+func (w watermark) arg(overlap time.Duration) (any, error) {
+	switch w.Type {
+	case watermarkTypeInt:
+		return strconv.ParseInt(w.Value, 10, 64)
+	case watermarkTypeUint:
+		return strconv.ParseUint(w.Value, 10, 64)
+	case watermarkTypeFloat:
+		return strconv.ParseFloat(w.Value, 64)
+	case watermarkTypeTimestamp:
+		t, err := time.Parse(time.RFC3339Nano, w.Value)
+		if err != nil {
+			return nil, err
+		}
+		return t.Add(-overlap), nil
+	case watermarkTypeString:
+		return w.Value, nil
+	}
+	return nil, fmt.Errorf("unknown watermark type: %v", w.Type)
+}
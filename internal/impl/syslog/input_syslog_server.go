@@ -0,0 +1,685 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	gsyslog "github.com/influxdata/go-syslog/v3"
+	"github.com/influxdata/go-syslog/v3/rfc3164"
+	"github.com/influxdata/go-syslog/v3/rfc5424"
+	"github.com/pires/go-proxyproto"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	ssiFieldNetwork        = "network"
+	ssiFieldAddress        = "address"
+	ssiFieldFormat         = "format"
+	ssiFieldMaxMessageSize = "max_message_size"
+	ssiFieldTLS            = "tls"
+	ssiFieldTLSCertFile    = "cert_file"
+	ssiFieldTLSKeyFile     = "key_file"
+	ssiFieldProxyProtocol  = "proxy_protocol"
+	ssiFieldProxyEnabled   = "enabled"
+	ssiFieldProxyRequired  = "required"
+	ssiFieldProxyTrusted   = "trusted_proxies"
+)
+
+const (
+	formatAuto    = "auto"
+	formatRFC5424 = "rfc5424"
+	formatRFC3164 = "rfc3164"
+)
+
+func syslogServerInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.48.0").
+		Summary("Receives syslog messages over UDP, TCP or TLS.").
+		Description(`
+Each syslog message received becomes a structured message containing the fields parsed from it, which may contain any of the following fields:
+
+- `+"`message`"+` (string)
+- `+"`timestamp`"+` (string, RFC3339)
+- `+"`facility`"+` (int)
+- `+"`severity`"+` (int)
+- `+"`priority`"+` (int)
+- `+"`version`"+` (int, RFC5424 only)
+- `+"`hostname`"+` (string)
+- `+"`procid`"+` (string)
+- `+"`appname`"+` (string)
+- `+"`msgid`"+` (string)
+- `+"`structureddata`"+` (object, RFC5424 only)
+
+Messages are parsed on a best effort basis, and therefore messages that deviate from the RFCs yield the fields that could be parsed. Messages that cannot be parsed at all are delivered with their raw contents and flagged as errors, which can be handled with xref:configuration:error_handling.adoc[error handling].
+
+== Framing
+
+Messages received over UDP are read from each datagram. Messages received over TCP or TLS can be framed either with octet counting, where each message is prefixed with its length, or with a newline at the end of each message, as described in https://datatracker.ietf.org/doc/html/rfc6587[RFC6587^]. The framing is detected for each message, and therefore clients are free to use either.
+
+== PROXY protocol
+
+When messages are received through a load balancer such as HAProxy or an AWS Network Load Balancer the address of the client is replaced by the address of the load balancer. Enabling `+"`proxy_protocol`"+` allows connections over TCP or TLS to start with a https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt[PROXY protocol^] v1 or v2 header, in which case the client address from the header is used for the metadata field `+"`syslog_remote_addr`"+` and the address of the load balancer is added as `+"`syslog_proxy_addr`"+`.
+
+The headers of connections from addresses that are not listed in `+"`trusted_proxies`"+` are ignored, which prevents clients from spoofing their addresses when the input is also reachable without a load balancer.
+
+== Delivery guarantees
+
+Messages are not acknowledged to clients, and therefore messages that are buffered or in flight when Redpanda Connect shuts down are lost.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- syslog_format (rfc5424 or rfc3164)
+- syslog_facility
+- syslog_severity
+- syslog_hostname
+- syslog_remote_addr
+- syslog_proxy_addr
+`+"```"+`
+
+Metadata fields are only added when their values are present in the message.
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+`).
+		Fields(
+			service.NewStringEnumField(ssiFieldNetwork, "udp", "tcp", "tls").
+				Description("The network type to listen on.").
+				Default("udp"),
+			service.NewStringField(ssiFieldAddress).
+				Description("The address to listen on.").
+				Example("0.0.0.0:514").
+				Example("0.0.0.0:6514"),
+			service.NewStringAnnotatedEnumField(ssiFieldFormat, map[string]string{
+				formatAuto:    "Detect the format of each message.",
+				formatRFC5424: "Parse messages with the https://tools.ietf.org/html/rfc5424[RFC5424^] format.",
+				formatRFC3164: "Parse messages with the https://tools.ietf.org/html/rfc3164[RFC3164^] format.",
+			}).
+				Description("The format of the messages received.").
+				Default(formatAuto),
+			service.NewIntField(ssiFieldMaxMessageSize).
+				Description("The maximum size in bytes of a message received over TCP or TLS. Connections that send larger messages are closed.").
+				Advanced().
+				Default(65536),
+			service.NewObjectField(ssiFieldTLS,
+				service.NewStringField(ssiFieldTLSCertFile).
+					Description("The certificate file to use when the network is `tls`.").
+					Default(""),
+				service.NewStringField(ssiFieldTLSKeyFile).
+					Description("The key file to use when the network is `tls`.").
+					Default(""),
+			).
+				Description("TLS specific configuration, valid when the `network` is set to `tls`.").
+				Optional(),
+			service.NewObjectField(ssiFieldProxyProtocol,
+				service.NewBoolField(ssiFieldProxyEnabled).
+					Description("Whether to accept PROXY protocol headers on connections.").
+					Default(false),
+				service.NewBoolField(ssiFieldProxyRequired).
+					Description("Whether to reject connections from trusted proxies that do not start with a PROXY protocol header.").
+					Default(false),
+				service.NewStringListField(ssiFieldProxyTrusted).
+					Description("A list of IP addresses or CIDR ranges that PROXY protocol headers are accepted from. When empty headers are accepted from all addresses.").
+					Example([]any{"10.0.0.0/8"}).
+					Default([]any{}),
+			).
+				Description("Accept https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt[PROXY protocol^] headers in order to preserve the addresses of clients connecting through a load balancer. Only valid when the `network` is `tcp` or `tls`. For more information refer to <<proxy-protocol, PROXY protocol>>.").
+				Version("4.48.0").
+				Advanced(),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Network Devices", "Receive syslog messages from network devices over TLS and forward the errors to Kafka.", `
+input:
+  syslog_server:
+    network: tls
+    address: 0.0.0.0:6514
+    tls:
+      cert_file: ./cert.pem
+      key_file: ./key.pem
+
+pipeline:
+  processors:
+    - mapping: |
+        root = if this.severity.or(7) > 3 { deleted() }
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: syslog_errors
+    key: ${! @syslog_hostname }
+`)
+}
+
+func init() {
+	err := service.RegisterInput("syslog_server", syslogServerInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			i, err := newSyslogServerInputFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, i)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type syslogServerInput struct {
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	network        string
+	address        string
+	format         string
+	maxMessageSize int
+	tlsConf        *tls.Config
+	proxyPolicy    proxyproto.PolicyFunc
+
+	msgChan chan *service.Message
+
+	listenerMut sync.Mutex
+	listener    io.Closer
+	listenAddr  net.Addr
+
+	connsMut sync.Mutex
+	conns    map[net.Conn]struct{}
+	loopsWG  sync.WaitGroup
+}
+
+func newSyslogServerInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*syslogServerInput, error) {
+	s := &syslogServerInput{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+		msgChan: make(chan *service.Message),
+		conns:   map[net.Conn]struct{}{},
+	}
+
+	var err error
+	if s.network, err = conf.FieldString(ssiFieldNetwork); err != nil {
+		return nil, err
+	}
+	if s.address, err = conf.FieldString(ssiFieldAddress); err != nil {
+		return nil, err
+	}
+	if s.format, err = conf.FieldString(ssiFieldFormat); err != nil {
+		return nil, err
+	}
+	if s.maxMessageSize, err = conf.FieldInt(ssiFieldMaxMessageSize); err != nil {
+		return nil, err
+	}
+	if s.maxMessageSize <= 0 {
+		return nil, fmt.Errorf("field %v must be greater than zero", ssiFieldMaxMessageSize)
+	}
+
+	if s.network == "tls" {
+		if !conf.Contains(ssiFieldTLS) {
+			return nil, errors.New("tls fields cert_file and key_file must be set when the network is tls")
+		}
+		tlsConf := conf.Namespace(ssiFieldTLS)
+		certFile, err := tlsConf.FieldString(ssiFieldTLSCertFile)
+		if err != nil {
+			return nil, err
+		}
+		keyFile, err := tlsConf.FieldString(ssiFieldTLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if certFile == "" || keyFile == "" {
+			return nil, errors.New("tls fields cert_file and key_file must be set when the network is tls")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.tlsConf = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+
+	if s.proxyPolicy, err = proxyPolicyFromParsed(conf.Namespace(ssiFieldProxyProtocol)); err != nil {
+		return nil, err
+	}
+	if s.proxyPolicy != nil && s.network == "udp" {
+		return nil, errors.New("proxy_protocol requires the network tcp or tls")
+	}
+	return s, nil
+}
+
+// proxyPolicyFromParsed returns the policy of PROXY protocol headers for each
+// upstream address, or nil when the PROXY protocol is disabled.
+func proxyPolicyFromParsed(conf *service.ParsedConfig) (proxyproto.PolicyFunc, error) {
+	enabled, err := conf.FieldBool(ssiFieldProxyEnabled)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	required, err := conf.FieldBool(ssiFieldProxyRequired)
+	if err != nil {
+		return nil, err
+	}
+	trusted, err := conf.FieldStringList(ssiFieldProxyTrusted)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedPolicy := proxyproto.USE
+	if required {
+		trustedPolicy = proxyproto.REQUIRE
+	}
+	if len(trusted) == 0 {
+		return func(net.Addr) (proxyproto.Policy, error) {
+			return trustedPolicy, nil
+		}, nil
+	}
+
+	laxPolicy, err := proxyproto.LaxWhiteListPolicy(trusted)
+	if err != nil {
+		return nil, fmt.Errorf("field %v: %w", ssiFieldProxyTrusted, err)
+	}
+	return func(upstream net.Addr) (proxyproto.Policy, error) {
+		policy, err := laxPolicy(upstream)
+		if err == nil && policy == proxyproto.USE {
+			policy = trustedPolicy
+		}
+		return policy, err
+	}, nil
+}
+
+//------------------------------------------------------------------------------
+
+// syslogParser parses syslog messages of a configured format, which is not
+// safe for concurrent use and therefore each connection has its own.
+type syslogParser struct {
+	format  string
+	rfc5424 gsyslog.Machine
+	rfc3164 gsyslog.Machine
+}
+
+func newSyslogParser(format string) *syslogParser {
+	return &syslogParser{
+		format:  format,
+		rfc5424: rfc5424.NewParser(rfc5424.WithBestEffort()),
+		rfc3164: rfc3164.NewParser(
+			rfc3164.WithBestEffort(),
+			rfc3164.WithRFC3339(),
+			rfc3164.WithYear(rfc3164.CurrentYear{}),
+		),
+	}
+}
+
+// detectFormat returns rfc5424 when a message has a version following its
+// priority, and rfc3164 otherwise.
+func detectFormat(b []byte) string {
+	if i := bytes.IndexByte(b, '>'); i > 0 && b[0] == '<' {
+		rest := b[i+1:]
+		j := 0
+		for j < len(rest) && j < 3 && rest[j] >= '0' && rest[j] <= '9' {
+			j++
+		}
+		if j > 0 && rest[0] != '0' && len(rest) > j && rest[j] == ' ' {
+			return formatRFC5424
+		}
+	}
+	return formatRFC3164
+}
+
+func (p *syslogParser) parse(b []byte) (string, map[string]any, error) {
+	format := p.format
+	if format == formatAuto {
+		format = detectFormat(b)
+	}
+
+	var base *gsyslog.Base
+	obj := map[string]any{}
+	switch format {
+	case formatRFC5424:
+		res, err := p.rfc5424.Parse(b)
+		if res == nil {
+			return format, nil, err
+		}
+		msg := res.(*rfc5424.SyslogMessage)
+		base = &msg.Base
+		if msg.Version != 0 {
+			obj["version"] = msg.Version
+		}
+		if msg.StructuredData != nil {
+			structuredData := make(map[string]any, len(*msg.StructuredData))
+			for key, dataItem := range *msg.StructuredData {
+				elements := make(map[string]any, len(dataItem))
+				for itemKey, itemVal := range dataItem {
+					elements[itemKey] = itemVal
+				}
+				structuredData[key] = elements
+			}
+			obj["structureddata"] = structuredData
+		}
+	default:
+		res, err := p.rfc3164.Parse(b)
+		if res == nil {
+			return format, nil, err
+		}
+		base = &res.(*rfc3164.SyslogMessage).Base
+	}
+
+	if base.Message != nil {
+		obj["message"] = *base.Message
+	}
+	if base.Timestamp != nil {
+		obj["timestamp"] = base.Timestamp.Format(time.RFC3339Nano)
+	}
+	if base.Facility != nil {
+		obj["facility"] = *base.Facility
+	}
+	if base.Severity != nil {
+		obj["severity"] = *base.Severity
+	}
+	if base.Priority != nil {
+		obj["priority"] = *base.Priority
+	}
+	if base.Hostname != nil {
+		obj["hostname"] = *base.Hostname
+	}
+	if base.ProcID != nil {
+		obj["procid"] = *base.ProcID
+	}
+	if base.Appname != nil {
+		obj["appname"] = *base.Appname
+	}
+	if base.MsgID != nil {
+		obj["msgid"] = *base.MsgID
+	}
+	return format, obj, nil
+}
+
+func (s *syslogServerInput) newMessage(p *syslogParser, b []byte, remoteAddr, proxyAddr net.Addr) *service.Message {
+	format, obj, err := p.parse(b)
+	if err != nil {
+		msg := service.NewMessage(bytes.Clone(b))
+		msg.SetError(fmt.Errorf("failed to parse syslog message: %w", err))
+		setAddrMetadata(msg, remoteAddr, proxyAddr)
+		return msg
+	}
+
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(obj)
+	msg.MetaSetMut("syslog_format", format)
+	setAddrMetadata(msg, remoteAddr, proxyAddr)
+	if v, exists := obj["facility"]; exists {
+		msg.MetaSetMut("syslog_facility", strconv.Itoa(int(v.(uint8))))
+	}
+	if v, exists := obj["severity"]; exists {
+		msg.MetaSetMut("syslog_severity", strconv.Itoa(int(v.(uint8))))
+	}
+	if v, exists := obj["hostname"]; exists {
+		msg.MetaSetMut("syslog_hostname", v)
+	}
+	return msg
+}
+
+func setAddrMetadata(msg *service.Message, remoteAddr, proxyAddr net.Addr) {
+	msg.MetaSetMut("syslog_remote_addr", remoteAddr.String())
+	if proxyAddr != nil {
+		msg.MetaSetMut("syslog_proxy_addr", proxyAddr.String())
+	}
+}
+
+// proxyAddr returns the address of the proxy a connection was received
+// through, or nil when the connection did not start with a PROXY protocol
+// header.
+func proxyAddr(conn net.Conn) net.Addr {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	if pConn, ok := conn.(*proxyproto.Conn); ok && pConn.ProxyHeader() != nil {
+		return pConn.Raw().RemoteAddr()
+	}
+	return nil
+}
+
+func (s *syslogServerInput) send(msg *service.Message) bool {
+	select {
+	case s.msgChan <- msg:
+		return true
+	case <-s.shutSig.SoftStopChan():
+		return false
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// readFrame reads the next message of a stream, which is framed with either
+// octet counting or a trailing newline.
+func readFrame(r *bufio.Reader, maxSize int) ([]byte, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if first[0] >= '1' && first[0] <= '9' {
+		lenStr, err := r.ReadString(' ')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(lenStr[:len(lenStr)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid message length: %w", err)
+		}
+		if size > maxSize {
+			return nil, fmt.Errorf("message length %v exceeds the maximum of %v", size, maxSize)
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+
+	var frame []byte
+	for {
+		line, err := r.ReadSlice('\n')
+		frame = append(frame, line...)
+		if len(frame) > maxSize+1 {
+			return nil, fmt.Errorf("message length exceeds the maximum of %v", maxSize)
+		}
+		if err == nil {
+			break
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if errors.Is(err, io.EOF) && len(frame) > 0 {
+			break
+		}
+		return nil, err
+	}
+	return bytes.TrimRight(frame, "\r\n"), nil
+}
+
+func (s *syslogServerInput) trackConn(conn net.Conn) bool {
+	s.connsMut.Lock()
+	defer s.connsMut.Unlock()
+	if s.shutSig.IsSoftStopSignalled() {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.loopsWG.Add(1)
+	return true
+}
+
+func (s *syslogServerInput) untrackConn(conn net.Conn) {
+	s.connsMut.Lock()
+	delete(s.conns, conn)
+	s.connsMut.Unlock()
+	s.loopsWG.Done()
+}
+
+func (s *syslogServerInput) handleConn(conn net.Conn) {
+	defer s.untrackConn(conn)
+	defer conn.Close()
+
+	p := newSyslogParser(s.format)
+	r := bufio.NewReader(conn)
+	for {
+		frame, err := readFrame(r, s.maxMessageSize)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !s.shutSig.IsSoftStopSignalled() {
+				s.log.Errorf("Closing syslog connection from %v: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if len(frame) == 0 {
+			continue
+		}
+		if !s.send(s.newMessage(p, frame, conn.RemoteAddr(), proxyAddr(conn))) {
+			return
+		}
+	}
+}
+
+func (s *syslogServerInput) acceptLoop(lis net.Listener) {
+	defer s.loopsWG.Done()
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if !s.shutSig.IsSoftStopSignalled() && !errors.Is(err, net.ErrClosed) {
+				s.log.Errorf("Failed to accept syslog connection: %v", err)
+			}
+			return
+		}
+		if !s.trackConn(conn) {
+			_ = conn.Close()
+			return
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *syslogServerInput) packetLoop(conn net.PacketConn) {
+	defer s.loopsWG.Done()
+
+	p := newSyslogParser(s.format)
+	buf := make([]byte, 65536)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if !s.shutSig.IsSoftStopSignalled() && !errors.Is(err, net.ErrClosed) {
+				s.log.Errorf("Failed to read syslog datagram: %v", err)
+			}
+			return
+		}
+		frame := bytes.TrimRight(buf[:n], "\r\n\x00")
+		if len(frame) == 0 {
+			continue
+		}
+		if !s.send(s.newMessage(p, frame, addr, nil)) {
+			return
+		}
+	}
+}
+
+func (s *syslogServerInput) Connect(ctx context.Context) error {
+	s.listenerMut.Lock()
+	defer s.listenerMut.Unlock()
+	if s.listener != nil {
+		return nil
+	}
+
+	if s.network == "udp" {
+		conn, err := net.ListenPacket("udp", s.address)
+		if err != nil {
+			return err
+		}
+		s.loopsWG.Add(1)
+		go s.packetLoop(conn)
+		s.listener = conn
+		s.listenAddr = conn.LocalAddr()
+	} else {
+		lis, err := net.Listen("tcp", s.address)
+		if err != nil {
+			return err
+		}
+		if s.proxyPolicy != nil {
+			// The PROXY protocol header precedes the TLS handshake.
+			lis = &proxyproto.Listener{Listener: lis, Policy: s.proxyPolicy}
+		}
+		if s.tlsConf != nil {
+			lis = tls.NewListener(lis, s.tlsConf)
+		}
+		s.loopsWG.Add(1)
+		go s.acceptLoop(lis)
+		s.listener = lis
+		s.listenAddr = lis.Addr()
+	}
+	s.log.Infof("Receiving syslog messages over %v at: %v", s.network, s.listenAddr)
+	return nil
+}
+
+func (s *syslogServerInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	select {
+	case msg := <-s.msgChan:
+		return msg, func(ctx context.Context, err error) error {
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-s.shutSig.SoftStopChan():
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (s *syslogServerInput) Close(ctx context.Context) error {
+	s.shutSig.TriggerSoftStop()
+
+	s.listenerMut.Lock()
+	listener := s.listener
+	s.listener = nil
+	s.listenerMut.Unlock()
+
+	var err error
+	if listener != nil {
+		err = listener.Close()
+	}
+
+	s.connsMut.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.connsMut.Unlock()
+
+	closed := make(chan struct{})
+	go func() {
+		s.loopsWG.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
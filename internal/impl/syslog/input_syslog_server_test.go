@@ -0,0 +1,280 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testSyslogServerInput(t *testing.T, conf string) *syslogServerInput {
+	t.Helper()
+
+	pConf, err := syslogServerInputSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	s, err := newSyslogServerInputFromConfig(pConf, service.MockResources())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Connect(context.Background()))
+	t.Cleanup(func() {
+		require.NoError(t, s.Close(context.Background()))
+	})
+	return s
+}
+
+func readSyslogMessage(t *testing.T, s *syslogServerInput) *service.Message {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	msg, ackFn, err := s.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ackFn(ctx, nil))
+	return msg
+}
+
+func TestSyslogServerTCPFraming(t *testing.T) {
+	s := testSyslogServerInput(t, `
+network: tcp
+address: 127.0.0.1:0
+`)
+
+	conn, err := net.Dial("tcp", s.listenAddr.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	rfc5424Msg := `<165>1 2025-01-02T15:04:05.000Z host1 app 123 ID47 [exampleSDID@32473 iut="3" eventSource="App"] hello world`
+	rfc3164Msg := `<34>Oct 11 22:14:15 host2 su[42]: 'su root' failed`
+
+	_, err = conn.Write([]byte(strings.Join([]string{
+		strconv.Itoa(len(rfc5424Msg)) + " " + rfc5424Msg,
+		rfc3164Msg + "\n",
+		"10 not syslog",
+	}, "")))
+	require.NoError(t, err)
+
+	msg := readSyslogMessage(t, s)
+	require.NoError(t, msg.GetError())
+	structured, err := msg.AsStructured()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"message":   "hello world",
+		"timestamp": "2025-01-02T15:04:05Z",
+		"facility":  uint8(20),
+		"severity":  uint8(5),
+		"priority":  uint8(165),
+		"version":   uint16(1),
+		"hostname":  "host1",
+		"appname":   "app",
+		"procid":    "123",
+		"msgid":     "ID47",
+		"structureddata": map[string]any{
+			"exampleSDID@32473": map[string]any{
+				"iut":         "3",
+				"eventSource": "App",
+			},
+		},
+	}, structured)
+
+	for k, v := range map[string]string{
+		"syslog_format":   "rfc5424",
+		"syslog_facility": "20",
+		"syslog_severity": "5",
+		"syslog_hostname": "host1",
+	} {
+		actual, exists := msg.MetaGet(k)
+		assert.True(t, exists, k)
+		assert.Equal(t, v, actual, k)
+	}
+
+	msg = readSyslogMessage(t, s)
+	require.NoError(t, msg.GetError())
+	structured, err = msg.AsStructured()
+	require.NoError(t, err)
+	obj := structured.(map[string]any)
+	assert.Equal(t, "host2", obj["hostname"])
+	assert.Equal(t, "su", obj["appname"])
+	assert.Equal(t, "42", obj["procid"])
+	assert.Equal(t, "'su root' failed", obj["message"])
+	format, _ := msg.MetaGet("syslog_format")
+	assert.Equal(t, "rfc3164", format)
+
+	msg = readSyslogMessage(t, s)
+	require.Error(t, msg.GetError())
+	mBytes, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "not syslog", string(mBytes))
+}
+
+func TestSyslogServerTCPMaxMessageSize(t *testing.T) {
+	s := testSyslogServerInput(t, `
+network: tcp
+address: 127.0.0.1:0
+max_message_size: 10
+`)
+
+	conn, err := net.Dial("tcp", s.listenAddr.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("100 <34>Oct 11 22:14:15 host2 su: too long"))
+	require.NoError(t, err)
+
+	// The connection is closed without delivering a message.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+	_, err = bufio.NewReader(conn).ReadByte()
+	require.Error(t, err)
+}
+
+func TestSyslogServerUDP(t *testing.T) {
+	s := testSyslogServerInput(t, `
+network: udp
+address: 127.0.0.1:0
+format: rfc3164
+`)
+
+	conn, err := net.Dial("udp", s.listenAddr.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("<13>Feb  5 17:32:18 10.0.0.99 myapp: hello\n"))
+	require.NoError(t, err)
+
+	msg := readSyslogMessage(t, s)
+	require.NoError(t, msg.GetError())
+	structured, err := msg.AsStructured()
+	require.NoError(t, err)
+	obj := structured.(map[string]any)
+	assert.Equal(t, "10.0.0.99", obj["hostname"])
+	assert.Equal(t, "hello", obj["message"])
+
+	addr, exists := msg.MetaGet("syslog_remote_addr")
+	assert.True(t, exists)
+	assert.Equal(t, conn.LocalAddr().String(), addr)
+}
+
+func TestDetectFormat(t *testing.T) {
+	for input, exp := range map[string]string{
+		`<165>1 2025-01-02T15:04:05Z host app - - - hi`: formatRFC5424,
+		`<34>Oct 11 22:14:15 host su: hi`:               formatRFC3164,
+		`<34>10 11 22:14:15 host su: hi`:                formatRFC5424,
+		`<34>2025-01-02T15:04:05Z host su: hi`:          formatRFC3164,
+		`hello`:                                         formatRFC3164,
+	} {
+		assert.Equal(t, exp, detectFormat([]byte(input)), input)
+	}
+}
+
+func TestSyslogServerProxyProtocol(t *testing.T) {
+	s := testSyslogServerInput(t, `
+network: tcp
+address: 127.0.0.1:0
+proxy_protocol:
+  enabled: true
+  required: true
+`)
+
+	send := func(header []byte) *service.Message {
+		conn, err := net.Dial("tcp", s.listenAddr.String())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write(append(header, "<34>Oct 11 22:14:15 host su: hi\n"...))
+		require.NoError(t, err)
+		return readSyslogMessage(t, s)
+	}
+
+	msg := send([]byte("PROXY TCP4 192.0.2.10 198.51.100.1 40000 514\r\n"))
+	addr, _ := msg.MetaGet("syslog_remote_addr")
+	assert.Equal(t, "192.0.2.10:40000", addr)
+	proxy, exists := msg.MetaGet("syslog_proxy_addr")
+	assert.True(t, exists)
+	assert.Contains(t, proxy, "127.0.0.1:")
+
+	header := &proxyproto.Header{
+		Version:           2,
+		Command:           proxyproto.PROXY,
+		TransportProtocol: proxyproto.TCPv6,
+		SourceAddr:        &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 50000},
+		DestinationAddr:   &net.TCPAddr{IP: net.ParseIP("2001:db8::2"), Port: 514},
+	}
+	headerBytes, err := header.Format()
+	require.NoError(t, err)
+
+	msg = send(headerBytes)
+	addr, _ = msg.MetaGet("syslog_remote_addr")
+	assert.Equal(t, "[2001:db8::1]:50000", addr)
+
+	// Connections without a header are rejected.
+	conn, err := net.Dial("tcp", s.listenAddr.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("<34>Oct 11 22:14:15 host su: hi\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+	_, err = bufio.NewReader(conn).ReadByte()
+	require.Error(t, err)
+}
+
+func TestSyslogServerProxyProtocolUntrusted(t *testing.T) {
+	s := testSyslogServerInput(t, `
+network: tcp
+address: 127.0.0.1:0
+proxy_protocol:
+  enabled: true
+  trusted_proxies: [ 10.0.0.0/8 ]
+`)
+
+	conn, err := net.Dial("tcp", s.listenAddr.String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("PROXY TCP4 192.0.2.10 198.51.100.1 40000 514\r\n<34>Oct 11 22:14:15 host su: hi\n"))
+	require.NoError(t, err)
+
+	msg := readSyslogMessage(t, s)
+	require.NoError(t, msg.GetError())
+	addr, _ := msg.MetaGet("syslog_remote_addr")
+	assert.Equal(t, conn.LocalAddr().String(), addr)
+	_, exists := msg.MetaGet("syslog_proxy_addr")
+	assert.False(t, exists)
+}
+
+func TestSyslogServerProxyProtocolUDP(t *testing.T) {
+	pConf, err := syslogServerInputSpec().ParseYAML(`
+network: udp
+address: 127.0.0.1:0
+proxy_protocol:
+  enabled: true
+`, nil)
+	require.NoError(t, err)
+
+	_, err = newSyslogServerInputFromConfig(pConf, service.MockResources())
+	require.Error(t, err)
+}
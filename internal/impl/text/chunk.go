@@ -0,0 +1,251 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// span is a range of byte offsets within a text.
+type span struct {
+	start, end int
+}
+
+// chunk is a span of a text emitted as a message.
+type chunk struct {
+	span
+	headings string
+}
+
+// piece is a span of a text along with its length, which is combined with
+// consecutive pieces into chunks.
+type piece struct {
+	span
+	length int
+}
+
+// merge greedily combines consecutive spans into chunks that are no longer
+// than the chunk size, where each chunk begins with the trailing spans of the
+// previous chunk that fit within the overlap.
+func (c *textChunker) merge(text string, spans []span, headings string) []chunk {
+	var chunks []chunk
+	var cur []piece
+	var curLen int
+	for _, s := range spans {
+		p := piece{span: s, length: c.length(text[s.start:s.end])}
+		if len(cur) > 0 && curLen+p.length > c.size {
+			chunks = append(chunks, chunk{span: span{cur[0].start, cur[len(cur)-1].end}, headings: headings})
+			for len(cur) > 0 && (curLen > c.overlap || curLen+p.length > c.size) {
+				curLen -= cur[0].length
+				cur = cur[1:]
+			}
+		}
+		cur = append(cur, p)
+		curLen += p.length
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, chunk{span: span{cur[0].start, cur[len(cur)-1].end}, headings: headings})
+	}
+	return chunks
+}
+
+// splitRecursive splits a span by the first separator that it contains, with
+// each separator kept at the end of the preceding piece, and recursively
+// splits the pieces that are longer than the chunk size by the remaining
+// separators.
+func (c *textChunker) splitRecursive(text string, s span, separators []string) []span {
+	if s.start == s.end {
+		return nil
+	}
+	if c.length(text[s.start:s.end]) <= c.size {
+		return []span{s}
+	}
+	for i, sep := range separators {
+		if sep != "" && !strings.Contains(text[s.start:s.end], sep) {
+			continue
+		}
+		var spans []span
+		for _, p := range splitKeepSeparator(text, s, sep) {
+			spans = append(spans, c.splitRecursive(text, p, separators[i+1:])...)
+		}
+		return spans
+	}
+	// None of the separators are present, and so the span is kept whole even
+	// though it exceeds the chunk size.
+	return []span{s}
+}
+
+// splitKeepSeparator splits a span by a separator, where an empty separator
+// splits the span into individual characters.
+func splitKeepSeparator(text string, s span, sep string) []span {
+	var spans []span
+	if sep == "" {
+		for i := s.start; i < s.end; {
+			_, size := utf8.DecodeRuneInString(text[i:s.end])
+			spans = append(spans, span{i, i + size})
+			i += size
+		}
+		return spans
+	}
+	start := s.start
+	for {
+		idx := strings.Index(text[start:s.end], sep)
+		if idx < 0 {
+			break
+		}
+		end := start + idx + len(sep)
+		spans = append(spans, span{start, end})
+		start = end
+	}
+	if start < s.end {
+		spans = append(spans, span{start, s.end})
+	}
+	return spans
+}
+
+// sentenceEnd matches the punctuation and trailing whitespace at the end of a
+// sentence.
+var sentenceEnd = regexp.MustCompile(`[.!?。！？]+["'”’)\]]*(\s+|$)`)
+
+// splitSentences splits a text into sentences, where sentences longer than the
+// chunk size are split into words and then characters.
+func (c *textChunker) splitSentences(text string) []span {
+	var spans []span
+	start := 0
+	for _, m := range sentenceEnd.FindAllStringIndex(text, -1) {
+		spans = append(spans, c.splitRecursive(text, span{start, m[1]}, []string{" ", ""})...)
+		start = m[1]
+	}
+	if start < len(text) {
+		spans = append(spans, c.splitRecursive(text, span{start, len(text)}, []string{" ", ""})...)
+	}
+	return spans
+}
+
+// splitTokens splits a text into chunks of tokens, with the boundaries of
+// each chunk aligned to characters.
+func (c *textChunker) splitTokens(text string) []chunk {
+	tokens := c.tokenizer.EncodeOrdinary(text)
+	if len(tokens) == 0 {
+		return nil
+	}
+	// The byte offset of the start of each token, which are summed from the
+	// bytes of each token as a token may end partway through a character.
+	offsets := make([]int, len(tokens)+1)
+	for i, t := range tokens {
+		offsets[i+1] = offsets[i] + len(c.tokenizer.Decode([]int{t}))
+	}
+
+	var chunks []chunk
+	for start := 0; ; start += c.size - c.overlap {
+		end := min(start+c.size, len(tokens))
+		chunks = append(chunks, chunk{span: span{
+			start: runeStart(text, offsets[start]),
+			end:   runeEnd(text, offsets[end]),
+		}})
+		if end == len(tokens) {
+			return chunks
+		}
+	}
+}
+
+// runeStart moves an offset back to the start of the character it's within.
+func runeStart(text string, i int) int {
+	for i > 0 && i < len(text) && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
+
+// runeEnd moves an offset forward to the end of the character it's within.
+func runeEnd(text string, i int) int {
+	for i < len(text) && !utf8.RuneStart(text[i]) {
+		i++
+	}
+	return min(i, len(text))
+}
+
+func dropBlankChunks(text string, chunks []chunk) []chunk {
+	out := chunks[:0]
+	for _, ch := range chunks {
+		if strings.TrimFunc(text[ch.start:ch.end], unicode.IsSpace) != "" {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+// markdownSection is a span of a Markdown document that begins with a heading,
+// along with the headings that it's nested within.
+type markdownSection struct {
+	span
+	headings string
+}
+
+var markdownHeading = regexp.MustCompile(`^ {0,3}(#{1,6})[ \t]+(.*?)[ \t#]*$`)
+
+// markdownSections splits a Markdown document into sections at each ATX
+// heading that isn't within a fenced code block.
+func markdownSections(text string) []markdownSection {
+	var sections []markdownSection
+	var path []string
+	var levels []int
+	sectionStart, sectionHeadings := 0, ""
+	var fence string
+
+	for lineStart := 0; lineStart < len(text); {
+		lineEnd := strings.IndexByte(text[lineStart:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(text)
+		} else {
+			lineEnd += lineStart + 1
+		}
+		line := strings.TrimRight(text[lineStart:lineEnd], "\r\n")
+
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"):
+			fence = "```"
+		case strings.HasPrefix(trimmed, "~~~"):
+			fence = "~~~"
+		default:
+			if m := markdownHeading.FindStringSubmatch(line); m != nil {
+				if lineStart > sectionStart {
+					sections = append(sections, markdownSection{span{sectionStart, lineStart}, sectionHeadings})
+				}
+				level := len(m[1])
+				for len(levels) > 0 && levels[len(levels)-1] >= level {
+					levels = levels[:len(levels)-1]
+					path = path[:len(path)-1]
+				}
+				levels = append(levels, level)
+				path = append(path, m[2])
+				sectionStart, sectionHeadings = lineStart, strings.Join(path, " > ")
+			}
+		}
+		lineStart = lineEnd
+	}
+	if sectionStart < len(text) {
+		sections = append(sections, markdownSection{span{sectionStart, len(text)}, sectionHeadings})
+	}
+	return sections
+}
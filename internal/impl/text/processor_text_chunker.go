@@ -0,0 +1,237 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	tcFieldStrategy   = "strategy"
+	tcFieldChunkSize  = "chunk_size"
+	tcFieldOverlap    = "chunk_overlap"
+	tcFieldSizeUnit   = "size_unit"
+	tcFieldSeparators = "separators"
+	tcFieldEncoding   = "encoding"
+)
+
+const (
+	strategyTokens    = "tokens"
+	strategySentences = "sentences"
+	strategyRecursive = "recursive"
+	strategyMarkdown  = "markdown"
+
+	sizeUnitCharacters = "characters"
+	sizeUnitTokens     = "tokens"
+)
+
+func init() {
+	// Use the encodings embedded within the binary rather than downloading
+	// them at runtime.
+	tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+
+	err := service.RegisterProcessor("text_chunker", textChunkerSpec(), newTextChunkerFromConfig)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func textChunkerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Categories("AI", "Parsing").
+		Summary("Splits the text of a message into chunks, emitting a message for each chunk.").
+		Description(`
+This processor splits documents into chunks that are small enough to generate embeddings for, as is commonly required when ingesting documents for retrieval augmented generation (RAG). Each chunk is at most `+"`"+tcFieldChunkSize+"`"+` long, measured in the `+"`"+tcFieldSizeUnit+"`"+`, and the text of each chunk is an exact substring of the original message. Chunks that only contain whitespace are dropped, and so an empty message results in no messages.
+
+The following strategies are supported:
+
+- `+"`tokens`"+`: Splits the text into chunks of `+"`"+tcFieldChunkSize+"`"+` tokens, regardless of the structure of the text.
+- `+"`sentences`"+`: Splits the text into sentences, and combines consecutive sentences into chunks. Sentences that are too long on their own are split into words.
+- `+"`recursive`"+`: Splits the text by the first of the `+"`"+tcFieldSeparators+"`"+` that it contains, and recursively splits each piece that is too long by the next separator. The pieces are then combined into chunks.
+- `+"`markdown`"+`: Splits the text into sections by its headings, and then splits each section with the `+"`recursive`"+` strategy. Chunks never span multiple sections.
+
+When `+"`"+tcFieldOverlap+"`"+` is set, each chunk begins with up to that much of the end of the previous chunk, so that context isn't lost at the boundaries of chunks.
+
+== Metadata
+
+The following metadata fields are added to each chunk, along with the metadata of the original message:
+
+`+"```text"+`
+- chunk_index
+- chunk_count
+- chunk_offset
+- chunk_headings
+`+"```"+`
+
+The `+"`chunk_offset`"+` is the byte offset of the chunk within the original message. The `+"`chunk_headings`"+` field is only added by the `+"`markdown`"+` strategy, and contains the headings of the section of the chunk, separated by `+"` > `"+`.`).
+		Version("4.48.0").
+		Fields(
+			service.NewStringEnumField(tcFieldStrategy, strategyTokens, strategySentences, strategyRecursive, strategyMarkdown).
+				Description("The strategy to split the text with.").
+				Default(strategyRecursive),
+			service.NewIntField(tcFieldChunkSize).
+				Description("The maximum size of each chunk.").
+				Example(512).
+				LintRule(`root = if this < 1 { ["field must be greater than or equal to 1"] }`),
+			service.NewIntField(tcFieldOverlap).
+				Description("The maximum size of the end of each chunk to repeat at the start of the next chunk. This must be less than the `"+tcFieldChunkSize+"`.").
+				Default(0).
+				LintRule(`root = if this < 0 { ["field must be greater than or equal to 0"] }`),
+			service.NewStringEnumField(tcFieldSizeUnit, sizeUnitCharacters, sizeUnitTokens).
+				Description("The unit that the `"+tcFieldChunkSize+"` and `"+tcFieldOverlap+"` are measured in. The `tokens` strategy always measures chunks in tokens.").
+				Default(sizeUnitCharacters),
+			service.NewStringListField(tcFieldSeparators).
+				Description("The separators to split text by with the `recursive` and `markdown` strategies, in order of preference. An empty separator splits text into individual characters.").
+				Default([]any{"\n\n", "\n", " ", ""}).
+				Advanced(),
+			service.NewStringField(tcFieldEncoding).
+				Description("The tokenizer encoding used to count tokens.").
+				Default("cl100k_base").
+				Examples("cl100k_base", "o200k_base").
+				Advanced(),
+		).
+		Example(
+			"Chunk Markdown documents for embeddings",
+			"Split Markdown files into chunks of at most 256 tokens with some overlap, and generate an embedding of each chunk.",
+			`
+input:
+  file:
+    paths: [ ./docs/**/*.md ]
+    scanner:
+      to_the_end: {}
+pipeline:
+  processors:
+    - text_chunker:
+        strategy: markdown
+        chunk_size: 256
+        chunk_overlap: 32
+        size_unit: tokens
+    - branch:
+        request_map: 'root = content()'
+        processors:
+          - ollama_embeddings:
+              model: nomic-embed-text
+        result_map: 'root.embedding = this'
+    - mapping: |
+        root.embedding = this.embedding
+        root.text = content().string()
+        root.source = @path
+        root.headings = @chunk_headings
+`)
+}
+
+type textChunker struct {
+	strategy   string
+	size       int
+	overlap    int
+	separators []string
+	tokenizer  *tiktoken.Tiktoken
+	length     func(string) int
+}
+
+func newTextChunkerFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+	var c textChunker
+	var err error
+	if c.strategy, err = conf.FieldString(tcFieldStrategy); err != nil {
+		return nil, err
+	}
+	if c.size, err = conf.FieldInt(tcFieldChunkSize); err != nil {
+		return nil, err
+	}
+	if c.overlap, err = conf.FieldInt(tcFieldOverlap); err != nil {
+		return nil, err
+	}
+	if c.size < 1 {
+		return nil, fmt.Errorf("field `%s` must be greater than zero", tcFieldChunkSize)
+	}
+	if c.overlap < 0 || c.overlap >= c.size {
+		return nil, fmt.Errorf("field `%s` must be at least zero and less than `%s`", tcFieldOverlap, tcFieldChunkSize)
+	}
+	if c.separators, err = conf.FieldStringList(tcFieldSeparators); err != nil {
+		return nil, err
+	}
+	unit, err := conf.FieldString(tcFieldSizeUnit)
+	if err != nil {
+		return nil, err
+	}
+	encoding, err := conf.FieldString(tcFieldEncoding)
+	if err != nil {
+		return nil, err
+	}
+	if unit == sizeUnitTokens || c.strategy == strategyTokens {
+		if c.tokenizer, err = tiktoken.GetEncoding(encoding); err != nil {
+			return nil, fmt.Errorf("failed to load encoding %s: %w", encoding, err)
+		}
+	}
+	if unit == sizeUnitTokens {
+		c.length = func(s string) int { return len(c.tokenizer.EncodeOrdinary(s)) }
+	} else {
+		c.length = utf8.RuneCountInString
+	}
+	return &c, nil
+}
+
+func (c *textChunker) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	b, err := msg.AsBytes()
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(b) {
+		return nil, errors.New("message contained invalid UTF-8")
+	}
+	text := string(b)
+
+	var chunks []chunk
+	switch c.strategy {
+	case strategyTokens:
+		chunks = c.splitTokens(text)
+	case strategySentences:
+		chunks = c.merge(text, c.splitSentences(text), "")
+	case strategyRecursive:
+		chunks = c.merge(text, c.splitRecursive(text, span{0, len(text)}, c.separators), "")
+	case strategyMarkdown:
+		for _, sec := range markdownSections(text) {
+			chunks = append(chunks, c.merge(text, c.splitRecursive(text, sec.span, c.separators), sec.headings)...)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported strategy: %s", c.strategy)
+	}
+	chunks = dropBlankChunks(text, chunks)
+
+	batch := make(service.MessageBatch, 0, len(chunks))
+	for i, ch := range chunks {
+		out := msg.Copy()
+		out.SetBytes([]byte(text[ch.start:ch.end]))
+		out.MetaSetMut("chunk_index", i)
+		out.MetaSetMut("chunk_count", len(chunks))
+		out.MetaSetMut("chunk_offset", ch.start)
+		if c.strategy == strategyMarkdown {
+			out.MetaSetMut("chunk_headings", ch.headings)
+		}
+		batch = append(batch, out)
+	}
+	return batch, nil
+}
+
+func (c *textChunker) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package text
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chunkResult struct {
+	text     string
+	offset   int
+	headings string
+}
+
+func runChunker(t *testing.T, yaml, input string) []chunkResult {
+	t.Helper()
+	conf, err := textChunkerSpec().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+	proc, err := newTextChunkerFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+
+	in := service.NewMessage([]byte(input))
+	in.MetaSetMut("path", "doc.md")
+	batch, err := proc.Process(context.Background(), in)
+	require.NoError(t, err)
+
+	var results []chunkResult
+	for i, msg := range batch {
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		res := chunkResult{text: string(b)}
+
+		v, ok := msg.MetaGetMut("chunk_index")
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+		v, ok = msg.MetaGetMut("chunk_count")
+		require.True(t, ok)
+		assert.Equal(t, len(batch), v)
+		v, ok = msg.MetaGetMut("chunk_offset")
+		require.True(t, ok)
+		res.offset = v.(int)
+		assert.Equal(t, res.text, input[res.offset:res.offset+len(res.text)])
+		if v, ok := msg.MetaGetMut("chunk_headings"); ok {
+			res.headings = v.(string)
+		}
+		p, _ := msg.MetaGet("path")
+		assert.Equal(t, "doc.md", p)
+
+		results = append(results, res)
+	}
+	return results
+}
+
+func chunkTexts(results []chunkResult) []string {
+	var texts []string
+	for _, r := range results {
+		texts = append(texts, r.text)
+	}
+	return texts
+}
+
+func TestTextChunkerRecursive(t *testing.T) {
+	input := "First paragraph is here.\n\nSecond paragraph is a little longer than the first.\n\nThird."
+	res := runChunker(t, `
+chunk_size: 30
+`, input)
+	assert.Equal(t, []string{
+		"First paragraph is here.\n\n",
+		"Second paragraph is a little ",
+		"longer than the first.\n\nThird.",
+	}, chunkTexts(res))
+	assert.Equal(t, 0, res[0].offset)
+	assert.Equal(t, strings.Index(input, "Second"), res[1].offset)
+}
+
+func TestTextChunkerRecursiveOverlap(t *testing.T) {
+	res := runChunker(t, `
+chunk_size: 12
+chunk_overlap: 6
+separators: [ " " ]
+`, "aa bb cc dd ee ff gg")
+	assert.Equal(t, []string{
+		"aa bb cc dd ",
+		"cc dd ee ff ",
+		"ee ff gg",
+	}, chunkTexts(res))
+}
+
+func TestTextChunkerSentences(t *testing.T) {
+	res := runChunker(t, `
+strategy: sentences
+chunk_size: 40
+`, "The cat sat. The dog barked loudly! Did the bird sing? It did.")
+	assert.Equal(t, []string{
+		"The cat sat. The dog barked loudly! ",
+		"Did the bird sing? It did.",
+	}, chunkTexts(res))
+}
+
+func TestTextChunkerSentencesLong(t *testing.T) {
+	res := runChunker(t, `
+strategy: sentences
+chunk_size: 10
+`, "One two three four five. Six.")
+	assert.Equal(t, []string{
+		"One two ",
+		"three ",
+		"four ",
+		"five. Six.",
+	}, chunkTexts(res))
+}
+
+func TestTextChunkerTokens(t *testing.T) {
+	input := "one two three four five six seven"
+	res := runChunker(t, `
+strategy: tokens
+chunk_size: 3
+chunk_overlap: 1
+`, input)
+	assert.Equal(t, []string{
+		"one two three",
+		" three four five",
+		" five six seven",
+	}, chunkTexts(res))
+}
+
+func TestTextChunkerTokensMultibyte(t *testing.T) {
+	input := strings.Repeat("日本語のテキスト", 10)
+	res := runChunker(t, `
+strategy: tokens
+chunk_size: 5
+`, input)
+	require.NotEmpty(t, res)
+	var joined strings.Builder
+	for _, r := range res {
+		joined.WriteString(r.text)
+	}
+	// Chunks are aligned to characters, and so may overlap by a character at
+	// most when a token boundary falls within a character.
+	assert.GreaterOrEqual(t, joined.Len(), len(input))
+	assert.Equal(t, input[:len(res[0].text)], res[0].text)
+}
+
+func TestTextChunkerTokenSizeUnit(t *testing.T) {
+	res := runChunker(t, `
+chunk_size: 4
+size_unit: tokens
+separators: [ " " ]
+`, "one two three four five six seven")
+	assert.Equal(t, []string{
+		"one two ",
+		"three four ",
+		"five six ",
+		"seven",
+	}, chunkTexts(res))
+}
+
+func TestTextChunkerMarkdown(t *testing.T) {
+	input := `Intro text.
+
+# Setup
+
+Install it.
+
+## Linux
+
+` + "```sh\n# not a heading\napt install foo\n```" + `
+
+# Usage
+
+Run it.
+`
+	res := runChunker(t, `
+strategy: markdown
+chunk_size: 1000
+`, input)
+	assert.Equal(t, []chunkResult{
+		{text: "Intro text.\n\n", offset: 0, headings: ""},
+		{text: "# Setup\n\nInstall it.\n\n", offset: strings.Index(input, "# Setup"), headings: "Setup"},
+		{text: "## Linux\n\n```sh\n# not a heading\napt install foo\n```\n\n", offset: strings.Index(input, "## Linux"), headings: "Setup > Linux"},
+		{text: "# Usage\n\nRun it.\n", offset: strings.Index(input, "# Usage"), headings: "Usage"},
+	}, res)
+}
+
+func TestTextChunkerEmpty(t *testing.T) {
+	assert.Empty(t, runChunker(t, `chunk_size: 10`, ""))
+	assert.Empty(t, runChunker(t, `chunk_size: 10`, "  \n\n  "))
+	assert.Empty(t, runChunker(t, "strategy: tokens\nchunk_size: 10", ""))
+}
+
+func TestTextChunkerConfigErrors(t *testing.T) {
+	conf, err := textChunkerSpec().ParseYAML(`
+chunk_size: 10
+chunk_overlap: 10
+`, nil)
+	require.NoError(t, err)
+	_, err = newTextChunkerFromConfig(conf, service.MockResources())
+	require.ErrorContains(t, err, "chunk_overlap")
+}
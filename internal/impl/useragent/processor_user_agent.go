@@ -0,0 +1,215 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package useragent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ua-parser/uap-go/uaparser"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	uapFieldUserAgent   = "user_agent"
+	uapFieldResultMap   = "result_map"
+	uapFieldRegexesFile = "regexes_file"
+	uapFieldCacheSize   = "cache_size"
+)
+
+func userAgentProcessorSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Parsing").
+		Version("4.48.0").
+		Summary("Parses user agent strings into the browser, operating system and device they describe.").
+		Description(`
+The user agent string of each message is extracted with the `+"`user_agent`"+` query and parsed with the regular expressions of the https://github.com/ua-parser/uap-core[uap-core^] project, yielding a result of the following form:
+
+`+"```json"+`
+{
+  "browser": { "family": "Chrome", "major": "120", "minor": "0", "patch": "6099", "version": "120.0.6099" },
+  "os": { "family": "Mac OS X", "major": "10", "minor": "15", "patch": "7", "patch_minor": "", "version": "10.15.7" },
+  "device": { "family": "Mac", "brand": "Apple", "model": "Mac" }
+}
+`+"```"+`
+
+The result is then mapped into the message with `+"`result_map`"+`, where `+"`this`"+` refers to the result and `+"`root`"+` refers to the message being enriched. Families that are not recognised are reported as `+"`Other`"+`.
+
+The regular expressions of uap-core are built into Redpanda Connect, a more recent or customised version of the `+"`regexes.yaml`"+` file of uap-core can be used instead with the field `+"`regexes_file`"+`.
+`).
+		Fields(
+			service.NewBloblangField(uapFieldUserAgent).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang query] that extracts the user agent string to parse from each message.").
+				Example("this.user_agent").
+				Example(`@User-Agent`),
+			service.NewBloblangField(uapFieldResultMap).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that maps the result of parsing into the message, where `this` refers to the result.").
+				Example(`root.browser = this.browser.family
+root.os = this.os.family
+root.device = this.device.family`).
+				Default("root.user_agent_details = this"),
+			service.NewStringField(uapFieldRegexesFile).
+				Description("An optional path to a uap-core `regexes.yaml` file to use instead of the built in regular expressions.").
+				Advanced().
+				Default(""),
+			service.NewIntField(uapFieldCacheSize).
+				Description("The number of parsed user agent strings to cache, which avoids parsing common user agents repeatedly.").
+				Advanced().
+				Default(1000),
+		).
+		Example("Clickstream Enrichment", "Add the browser and operating system of the user agent header to HTTP requests.", `
+input:
+  http_server:
+    path: /events
+
+pipeline:
+  processors:
+    - user_agent:
+        user_agent: '@User-Agent'
+        result_map: |
+          root.browser = this.browser.family
+          root.browser_version = this.browser.version
+          root.os = this.os.family
+          root.device = this.device.family
+`)
+}
+
+func init() {
+	err := service.RegisterProcessor("user_agent", userAgentProcessorSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Processor, error) {
+			return newUserAgentProcessorFromConfig(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type userAgentProcessor struct {
+	parser    *uaparser.Parser
+	userAgent *bloblang.Executor
+	resultMap *bloblang.Executor
+}
+
+func newUserAgentProcessorFromConfig(conf *service.ParsedConfig) (*userAgentProcessor, error) {
+	u := &userAgentProcessor{}
+
+	var err error
+	if u.userAgent, err = conf.FieldBloblang(uapFieldUserAgent); err != nil {
+		return nil, err
+	}
+	if u.resultMap, err = conf.FieldBloblang(uapFieldResultMap); err != nil {
+		return nil, err
+	}
+
+	cacheSize, err := conf.FieldInt(uapFieldCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	opts := []uaparser.Option{uaparser.WithCacheSize(cacheSize)}
+
+	regexesFile, err := conf.FieldString(uapFieldRegexesFile)
+	if err != nil {
+		return nil, err
+	}
+	if regexesFile != "" {
+		regexesBytes, err := os.ReadFile(regexesFile)
+		if err != nil {
+			return nil, err
+		}
+		var def uaparser.RegexDefinitions
+		if err := yaml.Unmarshal(regexesBytes, &def); err != nil {
+			return nil, fmt.Errorf("failed to parse regexes file: %w", err)
+		}
+		opts = append(opts, uaparser.WithRegexDefinitions(def))
+	}
+
+	if u.parser, err = uaparser.New(opts...); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func joinVersion(parts ...string) string {
+	var version []string
+	for _, p := range parts {
+		if p == "" {
+			break
+		}
+		version = append(version, p)
+	}
+	return strings.Join(version, ".")
+}
+
+func (u *userAgentProcessor) parse(ua string) map[string]any {
+	client := u.parser.Parse(ua)
+	return map[string]any{
+		"browser": map[string]any{
+			"family":  client.UserAgent.Family,
+			"major":   client.UserAgent.Major,
+			"minor":   client.UserAgent.Minor,
+			"patch":   client.UserAgent.Patch,
+			"version": joinVersion(client.UserAgent.Major, client.UserAgent.Minor, client.UserAgent.Patch),
+		},
+		"os": map[string]any{
+			"family":      client.Os.Family,
+			"major":       client.Os.Major,
+			"minor":       client.Os.Minor,
+			"patch":       client.Os.Patch,
+			"patch_minor": client.Os.PatchMinor,
+			"version":     joinVersion(client.Os.Major, client.Os.Minor, client.Os.Patch, client.Os.PatchMinor),
+		},
+		"device": map[string]any{
+			"family": client.Device.Family,
+			"brand":  client.Device.Brand,
+			"model":  client.Device.Model,
+		},
+	}
+}
+
+func (u *userAgentProcessor) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	res, err := msg.BloblangQuery(u.userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("user agent query failed: %w", err)
+	}
+	if res == nil {
+		return nil, errors.New("user agent query deleted the message")
+	}
+	uaBytes, err := res.AsBytes()
+	if err != nil {
+		return nil, fmt.Errorf("user agent query failed: %w", err)
+	}
+
+	resultMsg := msg.Copy()
+	resultMsg.SetStructuredMut(u.parse(string(uaBytes)))
+
+	if msg, err = msg.BloblangMutateFrom(u.resultMap, resultMsg); err != nil {
+		return nil, fmt.Errorf("result map failed: %w", err)
+	}
+	if msg == nil {
+		return nil, nil
+	}
+	return service.MessageBatch{msg}, nil
+}
+
+func (u *userAgentProcessor) Close(ctx context.Context) error {
+	return nil
+}
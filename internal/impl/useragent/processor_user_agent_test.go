@@ -0,0 +1,114 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package useragent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testUserAgentProcessor(t *testing.T, conf string) *userAgentProcessor {
+	t.Helper()
+
+	pConf, err := userAgentProcessorSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	p, err := newUserAgentProcessorFromConfig(pConf)
+	require.NoError(t, err)
+	return p
+}
+
+func TestUserAgentProcessor(t *testing.T) {
+	p := testUserAgentProcessor(t, `
+user_agent: this.ua
+`)
+
+	batch, err := p.Process(context.Background(), service.NewMessage([]byte(`{"ua":"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36"}`)))
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	structured, err := batch[0].AsStructured()
+	require.NoError(t, err)
+	details := structured.(map[string]any)["user_agent_details"].(map[string]any)
+
+	assert.Equal(t, map[string]any{
+		"family":  "Chrome",
+		"major":   "120",
+		"minor":   "0",
+		"patch":   "6099",
+		"version": "120.0.6099",
+	}, details["browser"])
+	assert.Equal(t, "Mac OS X", details["os"].(map[string]any)["family"])
+	assert.Equal(t, "10.15.7", details["os"].(map[string]any)["version"])
+	assert.Equal(t, "Apple", details["device"].(map[string]any)["brand"])
+}
+
+func TestUserAgentProcessorResultMap(t *testing.T) {
+	p := testUserAgentProcessor(t, `
+user_agent: '@ua'
+result_map: |
+  root.browser = this.browser.family
+  root.device = this.device.family
+`)
+
+	msg := service.NewMessage([]byte(`{"id":1}`))
+	msg.MetaSetMut("ua", "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1")
+
+	batch, err := p.Process(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+
+	mBytes, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"browser":"Mobile Safari","device":"iPhone"}`, string(mBytes))
+
+	batch, err = p.Process(context.Background(), service.NewMessage([]byte(`{}`)))
+	require.NoError(t, err)
+	mBytes, err = batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"browser":"Other","device":"Other"}`, string(mBytes))
+}
+
+func TestUserAgentProcessorRegexesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regexes.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+user_agent_parsers:
+  - regex: '(AcmeBot)/(\d+)\.(\d+)'
+    family_replacement: 'Acme Bot'
+os_parsers: []
+device_parsers:
+  - regex: 'AcmeBot'
+    device_replacement: 'Spider'
+`), 0o644))
+
+	p := testUserAgentProcessor(t, `
+user_agent: content()
+regexes_file: `+path+`
+result_map: root = this.browser.version + " " + this.device.family
+`)
+
+	batch, err := p.Process(context.Background(), service.NewMessage([]byte(`AcmeBot/2.1`)))
+	require.NoError(t, err)
+	mBytes, err := batch[0].AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "2.1 Spider", string(mBytes))
+}
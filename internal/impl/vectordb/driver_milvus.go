@@ -0,0 +1,114 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	milvusIDField     = "id"
+	milvusVectorField = "vector"
+	milvusIDMaxLength = 512
+)
+
+// milvusDriver writes entities to Milvus, see
+// https://milvus.io/api-reference/restful/v2.5.x/About.md.
+type milvusDriver struct {
+	client  *restClient
+	headers map[string]string
+}
+
+func newMilvusDriver(client *restClient, apiKey string) *milvusDriver {
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+	return &milvusDriver{client: client, headers: headers}
+}
+
+// milvusResponse is the envelope of all Milvus responses, which report errors
+// with a code rather than the status of the response.
+type milvusResponse[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+func (r *milvusResponse[T]) err() error {
+	if r.Code != 0 {
+		return fmt.Errorf("milvus returned error code %d: %s", r.Code, r.Message)
+	}
+	return nil
+}
+
+func (m *milvusDriver) collectionExists(ctx context.Context, collection string) (bool, error) {
+	var resp milvusResponse[struct {
+		Has bool `json:"has"`
+	}]
+	if err := m.client.do(ctx, http.MethodPost, "/v2/vectordb/collections/has", m.headers, map[string]any{"collectionName": collection}, &resp); err != nil {
+		return false, err
+	}
+	return resp.Data.Has, resp.err()
+}
+
+var milvusMetrics = map[string]string{
+	metricCosine:    "COSINE",
+	metricDot:       "IP",
+	metricEuclidean: "L2",
+}
+
+func (m *milvusDriver) createCollection(ctx context.Context, collection string, dims int, metric string) error {
+	body := map[string]any{
+		"collectionName":   collection,
+		"dimension":        dims,
+		"metricType":       milvusMetrics[metric],
+		"idType":           "VarChar",
+		"primaryFieldName": milvusIDField,
+		"vectorFieldName":  milvusVectorField,
+		"params": map[string]any{
+			"max_length": milvusIDMaxLength,
+		},
+	}
+	var resp milvusResponse[any]
+	if err := m.client.do(ctx, http.MethodPost, "/v2/vectordb/collections/create", m.headers, body, &resp); err != nil {
+		return err
+	}
+	return resp.err()
+}
+
+func (m *milvusDriver) upsert(ctx context.Context, collection string, records []vectorRecord) error {
+	data := make([]map[string]any, len(records))
+	for i, r := range records {
+		entity := make(map[string]any, len(r.payload)+2)
+		for k, v := range r.payload {
+			entity[k] = v
+		}
+		entity[milvusIDField] = r.id
+		entity[milvusVectorField] = r.vector
+		data[i] = entity
+	}
+	body := map[string]any{
+		"collectionName": collection,
+		"data":           data,
+	}
+	var resp milvusResponse[any]
+	if err := m.client.do(ctx, http.MethodPost, "/v2/vectordb/entities/upsert", m.headers, body, &resp); err != nil {
+		return err
+	}
+	return resp.err()
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// The maximum number of vectors within a single Pinecone upsert request.
+const pineconeMaxUpsertVectors = 1000
+
+// pineconeDriver writes vectors to a namespace of a Pinecone index, see
+// https://docs.pinecone.io/reference/api/data-plane/upsert.
+type pineconeDriver struct {
+	client  *restClient
+	headers map[string]string
+}
+
+func newPineconeDriver(client *restClient, apiKey string) *pineconeDriver {
+	return &pineconeDriver{
+		client: client,
+		headers: map[string]string{
+			"Api-Key":                apiKey,
+			"X-Pinecone-Api-Version": "2024-07",
+		},
+	}
+}
+
+func (p *pineconeDriver) collectionExists(ctx context.Context, namespace string) (bool, error) {
+	// Namespaces are created implicitly by the first upsert to them.
+	return true, nil
+}
+
+func (p *pineconeDriver) createCollection(ctx context.Context, namespace string, dims int, metric string) error {
+	return errors.New("creating indexes isn't supported by the pinecone driver")
+}
+
+func (p *pineconeDriver) upsert(ctx context.Context, namespace string, records []vectorRecord) error {
+	for len(records) > 0 {
+		n := min(len(records), pineconeMaxUpsertVectors)
+		vectors := make([]map[string]any, n)
+		for i, r := range records[:n] {
+			v := map[string]any{
+				"id":     r.id,
+				"values": r.vector,
+			}
+			if len(r.payload) > 0 {
+				v["metadata"] = r.payload
+			}
+			vectors[i] = v
+		}
+		body := map[string]any{
+			"vectors":   vectors,
+			"namespace": namespace,
+		}
+		if err := p.client.do(ctx, http.MethodPost, "/vectors/upsert", p.headers, body, nil); err != nil {
+			return err
+		}
+		records = records[n:]
+	}
+	return nil
+}
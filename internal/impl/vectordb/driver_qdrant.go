@@ -0,0 +1,81 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectordb
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// qdrantDriver writes points to Qdrant, see
+// https://api.qdrant.tech/api-reference.
+type qdrantDriver struct {
+	client  *restClient
+	headers map[string]string
+}
+
+func newQdrantDriver(client *restClient, apiKey string) *qdrantDriver {
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Api-Key"] = apiKey
+	}
+	return &qdrantDriver{client: client, headers: headers}
+}
+
+func (q *qdrantDriver) collectionExists(ctx context.Context, collection string) (bool, error) {
+	var resp struct {
+		Result struct {
+			Exists bool `json:"exists"`
+		} `json:"result"`
+	}
+	err := q.client.do(ctx, http.MethodGet, "/collections/"+url.PathEscape(collection)+"/exists", q.headers, nil, &resp)
+	return resp.Result.Exists, err
+}
+
+var qdrantDistances = map[string]string{
+	metricCosine:    "Cosine",
+	metricDot:       "Dot",
+	metricEuclidean: "Euclid",
+}
+
+func (q *qdrantDriver) createCollection(ctx context.Context, collection string, dims int, metric string) error {
+	body := map[string]any{
+		"vectors": map[string]any{
+			"size":     dims,
+			"distance": qdrantDistances[metric],
+		},
+	}
+	return q.client.do(ctx, http.MethodPut, "/collections/"+url.PathEscape(collection), q.headers, body, nil)
+}
+
+func (q *qdrantDriver) upsert(ctx context.Context, collection string, records []vectorRecord) error {
+	points := make([]map[string]any, len(records))
+	for i, r := range records {
+		// Qdrant IDs are either unsigned integers or UUIDs.
+		var id any = r.id
+		if n, err := strconv.ParseUint(r.id, 10, 64); err == nil {
+			id = n
+		}
+		points[i] = map[string]any{
+			"id":      id,
+			"vector":  r.vector,
+			"payload": r.payload,
+		}
+	}
+	body := map[string]any{"points": points}
+	return q.client.do(ctx, http.MethodPut, "/collections/"+url.PathEscape(collection)+"/points?wait=true", q.headers, body, nil)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectordb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// weaviateIDNamespace is the namespace of the UUIDs generated for IDs that
+// aren't already UUIDs.
+var weaviateIDNamespace = uuid.Must(uuid.FromString("3a1e5d36-7f4c-4b8e-9a51-8c1f2f4b6d20"))
+
+// weaviateDriver writes objects to Weaviate, see
+// https://weaviate.io/developers/weaviate/api/rest.
+type weaviateDriver struct {
+	client  *restClient
+	headers map[string]string
+}
+
+func newWeaviateDriver(client *restClient, apiKey string) *weaviateDriver {
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+	return &weaviateDriver{client: client, headers: headers}
+}
+
+func (w *weaviateDriver) collectionExists(ctx context.Context, collection string) (bool, error) {
+	err := w.client.do(ctx, http.MethodGet, "/v1/schema/"+url.PathEscape(collection), w.headers, nil, nil)
+	var serr *statusError
+	if errors.As(err, &serr) && serr.code == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+var weaviateDistances = map[string]string{
+	metricCosine:    "cosine",
+	metricDot:       "dot",
+	metricEuclidean: "l2-squared",
+}
+
+func (w *weaviateDriver) createCollection(ctx context.Context, collection string, dims int, metric string) error {
+	body := map[string]any{
+		"class":      collection,
+		"vectorizer": "none",
+		"vectorIndexConfig": map[string]any{
+			"distance": weaviateDistances[metric],
+		},
+	}
+	return w.client.do(ctx, http.MethodPost, "/v1/schema", w.headers, body, nil)
+}
+
+type weaviateBatchResult struct {
+	ID     string `json:"id"`
+	Result struct {
+		Errors *struct {
+			Error []struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"errors"`
+	} `json:"result"`
+}
+
+func (w *weaviateDriver) upsert(ctx context.Context, collection string, records []vectorRecord) error {
+	objects := make([]map[string]any, len(records))
+	for i, r := range records {
+		objects[i] = map[string]any{
+			"class":      collection,
+			"id":         weaviateID(r.id),
+			"vector":     r.vector,
+			"properties": r.payload,
+		}
+	}
+	var results []weaviateBatchResult
+	if err := w.client.do(ctx, http.MethodPost, "/v1/batch/objects", w.headers, map[string]any{"objects": objects}, &results); err != nil {
+		return err
+	}
+	// Errors of individual objects are reported within a successful response.
+	var errs []string
+	for _, res := range results {
+		if res.Result.Errors == nil {
+			continue
+		}
+		for _, e := range res.Result.Errors.Error {
+			errs = append(errs, fmt.Sprintf("object %s: %s", res.ID, e.Message))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// weaviateID returns the ID unchanged when it's a UUID, or otherwise a UUID
+// derived from the ID.
+func weaviateID(id string) string {
+	if u, err := uuid.FromString(id); err == nil {
+		return u.String()
+	}
+	return uuid.NewV5(weaviateIDNamespace, id).String()
+}
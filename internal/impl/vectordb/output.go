@@ -0,0 +1,458 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectordb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	voFieldBatching          = "batching"
+	voFieldDriver            = "driver"
+	voFieldURL               = "url"
+	voFieldAPIKey            = "api_key"
+	voFieldTLS               = "tls"
+	voFieldTimeout           = "timeout"
+	voFieldCollection        = "collection"
+	voFieldID                = "id"
+	voFieldVectorMapping     = "vector_mapping"
+	voFieldPayloadMapping    = "payload_mapping"
+	voFieldAutoCreate        = "auto_create"
+	voAutoCreateFieldEnabled = "enabled"
+	voAutoCreateFieldMetric  = "metric"
+)
+
+const (
+	driverQdrant   = "qdrant"
+	driverPinecone = "pinecone"
+	driverWeaviate = "weaviate"
+	driverMilvus   = "milvus"
+)
+
+const (
+	metricCosine    = "cosine"
+	metricDot       = "dot"
+	metricEuclidean = "euclidean"
+)
+
+func outputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Version("4.48.0").
+		Categories("AI").
+		Summary("Upserts vectors into a vector database.").
+		Description(`
+This output writes a vector along with an ID and a payload for each message to one of the following vector databases, using the REST API of each database:
+
+- `+"`qdrant`"+`: Points are upserted into a https://qdrant.tech/[Qdrant^] collection. IDs that are unsigned integers are sent as numbers, and all other IDs must be UUIDs.
+- `+"`pinecone`"+`: Vectors are upserted into a namespace of a https://www.pinecone.io/[Pinecone^] index, where `+"`"+voFieldURL+"`"+` is the host of the index and `+"`"+voFieldCollection+"`"+` is the namespace. Pinecone namespaces are created implicitly, and indexes can't be created by this output.
+- `+"`weaviate`"+`: Objects are added to a https://weaviate.io/[Weaviate^] collection with the vectorizer disabled. Weaviate requires object IDs to be UUIDs, and so IDs that aren't UUIDs are converted into a deterministic UUID (version 5).
+- `+"`milvus`"+`: Entities are upserted into a https://milvus.io/[Milvus^] collection, which must have a `+"`VarChar`"+` primary key field named `+"`id`"+`, a vector field named `+"`vector`"+` and dynamic fields enabled, as created by `+"`"+voFieldAutoCreate+"`"+`. The fields of the payload are stored as dynamic fields.
+
+== Creating collections
+
+When `+"`"+voFieldAutoCreate+"."+voAutoCreateFieldEnabled+"`"+` is `+"`true`"+` a collection that doesn't exist is created before the first write to it, with the number of dimensions of the vectors being written and the configured distance `+"`"+voAutoCreateFieldMetric+"`"+`. This isn't supported by the `+"`pinecone`"+` driver.
+
+`+service.OutputPerformanceDocs(true, true)).
+		Fields(
+			service.NewStringEnumField(voFieldDriver, driverQdrant, driverPinecone, driverWeaviate, driverMilvus).
+				Description("The vector database to write to."),
+			service.NewURLField(voFieldURL).
+				Description("The URL of the REST API of the database. When the driver is `pinecone` this is the URL of the index host.").
+				Example("http://localhost:6333").
+				Example("https://example-index-abc123.svc.us-east-1-aws.pinecone.io").
+				Example("http://localhost:8080").
+				Example("http://localhost:19530"),
+			service.NewStringField(voFieldAPIKey).
+				Description("The API key or token to authenticate with.").
+				Secret().
+				Default(""),
+			service.NewTLSToggledField(voFieldTLS),
+			service.NewDurationField(voFieldTimeout).
+				Description("The maximum time to wait for each request to the database.").
+				Default("30s").
+				Advanced(),
+			service.NewInterpolatedStringField(voFieldCollection).
+				Description("The collection to write to. When the driver is `pinecone` this is the namespace of the index, where an empty string targets the default namespace.").
+				Example("documents").
+				Example(`${! @collection }`),
+			service.NewBloblangField(voFieldID).
+				Description("A mapping that results in the ID of the vector, which must be a string or an integer.").
+				Example(`root = @id`).
+				Example(`root = this.id`),
+			service.NewBloblangField(voFieldVectorMapping).
+				Description("A mapping that results in the vector to write, which must be an array of numbers.").
+				Example(`root = this.embedding`),
+			service.NewBloblangField(voFieldPayloadMapping).
+				Description("A mapping that results in an object of fields to store along with the vector.").
+				Default(`root = {}`).
+				Example(`root = {"text": this.text, "source": @path}`),
+			service.NewObjectField(voFieldAutoCreate,
+				service.NewBoolField(voAutoCreateFieldEnabled).
+					Description("Whether to create collections that don't exist.").
+					Default(false),
+				service.NewStringEnumField(voAutoCreateFieldMetric, metricCosine, metricDot, metricEuclidean).
+					Description("The distance metric of created collections.").
+					Default(metricCosine),
+			).
+				Description("Create collections that don't exist before writing to them.").
+				Advanced(),
+			service.NewOutputMaxInFlightField(),
+			service.NewBatchPolicyField(voFieldBatching),
+		).
+		Example(
+			"Store document chunks in Qdrant",
+			"Generate embeddings for document chunks and store them along with their text in a Qdrant collection, which is created when it doesn't exist.",
+			`
+pipeline:
+  processors:
+    - openai_embeddings:
+        model: text-embedding-3-small
+        api_key: "${OPENAI_API_KEY}"
+        text_mapping: 'root = this.text'
+        chunking:
+          enabled: true
+          max_tokens: 512
+output:
+  vector_db:
+    driver: qdrant
+    url: http://localhost:6333
+    collection: documents
+    id: 'root = uuid_v4()'
+    vector_mapping: 'root = this'
+    payload_mapping: 'root.text = @embeddings_chunk_text'
+    auto_create:
+      enabled: true
+    batching:
+      count: 100
+      period: 1s
+`)
+}
+
+func init() {
+	err := service.RegisterBatchOutput(
+		"vector_db",
+		outputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (out service.BatchOutput, batchPol service.BatchPolicy, mif int, err error) {
+			if batchPol, err = conf.FieldBatchPolicy(voFieldBatching); err != nil {
+				return
+			}
+			if mif, err = conf.FieldMaxInFlight(); err != nil {
+				return
+			}
+			if out, err = newOutputWriter(conf, mgr); err != nil {
+				return
+			}
+			return
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// vectorRecord is a vector along with its ID and payload, as written to a
+// vector database.
+type vectorRecord struct {
+	id      string
+	vector  []float32
+	payload map[string]any
+}
+
+// vectorDriver is implemented by each of the supported vector databases.
+type vectorDriver interface {
+	// collectionExists returns whether a collection exists.
+	collectionExists(ctx context.Context, collection string) (bool, error)
+	// createCollection creates a collection with the given number of vector
+	// dimensions and distance metric.
+	createCollection(ctx context.Context, collection string, dims int, metric string) error
+	// upsert writes records to a collection, replacing existing records with
+	// the same IDs.
+	upsert(ctx context.Context, collection string, records []vectorRecord) error
+}
+
+type outputWriter struct {
+	driver vectorDriver
+	log    *service.Logger
+
+	collection     *service.InterpolatedString
+	id             *bloblang.Executor
+	vectorMapping  *bloblang.Executor
+	payloadMapping *bloblang.Executor
+
+	autoCreate bool
+	metric     string
+
+	// The collections which are known to exist.
+	existsMut sync.Mutex
+	exists    map[string]struct{}
+}
+
+func newOutputWriter(conf *service.ParsedConfig, mgr *service.Resources) (*outputWriter, error) {
+	w := &outputWriter{
+		log:    mgr.Logger(),
+		exists: map[string]struct{}{},
+	}
+	var err error
+	if w.collection, err = conf.FieldInterpolatedString(voFieldCollection); err != nil {
+		return nil, err
+	}
+	if w.id, err = conf.FieldBloblang(voFieldID); err != nil {
+		return nil, err
+	}
+	if w.vectorMapping, err = conf.FieldBloblang(voFieldVectorMapping); err != nil {
+		return nil, err
+	}
+	if w.payloadMapping, err = conf.FieldBloblang(voFieldPayloadMapping); err != nil {
+		return nil, err
+	}
+	autoCreateConf := conf.Namespace(voFieldAutoCreate)
+	if w.autoCreate, err = autoCreateConf.FieldBool(voAutoCreateFieldEnabled); err != nil {
+		return nil, err
+	}
+	if w.metric, err = autoCreateConf.FieldString(voAutoCreateFieldMetric); err != nil {
+		return nil, err
+	}
+
+	driver, err := conf.FieldString(voFieldDriver)
+	if err != nil {
+		return nil, err
+	}
+	if driver == driverPinecone && w.autoCreate {
+		return nil, fmt.Errorf("field `%s` is not supported by the %s driver", voFieldAutoCreate, driver)
+	}
+	baseURL, err := conf.FieldString(voFieldURL)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := conf.FieldString(voFieldAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := conf.FieldDuration(voFieldTimeout)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf, tlsEnabled, err := conf.FieldTLSToggled(voFieldTLS)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Timeout: timeout}
+	if tlsEnabled {
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConf}
+	}
+	c := &restClient{
+		client:  httpClient,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+
+	switch driver {
+	case driverQdrant:
+		w.driver = newQdrantDriver(c, apiKey)
+	case driverPinecone:
+		w.driver = newPineconeDriver(c, apiKey)
+	case driverWeaviate:
+		w.driver = newWeaviateDriver(c, apiKey)
+	case driverMilvus:
+		w.driver = newMilvusDriver(c, apiKey)
+	default:
+		return nil, fmt.Errorf("unsupported driver: %s", driver)
+	}
+	return w, nil
+}
+
+func (w *outputWriter) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (w *outputWriter) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	records, err := w.batchRecordsByCollection(batch)
+	if err != nil {
+		return err
+	}
+	for collection, recs := range records {
+		if w.autoCreate {
+			if err := w.ensureCollection(ctx, collection, len(recs[0].vector)); err != nil {
+				return err
+			}
+		}
+		w.log.Debugf("Upserting %d vectors to collection %s", len(recs), collection)
+		if err := w.driver.upsert(ctx, collection, recs); err != nil {
+			return fmt.Errorf("failed to upsert to collection %s: %w", collection, err)
+		}
+	}
+	return nil
+}
+
+func (w *outputWriter) ensureCollection(ctx context.Context, collection string, dims int) error {
+	w.existsMut.Lock()
+	defer w.existsMut.Unlock()
+	if _, ok := w.exists[collection]; ok {
+		return nil
+	}
+	exists, err := w.driver.collectionExists(ctx, collection)
+	if err != nil {
+		return fmt.Errorf("failed to check whether collection %s exists: %w", collection, err)
+	}
+	if !exists {
+		w.log.Infof("Creating collection %s with %d dimensions", collection, dims)
+		if err := w.driver.createCollection(ctx, collection, dims, w.metric); err != nil {
+			return fmt.Errorf("failed to create collection %s: %w", collection, err)
+		}
+	}
+	w.exists[collection] = struct{}{}
+	return nil
+}
+
+func (w *outputWriter) batchRecordsByCollection(batch service.MessageBatch) (map[string][]vectorRecord, error) {
+	collectionExec := batch.InterpolationExecutor(w.collection)
+	idExec := batch.BloblangExecutor(w.id)
+	vectorExec := batch.BloblangExecutor(w.vectorMapping)
+	payloadExec := batch.BloblangExecutor(w.payloadMapping)
+
+	records := map[string][]vectorRecord{}
+	for i := range batch {
+		collection, err := collectionExec.TryString(i)
+		if err != nil {
+			return nil, fmt.Errorf("%s interpolation error: %w", voFieldCollection, err)
+		}
+		rawID, err := queryValue(idExec, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute %s: %w", voFieldID, err)
+		}
+		var rec vectorRecord
+		if rec.id, err = asID(rawID); err != nil {
+			return nil, fmt.Errorf("%s extraction failed: %w", voFieldID, err)
+		}
+		if rec.id == "" {
+			return nil, fmt.Errorf("%s must not be empty", voFieldID)
+		}
+
+		rawVec, err := queryValue(vectorExec, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute %s: %w", voFieldVectorMapping, err)
+		}
+		if rec.vector, err = asVector(rawVec); err != nil {
+			return nil, fmt.Errorf("%s extraction failed: %w", voFieldVectorMapping, err)
+		}
+
+		rawPayload, err := queryValue(payloadExec, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute %s: %w", voFieldPayloadMapping, err)
+		}
+		payload, ok := rawPayload.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s must result in an object, got: %T", voFieldPayloadMapping, rawPayload)
+		}
+		rec.payload = payload
+
+		if prev := records[collection]; len(prev) > 0 && len(prev[0].vector) != len(rec.vector) {
+			return nil, fmt.Errorf("vectors written to collection %s have differing dimensions: %d and %d", collection, len(prev[0].vector), len(rec.vector))
+		}
+		records[collection] = append(records[collection], rec)
+	}
+	return records, nil
+}
+
+// queryValue executes a mapping and returns the result, where results that
+// aren't structured, such as strings, are returned as strings.
+func queryValue(exec *service.MessageBatchBloblangExecutor, i int) (any, error) {
+	msg, err := exec.Query(i)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, errors.New("mapping resulted in a deleted message")
+	}
+	if !msg.HasStructured() {
+		b, err := msg.AsBytes()
+		return string(b), err
+	}
+	return msg.AsStructured()
+}
+
+// asID converts the result of the ID mapping, which must be a string or an
+// integer, into a string.
+func asID(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	case uint64:
+		return strconv.FormatUint(t, 10), nil
+	case int:
+		return strconv.Itoa(t), nil
+	case json.Number:
+		if _, err := t.Int64(); err != nil {
+			return "", fmt.Errorf("expected an integer, got: %v", t)
+		}
+		return t.String(), nil
+	case float64:
+		if t != math.Trunc(t) {
+			return "", fmt.Errorf("expected an integer, got: %v", t)
+		}
+		return strconv.FormatInt(int64(t), 10), nil
+	}
+	return "", fmt.Errorf("expected a string or an integer, got: %T", v)
+}
+
+func asVector(v any) ([]float32, error) {
+	switch t := v.(type) {
+	case []float32:
+		if len(t) == 0 {
+			return nil, errors.New("vector must not be empty")
+		}
+		return t, nil
+	case []float64:
+		if len(t) == 0 {
+			return nil, errors.New("vector must not be empty")
+		}
+		vec := make([]float32, len(t))
+		for i, f := range t {
+			vec[i] = float32(f)
+		}
+		return vec, nil
+	}
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an array of numbers, got: %T", v)
+	}
+	if len(arr) == 0 {
+		return nil, errors.New("vector must not be empty")
+	}
+	vec := make([]float32, len(arr))
+	for i, e := range arr {
+		f, err := bloblang.ValueAsFloat32(e)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		vec[i] = f
+	}
+	return vec, nil
+}
+
+func (w *outputWriter) Close(ctx context.Context) error {
+	return nil
+}
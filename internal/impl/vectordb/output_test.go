@@ -0,0 +1,270 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vectordb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+type fakeServer struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+	handler  func(r recordedRequest) (int, string)
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := io.ReadAll(r.Body)
+	req := recordedRequest{method: r.Method, path: r.URL.RequestURI(), header: r.Header, body: string(b)}
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	code, body := http.StatusOK, "{}"
+	if f.handler != nil {
+		code, body = f.handler(req)
+	}
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(body))
+}
+
+func newWriterForTest(t *testing.T, srv *httptest.Server, yaml string) *outputWriter {
+	t.Helper()
+	conf, err := outputSpec().ParseYAML(fmt.Sprintf("url: %s\n%s", srv.URL, yaml), nil)
+	require.NoError(t, err)
+	w, err := newOutputWriter(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, w.Connect(context.Background()))
+	return w
+}
+
+func testBatch(docs ...string) service.MessageBatch {
+	var batch service.MessageBatch
+	for _, d := range docs {
+		batch = append(batch, service.NewMessage([]byte(d)))
+	}
+	return batch
+}
+
+func TestVectorDBQdrantAutoCreate(t *testing.T) {
+	fake := &fakeServer{handler: func(r recordedRequest) (int, string) {
+		if r.path == "/collections/docs/exists" {
+			return http.StatusOK, `{"result":{"exists":false}}`
+		}
+		return http.StatusOK, `{"result":{}}`
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	w := newWriterForTest(t, srv, `
+driver: qdrant
+api_key: secret
+collection: docs
+id: 'root = this.id'
+vector_mapping: 'root = this.vec'
+payload_mapping: 'root.text = this.text'
+auto_create:
+  enabled: true
+  metric: dot
+`)
+	ctx := context.Background()
+	require.NoError(t, w.WriteBatch(ctx, testBatch(
+		`{"id":1,"vec":[0.1,0.2],"text":"a"}`,
+		`{"id":"6f1c2a7e-2d4b-4c6e-9d8f-0a1b2c3d4e5f","vec":[0.3,0.4],"text":"b"}`,
+	)))
+	require.NoError(t, w.WriteBatch(ctx, testBatch(`{"id":2,"vec":[0.5,0.6],"text":"c"}`)))
+
+	require.Len(t, fake.requests, 4)
+	assert.Equal(t, "secret", fake.requests[0].header.Get("Api-Key"))
+	assert.Equal(t, "GET /collections/docs/exists", fake.requests[0].method+" "+fake.requests[0].path)
+	assert.Equal(t, "PUT /collections/docs", fake.requests[1].method+" "+fake.requests[1].path)
+	assert.JSONEq(t, `{"vectors":{"size":2,"distance":"Dot"}}`, fake.requests[1].body)
+	assert.Equal(t, "PUT /collections/docs/points?wait=true", fake.requests[2].method+" "+fake.requests[2].path)
+	assert.JSONEq(t, `{"points":[
+  {"id":1,"vector":[0.1,0.2],"payload":{"text":"a"}},
+  {"id":"6f1c2a7e-2d4b-4c6e-9d8f-0a1b2c3d4e5f","vector":[0.3,0.4],"payload":{"text":"b"}}
+]}`, fake.requests[2].body)
+	assert.Equal(t, "PUT /collections/docs/points?wait=true", fake.requests[3].method+" "+fake.requests[3].path)
+}
+
+func TestVectorDBPinecone(t *testing.T) {
+	fake := &fakeServer{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	w := newWriterForTest(t, srv, `
+driver: pinecone
+api_key: secret
+collection: '${! @ns }'
+id: 'root = this.id'
+vector_mapping: 'root = this.vec'
+payload_mapping: 'root = this.without("id", "vec")'
+`)
+	batch := testBatch(`{"id":"a","vec":[1,2],"text":"x"}`, `{"id":"b","vec":[3,4]}`)
+	batch[0].MetaSetMut("ns", "one")
+	batch[1].MetaSetMut("ns", "one")
+	require.NoError(t, w.WriteBatch(context.Background(), batch))
+
+	require.Len(t, fake.requests, 1)
+	req := fake.requests[0]
+	assert.Equal(t, "POST /vectors/upsert", req.method+" "+req.path)
+	assert.Equal(t, "secret", req.header.Get("Api-Key"))
+	assert.JSONEq(t, `{"namespace":"one","vectors":[
+  {"id":"a","values":[1,2],"metadata":{"text":"x"}},
+  {"id":"b","values":[3,4]}
+]}`, req.body)
+}
+
+func TestVectorDBPineconeAutoCreateUnsupported(t *testing.T) {
+	conf, err := outputSpec().ParseYAML(`
+driver: pinecone
+url: http://localhost
+collection: foo
+id: 'root = this.id'
+vector_mapping: 'root = this.vec'
+auto_create:
+  enabled: true
+`, nil)
+	require.NoError(t, err)
+	_, err = newOutputWriter(conf, service.MockResources())
+	require.ErrorContains(t, err, "not supported by the pinecone driver")
+}
+
+func TestVectorDBWeaviate(t *testing.T) {
+	fake := &fakeServer{handler: func(r recordedRequest) (int, string) {
+		switch r.path {
+		case "/v1/schema/Docs":
+			return http.StatusNotFound, ``
+		case "/v1/batch/objects":
+			var body struct {
+				Objects []map[string]any `json:"objects"`
+			}
+			_ = json.Unmarshal([]byte(r.body), &body)
+			if body.Objects[0]["properties"].(map[string]any)["text"] == "bad" {
+				return http.StatusOK, `[{"id":"x","result":{"errors":{"error":[{"message":"invalid property"}]}}}]`
+			}
+			return http.StatusOK, `[{"id":"x","result":{}}]`
+		}
+		return http.StatusOK, `{}`
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	w := newWriterForTest(t, srv, `
+driver: weaviate
+api_key: secret
+collection: Docs
+id: 'root = this.id'
+vector_mapping: 'root = this.vec'
+payload_mapping: 'root.text = this.text'
+auto_create:
+  enabled: true
+`)
+	ctx := context.Background()
+	require.NoError(t, w.WriteBatch(ctx, testBatch(`{"id":"doc-1","vec":[1,2],"text":"a"}`)))
+
+	require.Len(t, fake.requests, 3)
+	assert.Equal(t, "Bearer secret", fake.requests[0].header.Get("Authorization"))
+	assert.Equal(t, "POST /v1/schema", fake.requests[1].method+" "+fake.requests[1].path)
+	assert.JSONEq(t, `{"class":"Docs","vectorizer":"none","vectorIndexConfig":{"distance":"cosine"}}`, fake.requests[1].body)
+	assert.JSONEq(t, fmt.Sprintf(`{"objects":[{"class":"Docs","id":%q,"vector":[1,2],"properties":{"text":"a"}}]}`, weaviateID("doc-1")), fake.requests[2].body)
+
+	err := w.WriteBatch(ctx, testBatch(`{"id":"doc-2","vec":[1,2],"text":"bad"}`))
+	require.ErrorContains(t, err, "object x: invalid property")
+}
+
+func TestWeaviateID(t *testing.T) {
+	assert.Equal(t, "6f1c2a7e-2d4b-4c6e-9d8f-0a1b2c3d4e5f", weaviateID("6F1C2A7E-2D4B-4C6E-9D8F-0A1B2C3D4E5F"))
+	assert.Equal(t, weaviateID("foo"), weaviateID("foo"))
+	assert.NotEqual(t, weaviateID("foo"), weaviateID("bar"))
+}
+
+func TestVectorDBMilvus(t *testing.T) {
+	fake := &fakeServer{handler: func(r recordedRequest) (int, string) {
+		switch r.path {
+		case "/v2/vectordb/collections/has":
+			return http.StatusOK, `{"code":0,"data":{"has":false}}`
+		case "/v2/vectordb/entities/upsert":
+			if strings.Contains(r.body, `"fail"`) {
+				return http.StatusOK, `{"code":1100,"message":"invalid parameter"}`
+			}
+		}
+		return http.StatusOK, `{"code":0,"data":{}}`
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	w := newWriterForTest(t, srv, `
+driver: milvus
+collection: docs
+id: 'root = this.id'
+vector_mapping: 'root = this.vec'
+payload_mapping: 'root.text = this.text'
+auto_create:
+  enabled: true
+  metric: euclidean
+`)
+	ctx := context.Background()
+	require.NoError(t, w.WriteBatch(ctx, testBatch(`{"id":7,"vec":[1,2,3],"text":"a"}`)))
+
+	require.Len(t, fake.requests, 3)
+	assert.JSONEq(t, `{"collectionName":"docs"}`, fake.requests[0].body)
+	assert.JSONEq(t, `{
+  "collectionName":"docs",
+  "dimension":3,
+  "metricType":"L2",
+  "idType":"VarChar",
+  "primaryFieldName":"id",
+  "vectorFieldName":"vector",
+  "params":{"max_length":512}
+}`, fake.requests[1].body)
+	assert.JSONEq(t, `{"collectionName":"docs","data":[{"id":"7","vector":[1,2,3],"text":"a"}]}`, fake.requests[2].body)
+
+	err := w.WriteBatch(ctx, testBatch(`{"id":8,"vec":[1,2,3],"text":"fail"}`))
+	require.ErrorContains(t, err, "milvus returned error code 1100: invalid parameter")
+}
+
+func TestVectorDBMappingErrors(t *testing.T) {
+	srv := httptest.NewServer(&fakeServer{})
+	defer srv.Close()
+
+	w := newWriterForTest(t, srv, `
+driver: qdrant
+collection: docs
+id: 'root = this.id'
+vector_mapping: 'root = this.vec'
+`)
+	ctx := context.Background()
+	require.ErrorContains(t, w.WriteBatch(ctx, testBatch(`{"id":1.5,"vec":[1]}`)), "expected an integer")
+	require.ErrorContains(t, w.WriteBatch(ctx, testBatch(`{"id":1,"vec":"nope"}`)), "expected an array of numbers")
+	require.ErrorContains(t, w.WriteBatch(ctx, testBatch(`{"id":1,"vec":[1]}`, `{"id":2,"vec":[1,2]}`)), "differing dimensions")
+}
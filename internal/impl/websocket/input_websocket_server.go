@@ -0,0 +1,409 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/gorilla/websocket"
+	"github.com/rs/xid"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	wsiFieldAddress        = "address"
+	wsiFieldPath           = "path"
+	wsiFieldAllowedOrigins = "allowed_origins"
+	wsiFieldBufferSize     = "buffer_size"
+	wsiFieldBackpressure   = "backpressure"
+	wsiFieldCertFile       = "cert_file"
+	wsiFieldKeyFile        = "key_file"
+)
+
+const (
+	backpressureBlock = "block"
+	backpressureClose = "close"
+)
+
+func websocketServerInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Network").
+		Version("4.48.0").
+		Summary("Accepts inbound WebSocket connections and consumes the frames sent over them as messages.").
+		Description(`
+Each text or binary frame received over a connection becomes a message. Any number of clients can be connected at the same time, and the frames of each connection are delivered in the order they were received.
+
+== Backpressure
+
+Frames of each connection are buffered up to `+"`buffer_size`"+` messages while they wait to be consumed by the pipeline. When the buffer of a connection is full the field `+"`backpressure`"+` determines what happens:
+
+- `+"`block`"+`: Stop reading frames from the connection until the buffer has room, which pushes back on the client through the underlying TCP connection.
+- `+"`close`"+`: Close the connection with the status code 1013 (try again later), which allows clients to reconnect to another instance or after a delay.
+
+== Delivery guarantees
+
+Frames are not acknowledged to clients, and therefore frames that are buffered or in flight when Redpanda Connect shuts down are lost. Messages that are rejected by the pipeline are retried until they are delivered.
+
+== Metadata
+
+This input adds the following metadata fields to each message:
+
+`+"```text"+`
+- websocket_connection_id
+- websocket_path
+- websocket_remote_addr
+- websocket_message_type (text or binary)
+- All headers of the request that opened the connection
+`+"```"+`
+
+The connection ID is unique to each connection, and can be used to correlate messages sent over the same connection.
+
+You can access these metadata fields using xref:configuration:interpolation.adoc#bloblang-queries[function interpolation].
+`).
+		Fields(
+			service.NewStringField(wsiFieldAddress).
+				Description("The address to listen for connections on.").
+				Example("0.0.0.0:8080"),
+			service.NewStringField(wsiFieldPath).
+				Description("The path to accept connections on. A path ending with a slash accepts connections on all paths beneath it.").
+				Default("/ws"),
+			service.NewStringListField(wsiFieldAllowedOrigins).
+				Description("A list of origins that are allowed to open connections from browsers, where `*` allows any origin. When empty only connections from the same host, or without an `Origin` header, are allowed.").
+				Example([]any{"https://example.com"}).
+				Advanced().
+				Default([]any{}),
+			service.NewIntField(wsiFieldBufferSize).
+				Description("The maximum number of frames to buffer for each connection while they wait to be consumed.").
+				Advanced().
+				Default(64),
+			service.NewStringAnnotatedEnumField(wsiFieldBackpressure, map[string]string{
+				backpressureBlock: "Stop reading frames from the connection until its buffer has room.",
+				backpressureClose: "Close the connection with the status code 1013 (try again later).",
+			}).
+				Description("What to do when the buffer of a connection is full. For more information refer to <<backpressure, backpressure>>.").
+				Default(backpressureBlock),
+			service.NewStringField(wsiFieldCertFile).
+				Description("An optional certificate file for enabling TLS.").
+				Advanced().
+				Default(""),
+			service.NewStringField(wsiFieldKeyFile).
+				Description("An optional key file for enabling TLS.").
+				Advanced().
+				Default(""),
+			service.NewAutoRetryNacksToggleField(),
+		).
+		Example("Browser Events", "Accept events sent by browsers on the same site, closing connections that send events faster than they can be written to Kafka.", `
+input:
+  websocket_server:
+    address: 0.0.0.0:8080
+    path: /events
+    buffer_size: 16
+    backpressure: close
+
+output:
+  kafka_franz:
+    seed_brokers: [ localhost:9092 ]
+    topic: browser_events
+    key: ${! @websocket_connection_id }
+`)
+}
+
+func init() {
+	err := service.RegisterInput("websocket_server", websocketServerInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.Input, error) {
+			i, err := newWebsocketServerInputFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksToggled(conf, i)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+type websocketServerInput struct {
+	log     *service.Logger
+	shutSig *shutdown.Signaller
+
+	address     string
+	path        string
+	bufferSize  int
+	closeOnFull bool
+	certFile    string
+	keyFile     string
+	upgrader    websocket.Upgrader
+
+	msgChan chan *service.Message
+
+	serverMut  sync.Mutex
+	server     *http.Server
+	listenAddr net.Addr
+
+	connsMut sync.Mutex
+	conns    map[*websocket.Conn]struct{}
+	connsWG  sync.WaitGroup
+}
+
+func newWebsocketServerInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*websocketServerInput, error) {
+	w := &websocketServerInput{
+		log:     mgr.Logger(),
+		shutSig: shutdown.NewSignaller(),
+		msgChan: make(chan *service.Message),
+		conns:   map[*websocket.Conn]struct{}{},
+	}
+
+	var err error
+	if w.address, err = conf.FieldString(wsiFieldAddress); err != nil {
+		return nil, err
+	}
+	if w.path, err = conf.FieldString(wsiFieldPath); err != nil {
+		return nil, err
+	}
+
+	allowedOrigins, err := conf.FieldStringList(wsiFieldAllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+	if len(allowedOrigins) > 0 {
+		w.upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || slices.Contains(allowedOrigins, "*") || slices.Contains(allowedOrigins, origin)
+		}
+	}
+
+	if w.bufferSize, err = conf.FieldInt(wsiFieldBufferSize); err != nil {
+		return nil, err
+	}
+	if w.bufferSize < 0 {
+		return nil, fmt.Errorf("field %v must not be negative", wsiFieldBufferSize)
+	}
+
+	backpressure, err := conf.FieldString(wsiFieldBackpressure)
+	if err != nil {
+		return nil, err
+	}
+	w.closeOnFull = backpressure == backpressureClose
+
+	if w.certFile, err = conf.FieldString(wsiFieldCertFile); err != nil {
+		return nil, err
+	}
+	if w.keyFile, err = conf.FieldString(wsiFieldKeyFile); err != nil {
+		return nil, err
+	}
+	if (w.certFile == "") != (w.keyFile == "") {
+		return nil, errors.New("both cert_file and key_file must be set in order to enable TLS")
+	}
+	return w, nil
+}
+
+func (w *websocketServerInput) trackConn(ws *websocket.Conn) bool {
+	w.connsMut.Lock()
+	defer w.connsMut.Unlock()
+	if w.shutSig.IsSoftStopSignalled() {
+		return false
+	}
+	w.conns[ws] = struct{}{}
+	w.connsWG.Add(1)
+	return true
+}
+
+func (w *websocketServerInput) untrackConn(ws *websocket.Conn) {
+	w.connsMut.Lock()
+	delete(w.conns, ws)
+	w.connsMut.Unlock()
+	w.connsWG.Done()
+}
+
+func (w *websocketServerInput) handler(rw http.ResponseWriter, r *http.Request) {
+	if w.shutSig.IsSoftStopSignalled() {
+		http.Error(rw, "server closing", http.StatusServiceUnavailable)
+		return
+	}
+
+	ws, err := w.upgrader.Upgrade(rw, r, nil)
+	if err != nil {
+		// The upgrader has already responded with an error.
+		w.log.Debugf("Failed to upgrade websocket connection: %v", err)
+		return
+	}
+	if !w.trackConn(ws) {
+		_ = ws.Close()
+		return
+	}
+	defer w.untrackConn(ws)
+	defer ws.Close()
+
+	connID := xid.New().String()
+	w.log.Debugf("Accepted websocket connection %v from %v", connID, r.RemoteAddr)
+
+	// Frames are forwarded to the pipeline from a buffer so that a slow
+	// pipeline can be detected without blocking the read loop.
+	buf := make(chan *service.Message, w.bufferSize)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for msg := range buf {
+			select {
+			case w.msgChan <- msg:
+			case <-w.shutSig.SoftStopChan():
+				return
+			}
+		}
+	}()
+	defer func() {
+		close(buf)
+		<-forwarded
+	}()
+
+	for {
+		msgType, data, err := ws.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) && !w.shutSig.IsSoftStopSignalled() {
+				w.log.Debugf("Websocket connection %v closed: %v", connID, err)
+			}
+			return
+		}
+
+		msg := service.NewMessage(data)
+		msg.MetaSetMut("websocket_connection_id", connID)
+		msg.MetaSetMut("websocket_path", r.URL.Path)
+		msg.MetaSetMut("websocket_remote_addr", r.RemoteAddr)
+		if msgType == websocket.TextMessage {
+			msg.MetaSetMut("websocket_message_type", "text")
+		} else {
+			msg.MetaSetMut("websocket_message_type", "binary")
+		}
+		for k, v := range r.Header {
+			if len(v) > 0 {
+				msg.MetaSetMut(k, v[0])
+			}
+		}
+
+		if w.closeOnFull {
+			select {
+			case buf <- msg:
+				continue
+			default:
+			}
+			w.log.Warnf("Closing websocket connection %v from %v as its buffer is full", connID, r.RemoteAddr)
+			_ = ws.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "buffer full"),
+				time.Now().Add(time.Second))
+			return
+		}
+
+		select {
+		case buf <- msg:
+		case <-w.shutSig.SoftStopChan():
+			return
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+
+func (w *websocketServerInput) Connect(ctx context.Context) error {
+	w.serverMut.Lock()
+	defer w.serverMut.Unlock()
+	if w.server != nil {
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", w.address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(w.path, w.handler)
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		var err error
+		if w.certFile != "" {
+			err = server.ServeTLS(lis, w.certFile, w.keyFile)
+		} else {
+			err = server.Serve(lis)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			w.log.Errorf("Websocket server stopped: %v", err)
+		}
+	}()
+	w.log.Infof("Accepting websocket connections at: %v%v", lis.Addr(), w.path)
+	w.server = server
+	w.listenAddr = lis.Addr()
+	return nil
+}
+
+func (w *websocketServerInput) Read(ctx context.Context) (*service.Message, service.AckFunc, error) {
+	select {
+	case msg := <-w.msgChan:
+		return msg, func(ctx context.Context, err error) error {
+			return nil
+		}, nil
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case <-w.shutSig.SoftStopChan():
+		return nil, nil, service.ErrEndOfInput
+	}
+}
+
+func (w *websocketServerInput) Close(ctx context.Context) error {
+	w.shutSig.TriggerSoftStop()
+
+	w.serverMut.Lock()
+	server := w.server
+	w.server = nil
+	w.serverMut.Unlock()
+
+	var err error
+	if server != nil {
+		err = server.Shutdown(ctx)
+	}
+
+	// Connections are hijacked from the server and therefore need closing
+	// explicitly.
+	w.connsMut.Lock()
+	for ws := range w.conns {
+		_ = ws.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server closing"),
+			time.Now().Add(time.Second))
+		_ = ws.Close()
+	}
+	w.connsMut.Unlock()
+
+	closed := make(chan struct{})
+	go func() {
+		w.connsWG.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testServer(t *testing.T, extra string) *websocketServerInput {
+	t.Helper()
+
+	conf, err := websocketServerInputSpec().ParseYAML(`
+address: 127.0.0.1:0
+`+extra, nil)
+	require.NoError(t, err)
+
+	w, err := newWebsocketServerInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, w.Connect(context.Background()))
+	t.Cleanup(func() {
+		ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		assert.NoError(t, w.Close(ctx))
+	})
+	return w
+}
+
+func dial(t *testing.T, w *websocketServerInput, path string, header http.Header) *websocket.Conn {
+	t.Helper()
+
+	ws, res, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%v%v", w.listenAddr, path), header)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	t.Cleanup(func() {
+		_ = ws.Close()
+	})
+	return ws
+}
+
+func readMsg(t *testing.T, w *websocketServerInput) *service.Message {
+	t.Helper()
+
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	defer done()
+
+	msg, ackFn, err := w.Read(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ackFn(ctx, nil))
+	return msg
+}
+
+func TestWebsocketServerMessages(t *testing.T) {
+	w := testServer(t, `path: /events/`)
+
+	wsA := dial(t, w, "/events/a", http.Header{"X-Foo": []string{"bar"}})
+	wsB := dial(t, w, "/events/b", nil)
+
+	require.NoError(t, wsA.WriteMessage(websocket.TextMessage, []byte("hello")))
+	require.NoError(t, wsA.WriteMessage(websocket.BinaryMessage, []byte("world")))
+
+	msg := readMsg(t, w)
+	b, err := msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+
+	connID, _ := msg.MetaGet("websocket_connection_id")
+	assert.NotEmpty(t, connID)
+
+	v, _ := msg.MetaGet("websocket_path")
+	assert.Equal(t, "/events/a", v)
+	v, _ = msg.MetaGet("websocket_message_type")
+	assert.Equal(t, "text", v)
+	v, _ = msg.MetaGet("X-Foo")
+	assert.Equal(t, "bar", v)
+	v, _ = msg.MetaGet("websocket_remote_addr")
+	assert.NotEmpty(t, v)
+
+	msg = readMsg(t, w)
+	b, err = msg.AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(b))
+	v, _ = msg.MetaGet("websocket_message_type")
+	assert.Equal(t, "binary", v)
+	v, _ = msg.MetaGet("websocket_connection_id")
+	assert.Equal(t, connID, v)
+
+	require.NoError(t, wsB.WriteMessage(websocket.TextMessage, []byte("from b")))
+
+	msg = readMsg(t, w)
+	v, _ = msg.MetaGet("websocket_path")
+	assert.Equal(t, "/events/b", v)
+	v, _ = msg.MetaGet("websocket_connection_id")
+	assert.NotEqual(t, connID, v)
+}
+
+func TestWebsocketServerBackpressureClose(t *testing.T) {
+	w := testServer(t, `
+buffer_size: 1
+backpressure: close
+`)
+
+	ws := dial(t, w, "/ws", nil)
+
+	// Nothing is reading from the input, so at most one frame is held by the
+	// forwarder and one by the buffer before it overflows.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("msg%v", i))))
+	}
+
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, _, err := ws.ReadMessage()
+	require.Error(t, err)
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), err.Error())
+
+	// Frames received before the connection was closed are still delivered.
+	b, err := readMsg(t, w).AsBytes()
+	require.NoError(t, err)
+	assert.Equal(t, "msg0", string(b))
+}
+
+func TestWebsocketServerBackpressureBlock(t *testing.T) {
+	w := testServer(t, `
+buffer_size: 1
+`)
+
+	ws := dial(t, w, "/ws", nil)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf("msg%v", i))))
+	}
+
+	for i := 0; i < 5; i++ {
+		b, err := readMsg(t, w).AsBytes()
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("msg%v", i), string(b))
+	}
+}
+
+func TestWebsocketServerOrigins(t *testing.T) {
+	w := testServer(t, `
+allowed_origins: [ https://example.com ]
+`)
+
+	url := fmt.Sprintf("ws://%v/ws", w.listenAddr)
+
+	_, res, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://evil.com"}})
+	require.Error(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	_ = res.Body.Close()
+
+	ws, res, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{"https://example.com"}})
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	_ = ws.Close()
+}
+
+func TestWebsocketServerClose(t *testing.T) {
+	conf, err := websocketServerInputSpec().ParseYAML(`address: 127.0.0.1:0`, nil)
+	require.NoError(t, err)
+
+	w, err := newWebsocketServerInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	require.NoError(t, w.Connect(context.Background()))
+
+	ws := dial(t, w, "/ws", nil)
+	require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte("unread")))
+
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	defer done()
+	require.NoError(t, w.Close(ctx))
+
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, _, err = ws.ReadMessage()
+	require.Error(t, err)
+
+	_, _, err = w.Read(ctx)
+	assert.ErrorIs(t, err, service.ErrEndOfInput)
+}
@@ -0,0 +1,778 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package window
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	kwFieldKey                = "key"
+	kwFieldTimestampMapping   = "timestamp_mapping"
+	kwFieldType               = "type"
+	kwFieldSize               = "size"
+	kwFieldSlide              = "slide"
+	kwFieldGap                = "gap"
+	kwFieldAllowedLateness    = "allowed_lateness"
+	kwFieldReduceMapping      = "reduce_mapping"
+	kwFieldCheckpoint         = "checkpoint"
+	kwFieldCheckpointCache    = "cache"
+	kwFieldCheckpointKey      = "key"
+	kwFieldCheckpointInterval = "interval"
+
+	kwTypeTumbling = "tumbling"
+	kwTypeHopping  = "hopping"
+	kwTypeSession  = "session"
+)
+
+func keyedWindowBufferSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Windowing").
+		Version("4.48.0").
+		Summary("Aggregates messages into tumbling, hopping or session windows for each key, and emits the result of each window once it closes.").
+		Description(`
+Each message is allocated to the windows of its key, which is extracted with `+"`key`"+`, according to its timestamp, which is extracted with `+"`timestamp_mapping`"+`. Rather than storing the messages of a window, each message is folded into the state of the window with `+"`reduce_mapping`"+`, and once the window closes its final state is emitted as a message.
+
+== Window types
+
+- `+"`tumbling`"+`: Windows of a fixed `+"`size`"+` that follow each other without overlapping, aligned to the zeroth minute and zeroth hour on the UTC clock.
+- `+"`hopping`"+`: Windows of a fixed `+"`size`"+` that begin every `+"`slide`"+`, and therefore overlap when the slide is smaller than the size. Messages are folded into every window they fall within.
+- `+"`session`"+`: Windows that begin with the first message of a key and are extended by each message that arrives within `+"`gap`"+` of the previous one, which closes after a period of inactivity of `+"`gap`"+`.
+
+Windows close once the system clock surpasses their end plus `+"`allowed_lateness`"+`, and messages that belong to windows that have already closed are dropped.
+
+== Reduce mapping
+
+The reduce mapping is executed for each message and each window it belongs to, where `+"`this.state`"+` is the state of the window, which is `+"`null`"+` for the first message of a window, and `+"`this.message`"+` is the contents of the message. The result of the mapping becomes the new state of the window, and the metadata of the message can be referenced as usual.
+
+== Metadata
+
+The messages emitted for each window have the following metadata fields:
+
+`+"```text"+`
+- window_key
+- window_start_timestamp
+- window_end_timestamp
+- window_count
+`+"```"+`
+
+Timestamps are RFC3339 strings and `+"`window_count`"+` is the number of messages folded into the window.
+
+== Delivery guarantees
+
+Without a checkpoint messages are acknowledged once every window they were folded into has been delivered, and therefore windows are rebuilt from messages that are redelivered after a restart. With a `+"`checkpoint`"+` cache the state of all windows that have not yet been delivered is written to the cache periodically, messages are acknowledged once they are part of a written checkpoint, and the windows are restored from the cache when the service restarts.
+
+Windows that are rejected downstream are emitted again until they are delivered.
+`).
+		Fields(
+			service.NewBloblangField(kwFieldKey).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang query] that extracts the key of each message, windows are tracked separately for each key.").
+				Example("this.user_id").
+				Example(`@kafka_key`),
+			service.NewBloblangField(kwFieldTimestampMapping).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] applied to each message that provides the timestamp used to allocate it to windows. By default the processing time is used.").
+				Example(`root = this.created_at.ts_parse("2006-01-02T15:04:05Z07:00")`).
+				Default("root = now()"),
+			service.NewStringAnnotatedEnumField(kwFieldType, map[string]string{
+				kwTypeTumbling: "Fixed size windows that do not overlap.",
+				kwTypeHopping:  "Fixed size windows that begin every `slide`.",
+				kwTypeSession:  "Windows that close after a period of inactivity of `gap`.",
+			}).
+				Description("The type of windows to aggregate messages into. For more information refer to <<window-types, window types>>.").
+				Default(kwTypeTumbling),
+			service.NewDurationField(kwFieldSize).
+				Description("The size of `tumbling` and `hopping` windows.").
+				Example("1m").
+				Example("1h").
+				Optional(),
+			service.NewDurationField(kwFieldSlide).
+				Description("The interval at which `hopping` windows begin.").
+				Example("30s").
+				Optional(),
+			service.NewDurationField(kwFieldGap).
+				Description("The period of inactivity after which `session` windows close.").
+				Example("30m").
+				Optional(),
+			service.NewDurationField(kwFieldAllowedLateness).
+				Description("The length of time to wait after a window has ended before closing it, allowing late messages to be included.").
+				Default("0s"),
+			service.NewBloblangField(kwFieldReduceMapping).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang mapping] that folds a message into the state of a window. For more information refer to <<reduce-mapping, reduce mapping>>.").
+				Example(`root.count = this.state.count.or(0) + 1
+root.total = this.state.total.or(0) + this.message.amount`),
+			service.NewObjectField(kwFieldCheckpoint,
+				service.NewStringField(kwFieldCheckpointCache).
+					Description("A cache resource to write checkpoints to."),
+				service.NewStringField(kwFieldCheckpointKey).
+					Description("The key of the checkpoint within the cache, which must be unique to each buffer.").
+					Default("keyed_window"),
+				service.NewDurationField(kwFieldCheckpointInterval).
+					Description("The interval at which checkpoints are written when windows have changed.").
+					Default("1s"),
+			).
+				Description("Write the state of windows to a cache so that they can be restored after a restart. For more information refer to <<delivery-guarantees, delivery guarantees>>.").
+				Optional(),
+		).
+		LintRule(`root = if this.type.or("tumbling") == "tumbling" && !this.exists("size") {
+  [ "field size is required for tumbling windows" ]
+} else if this.type.or("tumbling") == "hopping" && (!this.exists("size") || !this.exists("slide")) {
+  [ "fields size and slide are required for hopping windows" ]
+} else if this.type.or("tumbling") == "session" && !this.exists("gap") {
+  [ "field gap is required for session windows" ]
+}`).
+		Example("Session Summaries", "Summarise the page views of each user session, where sessions end after 30 minutes of inactivity.", `
+buffer:
+  keyed_window:
+    key: this.user_id
+    timestamp_mapping: root = this.ts.ts_parse("2006-01-02T15:04:05Z07:00")
+    type: session
+    gap: 30m
+    reduce_mapping: |
+      root.user_id = this.message.user_id
+      root.pages = this.state.pages.or([]).append(this.message.page)
+    checkpoint:
+      cache: sessions
+
+cache_resources:
+  - label: sessions
+    redis:
+      url: redis://localhost:6379
+`)
+}
+
+func init() {
+	err := service.RegisterBatchBuffer("keyed_window", keyedWindowBufferSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchBuffer, error) {
+			return newKeyedWindowBufferFromConfig(conf, mgr)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type keyedWindowConfig struct {
+	key       *bloblang.Executor
+	timestamp *bloblang.Executor
+	reduce    *bloblang.Executor
+
+	windowType string
+	size       time.Duration
+	slide      time.Duration
+	gap        time.Duration
+	lateness   time.Duration
+
+	checkpointCache    string
+	checkpointKey      string
+	checkpointInterval time.Duration
+}
+
+func newKeyedWindowBufferFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*keyedWindowBuffer, error) {
+	var c keyedWindowConfig
+	var err error
+	if c.key, err = conf.FieldBloblang(kwFieldKey); err != nil {
+		return nil, err
+	}
+	if c.timestamp, err = conf.FieldBloblang(kwFieldTimestampMapping); err != nil {
+		return nil, err
+	}
+	if c.reduce, err = conf.FieldBloblang(kwFieldReduceMapping); err != nil {
+		return nil, err
+	}
+	if c.windowType, err = conf.FieldString(kwFieldType); err != nil {
+		return nil, err
+	}
+
+	optDuration := func(name string) (time.Duration, error) {
+		if !conf.Contains(name) {
+			return 0, nil
+		}
+		d, err := conf.FieldDuration(name)
+		if err == nil && d <= 0 {
+			err = fmt.Errorf("field %v must be larger than zero", name)
+		}
+		return d, err
+	}
+	if c.size, err = optDuration(kwFieldSize); err != nil {
+		return nil, err
+	}
+	if c.slide, err = optDuration(kwFieldSlide); err != nil {
+		return nil, err
+	}
+	if c.gap, err = optDuration(kwFieldGap); err != nil {
+		return nil, err
+	}
+	if c.lateness, err = conf.FieldDuration(kwFieldAllowedLateness); err != nil {
+		return nil, err
+	}
+
+	switch c.windowType {
+	case kwTypeTumbling:
+		if c.size == 0 {
+			return nil, errors.New("field size is required for tumbling windows")
+		}
+	case kwTypeHopping:
+		if c.size == 0 || c.slide == 0 {
+			return nil, errors.New("fields size and slide are required for hopping windows")
+		}
+		if c.slide > c.size {
+			return nil, errors.New("field slide must not be larger than size")
+		}
+	case kwTypeSession:
+		if c.gap == 0 {
+			return nil, errors.New("field gap is required for session windows")
+		}
+	default:
+		return nil, fmt.Errorf("window type not recognised: %v", c.windowType)
+	}
+
+	if conf.Contains(kwFieldCheckpoint) {
+		cConf := conf.Namespace(kwFieldCheckpoint)
+		if c.checkpointCache, err = cConf.FieldString(kwFieldCheckpointCache); err != nil {
+			return nil, err
+		}
+		if c.checkpointKey, err = cConf.FieldString(kwFieldCheckpointKey); err != nil {
+			return nil, err
+		}
+		if c.checkpointInterval, err = cConf.FieldDuration(kwFieldCheckpointInterval); err != nil {
+			return nil, err
+		}
+		if c.checkpointInterval <= 0 {
+			return nil, errors.New("checkpoint interval must be larger than zero")
+		}
+		if !mgr.HasCache(c.checkpointCache) {
+			return nil, fmt.Errorf("cache resource '%v' was not found", c.checkpointCache)
+		}
+	}
+	return newKeyedWindowBuffer(c, mgr, time.Now)
+}
+
+//------------------------------------------------------------------------------
+
+// pendingAck tracks the acknowledgement of a batch written to the buffer that
+// is waiting on the delivery of the windows its messages were folded into.
+type pendingAck struct {
+	remaining int
+	fn        service.AckFunc
+}
+
+type window struct {
+	key   string
+	start time.Time
+	// The end of tumbling and hopping windows is fixed, sessions end gap
+	// after their latest message.
+	end   time.Time
+	count int
+	state any
+
+	acks map[*pendingAck]struct{}
+}
+
+// windowSnapshot is the serialised form of a window within a checkpoint.
+type windowSnapshot struct {
+	Key   string    `json:"key"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Count int       `json:"count"`
+	State any       `json:"state"`
+}
+
+type keyedWindowBuffer struct {
+	conf  keyedWindowConfig
+	mgr   *service.Resources
+	log   *service.Logger
+	nowFn func() time.Time
+
+	cond *sync.Cond
+
+	// Windows that are open, by key.
+	windows map[string][]*window
+
+	// Windows that have closed and are waiting to be read, followed by those
+	// that have been read and not yet acknowledged.
+	ready    []*window
+	inFlight map[*window]struct{}
+
+	// Acknowledgements of batches waiting for the next checkpoint.
+	awaitingCheckpoint []service.AckFunc
+	checkpointDirty    bool
+
+	wakeTimer  *time.Timer
+	endOfInput bool
+	closed     bool
+
+	stopBackground   chan struct{}
+	backgroundDoneWG sync.WaitGroup
+}
+
+func newKeyedWindowBuffer(conf keyedWindowConfig, mgr *service.Resources, nowFn func() time.Time) (*keyedWindowBuffer, error) {
+	k := &keyedWindowBuffer{
+		conf:           conf,
+		mgr:            mgr,
+		log:            mgr.Logger(),
+		nowFn:          nowFn,
+		cond:           sync.NewCond(&sync.Mutex{}),
+		windows:        map[string][]*window{},
+		inFlight:       map[*window]struct{}{},
+		stopBackground: make(chan struct{}),
+	}
+	if conf.checkpointCache != "" {
+		if err := k.restoreCheckpoint(); err != nil {
+			return nil, err
+		}
+		k.backgroundDoneWG.Add(1)
+		go k.checkpointLoop()
+	}
+	return k, nil
+}
+
+func (k *keyedWindowBuffer) restoreCheckpoint() error {
+	var data []byte
+	var err error
+	if aerr := k.mgr.AccessCache(context.Background(), k.conf.checkpointCache, func(c service.Cache) {
+		data, err = c.Get(context.Background(), k.conf.checkpointKey)
+	}); aerr != nil {
+		return aerr
+	}
+	if errors.Is(err, service.ErrKeyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var snapshots []windowSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	for _, s := range snapshots {
+		k.windows[s.Key] = append(k.windows[s.Key], &window{
+			key:   s.Key,
+			start: s.Start,
+			end:   s.End,
+			count: s.Count,
+			state: s.State,
+			acks:  map[*pendingAck]struct{}{},
+		})
+	}
+	return nil
+}
+
+// writeCheckpoint writes every window that has not been delivered to the
+// cache, and must be called with the lock held.
+func (k *keyedWindowBuffer) writeCheckpoint(ctx context.Context) error {
+	snapshots := []windowSnapshot{}
+	add := func(w *window) {
+		snapshots = append(snapshots, windowSnapshot{
+			Key:   w.key,
+			Start: w.start,
+			End:   w.end,
+			Count: w.count,
+			State: w.state,
+		})
+	}
+	for _, ws := range k.windows {
+		for _, w := range ws {
+			add(w)
+		}
+	}
+	for _, w := range k.ready {
+		add(w)
+	}
+	for w := range k.inFlight {
+		add(w)
+	}
+
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+	if aerr := k.mgr.AccessCache(ctx, k.conf.checkpointCache, func(c service.Cache) {
+		err = c.Set(ctx, k.conf.checkpointKey, data, nil)
+	}); aerr != nil {
+		return aerr
+	}
+	if err != nil {
+		return err
+	}
+
+	k.checkpointDirty = false
+	for _, aFn := range k.awaitingCheckpoint {
+		_ = aFn(ctx, nil)
+	}
+	k.awaitingCheckpoint = nil
+	return nil
+}
+
+func (k *keyedWindowBuffer) checkpointLoop() {
+	defer k.backgroundDoneWG.Done()
+
+	ticker := time.NewTicker(k.conf.checkpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-k.stopBackground:
+			return
+		}
+
+		k.cond.L.Lock()
+		if k.checkpointDirty {
+			if err := k.writeCheckpoint(context.Background()); err != nil {
+				k.log.Errorf("Failed to write window checkpoint: %v", err)
+			}
+		}
+		k.cond.L.Unlock()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// closesAt returns the time at which a window closes according to the system
+// clock.
+func (k *keyedWindowBuffer) closesAt(w *window) time.Time {
+	return w.end.Add(k.conf.lateness)
+}
+
+// windowsFor returns the windows of a key that a timestamp falls within,
+// creating them as required, and excluding any that would have already
+// closed.
+func (k *keyedWindowBuffer) windowsFor(key string, ts, now time.Time) []*window {
+	var matched []*window
+	existing := k.windows[key]
+
+	if k.conf.windowType == kwTypeSession {
+		for _, w := range existing {
+			if !ts.Before(w.start.Add(-k.conf.gap)) && ts.Before(w.end) {
+				if ts.Before(w.start) {
+					w.start = ts
+				}
+				if end := ts.Add(k.conf.gap); end.After(w.end) {
+					w.end = end
+				}
+				return []*window{w}
+			}
+		}
+		w := &window{key: key, start: ts, end: ts.Add(k.conf.gap)}
+		if !k.closesAt(w).After(now) {
+			return nil
+		}
+		w.acks = map[*pendingAck]struct{}{}
+		k.windows[key] = append(existing, w)
+		return []*window{w}
+	}
+
+	slide := k.conf.size
+	if k.conf.windowType == kwTypeHopping {
+		slide = k.conf.slide
+	}
+	for start := ts.Truncate(slide); start.Add(k.conf.size).After(ts); start = start.Add(-slide) {
+		var w *window
+		for _, e := range existing {
+			if e.start.Equal(start) {
+				w = e
+				break
+			}
+		}
+		if w == nil {
+			w = &window{key: key, start: start, end: start.Add(k.conf.size)}
+			if !k.closesAt(w).After(now) {
+				continue
+			}
+			w.acks = map[*pendingAck]struct{}{}
+			existing = append(existing, w)
+			k.windows[key] = existing
+		}
+		matched = append(matched, w)
+	}
+	return matched
+}
+
+func (k *keyedWindowBuffer) reduce(msg *service.Message, w *window) error {
+	content, err := msg.AsStructured()
+	if err != nil {
+		return err
+	}
+
+	input := msg.Copy()
+	input.SetStructuredMut(map[string]any{
+		"state":   w.state,
+		"message": content,
+	})
+
+	res, err := input.BloblangQuery(k.conf.reduce)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return errors.New("reduce mapping deleted the state")
+	}
+	if w.state, err = res.AsStructuredMut(); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+func queryString(msg *service.Message, exec *bloblang.Executor) (string, error) {
+	res, err := msg.BloblangQuery(exec)
+	if err != nil {
+		return "", err
+	}
+	if res == nil {
+		return "", errors.New("query deleted the message")
+	}
+	b, err := res.AsBytes()
+	return string(b), err
+}
+
+func queryTimestamp(msg *service.Message, exec *bloblang.Executor) (time.Time, error) {
+	res, err := msg.BloblangQuery(exec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if res == nil {
+		return time.Time{}, errors.New("timestamp mapping deleted the message")
+	}
+	v, err := res.AsStructured()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return bloblang.ValueAsTimestamp(v)
+}
+
+// WriteBatch folds each message of a batch into the windows it belongs to.
+// Messages that cannot be folded into a window are logged and dropped.
+func (k *keyedWindowBuffer) WriteBatch(ctx context.Context, batch service.MessageBatch, aFn service.AckFunc) error {
+	k.cond.L.Lock()
+	defer k.cond.L.Unlock()
+
+	if k.closed {
+		return service.ErrEndOfBuffer
+	}
+
+	pending := &pendingAck{fn: aFn}
+	now := k.nowFn()
+	for _, msg := range batch {
+		key, err := queryString(msg, k.conf.key)
+		if err != nil {
+			k.log.Errorf("Dropping message due to key query error: %v", err)
+			continue
+		}
+		ts, err := queryTimestamp(msg, k.conf.timestamp)
+		if err != nil {
+			k.log.Errorf("Dropping message due to timestamp mapping error: %v", err)
+			continue
+		}
+
+		windows := k.windowsFor(key, ts, now)
+		if len(windows) == 0 {
+			k.log.Debugf("Dropping message with key %v as its windows have already closed", key)
+		}
+		for _, w := range windows {
+			if err := k.reduce(msg, w); err != nil {
+				k.log.Errorf("Dropping message due to reduce mapping error: %v", err)
+				break
+			}
+			if _, exists := w.acks[pending]; !exists {
+				w.acks[pending] = struct{}{}
+				pending.remaining++
+			}
+		}
+	}
+
+	switch {
+	case k.conf.checkpointCache != "":
+		// Windows are restored from checkpoints, and so batches are
+		// acknowledged once a checkpoint containing them has been written.
+		for _, ws := range k.windows {
+			for _, w := range ws {
+				delete(w.acks, pending)
+			}
+		}
+		k.awaitingCheckpoint = append(k.awaitingCheckpoint, aFn)
+		k.checkpointDirty = true
+	case pending.remaining == 0:
+		_ = aFn(ctx, nil)
+	}
+
+	k.cond.Broadcast()
+	return nil
+}
+
+// collectClosed moves windows that have closed into the ready queue, and
+// returns the time at which the next window closes.
+func (k *keyedWindowBuffer) collectClosed(now time.Time) (next time.Time) {
+	keys := make([]string, 0, len(k.windows))
+	for key := range k.windows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var open []*window
+		for _, w := range k.windows[key] {
+			if closesAt := k.closesAt(w); k.endOfInput || !closesAt.After(now) {
+				k.ready = append(k.ready, w)
+			} else {
+				open = append(open, w)
+				if next.IsZero() || closesAt.Before(next) {
+					next = closesAt
+				}
+			}
+		}
+		if len(open) == 0 {
+			delete(k.windows, key)
+		} else {
+			k.windows[key] = open
+		}
+	}
+	return
+}
+
+func (k *keyedWindowBuffer) windowMessage(w *window) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(w.state)
+	msg.MetaSetMut("window_key", w.key)
+	msg.MetaSetMut("window_start_timestamp", w.start.Format(time.RFC3339Nano))
+	msg.MetaSetMut("window_end_timestamp", w.end.Format(time.RFC3339Nano))
+	msg.MetaSetMut("window_count", w.count)
+	return msg
+}
+
+func (k *keyedWindowBuffer) ack(ctx context.Context, windows []*window, err error) error {
+	k.cond.L.Lock()
+	defer k.cond.L.Unlock()
+
+	for _, w := range windows {
+		delete(k.inFlight, w)
+	}
+	if err != nil {
+		// Windows are emitted again until they are delivered.
+		k.ready = append(k.ready, windows...)
+		k.cond.Broadcast()
+		return nil
+	}
+
+	for _, w := range windows {
+		for pending := range w.acks {
+			if pending.remaining--; pending.remaining == 0 {
+				_ = pending.fn(ctx, nil)
+			}
+		}
+	}
+	if k.conf.checkpointCache != "" {
+		k.checkpointDirty = true
+	}
+	return nil
+}
+
+func (k *keyedWindowBuffer) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		k.cond.Broadcast()
+	}()
+
+	k.cond.L.Lock()
+	defer k.cond.L.Unlock()
+
+	for {
+		if k.closed {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		next := k.collectClosed(k.nowFn())
+		if len(k.ready) > 0 {
+			windows := k.ready
+			k.ready = nil
+
+			batch := make(service.MessageBatch, len(windows))
+			for i, w := range windows {
+				k.inFlight[w] = struct{}{}
+				batch[i] = k.windowMessage(w)
+			}
+			return batch, func(ctx context.Context, err error) error {
+				return k.ack(ctx, windows, err)
+			}, nil
+		}
+
+		if k.endOfInput && len(k.inFlight) == 0 {
+			return nil, nil, service.ErrEndOfBuffer
+		}
+
+		if !next.IsZero() {
+			if k.wakeTimer != nil {
+				k.wakeTimer.Stop()
+			}
+			k.wakeTimer = time.AfterFunc(next.Sub(k.nowFn()), k.cond.Broadcast)
+		}
+		k.cond.Wait()
+	}
+}
+
+// EndOfInput signals to the buffer that the input is finished, and therefore
+// all windows are closed immediately.
+func (k *keyedWindowBuffer) EndOfInput() {
+	go func() {
+		k.cond.L.Lock()
+		defer k.cond.L.Unlock()
+
+		k.endOfInput = true
+		k.cond.Broadcast()
+	}()
+}
+
+// Close writes a final checkpoint of the windows that have not been
+// delivered.
+func (k *keyedWindowBuffer) Close(ctx context.Context) error {
+	k.cond.L.Lock()
+	if k.closed {
+		k.cond.L.Unlock()
+		return nil
+	}
+	k.closed = true
+	close(k.stopBackground)
+	if k.wakeTimer != nil {
+		k.wakeTimer.Stop()
+	}
+
+	var err error
+	if k.conf.checkpointCache != "" && k.checkpointDirty {
+		err = k.writeCheckpoint(ctx)
+	}
+	k.cond.Broadcast()
+	k.cond.L.Unlock()
+
+	k.backgroundDoneWG.Wait()
+	return err
+}
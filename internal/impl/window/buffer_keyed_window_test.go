@@ -0,0 +1,303 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package window
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+type testClock struct {
+	mut sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(t time.Time) {
+	c.mut.Lock()
+	c.now = t
+	c.mut.Unlock()
+}
+
+func newKeyedWindowForTest(t *testing.T, mgr *service.Resources, clock *testClock, yaml string) *keyedWindowBuffer {
+	t.Helper()
+	conf, err := keyedWindowBufferSpec().ParseYAML(yaml, nil)
+	require.NoError(t, err)
+	k, err := newKeyedWindowBufferFromConfig(conf, mgr)
+	require.NoError(t, err)
+	k.nowFn = clock.Now
+	t.Cleanup(func() {
+		_ = k.Close(context.Background())
+	})
+	return k
+}
+
+func writeDocs(t *testing.T, k *keyedWindowBuffer, docs ...string) *bool {
+	t.Helper()
+	var batch service.MessageBatch
+	for _, d := range docs {
+		batch = append(batch, service.NewMessage([]byte(d)))
+	}
+	acked := new(bool)
+	require.NoError(t, k.WriteBatch(context.Background(), batch, func(context.Context, error) error {
+		*acked = true
+		return nil
+	}))
+	return acked
+}
+
+type windowResult struct {
+	Key   string
+	Start string
+	End   string
+	Count int
+	State string
+}
+
+func readWindows(t *testing.T, k *keyedWindowBuffer) ([]windowResult, service.AckFunc) {
+	t.Helper()
+	ctx, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	batch, ackFn, err := k.ReadBatch(ctx)
+	require.NoError(t, err)
+
+	var results []windowResult
+	for _, msg := range batch {
+		var r windowResult
+		var ok bool
+		r.Key, _ = msg.MetaGet("window_key")
+		r.Start, _ = msg.MetaGet("window_start_timestamp")
+		r.End, _ = msg.MetaGet("window_end_timestamp")
+		count, _ := msg.MetaGetMut("window_count")
+		r.Count, ok = count.(int)
+		require.True(t, ok)
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		r.State = string(b)
+		results = append(results, r)
+	}
+	return results, ackFn
+}
+
+func assertNoWindows(t *testing.T, k *keyedWindowBuffer) {
+	t.Helper()
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer done()
+	_, _, err := k.ReadBatch(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestKeyedWindowTumbling(t *testing.T) {
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 30, 0, time.UTC)}
+	k := newKeyedWindowForTest(t, service.MockResources(), clock, `
+key: this.id
+timestamp_mapping: root = this.ts.ts_parse("2006-01-02T15:04:05Z07:00")
+type: tumbling
+size: 1m
+allowed_lateness: 10s
+reduce_mapping: root.total = this.state.total.or(0) + this.message.value
+`)
+
+	acked := writeDocs(t, k,
+		`{"id":"a","ts":"2025-01-01T00:00:05Z","value":1}`,
+		`{"id":"b","ts":"2025-01-01T00:00:10Z","value":2}`,
+		`{"id":"a","ts":"2025-01-01T00:00:50Z","value":3}`,
+		`{"id":"a","ts":"2025-01-01T00:01:10Z","value":4}`,
+	)
+	assert.False(t, *acked)
+	assertNoWindows(t, k)
+
+	clock.Set(time.Date(2025, 1, 1, 0, 1, 10, 0, time.UTC))
+	results, ackFn := readWindows(t, k)
+	assert.Equal(t, []windowResult{
+		{Key: "a", Start: "2025-01-01T00:00:00Z", End: "2025-01-01T00:01:00Z", Count: 2, State: `{"total":4}`},
+		{Key: "b", Start: "2025-01-01T00:00:00Z", End: "2025-01-01T00:01:00Z", Count: 1, State: `{"total":2}`},
+	}, results)
+
+	// Messages from windows that have closed are dropped.
+	writeDocs(t, k, `{"id":"b","ts":"2025-01-01T00:00:55Z","value":5}`)
+
+	require.NoError(t, ackFn(context.Background(), nil))
+	assert.False(t, *acked, "batch acknowledged before all of its windows were delivered")
+
+	k.EndOfInput()
+	results, ackFn = readWindows(t, k)
+	assert.Equal(t, []windowResult{
+		{Key: "a", Start: "2025-01-01T00:01:00Z", End: "2025-01-01T00:02:00Z", Count: 1, State: `{"total":4}`},
+	}, results)
+	require.NoError(t, ackFn(context.Background(), nil))
+	assert.True(t, *acked)
+
+	_, _, err := k.ReadBatch(context.Background())
+	require.ErrorIs(t, err, service.ErrEndOfBuffer)
+}
+
+func TestKeyedWindowHopping(t *testing.T) {
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	k := newKeyedWindowForTest(t, service.MockResources(), clock, `
+key: '"all"'
+timestamp_mapping: root = this.ts.ts_parse("2006-01-02T15:04:05Z07:00")
+type: hopping
+size: 1m
+slide: 30s
+reduce_mapping: root = this.state.or([]).append(this.message.value)
+`)
+
+	writeDocs(t, k,
+		`{"ts":"2025-01-01T00:00:10Z","value":1}`,
+		`{"ts":"2025-01-01T00:00:40Z","value":2}`,
+	)
+	k.EndOfInput()
+
+	results, ackFn := readWindows(t, k)
+	assert.ElementsMatch(t, []windowResult{
+		{Key: "all", Start: "2024-12-31T23:59:30Z", End: "2025-01-01T00:00:30Z", Count: 1, State: `[1]`},
+		{Key: "all", Start: "2025-01-01T00:00:00Z", End: "2025-01-01T00:01:00Z", Count: 2, State: `[1,2]`},
+		{Key: "all", Start: "2025-01-01T00:00:30Z", End: "2025-01-01T00:01:30Z", Count: 1, State: `[2]`},
+	}, results)
+	require.NoError(t, ackFn(context.Background(), nil))
+}
+
+func TestKeyedWindowSession(t *testing.T) {
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	k := newKeyedWindowForTest(t, service.MockResources(), clock, `
+key: this.user
+timestamp_mapping: root = this.ts.ts_parse("2006-01-02T15:04:05Z07:00")
+type: session
+gap: 30s
+reduce_mapping: root.pages = this.state.pages.or([]).append(this.message.page)
+`)
+
+	writeDocs(t, k,
+		`{"user":"a","ts":"2025-01-01T00:00:00Z","page":"home"}`,
+		`{"user":"a","ts":"2025-01-01T00:00:20Z","page":"search"}`,
+		`{"user":"a","ts":"2025-01-01T00:00:45Z","page":"item"}`,
+		`{"user":"a","ts":"2025-01-01T00:02:00Z","page":"home"}`,
+	)
+
+	clock.Set(time.Date(2025, 1, 1, 0, 1, 30, 0, time.UTC))
+	results, ackFn := readWindows(t, k)
+	assert.Equal(t, []windowResult{
+		{Key: "a", Start: "2025-01-01T00:00:00Z", End: "2025-01-01T00:01:15Z", Count: 3, State: `{"pages":["home","search","item"]}`},
+	}, results)
+
+	// Rejected windows are emitted again.
+	require.NoError(t, ackFn(context.Background(), assert.AnError))
+	results, ackFn = readWindows(t, k)
+	require.Len(t, results, 1)
+	assert.Equal(t, 3, results[0].Count)
+	require.NoError(t, ackFn(context.Background(), nil))
+
+	assertNoWindows(t, k)
+}
+
+func TestKeyedWindowCheckpointRestore(t *testing.T) {
+	mgr := service.MockResources(service.MockResourcesOptAddCache("windows"))
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	conf := `
+key: this.id
+timestamp_mapping: root = this.ts.ts_parse("2006-01-02T15:04:05Z07:00")
+type: tumbling
+size: 1m
+reduce_mapping: root.count = this.state.count.or(0) + 1
+checkpoint:
+  cache: windows
+  interval: 10ms
+`
+
+	k := newKeyedWindowForTest(t, mgr, clock, conf)
+	acked := writeDocs(t, k,
+		`{"id":"a","ts":"2025-01-01T00:00:05Z"}`,
+		`{"id":"a","ts":"2025-01-01T00:00:10Z"}`,
+	)
+	assert.Eventually(t, func() bool {
+		k.cond.L.Lock()
+		defer k.cond.L.Unlock()
+		return *acked
+	}, time.Second, time.Millisecond*10)
+	require.NoError(t, k.Close(context.Background()))
+
+	k = newKeyedWindowForTest(t, mgr, clock, conf)
+	writeDocs(t, k, `{"id":"a","ts":"2025-01-01T00:00:15Z"}`)
+
+	clock.Set(time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC))
+	results, ackFn := readWindows(t, k)
+	assert.Equal(t, []windowResult{
+		{Key: "a", Start: "2025-01-01T00:00:00Z", End: "2025-01-01T00:01:00Z", Count: 3, State: `{"count":3}`},
+	}, results)
+	require.NoError(t, ackFn(context.Background(), nil))
+	require.NoError(t, k.Close(context.Background()))
+
+	k = newKeyedWindowForTest(t, mgr, clock, conf)
+	assertNoWindows(t, k)
+}
+
+func TestKeyedWindowLint(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		config string
+		lint   string
+	}{
+		{
+			name:   "tumbling without size",
+			config: `type: tumbling`,
+			lint:   "field size is required for tumbling windows",
+		},
+		{
+			name: "hopping without slide",
+			config: `type: hopping
+size: 1m`,
+			lint: "fields size and slide are required for hopping windows",
+		},
+		{
+			name:   "session without gap",
+			config: `type: session`,
+			lint:   "field gap is required for session windows",
+		},
+		{
+			name: "session with gap",
+			config: `type: session
+gap: 1m`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			conf := "buffer:\n  keyed_window:\n    key: this.id\n    reduce_mapping: root = this.state\n"
+			for _, line := range strings.Split(test.config, "\n") {
+				conf += "    " + line + "\n"
+			}
+			err := service.NewStreamBuilder().SetYAML(conf)
+			if test.lint == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.lint)
+			}
+		})
+	}
+}
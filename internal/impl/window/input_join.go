@@ -0,0 +1,476 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package window
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	jiFieldLeft     = "left"
+	jiFieldRight    = "right"
+	jiFieldLeftKey  = "left_key"
+	jiFieldRightKey = "right_key"
+	jiFieldTTL      = "ttl"
+)
+
+func joinInputSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Categories("Utility").
+		Version("4.48.0").
+		Summary("Joins the messages of two inputs that share a key and arrive within a period of time of each other.").
+		Description(`
+Messages consumed from each of the `+"`left`"+` and `+"`right`"+` inputs are held for the duration of `+"`ttl`"+` under a key extracted with `+"`left_key`"+` and `+"`right_key`"+` respectively. When a message arrives and messages of the other input are held under the same key, a joined message is emitted for each pair, and therefore messages can be joined with any number of messages of the other input.
+
+Joined messages are objects of the form `+"`{\"left\":{...},\"right\":{...}}`"+`, where each field contains the contents of the respective message. The metadata of both messages is copied to the joined message, where the metadata of the right message takes precedence, and the key is added as the metadata field `+"`join_key`"+`.
+
+Messages that have not been joined with any message by the time they expire are dropped.
+
+== Delivery guarantees
+
+Messages are acknowledged once they have expired and every joined message they are part of has been delivered. Messages are therefore redelivered by inputs that support it after a restart, and joins are rebuilt from them.
+
+Since messages remain unacknowledged for at least the duration of `+"`ttl`"+`, inputs that limit the number of unacknowledged messages stop consuming once that limit is reached until held messages expire. For example, the `+"`checkpoint_limit`"+` of the `+"`kafka_franz`"+` input caps the messages held per topic partition at 1024 by default, and should be raised above the number of messages expected to arrive on a partition within `+"`ttl`"+`.
+
+Both inputs are consumed until they have ended, at which point all remaining messages are expired immediately. Inputs that wait for their messages to be acknowledged before ending will therefore only end once their remaining messages have expired.`).
+		Fields(
+			service.NewInputField(jiFieldLeft).
+				Description("The first input of the join."),
+			service.NewInputField(jiFieldRight).
+				Description("The second input of the join."),
+			service.NewBloblangField(jiFieldLeftKey).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang query] that extracts the join key of messages from the `left` input.").
+				Example("this.order_id"),
+			service.NewBloblangField(jiFieldRightKey).
+				Description("A xref:guides:bloblang/about.adoc[Bloblang query] that extracts the join key of messages from the `right` input.").
+				Example("this.id"),
+			service.NewDurationField(jiFieldTTL).
+				Description("The period of time that messages are held for while they wait to be joined.").
+				Default("5m"),
+		).
+		Example("Enrich Orders With Payments", "Joins orders with the payments made against them from a separate topic, provided the payment arrives within ten minutes of the order. The `checkpoint_limit` of each input is raised so that up to ten minutes of messages per partition can be held without blocking consumption.", `
+input:
+  join:
+    left:
+      kafka_franz:
+        seed_brokers: [ localhost:9092 ]
+        topics: [ orders ]
+        consumer_group: orders_payments
+        checkpoint_limit: 100000
+    right:
+      kafka_franz:
+        seed_brokers: [ localhost:9092 ]
+        topics: [ payments ]
+        consumer_group: orders_payments
+        checkpoint_limit: 100000
+    left_key: this.id
+    right_key: this.order_id
+    ttl: 10m
+
+pipeline:
+  processors:
+    - mapping: |
+        root = this.left
+        root.payment = this.right
+`)
+}
+
+func init() {
+	err := service.RegisterBatchInput("join", joinInputSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchInput, error) {
+			j, err := newJoinInputFromConfig(conf, mgr)
+			if err != nil {
+				return nil, err
+			}
+			return service.AutoRetryNacksBatched(j), nil
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	joinSideLeft = iota
+	joinSideRight
+)
+
+// joinAck tracks the references to the messages of a batch consumed from one
+// of the inputs, and acknowledges the batch once they have all been released.
+type joinAck struct {
+	remaining int
+	fn        service.AckFunc
+}
+
+type joinRecord struct {
+	msg     *service.Message
+	content any
+	expires time.Time
+	ack     *joinAck
+}
+
+type joinInput struct {
+	inputs [2]*service.OwnedInput
+	keys   [2]*bloblang.Executor
+	ttl    time.Duration
+
+	log   *service.Logger
+	nowFn func() time.Time
+
+	cond *sync.Cond
+
+	// Records waiting to be joined by key, for each side.
+	records [2]map[string][]*joinRecord
+
+	// Joined messages waiting to be read, and the number that have been read
+	// and not yet acknowledged.
+	joined   []*joinedPair
+	inFlight int
+
+	inputsEnded int
+	started     bool
+	closed      bool
+
+	shutSig          chan struct{}
+	backgroundDoneWG sync.WaitGroup
+}
+
+type joinedPair struct {
+	msg  *service.Message
+	acks [2]*joinAck
+}
+
+func newJoinInputFromConfig(conf *service.ParsedConfig, mgr *service.Resources) (*joinInput, error) {
+	leftKey, err := conf.FieldBloblang(jiFieldLeftKey)
+	if err != nil {
+		return nil, err
+	}
+	rightKey, err := conf.FieldBloblang(jiFieldRightKey)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := conf.FieldDuration(jiFieldTTL)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		return nil, errors.New("field ttl must be larger than zero")
+	}
+
+	left, err := conf.FieldInput(jiFieldLeft)
+	if err != nil {
+		return nil, err
+	}
+	right, err := conf.FieldInput(jiFieldRight)
+	if err != nil {
+		_ = left.Close(context.Background())
+		return nil, err
+	}
+	return newJoinInput([2]*service.OwnedInput{left, right}, [2]*bloblang.Executor{leftKey, rightKey}, ttl, mgr, time.Now), nil
+}
+
+func newJoinInput(inputs [2]*service.OwnedInput, keys [2]*bloblang.Executor, ttl time.Duration, mgr *service.Resources, nowFn func() time.Time) *joinInput {
+	return &joinInput{
+		inputs:  inputs,
+		keys:    keys,
+		ttl:     ttl,
+		log:     mgr.Logger(),
+		nowFn:   nowFn,
+		cond:    sync.NewCond(&sync.Mutex{}),
+		records: [2]map[string][]*joinRecord{{}, {}},
+		shutSig: make(chan struct{}),
+	}
+}
+
+func (j *joinInput) Connect(ctx context.Context) error {
+	j.cond.L.Lock()
+	defer j.cond.L.Unlock()
+
+	if j.closed || j.started {
+		return nil
+	}
+	j.started = true
+	for side := range j.inputs {
+		j.backgroundDoneWG.Add(1)
+		go j.consume(side)
+	}
+	j.backgroundDoneWG.Add(1)
+	go j.expireLoop()
+	return nil
+}
+
+func (j *joinInput) consume(side int) {
+	defer j.backgroundDoneWG.Done()
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+	go func() {
+		select {
+		case <-j.shutSig:
+			done()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		// Apply back pressure until pending joins have been read.
+		j.cond.L.Lock()
+		for len(j.joined) > 0 && !j.closed {
+			j.cond.Wait()
+		}
+		closed := j.closed
+		j.cond.L.Unlock()
+		if closed {
+			return
+		}
+
+		batch, ackFn, err := j.inputs[side].ReadBatch(ctx)
+		if err != nil {
+			if errors.Is(err, service.ErrEndOfInput) {
+				j.cond.L.Lock()
+				j.inputsEnded++
+				if j.inputsEnded == len(j.inputs) {
+					j.expire(time.Time{})
+				}
+				j.cond.Broadcast()
+				j.cond.L.Unlock()
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			j.log.Errorf("Failed to read from join input: %v", err)
+			select {
+			case <-time.After(time.Second):
+			case <-j.shutSig:
+				return
+			}
+			continue
+		}
+		j.addBatch(side, batch, ackFn)
+	}
+}
+
+// expireLoop periodically releases records that have expired.
+func (j *joinInput) expireLoop() {
+	defer j.backgroundDoneWG.Done()
+
+	interval := min(j.ttl, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-j.shutSig:
+			return
+		}
+
+		j.cond.L.Lock()
+		j.expire(j.nowFn())
+		j.cond.L.Unlock()
+	}
+}
+
+// release drops a reference to a batch, and must be called with the lock
+// held.
+func (j *joinInput) release(a *joinAck) {
+	if a.remaining--; a.remaining == 0 {
+		_ = a.fn(context.Background(), nil)
+	}
+}
+
+// expire releases all records that expire before a given time, or all records
+// when the time is zero, and must be called with the lock held.
+func (j *joinInput) expire(now time.Time) {
+	for side := range j.records {
+		for key, records := range j.records[side] {
+			var remaining []*joinRecord
+			for _, r := range records {
+				if now.IsZero() || !r.expires.After(now) {
+					j.release(r.ack)
+				} else {
+					remaining = append(remaining, r)
+				}
+			}
+			if len(remaining) == 0 {
+				delete(j.records[side], key)
+			} else {
+				j.records[side][key] = remaining
+			}
+		}
+	}
+}
+
+func joinedMessage(key string, left, right *joinRecord) *service.Message {
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(map[string]any{
+		"left":  left.content,
+		"right": right.content,
+	})
+	for _, r := range []*joinRecord{left, right} {
+		_ = r.msg.MetaWalkMut(func(k string, v any) error {
+			msg.MetaSetMut(k, v)
+			return nil
+		})
+	}
+	msg.MetaSetMut("join_key", key)
+	return msg
+}
+
+// addBatch holds the messages of a batch consumed from one side of the join,
+// and joins them with the records held from the other side.
+func (j *joinInput) addBatch(side int, batch service.MessageBatch, ackFn service.AckFunc) {
+	j.cond.L.Lock()
+	defer j.cond.L.Unlock()
+
+	now := j.nowFn()
+	other := 1 - side
+
+	// The batch holds a reference until it has been processed in order to
+	// prevent it from being acknowledged early.
+	ack := &joinAck{remaining: 1, fn: ackFn}
+	defer j.release(ack)
+
+	for _, msg := range batch {
+		res, err := msg.BloblangQuery(j.keys[side])
+		if err != nil {
+			j.log.Errorf("Dropping message due to key query error: %v", err)
+			continue
+		}
+		if res == nil {
+			j.log.Error("Dropping message as the key query deleted it")
+			continue
+		}
+		keyBytes, err := res.AsBytes()
+		if err != nil {
+			j.log.Errorf("Dropping message due to key query error: %v", err)
+			continue
+		}
+		content, err := msg.AsStructured()
+		if err != nil {
+			j.log.Errorf("Dropping message as it could not be parsed: %v", err)
+			continue
+		}
+
+		key := string(keyBytes)
+		r := &joinRecord{
+			msg:     msg,
+			content: content,
+			expires: now.Add(j.ttl),
+			ack:     ack,
+		}
+		ack.remaining++
+		j.records[side][key] = append(j.records[side][key], r)
+
+		for _, o := range j.records[other][key] {
+			if !o.expires.After(now) {
+				continue
+			}
+			left, right := r, o
+			if side == joinSideRight {
+				left, right = o, r
+			}
+			left.ack.remaining++
+			right.ack.remaining++
+			j.joined = append(j.joined, &joinedPair{
+				msg:  joinedMessage(key, left, right),
+				acks: [2]*joinAck{left.ack, right.ack},
+			})
+		}
+	}
+	j.cond.Broadcast()
+}
+
+func (j *joinInput) ReadBatch(ctx context.Context) (service.MessageBatch, service.AckFunc, error) {
+	ctx, done := context.WithCancel(ctx)
+	defer done()
+
+	go func() {
+		<-ctx.Done()
+		j.cond.Broadcast()
+	}()
+
+	j.cond.L.Lock()
+	defer j.cond.L.Unlock()
+
+	for {
+		if j.closed {
+			return nil, nil, service.ErrNotConnected
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		if len(j.joined) > 0 {
+			pairs := j.joined
+			j.joined = nil
+			j.inFlight += len(pairs)
+			j.cond.Broadcast()
+
+			batch := make(service.MessageBatch, len(pairs))
+			for i, p := range pairs {
+				batch[i] = p.msg
+			}
+			return batch, func(context.Context, error) error {
+				j.cond.L.Lock()
+				defer j.cond.L.Unlock()
+
+				j.inFlight -= len(pairs)
+				for _, p := range pairs {
+					j.release(p.acks[0])
+					j.release(p.acks[1])
+				}
+				j.cond.Broadcast()
+				return nil
+			}, nil
+		}
+		if j.inputsEnded == len(j.inputs) && j.inFlight == 0 {
+			return nil, nil, service.ErrEndOfInput
+		}
+		j.cond.Wait()
+	}
+}
+
+func (j *joinInput) Close(ctx context.Context) error {
+	j.cond.L.Lock()
+	if j.closed {
+		j.cond.L.Unlock()
+		return nil
+	}
+	j.closed = true
+	close(j.shutSig)
+	j.cond.Broadcast()
+	j.cond.L.Unlock()
+
+	j.backgroundDoneWG.Wait()
+
+	var err error
+	for _, in := range j.inputs {
+		if in != nil {
+			if cerr := in.Close(ctx); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
+}
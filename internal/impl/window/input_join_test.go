@@ -0,0 +1,146 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package window
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func addJoinDocs(t *testing.T, j *joinInput, side int, docs ...string) *bool {
+	t.Helper()
+	var batch service.MessageBatch
+	for _, d := range docs {
+		msg := service.NewMessage([]byte(d))
+		msg.MetaSetMut("side", side)
+		batch = append(batch, msg)
+	}
+	acked := new(bool)
+	j.addBatch(side, batch, func(context.Context, error) error {
+		*acked = true
+		return nil
+	})
+	return acked
+}
+
+func readJoined(t *testing.T, j *joinInput) ([]string, service.AckFunc) {
+	t.Helper()
+	ctx, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	batch, ackFn, err := j.ReadBatch(ctx)
+	require.NoError(t, err)
+
+	var results []string
+	for _, msg := range batch {
+		key, _ := msg.MetaGet("join_key")
+		side, _ := msg.MetaGetMut("side")
+		assert.Equal(t, joinSideRight, side)
+		b, err := msg.AsBytes()
+		require.NoError(t, err)
+		results = append(results, key+":"+string(b))
+	}
+	return results, ackFn
+}
+
+func TestJoinInputTTL(t *testing.T) {
+	clock := &testClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	leftKey, err := bloblang.Parse("this.id")
+	require.NoError(t, err)
+	rightKey, err := bloblang.Parse("this.order")
+	require.NoError(t, err)
+	j := newJoinInput([2]*service.OwnedInput{}, [2]*bloblang.Executor{leftKey, rightKey}, time.Minute, service.MockResources(), clock.Now)
+
+	leftAcked := addJoinDocs(t, j, joinSideLeft, `{"id":"a","v":1}`, `{"id":"b","v":2}`)
+
+	clock.Set(clock.Now().Add(time.Second * 30))
+	rightAcked := addJoinDocs(t, j, joinSideRight, `{"order":"a","p":1}`, `{"order":"a","p":2}`, `{"order":"c","p":3}`)
+
+	results, ackFn := readJoined(t, j)
+	assert.Equal(t, []string{
+		`a:{"left":{"id":"a","v":1},"right":{"order":"a","p":1}}`,
+		`a:{"left":{"id":"a","v":1},"right":{"order":"a","p":2}}`,
+	}, results)
+
+	// The left records expire before the remaining right record arrives.
+	clock.Set(clock.Now().Add(time.Second * 31))
+	j.expire(clock.Now())
+	assert.False(t, *leftAcked, "acknowledged before joined messages were delivered")
+	require.NoError(t, ackFn(context.Background(), nil))
+	assert.True(t, *leftAcked)
+	assert.False(t, *rightAcked)
+
+	addJoinDocs(t, j, joinSideLeft, `{"id":"b","v":3}`)
+	addJoinDocs(t, j, joinSideLeft, `{"id":"c","v":4}`)
+	results, ackFn = readJoined(t, j)
+	assert.Equal(t, []string{
+		`c:{"left":{"id":"c","v":4},"right":{"order":"c","p":3}}`,
+	}, results)
+	require.NoError(t, ackFn(context.Background(), nil))
+
+	clock.Set(clock.Now().Add(time.Minute))
+	j.expire(clock.Now())
+	assert.True(t, *rightAcked)
+}
+
+func TestJoinInputEndToEnd(t *testing.T) {
+	conf, err := joinInputSpec().ParseYAML(`
+left:
+  generate:
+    count: 3
+    interval: ""
+    mapping: 'root = {"id": counter(), "name": "left"}'
+right:
+  generate:
+    count: 4
+    interval: ""
+    mapping: 'root = {"id": counter(), "name": "right"}'
+left_key: this.id
+right_key: this.id
+ttl: 500ms
+`, nil)
+	require.NoError(t, err)
+
+	j, err := newJoinInputFromConfig(conf, service.MockResources())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = j.Close(context.Background())
+	})
+	require.NoError(t, j.Connect(context.Background()))
+
+	var keys []string
+	for {
+		ctx, done := context.WithTimeout(context.Background(), time.Second*5)
+		batch, ackFn, err := j.ReadBatch(ctx)
+		done()
+		if errors.Is(err, service.ErrEndOfInput) {
+			break
+		}
+		require.NoError(t, err)
+		for _, msg := range batch {
+			key, _ := msg.MetaGet("join_key")
+			keys = append(keys, key)
+		}
+		require.NoError(t, ackFn(context.Background(), nil))
+	}
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, keys)
+}
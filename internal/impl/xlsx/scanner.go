@@ -0,0 +1,290 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xlsx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	sFieldSheets         = "sheets"
+	sFieldParseHeaderRow = "parse_header_row"
+	sFieldSkipEmptyRows  = "skip_empty_rows"
+)
+
+func xlsxScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Summary("Consume the rows of Excel (XLSX) workbooks as structured messages.").
+		Description(`
+A message is emitted for each row of the selected sheets of a workbook. When `+"`parse_header_row`"+` is enabled the first row of each sheet is used as the field names of the rows that follow, and each row is emitted as an object, otherwise rows are emitted as arrays. Cell values are emitted as strings formatted as they are displayed, and the cells of formulas are emitted as their cached results.
+
+Workbooks are read into memory in full, as they are zip archives which require random access, but rows are decoded incrementally.
+
+== Metadata
+
+This scanner adds the following metadata to each message:
+
+- `+"`xlsx_sheet`"+`: The name of the sheet of the row.
+- `+"`xlsx_row`"+`: The index of the row within its sheet as shown in Excel, starting from 1, which is unaffected by skipped empty rows and the header row.
+`).
+		Fields(
+			service.NewStringListField(sFieldSheets).
+				Description("The names of the sheets to consume, in order. When empty all sheets are consumed in the order they appear in the workbook.").
+				Default([]any{}).
+				Example([]any{"Orders", "Returns"}),
+			service.NewBoolField(sFieldParseHeaderRow).
+				Description("Whether to use the first row of each sheet as the field names of the rows that follow. Cells without a header are named after their column, such as `D`, and repeated header names are suffixed with an increasing number, such as `price_2`.").
+				Default(true),
+			service.NewBoolField(sFieldSkipEmptyRows).
+				Description("Whether to skip rows in which all cells are empty.").
+				Default(true),
+		).
+		Example("Consuming Spreadsheets", "In this example we consume the rows of the `Orders` sheet of spreadsheets uploaded to a bucket.", `
+input:
+  aws_s3:
+    bucket: TODO
+    prefix: reports/
+    scanner:
+      xlsx:
+        sheets: [ Orders ]
+`).
+		Version("4.48.0")
+}
+
+func init() {
+	err := service.RegisterBatchScannerCreator("xlsx", xlsxScannerSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchScannerCreator, error) {
+			return xlsxScannerFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func xlsxScannerFromParsed(conf *service.ParsedConfig) (c *xlsxScannerCreator, err error) {
+	c = &xlsxScannerCreator{}
+	if c.sheets, err = conf.FieldStringList(sFieldSheets); err != nil {
+		return nil, err
+	}
+	if c.parseHeaderRow, err = conf.FieldBool(sFieldParseHeaderRow); err != nil {
+		return nil, err
+	}
+	if c.skipEmptyRows, err = conf.FieldBool(sFieldSkipEmptyRows); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type xlsxScannerCreator struct {
+	sheets         []string
+	parseHeaderRow bool
+	skipEmptyRows  bool
+}
+
+func (c *xlsxScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, details *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	f, err := excelize.OpenReader(rdr)
+	if err != nil {
+		_ = rdr.Close()
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+
+	sheets := c.sheets
+	if len(sheets) == 0 {
+		sheets = f.GetSheetList()
+	} else {
+		available := f.GetSheetList()
+		for _, s := range sheets {
+			if !slices.Contains(available, s) {
+				_ = f.Close()
+				_ = rdr.Close()
+				return nil, fmt.Errorf("sheet %v does not exist in the workbook", s)
+			}
+		}
+	}
+
+	return service.AutoAggregateBatchScannerAcks(&xlsxScanner{
+		r:              rdr,
+		file:           f,
+		sheets:         sheets,
+		parseHeaderRow: c.parseHeaderRow,
+		skipEmptyRows:  c.skipEmptyRows,
+	}, aFn), nil
+}
+
+func (c *xlsxScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+type xlsxScanner struct {
+	r    io.ReadCloser
+	file *excelize.File
+
+	sheets         []string
+	parseHeaderRow bool
+	skipEmptyRows  bool
+
+	// The rows of the current sheet, along with its header and the index of
+	// the latest row read. Rows are yielded for every index up to the last row
+	// of a sheet, including those absent from the sheet, and therefore the
+	// index is counted rather than read from the row.
+	rows     *excelize.Rows
+	sheet    string
+	header   []string
+	rowIndex int
+}
+
+func (s *xlsxScanner) nextSheet() error {
+	if s.rows != nil {
+		_ = s.rows.Close()
+		s.rows = nil
+	}
+	if len(s.sheets) == 0 {
+		return io.EOF
+	}
+
+	s.sheet, s.sheets = s.sheets[0], s.sheets[1:]
+	rows, err := s.file.Rows(s.sheet)
+	if err != nil {
+		return fmt.Errorf("failed to read sheet %v: %w", s.sheet, err)
+	}
+	s.rows, s.header, s.rowIndex = rows, nil, 0
+	return nil
+}
+
+func (s *xlsxScanner) NextBatch(ctx context.Context) (service.MessageBatch, error) {
+	if s.file == nil {
+		return nil, io.EOF
+	}
+
+	for {
+		if s.rows == nil || !s.rows.Next() {
+			if s.rows != nil {
+				if err := s.rows.Error(); err != nil {
+					return nil, fmt.Errorf("failed to read sheet %v: %w", s.sheet, err)
+				}
+			}
+			if err := s.nextSheet(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		s.rowIndex++
+
+		cols, err := s.rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row %v of sheet %v: %w", s.rowIndex, s.sheet, err)
+		}
+		if s.skipEmptyRows && !slices.ContainsFunc(cols, func(c string) bool { return c != "" }) {
+			continue
+		}
+
+		if s.parseHeaderRow && s.header == nil {
+			s.header = headerNames(cols)
+			continue
+		}
+
+		msg := service.NewMessage(nil)
+		if s.parseHeaderRow {
+			msg.SetStructuredMut(s.rowObject(cols))
+		} else {
+			values := make([]any, len(cols))
+			for i, c := range cols {
+				values[i] = c
+			}
+			msg.SetStructuredMut(values)
+		}
+		msg.MetaSetMut("xlsx_sheet", s.sheet)
+		msg.MetaSetMut("xlsx_row", s.rowIndex)
+		return service.MessageBatch{msg}, nil
+	}
+}
+
+// headerNames returns the field names of a header row, where cells without a
+// header are named after their column, and repeated names are suffixed with
+// an increasing number, such as `name_2`, which skips names already present.
+func headerNames(cols []string) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		if c == "" {
+			c, _ = excelize.ColumnNumberToName(i + 1)
+		}
+		names[i] = c
+	}
+
+	seen := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		seen[n] = struct{}{}
+	}
+	counts := make(map[string]int, len(names))
+	for i, n := range names {
+		if counts[n]++; counts[n] == 1 {
+			continue
+		}
+		suffixed := fmt.Sprintf("%v_%v", n, counts[n])
+		for {
+			if _, exists := seen[suffixed]; !exists {
+				break
+			}
+			counts[n]++
+			suffixed = fmt.Sprintf("%v_%v", n, counts[n])
+		}
+		seen[suffixed] = struct{}{}
+		names[i] = suffixed
+	}
+	return names
+}
+
+// rowObject maps the cells of a row to the header, where cells missing from
+// the end of the row are empty strings.
+func (s *xlsxScanner) rowObject(cols []string) map[string]any {
+	obj := make(map[string]any, max(len(cols), len(s.header)))
+	for i, name := range s.header {
+		var v string
+		if i < len(cols) {
+			v = cols[i]
+		}
+		obj[name] = v
+	}
+	for i := len(s.header); i < len(cols); i++ {
+		if cols[i] == "" {
+			continue
+		}
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		obj[name] = cols[i]
+	}
+	return obj
+}
+
+func (s *xlsxScanner) Close(ctx context.Context) error {
+	if s.file == nil {
+		return nil
+	}
+	if s.rows != nil {
+		_ = s.rows.Close()
+		s.rows = nil
+	}
+	err := errors.Join(s.file.Close(), s.r.Close())
+	s.file = nil
+	return err
+}
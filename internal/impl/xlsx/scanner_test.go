@@ -0,0 +1,160 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xlsx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+func testWorkbook(t *testing.T) []byte {
+	t.Helper()
+
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetName("Sheet1", "Orders"))
+	require.NoError(t, f.SetSheetRow("Orders", "A1", &[]any{"id", "item", "", "price"}))
+	require.NoError(t, f.SetSheetRow("Orders", "A2", &[]any{1, "apple", "x", 1.5}))
+	require.NoError(t, f.SetSheetRow("Orders", "A4", &[]any{2, "pear"}))
+	require.NoError(t, f.SetSheetRow("Orders", "A5", &[]any{3, "plum", "", 2, "extra"}))
+
+	_, err := f.NewSheet("Notes")
+	require.NoError(t, err)
+	require.NoError(t, f.SetSheetRow("Notes", "A1", &[]any{"note"}))
+	require.NoError(t, f.SetSheetRow("Notes", "A2", &[]any{"hello"}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	return buf.Bytes()
+}
+
+type scannedRow struct {
+	sheet string
+	row   int
+	value any
+}
+
+func scanWorkbook(t *testing.T, conf string, b []byte) (rows []scannedRow) {
+	t.Helper()
+
+	pConf, err := xlsxScannerSpec().ParseYAML(conf, nil)
+	require.NoError(t, err)
+
+	creator, err := xlsxScannerFromParsed(pConf)
+	require.NoError(t, err)
+
+	scanner, err := creator.Create(io.NopCloser(bytes.NewReader(b)), func(context.Context, error) error { return nil }, service.NewScannerSourceDetails())
+	require.NoError(t, err)
+
+	for {
+		batch, _, err := scanner.NextBatch(context.Background())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+
+		v, err := batch[0].AsStructured()
+		require.NoError(t, err)
+		sheet, _ := batch[0].MetaGetMut("xlsx_sheet")
+		row, _ := batch[0].MetaGetMut("xlsx_row")
+		rows = append(rows, scannedRow{sheet: sheet.(string), row: row.(int), value: v})
+	}
+	require.NoError(t, scanner.Close(context.Background()))
+	return
+}
+
+func TestXLSXScannerHeader(t *testing.T) {
+	rows := scanWorkbook(t, `{}`, testWorkbook(t))
+	assert.Equal(t, []scannedRow{
+		{sheet: "Orders", row: 2, value: map[string]any{"id": "1", "item": "apple", "C": "x", "price": "1.5"}},
+		{sheet: "Orders", row: 4, value: map[string]any{"id": "2", "item": "pear", "C": "", "price": ""}},
+		{sheet: "Orders", row: 5, value: map[string]any{"id": "3", "item": "plum", "C": "", "price": "2", "E": "extra"}},
+		{sheet: "Notes", row: 2, value: map[string]any{"note": "hello"}},
+	}, rows)
+}
+
+func TestXLSXScannerArrays(t *testing.T) {
+	rows := scanWorkbook(t, `
+sheets: [ Notes, Orders ]
+parse_header_row: false
+skip_empty_rows: false
+`, testWorkbook(t))
+	assert.Equal(t, []scannedRow{
+		{sheet: "Notes", row: 1, value: []any{"note"}},
+		{sheet: "Notes", row: 2, value: []any{"hello"}},
+		{sheet: "Orders", row: 1, value: []any{"id", "item", "", "price"}},
+		{sheet: "Orders", row: 2, value: []any{"1", "apple", "x", "1.5"}},
+		{sheet: "Orders", row: 3, value: []any{}},
+		{sheet: "Orders", row: 4, value: []any{"2", "pear"}},
+		{sheet: "Orders", row: 5, value: []any{"3", "plum", "", "2", "extra"}},
+	}, rows)
+}
+
+func TestXLSXScannerMissingSheet(t *testing.T) {
+	pConf, err := xlsxScannerSpec().ParseYAML(`sheets: [ Nope ]`, nil)
+	require.NoError(t, err)
+
+	creator, err := xlsxScannerFromParsed(pConf)
+	require.NoError(t, err)
+
+	_, err = creator.Create(io.NopCloser(bytes.NewReader(testWorkbook(t))), nil, service.NewScannerSourceDetails())
+	require.ErrorContains(t, err, "sheet Nope does not exist")
+}
+
+func TestXLSXScannerRowIndex(t *testing.T) {
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{"id"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A7", &[]any{1}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A12", &[]any{2}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	rows := scanWorkbook(t, `{}`, buf.Bytes())
+	assert.Equal(t, []scannedRow{
+		{sheet: "Sheet1", row: 7, value: map[string]any{"id": "1"}},
+		{sheet: "Sheet1", row: 12, value: map[string]any{"id": "2"}},
+	}, rows)
+}
+
+func TestXLSXScannerDuplicateHeaders(t *testing.T) {
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"price", "B", "price", "", "price", "price_2"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, 2, 3, 4, 5, 6}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	rows := scanWorkbook(t, `{}`, buf.Bytes())
+	assert.Equal(t, []scannedRow{
+		{sheet: "Sheet1", row: 2, value: map[string]any{
+			"price":   "1",
+			"B":       "2",
+			"price_3": "3",
+			"D":       "4",
+			"price_4": "5",
+			"price_2": "6",
+		}},
+	}, rows)
+}
@@ -67,3 +67,78 @@ func TestParseXML(t *testing.T) {
 		})
 	}
 }
+
+func TestXPath(t *testing.T) {
+	doc := `<library>
+  <book lang="en" id="1"><title>Dune</title><price>9.5</price></book>
+  <book lang="fr" id="2"><title>Vendredi</title><price>7</price></book>
+</library>`
+
+	testCases := []struct {
+		name    string
+		mapping string
+		exp     any
+		err     string
+	}{
+		{
+			name:    "node values",
+			mapping: `root = this.xpath("//book/title")`,
+			exp:     []any{"Dune", "Vendredi"},
+		},
+		{
+			name:    "attribute values",
+			mapping: `root = this.xpath("//book[@lang='fr']/@id")`,
+			exp:     []any{"2"},
+		},
+		{
+			name:    "no matches",
+			mapping: `root = this.xpath("//magazine")`,
+			exp:     []any{},
+		},
+		{
+			name:    "nodes as xml",
+			mapping: `root = this.xpath(expression: "//book[1]/title", xml: true)`,
+			exp:     []any{"<title>Dune</title>"},
+		},
+		{
+			name:    "number",
+			mapping: `root = this.xpath("sum(//price)")`,
+			exp:     16.5,
+		},
+		{
+			name:    "boolean",
+			mapping: `root = this.xpath("count(//book) = 2")`,
+			exp:     true,
+		},
+		{
+			name:    "string",
+			mapping: `root = this.xpath("string(//book[2]/title)")`,
+			exp:     "Vendredi",
+		},
+		{
+			name:    "invalid document",
+			mapping: `root = "not xml".xpath("//book")`,
+			err:     "failed to parse value as XML",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			exec, err := bloblang.Parse(test.mapping)
+			require.NoError(t, err)
+
+			res, err := exec.Query(doc)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.exp, res)
+		})
+	}
+}
+
+func TestXPathCompileError(t *testing.T) {
+	_, err := bloblang.Parse(`root = this.xpath("//book[")`)
+	require.ErrorContains(t, err, "failed to compile xpath expression")
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+
+	"github.com/redpanda-data/benthos/v4/public/bloblang"
+)
+
+func init() {
+	if err := bloblang.RegisterMethodV2("xpath",
+		bloblang.NewPluginSpec().
+			Beta().
+			Version("4.48.0").
+			Category("Parsing").
+			Description(`
+Executes an https://www.w3.org/TR/xpath/[XPath 1.0^] expression against a string containing an XML document, without converting the document into a structured value first.
+
+Expressions that select nodes return an array containing the string value of each matched node, or the XML of each matched node when `+"`xml`"+` is true. Expressions that evaluate to a number, string or boolean, such as `+"`count(//item)`"+`, return that value.
+`).
+			Example("", `root.titles = this.doc.xpath("//book[@lang='en']/title")`, [2]string{
+				`{"doc":"<library><book lang=\"en\"><title>Dune</title></book><book lang=\"fr\"><title>Vendredi</title></book><book lang=\"en\"><title>Emma</title></book></library>"}`,
+				`{"titles":["Dune","Emma"]}`,
+			}).
+			Example("", `root.total = this.doc.xpath("sum(//item/@price)")`, [2]string{
+				`{"doc":"<order><item price=\"2.5\"/><item price=\"4\"/></order>"}`,
+				`{"total":6.5}`,
+			}).
+			Example("", `root.first = this.doc.xpath(expression: "/order/item[1]", xml: true).index(0)`, [2]string{
+				`{"doc":"<order><item id=\"a\">foo</item><item id=\"b\">bar</item></order>"}`,
+				`{"first":"<item id=\"a\">foo</item>"}`,
+			}).
+			Param(bloblang.NewStringParam("expression").Description("The XPath expression to execute.")).
+			Param(bloblang.NewBoolParam("xml").
+				Description("Whether to return matched nodes as XML rather than their string values.").
+				Default(false)),
+		func(args *bloblang.ParsedParams) (bloblang.Method, error) {
+			exprStr, err := args.GetString("expression")
+			if err != nil {
+				return nil, err
+			}
+			asXML, err := args.GetBool("xml")
+			if err != nil {
+				return nil, err
+			}
+			expr, err := xpath.Compile(exprStr)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile xpath expression: %w", err)
+			}
+			return bloblang.BytesMethod(func(xmlBytes []byte) (any, error) {
+				doc, err := xmlquery.Parse(bytes.NewReader(xmlBytes))
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse value as XML: %w", err)
+				}
+				return evaluateXPath(expr, doc, asXML), nil
+			}), nil
+		}); err != nil {
+		panic(err)
+	}
+}
+
+func evaluateXPath(expr *xpath.Expr, doc *xmlquery.Node, asXML bool) any {
+	res := expr.Evaluate(xmlquery.CreateXPathNavigator(doc))
+	iter, ok := res.(*xpath.NodeIterator)
+	if !ok {
+		return res
+	}
+
+	values := []any{}
+	for iter.MoveNext() {
+		nav := iter.Current().(*xmlquery.NodeNavigator)
+		if asXML && nav.NodeType() != xpath.AttributeNode {
+			values = append(values, nav.Current().OutputXML(true))
+		} else {
+			values = append(values, nav.Value())
+		}
+	}
+	return values
+}
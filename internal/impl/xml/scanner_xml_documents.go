@@ -0,0 +1,257 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	xdsFieldElement = "element"
+)
+
+func xmlDocumentsScannerSpec() *service.ConfigSpec {
+	return service.NewConfigSpec().
+		Beta().
+		Summary("Consume a stream of XML documents, or of the repeated elements of a large XML document, as individual messages.").
+		Description(`
+The stream is decoded incrementally, and only the element being emitted is held in memory, which makes this scanner suitable for XML files that are too large to read in full.
+
+When `+"`element`"+` is empty each top level element of the stream is emitted as a message, which supports streams of concatenated XML documents. Otherwise each element with a matching local name is emitted as a message wherever it appears, elements nested within a matched element are not matched separately, and all other content is discarded.
+
+Messages contain the raw XML of each element exactly as it appears within the stream, and can be queried with the `+"`xpath`"+` Bloblang method or converted with the `+"`parse_xml`"+` method. Namespace declarations made by ancestors of an emitted element are added to its start tag, unless the element redeclares them, so that each message can be parsed on its own.
+
+== Metadata
+
+This scanner adds the following metadata to each message:
+
+- `+"`xml_element`"+`: The local name of the element.
+`).
+		Fields(
+			service.NewStringField(xdsFieldElement).
+				Description("The local name of the elements to emit. When empty each top level element is emitted.").
+				Default("").
+				Example("record"),
+		).
+		Example("Consuming Large Exports", "In this example we consume each `record` element of XML exports uploaded to a bucket.", `
+input:
+  aws_s3:
+    bucket: TODO
+    prefix: exports/
+    scanner:
+      xml_documents:
+        element: record
+`).
+		Version("4.48.0")
+}
+
+func init() {
+	err := service.RegisterBatchScannerCreator("xml_documents", xmlDocumentsScannerSpec(),
+		func(conf *service.ParsedConfig, mgr *service.Resources) (service.BatchScannerCreator, error) {
+			return xmlDocumentsScannerFromParsed(conf)
+		})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func xmlDocumentsScannerFromParsed(conf *service.ParsedConfig) (c *xmlDocumentsScannerCreator, err error) {
+	c = &xmlDocumentsScannerCreator{}
+	if c.element, err = conf.FieldString(xdsFieldElement); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type xmlDocumentsScannerCreator struct {
+	element string
+}
+
+func (c *xmlDocumentsScannerCreator) Create(rdr io.ReadCloser, aFn service.AckFunc, details *service.ScannerSourceDetails) (service.BatchScanner, error) {
+	rec := &recordingReader{r: rdr}
+	return service.AutoAggregateBatchScannerAcks(&xmlDocumentsScanner{
+		r:       rdr,
+		rec:     rec,
+		dec:     xml.NewDecoder(rec),
+		element: c.element,
+	}, aFn), nil
+}
+
+func (c *xmlDocumentsScannerCreator) Close(context.Context) error {
+	return nil
+}
+
+// recordingReader retains the bytes read from a reader since the last call to
+// discard, so that the raw bytes of decoded elements can be obtained from the
+// input offsets of a decoder.
+type recordingReader struct {
+	r    io.Reader
+	buf  []byte
+	base int64
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.buf = append(r.buf, p[:n]...)
+	return n, err
+}
+
+func (r *recordingReader) discard(offset int64) {
+	r.buf = append(r.buf[:0], r.buf[offset-r.base:]...)
+	r.base = offset
+}
+
+func (r *recordingReader) slice(from, to int64) []byte {
+	return r.buf[from-r.base : to-r.base]
+}
+
+type xmlDocumentsScanner struct {
+	r       io.ReadCloser
+	rec     *recordingReader
+	dec     *xml.Decoder
+	element string
+
+	// The namespace declarations of each open element.
+	namespaces [][]xml.Attr
+}
+
+func (s *xmlDocumentsScanner) NextBatch(ctx context.Context) (service.MessageBatch, error) {
+	if s.r == nil {
+		return nil, io.EOF
+	}
+
+	var (
+		start    int64
+		name     string
+		inherit  []xml.Attr
+		capDepth = -1
+	)
+	for {
+		if capDepth < 0 {
+			s.rec.discard(s.dec.InputOffset())
+		}
+		offset := s.dec.InputOffset()
+
+		tok, err := s.dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				if capDepth >= 0 {
+					return nil, fmt.Errorf("unexpected end of stream within element %v", name)
+				}
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to decode XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth := len(s.namespaces)
+			decls := namespaceDecls(t.Attr)
+			if capDepth < 0 && ((s.element == "" && depth == 0) || (s.element != "" && t.Name.Local == s.element)) {
+				start, name, capDepth = offset, t.Name.Local, depth
+				inherit = s.inheritedDecls(decls)
+			}
+			s.namespaces = append(s.namespaces, decls)
+		case xml.EndElement:
+			s.namespaces = s.namespaces[:len(s.namespaces)-1]
+			if capDepth >= 0 && len(s.namespaces) == capDepth {
+				raw := s.rec.slice(start, s.dec.InputOffset())
+				msg := service.NewMessage(withNamespaceDecls(raw, inherit))
+				msg.MetaSetMut("xml_element", name)
+				return service.MessageBatch{msg}, nil
+			}
+		}
+	}
+}
+
+// namespaceDecls returns the namespace declarations of the attributes of an
+// element.
+func namespaceDecls(attrs []xml.Attr) (decls []xml.Attr) {
+	for _, a := range attrs {
+		if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+			decls = append(decls, a)
+		}
+	}
+	return
+}
+
+// inheritedDecls returns the namespace declarations in scope from the open
+// elements, other than those redeclared by an element with the declarations
+// decls, sorted by their attribute name.
+func (s *xmlDocumentsScanner) inheritedDecls(decls []xml.Attr) []xml.Attr {
+	inScope := map[xml.Name]struct{}{}
+	for _, d := range decls {
+		inScope[d.Name] = struct{}{}
+	}
+	var inherit []xml.Attr
+	for i := len(s.namespaces) - 1; i >= 0; i-- {
+		for _, d := range s.namespaces[i] {
+			if _, exists := inScope[d.Name]; exists {
+				continue
+			}
+			inScope[d.Name] = struct{}{}
+			inherit = append(inherit, d)
+		}
+	}
+	slices.SortFunc(inherit, func(a, b xml.Attr) int {
+		if c := strings.Compare(a.Name.Space, b.Name.Space); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name.Local, b.Name.Local)
+	})
+	return inherit
+}
+
+// withNamespaceDecls returns a copy of the raw XML of an element with the
+// namespace declarations decls added to its start tag.
+func withNamespaceDecls(raw []byte, decls []xml.Attr) []byte {
+	if len(decls) == 0 {
+		return append([]byte(nil), raw...)
+	}
+
+	nameEnd := 1 + bytes.IndexAny(raw[1:], " \t\r\n/>")
+
+	var buf bytes.Buffer
+	buf.Write(raw[:nameEnd])
+	for _, d := range decls {
+		buf.WriteByte(' ')
+		if d.Name.Space != "" {
+			buf.WriteString(d.Name.Space)
+			buf.WriteByte(':')
+		}
+		buf.WriteString(d.Name.Local)
+		buf.WriteString(`="`)
+		_ = xml.EscapeText(&buf, []byte(d.Value))
+		buf.WriteByte('"')
+	}
+	buf.Write(raw[nameEnd:])
+	return buf.Bytes()
+}
+
+func (s *xmlDocumentsScanner) Close(ctx context.Context) error {
+	if s.r == nil {
+		return nil
+	}
+	return s.r.Close()
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xml
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type scannedElement struct {
+	name string
+	raw  string
+}
+
+func scanXMLDocuments(t *testing.T, conf string, r io.Reader) (elements []scannedElement, err error) {
+	t.Helper()
+
+	pConf, perr := xmlDocumentsScannerSpec().ParseYAML(conf, nil)
+	require.NoError(t, perr)
+
+	creator, perr := xmlDocumentsScannerFromParsed(pConf)
+	require.NoError(t, perr)
+
+	scanner, perr := creator.Create(io.NopCloser(r), func(context.Context, error) error { return nil }, service.NewScannerSourceDetails())
+	require.NoError(t, perr)
+	defer scanner.Close(context.Background())
+
+	for {
+		batch, _, err := scanner.NextBatch(context.Background())
+		if errors.Is(err, io.EOF) {
+			return elements, nil
+		}
+		if err != nil {
+			return elements, err
+		}
+		require.Len(t, batch, 1)
+
+		b, err := batch[0].AsBytes()
+		require.NoError(t, err)
+		name, _ := batch[0].MetaGetMut("xml_element")
+		elements = append(elements, scannedElement{name: name.(string), raw: string(b)})
+	}
+}
+
+func TestXMLDocumentsScannerTopLevel(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<a id="1"><b>foo</b></a>
+<!-- a comment -->
+<c/>
+<a id="2">bar &amp; baz</a>
+`
+	for name, r := range map[string]func() io.Reader{
+		"whole":    func() io.Reader { return strings.NewReader(input) },
+		"one byte": func() io.Reader { return iotest.OneByteReader(strings.NewReader(input)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			elements, err := scanXMLDocuments(t, ``, r())
+			require.NoError(t, err)
+			assert.Equal(t, []scannedElement{
+				{name: "a", raw: `<a id="1"><b>foo</b></a>`},
+				{name: "c", raw: `<c/>`},
+				{name: "a", raw: `<a id="2">bar &amp; baz</a>`},
+			}, elements)
+		})
+	}
+}
+
+func TestXMLDocumentsScannerElement(t *testing.T) {
+	input := `<export>
+  <meta><record>in meta</record></meta>
+  <records>
+    <record id="1"><name>foo</name><record>nested</record></record>
+    <other/>
+    <ns:record xmlns:ns="urn:x" id="2"/>
+  </records>
+</export>`
+
+	elements, err := scanXMLDocuments(t, `element: record`, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []scannedElement{
+		{name: "record", raw: `<record>in meta</record>`},
+		{name: "record", raw: `<record id="1"><name>foo</name><record>nested</record></record>`},
+		{name: "record", raw: `<ns:record xmlns:ns="urn:x" id="2"/>`},
+	}, elements)
+}
+
+func TestXMLDocumentsScannerTruncated(t *testing.T) {
+	elements, err := scanXMLDocuments(t, ``, strings.NewReader(`<a>foo</a><b><c>bar`))
+	require.Error(t, err)
+	assert.Equal(t, []scannedElement{{name: "a", raw: `<a>foo</a>`}}, elements)
+}
+
+func TestXMLDocumentsScannerNamespaces(t *testing.T) {
+	input := `<feed xmlns="urn:feed" xmlns:a="urn:a&amp;b" xmlns:b="urn:b">
+  <group xmlns:b="urn:b2">
+    <a:record id="1"><b:value>foo</b:value></a:record>
+    <a:record
+      xmlns:a="urn:other" id="2"/>
+  </group>
+  <a:record>bar</a:record>
+</feed>`
+
+	elements, err := scanXMLDocuments(t, `element: record`, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, []scannedElement{
+		{name: "record", raw: `<a:record xmlns="urn:feed" xmlns:a="urn:a&amp;b" xmlns:b="urn:b2" id="1"><b:value>foo</b:value></a:record>`},
+		{name: "record", raw: `<a:record xmlns="urn:feed" xmlns:b="urn:b2"
+      xmlns:a="urn:other" id="2"/>`},
+		{name: "record", raw: `<a:record xmlns="urn:feed" xmlns:a="urn:a&amp;b" xmlns:b="urn:b">bar</a:record>`},
+	}, elements)
+
+	for i, space := range []string{"urn:a&b", "urn:other", "urn:a&b"} {
+		var doc struct {
+			XMLName xml.Name
+		}
+		require.NoError(t, xml.Unmarshal([]byte(elements[i].raw), &doc))
+		assert.Equal(t, xml.Name{Space: space, Local: "record"}, doc.XMLName)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lifecycle provides hooks that run processors at points within the
+// lifecycle of a Redpanda Connect instance.
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	fieldOnStart = "on_start"
+	fieldOnClose = "on_close"
+	fieldOnError = "on_error"
+	fieldTimeout = "timeout"
+)
+
+const (
+	eventStart = "start"
+	eventClose = "close"
+	eventError = "error"
+)
+
+// Fields returns the config fields of lifecycle hooks, which are expected to
+// be nested within a top-level object field.
+func Fields() []*service.ConfigField {
+	return []*service.ConfigField{
+		service.NewProcessorListField(fieldOnStart).
+			Description("A list of processors to execute once the stream has started.").
+			Optional(),
+		service.NewProcessorListField(fieldOnClose).
+			Description("A list of processors to execute once the stream has stopped without errors. The stream resources are closed by this point and therefore cannot be referenced by these processors.").
+			Optional(),
+		service.NewProcessorListField(fieldOnError).
+			Description("A list of processors to execute once the stream has stopped due to an error. The stream resources are closed by this point and therefore cannot be referenced by these processors.").
+			Optional(),
+		service.NewDurationField(fieldTimeout).
+			Description("The maximum period of time that each hook is allowed to run for.").
+			Default("10s"),
+	}
+}
+
+// Hooks executes the processors configured for lifecycle events.
+type Hooks struct {
+	instanceID string
+
+	mut     sync.Mutex
+	log     *service.Logger
+	timeout time.Duration
+	onStart []*service.OwnedProcessor
+	onClose []*service.OwnedProcessor
+	onError []*service.OwnedProcessor
+}
+
+// New creates lifecycle hooks for an instance, the hooks do nothing until they
+// are initialised from a parsed config.
+func New(instanceID string) *Hooks {
+	return &Hooks{instanceID: instanceID}
+}
+
+// InitFromParsed replaces the hooks with those configured within a parsed
+// config, the config is expected to be namespaced to the fields returned by
+// Fields.
+func (h *Hooks) InitFromParsed(pConf *service.ParsedConfig) error {
+	timeout, err := pConf.FieldDuration(fieldTimeout)
+	if err != nil {
+		return err
+	}
+
+	procs := map[string][]*service.OwnedProcessor{}
+	for _, field := range []string{fieldOnStart, fieldOnClose, fieldOnError} {
+		if !pConf.Contains(field) {
+			continue
+		}
+		if procs[field], err = pConf.FieldProcessorList(field); err != nil {
+			closeProcessors(procs)
+			return err
+		}
+	}
+
+	h.mut.Lock()
+	previous := map[string][]*service.OwnedProcessor{
+		fieldOnStart: h.onStart,
+		fieldOnClose: h.onClose,
+		fieldOnError: h.onError,
+	}
+	h.log = pConf.Resources().Logger()
+	h.timeout = timeout
+	h.onStart = procs[fieldOnStart]
+	h.onClose = procs[fieldOnClose]
+	h.onError = procs[fieldOnError]
+	h.mut.Unlock()
+
+	closeProcessors(previous)
+	return nil
+}
+
+// TriggerStart executes the hook for when the stream has started.
+func (h *Hooks) TriggerStart(ctx context.Context) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	h.run(ctx, eventStart, h.onStart, nil)
+}
+
+// TriggerStopped executes the hook for when the stream has stopped, either
+// cleanly or due to an issue described in the provided error.
+func (h *Hooks) TriggerStopped(ctx context.Context, err error) {
+	h.mut.Lock()
+	defer h.mut.Unlock()
+
+	if err != nil {
+		h.run(ctx, eventError, h.onError, err)
+	} else {
+		h.run(ctx, eventClose, h.onClose, nil)
+	}
+}
+
+// Close all processors of the hooks.
+func (h *Hooks) Close() {
+	h.mut.Lock()
+	procs := map[string][]*service.OwnedProcessor{
+		fieldOnStart: h.onStart,
+		fieldOnClose: h.onClose,
+		fieldOnError: h.onError,
+	}
+	h.onStart, h.onClose, h.onError = nil, nil, nil
+	h.mut.Unlock()
+
+	closeProcessors(procs)
+}
+
+func (h *Hooks) run(ctx context.Context, event string, procs []*service.OwnedProcessor, stopErr error) {
+	if len(procs) == 0 {
+		return
+	}
+
+	ctx, done := context.WithTimeout(ctx, h.timeout)
+	defer done()
+
+	body := map[string]any{
+		"event":       event,
+		"instance_id": h.instanceID,
+		"timestamp":   time.Now().Format(time.RFC3339Nano),
+	}
+	if stopErr != nil {
+		body["error"] = stopErr.Error()
+	}
+
+	msg := service.NewMessage(nil)
+	msg.SetStructuredMut(body)
+
+	batches, err := service.ExecuteProcessors(ctx, procs, service.MessageBatch{msg})
+	if err != nil {
+		h.log.Errorf("Failed to execute %v lifecycle hook: %v", event, err)
+		return
+	}
+	for _, b := range batches {
+		for _, m := range b {
+			if err := m.GetError(); err != nil {
+				h.log.Errorf("Failed to execute %v lifecycle hook: %v", event, err)
+			}
+		}
+	}
+}
+
+func closeProcessors(procs map[string][]*service.OwnedProcessor) {
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	defer done()
+
+	for _, pList := range procs {
+		for _, p := range pList {
+			_ = p.Close(ctx)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+
+	_ "github.com/redpanda-data/benthos/v4/public/components/pure"
+)
+
+type captureProc struct {
+	mut    *sync.Mutex
+	events *[]map[string]any
+}
+
+func (c captureProc) Process(ctx context.Context, msg *service.Message) (service.MessageBatch, error) {
+	v, err := msg.AsStructured()
+	if err != nil {
+		return nil, err
+	}
+	c.mut.Lock()
+	*c.events = append(*c.events, v.(map[string]any))
+	c.mut.Unlock()
+	return service.MessageBatch{msg}, nil
+}
+
+func (c captureProc) Close(ctx context.Context) error {
+	return nil
+}
+
+func hooksFromYAML(t *testing.T, conf string) (*Hooks, func() []map[string]any) {
+	t.Helper()
+
+	var mut sync.Mutex
+	var events []map[string]any
+
+	env := service.NewEnvironment()
+	require.NoError(t, env.RegisterProcessor("capture", service.NewConfigSpec(),
+		func(*service.ParsedConfig, *service.Resources) (service.Processor, error) {
+			return captureProc{mut: &mut, events: &events}, nil
+		}))
+
+	pConf, err := service.NewConfigSpec().Fields(Fields()...).ParseYAML(conf, env)
+	require.NoError(t, err)
+
+	h := New("foo")
+	require.NoError(t, h.InitFromParsed(pConf))
+	t.Cleanup(h.Close)
+
+	return h, func() []map[string]any {
+		mut.Lock()
+		defer mut.Unlock()
+		return events
+	}
+}
+
+func TestHooksCleanStop(t *testing.T) {
+	h, events := hooksFromYAML(t, `
+on_start:
+  - mapping: 'root = this.merge({"hook": "start"})'
+  - capture: {}
+on_close:
+  - mapping: 'root = this.merge({"hook": "close"})'
+  - capture: {}
+on_error:
+  - mapping: 'root = this.merge({"hook": "error"})'
+  - capture: {}
+`)
+
+	h.TriggerStart(context.Background())
+	h.TriggerStopped(context.Background(), nil)
+
+	got := events()
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "start", got[0]["event"])
+	assert.Equal(t, "start", got[0]["hook"])
+	assert.Equal(t, "foo", got[0]["instance_id"])
+	assert.NotEmpty(t, got[0]["timestamp"])
+	assert.NotContains(t, got[0], "error")
+
+	assert.Equal(t, "close", got[1]["event"])
+	assert.Equal(t, "close", got[1]["hook"])
+}
+
+func TestHooksErrorStop(t *testing.T) {
+	h, events := hooksFromYAML(t, `
+on_close:
+  - capture: {}
+on_error:
+  - capture: {}
+`)
+
+	h.TriggerStart(context.Background())
+	h.TriggerStopped(context.Background(), errors.New("uh oh"))
+
+	got := events()
+	require.Len(t, got, 1)
+	assert.Equal(t, "error", got[0]["event"])
+	assert.Equal(t, "uh oh", got[0]["error"])
+}
+
+func TestHooksFailingProcessor(t *testing.T) {
+	h, events := hooksFromYAML(t, `
+on_start:
+  - mapping: 'root = throw("nope")'
+  - capture: {}
+`)
+
+	// Errors are logged and the message continues through the chain.
+	h.TriggerStart(context.Background())
+	require.Len(t, events(), 1)
+}
+
+func TestHooksNoneConfigured(t *testing.T) {
+	h, events := hooksFromYAML(t, ``)
+
+	h.TriggerStart(context.Background())
+	h.TriggerStopped(context.Background(), errors.New("uh oh"))
+	assert.Empty(t, events())
+}
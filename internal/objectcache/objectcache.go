@@ -0,0 +1,177 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package objectcache contains helpers shared by caches that store items as
+// objects within a bucket, such as S3 and Google Cloud Storage.
+package objectcache
+
+import (
+	"context"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// ExpiresAtKey is the object metadata key used to store the time at which
+	// an item expires.
+	ExpiresAtKey = "expires-at"
+
+	lcFieldLocalCache = "local_cache"
+	lcFieldSize       = "size"
+	lcFieldTTL        = "ttl"
+)
+
+// ExpiresAt returns the formatted expiry metadata value of an item cached now
+// with the provided TTL, and false if the item should not expire.
+func ExpiresAt(now time.Time, ttl *time.Duration) (string, bool) {
+	if ttl == nil || *ttl <= 0 {
+		return "", false
+	}
+	return now.Add(*ttl).UTC().Format(time.RFC3339Nano), true
+}
+
+// Expired returns true if the expiry metadata value of an item has passed.
+// Values that cannot be parsed are treated as never expiring.
+func Expired(now time.Time, expiresAt string) bool {
+	if expiresAt == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339Nano, expiresAt)
+	if err != nil {
+		return false
+	}
+	return !now.Before(t)
+}
+
+// LocalCacheField returns a config field for configuring an in-process read
+// through layer in front of an object store cache.
+func LocalCacheField() *service.ConfigField {
+	return service.NewObjectField(lcFieldLocalCache,
+		service.NewIntField(lcFieldSize).
+			Description("The maximum number of items to keep in memory. When set to zero the local layer is disabled and all reads go to the bucket.").
+			Default(0),
+		service.NewDurationField(lcFieldTTL).
+			Description("The maximum period of time an item is served from memory before it is read from the bucket again. Changes made to the bucket by other processes may not be observed until this period has passed.").
+			Default("5m"),
+	).
+		Description("An optional in-process LRU layer that items read from or written to the bucket are kept within, which reduces the number of requests made for frequently accessed keys.").
+		Version("4.48.0").
+		Advanced()
+}
+
+// WrapFromParsed wraps a cache with a local read through layer as configured
+// by a field created with LocalCacheField. If the layer is disabled the cache
+// is returned unchanged.
+func WrapFromParsed(conf *service.ParsedConfig, c service.Cache) (service.Cache, error) {
+	lConf := conf.Namespace(lcFieldLocalCache)
+	size, err := lConf.FieldInt(lcFieldSize)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := lConf.FieldDuration(lcFieldTTL)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(c, size, ttl)
+}
+
+// Wrap returns a cache that keeps up to size items from c in memory for at
+// most ttl. Reads are served from memory when possible, and writes go to both
+// memory and c. If size is zero or less c is returned unchanged.
+func Wrap(c service.Cache, size int, ttl time.Duration) (service.Cache, error) {
+	if size <= 0 {
+		return c, nil
+	}
+	items, err := lru.New[string, localItem](size)
+	if err != nil {
+		return nil, err
+	}
+	return &localCache{
+		remote: c,
+		items:  items,
+		ttl:    ttl,
+		nowFn:  time.Now,
+	}, nil
+}
+
+type localItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+type localCache struct {
+	remote service.Cache
+	items  *lru.Cache[string, localItem]
+	ttl    time.Duration
+	nowFn  func() time.Time
+}
+
+func (l *localCache) expiry(ttl *time.Duration) time.Time {
+	d := l.ttl
+	if ttl != nil && *ttl > 0 && *ttl < d {
+		d = *ttl
+	}
+	return l.nowFn().Add(d)
+}
+
+func (l *localCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if item, ok := l.items.Get(key); ok {
+		if l.nowFn().Before(item.expiresAt) {
+			return item.value, nil
+		}
+		l.items.Remove(key)
+	}
+
+	value, err := l.remote.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// A value written concurrently with this read takes precedence.
+	l.items.ContainsOrAdd(key, localItem{value: value, expiresAt: l.expiry(nil)})
+	return value, nil
+}
+
+func (l *localCache) Set(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	l.items.Remove(key)
+	if err := l.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	l.items.Add(key, localItem{value: value, expiresAt: l.expiry(ttl)})
+	return nil
+}
+
+func (l *localCache) Add(ctx context.Context, key string, value []byte, ttl *time.Duration) error {
+	// The remote cache is the source of truth for whether a key exists, so
+	// the local layer is always bypassed here.
+	l.items.Remove(key)
+	if err := l.remote.Add(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	l.items.Add(key, localItem{value: value, expiresAt: l.expiry(ttl)})
+	return nil
+}
+
+func (l *localCache) Delete(ctx context.Context, key string) error {
+	l.items.Remove(key)
+	return l.remote.Delete(ctx, key)
+}
+
+func (l *localCache) Close(ctx context.Context) error {
+	l.items.Purge()
+	return l.remote.Close(ctx)
+}
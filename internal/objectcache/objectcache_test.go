@@ -0,0 +1,176 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type fakeRemote struct {
+	items map[string][]byte
+	gets  int
+}
+
+func (f *fakeRemote) Get(_ context.Context, key string) ([]byte, error) {
+	f.gets++
+	v, ok := f.items[key]
+	if !ok {
+		return nil, service.ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func (f *fakeRemote) Set(_ context.Context, key string, value []byte, _ *time.Duration) error {
+	f.items[key] = value
+	return nil
+}
+
+func (f *fakeRemote) Add(_ context.Context, key string, value []byte, _ *time.Duration) error {
+	if _, ok := f.items[key]; ok {
+		return service.ErrKeyAlreadyExists
+	}
+	f.items[key] = value
+	return nil
+}
+
+func (f *fakeRemote) Delete(_ context.Context, key string) error {
+	delete(f.items, key)
+	return nil
+}
+
+func (f *fakeRemote) Close(context.Context) error {
+	return nil
+}
+
+func TestExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ok := ExpiresAt(now, nil)
+	assert.False(t, ok)
+
+	ttl := time.Minute
+	v, ok := ExpiresAt(now, &ttl)
+	require.True(t, ok)
+	assert.Equal(t, "2025-01-01T00:01:00Z", v)
+
+	assert.False(t, Expired(now, v))
+	assert.True(t, Expired(now.Add(time.Minute), v))
+	assert.False(t, Expired(now, ""))
+	assert.False(t, Expired(now, "not a time"))
+}
+
+func TestLocalCacheDisabled(t *testing.T) {
+	remote := &fakeRemote{items: map[string][]byte{}}
+	c, err := Wrap(remote, 0, time.Minute)
+	require.NoError(t, err)
+	assert.Same(t, remote, c)
+}
+
+func TestLocalCacheReadThrough(t *testing.T) {
+	ctx := context.Background()
+	remote := &fakeRemote{items: map[string][]byte{"foo": []byte("bar")}}
+	c, err := Wrap(remote, 10, time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	c.(*localCache).nowFn = func() time.Time { return now }
+
+	for range 3 {
+		v, err := c.Get(ctx, "foo")
+		require.NoError(t, err)
+		assert.Equal(t, "bar", string(v))
+	}
+	assert.Equal(t, 1, remote.gets)
+
+	_, err = c.Get(ctx, "baz")
+	require.ErrorIs(t, err, service.ErrKeyNotFound)
+	assert.Equal(t, 2, remote.gets)
+
+	now = now.Add(time.Minute)
+	_, err = c.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, 3, remote.gets)
+}
+
+func TestLocalCacheWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	remote := &fakeRemote{items: map[string][]byte{}}
+	c, err := Wrap(remote, 10, time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	c.(*localCache).nowFn = func() time.Time { return now }
+
+	require.NoError(t, c.Set(ctx, "foo", []byte("bar"), nil))
+	assert.Equal(t, "bar", string(remote.items["foo"]))
+
+	v, err := c.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(v))
+	assert.Equal(t, 0, remote.gets)
+
+	require.ErrorIs(t, c.Add(ctx, "foo", []byte("baz"), nil), service.ErrKeyAlreadyExists)
+	v, err = c.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(v))
+	assert.Equal(t, 1, remote.gets)
+
+	require.NoError(t, c.Delete(ctx, "foo"))
+	_, err = c.Get(ctx, "foo")
+	require.ErrorIs(t, err, service.ErrKeyNotFound)
+
+	ttl := time.Second
+	require.NoError(t, c.Add(ctx, "foo", []byte("qux"), &ttl))
+	remote.items["foo"] = []byte("changed")
+
+	v, err = c.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "qux", string(v))
+
+	// The item TTL is shorter than the local TTL and therefore takes priority.
+	now = now.Add(time.Second)
+	v, err = c.Get(ctx, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "changed", string(v))
+}
+
+func TestLocalCacheFromParsed(t *testing.T) {
+	spec := service.NewConfigSpec().Field(LocalCacheField())
+
+	conf, err := spec.ParseYAML(``, nil)
+	require.NoError(t, err)
+	remote := &fakeRemote{items: map[string][]byte{}}
+	c, err := WrapFromParsed(conf, remote)
+	require.NoError(t, err)
+	assert.Same(t, remote, c)
+
+	conf, err = spec.ParseYAML(`
+local_cache:
+  size: 5
+  ttl: 10s
+`, nil)
+	require.NoError(t, err)
+	c, err = WrapFromParsed(conf, remote)
+	require.NoError(t, err)
+	require.IsType(t, &localCache{}, c)
+	assert.Equal(t, 10*time.Second, c.(*localCache).ttl)
+}
@@ -0,0 +1,338 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rolling implements accumulating the messages written to object
+// storage outputs into files which are rotated by size, record count or age.
+package rolling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Jeffail/shutdown"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+const (
+	// FieldRolling is the name of the rolling config field.
+	FieldRolling    = "rolling"
+	fieldEnabled    = "enabled"
+	fieldFormat     = "format"
+	fieldMaxBytes   = "max_bytes"
+	fieldMaxRecords = "max_records"
+	fieldMaxAge     = "max_age"
+)
+
+// ConfigField returns the config field of the rolling mode of an output, which
+// links to a rolling-files section in the documentation of the output.
+func ConfigField() *service.ConfigField {
+	return service.NewObjectField(FieldRolling,
+		service.NewBoolField(fieldEnabled).
+			Description("Whether to accumulate messages into rolling files.").
+			Default(false),
+		service.NewStringAnnotatedEnumField(fieldFormat, map[string]string{
+			"lines":   "Messages are written to files each followed by a line break.",
+			"raw":     "Messages are written to files without separators.",
+			"parquet": "Each message must be a Parquet file, such as those encoded by the `parquet_encode` processor, of which the row groups are appended to files. A file is rotated early when a message has a different schema to the messages already in it, and files are compressed with the codec of their first message.",
+		}).
+			Description("The format of files.").
+			Default("lines"),
+		service.NewIntField(fieldMaxBytes).
+			Description("The size in bytes of the messages of a file at which it's rotated. For the `parquet` format this is the size of the encoded messages, and the uploaded file may differ in size. Set to zero to disable.").
+			Default(64*1024*1024),
+		service.NewIntField(fieldMaxRecords).
+			Description("The number of records in a file at which it's rotated, which is the number of messages, or rows for the `parquet` format. Set to zero to disable.").
+			Default(0),
+		service.NewDurationField(fieldMaxAge).
+			Description("The maximum period of time after a file receives its first message before it's rotated.").
+			Default("5m"),
+	).
+		Description("Accumulate messages into rolling files which are uploaded as single objects once rotated, rather than uploading an object per message. See <<rolling-files>>.").
+		Advanced().
+		Version("4.48.0")
+}
+
+// Config contains the rolling mode config of an output.
+type Config struct {
+	Format     string
+	MaxBytes   int
+	MaxRecords int
+	MaxAge     time.Duration
+}
+
+// ConfigFromParsed extracts the rolling mode config from a parsed config, and
+// returns nil when the rolling mode isn't enabled.
+func ConfigFromParsed(pConf *service.ParsedConfig) (*Config, error) {
+	pConf = pConf.Namespace(FieldRolling)
+	if enabled, err := pConf.FieldBool(fieldEnabled); err != nil || !enabled {
+		return nil, err
+	}
+
+	var conf Config
+	var err error
+	if conf.Format, err = pConf.FieldString(fieldFormat); err != nil {
+		return nil, err
+	}
+	if conf.MaxBytes, err = pConf.FieldInt(fieldMaxBytes); err != nil {
+		return nil, err
+	}
+	if conf.MaxRecords, err = pConf.FieldInt(fieldMaxRecords); err != nil {
+		return nil, err
+	}
+	if conf.MaxAge, err = pConf.FieldDuration(fieldMaxAge); err != nil {
+		return nil, err
+	}
+	if conf.MaxAge <= 0 {
+		return nil, errors.New("rolling max_age must be greater than zero")
+	}
+	return &conf, nil
+}
+
+//------------------------------------------------------------------------------
+
+var errRollingSchemaChanged = errors.New("parquet schema differs from the schema of the file")
+
+// file is a file which accumulates messages until it's rotated.
+type file struct {
+	format string
+	first  *service.Message
+	opened time.Time
+
+	buf      bytes.Buffer
+	pqWriter *parquet.Writer
+	size     int
+	records  int
+
+	// done is closed once the file is uploaded, with err set to the result.
+	done chan struct{}
+	err  error
+}
+
+func newFile(format string, first *service.Message) *file {
+	return &file{
+		format: format,
+		first:  first,
+		opened: time.Now(),
+		done:   make(chan struct{}),
+	}
+}
+
+// add appends a message to the file, where Parquet messages are passed as an
+// opened file.
+func (f *file) add(b []byte, pqFile *parquet.File) error {
+	switch f.format {
+	case "lines":
+		f.buf.Write(b)
+		f.buf.WriteByte('\n')
+		f.size += len(b) + 1
+		f.records++
+	case "raw":
+		f.buf.Write(b)
+		f.size += len(b)
+		f.records++
+	case "parquet":
+		if f.pqWriter == nil {
+			opts := []parquet.WriterOption{pqFile.Schema()}
+			if rgs := pqFile.Metadata().RowGroups; len(rgs) > 0 && len(rgs[0].Columns) > 0 {
+				opts = append(opts, parquet.Compression(parquet.LookupCompressionCodec(rgs[0].Columns[0].MetaData.Codec)))
+			}
+			f.pqWriter = parquet.NewWriter(&f.buf, opts...)
+		}
+		for _, rg := range pqFile.RowGroups() {
+			n, err := f.pqWriter.WriteRowGroup(rg)
+			if errors.Is(err, parquet.ErrRowGroupSchemaMismatch) {
+				return errRollingSchemaChanged
+			}
+			if err != nil {
+				return err
+			}
+			f.records += int(n)
+		}
+		f.size += len(b)
+	}
+	return nil
+}
+
+func (f *file) full(conf *Config) bool {
+	return (conf.MaxBytes > 0 && f.size >= conf.MaxBytes) ||
+		(conf.MaxRecords > 0 && f.records >= conf.MaxRecords)
+}
+
+func (f *file) bytes() ([]byte, error) {
+	if f.pqWriter != nil {
+		if err := f.pqWriter.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return f.buf.Bytes(), nil
+}
+
+// UploadFn uploads the contents of a file, with the fields of the object
+// interpolated from the first message of the file.
+type UploadFn func(ctx context.Context, first *service.Message, body []byte) error
+
+// Roller accumulates the messages of batches into rolling files, and blocks
+// writes of batches until the files they were added to are uploaded.
+type Roller struct {
+	conf   *Config
+	upload UploadFn
+	log    *service.Logger
+
+	mut     sync.Mutex
+	cur     *file
+	flushes sync.WaitGroup
+	shutSig *shutdown.Signaller
+}
+
+// NewRoller creates a roller which uploads files with the provided function.
+func NewRoller(conf *Config, upload UploadFn, log *service.Logger) *Roller {
+	r := &Roller{
+		conf:    conf,
+		upload:  upload,
+		log:     log,
+		shutSig: shutdown.NewSignaller(),
+	}
+	go r.loop()
+	return r
+}
+
+func (r *Roller) loop() {
+	defer r.shutSig.TriggerHasStopped()
+
+	ticker := time.NewTicker(max(r.conf.MaxAge/10, 10*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.mut.Lock()
+			if r.cur != nil && time.Since(r.cur.opened) >= r.conf.MaxAge {
+				r.rotateLocked()
+			}
+			r.mut.Unlock()
+		case <-r.shutSig.SoftStopChan():
+			return
+		}
+	}
+}
+
+// WriteBatch adds the messages of a batch to the current file, rotating it as
+// needed, and blocks until the files the messages were added to are uploaded.
+func (r *Roller) WriteBatch(ctx context.Context, batch service.MessageBatch) error {
+	contents := make([][]byte, len(batch))
+	pqFiles := make([]*parquet.File, len(batch))
+	for i, m := range batch {
+		var err error
+		if contents[i], err = m.AsBytes(); err != nil {
+			return err
+		}
+		if r.conf.Format == "parquet" {
+			if pqFiles[i], err = parquet.OpenFile(bytes.NewReader(contents[i]), int64(len(contents[i]))); err != nil {
+				return fmt.Errorf("failed to open parquet file of message %v: %w", i, err)
+			}
+		}
+	}
+
+	var files []*file
+	r.mut.Lock()
+	for i, m := range batch {
+		if r.cur == nil {
+			r.cur = newFile(r.conf.Format, m)
+		}
+		err := r.cur.add(contents[i], pqFiles[i])
+		if errors.Is(err, errRollingSchemaChanged) {
+			r.rotateLocked()
+			r.cur = newFile(r.conf.Format, m)
+			err = r.cur.add(contents[i], pqFiles[i])
+		}
+		if err != nil {
+			r.mut.Unlock()
+			return err
+		}
+		if len(files) == 0 || files[len(files)-1] != r.cur {
+			files = append(files, r.cur)
+		}
+		if r.cur.full(r.conf) {
+			r.rotateLocked()
+		}
+	}
+	r.mut.Unlock()
+
+	for _, f := range files {
+		select {
+		case <-f.done:
+			if f.err != nil {
+				return f.err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// rotateLocked uploads the current file in the background, and must be called
+// with the mutex held.
+func (r *Roller) rotateLocked() {
+	f := r.cur
+	r.cur = nil
+	if f == nil {
+		return
+	}
+
+	r.flushes.Add(1)
+	go func() {
+		defer r.flushes.Done()
+		defer close(f.done)
+
+		body, err := f.bytes()
+		if err != nil {
+			f.err = fmt.Errorf("failed to finish file: %w", err)
+			return
+		}
+
+		ctx, done := r.shutSig.HardStopCtx(context.Background())
+		defer done()
+		if f.err = r.upload(ctx, f.first, body); f.err != nil {
+			r.log.Errorf("Failed to upload rolling file of %v records: %v", f.records, f.err)
+		}
+	}()
+}
+
+// Close uploads the current file and waits for all uploads to complete.
+func (r *Roller) Close(ctx context.Context) error {
+	r.shutSig.TriggerSoftStop()
+	r.mut.Lock()
+	r.rotateLocked()
+	r.mut.Unlock()
+
+	flushed := make(chan struct{})
+	go func() {
+		r.flushes.Wait()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+	case <-ctx.Done():
+		r.shutSig.TriggerHardStop()
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rolling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/benthos/v4/public/service"
+)
+
+type rollingUploads struct {
+	mut   sync.Mutex
+	files []string
+	err   error
+}
+
+func (u *rollingUploads) upload(_ context.Context, first *service.Message, body []byte) error {
+	u.mut.Lock()
+	defer u.mut.Unlock()
+	if u.err != nil {
+		return u.err
+	}
+	u.files = append(u.files, string(body))
+	return nil
+}
+
+func (u *rollingUploads) get() []string {
+	u.mut.Lock()
+	defer u.mut.Unlock()
+	return append([]string(nil), u.files...)
+}
+
+func rollingBatch(contents ...string) service.MessageBatch {
+	var b service.MessageBatch
+	for _, c := range contents {
+		b = append(b, service.NewMessage([]byte(c)))
+	}
+	return b
+}
+
+func TestRollingConfig(t *testing.T) {
+	spec := service.NewConfigSpec().Field(ConfigField())
+
+	pConf, err := spec.ParseYAML(`{}`, nil)
+	require.NoError(t, err)
+	conf, err := ConfigFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Nil(t, conf)
+
+	pConf, err = spec.ParseYAML(`
+rolling:
+  enabled: true
+  format: raw
+  max_records: 10
+  max_age: 1s
+`, nil)
+	require.NoError(t, err)
+	conf, err = ConfigFromParsed(pConf)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Format:     "raw",
+		MaxBytes:   64 * 1024 * 1024,
+		MaxRecords: 10,
+		MaxAge:     time.Second,
+	}, conf)
+
+	pConf, err = spec.ParseYAML(`
+rolling:
+  enabled: true
+  max_age: 0s
+`, nil)
+	require.NoError(t, err)
+	_, err = ConfigFromParsed(pConf)
+	require.Error(t, err)
+}
+
+func TestRollingRecords(t *testing.T) {
+	var u rollingUploads
+	r := NewRoller(&Config{
+		Format:     "lines",
+		MaxRecords: 4,
+		MaxAge:     time.Hour,
+	}, u.upload, nil)
+
+	var wg sync.WaitGroup
+	for _, b := range []service.MessageBatch{rollingBatch("a", "b"), rollingBatch("c", "d")} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, r.WriteBatch(context.Background(), b))
+		}()
+	}
+	wg.Wait()
+
+	files := u.get()
+	require.Len(t, files, 1)
+	assert.Len(t, files[0], 8)
+	assert.Contains(t, []string{"a\nb\nc\nd\n", "c\nd\na\nb\n"}, files[0])
+
+	require.NoError(t, r.Close(context.Background()))
+	assert.Len(t, u.get(), 1)
+}
+
+func TestRollingBytes(t *testing.T) {
+	var u rollingUploads
+	r := NewRoller(&Config{
+		Format:   "raw",
+		MaxBytes: 3,
+		MaxAge:   time.Hour,
+	}, u.upload, nil)
+
+	require.NoError(t, r.WriteBatch(context.Background(), rollingBatch("ab", "cd", "ef", "g")))
+
+	ctx, done := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer done()
+	require.ErrorIs(t, r.WriteBatch(ctx, rollingBatch("h")), context.DeadlineExceeded)
+
+	require.NoError(t, r.Close(context.Background()))
+	assert.ElementsMatch(t, []string{"abcd", "efg", "h"}, u.get())
+}
+
+func TestRollingAge(t *testing.T) {
+	var u rollingUploads
+	r := NewRoller(&Config{
+		Format: "lines",
+		MaxAge: time.Millisecond * 50,
+	}, u.upload, nil)
+
+	require.NoError(t, r.WriteBatch(context.Background(), rollingBatch("a")))
+	require.NoError(t, r.WriteBatch(context.Background(), rollingBatch("b")))
+	require.NoError(t, r.Close(context.Background()))
+
+	assert.Equal(t, []string{"a\n", "b\n"}, u.get())
+}
+
+func TestRollingUploadError(t *testing.T) {
+	u := rollingUploads{err: errors.New("nope")}
+	r := NewRoller(&Config{
+		Format:     "lines",
+		MaxRecords: 1,
+		MaxAge:     time.Hour,
+	}, u.upload, service.MockResources().Logger())
+
+	require.EqualError(t, r.WriteBatch(context.Background(), rollingBatch("a")), "nope")
+	require.NoError(t, r.Close(context.Background()))
+}
+
+func TestRollingParquet(t *testing.T) {
+	type rowA struct {
+		ID int64 `parquet:"id"`
+	}
+	type rowB struct {
+		Name string `parquet:"name"`
+	}
+
+	encode := func(rows any) string {
+		var buf bytes.Buffer
+		switch r := rows.(type) {
+		case []rowA:
+			require.NoError(t, parquet.Write(&buf, r, parquet.Compression(&parquet.Zstd)))
+		case []rowB:
+			require.NoError(t, parquet.Write(&buf, r))
+		}
+		return buf.String()
+	}
+
+	var u rollingUploads
+	r := NewRoller(&Config{
+		Format:     "parquet",
+		MaxRecords: 100,
+		MaxAge:     time.Hour,
+	}, u.upload, nil)
+
+	go func() {
+		assert.NoError(t, r.WriteBatch(context.Background(), rollingBatch(
+			encode([]rowA{{ID: 1}, {ID: 2}}),
+			encode([]rowA{{ID: 3}}),
+			encode([]rowB{{Name: "foo"}}),
+		)))
+	}()
+	require.Eventually(t, func() bool {
+		return len(u.get()) == 1
+	}, time.Second, time.Millisecond*10)
+	require.NoError(t, r.Close(context.Background()))
+
+	files := u.get()
+	require.Len(t, files, 2)
+
+	as, err := parquet.Read[rowA](bytes.NewReader([]byte(files[0])), int64(len(files[0])))
+	require.NoError(t, err)
+	assert.Equal(t, []rowA{{ID: 1}, {ID: 2}, {ID: 3}}, as)
+
+	f, err := parquet.OpenFile(bytes.NewReader([]byte(files[0])), int64(len(files[0])))
+	require.NoError(t, err)
+	assert.Equal(t, parquet.Zstd.CompressionCodec(), f.Metadata().RowGroups[0].Columns[0].MetaData.Codec)
+
+	bs, err := parquet.Read[rowB](bytes.NewReader([]byte(files[1])), int64(len(files[1])))
+	require.NoError(t, err)
+	assert.Equal(t, []rowB{{Name: "foo"}}, bs)
+
+	require.Error(t, r.WriteBatch(context.Background(), rollingBatch("not parquet")))
+}
@@ -97,6 +97,12 @@ func parseSecretsLookupURN(ctx context.Context, logger *slog.Logger, urn string)
 			return nil, err
 		}
 		return lookupFn(secrets.NewSecretProvider, secretsManager, path, u.Query().Get(trimPrefixParam))
+	case "vault":
+		secretsManager, prefix, err := newVaultSecretsManager(logger, u)
+		if err != nil {
+			return nil, err
+		}
+		return lookupFn(secrets.NewSecretProvider, secretsManager, prefix, u.Query().Get(trimPrefixParam))
 	case "none":
 		return func(ctx context.Context, key string) (string, bool) {
 			return "", false
@@ -0,0 +1,210 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// vaultSecretsManager obtains secrets stored within a HashiCorp Vault KV
+// version 2 secrets engine, where each secret is returned as a JSON object of
+// its fields.
+type vaultSecretsManager struct {
+	logger    *slog.Logger
+	client    *http.Client
+	dataURL   string
+	namespace string
+
+	// When set tokens are obtained by logging in with an AppRole, and are
+	// renewed by logging in again before they expire or once rejected.
+	loginURL string
+	roleID   string
+	secretID string
+
+	tokenMut     sync.Mutex
+	token        string
+	tokenRenewAt time.Time
+	nowFn        func() time.Time
+}
+
+// newVaultSecretsManager creates a secrets manager from a URN of the form
+// vault://host:port/mount/path, where the first segment of the path is the
+// mount of the secrets engine and the remainder is returned as a prefix to add
+// to the names of secrets.
+func newVaultSecretsManager(logger *slog.Logger, u *url.URL) (*vaultSecretsManager, string, error) {
+	mount, prefix, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if mount == "" {
+		return nil, "", errors.New("vault secrets URN must specify the mount of a secrets engine as the first segment of the path")
+	}
+
+	scheme := "https"
+	if s := u.Query().Get("scheme"); s != "" {
+		scheme = s
+	}
+
+	v := &vaultSecretsManager{
+		logger:    logger,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		dataURL:   fmt.Sprintf("%v://%v/v1/%v/data/", scheme, u.Host, mount),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		nowFn:     time.Now,
+	}
+	if v.token == "" {
+		if v.roleID = os.Getenv("VAULT_ROLE_ID"); v.roleID == "" {
+			return nil, "", errors.New("vault secrets require either the environment variable VAULT_TOKEN or VAULT_ROLE_ID to be set")
+		}
+		v.secretID = os.Getenv("VAULT_SECRET_ID")
+
+		authMount := "approle"
+		if m := os.Getenv("VAULT_APPROLE_MOUNT"); m != "" {
+			authMount = m
+		}
+		v.loginURL = fmt.Sprintf("%v://%v/v1/auth/%v/login", scheme, u.Host, authMount)
+	}
+	return v, prefix, nil
+}
+
+// getToken returns the token to authenticate requests with, logging in with
+// the AppRole when there is no token, it is due to be renewed or renew is set.
+func (v *vaultSecretsManager) getToken(ctx context.Context, renew bool) (string, error) {
+	v.tokenMut.Lock()
+	defer v.tokenMut.Unlock()
+
+	if v.loginURL == "" {
+		return v.token, nil
+	}
+	if v.token != "" && !renew && (v.tokenRenewAt.IsZero() || v.nowFn().Before(v.tokenRenewAt)) {
+		return v.token, nil
+	}
+
+	reqBody, err := json.Marshal(map[string]string{
+		"role_id":   v.roleID,
+		"secret_id": v.secretID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.loginURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("approle login returned status %v", res.StatusCode)
+	}
+
+	var body struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode approle login response: %w", err)
+	}
+	if body.Auth.ClientToken == "" {
+		return "", errors.New("approle login response contained no token")
+	}
+
+	v.token = body.Auth.ClientToken
+	v.tokenRenewAt = time.Time{}
+	if body.Auth.LeaseDuration > 0 {
+		// Renew tokens once two thirds of their lease has passed so that
+		// requests in flight are not rejected.
+		v.tokenRenewAt = v.nowFn().Add(time.Duration(body.Auth.LeaseDuration) * time.Second * 2 / 3)
+	}
+	return v.token, nil
+}
+
+func (v *vaultSecretsManager) GetSecretValue(ctx context.Context, name string) (string, bool) {
+	token, err := v.getToken(ctx, false)
+	if err != nil {
+		v.logger.With("error", err, "key", name).Error("Failed to authenticate with vault")
+		return "", false
+	}
+
+	res, err := v.getSecret(ctx, name, token)
+	if err == nil && res.StatusCode == http.StatusForbidden && v.loginURL != "" {
+		// The token might have been revoked or expired early, in which case
+		// log in again and retry once.
+		res.Body.Close()
+		if token, err = v.getToken(ctx, true); err != nil {
+			v.logger.With("error", err, "key", name).Error("Failed to authenticate with vault")
+			return "", false
+		}
+		res, err = v.getSecret(ctx, name, token)
+	}
+	if err != nil {
+		v.logger.With("error", err, "key", name).Error("Failed to look up secret")
+		return "", false
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", false
+	}
+	if res.StatusCode != http.StatusOK {
+		v.logger.With("status", res.StatusCode, "key", name).Error("Failed to look up secret")
+		return "", false
+	}
+
+	var body struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		v.logger.With("error", err, "key", name).Error("Failed to decode secret")
+		return "", false
+	}
+	if len(body.Data.Data) == 0 || string(body.Data.Data) == "null" {
+		// Secrets where the latest version has been deleted have no data.
+		return "", false
+	}
+	return string(body.Data.Data), true
+}
+
+func (v *vaultSecretsManager) getSecret(ctx context.Context, name, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.dataURL+name, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	return v.client.Do(req)
+}
+
+func (v *vaultSecretsManager) CheckSecretExists(ctx context.Context, name string) bool {
+	_, exists := v.GetSecretValue(ctx, name)
+	return exists
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Redpanda Data, Inc.
+//
+// Licensed as a Redpanda Enterprise file under the Redpanda Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+// https://github.com/redpanda-data/connect/blob/main/licenses/rcl.md
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "footoken" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/connect/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"user":"foo","password":"bar"},"metadata":{"version":2}}}`))
+		case "/v1/secret/data/connect/deleted":
+			_, _ = w.Write([]byte(`{"data":{"data":null,"metadata":{"version":3}}}`))
+		case "/v1/secret/data/connect/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("VAULT_TOKEN", "footoken")
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	lookup, err := parseSecretsLookupURN(context.Background(), slog.Default(), "vault://"+host+"/secret/connect/?scheme=http&trimPrefix=secrets.")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		key    string
+		value  string
+		exists bool
+	}{
+		{key: "secrets.db.password", value: "bar", exists: true},
+		{key: "secrets.db.user", value: "foo", exists: true},
+		{key: "secrets.db", value: `{"user":"foo","password":"bar"}`, exists: true},
+		{key: "secrets.db.nope", exists: false},
+		{key: "db.password", exists: false},
+		{key: "secrets.missing", exists: false},
+		{key: "secrets.deleted", exists: false},
+		{key: "secrets.broken", exists: false},
+	} {
+		v, exists := lookup(context.Background(), test.key)
+		assert.Equal(t, test.exists, exists, test.key)
+		assert.Equal(t, test.value, v, test.key)
+	}
+
+	t.Setenv("VAULT_TOKEN", "badtoken")
+
+	lookup, err = parseSecretsLookupURN(context.Background(), slog.Default(), "vault://"+host+"/secret/connect/?scheme=http")
+	require.NoError(t, err)
+
+	_, exists := lookup(context.Background(), "db")
+	assert.False(t, exists)
+}
+
+func TestVaultSecretsAppRole(t *testing.T) {
+	var (
+		mut        sync.Mutex
+		logins     int
+		validToken string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["role_id"] != "foorole" || body["secret_id"] != "foosecret" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			logins++
+			validToken = fmt.Sprintf("token%v", logins)
+			_, _ = fmt.Fprintf(w, `{"auth":{"client_token":%q,"lease_duration":60,"renewable":true}}`, validToken)
+		case "/v1/secret/data/db":
+			if r.Header.Get("X-Vault-Token") != validToken {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"data":{"user":"foo"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_ROLE_ID", "foorole")
+	t.Setenv("VAULT_SECRET_ID", "foosecret")
+
+	u, err := url.Parse("vault://" + strings.TrimPrefix(server.URL, "http://") + "/secret?scheme=http")
+	require.NoError(t, err)
+
+	v, _, err := newVaultSecretsManager(slog.Default(), u)
+	require.NoError(t, err)
+
+	now := time.Now()
+	v.nowFn = func() time.Time { return now }
+
+	getLogins := func() int {
+		mut.Lock()
+		defer mut.Unlock()
+		return logins
+	}
+
+	value, exists := v.GetSecretValue(context.Background(), "db")
+	require.True(t, exists)
+	assert.Equal(t, `{"user":"foo"}`, value)
+	assert.Equal(t, 1, getLogins())
+
+	_, exists = v.GetSecretValue(context.Background(), "db")
+	require.True(t, exists)
+	assert.Equal(t, 1, getLogins())
+
+	// Tokens are renewed once two thirds of their lease has passed.
+	now = now.Add(41 * time.Second)
+	_, exists = v.GetSecretValue(context.Background(), "db")
+	require.True(t, exists)
+	assert.Equal(t, 2, getLogins())
+
+	// Rejected tokens are renewed and the request retried.
+	mut.Lock()
+	validToken = "revoked"
+	mut.Unlock()
+	_, exists = v.GetSecretValue(context.Background(), "db")
+	require.True(t, exists)
+	assert.Equal(t, 3, getLogins())
+}
+
+func TestVaultSecretsBadURN(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_ROLE_ID", "")
+	_, err := parseSecretsLookupURN(context.Background(), slog.Default(), "vault://localhost:8200/secret")
+	require.Error(t, err)
+
+	t.Setenv("VAULT_TOKEN", "footoken")
+	_, err = parseSecretsLookupURN(context.Background(), slog.Default(), "vault://localhost:8200")
+	require.Error(t, err)
+}
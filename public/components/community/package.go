@@ -27,24 +27,33 @@ import (
 	_ "github.com/redpanda-data/connect/v4/public/components/beanstalkd"
 	_ "github.com/redpanda-data/connect/v4/public/components/cassandra"
 	_ "github.com/redpanda-data/connect/v4/public/components/changelog"
+	_ "github.com/redpanda-data/connect/v4/public/components/clickhouse"
 	_ "github.com/redpanda-data/connect/v4/public/components/cockroachdb"
 	_ "github.com/redpanda-data/connect/v4/public/components/confluent"
 	_ "github.com/redpanda-data/connect/v4/public/components/couchbase"
 	_ "github.com/redpanda-data/connect/v4/public/components/crypto"
 	_ "github.com/redpanda-data/connect/v4/public/components/cypher"
+	_ "github.com/redpanda-data/connect/v4/public/components/datadog"
+	_ "github.com/redpanda-data/connect/v4/public/components/delta"
 	_ "github.com/redpanda-data/connect/v4/public/components/dgraph"
 	_ "github.com/redpanda-data/connect/v4/public/components/discord"
+	_ "github.com/redpanda-data/connect/v4/public/components/disk"
 	_ "github.com/redpanda-data/connect/v4/public/components/elasticsearch"
 	_ "github.com/redpanda-data/connect/v4/public/components/elasticsearch/v8"
 	_ "github.com/redpanda-data/connect/v4/public/components/gcp"
+	_ "github.com/redpanda-data/connect/v4/public/components/grpc"
 	_ "github.com/redpanda-data/connect/v4/public/components/hdfs"
+	_ "github.com/redpanda-data/connect/v4/public/components/iceberg"
 	_ "github.com/redpanda-data/connect/v4/public/components/influxdb"
 	_ "github.com/redpanda-data/connect/v4/public/components/io"
 	_ "github.com/redpanda-data/connect/v4/public/components/jaeger"
 	_ "github.com/redpanda-data/connect/v4/public/components/javascript"
 	_ "github.com/redpanda-data/connect/v4/public/components/kafka"
+	_ "github.com/redpanda-data/connect/v4/public/components/loki"
+	_ "github.com/redpanda-data/connect/v4/public/components/lookup"
 	_ "github.com/redpanda-data/connect/v4/public/components/maxmind"
 	_ "github.com/redpanda-data/connect/v4/public/components/memcached"
+	_ "github.com/redpanda-data/connect/v4/public/components/metric"
 	_ "github.com/redpanda-data/connect/v4/public/components/mongodb"
 	_ "github.com/redpanda-data/connect/v4/public/components/mqtt"
 	_ "github.com/redpanda-data/connect/v4/public/components/msgpack"
@@ -62,15 +71,24 @@ import (
 	_ "github.com/redpanda-data/connect/v4/public/components/pusher"
 	_ "github.com/redpanda-data/connect/v4/public/components/qdrant"
 	_ "github.com/redpanda-data/connect/v4/public/components/questdb"
+	_ "github.com/redpanda-data/connect/v4/public/components/ratelimit"
 	_ "github.com/redpanda-data/connect/v4/public/components/redis"
 	_ "github.com/redpanda-data/connect/v4/public/components/redpanda"
 	_ "github.com/redpanda-data/connect/v4/public/components/sentry"
 	_ "github.com/redpanda-data/connect/v4/public/components/sftp"
+	_ "github.com/redpanda-data/connect/v4/public/components/snmp"
 	_ "github.com/redpanda-data/connect/v4/public/components/spicedb"
 	_ "github.com/redpanda-data/connect/v4/public/components/sql"
 	_ "github.com/redpanda-data/connect/v4/public/components/statsd"
+	_ "github.com/redpanda-data/connect/v4/public/components/syslog"
+	_ "github.com/redpanda-data/connect/v4/public/components/text"
 	_ "github.com/redpanda-data/connect/v4/public/components/timeplus"
 	_ "github.com/redpanda-data/connect/v4/public/components/twitter"
+	_ "github.com/redpanda-data/connect/v4/public/components/useragent"
+	_ "github.com/redpanda-data/connect/v4/public/components/vectordb"
 	_ "github.com/redpanda-data/connect/v4/public/components/wasm"
+	_ "github.com/redpanda-data/connect/v4/public/components/websocket"
+	_ "github.com/redpanda-data/connect/v4/public/components/window"
+	_ "github.com/redpanda-data/connect/v4/public/components/xlsx"
 	_ "github.com/redpanda-data/connect/v4/public/components/zeromq"
 )
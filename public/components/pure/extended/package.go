@@ -28,7 +28,9 @@ import (
 
 	_ "github.com/redpanda-data/connect/v4/internal/impl/awk"
 	_ "github.com/redpanda-data/connect/v4/internal/impl/html"
+	_ "github.com/redpanda-data/connect/v4/internal/impl/jq"
 	_ "github.com/redpanda-data/connect/v4/internal/impl/jsonpath"
+	_ "github.com/redpanda-data/connect/v4/internal/impl/jsonschema"
 	_ "github.com/redpanda-data/connect/v4/internal/impl/lang"
 	_ "github.com/redpanda-data/connect/v4/internal/impl/msgpack"
 	_ "github.com/redpanda-data/connect/v4/internal/impl/parquet"
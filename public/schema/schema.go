@@ -19,6 +19,7 @@ import (
 	"github.com/redpanda-data/benthos/v4/public/service"
 
 	"github.com/redpanda-data/connect/v4/internal/impl/kafka/enterprise"
+	"github.com/redpanda-data/connect/v4/internal/lifecycle"
 	"github.com/redpanda-data/connect/v4/internal/plugins"
 )
 
@@ -26,6 +27,13 @@ func redpandaTopLevelConfigField() *service.ConfigField {
 	return service.NewObjectField("redpanda", enterprise.TopicLoggerFields()...)
 }
 
+func lifecycleHooksTopLevelConfigField() *service.ConfigField {
+	return service.NewObjectField("lifecycle_hooks", lifecycle.Fields()...).
+		Description("Processors to execute when the stream starts, stops cleanly or stops due to an error, which is useful for tasks such as registering with service discovery or sending deployment notifications. Each hook is executed with a single JSON message containing the fields `event` (`start`, `close` or `error`), `instance_id`, `timestamp` and, for errors, `error`. Outbound calls can be made with processors such as `http`, and any errors are logged.").
+		Advanced().
+		Version("4.48.0")
+}
+
 // Standard returns the config schema of a standard build of Redpanda Connect.
 func Standard(version, dateBuilt string) *service.ConfigSchema {
 	env := service.NewEnvironment()
@@ -35,6 +43,7 @@ func Standard(version, dateBuilt string) *service.ConfigSchema {
 		"@service": "redpanda-connect",
 	}, "logger", "static_fields")
 	s = s.Field(redpandaTopLevelConfigField())
+	s = s.Field(lifecycleHooksTopLevelConfigField())
 	return s
 }
 
@@ -64,6 +73,7 @@ func Cloud(version, dateBuilt string) *service.ConfigSchema {
 		"@service": "redpanda-connect",
 	}, "logger", "static_fields")
 	s = s.Field(redpandaTopLevelConfigField())
+	s = s.Field(lifecycleHooksTopLevelConfigField())
 	return s
 }
 
@@ -93,5 +103,6 @@ func CloudAI(version, dateBuilt string) *service.ConfigSchema {
 		"@service": "redpanda-connect",
 	}, "logger", "static_fields")
 	s = s.Field(redpandaTopLevelConfigField())
+	s = s.Field(lifecycleHooksTopLevelConfigField())
 	return s
 }